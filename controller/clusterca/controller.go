@@ -0,0 +1,447 @@
+package clusterca
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	ctrlconsts "github.com/kong/kong-operator/controller/consts"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+	"github.com/kong/kong-operator/modules/manager/logging"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+const (
+	// ClusterCARotationStartedEventReason is the reason of the event emitted when
+	// the rotation of the cluster CA starts.
+	ClusterCARotationStartedEventReason = "ClusterCARotationStarted"
+	// ClusterCARotationProgressingEventReason is the reason of the event emitted when
+	// the rotation of the cluster CA moves to its next phase.
+	ClusterCARotationProgressingEventReason = "ClusterCARotationProgressing"
+	// ClusterCARotationCompletedEventReason is the reason of the event emitted when
+	// the rotation of the cluster CA is completed.
+	ClusterCARotationCompletedEventReason = "ClusterCARotationCompleted"
+	// ClusterCARotationFailedEventReason is the reason of the event emitted when
+	// the rotation of the cluster CA can't proceed.
+	ClusterCARotationFailedEventReason = "ClusterCARotationFailed"
+	// ClusterCARotationDataPlanesSkippedEventReason is the reason of the event emitted when
+	// the rotation of the cluster CA moves to its next phase without the DataPlanes which
+	// Deployments didn't roll out within the configured timeout.
+	ClusterCARotationDataPlanesSkippedEventReason = "ClusterCARotationDataPlanesSkipped"
+)
+
+// defaultRotationPollInterval is the interval at which the progress of an ongoing
+// rotation of the cluster CA is checked.
+const defaultRotationPollInterval = 10 * time.Second
+
+// maxReportedPendingDataPlanes is the maximum number of DataPlanes named in the
+// pending DataPlanes annotation of the cluster CA Secret.
+const maxReportedPendingDataPlanes = 10
+
+// -----------------------------------------------------------------------------
+// ClusterCAReconciler
+// -----------------------------------------------------------------------------
+
+// Reconciler rotates the cluster CA used to sign the ControlPlane and DataPlane
+// Admin API mTLS certificates.
+//
+// The rotation is requested by annotating the cluster CA Secret with the
+// "Requested" rotation phase or, when enabled, automatically once the configured
+// fraction of the CA certificate's lifetime has passed. It then goes through phases
+// which only move forward when all certificates and DataPlane Deployments are in
+// sync with the current one, so that the Admin API connections aren't interrupted:
+//   - Trusting: a new CA is generated and added to the trust bundle,
+//   - Reissuing: the new CA signs certificates and existing ones are re-issued,
+//   - Retiring: the old CA is removed from the trust bundle.
+//
+// DataPlanes which Deployments are not yet in sync are listed in the pending DataPlanes
+// annotation of the cluster CA Secret. When DataPlaneSyncTimeout is set, a phase stops
+// waiting for them once it passes: their Deployments get the current certificate and
+// trust bundle when they're rolled out again.
+type Reconciler struct {
+	client.Client
+	CacheSyncTimeout time.Duration
+	LoggingMode      logging.Mode
+
+	// CASecretNN is the namespaced name of the cluster CA Secret.
+	CASecretNN types.NamespacedName
+	// CAKeyConfig is the configuration of the keys of generated CAs.
+	CAKeyConfig secrets.KeyConfig
+	// CARenewalConfig configures the lifetime of generated CAs and their automatic
+	// rotation. The rotation is only performed on request when its renewal fraction is 0.
+	CARenewalConfig secrets.CertificateRenewalConfig
+	// RotationPollInterval is the interval at which the progress of an ongoing rotation is checked.
+	RotationPollInterval time.Duration
+	// DataPlaneSyncTimeout is the time a phase of the rotation waits for DataPlane Deployments
+	// to be in sync before proceeding without them. It waits indefinitely when it's 0.
+	DataPlaneSyncTimeout time.Duration
+
+	eventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("clusterca")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("clusterca").
+		WithOptions(controller.Options{
+			CacheSyncTimeout: r.CacheSyncTimeout,
+		}).
+		For(&corev1.Secret{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(r.isClusterCASecret))).
+		Complete(r)
+}
+
+func (r *Reconciler) isClusterCASecret(obj client.Object) bool {
+	return obj.GetNamespace() == r.CASecretNN.Namespace && obj.GetName() == r.CASecretNN.Name
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "clusterca", r.LoggingMode)
+
+	var ca corev1.Secret
+	if err := r.Get(ctx, req.NamespacedName, &ca); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	phase := secrets.GetClusterCARotationPhase(&ca)
+	switch phase {
+	case secrets.ClusterCARotationPhaseNone:
+		rotationTime, ok := r.automaticRotationTime(&ca)
+		if !ok {
+			return ctrl.Result{}, nil
+		}
+		if until := time.Until(rotationTime); until > 0 {
+			return ctrl.Result{RequeueAfter: until}, nil
+		}
+		log.Info(logger, "cluster CA certificate reached its rotation time, rotating it")
+		return r.startRotation(ctx, &ca)
+
+	case secrets.ClusterCARotationPhaseRequested:
+		log.Info(logger, "cluster CA rotation requested")
+		return r.startRotation(ctx, &ca)
+
+	case secrets.ClusterCARotationPhaseTrusting,
+		secrets.ClusterCARotationPhaseReissuing,
+		secrets.ClusterCARotationPhaseRetiring:
+		return r.progressRotation(ctx, logger, &ca, phase)
+
+	default:
+		r.eventf(&ca, corev1.EventTypeWarning, ClusterCARotationFailedEventReason,
+			"Unknown cluster CA rotation phase %q, set it to %q to start the rotation",
+			phase, secrets.ClusterCARotationPhaseRequested,
+		)
+		return ctrl.Result{}, nil
+	}
+}
+
+// automaticRotationTime returns the time at which the cluster CA should be rotated
+// automatically. It returns false if the automatic rotation is disabled.
+func (r *Reconciler) automaticRotationTime(ca *corev1.Secret) (time.Time, bool) {
+	cert, err := secrets.ParseSecretCertificate(ca)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return r.CARenewalConfig.RenewalTime(cert)
+}
+
+func (r *Reconciler) startRotation(ctx context.Context, ca *corev1.Secret) (ctrl.Result, error) {
+	if err := secrets.StartClusterCARotation(ca, r.CAKeyConfig, r.CARenewalConfig.Lifetime); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed generating new cluster CA: %w", err)
+	}
+	if err := r.Update(ctx, ca); err != nil {
+		return r.handleUpdateError(err)
+	}
+	r.eventf(ca, corev1.EventTypeNormal, ClusterCARotationStartedEventReason,
+		"Generated a new cluster CA, adding it to the trust bundle of all components",
+	)
+	return ctrl.Result{RequeueAfter: r.pollInterval()}, nil
+}
+
+func (r *Reconciler) progressRotation(
+	ctx context.Context,
+	logger logr.Logger,
+	ca *corev1.Secret,
+	phase secrets.ClusterCARotationPhase,
+) (ctrl.Result, error) {
+	certSecrets, err := r.listCertificateSecrets(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Make owners of the certificates reconcile them, so that they follow the rotation.
+	if err := r.annotateCertificateSecrets(ctx, certSecrets, phase); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	progress, err := r.collectRotationProgress(ctx, ca, certSecrets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	total := progress.total
+
+	if progress.inSync < total {
+		if progress.inSync+progress.pendingDeployments < total || !r.dataPlaneSyncTimedOut(ca) {
+			if r.annotateProgress(ca, progress) {
+				if err := r.Update(ctx, ca); err != nil {
+					return r.handleUpdateError(err)
+				}
+			}
+			return ctrl.Result{RequeueAfter: r.pollInterval()}, nil
+		}
+		log.Info(logger, "timed out waiting for DataPlane Deployments, proceeding with the cluster CA rotation",
+			"dataplanes", progress.pendingDataPlanes)
+		r.eventf(ca, corev1.EventTypeWarning, ClusterCARotationDataPlanesSkippedEventReason,
+			"DataPlanes %s were not in sync within %s, proceeding without them",
+			strings.Join(progress.pendingDataPlanes, ", "), r.DataPlaneSyncTimeout,
+		)
+	}
+
+	var msg string
+	switch phase {
+	case secrets.ClusterCARotationPhaseTrusting:
+		if err := secrets.PromoteClusterCA(ca); err != nil {
+			r.eventf(ca, corev1.EventTypeWarning, ClusterCARotationFailedEventReason, "%v", err)
+			return ctrl.Result{}, err
+		}
+		msg = "New cluster CA is trusted by all components, re-issuing certificates"
+	case secrets.ClusterCARotationPhaseReissuing:
+		secrets.RetireClusterCA(ca)
+		msg = "All certificates are signed by the new cluster CA, retiring the old cluster CA"
+	default:
+		secrets.CompleteClusterCARotation(ca)
+	}
+	if msg != "" {
+		ca.Annotations[consts.ClusterCARotationProgressAnnotation] = fmt.Sprintf("0/%d", total)
+	}
+	if err := r.Update(ctx, ca); err != nil {
+		return r.handleUpdateError(err)
+	}
+
+	if msg == "" {
+		if err := r.annotateCertificateSecrets(ctx, certSecrets, secrets.ClusterCARotationPhaseNone); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info(logger, "cluster CA rotation completed")
+		r.eventf(ca, corev1.EventTypeNormal, ClusterCARotationCompletedEventReason,
+			"Cluster CA rotation completed",
+		)
+		return ctrl.Result{}, nil
+	}
+	log.Info(logger, "cluster CA rotation progressing", "phase", secrets.GetClusterCARotationPhase(ca))
+	r.eventf(ca, corev1.EventTypeNormal, ClusterCARotationProgressingEventReason, "%s", msg)
+	return ctrl.Result{RequeueAfter: r.pollInterval()}, nil
+}
+
+// listCertificateSecrets lists the Secrets holding the ControlPlane and DataPlane
+// Admin API certificates signed by the cluster CA.
+func (r *Reconciler) listCertificateSecrets(ctx context.Context) ([]corev1.Secret, error) {
+	var certSecrets []corev1.Secret
+	for _, managedBy := range []string{consts.ControlPlaneManagedLabelValue, consts.DataPlaneManagedLabelValue} {
+		var list corev1.SecretList
+		if err := r.List(ctx, &list, client.MatchingLabels{
+			consts.GatewayOperatorManagedByLabel: managedBy,
+		}); err != nil {
+			return nil, fmt.Errorf("failed listing Secrets managed by %s: %w", managedBy, err)
+		}
+		for _, s := range list.Items {
+			if s.Type == corev1.SecretTypeTLS && len(s.Data["ca.crt"]) > 0 {
+				certSecrets = append(certSecrets, s)
+			}
+		}
+	}
+	return certSecrets, nil
+}
+
+// annotateCertificateSecrets sets the rotation phase annotation on the certificate Secrets,
+// or removes it when phase is ClusterCARotationPhaseNone.
+func (r *Reconciler) annotateCertificateSecrets(ctx context.Context, certSecrets []corev1.Secret, phase secrets.ClusterCARotationPhase) error {
+	for i := range certSecrets {
+		s := &certSecrets[i]
+		current, ok := s.Annotations[consts.ClusterCARotationPhaseAnnotation]
+		if (phase == secrets.ClusterCARotationPhaseNone && !ok) || (ok && current == string(phase)) {
+			continue
+		}
+		old := s.DeepCopy()
+		if phase == secrets.ClusterCARotationPhaseNone {
+			delete(s.Annotations, consts.ClusterCARotationPhaseAnnotation)
+		} else {
+			if s.Annotations == nil {
+				s.Annotations = make(map[string]string)
+			}
+			s.Annotations[consts.ClusterCARotationPhaseAnnotation] = string(phase)
+		}
+		if err := r.Patch(ctx, s, client.MergeFrom(old)); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed annotating Secret %s/%s: %w", s.Namespace, s.Name, err)
+		}
+	}
+	return nil
+}
+
+// rotationProgress describes how many certificate Secrets and running DataPlane Deployments
+// are in sync with the current phase of the rotation.
+type rotationProgress struct {
+	inSync int
+	total  int
+	// pendingDeployments is the number of running DataPlane Deployments which are not in sync.
+	pendingDeployments int
+	// pendingDataPlanes are the sorted namespaced names of the DataPlanes owning them.
+	pendingDataPlanes []string
+}
+
+// collectRotationProgress returns the progress of the rotation with regard to the certificate Secrets
+// and running DataPlane Deployments.
+func (r *Reconciler) collectRotationProgress(ctx context.Context, ca *corev1.Secret, certSecrets []corev1.Secret) (rotationProgress, error) {
+	var (
+		progress = rotationProgress{total: len(certSecrets)}
+		serials  = make(map[string]struct{})
+	)
+	for i := range certSecrets {
+		s := &certSecrets[i]
+		if secrets.IsCertificateSecretInSyncWithClusterCA(s, ca) {
+			progress.inSync++
+		}
+		if cert, err := secrets.ParseSecretCertificate(s); err == nil {
+			serials[cert.SerialNumber.Text(16)] = struct{}{}
+		}
+	}
+
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.MatchingLabels{
+		consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+	}); err != nil {
+		return rotationProgress{}, fmt.Errorf("failed listing DataPlane Deployments: %w", err)
+	}
+	checksum := secrets.CATrustBundleChecksum(secrets.CATrustBundle(ca))
+	pending := sets.New[string]()
+	for i := range deployments.Items {
+		d := &deployments.Items[i]
+		if !isDeploymentRunning(d) {
+			continue
+		}
+		progress.total++
+		if isDeploymentInSync(d, checksum, serials) {
+			progress.inSync++
+			continue
+		}
+		progress.pendingDeployments++
+		pending.Insert(deploymentDataPlaneName(d))
+	}
+	progress.pendingDataPlanes = sets.List(pending)
+
+	return progress, nil
+}
+
+// deploymentDataPlaneName returns the namespaced name of the DataPlane controlling the Deployment,
+// or the Deployment's one when it has no controller.
+func deploymentDataPlaneName(d *appsv1.Deployment) string {
+	name := d.Name
+	if owner := metav1.GetControllerOf(d); owner != nil {
+		name = owner.Name
+	}
+	return types.NamespacedName{Namespace: d.Namespace, Name: name}.String()
+}
+
+// annotateProgress sets the progress and pending DataPlanes annotations of the cluster CA Secret,
+// and records the start time of the current phase if it's missing. It returns true if the
+// Secret has changed.
+func (r *Reconciler) annotateProgress(ca *corev1.Secret, progress rotationProgress) bool {
+	old := maps.Clone(ca.Annotations)
+	if _, ok := secrets.GetClusterCARotationPhaseStartTime(ca); !ok {
+		secrets.SetClusterCARotationPhaseStartTime(ca, time.Now())
+	}
+	ca.Annotations[consts.ClusterCARotationProgressAnnotation] = fmt.Sprintf("%d/%d", progress.inSync, progress.total)
+	if len(progress.pendingDataPlanes) == 0 {
+		delete(ca.Annotations, consts.ClusterCARotationPendingDataPlanesAnnotation)
+	} else {
+		pending := lo.Slice(progress.pendingDataPlanes, 0, maxReportedPendingDataPlanes)
+		if rest := len(progress.pendingDataPlanes) - maxReportedPendingDataPlanes; rest > 0 {
+			pending = append(pending, fmt.Sprintf("and %d more", rest))
+		}
+		ca.Annotations[consts.ClusterCARotationPendingDataPlanesAnnotation] = strings.Join(pending, ",")
+	}
+	return !maps.Equal(old, ca.Annotations)
+}
+
+// dataPlaneSyncTimedOut returns true if the current phase of the rotation has been waiting
+// for DataPlane Deployments for longer than DataPlaneSyncTimeout.
+func (r *Reconciler) dataPlaneSyncTimedOut(ca *corev1.Secret) bool {
+	if r.DataPlaneSyncTimeout <= 0 {
+		return false
+	}
+	started, ok := secrets.GetClusterCARotationPhaseStartTime(ca)
+	return ok && time.Since(started) > r.DataPlaneSyncTimeout
+}
+
+// isDeploymentRunning returns false for DataPlane Deployments which don't run any Pods,
+// i.e. retired or scaled to 0 replicas ones. They don't take part in the rotation as
+// they're not rolled out until they're scaled up again, when they get the current
+// certificate and trust bundle.
+func isDeploymentRunning(d *appsv1.Deployment) bool {
+	if d.Labels[consts.DataPlaneDeploymentStateLabel] == consts.DataPlaneStateLabelValueRetired {
+		return false
+	}
+	return d.Spec.Replicas == nil || *d.Spec.Replicas > 0
+}
+
+// isDeploymentInSync returns true if the DataPlane Deployment's Pods have been rolled out
+// with the provided trust bundle and one of the current certificates.
+func isDeploymentInSync(d *appsv1.Deployment, trustBundleChecksum string, serials map[string]struct{}) bool {
+	if d.Spec.Template.Annotations[consts.DataPlaneClusterCAChecksumAnnotation] != trustBundleChecksum {
+		return false
+	}
+	// The serial is recorded on the Deployment as its Pod template is only annotated when the
+	// certificate is renewed.
+	if _, ok := serials[d.Annotations[consts.DataPlaneClusterCertificateSerialAnnotation]]; !ok {
+		return false
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.Replicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
+
+func (r *Reconciler) handleUpdateError(err error) (ctrl.Result, error) {
+	if k8serrors.IsConflict(err) {
+		return ctrl.Result{Requeue: true, RequeueAfter: ctrlconsts.RequeueWithoutBackoff}, nil
+	}
+	return ctrl.Result{}, fmt.Errorf("failed updating cluster CA secret: %w", err)
+}
+
+func (r *Reconciler) pollInterval() time.Duration {
+	if r.RotationPollInterval == 0 {
+		return defaultRotationPollInterval
+	}
+	return r.RotationPollInterval
+}
+
+func (r *Reconciler) eventf(ca *corev1.Secret, eventType, reason, messageFmt string, args ...any) {
+	if r.eventRecorder == nil {
+		return
+	}
+	r.eventRecorder.Eventf(ca, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,9 @@
+package clusterca
+
+// -----------------------------------------------------------------------------
+// ClusterCAReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
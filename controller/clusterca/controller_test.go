@@ -0,0 +1,354 @@
+package clusterca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestReconcilerRotatesClusterCA(t *testing.T) {
+	ctx := t.Context()
+	caNN := types.NamespacedName{Name: "kong-operator-ca", Namespace: "kong-system"}
+	ca := newCASecret(t, caNN, time.Now(), time.Now().Add(24*time.Hour))
+	ca.Annotations = map[string]string{
+		consts.ClusterCARotationPhaseAnnotation: string(secrets.ClusterCARotationPhaseRequested),
+	}
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       types.UID("dp-uid"),
+		},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dataplane-dp",
+			Namespace:  "default",
+			Generation: 1,
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+					Kind:       "DataPlane",
+					Name:       dp.Name,
+					UID:        dp.UID,
+					Controller: lo.ToPtr(true),
+				},
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: lo.ToPtr(int32(1)),
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+
+	// Deployments not running any Pods are never rolled out, so they mustn't block the rotation.
+	retiredDeployment := deployment.DeepCopy()
+	retiredDeployment.Name = "dataplane-dp-retired"
+	retiredDeployment.Labels[consts.DataPlaneDeploymentStateLabel] = consts.DataPlaneStateLabelValueRetired
+	scaledDownDeployment := deployment.DeepCopy()
+	scaledDownDeployment.Name = "dataplane-dp-preview"
+	scaledDownDeployment.Spec.Replicas = lo.ToPtr(int32(0))
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(ca, dp, deployment, retiredDeployment, scaledDownDeployment).
+		Build()
+	eventRecorder := record.NewFakeRecorder(20)
+	r := &Reconciler{
+		Client:          cl,
+		CASecretNN:      caNN,
+		CAKeyConfig:     secrets.KeyConfig{Type: x509.ECDSA},
+		CARenewalConfig: secrets.CertificateRenewalConfig{Lifetime: 48 * time.Hour},
+		eventRecorder:   eventRecorder,
+	}
+
+	// syncDataPlane does what the DataPlane controller does for its certificate and Deployment.
+	syncDataPlane := func(t *testing.T) *corev1.Secret {
+		t.Helper()
+		_, certSecret, err := secrets.EnsureCertificate(ctx, dp, "dp.default", caNN,
+			[]certificatesv1.KeyUsage{certificatesv1.UsageServerAuth},
+			secrets.KeyConfig{Type: x509.ECDSA},
+			secrets.IssuerConfig{},
+			secrets.CertificateRenewalConfig{FollowCARotation: true},
+			cl, nil, nil,
+		)
+		require.NoError(t, err)
+		cert, err := secrets.ParseSecretCertificate(certSecret)
+		require.NoError(t, err)
+
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(deployment), deployment))
+		deployment.Annotations = map[string]string{
+			consts.DataPlaneClusterCertificateSerialAnnotation: cert.SerialNumber.Text(16),
+		}
+		deployment.Spec.Template.Annotations = map[string]string{
+			consts.DataPlaneClusterCAChecksumAnnotation: secrets.CATrustBundleChecksum(certSecret.Data["ca.crt"]),
+		}
+		require.NoError(t, cl.Update(ctx, deployment))
+		return certSecret
+	}
+	reconcile := func(t *testing.T) (ctrl.Result, *corev1.Secret) {
+		t.Helper()
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: caNN})
+		require.NoError(t, err)
+		var ca corev1.Secret
+		require.NoError(t, cl.Get(ctx, caNN, &ca))
+		return res, &ca
+	}
+
+	certSecret := syncDataPlane(t)
+	oldCACert := ca.Data["tls.crt"]
+
+	t.Log("requested rotation generates a new CA and starts trusting it")
+	res, ca := reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseTrusting, secrets.GetClusterCARotationPhase(ca))
+	assert.Equal(t, oldCACert, ca.Data["tls.crt"])
+	assert.Equal(t, defaultRotationPollInterval, res.RequeueAfter)
+
+	t.Log("rotation waits for the certificates and DataPlane Deployments to trust the new CA")
+	_, ca = reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseTrusting, secrets.GetClusterCARotationPhase(ca))
+	assert.Equal(t, "0/2", ca.Annotations[consts.ClusterCARotationProgressAnnotation])
+	assert.Equal(t, "default/dp", ca.Annotations[consts.ClusterCARotationPendingDataPlanesAnnotation])
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(certSecret), certSecret))
+	assert.Equal(t, string(secrets.ClusterCARotationPhaseTrusting), certSecret.Annotations[consts.ClusterCARotationPhaseAnnotation],
+		"certificate Secret should be annotated to trigger its owner's reconciliation")
+
+	syncDataPlane(t)
+	_, ca = reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseReissuing, secrets.GetClusterCARotationPhase(ca))
+	assert.NotEqual(t, oldCACert, ca.Data["tls.crt"])
+
+	t.Log("rotation waits for the certificates to be re-issued by the new CA")
+	_, ca = reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseReissuing, secrets.GetClusterCARotationPhase(ca))
+	assert.Equal(t, "1/2", ca.Annotations[consts.ClusterCARotationProgressAnnotation])
+	assert.NotContains(t, ca.Annotations, consts.ClusterCARotationPendingDataPlanesAnnotation)
+
+	syncDataPlane(t)
+	_, ca = reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseRetiring, secrets.GetClusterCARotationPhase(ca))
+	assert.Equal(t, ca.Data["tls.crt"], secrets.CATrustBundle(ca))
+
+	t.Log("rotation completes once the old CA isn't trusted anymore")
+	certSecret = syncDataPlane(t)
+	_, ca = reconcile(t)
+	assert.Equal(t, secrets.ClusterCARotationPhaseNone, secrets.GetClusterCARotationPhase(ca))
+	assert.NotContains(t, ca.Annotations, consts.ClusterCARotationProgressAnnotation)
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(certSecret), certSecret))
+	assert.NotContains(t, certSecret.Annotations, consts.ClusterCARotationPhaseAnnotation)
+	assert.True(t, secrets.IsCertificateSecretInSyncWithClusterCA(certSecret, ca))
+
+	var reasons []string
+	for len(eventRecorder.Events) > 0 {
+		reasons = append(reasons, <-eventRecorder.Events)
+	}
+	require.Len(t, reasons, 4)
+	assert.Contains(t, reasons[0], ClusterCARotationStartedEventReason)
+	assert.Contains(t, reasons[1], ClusterCARotationProgressingEventReason)
+	assert.Contains(t, reasons[2], ClusterCARotationProgressingEventReason)
+	assert.Contains(t, reasons[3], ClusterCARotationCompletedEventReason)
+}
+
+func TestReconcilerAutomaticRotation(t *testing.T) {
+	caNN := types.NamespacedName{Name: "kong-operator-ca", Namespace: "kong-system"}
+
+	testCases := []struct {
+		name                string
+		notBefore           time.Time
+		notAfter            time.Time
+		rotationFraction    float64
+		expectedPhase       secrets.ClusterCARotationPhase
+		expectedRequeueFrom time.Duration
+	}{
+		{
+			name:             "CA is not rotated when the automatic rotation is disabled",
+			notBefore:        time.Now().Add(-9 * time.Hour),
+			notAfter:         time.Now().Add(time.Hour),
+			rotationFraction: 0,
+			expectedPhase:    secrets.ClusterCARotationPhaseNone,
+		},
+		{
+			name:                "CA rotation is scheduled before the configured fraction of its lifetime passes",
+			notBefore:           time.Now().Add(-time.Hour),
+			notAfter:            time.Now().Add(9 * time.Hour),
+			rotationFraction:    0.5,
+			expectedPhase:       secrets.ClusterCARotationPhaseNone,
+			expectedRequeueFrom: 3 * time.Hour,
+		},
+		{
+			name:             "CA is rotated after the configured fraction of its lifetime passes",
+			notBefore:        time.Now().Add(-9 * time.Hour),
+			notAfter:         time.Now().Add(time.Hour),
+			rotationFraction: 0.5,
+			expectedPhase:    secrets.ClusterCARotationPhaseTrusting,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(newCASecret(t, caNN, tc.notBefore, tc.notAfter)).
+				Build()
+			r := &Reconciler{
+				Client:          cl,
+				CASecretNN:      caNN,
+				CAKeyConfig:     secrets.KeyConfig{Type: x509.ECDSA},
+				CARenewalConfig: secrets.CertificateRenewalConfig{RenewalFraction: tc.rotationFraction},
+			}
+
+			res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: caNN})
+			require.NoError(t, err)
+			var ca corev1.Secret
+			require.NoError(t, cl.Get(ctx, caNN, &ca))
+			assert.Equal(t, tc.expectedPhase, secrets.GetClusterCARotationPhase(&ca))
+			if tc.expectedRequeueFrom > 0 {
+				assert.Greater(t, res.RequeueAfter, tc.expectedRequeueFrom)
+			}
+		})
+	}
+}
+
+func TestReconcilerDataPlaneSyncTimeout(t *testing.T) {
+	caNN := types.NamespacedName{Name: "kong-operator-ca", Namespace: "kong-system"}
+
+	testCases := []struct {
+		name                 string
+		phaseStarted         time.Time
+		dataPlaneSyncTimeout time.Duration
+		expectedPhase        secrets.ClusterCARotationPhase
+		expectedEventReason  string
+	}{
+		{
+			name:                 "rotation waits for unavailable DataPlane Deployments within the timeout",
+			phaseStarted:         time.Now().Add(-time.Minute),
+			dataPlaneSyncTimeout: 30 * time.Minute,
+			expectedPhase:        secrets.ClusterCARotationPhaseTrusting,
+		},
+		{
+			name:                 "rotation waits for unavailable DataPlane Deployments indefinitely when the timeout is 0",
+			phaseStarted:         time.Now().Add(-time.Hour),
+			dataPlaneSyncTimeout: 0,
+			expectedPhase:        secrets.ClusterCARotationPhaseTrusting,
+		},
+		{
+			name:                 "rotation proceeds without unavailable DataPlane Deployments after the timeout",
+			phaseStarted:         time.Now().Add(-time.Hour),
+			dataPlaneSyncTimeout: 30 * time.Minute,
+			expectedPhase:        secrets.ClusterCARotationPhaseReissuing,
+			expectedEventReason:  ClusterCARotationDataPlanesSkippedEventReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			ca := newCASecret(t, caNN, time.Now(), time.Now().Add(24*time.Hour))
+			require.NoError(t, secrets.StartClusterCARotation(ca, secrets.KeyConfig{Type: x509.ECDSA}, 48*time.Hour))
+			secrets.SetClusterCARotationPhaseStartTime(ca, tc.phaseStarted)
+			deployment := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dataplane-dp",
+					Namespace: "default",
+					Labels: map[string]string{
+						consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+					},
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: lo.ToPtr(int32(1)),
+				},
+			}
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(ca, deployment).
+				Build()
+			eventRecorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Client:               cl,
+				CASecretNN:           caNN,
+				DataPlaneSyncTimeout: tc.dataPlaneSyncTimeout,
+				eventRecorder:        eventRecorder,
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: caNN})
+			require.NoError(t, err)
+			require.NoError(t, cl.Get(ctx, caNN, ca))
+			assert.Equal(t, tc.expectedPhase, secrets.GetClusterCARotationPhase(ca))
+			if tc.expectedEventReason == "" {
+				assert.Equal(t, "0/1", ca.Annotations[consts.ClusterCARotationProgressAnnotation])
+				assert.Equal(t, "default/dataplane-dp", ca.Annotations[consts.ClusterCARotationPendingDataPlanesAnnotation])
+				assert.Empty(t, eventRecorder.Events)
+				return
+			}
+			assert.NotContains(t, ca.Annotations, consts.ClusterCARotationPendingDataPlanesAnnotation)
+			require.NotEmpty(t, eventRecorder.Events)
+			assert.Contains(t, <-eventRecorder.Events, tc.expectedEventReason)
+		})
+	}
+}
+
+func newCASecret(t *testing.T, nn types.NamespacedName, notBefore, notAfter time.Time) *corev1.Secret {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Kong Operator CA"},
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, priv.Public(), priv)
+	require.NoError(t, err)
+	privDer, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nn.Name,
+			Namespace: nn.Namespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDer}),
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package controlplane
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// adminAPIClientCertificates holds the Admin API client certificates of the ControlPlane instances.
+// The instances get their certificate from it for every TLS handshake, so a renewed certificate is
+// used for new connections without restarting the instance.
+type adminAPIClientCertificates struct {
+	lock  sync.Mutex
+	certs map[types.UID]*adminAPIClientCertificate
+}
+
+func newAdminAPIClientCertificates() *adminAPIClientCertificates {
+	return &adminAPIClientCertificates{
+		certs: make(map[types.UID]*adminAPIClientCertificate),
+	}
+}
+
+// set stores the certificate of the ControlPlane with the provided UID and returns the function
+// getting it, which returns the most recently stored certificate.
+func (c *adminAPIClientCertificates) set(
+	uid types.UID, certPEM, keyPEM []byte,
+) (func(*tls.CertificateRequestInfo) (*tls.Certificate, error), error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	holder, ok := c.certs[uid]
+	if !ok {
+		holder = &adminAPIClientCertificate{}
+		c.certs[uid] = holder
+	}
+	holder.set(&cert)
+	return holder.get, nil
+}
+
+// delete removes the certificate of the ControlPlane with the provided UID.
+func (c *adminAPIClientCertificates) delete(uid types.UID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.certs, uid)
+}
+
+// adminAPIClientCertificate holds the Admin API client certificate of a single ControlPlane instance.
+type adminAPIClientCertificate struct {
+	lock sync.RWMutex
+	cert *tls.Certificate
+}
+
+func (c *adminAPIClientCertificate) set(cert *tls.Certificate) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cert = cert
+}
+
+// get implements tls.Config's GetClientCertificate.
+func (c *adminAPIClientCertificate) get(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.cert == nil {
+		return nil, errors.New("client certificate is not set")
+	}
+	return c.cert, nil
+}
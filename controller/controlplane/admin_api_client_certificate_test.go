@@ -0,0 +1,51 @@
+package controlplane
+
+import (
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kong-operator/test/helpers"
+)
+
+func TestAdminAPIClientCertificates(t *testing.T) {
+	const uid = types.UID("cp-uid")
+	certs := newAdminAPIClientCertificates()
+	der := func(c helpers.Cert) []byte {
+		block, _ := pem.Decode(c.CertPEM.Bytes())
+		require.NotNil(t, block)
+		return block.Bytes
+	}
+
+	first := helpers.CreateCA(t)
+	getCertificate, err := certs.set(uid, first.CertPEM.Bytes(), first.KeyPEM.Bytes())
+	require.NoError(t, err)
+	cert, err := getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, der(first), cert.Certificate[0])
+
+	t.Log("renewed certificate is returned by the getter of the running instance")
+	renewed := helpers.CreateCA(t)
+	_, err = certs.set(uid, renewed.CertPEM.Bytes(), renewed.KeyPEM.Bytes())
+	require.NoError(t, err)
+	cert, err = getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, der(renewed), cert.Certificate[0])
+
+	t.Log("invalid certificate doesn't replace the current one")
+	_, err = certs.set(uid, []byte("invalid"), renewed.KeyPEM.Bytes())
+	require.Error(t, err)
+	cert, err = getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, der(renewed), cert.Certificate[0])
+
+	t.Log("deleted certificate is not shared with a ControlPlane using the same UID")
+	certs.delete(uid)
+	_, err = certs.set(uid, first.CertPEM.Bytes(), first.KeyPEM.Bytes())
+	require.NoError(t, err)
+	cert, err = getCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, der(renewed), cert.Certificate[0])
+}
@@ -16,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -56,11 +57,18 @@ const requeueAfterBoot = time.Second
 // Reconciler reconciles a ControlPlane object
 type Reconciler struct {
 	client.Client
+	eventRecorder            record.EventRecorder
 	CacheSyncPeriod          time.Duration
 	CacheSyncTimeout         time.Duration
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
+	// ClusterCertificateRenewalConfig configures the lifetime and renewal of the
+	// ControlPlane's Admin API client certificate.
+	ClusterCertificateRenewalConfig secrets.CertificateRenewalConfig
+	// ClusterCertificateIssuer configures the issuer of the ControlPlane's Admin API client certificate.
+	// The cluster CA is used when it's not set.
+	ClusterCertificateIssuer secrets.IssuerConfig
 
 	RestConfig              *rest.Config
 	KubeConfigPath          string
@@ -81,10 +89,15 @@ type Reconciler struct {
 
 	// WatchNamespaces is a list of namespaces to watch. If empty (default), all namespaces are watched.
 	WatchNamespaces []string
+
+	adminAPIClientCertificates *adminAPIClientCertificates
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *Reconciler) SetupWithManager(_ context.Context, mgr ctrl.Manager) error {
+	r.eventRecorder = mgr.GetEventRecorderFor("controlplane")
+	r.adminAPIClientCertificates = newAdminAPIClientCertificates()
+
 	builder := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			CacheSyncTimeout: r.CacheSyncTimeout,
@@ -160,6 +173,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 				return ctrl.Result{}, fmt.Errorf("failed to stop instance: %w", err)
 			}
 		}
+		r.adminAPIClientCertificates.delete(cp.GetUID())
 
 		// remove finalizer
 		if controllerutil.RemoveFinalizer(cp, string(ControlPlaneFinalizerCPInstanceTeardown)) {
@@ -343,16 +357,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return r.patchStatus(ctx, logger, cp)
 	}
 
-	var caSecret corev1.Secret
-	if err := r.Get(ctx, types.NamespacedName{
-		Namespace: r.ClusterCASecretNamespace,
-		Name:      r.ClusterCASecretName,
-	}, &caSecret); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to get CA secret: %w", err)
-	}
-
 	log.Trace(logger, "ensuring mTLS certificate secret exists")
 	res, mtlsSecret, err := r.ensureAdminMTLSCertificateSecret(ctx, cp)
+	if errors.Is(err, secrets.ErrCertificateNotReady) {
+		log.Debug(logger, "waiting for mTLS certificate to be issued")
+		return ctrl.Result{RequeueAfter: secrets.CertificateNotReadyRequeueAfter}, nil
+	}
+	if errors.Is(err, secrets.ErrCertificateIssuanceFailed) {
+		// The certificate is requested again when the request or its Secret changes.
+		log.Info(logger, "mTLS certificate issuance failed", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err != nil || res != op.Noop {
 		return ctrl.Result{}, err
 	}
@@ -365,7 +380,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 			log.Debug(logger, "control plane instance not found, creating new instance")
 			cfgOpts, err := r.constructControlPlaneManagerConfigOptions(
-				logger, cp, &caSecret, mtlsSecret, dataplaneAdminServiceName, dataplaneIngressServiceName,
+				logger, cp, mtlsSecret, dataplaneAdminServiceName, dataplaneIngressServiceName,
 				r.RestConfig.Burst, r.RestConfig.QPS, validatedWatchNamespaces, konnectExtensionProcessor.GetKonnectConfig(),
 			)
 			if err != nil {
@@ -392,7 +407,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	} else {
 		// Calculate the hash of config from the ControlPlane spec.
 		cfgOpts, err := r.constructControlPlaneManagerConfigOptions(
-			logger, cp, &caSecret, mtlsSecret, dataplaneAdminServiceName, dataplaneIngressServiceName,
+			logger, cp, mtlsSecret, dataplaneAdminServiceName, dataplaneIngressServiceName,
 			r.RestConfig.Burst, r.RestConfig.QPS, validatedWatchNamespaces, konnectExtensionProcessor.GetKonnectConfig(),
 		)
 		if err != nil {
@@ -438,7 +453,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Debug(logger, "reconciliation complete for ControlPlane resource")
-	return ctrl.Result{}, nil
+	return ctrl.Result{
+		RequeueAfter: secrets.CertificateRenewalRequeueAfter(mtlsSecret, r.ClusterCertificateRenewalConfig),
+	}, nil
 }
 
 // patchStatus Patches the resource status only when there are changes in the Conditions
@@ -468,7 +485,6 @@ func (r *Reconciler) patchStatus(ctx context.Context, logger logr.Logger, update
 func (r *Reconciler) constructControlPlaneManagerConfigOptions(
 	logger logr.Logger,
 	cp *ControlPlane,
-	caSecret *corev1.Secret,
 	mtlsSecret *corev1.Secret,
 	dataplaneAdminServiceName string,
 	dataplaneIngressServiceName string,
@@ -488,6 +504,19 @@ func (r *Reconciler) constructControlPlaneManagerConfigOptions(
 	if !ok {
 		return nil, fmt.Errorf("failed to get client key from mTLS secret %s", client.ObjectKeyFromObject(mtlsSecret))
 	}
+	// The mTLS secret holds the CA certificates trusted for the DataPlane's Admin API,
+	// provided either by the cluster CA or by the external issuer.
+	caCert, ok := mtlsSecret.Data["ca.crt"]
+	if !ok {
+		return nil, fmt.Errorf("failed to get CA certificate from mTLS secret %s", client.ObjectKeyFromObject(mtlsSecret))
+	}
+
+	// The client certificate is provided to the instance with a getter, so that it isn't part of the
+	// instance's configuration hash and the renewed certificate is used without restarting the instance.
+	getClientCertificate, err := r.adminAPIClientCertificates.set(cp.GetUID(), clientCert, clientKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate from mTLS secret %s: %w", client.ObjectKeyFromObject(mtlsSecret), err)
+	}
 
 	payloadCustomizer, err := defaultPayloadCustomizer()
 	if err != nil {
@@ -508,10 +537,9 @@ func (r *Reconciler) constructControlPlaneManagerConfigOptions(
 		WithKongAdminInitializationRetries(1),
 		WithGatewayAPIControllerName(),
 		WithKongAdminAPIConfig(managercfg.AdminAPIClientConfig{
-			CACert: string(caSecret.Data["tls.crt"]),
+			CACert: string(caCert),
 			TLSClient: managercfg.TLSClientConfig{
-				Cert: string(clientCert),
-				Key:  string(clientKey),
+				GetCertificate: getClientCertificate,
 			},
 		}),
 		WithDisabledLeaderElection(),
@@ -596,6 +624,13 @@ func (r *Reconciler) constructControlPlaneManagerConfigOptions(
 		}
 	}
 
+	if secretName, ok := cp.Annotations[consts.ControlPlaneLastValidConfigSecretAnnotation]; ok && secretName != "" {
+		cfgOpts = append(cfgOpts, WithLastValidConfigSecret(types.NamespacedName{
+			Namespace: cp.Namespace,
+			Name:      secretName,
+		}))
+	}
+
 	switch cp.Spec.DataPlane.Type {
 	case gwtypes.ControlPlaneDataPlaneTargetManagedByType:
 		// If the ControlPlane is owned by a Gateway, we set the Gateway to be the only one to reconcile.
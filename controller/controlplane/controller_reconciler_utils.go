@@ -125,7 +125,10 @@ func (r *Reconciler) ensureAdminMTLSCertificateSecret(
 		},
 		usages,
 		r.ClusterCAKeyConfig,
+		r.ClusterCertificateIssuer,
+		r.ClusterCertificateRenewalConfig,
 		r.Client,
+		r.eventRecorder,
 		matchingLabels,
 	)
 }
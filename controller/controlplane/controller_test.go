@@ -455,10 +455,11 @@ func TestReconciler_Reconcile(t *testing.T) {
 				Build()
 
 			reconciler := Reconciler{
-				Client:                   fakeClient,
-				ClusterCASecretName:      mtlsSecret.Name,
-				ClusterCASecretNamespace: mtlsSecret.Namespace,
-				InstancesManager:         multiinstance.NewManager(logr.Discard()),
+				Client:                     fakeClient,
+				ClusterCASecretName:        mtlsSecret.Name,
+				ClusterCASecretNamespace:   mtlsSecret.Namespace,
+				InstancesManager:           multiinstance.NewManager(logr.Discard()),
+				adminAPIClientCertificates: newAdminAPIClientCertificates(),
 			}
 
 			tc.testBody(t, reconciler, tc.controlplaneReq)
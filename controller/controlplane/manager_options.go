@@ -532,6 +532,13 @@ func WithTranslationOptions(opts *gwtypes.ControlPlaneTranslationOptions) manage
 	}
 }
 
+// WithLastValidConfigSecret sets the Secret the last valid configuration is persisted in.
+func WithLastValidConfigSecret(secret types.NamespacedName) managercfg.Opt {
+	return func(c *managercfg.Config) {
+		c.LastValidConfigSecret = mo.Some(secret)
+	}
+}
+
 // WithConfigDumpEnabled enables/disables dumping Kong configuration in ControlPlane.
 func WithConfigDumpEnabled(enabled bool) managercfg.Opt {
 	return func(c *managercfg.Config) {
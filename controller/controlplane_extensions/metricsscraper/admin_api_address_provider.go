@@ -3,6 +3,8 @@ package metricsscraper
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 
 	discoveryv1 "k8s.io/api/discovery/v1"
@@ -23,12 +25,25 @@ type AdminAPIAddressProvider interface {
 
 type adminAPIAddressProvider struct {
 	client client.Client
+	state  string
 }
 
-// NewAdminAPIAddressProvider creates a new AdminAPIAddressProvider.
+// NewAdminAPIAddressProvider creates a new AdminAPIAddressProvider which returns
+// the addresses of the "live" Admin API endpoints of a DataPlane.
 func NewAdminAPIAddressProvider(cl client.Client) *adminAPIAddressProvider {
 	return &adminAPIAddressProvider{
 		client: cl,
+		state:  consts.DataPlaneStateLabelValueLive,
+	}
+}
+
+// NewPreviewAdminAPIAddressProvider creates a new AdminAPIAddressProvider which
+// returns the addresses of the "preview" Admin API endpoints of a DataPlane
+// that is being rolled out using the BlueGreen strategy.
+func NewPreviewAdminAPIAddressProvider(cl client.Client) *adminAPIAddressProvider {
+	return &adminAPIAddressProvider{
+		client: cl,
+		state:  consts.DataPlaneStateLabelValuePreview,
 	}
 }
 
@@ -42,7 +57,7 @@ func (a *adminAPIAddressProvider) AdminAddressesForDP(ctx context.Context, datap
 		{
 			labelName: consts.DataPlaneServiceStateLabel,
 			selector:  selection.Equals,
-			values:    []string{consts.DataPlaneStateLabelValueLive},
+			values:    []string{a.state},
 		},
 		{
 			labelName: consts.DataPlaneServiceTypeLabel,
@@ -180,3 +195,21 @@ func adminAPIFromEndpoint(
 		Address: fmt.Sprintf("https://%s:%d", address, *port.Port),
 	}, nil
 }
+
+// podIPFromAdminAPIURL returns the IP of the Pod serving the Admin API at the
+// provided URL, as generated by adminAPIFromEndpoint.
+func podIPFromAdminAPIURL(adminAPIURL string) (string, bool) {
+	u, err := url.Parse(adminAPIURL)
+	if err != nil {
+		return "", false
+	}
+	ipAddr, _, found := strings.Cut(u.Hostname(), ".")
+	if !found {
+		return "", false
+	}
+	ip := net.ParseIP(strings.ReplaceAll(ipAddr, "-", "."))
+	if ip == nil {
+		return "", false
+	}
+	return ip.String(), true
+}
@@ -0,0 +1,124 @@
+package metricsscraper
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// KongMetricNameKongUpstreamLatencyMs is the name of the kong_upstream_latency_ms metric.
+	KongMetricNameKongUpstreamLatencyMs = "kong_upstream_latency_ms"
+	// KongMetricNameKongRequestLatencyMs is the name of the kong_request_latency_ms metric.
+	KongMetricNameKongRequestLatencyMs = "kong_request_latency_ms"
+	// KongMetricNameKongKongLatencyMs is the name of the kong_kong_latency_ms metric.
+	KongMetricNameKongKongLatencyMs = "kong_kong_latency_ms"
+	// KongMetricNameKongBandwidthBytes is the name of the kong_bandwidth_bytes metric.
+	KongMetricNameKongBandwidthBytes = "kong_bandwidth_bytes"
+)
+
+// DefaultExportedMetricFamilies is the list of Kong metric families exported
+// when a DataPlaneMetricsExtension does not specify which ones to export.
+var DefaultExportedMetricFamilies = []string{
+	KongMetricNameKongHTTPRequestsTotal,
+	KongMetricNameKongBandwidthBytes,
+	KongMetricNameKongKongLatencyMs,
+	KongMetricNameKongRequestLatencyMs,
+	KongMetricNameKongUpstreamLatencyMs,
+}
+
+// PassthroughCollector is a prometheus.Collector that passes through metrics
+// scraped from DataPlanes.
+// Metrics are grouped by the UID of the DataPlane they were scraped from so that
+// all of a DataPlane's series are replaced on every scrape and can be pruned
+// when the DataPlane goes away.
+type PassthroughCollector struct {
+	lock    sync.RWMutex
+	metrics map[types.UID][]prometheus.Metric
+}
+
+var _ prometheus.Collector = &PassthroughCollector{}
+
+// KongMetricsCollector is a prometheus.Collector that collects Kong metrics
+// enriched with Kubernetes metadata.
+var KongMetricsCollector = NewPassthroughCollector()
+
+// NewPassthroughCollector creates a new PassthroughCollector.
+func NewPassthroughCollector() *PassthroughCollector {
+	return &PassthroughCollector{
+		metrics: make(map[types.UID][]prometheus.Metric),
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *PassthroughCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, metrics := range c.metrics {
+		for _, metric := range metrics {
+			ch <- metric
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+// It doesn't send any descriptors because the exported metric families depend
+// on the configuration, which makes it an unchecked collector.
+func (c *PassthroughCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Set replaces all the metrics of the DataPlane with the given UID.
+func (c *PassthroughCollector) Set(dataplaneUID types.UID, metrics []prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(metrics) == 0 {
+		delete(c.metrics, dataplaneUID)
+		return
+	}
+	c.metrics[dataplaneUID] = metrics
+}
+
+// Delete removes all the metrics of the DataPlane with the given UID.
+func (c *PassthroughCollector) Delete(dataplaneUID types.UID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.metrics, dataplaneUID)
+}
+
+// PassthroughMetric is a prometheus.Metric that passes through a dto.Metric.
+// It allows observing whole counters, gauges and histograms and not observing
+// individual data points like it's done with e.g. prometheus.HistogramVec.
+type PassthroughMetric struct {
+	Name   string
+	Help   string
+	Metric *dto.Metric
+}
+
+var _ prometheus.Metric = &PassthroughMetric{}
+
+// Desc implements prometheus.Metric.
+// Its variable labels are the labels of the passed through metric.
+func (m *PassthroughMetric) Desc() *prometheus.Desc {
+	labels := make([]string, 0, len(m.Metric.GetLabel()))
+	for _, l := range m.Metric.GetLabel() {
+		labels = append(labels, l.GetName())
+	}
+	sort.Strings(labels)
+	return prometheus.NewDesc(m.Name, m.Help, labels, nil)
+}
+
+// Write implements prometheus.Write.
+// Passed parameter dm is an output for metrics (target of write).
+func (m *PassthroughMetric) Write(dm *dto.Metric) error {
+	dm.Counter = m.Metric.Counter
+	dm.Gauge = m.Metric.Gauge
+	dm.Histogram = m.Metric.Histogram
+	dm.Summary = m.Metric.Summary
+	dm.Untyped = m.Metric.Untyped
+	dm.Label = m.Metric.Label
+	dm.TimestampMs = m.Metric.TimestampMs
+
+	return nil
+}
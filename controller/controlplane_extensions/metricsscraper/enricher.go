@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/kong/go-kong/kong"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
@@ -20,7 +24,7 @@ import (
 
 func init() {
 	collectors := []prometheus.Collector{
-		KongUpstreamLatencyMsHistogram,
+		KongMetricsCollector,
 	}
 
 	for _, c := range collectors {
@@ -44,25 +48,67 @@ type metricsEnricher struct {
 	httpClient              *http.Client
 	cl                      client.Client
 	logger                  logr.Logger
+	collector               *PassthroughCollector
+
+	exportedMetricFamiliesLock sync.RWMutex
+	exportedMetricFamilies     sets.Set[string]
+
+	subscribersLock    sync.RWMutex
+	metricsSubscribers []MetricsConsumer
 }
 
-// NewEnricher creates a new MetricsEnricher.
+// NewEnricher creates a new MetricsEnricher which exports the provided
+// Kong metric families to the KongMetricsCollector.
 func NewEnricher(
 	logger logr.Logger,
 	dataplane *operatorv1beta1.DataPlane,
 	cl client.Client,
-	certs certs,
+	getCerts func() certs,
 	adminAPIAddressProvider AdminAPIAddressProvider,
-) (metricsEnricher, error) {
-	return metricsEnricher{
+	exportedMetricFamilies sets.Set[string],
+) (*metricsEnricher, error) {
+	return &metricsEnricher{
 		dataplane:               dataplane,
 		adminAPIAddressProvider: adminAPIAddressProvider,
-		httpClient:              httpClientWithCerts(certs),
+		httpClient:              httpClientWithCerts(getCerts),
 		cl:                      cl,
 		logger:                  logger,
+		collector:               KongMetricsCollector,
+		exportedMetricFamilies:  exportedMetricFamilies,
 	}, nil
 }
 
+// SetExportedMetricFamilies sets the Kong metric families exported by the enricher.
+// Series of the families which are not exported anymore are removed on the next Consume.
+func (me *metricsEnricher) SetExportedMetricFamilies(families sets.Set[string]) {
+	me.exportedMetricFamiliesLock.Lock()
+	defer me.exportedMetricFamiliesLock.Unlock()
+	me.exportedMetricFamilies = families
+}
+
+func (me *metricsEnricher) getExportedMetricFamilies() sets.Set[string] {
+	me.exportedMetricFamiliesLock.RLock()
+	defer me.exportedMetricFamiliesLock.RUnlock()
+	return me.exportedMetricFamilies
+}
+
+// SetSubscribers replaces the consumers of the enriched metrics.
+// Replaced subscribers which implement io.Closer are closed.
+func (me *metricsEnricher) SetSubscribers(subscribers ...MetricsConsumer) error {
+	me.subscribersLock.Lock()
+	old := me.metricsSubscribers
+	me.metricsSubscribers = subscribers
+	me.subscribersLock.Unlock()
+
+	var errs []error
+	for _, s := range old {
+		if c, ok := s.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
 const (
 	// KongMetricTagK8sName is the tag set on Kong Services in the Admin API
 	// configuration to indicate the name of the Kubernetes Service associated
@@ -75,7 +121,11 @@ const (
 )
 
 // Consume consumes the metrics and enriches them with kubernetes metadata.
-func (me metricsEnricher) Consume(ctx context.Context, m Metrics) error {
+// All the series previously exported for the DataPlane are replaced with the
+// enriched metrics so that series of Admin API endpoints, Kong Services or
+// metric families that went away are pruned.
+// The enriched metrics are then passed to the enricher's subscribers.
+func (me *metricsEnricher) Consume(ctx context.Context, m Metrics) error {
 	// TODO: Potentially, create a watch which will get notifications on new
 	// endpoints for a DataPlane.
 	addrs, err := me.adminAPIAddressProvider.AdminAddressesForDP(ctx, me.dataplane)
@@ -85,6 +135,7 @@ func (me metricsEnricher) Consume(ctx context.Context, m Metrics) error {
 		)
 	}
 	if len(addrs) == 0 {
+		me.collector.Delete(me.dataplane.UID)
 		return nil
 	}
 
@@ -99,89 +150,131 @@ func (me metricsEnricher) Consume(ctx context.Context, m Metrics) error {
 	if err != nil {
 		return fmt.Errorf("failed listing Services for DataPlane %s error: %w", client.ObjectKeyFromObject(me.dataplane), err)
 	}
+	servicesByName := make(map[string]*kong.Service, len(services))
+	for _, svc := range services {
+		if svc.Name != nil {
+			servicesByName[*svc.Name] = svc
+		}
+	}
 
-	for dataplaneURL, metricFamily := range m.metrics {
-		for name, metric := range metricFamily {
-			if name != KongMetricNameKongUpstreamLatencyMs {
+	exported := me.getExportedMetricFamilies()
+	var (
+		enriched        []prometheus.Metric
+		enrichedMetrics = Metrics{metrics: make(metricsMap, len(m.metrics))}
+	)
+	for dataplaneURL, metricFamilies := range m.metrics {
+		// Endpoints that went away in between the scrape and now are skipped.
+		if !lo.Contains(addrs, string(dataplaneURL)) {
+			continue
+		}
+		enrichedFamilies := make(map[metricName]*dto.MetricFamily, len(metricFamilies))
+		for name, family := range metricFamilies {
+			if !exported.Has(string(name)) {
 				continue
 			}
-
-			for _, m := range metric.GetMetric() {
-				// Extract the name of the service from the metric labels.
-				// This has the name of the service in the Kong configuration.
-				serviceLabel, ok := lo.Find(m.GetLabel(),
-					func(p *dto.LabelPair) bool {
-						return *p.Name == "service"
-					},
-				)
-				if !ok || serviceLabel.Value == nil {
-					me.logger.Info("'service' label not found", "metric", name)
-					continue
-				}
-
-				svc, ok := lo.Find(services, func(s *kong.Service) bool {
-					return s.Name != nil && serviceLabel.GetValue() == *s.Name
+			enrichedFamily := &dto.MetricFamily{
+				Name:   family.Name,
+				Help:   family.Help,
+				Type:   family.Type,
+				Unit:   family.Unit,
+				Metric: make([]*dto.Metric, 0, len(family.GetMetric())),
+			}
+			for _, metric := range family.GetMetric() {
+				enrichedMetric := me.enrich(metric, servicesByName, dataplaneURL)
+				enrichedFamily.Metric = append(enrichedFamily.Metric, enrichedMetric)
+				enriched = append(enriched, &PassthroughMetric{
+					Name:   family.GetName(),
+					Help:   family.GetHelp(),
+					Metric: enrichedMetric,
 				})
-				if !ok {
-					me.logger.Info("service not found in config", "service", *serviceLabel.Value)
-					continue
-				}
-
-				tagK8sName, ok := extractAndTrimPrefix(svc.Tags, KongMetricTagK8sName)
-				if !ok {
-					me.logger.Info(KongMetricTagK8sName + " tag not found for service " + *svc.Name)
-					continue
-				}
-
-				tagK8sNamespace, ok := extractAndTrimPrefix(svc.Tags, KongMetricTagK8sNamespace)
-				if !ok {
-					me.logger.Info(KongMetricTagK8sNamespace + " tag not found for service " + *svc.Name)
-					continue
-				}
-
-				// Below labels have to match the ones in HistogramPassthroughMetric.Desc.
-				m.Label = []*dto.LabelPair{
-					{
-						Name:  lo.ToPtr("namespace"),
-						Value: lo.ToPtr(tagK8sNamespace),
-					},
-					{
-						Name:  lo.ToPtr("service"),
-						Value: lo.ToPtr(tagK8sName),
-					},
-					{
-						Name:  lo.ToPtr("kubernetes_apiversion"),
-						Value: lo.ToPtr("v1"),
-					},
-					{
-						Name:  lo.ToPtr("kubernetes_kind"),
-						Value: lo.ToPtr("service"),
-					},
-					{
-						Name:  lo.ToPtr("kubernetes_name"),
-						Value: lo.ToPtr(tagK8sName),
-					},
-					{
-						Name:  lo.ToPtr("kubernetes_namespace"),
-						Value: lo.ToPtr(tagK8sNamespace),
-					},
-					{
-						Name:  lo.ToPtr("dataplane_url"),
-						Value: lo.ToPtr(string(dataplaneURL)),
-					},
-				}
-
-				KongUpstreamLatencyMsHistogram.Observe(m, dataplaneURL)
 			}
+			enrichedFamilies[name] = enrichedFamily
+		}
+		enrichedMetrics.metrics[dataplaneURL] = enrichedFamilies
+	}
+	me.collector.Set(me.dataplane.UID, enriched)
+
+	me.subscribersLock.RLock()
+	defer me.subscribersLock.RUnlock()
+	var errs []error
+	for _, subscriber := range me.metricsSubscribers {
+		if err := subscriber.Consume(ctx, enrichedMetrics); err != nil {
+			errs = append(errs, fmt.Errorf("failed to consume enriched metrics for DataPlane %s: %w",
+				client.ObjectKeyFromObject(me.dataplane), err,
+			))
 		}
 	}
+	return errors.Join(errs...)
+}
 
-	// NOTE: to consider
-	// Now that we've observed the metrics, we can remove the time series about
-	// dataplanes instances that do not exist anymore.
-	// KongUpstreamLatencyHistogram.Prune(addrs)
+// enrich returns a copy of the provided metric with the Kong Service label
+// replaced with the Kubernetes Service metadata and the DataPlane labels added.
+// Metrics of Kong Services which are not associated with a Kubernetes Service
+// get empty Kubernetes Service labels.
+func (me *metricsEnricher) enrich(
+	metric *dto.Metric, services map[string]*kong.Service, dataplaneURL adminAPIEndpointURL,
+) *dto.Metric {
+	var (
+		kongServiceName string
+		k8sName         string
+		k8sNamespace    string
+		k8sAPIVersion   string
+		k8sKind         string
+	)
+	if l, ok := lo.Find(metric.GetLabel(), func(p *dto.LabelPair) bool {
+		return p.GetName() == "service"
+	}); ok {
+		kongServiceName = l.GetValue()
+	}
+	if svc, ok := services[kongServiceName]; ok {
+		name, nameOK := extractAndTrimPrefix(svc.Tags, KongMetricTagK8sName)
+		namespace, namespaceOK := extractAndTrimPrefix(svc.Tags, KongMetricTagK8sNamespace)
+		if nameOK && namespaceOK {
+			k8sName, k8sNamespace = name, namespace
+			k8sAPIVersion, k8sKind = "v1", "service"
+		}
+	}
 
-	return nil
+	enrichedLabels := map[string]string{
+		"kong_service":          kongServiceName,
+		"namespace":             k8sNamespace,
+		"service":               k8sName,
+		"kubernetes_apiversion": k8sAPIVersion,
+		"kubernetes_kind":       k8sKind,
+		"kubernetes_name":       k8sName,
+		"kubernetes_namespace":  k8sNamespace,
+		"dataplane_name":        me.dataplane.Name,
+		"dataplane_namespace":   me.dataplane.Namespace,
+		"dataplane_url":         string(dataplaneURL),
+	}
+	labels := make([]*dto.LabelPair, 0, len(metric.GetLabel())+len(enrichedLabels))
+	for _, l := range metric.GetLabel() {
+		if _, ok := enrichedLabels[l.GetName()]; ok {
+			continue
+		}
+		labels = append(labels, l)
+	}
+	for name, value := range enrichedLabels {
+		labels = append(labels, &dto.LabelPair{
+			Name:  lo.ToPtr(name),
+			Value: lo.ToPtr(value),
+		})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].GetName() < labels[j].GetName()
+	})
+
+	// The scraped metric is not modified as the scraped metrics are shared
+	// with other consumers.
+	return &dto.Metric{
+		Label:       labels,
+		Counter:     metric.Counter,
+		Gauge:       metric.Gauge,
+		Histogram:   metric.Histogram,
+		Summary:     metric.Summary,
+		Untyped:     metric.Untyped,
+		TimestampMs: metric.TimestampMs,
+	}
 }
 
 // extractAndTrimPrefix looks for a tag with the given prefix and returns the
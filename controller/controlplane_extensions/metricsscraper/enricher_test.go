@@ -0,0 +1,260 @@
+package metricsscraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+	"github.com/kong/kong-operator/test/mocks/metricsmocks"
+)
+
+func kongAdminAPIServicesServer(t *testing.T) *httptest.Server {
+	const servicesBody = `{
+		"data": [
+			{
+				"id": "1a3b2c4d-0000-0000-0000-000000000001",
+				"name": "httproute.default.httproute-echo.0",
+				"tags": ["k8s-name:echo", "k8s-namespace:default"]
+			},
+			{
+				"id": "1a3b2c4d-0000-0000-0000-000000000002",
+				"name": "untagged"
+			}
+		],
+		"next": null
+	}`
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write([]byte(servicesBody)); err != nil {
+			t.Logf("failed to write response: %v", err)
+		}
+	}))
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func counterFamily(name string, metrics ...*dto.Metric) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name:   proto.String(name),
+		Help:   proto.String(name + " help"),
+		Type:   dto.MetricType_COUNTER.Enum(),
+		Metric: metrics,
+	}
+}
+
+func counterMetric(value float64, labels ...string) *dto.Metric {
+	m := &dto.Metric{
+		Counter: &dto.Counter{Value: proto.Float64(value)},
+	}
+	for i := 0; i+1 < len(labels); i += 2 {
+		m.Label = append(m.Label, &dto.LabelPair{
+			Name:  proto.String(labels[i]),
+			Value: proto.String(labels[i+1]),
+		})
+	}
+	return m
+}
+
+func gatherLabels(t *testing.T, collector *PassthroughCollector) map[string][]map[string]string {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	ret := make(map[string][]map[string]string)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			ret[f.GetName()] = append(ret[f.GetName()], labels)
+		}
+	}
+	return ret
+}
+
+type recordingConsumer struct {
+	consumed []Metrics
+	closed   bool
+}
+
+func (c *recordingConsumer) Consume(_ context.Context, m Metrics) error {
+	c.consumed = append(c.consumed, m)
+	return nil
+}
+
+func (c *recordingConsumer) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestMetricsEnricher_Consume(t *testing.T) {
+	adminAPI := kongAdminAPIServicesServer(t)
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dataplane-1",
+			Namespace: "default",
+			UID:       "dp-uid",
+		},
+	}
+	metrics := Metrics{
+		metrics: metricsMap{
+			adminAPIEndpointURL(adminAPI.URL): {
+				KongMetricNameKongHTTPRequestsTotal: counterFamily(KongMetricNameKongHTTPRequestsTotal,
+					counterMetric(10, "service", "httproute.default.httproute-echo.0", "route", "r", "code", "200"),
+					counterMetric(1, "service", "untagged", "route", "r", "code", "500"),
+				),
+				"kong_nginx_connections_total": counterFamily("kong_nginx_connections_total",
+					counterMetric(5, "state", "active"),
+				),
+			},
+			// Metrics of endpoints which are not returned by the address provider anymore are pruned.
+			"https://10.0.0.2:8444": {
+				KongMetricNameKongHTTPRequestsTotal: counterFamily(KongMetricNameKongHTTPRequestsTotal,
+					counterMetric(10, "service", "httproute.default.httproute-echo.0", "route", "r", "code", "200"),
+				),
+			},
+		},
+	}
+
+	collector := NewPassthroughCollector()
+	enricher := &metricsEnricher{
+		dataplane: dataplane,
+		adminAPIAddressProvider: &metricsmocks.MockAdminAPIAddressProvider{
+			Addresses: []string{adminAPI.URL},
+		},
+		httpClient:             http.DefaultClient,
+		logger:                 logr.Discard(),
+		collector:              collector,
+		exportedMetricFamilies: sets.New(DefaultExportedMetricFamilies...),
+	}
+
+	require.NoError(t, enricher.Consume(t.Context(), metrics))
+	assert.Equal(t,
+		map[string][]map[string]string{
+			KongMetricNameKongHTTPRequestsTotal: {
+				{
+					"code":                  "200",
+					"route":                 "r",
+					"kong_service":          "httproute.default.httproute-echo.0",
+					"namespace":             "default",
+					"service":               "echo",
+					"kubernetes_apiversion": "v1",
+					"kubernetes_kind":       "service",
+					"kubernetes_name":       "echo",
+					"kubernetes_namespace":  "default",
+					"dataplane_name":        "dataplane-1",
+					"dataplane_namespace":   "default",
+					"dataplane_url":         adminAPI.URL,
+				},
+				{
+					// Empty labels are not exported.
+					"code":                "500",
+					"route":               "r",
+					"kong_service":        "untagged",
+					"dataplane_name":      "dataplane-1",
+					"dataplane_namespace": "default",
+					"dataplane_url":       adminAPI.URL,
+				},
+			},
+		},
+		gatherLabels(t, collector),
+	)
+
+	t.Run("subscribers consume the enriched metrics", func(t *testing.T) {
+		subscriber := &recordingConsumer{}
+		require.NoError(t, enricher.SetSubscribers(subscriber))
+		require.NoError(t, enricher.Consume(t.Context(), metrics))
+
+		require.Len(t, subscriber.consumed, 1)
+		consumed := subscriber.consumed[0].metrics
+		require.Len(t, consumed, 1, "only metrics of current endpoints are passed on")
+		families := consumed[adminAPIEndpointURL(adminAPI.URL)]
+		require.Len(t, families, 1, "only exported metric families are passed on")
+		enriched := families[KongMetricNameKongHTTPRequestsTotal].GetMetric()
+		require.Len(t, enriched, 2)
+		assert.True(t, lo.ContainsBy(enriched[0].GetLabel(), func(l *dto.LabelPair) bool {
+			return l.GetName() == "kubernetes_name" && l.GetValue() == "echo"
+		}))
+
+		require.NoError(t, enricher.SetSubscribers())
+		assert.True(t, subscriber.closed, "replaced subscribers are closed")
+	})
+
+	t.Run("metric families which are not exported anymore are pruned", func(t *testing.T) {
+		enricher.SetExportedMetricFamilies(sets.New("kong_nginx_connections_total"))
+		require.NoError(t, enricher.Consume(t.Context(), metrics))
+
+		labels := gatherLabels(t, collector)
+		assert.NotContains(t, labels, KongMetricNameKongHTTPRequestsTotal)
+		require.Len(t, labels["kong_nginx_connections_total"], 1)
+		assert.Equal(t, "active", labels["kong_nginx_connections_total"][0]["state"])
+	})
+
+	t.Run("DataPlane metrics are pruned when it has no Admin API endpoints", func(t *testing.T) {
+		enricher.adminAPIAddressProvider = &metricsmocks.MockAdminAPIAddressProvider{}
+		require.NoError(t, enricher.Consume(t.Context(), metrics))
+		assert.Empty(t, gatherLabels(t, collector))
+	})
+}
+
+func TestExportedMetricFamiliesForExtension(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			name:     "defaults are used when the annotation is not set",
+			expected: DefaultExportedMetricFamilies,
+		},
+		{
+			name: "annotation value is parsed",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionExportedMetricsAnnotation: " kong_http_requests_total, ,kong_bandwidth_bytes",
+			},
+			expected: []string{"kong_http_requests_total", "kong_bandwidth_bytes"},
+		},
+		{
+			name: "empty annotation exports no metrics",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionExportedMetricsAnnotation: "",
+			},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := &operatorv1alpha1.DataPlaneMetricsExtension{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tt.annotations,
+				},
+			}
+			assert.Equal(t, tt.expected, exportedMetricFamiliesForExtension(ext))
+		})
+	}
+}
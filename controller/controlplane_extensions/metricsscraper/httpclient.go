@@ -4,30 +4,72 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+	"sync"
 	"time"
 )
 
-func httpClientWithCerts(certs certs) *http.Client {
-	rootCAs := x509.NewCertPool()
-	rootCAs.AddCert(certs.CA)
-
+// httpClientWithCerts returns an HTTP client using the mTLS certs returned by getCerts.
+// The certs are replaced when the cluster CA Secret changes, e.g. when the cluster CA
+// is rotated, so they are checked for every request.
+func httpClientWithCerts(getCerts func() certs) *http.Client {
 	httpClient := *http.DefaultClient
 	httpClient.Timeout = 10 * time.Second
-	httpClient.Transport = &http.Transport{
+	httpClient.Transport = &reloadingTransport{
+		getCerts: getCerts,
+	}
+	return &httpClient
+}
+
+// reloadingTransport is an http.RoundTripper using a transport configured with the current
+// mTLS certs. The transport is recreated, closing the connections made with the previous
+// certs, when the certs change.
+type reloadingTransport struct {
+	getCerts func() certs
+
+	lock      sync.Mutex
+	cert      *x509.Certificate
+	transport *http.Transport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *reloadingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport, err := t.currentTransport()
+	if err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(req)
+}
+
+func (t *reloadingTransport) currentTransport() (*http.Transport, error) {
+	c := t.getCerts()
+	if c.Cert == nil {
+		return nil, ErrMTLSCertsNotInitialized
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.transport != nil && t.cert == c.Cert {
+		return t.transport, nil
+	}
+	if t.transport != nil {
+		t.transport.CloseIdleConnections()
+	}
+	t.cert = c.Cert
+	t.transport = &http.Transport{
 		TLSHandshakeTimeout: 10 * time.Second,
 		TLSClientConfig: &tls.Config{
 			Certificates: []tls.Certificate{
 				{
 					Certificate: [][]byte{
-						certs.Cert.Raw,
+						c.Cert.Raw,
 					},
-					Leaf:       certs.Cert,
-					PrivateKey: certs.Key,
+					Leaf:       c.Cert,
+					PrivateKey: c.Key,
 				},
 			},
-			RootCAs:    rootCAs,
+			RootCAs:    c.CAs,
 			MinVersion: tls.VersionTLS12,
 		},
 	}
-	return &httpClient
+	return t.transport, nil
 }
@@ -4,10 +4,14 @@ import (
 	"context"
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,14 +20,19 @@ import (
 	"github.com/cloudflare/cfssl/signer"
 	"github.com/cloudflare/cfssl/signer/local"
 	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/samber/lo"
 	certificatesv1 "k8s.io/api/certificates/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
 
+	"github.com/kong/kong-operator/controller/pkg/extensions"
 	"github.com/kong/kong-operator/controller/pkg/log"
 	"github.com/kong/kong-operator/controller/pkg/secrets"
 	gwtypes "github.com/kong/kong-operator/internal/types"
@@ -32,8 +41,10 @@ import (
 
 type certs struct {
 	Key  crypto.Signer
-	CA   *x509.Certificate
 	Cert *x509.Certificate
+	// CAs are the CA certificates trusted for the DataPlanes' Admin API certificates.
+	// During a rotation of the cluster CA they contain both the old and the new CA.
+	CAs *x509.CertPool
 }
 
 // MetricsScrapePipeline is a pipeline for scraping and enriching metrics.
@@ -45,6 +56,77 @@ type MetricsScrapePipeline interface {
 type metricsPipeline struct {
 	MetricsScraper
 	MetricsEnricher
+
+	// consumers are the consumers which consume the scraped metrics in
+	// addition to the enricher.
+	consumers []MetricsConsumer
+
+	// otlpExporterConfigs are the configurations of the OTLP exporters which
+	// consume the enriched metrics.
+	otlpExporterConfigs []OTLPExporterConfig
+}
+
+// pipelineConfig is the configuration of a metrics pipeline derived from the
+// DataPlaneMetricsExtensions referenced by a ControlPlane.
+type pipelineConfig struct {
+	exportedMetricFamilies sets.Set[string]
+	otlpExporters          []OTLPExporterConfig
+}
+
+// Consume passes the scraped metrics to the enricher and the other consumers.
+func (p metricsPipeline) Consume(ctx context.Context, m Metrics) error {
+	errs := []error{p.MetricsEnricher.Consume(ctx, m)}
+	for _, c := range p.consumers {
+		errs = append(errs, c.Consume(ctx, m))
+	}
+	return errors.Join(errs...)
+}
+
+// configure applies the configuration to the pipeline's enricher.
+// OTLP exporters are only recreated when their configuration changes so that
+// connections to the collectors are reused.
+func (p *metricsPipeline) configure(dp *operatorv1beta1.DataPlane, cfg pipelineConfig) error {
+	e, ok := p.MetricsEnricher.(*metricsEnricher)
+	if !ok {
+		return nil
+	}
+	e.SetExportedMetricFamilies(cfg.exportedMetricFamilies)
+
+	if cmp.Equal(p.otlpExporterConfigs, cfg.otlpExporters, cmpopts.EquateEmpty()) {
+		return nil
+	}
+	exporters := make([]MetricsConsumer, 0, len(cfg.otlpExporters))
+	for _, c := range cfg.otlpExporters {
+		exporter, err := NewOTLPExporter(c, dp)
+		if err != nil {
+			for _, created := range exporters {
+				_ = created.(*otlpExporter).Close()
+			}
+			return err
+		}
+		exporters = append(exporters, exporter)
+	}
+	p.otlpExporterConfigs = cfg.otlpExporters
+	return e.SetSubscribers(exporters...)
+}
+
+// close releases the resources held by the pipeline.
+func (p *metricsPipeline) close() error {
+	if e, ok := p.MetricsEnricher.(*metricsEnricher); ok {
+		return e.SetSubscribers()
+	}
+	return nil
+}
+
+// closePipeline releases the resources held by the provided pipeline.
+func (msm *Manager) closePipeline(pipeline MetricsScrapePipeline) {
+	p, ok := pipeline.(*metricsPipeline)
+	if !ok {
+		return
+	}
+	if err := p.close(); err != nil {
+		msm.logger.Error(err, "failed to close metrics pipeline", "dataplane_uid", p.DataPlaneUID())
+	}
 }
 
 // Manager is a manager for metrics scrapers.
@@ -53,14 +135,19 @@ type Manager struct {
 	scrapeInterval           time.Duration
 	client                   client.Client
 	caSecretNN               types.NamespacedName
+	certsLock                sync.RWMutex
 	certs                    certs
+	caFingerprint            string
 	pipelinesNotificationsCh chan scrapeUpdateNotification
 	pipelinesLock            sync.RWMutex
 	pipelines                map[types.UID]MetricsScrapePipeline
 	cpNNToDpUID              map[types.NamespacedName]types.UID
 	clusterCAKeyConfig       secrets.KeyConfig
+	podMetrics               *PodMetricsStore
 }
 
+var _ PodMetricsProvider = &Manager{}
+
 // NewManager creates new MetricsScrapeManager.
 func NewManager(
 	logger logr.Logger,
@@ -78,23 +165,32 @@ func NewManager(
 		pipelines:                make(map[types.UID]MetricsScrapePipeline),
 		cpNNToDpUID:              make(map[types.NamespacedName]types.UID),
 		clusterCAKeyConfig:       clusterCAKeyConfig,
+		podMetrics:               NewPodMetricsStore(),
 	}
 }
 
+// PodMetrics returns the per Pod metrics of the provided DataPlane Pod computed
+// out of the metrics scraped from it. Only the Pods of DataPlanes which are
+// scraped, i.e. which belong to a ControlPlane with a DataPlaneMetricsExtension,
+// have metrics.
+func (msm *Manager) PodMetrics(pod types.NamespacedName) (PodMetrics, bool) {
+	return msm.podMetrics.PodMetrics(pod)
+}
+
+// pruneDataPlaneMetrics removes all the metrics exported for the DataPlane with the given UID.
+func (msm *Manager) pruneDataPlaneMetrics(dataplaneUID types.UID) {
+	KongMetricsCollector.Delete(dataplaneUID)
+	msm.podMetrics.Delete(dataplaneUID)
+}
+
 // initMTLSCerts creates mTLS certs for the manager so that it can use them for
 // secure communication with DataPlane's AdminAPI endpoints.
 // When successful, it sets the certs on the manager.
 func (msm *Manager) initMTLSCerts(ctx context.Context) error {
 	msm.logger.Info("getting CA cluster secret to generate certs for MTLs communication with Kong Gateway", "secret", msm.caSecretNN)
-	var (
-		caCert *x509.Certificate
-		caKey  crypto.Signer
-	)
-	if err := retry.Do(
+	return retry.Do(
 		func() error {
-			var err error
-			caCert, caKey, err = msm.getCASecretAndKey(ctx)
-			return err
+			return msm.reloadMTLSCerts(ctx)
 		},
 		retry.Context(ctx),
 		retry.Attempts(0),
@@ -108,9 +204,33 @@ func (msm *Manager) initMTLSCerts(ctx context.Context) error {
 				"error", err,
 			)
 		}),
-	); err != nil {
+	)
+}
+
+// reloadMTLSCerts creates new mTLS certs for the manager when the CAs in the cluster
+// CA Secret have changed since the certs were created, e.g. because the cluster CA is
+// being rotated. The certs are signed by the CA currently signing certificates and trust
+// all the CAs in the cluster CA trust bundle.
+func (msm *Manager) reloadMTLSCerts(ctx context.Context) error {
+	var caSecret corev1.Secret
+	if err := msm.client.Get(ctx, msm.caSecretNN, &caSecret); err != nil {
+		return err
+	}
+	fingerprint := caFingerprint(&caSecret)
+	msm.certsLock.RLock()
+	upToDate := msm.certs.Cert != nil && msm.caFingerprint == fingerprint
+	msm.certsLock.RUnlock()
+	if upToDate {
+		return nil
+	}
+
+	caCert, caKey, err := msm.parseCASecret(&caSecret)
+	if err != nil {
 		return err
 	}
+	caCerts := x509.NewCertPool()
+	caCerts.AddCert(caCert)
+	caCerts.AppendCertsFromPEM(secrets.CATrustBundle(&caSecret))
 
 	signingAlgorithm := secrets.SignatureAlgorithmForKeyType(msm.clusterCAKeyConfig.Type)
 	template := x509.CertificateRequest{
@@ -159,21 +279,42 @@ func (msm *Manager) initMTLSCerts(ctx context.Context) error {
 		return err
 	}
 
+	msm.certsLock.Lock()
+	defer msm.certsLock.Unlock()
 	msm.certs = certs{
-		CA:   caCert,
+		CAs:  caCerts,
 		Cert: cert,
 		Key:  csrKey,
 	}
+	msm.caFingerprint = fingerprint
 	return nil
 }
 
-func (msm *Manager) getCASecretAndKey(ctx context.Context) (*x509.Certificate, crypto.Signer, error) {
-	var caSecret corev1.Secret
-	err := msm.client.Get(ctx, msm.caSecretNN, &caSecret)
-	if err != nil {
-		return nil, nil, err
-	}
+// caFingerprint identifies the CA signing the certificates and the trusted CAs stored
+// in the cluster CA Secret, so that the certs aren't recreated when only its metadata changes.
+func caFingerprint(caSecret *corev1.Secret) string {
+	h := sha256.New()
+	h.Write(caSecret.Data[consts.TLSCRT])
+	h.Write(secrets.CATrustBundle(caSecret))
+	return hex.EncodeToString(h.Sum(nil))
+}
 
+// getCerts returns the mTLS certs used to communicate with DataPlanes' Admin API
+// endpoints and a flag indicating whether they have already been initialized.
+func (msm *Manager) getCerts() (certs, bool) {
+	msm.certsLock.RLock()
+	defer msm.certsLock.RUnlock()
+	return msm.certs, msm.certs.Cert != nil
+}
+
+// currentCerts returns the mTLS certs used to communicate with DataPlanes' Admin API
+// endpoints. They're replaced when the cluster CA Secret changes.
+func (msm *Manager) currentCerts() certs {
+	c, _ := msm.getCerts()
+	return c
+}
+
+func (msm *Manager) parseCASecret(caSecret *corev1.Secret) (*x509.Certificate, crypto.Signer, error) {
 	ca, ok := caSecret.Data[consts.TLSCRT]
 	if !ok {
 		return nil, nil, fmt.Errorf(consts.TLSCRT + " field not found")
@@ -229,6 +370,10 @@ func (msm *Manager) Start(ctx context.Context) error {
 				}
 
 			case <-ticker.C:
+				if err := msm.reloadMTLSCerts(ctx); err != nil {
+					msm.logger.Error(err, "failed to reload mTLS certs for communication with Kong Gateway")
+				}
+
 				msm.pipelinesLock.RLock()
 				pipeline := lo.Values(msm.pipelines)
 				msm.pipelinesLock.RUnlock()
@@ -244,6 +389,14 @@ func (msm *Manager) Start(ctx context.Context) error {
 							msm.logger.Error(err, "failed to consume metrics")
 							return
 						}
+						// The pipeline might have been removed while it was running,
+						// in which case the just exported metrics have to be pruned.
+						msm.pipelinesLock.RLock()
+						_, ok := msm.pipelines[p.DataPlaneUID()]
+						msm.pipelinesLock.RUnlock()
+						if !ok {
+							msm.pruneDataPlaneMetrics(p.DataPlaneUID())
+						}
 					}(p)
 				}
 
@@ -324,7 +477,11 @@ func (msm *Manager) Add(cp *gwtypes.ControlPlane, pipeline MetricsScrapePipeline
 		// if it's the same DataPlane. If it's not, we need to remove the old
 		// scraper.
 		if oldDpDUID != dpUID {
+			if old, ok := msm.pipelines[oldDpDUID]; ok {
+				msm.closePipeline(old)
+			}
 			delete(msm.pipelines, oldDpDUID)
+			msm.pruneDataPlaneMetrics(oldDpDUID)
 		}
 	}
 	msm.cpNNToDpUID[cpNN] = dpUID
@@ -341,8 +498,12 @@ func (msm *Manager) RemoveForControlPlaneNN(cpNN types.NamespacedName) {
 		return
 	}
 
+	if p, ok := msm.pipelines[dpUID]; ok {
+		msm.closePipeline(p)
+	}
 	delete(msm.pipelines, dpUID)
 	delete(msm.cpNNToDpUID, cpNN)
+	msm.pruneDataPlaneMetrics(dpUID)
 	log.Debug(msm.logger, "removed metrics scraper for ControlPlane", cpNN, "dataplane_uid", dpUID)
 }
 
@@ -382,10 +543,30 @@ func (msm *Manager) enableMetricsScraperForControlPlanesDataPlane(
 		return fmt.Errorf("failed to get DataPlane %s: %w", dpNN, err)
 	}
 
+	cfg, err := pipelineConfigForControlPlane(ctx, msm.client, msm.logger, controlplane)
+	if err != nil {
+		return err
+	}
+
+	// If the DataPlane is already scraped, only the pipeline's configuration
+	// might have changed.
+	msm.pipelinesLock.RLock()
+	existing, ok := msm.pipelines[dp.UID]
+	msm.pipelinesLock.RUnlock()
+	if ok {
+		if p, isMetricsPipeline := existing.(*metricsPipeline); isMetricsPipeline {
+			if err := p.configure(&dp, cfg); err != nil {
+				return fmt.Errorf("failed to configure metrics pipeline for DataPlane %s: %w", dpNN, err)
+			}
+		}
+		msm.Add(controlplane, existing)
+		return nil
+	}
+
 	adminAPIAddressProvider := NewAdminAPIAddressProvider(msm.client)
-	httpClient := httpClientWithCerts(msm.certs)
+	httpClient := httpClientWithCerts(msm.currentCerts)
 
-	enricher, err := NewEnricher(msm.logger, &dp, msm.client, msm.certs, adminAPIAddressProvider)
+	enricher, err := NewEnricher(msm.logger, &dp, msm.client, msm.currentCerts, adminAPIAddressProvider, cfg.exportedMetricFamilies)
 	if err != nil {
 		return fmt.Errorf("failed to create metrics enricher: %w", err)
 	}
@@ -393,6 +574,12 @@ func (msm *Manager) enableMetricsScraperForControlPlanesDataPlane(
 	pipeline := &metricsPipeline{
 		MetricsScraper:  NewPrometheusMetricsScraper(msm.logger, &dp, httpClient, adminAPIAddressProvider),
 		MetricsEnricher: enricher,
+		consumers: []MetricsConsumer{
+			newPodMetricsConsumer(&dp, msm.client, msm.podMetrics),
+		},
+	}
+	if err := pipeline.configure(&dp, cfg); err != nil {
+		return fmt.Errorf("failed to configure metrics pipeline for DataPlane %s: %w", dpNN, err)
 	}
 
 	if msm.Add(controlplane, pipeline) {
@@ -401,6 +588,60 @@ func (msm *Manager) enableMetricsScraperForControlPlanesDataPlane(
 	return nil
 }
 
+// pipelineConfigForControlPlane returns the configuration of the metrics pipeline
+// for the DataPlane of the provided ControlPlane.
+// The exported Kong metric families are the union of the metric families
+// configured in all the DataPlaneMetricsExtensions referenced by the
+// ControlPlane, with DefaultExportedMetricFamilies used for extensions which
+// do not configure them.
+// Each extension configuring an OTLP endpoint adds an OTLP exporter. Invalid
+// OTLP configurations are logged and skipped so that they do not prevent
+// metrics from being exported otherwise.
+func pipelineConfigForControlPlane(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	controlplane *gwtypes.ControlPlane,
+) (pipelineConfig, error) {
+	exts, err := extensions.GetAllDataPlaneMetricExtensionsForControlPlane(ctx, cl, controlplane)
+	if err != nil {
+		return pipelineConfig{}, fmt.Errorf("failed to get DataPlaneMetricsExtensions for ControlPlane %s: %w",
+			client.ObjectKeyFromObject(controlplane), err,
+		)
+	}
+
+	cfg := pipelineConfig{
+		exportedMetricFamilies: sets.New[string](),
+	}
+	for _, ext := range exts {
+		cfg.exportedMetricFamilies.Insert(exportedMetricFamiliesForExtension(&ext)...)
+
+		otlpCfg, ok, err := otlpExporterConfigForExtension(&ext)
+		if err != nil {
+			logger.Error(err, "invalid OTLP configuration in DataPlaneMetricsExtension",
+				"extension", client.ObjectKeyFromObject(&ext),
+			)
+			continue
+		}
+		if ok {
+			cfg.otlpExporters = append(cfg.otlpExporters, otlpCfg)
+		}
+	}
+	return cfg, nil
+}
+
+// exportedMetricFamiliesForExtension returns the Kong metric families configured
+// in the DataPlaneMetricsExtension's exported metrics annotation.
+func exportedMetricFamiliesForExtension(ext *operatorv1alpha1.DataPlaneMetricsExtension) []string {
+	v, ok := ext.Annotations[consts.DataPlaneMetricsExtensionExportedMetricsAnnotation]
+	if !ok {
+		return DefaultExportedMetricFamilies
+	}
+	return lo.Compact(lo.Map(strings.Split(v, ","), func(s string, _ int) string {
+		return strings.TrimSpace(s)
+	}))
+}
+
 func signCertificate(
 	csr certificatesv1.CertificateSigningRequestSpec,
 	key crypto.Signer,
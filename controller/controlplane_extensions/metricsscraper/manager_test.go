@@ -558,3 +558,56 @@ func TestMetricsScrapeManager_Start(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsScrapeManager_ReloadMTLSCerts(t *testing.T) {
+	ctx := t.Context()
+	keyConfig := secrets.KeyConfig{
+		Type: x509.ECDSA,
+	}
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca-secret",
+			Namespace: "kong-system",
+		},
+	}
+	require.NoError(t, secrets.StartClusterCARotation(caSecret, keyConfig, time.Hour))
+	require.NoError(t, secrets.PromoteClusterCA(caSecret))
+	secrets.RetireClusterCA(caSecret)
+	secrets.CompleteClusterCARotation(caSecret)
+	oldCA, err := secrets.ParseSecretCertificate(caSecret)
+	require.NoError(t, err)
+
+	fakeClient := fake.NewClientBuilder().WithObjects(caSecret).Build()
+	msm := NewManager(logr.Discard(), time.Second, fakeClient, client.ObjectKeyFromObject(caSecret), keyConfig)
+	require.NoError(t, msm.initMTLSCerts(ctx))
+	initial := msm.currentCerts()
+	require.NoError(t, initial.Cert.CheckSignatureFrom(oldCA))
+
+	t.Log("certs are kept when only the Secret's metadata changes")
+	caSecret.Annotations = map[string]string{"foo": "bar"}
+	require.NoError(t, fakeClient.Update(ctx, caSecret))
+	require.NoError(t, msm.reloadMTLSCerts(ctx))
+	require.Same(t, initial.Cert, msm.currentCerts().Cert)
+
+	t.Log("certs trust the new CA once it's added to the trust bundle")
+	require.NoError(t, secrets.StartClusterCARotation(caSecret, keyConfig, time.Hour))
+	require.NoError(t, fakeClient.Update(ctx, caSecret))
+	require.NoError(t, msm.reloadMTLSCerts(ctx))
+	trusting := msm.currentCerts()
+	require.NoError(t, trusting.Cert.CheckSignatureFrom(oldCA))
+
+	t.Log("certs are signed by the new CA once it's promoted and still trust the old CA")
+	require.NoError(t, secrets.PromoteClusterCA(caSecret))
+	require.NoError(t, fakeClient.Update(ctx, caSecret))
+	require.NoError(t, msm.reloadMTLSCerts(ctx))
+	reissued := msm.currentCerts()
+	newCA, err := secrets.ParseSecretCertificate(caSecret)
+	require.NoError(t, err)
+	require.NoError(t, reissued.Cert.CheckSignatureFrom(newCA))
+	for _, ca := range []*x509.Certificate{oldCA, newCA} {
+		_, err := ca.Verify(x509.VerifyOptions{Roots: trusting.CAs})
+		require.NoError(t, err)
+		_, err = ca.Verify(x509.VerifyOptions{Roots: reissued.CAs})
+		require.NoError(t, err)
+	}
+}
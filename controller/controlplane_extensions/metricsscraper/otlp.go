@@ -0,0 +1,480 @@
+package metricsscraper
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/samber/lo"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+// OTLPProtocol is the protocol used to push metrics to an OpenTelemetry collector.
+type OTLPProtocol string
+
+const (
+	// OTLPProtocolGRPC is the OTLP/gRPC protocol.
+	OTLPProtocolGRPC OTLPProtocol = "grpc"
+	// OTLPProtocolHTTPProtobuf is the OTLP/HTTP protocol with binary protobuf payloads.
+	OTLPProtocolHTTPProtobuf OTLPProtocol = "http/protobuf"
+)
+
+const (
+	otlpExportTimeout        = 10 * time.Second
+	otlpHTTPDefaultPath      = "/v1/metrics"
+	otlpInstrumentationScope = "github.com/kong/kong-operator"
+)
+
+// OTLPExporterConfig is the configuration of an OTLP metrics exporter.
+type OTLPExporterConfig struct {
+	// Endpoint is the collector's host:port for the grpc protocol and the
+	// collector's URL for the http/protobuf protocol.
+	Endpoint string
+	// Protocol is the OTLP protocol used to push metrics.
+	Protocol OTLPProtocol
+	// Insecure disables TLS for the grpc protocol.
+	Insecure bool
+	// ResourceAttributes are added to the resource of all the pushed metrics.
+	ResourceAttributes map[string]string
+}
+
+// otlpExporterConfigForExtension returns the OTLP exporter configuration set in the
+// DataPlaneMetricsExtension's annotations. It returns false when the extension
+// does not configure an OTLP endpoint.
+func otlpExporterConfigForExtension(ext *operatorv1alpha1.DataPlaneMetricsExtension) (OTLPExporterConfig, bool, error) {
+	endpoint := strings.TrimSpace(ext.Annotations[consts.DataPlaneMetricsExtensionOTLPEndpointAnnotation])
+	if endpoint == "" {
+		return OTLPExporterConfig{}, false, nil
+	}
+
+	cfg := OTLPExporterConfig{
+		Endpoint: endpoint,
+		Protocol: OTLPProtocolGRPC,
+	}
+
+	if v, ok := ext.Annotations[consts.DataPlaneMetricsExtensionOTLPProtocolAnnotation]; ok {
+		switch p := OTLPProtocol(strings.TrimSpace(v)); p {
+		case OTLPProtocolGRPC, OTLPProtocolHTTPProtobuf:
+			cfg.Protocol = p
+		default:
+			return OTLPExporterConfig{}, false, fmt.Errorf("unsupported OTLP protocol %q, supported protocols: %s, %s",
+				v, OTLPProtocolGRPC, OTLPProtocolHTTPProtobuf,
+			)
+		}
+	}
+
+	if v, ok := ext.Annotations[consts.DataPlaneMetricsExtensionOTLPInsecureAnnotation]; ok {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return OTLPExporterConfig{}, false, fmt.Errorf("invalid %s annotation value %q: %w",
+				consts.DataPlaneMetricsExtensionOTLPInsecureAnnotation, v, err,
+			)
+		}
+		cfg.Insecure = b
+	}
+
+	if v, ok := ext.Annotations[consts.DataPlaneMetricsExtensionOTLPResourceAttributesAnnotation]; ok {
+		attrs := make(map[string]string)
+		for _, kv := range lo.Compact(strings.Split(v, ",")) {
+			key, value, found := strings.Cut(kv, "=")
+			key = strings.TrimSpace(key)
+			if !found || key == "" {
+				return OTLPExporterConfig{}, false, fmt.Errorf("invalid OTLP resource attribute %q, expected key=value", kv)
+			}
+			attrs[key] = strings.TrimSpace(value)
+		}
+		cfg.ResourceAttributes = attrs
+	}
+
+	return cfg, true, nil
+}
+
+// otlpExporter is a MetricsConsumer which pushes the enriched metrics of a
+// DataPlane to an OpenTelemetry collector.
+type otlpExporter struct {
+	config    OTLPExporterConfig
+	dataplane *operatorv1beta1.DataPlane
+	startTime time.Time
+	now       func() time.Time
+
+	export  func(context.Context, *collectormetricspb.ExportMetricsServiceRequest) error
+	closeFn func() error
+}
+
+var _ io.Closer = &otlpExporter{}
+
+// NewOTLPExporter creates an OTLP exporter pushing metrics of the provided
+// DataPlane to the configured OpenTelemetry collector.
+// The exporter has to be closed when it's not used anymore.
+func NewOTLPExporter(cfg OTLPExporterConfig, dataplane *operatorv1beta1.DataPlane) (*otlpExporter, error) {
+	e := &otlpExporter{
+		config:    cfg,
+		dataplane: dataplane,
+		startTime: time.Now(),
+		now:       time.Now,
+		closeFn:   func() error { return nil },
+	}
+
+	switch cfg.Protocol {
+	case OTLPProtocolGRPC, "":
+		creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+		if cfg.Insecure {
+			creds = insecure.NewCredentials()
+		}
+		conn, err := grpc.NewClient(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP gRPC client for %s: %w", cfg.Endpoint, err)
+		}
+		metricsClient := collectormetricspb.NewMetricsServiceClient(conn)
+		e.export = func(ctx context.Context, req *collectormetricspb.ExportMetricsServiceRequest) error {
+			_, err := metricsClient.Export(ctx, req)
+			return err
+		}
+		e.closeFn = conn.Close
+
+	case OTLPProtocolHTTPProtobuf:
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP HTTP endpoint %s: %w", cfg.Endpoint, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("invalid OTLP HTTP endpoint %s: scheme has to be http or https", cfg.Endpoint)
+		}
+		if u.Path == "" || u.Path == "/" {
+			u.Path = otlpHTTPDefaultPath
+		}
+		endpoint := u.String()
+		httpClient := &http.Client{Timeout: otlpExportTimeout}
+		e.export = func(ctx context.Context, req *collectormetricspb.ExportMetricsServiceRequest) error {
+			return exportOTLPHTTP(ctx, httpClient, endpoint, req)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", cfg.Protocol)
+	}
+
+	return e, nil
+}
+
+// Consume pushes the metrics to the OpenTelemetry collector.
+func (e *otlpExporter) Consume(ctx context.Context, m Metrics) error {
+	req := e.exportRequest(m)
+	if len(req.GetResourceMetrics()) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, otlpExportTimeout)
+	defer cancel()
+	if err := e.export(ctx, req); err != nil {
+		return fmt.Errorf("failed to push metrics to OTLP endpoint %s: %w", e.config.Endpoint, err)
+	}
+	return nil
+}
+
+// Close releases the connection to the OpenTelemetry collector.
+func (e *otlpExporter) Close() error {
+	return e.closeFn()
+}
+
+func exportOTLPHTTP(
+	ctx context.Context,
+	httpClient *http.Client,
+	endpoint string,
+	req *collectormetricspb.ExportMetricsServiceRequest,
+) error {
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return nil
+}
+
+// otlpResourceLabels are the labels added by the enricher which are exported as
+// OTLP resource attributes instead of data point attributes.
+var otlpResourceLabels = []string{
+	"namespace",
+	"service",
+	"kubernetes_apiversion",
+	"kubernetes_kind",
+	"kubernetes_name",
+	"kubernetes_namespace",
+	"dataplane_name",
+	"dataplane_namespace",
+	"dataplane_url",
+}
+
+// otlpResourceKey identifies the resource the metrics are pushed for: the
+// Kubernetes Service proxied by a DataPlane Pod.
+type otlpResourceKey struct {
+	dataplaneURL adminAPIEndpointURL
+	namespace    string
+	name         string
+}
+
+// exportRequest converts the enriched metrics to an OTLP export request.
+// Metrics are grouped in resources per DataPlane Pod and Kubernetes Service.
+func (e *otlpExporter) exportRequest(m Metrics) *collectormetricspb.ExportMetricsServiceRequest {
+	var (
+		startTime = uint64(e.startTime.UnixNano()) //nolint:gosec
+		now       = uint64(e.now().UnixNano())     //nolint:gosec
+
+		keys      []otlpResourceKey
+		resources = make(map[otlpResourceKey]map[string]*metricspb.Metric)
+	)
+
+	urls := lo.Keys(m.metrics)
+	slices.Sort(urls)
+	for _, u := range urls {
+		families := m.metrics[u]
+		names := lo.Keys(families)
+		slices.Sort(names)
+		for _, name := range names {
+			family := families[name]
+			for _, metric := range family.GetMetric() {
+				labels := lo.SliceToMap(metric.GetLabel(), func(l *dto.LabelPair) (string, string) {
+					return l.GetName(), l.GetValue()
+				})
+				key := otlpResourceKey{
+					dataplaneURL: u,
+					namespace:    labels["kubernetes_namespace"],
+					name:         labels["kubernetes_name"],
+				}
+				metrics, ok := resources[key]
+				if !ok {
+					metrics = make(map[string]*metricspb.Metric)
+					resources[key] = metrics
+					keys = append(keys, key)
+				}
+				otlpMetric, ok := metrics[family.GetName()]
+				if !ok {
+					otlpMetric = newOTLPMetric(family)
+					if otlpMetric == nil {
+						continue
+					}
+					metrics[family.GetName()] = otlpMetric
+				}
+				addOTLPDataPoint(otlpMetric, metric, otlpDataPointAttributes(metric), startTime, now)
+			}
+		}
+	}
+
+	req := &collectormetricspb.ExportMetricsServiceRequest{}
+	for _, key := range keys {
+		metrics := resources[key]
+		names := lo.Keys(metrics)
+		slices.Sort(names)
+		req.ResourceMetrics = append(req.ResourceMetrics, &metricspb.ResourceMetrics{
+			Resource: &resourcepb.Resource{
+				Attributes: e.resourceAttributes(key),
+			},
+			ScopeMetrics: []*metricspb.ScopeMetrics{
+				{
+					Scope: &commonpb.InstrumentationScope{
+						Name: otlpInstrumentationScope,
+					},
+					Metrics: lo.Map(names, func(name string, _ int) *metricspb.Metric {
+						return metrics[name]
+					}),
+				},
+			},
+		})
+	}
+	return req
+}
+
+// resourceAttributes returns the attributes of the resource identified by the key:
+// the configured resource attributes and the DataPlane's and the Kubernetes
+// Service's metadata.
+func (e *otlpExporter) resourceAttributes(key otlpResourceKey) []*commonpb.KeyValue {
+	attrs := make(map[string]string, len(e.config.ResourceAttributes)+5)
+	for k, v := range e.config.ResourceAttributes {
+		attrs[k] = v
+	}
+	attrs["k8s.namespace.name"] = e.dataplane.Namespace
+	attrs["kong.dataplane.name"] = e.dataplane.Name
+	attrs["kong.dataplane.url"] = string(key.dataplaneURL)
+	if key.name != "" && key.namespace != "" {
+		attrs["k8s.service.name"] = key.name
+		attrs["k8s.service.namespace"] = key.namespace
+	}
+	return otlpKeyValues(attrs)
+}
+
+// otlpDataPointAttributes returns the metric's labels which are not exported as
+// resource attributes. Labels with empty values are skipped.
+func otlpDataPointAttributes(metric *dto.Metric) []*commonpb.KeyValue {
+	attrs := make(map[string]string, len(metric.GetLabel()))
+	for _, l := range metric.GetLabel() {
+		if l.GetValue() == "" || slices.Contains(otlpResourceLabels, l.GetName()) {
+			continue
+		}
+		attrs[l.GetName()] = l.GetValue()
+	}
+	return otlpKeyValues(attrs)
+}
+
+func otlpKeyValues(attrs map[string]string) []*commonpb.KeyValue {
+	keys := lo.Keys(attrs)
+	slices.Sort(keys)
+	return lo.Map(keys, func(k string, _ int) *commonpb.KeyValue {
+		return &commonpb.KeyValue{
+			Key: k,
+			Value: &commonpb.AnyValue{
+				Value: &commonpb.AnyValue_StringValue{StringValue: attrs[k]},
+			},
+		}
+	})
+}
+
+// newOTLPMetric returns an OTLP metric without data points for the provided
+// Prometheus metric family. Counters are converted to monotonic cumulative sums.
+// It returns nil for unsupported metric types.
+func newOTLPMetric(family *dto.MetricFamily) *metricspb.Metric {
+	m := &metricspb.Metric{
+		Name:        family.GetName(),
+		Description: family.GetHelp(),
+		Unit:        family.GetUnit(),
+	}
+	switch family.GetType() {
+	case dto.MetricType_COUNTER:
+		m.Data = &metricspb.Metric_Sum{
+			Sum: &metricspb.Sum{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+				IsMonotonic:            true,
+			},
+		}
+	case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+		m.Data = &metricspb.Metric_Gauge{
+			Gauge: &metricspb.Gauge{},
+		}
+	case dto.MetricType_HISTOGRAM:
+		m.Data = &metricspb.Metric_Histogram{
+			Histogram: &metricspb.Histogram{
+				AggregationTemporality: metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE,
+			},
+		}
+	case dto.MetricType_SUMMARY:
+		m.Data = &metricspb.Metric_Summary{
+			Summary: &metricspb.Summary{},
+		}
+	default:
+		return nil
+	}
+	return m
+}
+
+// addOTLPDataPoint adds the Prometheus metric as a data point to the OTLP metric.
+func addOTLPDataPoint(m *metricspb.Metric, metric *dto.Metric, attrs []*commonpb.KeyValue, startTime, now uint64) {
+	switch data := m.Data.(type) {
+	case *metricspb.Metric_Sum:
+		data.Sum.DataPoints = append(data.Sum.DataPoints, &metricspb.NumberDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: startTime,
+			TimeUnixNano:      now,
+			Value:             &metricspb.NumberDataPoint_AsDouble{AsDouble: metric.GetCounter().GetValue()},
+		})
+	case *metricspb.Metric_Gauge:
+		value := metric.GetGauge().GetValue()
+		if metric.Untyped != nil {
+			value = metric.GetUntyped().GetValue()
+		}
+		data.Gauge.DataPoints = append(data.Gauge.DataPoints, &metricspb.NumberDataPoint{
+			Attributes:   attrs,
+			TimeUnixNano: now,
+			Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+		})
+	case *metricspb.Metric_Histogram:
+		h := metric.GetHistogram()
+		bounds, counts := otlpHistogramBuckets(h)
+		data.Histogram.DataPoints = append(data.Histogram.DataPoints, &metricspb.HistogramDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: startTime,
+			TimeUnixNano:      now,
+			Count:             h.GetSampleCount(),
+			Sum:               lo.ToPtr(h.GetSampleSum()),
+			ExplicitBounds:    bounds,
+			BucketCounts:      counts,
+		})
+	case *metricspb.Metric_Summary:
+		s := metric.GetSummary()
+		data.Summary.DataPoints = append(data.Summary.DataPoints, &metricspb.SummaryDataPoint{
+			Attributes:        attrs,
+			StartTimeUnixNano: startTime,
+			TimeUnixNano:      now,
+			Count:             s.GetSampleCount(),
+			Sum:               s.GetSampleSum(),
+			QuantileValues: lo.Map(s.GetQuantile(), func(q *dto.Quantile, _ int) *metricspb.SummaryDataPoint_ValueAtQuantile {
+				return &metricspb.SummaryDataPoint_ValueAtQuantile{
+					Quantile: q.GetQuantile(),
+					Value:    q.GetValue(),
+				}
+			}),
+		})
+	}
+}
+
+// otlpHistogramBuckets converts Prometheus' cumulative histogram buckets to OTLP
+// explicit bounds and per bucket counts. OTLP's last bucket, which has no
+// explicit upper bound, holds the samples above the highest finite bound.
+func otlpHistogramBuckets(h *dto.Histogram) ([]float64, []uint64) {
+	var (
+		bounds []float64
+		counts []uint64
+		prev   uint64
+	)
+	for _, b := range h.GetBucket() {
+		if math.IsInf(b.GetUpperBound(), +1) {
+			continue
+		}
+		bounds = append(bounds, b.GetUpperBound())
+		counts = append(counts, subtractOrZero(b.GetCumulativeCount(), prev))
+		prev = max(prev, b.GetCumulativeCount())
+	}
+	counts = append(counts, subtractOrZero(h.GetSampleCount(), prev))
+	return bounds, counts
+}
+
+func subtractOrZero(a, b uint64) uint64 {
+	if a < b {
+		return 0
+	}
+	return a - b
+}
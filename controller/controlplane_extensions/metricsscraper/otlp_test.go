@@ -0,0 +1,228 @@
+package metricsscraper
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestOTLPExporterConfigForExtension(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    OTLPExporterConfig
+		expectedOK  bool
+		expectedErr bool
+	}{
+		{
+			name: "no endpoint",
+		},
+		{
+			name: "defaults",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionOTLPEndpointAnnotation: "collector:4317",
+			},
+			expected: OTLPExporterConfig{
+				Endpoint: "collector:4317",
+				Protocol: OTLPProtocolGRPC,
+			},
+			expectedOK: true,
+		},
+		{
+			name: "http with resource attributes",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionOTLPEndpointAnnotation:           "https://collector:4318",
+				consts.DataPlaneMetricsExtensionOTLPProtocolAnnotation:           "http/protobuf",
+				consts.DataPlaneMetricsExtensionOTLPInsecureAnnotation:           "true",
+				consts.DataPlaneMetricsExtensionOTLPResourceAttributesAnnotation: "k8s.cluster.name=prod, env = prod",
+			},
+			expected: OTLPExporterConfig{
+				Endpoint: "https://collector:4318",
+				Protocol: OTLPProtocolHTTPProtobuf,
+				Insecure: true,
+				ResourceAttributes: map[string]string{
+					"k8s.cluster.name": "prod",
+					"env":              "prod",
+				},
+			},
+			expectedOK: true,
+		},
+		{
+			name: "unsupported protocol",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionOTLPEndpointAnnotation: "collector:4317",
+				consts.DataPlaneMetricsExtensionOTLPProtocolAnnotation: "http/json",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "malformed resource attributes",
+			annotations: map[string]string{
+				consts.DataPlaneMetricsExtensionOTLPEndpointAnnotation:           "collector:4317",
+				consts.DataPlaneMetricsExtensionOTLPResourceAttributesAnnotation: "k8s.cluster.name",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ext := &operatorv1alpha1.DataPlaneMetricsExtension{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			cfg, ok, err := otlpExporterConfigForExtension(ext)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	const dataplaneURL = "https://10-0-0-1.dataplane-admin.default.svc:8444"
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dataplane-1",
+			Namespace: "default",
+		},
+	}
+	label := func(name, value string) *dto.LabelPair {
+		return &dto.LabelPair{Name: lo.ToPtr(name), Value: lo.ToPtr(value)}
+	}
+	enrichedLabels := []*dto.LabelPair{
+		label("code", "200"),
+		label("dataplane_name", "dataplane-1"),
+		label("dataplane_namespace", "default"),
+		label("dataplane_url", dataplaneURL),
+		label("kong_service", "default.echo.80"),
+		label("kubernetes_name", "echo"),
+		label("kubernetes_namespace", "default"),
+	}
+	metrics := Metrics{
+		metrics: metricsMap{
+			dataplaneURL: {
+				KongMetricNameKongHTTPRequestsTotal: {
+					Name: lo.ToPtr(KongMetricNameKongHTTPRequestsTotal),
+					Type: dto.MetricType_COUNTER.Enum(),
+					Metric: []*dto.Metric{
+						{Label: enrichedLabels, Counter: &dto.Counter{Value: lo.ToPtr(42.0)}},
+					},
+				},
+				KongMetricNameKongUpstreamLatencyMs: {
+					Name: lo.ToPtr(KongMetricNameKongUpstreamLatencyMs),
+					Type: dto.MetricType_HISTOGRAM.Enum(),
+					Metric: []*dto.Metric{
+						{
+							Label: enrichedLabels,
+							Histogram: &dto.Histogram{
+								SampleCount: lo.ToPtr(uint64(10)),
+								SampleSum:   lo.ToPtr(123.0),
+								Bucket: []*dto.Bucket{
+									{UpperBound: lo.ToPtr(10.0), CumulativeCount: lo.ToPtr(uint64(3))},
+									{UpperBound: lo.ToPtr(100.0), CumulativeCount: lo.ToPtr(uint64(8))},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	received := make(chan *collectormetricspb.ExportMetricsServiceRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/metrics", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var req collectormetricspb.ExportMetricsServiceRequest
+		assert.NoError(t, proto.Unmarshal(body, &req))
+		received <- &req
+	}))
+	t.Cleanup(server.Close)
+
+	exporter, err := NewOTLPExporter(OTLPExporterConfig{
+		Endpoint: server.URL,
+		Protocol: OTLPProtocolHTTPProtobuf,
+		ResourceAttributes: map[string]string{
+			"k8s.cluster.name": "prod",
+		},
+	}, dataplane)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = exporter.Close() })
+	exporter.now = func() time.Time { return exporter.startTime.Add(time.Minute) }
+
+	require.NoError(t, exporter.Consume(t.Context(), metrics))
+	req := <-received
+
+	require.Len(t, req.GetResourceMetrics(), 1)
+	rm := req.GetResourceMetrics()[0]
+	assert.Equal(t,
+		map[string]string{
+			"k8s.cluster.name":      "prod",
+			"k8s.namespace.name":    "default",
+			"k8s.service.name":      "echo",
+			"k8s.service.namespace": "default",
+			"kong.dataplane.name":   "dataplane-1",
+			"kong.dataplane.url":    dataplaneURL,
+		},
+		keyValuesToMap(rm.GetResource().GetAttributes()),
+	)
+
+	require.Len(t, rm.GetScopeMetrics(), 1)
+	otlpMetrics := rm.GetScopeMetrics()[0].GetMetrics()
+	require.Len(t, otlpMetrics, 2)
+
+	requests := otlpMetrics[0]
+	assert.Equal(t, KongMetricNameKongHTTPRequestsTotal, requests.GetName())
+	require.NotNil(t, requests.GetSum())
+	assert.True(t, requests.GetSum().GetIsMonotonic())
+	require.Len(t, requests.GetSum().GetDataPoints(), 1)
+	dp := requests.GetSum().GetDataPoints()[0]
+	assert.Equal(t, 42.0, dp.GetAsDouble())
+	assert.Equal(t,
+		map[string]string{"code": "200", "kong_service": "default.echo.80"},
+		keyValuesToMap(dp.GetAttributes()),
+		"labels exported as resource attributes are not repeated on data points",
+	)
+	assert.Less(t, dp.GetStartTimeUnixNano(), dp.GetTimeUnixNano())
+
+	latency := otlpMetrics[1]
+	assert.Equal(t, KongMetricNameKongUpstreamLatencyMs, latency.GetName())
+	require.NotNil(t, latency.GetHistogram())
+	require.Len(t, latency.GetHistogram().GetDataPoints(), 1)
+	hdp := latency.GetHistogram().GetDataPoints()[0]
+	assert.Equal(t, uint64(10), hdp.GetCount())
+	assert.Equal(t, 123.0, hdp.GetSum())
+	assert.Equal(t, []float64{10, 100}, hdp.GetExplicitBounds())
+	assert.Equal(t, []uint64{3, 5, 2}, hdp.GetBucketCounts())
+}
+
+func keyValuesToMap(kvs []*commonpb.KeyValue) map[string]string {
+	return lo.SliceToMap(kvs, func(kv *commonpb.KeyValue) (string, string) {
+		return kv.GetKey(), kv.GetValue().GetStringValue()
+	})
+}
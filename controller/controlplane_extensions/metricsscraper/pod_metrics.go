@@ -0,0 +1,191 @@
+package metricsscraper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+const (
+	// PodMetricNameKongRequestsPerSecond is the name of the per Pod metric
+	// providing the rate of requests served by a DataPlane Pod.
+	PodMetricNameKongRequestsPerSecond = "kong_requests_per_second"
+	// PodMetricNameKongUpstreamLatencyMs is the name of the per Pod metric
+	// providing the mean kong_upstream_latency_ms of a DataPlane Pod.
+	PodMetricNameKongUpstreamLatencyMs = "kong_upstream_latency_ms"
+)
+
+// PodMetricNames is the list of the per Pod metrics computed from the scraped metrics.
+var PodMetricNames = []string{
+	PodMetricNameKongRequestsPerSecond,
+	PodMetricNameKongUpstreamLatencyMs,
+}
+
+// PodMetrics contains the values of the per Pod metrics of a DataPlane Pod
+// computed out of two consecutive scrapes.
+type PodMetrics struct {
+	// Timestamp is the time of the latest scrape.
+	Timestamp time.Time
+	// Window is the time between the two scrapes the values were computed from.
+	Window time.Duration
+	// Values contains the metric values indexed by the metric name.
+	Values map[string]float64
+}
+
+// PodMetricsProvider provides the per Pod metrics of DataPlane Pods.
+type PodMetricsProvider interface {
+	PodMetrics(pod types.NamespacedName) (PodMetrics, bool)
+}
+
+// PodMetricsStore stores the per Pod metrics of DataPlane Pods.
+// Metrics are grouped by the UID of the DataPlane the Pods belong to so that
+// all of a DataPlane's Pods metrics are replaced on every scrape and can be
+// pruned when the DataPlane goes away.
+type PodMetricsStore struct {
+	lock    sync.RWMutex
+	metrics map[types.UID]map[types.NamespacedName]PodMetrics
+}
+
+var _ PodMetricsProvider = &PodMetricsStore{}
+
+// NewPodMetricsStore creates a new PodMetricsStore.
+func NewPodMetricsStore() *PodMetricsStore {
+	return &PodMetricsStore{
+		metrics: make(map[types.UID]map[types.NamespacedName]PodMetrics),
+	}
+}
+
+// PodMetrics returns the metrics of the provided Pod.
+func (s *PodMetricsStore) PodMetrics(pod types.NamespacedName) (PodMetrics, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, pods := range s.metrics {
+		if m, ok := pods[pod]; ok {
+			return m, true
+		}
+	}
+	return PodMetrics{}, false
+}
+
+// Set replaces the metrics of all the Pods of the DataPlane with the given UID.
+func (s *PodMetricsStore) Set(dataplaneUID types.UID, metrics map[types.NamespacedName]PodMetrics) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(metrics) == 0 {
+		delete(s.metrics, dataplaneUID)
+		return
+	}
+	s.metrics[dataplaneUID] = metrics
+}
+
+// Delete removes the metrics of all the Pods of the DataPlane with the given UID.
+func (s *PodMetricsStore) Delete(dataplaneUID types.UID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.metrics, dataplaneUID)
+}
+
+// podMetricsSample is the summary of the metrics scraped from a Pod.
+type podMetricsSample struct {
+	timestamp time.Time
+	summary   RolloutMetricsSummary
+}
+
+// podMetricsConsumer is a MetricsConsumer which computes the per Pod metrics
+// of a DataPlane's Pods out of two consecutive scrapes and stores them in
+// a PodMetricsStore.
+type podMetricsConsumer struct {
+	dataplane *operatorv1beta1.DataPlane
+	cl        client.Client
+	store     *PodMetricsStore
+	now       func() time.Time
+
+	lock     sync.Mutex
+	previous map[adminAPIEndpointURL]podMetricsSample
+}
+
+var _ MetricsConsumer = &podMetricsConsumer{}
+
+func newPodMetricsConsumer(dataplane *operatorv1beta1.DataPlane, cl client.Client, store *PodMetricsStore) *podMetricsConsumer {
+	return &podMetricsConsumer{
+		dataplane: dataplane,
+		cl:        cl,
+		store:     store,
+		now:       time.Now,
+		previous:  make(map[adminAPIEndpointURL]podMetricsSample),
+	}
+}
+
+// Consume implements MetricsConsumer.
+func (c *podMetricsConsumer) Consume(ctx context.Context, m Metrics) error {
+	var pods corev1.PodList
+	if err := c.cl.List(ctx, &pods,
+		client.InNamespace(c.dataplane.Namespace),
+		client.MatchingLabels{
+			"app":                                c.dataplane.Name,
+			consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+		},
+	); err != nil {
+		return fmt.Errorf("failed listing Pods for DataPlane %s: %w", client.ObjectKeyFromObject(c.dataplane), err)
+	}
+	podsByIP := lo.SliceToMap(pods.Items, func(p corev1.Pod) (string, types.NamespacedName) {
+		return p.Status.PodIP, client.ObjectKeyFromObject(&p)
+	})
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	current := make(map[adminAPIEndpointURL]podMetricsSample, len(m.metrics))
+	podMetrics := make(map[types.NamespacedName]PodMetrics, len(m.metrics))
+	for u, families := range m.metrics {
+		sample := podMetricsSample{
+			timestamp: now,
+			summary:   Metrics{metrics: metricsMap{u: families}}.Summarize(),
+		}
+		current[u] = sample
+
+		prev, ok := c.previous[u]
+		if !ok {
+			continue
+		}
+		ip, ok := podIPFromAdminAPIURL(string(u))
+		if !ok {
+			continue
+		}
+		pod, ok := podsByIP[ip]
+		if !ok {
+			continue
+		}
+		window := sample.timestamp.Sub(prev.timestamp)
+		if window <= 0 {
+			continue
+		}
+
+		delta := sample.summary.Sub(prev.summary)
+		podMetrics[pod] = PodMetrics{
+			Timestamp: now,
+			Window:    window,
+			Values: map[string]float64{
+				PodMetricNameKongRequestsPerSecond: delta.Requests / window.Seconds(),
+				PodMetricNameKongUpstreamLatencyMs: delta.MeanUpstreamLatencyMs(),
+			},
+		}
+	}
+
+	// Samples of Admin API endpoints which were not scraped this time are dropped.
+	c.previous = current
+	c.store.Set(c.dataplane.UID, podMetrics)
+
+	return nil
+}
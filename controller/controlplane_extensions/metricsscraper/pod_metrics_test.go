@@ -0,0 +1,111 @@
+package metricsscraper
+
+import (
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestPodMetricsConsumer(t *testing.T) {
+	const adminAPIURL = "https://10-0-0-1.dataplane-admin.default.svc:8444"
+
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dataplane-1",
+			Namespace: "default",
+			UID:       "dp-uid",
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dataplane-1-pod",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":                                "dataplane-1",
+				consts.GatewayOperatorManagedByLabel: consts.DataPlaneManagedLabelValue,
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.0.0.1",
+		},
+	}
+	podNN := types.NamespacedName{Namespace: "default", Name: "dataplane-1-pod"}
+
+	metrics := func(requests float64, latencySum float64, latencyCount uint64) Metrics {
+		return Metrics{
+			metrics: metricsMap{
+				adminAPIURL: {
+					KongMetricNameKongHTTPRequestsTotal: counterFamily(KongMetricNameKongHTTPRequestsTotal,
+						counterMetric(requests, "code", "200"),
+					),
+					KongMetricNameKongUpstreamLatencyMs: {
+						Name: proto.String(KongMetricNameKongUpstreamLatencyMs),
+						Type: dto.MetricType_HISTOGRAM.Enum(),
+						Metric: []*dto.Metric{
+							{
+								Histogram: &dto.Histogram{
+									SampleSum:   proto.Float64(latencySum),
+									SampleCount: proto.Uint64(latencyCount),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(pod).
+		Build()
+	store := NewPodMetricsStore()
+	consumer := newPodMetricsConsumer(dataplane, cl, store)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	consumer.now = func() time.Time { return now }
+
+	require.NoError(t, consumer.Consume(t.Context(), metrics(100, 1000, 100)))
+	_, ok := store.PodMetrics(podNN)
+	assert.False(t, ok, "rates can't be computed out of a single scrape")
+
+	now = now.Add(10 * time.Second)
+	require.NoError(t, consumer.Consume(t.Context(), metrics(300, 1800, 300)))
+	podMetrics, ok := store.PodMetrics(podNN)
+	require.True(t, ok)
+	assert.Equal(t, now, podMetrics.Timestamp)
+	assert.Equal(t, 10*time.Second, podMetrics.Window)
+	assert.Equal(t,
+		map[string]float64{
+			PodMetricNameKongRequestsPerSecond: 20,
+			PodMetricNameKongUpstreamLatencyMs: 4,
+		},
+		podMetrics.Values,
+	)
+
+	now = now.Add(10 * time.Second)
+	require.NoError(t, consumer.Consume(t.Context(), Metrics{}))
+	_, ok = store.PodMetrics(podNN)
+	assert.False(t, ok, "metrics of Pods which are not scraped anymore are pruned")
+}
+
+func TestPodIPFromAdminAPIURL(t *testing.T) {
+	ip, ok := podIPFromAdminAPIURL("https://10-0-0-1.dataplane-admin.default.svc:8444")
+	require.True(t, ok)
+	assert.Equal(t, "10.0.0.1", ip)
+
+	_, ok = podIPFromAdminAPIURL("https://localhost:8444")
+	assert.False(t, ok)
+}
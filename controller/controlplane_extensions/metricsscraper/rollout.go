@@ -0,0 +1,142 @@
+package metricsscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+)
+
+const (
+	// KongMetricNameKongHTTPRequestsTotal is the name of the kong_http_requests_total metric.
+	KongMetricNameKongHTTPRequestsTotal = "kong_http_requests_total"
+)
+
+// ErrMTLSCertsNotInitialized is returned when the Manager is asked to scrape
+// metrics before it has initialized its mTLS certificates.
+var ErrMTLSCertsNotInitialized = errors.New("metrics scraper mTLS certificates are not initialized yet")
+
+// RolloutMetricsSummary contains the aggregated values of Kong metrics which
+// are used to assess the health of DataPlane Pods during a rollout.
+// All the values are cumulative as reported by Kong's Prometheus plugin.
+type RolloutMetricsSummary struct {
+	// Requests is the total number of requests served.
+	Requests float64
+	// ServerErrors is the number of requests that ended with a 5xx status code.
+	ServerErrors float64
+	// UpstreamLatencySumMs is the sum of all observed kong_upstream_latency_ms values.
+	UpstreamLatencySumMs float64
+	// UpstreamLatencyCount is the number of observed kong_upstream_latency_ms values.
+	UpstreamLatencyCount uint64
+}
+
+// Sub returns the difference between s and prev which allows to compute the
+// values observed in a time window out of two cumulative summaries.
+// When a value in s is lower than in prev (e.g. because Pods were restarted and
+// counters were reset) the value from s is used as is.
+func (s RolloutMetricsSummary) Sub(prev RolloutMetricsSummary) RolloutMetricsSummary {
+	sub := func(cur, prev float64) float64 {
+		if cur < prev {
+			return cur
+		}
+		return cur - prev
+	}
+
+	ret := RolloutMetricsSummary{
+		Requests:             sub(s.Requests, prev.Requests),
+		ServerErrors:         sub(s.ServerErrors, prev.ServerErrors),
+		UpstreamLatencySumMs: sub(s.UpstreamLatencySumMs, prev.UpstreamLatencySumMs),
+		UpstreamLatencyCount: s.UpstreamLatencyCount,
+	}
+	if s.UpstreamLatencyCount >= prev.UpstreamLatencyCount {
+		ret.UpstreamLatencyCount = s.UpstreamLatencyCount - prev.UpstreamLatencyCount
+	}
+	return ret
+}
+
+// ErrorRate returns the fraction of requests that ended with a 5xx status code.
+// It returns 0 when no requests were served.
+func (s RolloutMetricsSummary) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.ServerErrors / s.Requests
+}
+
+// MeanUpstreamLatencyMs returns the mean kong_upstream_latency_ms.
+// It returns 0 when no latency was observed.
+func (s RolloutMetricsSummary) MeanUpstreamLatencyMs() float64 {
+	if s.UpstreamLatencyCount == 0 {
+		return 0
+	}
+	return s.UpstreamLatencySumMs / float64(s.UpstreamLatencyCount)
+}
+
+// Summarize aggregates the scraped metrics across all Admin API endpoints
+// into a RolloutMetricsSummary.
+func (m Metrics) Summarize() RolloutMetricsSummary {
+	var summary RolloutMetricsSummary
+	for _, families := range m.metrics {
+		if f, ok := families[KongMetricNameKongHTTPRequestsTotal]; ok {
+			for _, metric := range f.GetMetric() {
+				v := metric.GetCounter().GetValue()
+				summary.Requests += v
+				if isServerErrorCode(metric.GetLabel()) {
+					summary.ServerErrors += v
+				}
+			}
+		}
+		if f, ok := families[KongMetricNameKongUpstreamLatencyMs]; ok {
+			for _, metric := range f.GetMetric() {
+				h := metric.GetHistogram()
+				summary.UpstreamLatencySumMs += h.GetSampleSum()
+				summary.UpstreamLatencyCount += h.GetSampleCount()
+			}
+		}
+	}
+	return summary
+}
+
+func isServerErrorCode(labels []*dto.LabelPair) bool {
+	for _, l := range labels {
+		if l.GetName() != "code" {
+			continue
+		}
+		code, err := strconv.Atoi(l.GetValue())
+		if err != nil {
+			return false
+		}
+		return code >= 500 && code <= 599
+	}
+	return false
+}
+
+// ScrapeRolloutMetrics scrapes metrics from both the "live" and "preview"
+// Admin API endpoints of the provided DataPlane and returns their summaries.
+func (msm *Manager) ScrapeRolloutMetrics(
+	ctx context.Context, dp *operatorv1beta1.DataPlane,
+) (live RolloutMetricsSummary, preview RolloutMetricsSummary, err error) {
+	if _, ok := msm.getCerts(); !ok {
+		return live, preview, ErrMTLSCertsNotInitialized
+	}
+	httpClient := httpClientWithCerts(msm.currentCerts)
+
+	liveMetrics, err := NewPrometheusMetricsScraper(
+		msm.logger, dp, httpClient, NewAdminAPIAddressProvider(msm.client),
+	).Scrape(ctx)
+	if err != nil {
+		return live, preview, fmt.Errorf("failed scraping live DataPlane metrics: %w", err)
+	}
+	previewMetrics, err := NewPrometheusMetricsScraper(
+		msm.logger, dp, httpClient, NewPreviewAdminAPIAddressProvider(msm.client),
+	).Scrape(ctx)
+	if err != nil {
+		return live, preview, fmt.Errorf("failed scraping preview DataPlane metrics: %w", err)
+	}
+
+	return liveMetrics.Summarize(), previewMetrics.Summarize(), nil
+}
@@ -0,0 +1,75 @@
+package metricsscraper
+
+import (
+	"strings"
+	"testing"
+
+	prometheus "github.com/prometheus/client_model/go"
+	prometheusexpfmt "github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Summarize(t *testing.T) {
+	const metricsBody = `` +
+		`# TYPE kong_http_requests_total counter` + "\n" +
+		`kong_http_requests_total{service="svc",route="route",code="200",source="service",workspace="default",consumer=""} 90` + "\n" +
+		`kong_http_requests_total{service="svc",route="route",code="503",source="service",workspace="default",consumer=""} 8` + "\n" +
+		`kong_http_requests_total{service="svc",route="route",code="404",source="kong",workspace="default",consumer=""} 2` + "\n" +
+		`# TYPE kong_upstream_latency_ms histogram` + "\n" +
+		`kong_upstream_latency_ms_bucket{service="svc",route="route",le="25"} 98` + "\n" +
+		`kong_upstream_latency_ms_bucket{service="svc",route="route",le="+Inf"} 98` + "\n" +
+		`kong_upstream_latency_ms_count{service="svc",route="route"} 98` + "\n" +
+		`kong_upstream_latency_ms_sum{service="svc",route="route"} 980` + "\n"
+
+	parse := func(t *testing.T) map[metricName]*prometheus.MetricFamily {
+		var parser prometheusexpfmt.TextParser
+		families, err := parser.TextToMetricFamilies(strings.NewReader(metricsBody))
+		require.NoError(t, err)
+		ret := make(map[metricName]*prometheus.MetricFamily, len(families))
+		for name, f := range families {
+			ret[metricName(name)] = f
+		}
+		return ret
+	}
+
+	m := Metrics{
+		metrics: metricsMap{
+			"https://10-0-0-1.svc:8444": parse(t),
+			"https://10-0-0-2.svc:8444": parse(t),
+		},
+	}
+
+	summary := m.Summarize()
+	assert.Equal(t, RolloutMetricsSummary{
+		Requests:             200,
+		ServerErrors:         16,
+		UpstreamLatencySumMs: 1960,
+		UpstreamLatencyCount: 196,
+	}, summary)
+	assert.InDelta(t, 0.08, summary.ErrorRate(), 1e-9)
+	assert.InDelta(t, 10, summary.MeanUpstreamLatencyMs(), 1e-9)
+}
+
+func TestRolloutMetricsSummary_Sub(t *testing.T) {
+	t.Run("regular window", func(t *testing.T) {
+		prev := RolloutMetricsSummary{Requests: 100, ServerErrors: 1, UpstreamLatencySumMs: 1000, UpstreamLatencyCount: 100}
+		cur := RolloutMetricsSummary{Requests: 300, ServerErrors: 5, UpstreamLatencySumMs: 3000, UpstreamLatencyCount: 300}
+		assert.Equal(t,
+			RolloutMetricsSummary{Requests: 200, ServerErrors: 4, UpstreamLatencySumMs: 2000, UpstreamLatencyCount: 200},
+			cur.Sub(prev),
+		)
+	})
+
+	t.Run("counters reset", func(t *testing.T) {
+		prev := RolloutMetricsSummary{Requests: 300, ServerErrors: 5, UpstreamLatencySumMs: 3000, UpstreamLatencyCount: 300}
+		cur := RolloutMetricsSummary{Requests: 50, ServerErrors: 1, UpstreamLatencySumMs: 500, UpstreamLatencyCount: 50}
+		assert.Equal(t, cur, cur.Sub(prev))
+	})
+
+	t.Run("empty summary", func(t *testing.T) {
+		var s RolloutMetricsSummary
+		assert.Zero(t, s.ErrorRate())
+		assert.Zero(t, s.MeanUpstreamLatencyMs())
+	})
+}
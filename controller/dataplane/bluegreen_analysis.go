@@ -0,0 +1,338 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/v2/api/common/consts"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlaneBlueGreenReconciler - Promotion analysis
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneConditionTypePromotionAnalysis is the type of the condition set in
+	// DataPlane's Rollout Status with the verdict of the promotion analysis of
+	// the current preview Deployment. The verdict applies to the DataPlane generation
+	// it was observed for and the condition is removed once the preview is promoted.
+	DataPlaneConditionTypePromotionAnalysis kcfgconsts.ConditionType = "PromotionAnalysis"
+
+	// DataPlaneConditionReasonRolloutAnalysisPassed is the reason of the
+	// PromotionAnalysis condition set when the preview Deployment's metrics
+	// analysis found no regression compared to the live Deployment.
+	DataPlaneConditionReasonRolloutAnalysisPassed kcfgconsts.ConditionReason = "AnalysisPassed"
+
+	// DataPlaneConditionReasonRolloutAnalysisInProgress is the reason of the
+	// RolledOut and PromotionAnalysis conditions set when the preview
+	// Deployment's metrics are being analyzed before promotion.
+	DataPlaneConditionReasonRolloutAnalysisInProgress kcfgconsts.ConditionReason = "AnalysisInProgress"
+
+	// DataPlaneConditionReasonRolloutAnalysisFailed is the reason of the
+	// RolledOut and PromotionAnalysis conditions set when the preview
+	// Deployment's metrics analysis found a regression compared to the live
+	// Deployment and the promotion was aborted.
+	DataPlaneConditionReasonRolloutAnalysisFailed kcfgconsts.ConditionReason = "AnalysisFailed"
+)
+
+const (
+	defaultPromotionAnalysisWindow               = 5 * time.Minute
+	defaultPromotionAnalysisMinRequests          = 100
+	defaultPromotionAnalysisMaxErrorRateIncrease = 0.01
+	defaultPromotionAnalysisMaxLatencyRatio      = 1.2
+)
+
+// RolloutMetricsProvider provides summarized metrics of the live and preview
+// Pods of a DataPlane which are used in promotion analysis.
+type RolloutMetricsProvider interface {
+	ScrapeRolloutMetrics(
+		ctx context.Context, dp *operatorv1beta1.DataPlane,
+	) (live metricsscraper.RolloutMetricsSummary, preview metricsscraper.RolloutMetricsSummary, err error)
+}
+
+// promotionAnalysisConfig is the configuration of the promotion analysis
+// parsed from DataPlane's annotations.
+type promotionAnalysisConfig struct {
+	Window               time.Duration
+	MinRequests          float64
+	MaxErrorRateIncrease float64
+	MaxLatencyRatio      float64
+}
+
+// promotionAnalysisConfigFromDataPlane parses the promotion analysis configuration
+// from DataPlane's annotations. It returns false when the analysis is not enabled.
+func promotionAnalysisConfigFromDataPlane(dataplane *operatorv1beta1.DataPlane) (promotionAnalysisConfig, bool, error) {
+	cfg := promotionAnalysisConfig{
+		Window:               defaultPromotionAnalysisWindow,
+		MinRequests:          defaultPromotionAnalysisMinRequests,
+		MaxErrorRateIncrease: defaultPromotionAnalysisMaxErrorRateIncrease,
+		MaxLatencyRatio:      defaultPromotionAnalysisMaxLatencyRatio,
+	}
+
+	annotations := dataplane.GetAnnotations()
+	if v, ok := annotations[consts.DataPlanePromotionAnalysisAnnotation]; !ok {
+		return cfg, false, nil
+	} else if enabled, err := strconv.ParseBool(v); err != nil {
+		return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlanePromotionAnalysisAnnotation, v, err)
+	} else if !enabled {
+		return cfg, false, nil
+	}
+
+	if v, ok := annotations[consts.DataPlanePromotionAnalysisWindowAnnotation]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlanePromotionAnalysisWindowAnnotation, v, err)
+		}
+		if d <= 0 {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: must be positive", consts.DataPlanePromotionAnalysisWindowAnnotation, v)
+		}
+		cfg.Window = d
+	}
+
+	for _, f := range []struct {
+		annotation string
+		target     *float64
+	}{
+		{annotation: consts.DataPlanePromotionAnalysisMinRequestsAnnotation, target: &cfg.MinRequests},
+		{annotation: consts.DataPlanePromotionAnalysisMaxErrorRateIncreaseAnnotation, target: &cfg.MaxErrorRateIncrease},
+		{annotation: consts.DataPlanePromotionAnalysisMaxLatencyRatioAnnotation, target: &cfg.MaxLatencyRatio},
+	} {
+		v, ok := annotations[f.annotation]
+		if !ok {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", f.annotation, v, err)
+		}
+		if parsed < 0 {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: must not be negative", f.annotation, v)
+		}
+		*f.target = parsed
+	}
+
+	return cfg, true, nil
+}
+
+type promotionAnalysisVerdict uint8
+
+const (
+	promotionAnalysisVerdictInconclusive promotionAnalysisVerdict = iota
+	promotionAnalysisVerdictPassed
+	promotionAnalysisVerdictFailed
+)
+
+// evaluatePromotionAnalysis compares the metrics of the live and preview Pods
+// observed during the analysis window and returns the verdict together with
+// a human readable explanation.
+func evaluatePromotionAnalysis(
+	cfg promotionAnalysisConfig,
+	live, preview metricsscraper.RolloutMetricsSummary,
+) (promotionAnalysisVerdict, string) {
+	if preview.Requests < cfg.MinRequests {
+		return promotionAnalysisVerdictInconclusive,
+			fmt.Sprintf("preview served %.0f requests, at least %.0f are required", preview.Requests, cfg.MinRequests)
+	}
+
+	if increase := preview.ErrorRate() - live.ErrorRate(); increase > cfg.MaxErrorRateIncrease {
+		return promotionAnalysisVerdictFailed,
+			fmt.Sprintf("preview error rate %.4f exceeds live error rate %.4f by more than %.4f",
+				preview.ErrorRate(), live.ErrorRate(), cfg.MaxErrorRateIncrease)
+	}
+
+	if live.UpstreamLatencyCount > 0 && preview.UpstreamLatencyCount > 0 {
+		liveLatency, previewLatency := live.MeanUpstreamLatencyMs(), preview.MeanUpstreamLatencyMs()
+		if previewLatency > liveLatency*cfg.MaxLatencyRatio {
+			return promotionAnalysisVerdictFailed,
+				fmt.Sprintf("preview mean upstream latency %.2fms exceeds %.2f times the live one (%.2fms)",
+					previewLatency, cfg.MaxLatencyRatio, liveLatency)
+		}
+	}
+
+	return promotionAnalysisVerdictPassed, ""
+}
+
+// promotionAnalysis holds the metrics baselines of an ongoing promotion analysis of
+// a single preview Deployment (identified by its selector and DataPlane's generation).
+// The baselines are kept in memory only: when the operator restarts or the leader
+// changes, the analysis window starts over. Verdicts are persisted in DataPlane's
+// Rollout Status in the PromotionAnalysis condition.
+type promotionAnalysis struct {
+	selector        string
+	generation      int64
+	startedAt       time.Time
+	liveBaseline    metricsscraper.RolloutMetricsSummary
+	previewBaseline metricsscraper.RolloutMetricsSummary
+}
+
+// promotionAnalyses stores promotion analyses indexed by DataPlane UID.
+type promotionAnalyses struct {
+	lock     sync.Mutex
+	analyses map[types.UID]promotionAnalysis
+}
+
+func (p *promotionAnalyses) get(uid types.UID) (promotionAnalysis, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	a, ok := p.analyses[uid]
+	return a, ok
+}
+
+func (p *promotionAnalyses) set(uid types.UID, a promotionAnalysis) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.analyses == nil {
+		p.analyses = make(map[types.UID]promotionAnalysis)
+	}
+	p.analyses[uid] = a
+}
+
+func (p *promotionAnalyses) delete(uid types.UID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.analyses, uid)
+}
+
+// ensurePromotionAnalysisPassed runs the promotion analysis for the current preview
+// Deployment of the provided DataPlane.
+// It returns true when the analysis is either not enabled or has passed and the
+// promotion can proceed. Otherwise it returns false and a ctrl.Result which
+// should be returned from the reconciliation.
+func (r *BlueGreenReconciler) ensurePromotionAnalysisPassed(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+) (bool, ctrl.Result, error) {
+	cfg, enabled, err := promotionAnalysisConfigFromDataPlane(dataplane)
+	if err != nil {
+		cErr := r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutAnalysisFailed, err.Error())
+		return false, ctrl.Result{}, cErr
+	}
+	if !enabled {
+		r.promotionAnalyses.delete(dataplane.UID)
+		return true, ctrl.Result{}, nil
+	}
+
+	// Promotion has already started: live Services point to the preview Pods
+	// so there's nothing to compare against anymore.
+	selector := dataplane.Status.RolloutStatus.Deployment.Selector
+	if dataplane.Status.Selector == selector {
+		return true, ctrl.Result{}, nil
+	}
+
+	if r.RolloutMetricsProvider == nil {
+		err := r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutAnalysisFailed,
+			"promotion analysis requested but metrics are not available")
+		return false, ctrl.Result{}, err
+	}
+
+	// A verdict has already been recorded for this DataPlane generation.
+	if c, ok := k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, dataplane.Status.RolloutStatus); ok &&
+		c.ObservedGeneration == dataplane.Generation {
+		switch kcfgconsts.ConditionReason(c.Reason) {
+		case DataPlaneConditionReasonRolloutAnalysisPassed:
+			return true, ctrl.Result{}, nil
+		case DataPlaneConditionReasonRolloutAnalysisFailed:
+			err := r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutAnalysisFailed, c.Message)
+			return false, ctrl.Result{}, err
+		default:
+			// The analysis is still in progress.
+		}
+	}
+
+	a, ok := r.promotionAnalyses.get(dataplane.UID)
+	ok = ok && a.selector == selector && a.generation == dataplane.Generation
+	if ok {
+		if remaining := cfg.Window - time.Since(a.startedAt); remaining > 0 {
+			return false, ctrl.Result{RequeueAfter: remaining}, nil
+		}
+	}
+
+	live, preview, err := r.RolloutMetricsProvider.ScrapeRolloutMetrics(ctx, dataplane)
+	if err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("failed scraping metrics for promotion analysis: %w", err)
+	}
+
+	// No analysis for this preview Deployment yet (or its baselines were lost):
+	// record the baselines and wait for the analysis window to elapse.
+	if !ok {
+		r.promotionAnalyses.set(dataplane.UID, promotionAnalysis{
+			selector:        selector,
+			generation:      dataplane.Generation,
+			startedAt:       time.Now(),
+			liveBaseline:    live,
+			previewBaseline: preview,
+		})
+		log.Debug(logger, "promotion analysis started", "window", cfg.Window)
+		err := r.ensurePromotionAnalysisConditions(ctx, logger, dataplane, metav1.ConditionUnknown, DataPlaneConditionReasonRolloutAnalysisInProgress, "")
+		return false, ctrl.Result{RequeueAfter: cfg.Window}, err
+	}
+
+	verdict, msg := evaluatePromotionAnalysis(cfg, live.Sub(a.liveBaseline), preview.Sub(a.previewBaseline))
+	switch verdict {
+	case promotionAnalysisVerdictPassed:
+		log.Debug(logger, "promotion analysis passed")
+		r.promotionAnalyses.delete(dataplane.UID)
+		err := r.ensureRolloutStatusCondition(ctx, logger, dataplane, DataPlaneConditionTypePromotionAnalysis,
+			metav1.ConditionTrue, DataPlaneConditionReasonRolloutAnalysisPassed, "")
+		return err == nil, ctrl.Result{}, err
+
+	case promotionAnalysisVerdictFailed:
+		log.Info(logger, "promotion analysis failed, aborting promotion", "reason", msg)
+		r.promotionAnalyses.delete(dataplane.UID)
+		err := r.ensurePromotionAnalysisConditions(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutAnalysisFailed, msg)
+		return false, ctrl.Result{}, err
+
+	default:
+		// Not enough data to decide: start another analysis window.
+		log.Debug(logger, "promotion analysis inconclusive, extending analysis", "reason", msg)
+		r.promotionAnalyses.set(dataplane.UID, promotionAnalysis{
+			selector:        selector,
+			generation:      dataplane.Generation,
+			startedAt:       time.Now(),
+			liveBaseline:    live,
+			previewBaseline: preview,
+		})
+		err := r.ensurePromotionAnalysisConditions(ctx, logger, dataplane, metav1.ConditionUnknown, DataPlaneConditionReasonRolloutAnalysisInProgress, msg)
+		return false, ctrl.Result{RequeueAfter: cfg.Window}, err
+	}
+}
+
+// promotionAnalysisFailed returns the PromotionAnalysis condition and true when
+// the promotion analysis has failed for the current DataPlane generation.
+func promotionAnalysisFailed(dataplane *operatorv1beta1.DataPlane) (metav1.Condition, bool) {
+	c, ok := k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, dataplane.Status.RolloutStatus)
+	return c, ok && c.ObservedGeneration == dataplane.Generation &&
+		c.Reason == string(DataPlaneConditionReasonRolloutAnalysisFailed)
+}
+
+// ensurePromotionAnalysisConditions sets the PromotionAnalysis condition and the RolledOut
+// condition (which is always false while the analysis blocks the promotion) in DataPlane's
+// Rollout Status.
+func (r *BlueGreenReconciler) ensurePromotionAnalysisConditions(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	status metav1.ConditionStatus,
+	reason kcfgconsts.ConditionReason,
+	message string,
+) error {
+	if err := r.ensureRolloutStatusCondition(ctx, logger, dataplane, DataPlaneConditionTypePromotionAnalysis, status, reason, message); err != nil {
+		return err
+	}
+	return r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, reason, message)
+}
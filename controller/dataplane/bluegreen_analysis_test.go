@@ -0,0 +1,306 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/v2/api/common/consts"
+	kcfgdataplane "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+func TestPromotionAnalysisConfigFromDataPlane(t *testing.T) {
+	defaultCfg := promotionAnalysisConfig{
+		Window:               defaultPromotionAnalysisWindow,
+		MinRequests:          defaultPromotionAnalysisMinRequests,
+		MaxErrorRateIncrease: defaultPromotionAnalysisMaxErrorRateIncrease,
+		MaxLatencyRatio:      defaultPromotionAnalysisMaxLatencyRatio,
+	}
+
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		expectedCfg     promotionAnalysisConfig
+		expectedEnabled bool
+		expectedErr     bool
+	}{
+		{
+			name:        "no annotations",
+			expectedCfg: defaultCfg,
+		},
+		{
+			name: "analysis disabled",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation: "false",
+			},
+			expectedCfg: defaultCfg,
+		},
+		{
+			name: "analysis enabled with defaults",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation: "true",
+			},
+			expectedCfg:     defaultCfg,
+			expectedEnabled: true,
+		},
+		{
+			name: "analysis enabled with custom settings",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:                     "true",
+				consts.DataPlanePromotionAnalysisWindowAnnotation:               "2m",
+				consts.DataPlanePromotionAnalysisMinRequestsAnnotation:          "10",
+				consts.DataPlanePromotionAnalysisMaxErrorRateIncreaseAnnotation: "0.05",
+				consts.DataPlanePromotionAnalysisMaxLatencyRatioAnnotation:      "1.5",
+			},
+			expectedCfg: promotionAnalysisConfig{
+				Window:               2 * time.Minute,
+				MinRequests:          10,
+				MaxErrorRateIncrease: 0.05,
+				MaxLatencyRatio:      1.5,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "invalid enabled value",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation: "yes please",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid window",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:       "true",
+				consts.DataPlanePromotionAnalysisWindowAnnotation: "-1m",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "negative threshold",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:                "true",
+				consts.DataPlanePromotionAnalysisMaxLatencyRatioAnnotation: "-1",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			cfg, enabled, err := promotionAnalysisConfigFromDataPlane(dp)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEnabled, enabled)
+			assert.Equal(t, tc.expectedCfg, cfg)
+		})
+	}
+}
+
+func TestEvaluatePromotionAnalysis(t *testing.T) {
+	cfg := promotionAnalysisConfig{
+		Window:               time.Minute,
+		MinRequests:          100,
+		MaxErrorRateIncrease: 0.01,
+		MaxLatencyRatio:      1.2,
+	}
+
+	testCases := []struct {
+		name            string
+		live            metricsscraper.RolloutMetricsSummary
+		preview         metricsscraper.RolloutMetricsSummary
+		expectedVerdict promotionAnalysisVerdict
+	}{
+		{
+			name: "not enough preview requests",
+			live: metricsscraper.RolloutMetricsSummary{Requests: 1000},
+			preview: metricsscraper.RolloutMetricsSummary{
+				Requests: 99,
+			},
+			expectedVerdict: promotionAnalysisVerdictInconclusive,
+		},
+		{
+			name: "preview is as healthy as live",
+			live: metricsscraper.RolloutMetricsSummary{
+				Requests:             1000,
+				ServerErrors:         10,
+				UpstreamLatencySumMs: 10000,
+				UpstreamLatencyCount: 1000,
+			},
+			preview: metricsscraper.RolloutMetricsSummary{
+				Requests:             200,
+				ServerErrors:         2,
+				UpstreamLatencySumMs: 2200,
+				UpstreamLatencyCount: 200,
+			},
+			expectedVerdict: promotionAnalysisVerdictPassed,
+		},
+		{
+			name: "preview error rate regressed",
+			live: metricsscraper.RolloutMetricsSummary{
+				Requests:     1000,
+				ServerErrors: 10,
+			},
+			preview: metricsscraper.RolloutMetricsSummary{
+				Requests:     200,
+				ServerErrors: 10,
+			},
+			expectedVerdict: promotionAnalysisVerdictFailed,
+		},
+		{
+			name: "preview latency regressed",
+			live: metricsscraper.RolloutMetricsSummary{
+				Requests:             1000,
+				UpstreamLatencySumMs: 10000,
+				UpstreamLatencyCount: 1000,
+			},
+			preview: metricsscraper.RolloutMetricsSummary{
+				Requests:             200,
+				UpstreamLatencySumMs: 5000,
+				UpstreamLatencyCount: 200,
+			},
+			expectedVerdict: promotionAnalysisVerdictFailed,
+		},
+		{
+			name: "no live traffic to compare latency against",
+			live: metricsscraper.RolloutMetricsSummary{},
+			preview: metricsscraper.RolloutMetricsSummary{
+				Requests:             200,
+				UpstreamLatencySumMs: 5000,
+				UpstreamLatencyCount: 200,
+			},
+			expectedVerdict: promotionAnalysisVerdictPassed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict, msg := evaluatePromotionAnalysis(cfg, tc.live, tc.preview)
+			assert.Equal(t, tc.expectedVerdict, verdict)
+			if verdict != promotionAnalysisVerdictPassed {
+				assert.NotEmpty(t, msg)
+			}
+		})
+	}
+}
+
+type rolloutMetricsProviderFunc func(ctx context.Context, dp *operatorv1beta1.DataPlane) (metricsscraper.RolloutMetricsSummary, metricsscraper.RolloutMetricsSummary, error)
+
+func (f rolloutMetricsProviderFunc) ScrapeRolloutMetrics(
+	ctx context.Context, dp *operatorv1beta1.DataPlane,
+) (metricsscraper.RolloutMetricsSummary, metricsscraper.RolloutMetricsSummary, error) {
+	return f(ctx, dp)
+}
+
+func TestEnsurePromotionAnalysisPassedUsesPersistedVerdict(t *testing.T) {
+	testCases := []struct {
+		name           string
+		reason         kcfgconsts.ConditionReason
+		status         metav1.ConditionStatus
+		generation     int64
+		expectedPassed bool
+		expectScrape   bool
+	}{
+		{
+			name:           "passed verdict for the current generation",
+			reason:         DataPlaneConditionReasonRolloutAnalysisPassed,
+			status:         metav1.ConditionTrue,
+			generation:     2,
+			expectedPassed: true,
+		},
+		{
+			name:       "failed verdict for the current generation",
+			reason:     DataPlaneConditionReasonRolloutAnalysisFailed,
+			status:     metav1.ConditionFalse,
+			generation: 2,
+		},
+		{
+			name:         "failed verdict for a previous generation",
+			reason:       DataPlaneConditionReasonRolloutAnalysisFailed,
+			status:       metav1.ConditionFalse,
+			generation:   1,
+			expectScrape: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "dp",
+					Namespace:  "default",
+					UID:        types.UID("dp-uid"),
+					Generation: 2,
+					Annotations: map[string]string{
+						consts.DataPlanePromotionAnalysisAnnotation: "true",
+					},
+				},
+				Status: operatorv1beta1.DataPlaneStatus{
+					Selector: "live",
+					RolloutStatus: &operatorv1beta1.DataPlaneRolloutStatus{
+						Deployment: &operatorv1beta1.DataPlaneRolloutStatusDeployment{
+							Selector: "preview",
+						},
+						Conditions: []metav1.Condition{
+							k8sutils.NewConditionWithGeneration(DataPlaneConditionTypePromotionAnalysis, tc.status, tc.reason, "preview error rate too high", tc.generation),
+						},
+					},
+				},
+			}
+			fakeClient := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(dp).
+				WithStatusSubresource(dp).
+				Build()
+
+			var scraped bool
+			r := BlueGreenReconciler{
+				Client: fakeClient,
+				RolloutMetricsProvider: rolloutMetricsProviderFunc(func(context.Context, *operatorv1beta1.DataPlane) (metricsscraper.RolloutMetricsSummary, metricsscraper.RolloutMetricsSummary, error) {
+					scraped = true
+					return metricsscraper.RolloutMetricsSummary{}, metricsscraper.RolloutMetricsSummary{}, nil
+				}),
+			}
+
+			passed, _, err := r.ensurePromotionAnalysisPassed(t.Context(), logr.Discard(), dp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPassed, passed)
+			assert.Equal(t, tc.expectScrape, scraped)
+
+			if !tc.expectedPassed && !tc.expectScrape {
+				rolledOut, ok := k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, dp.Status.RolloutStatus)
+				require.True(t, ok)
+				assert.Equal(t, string(DataPlaneConditionReasonRolloutAnalysisFailed), rolledOut.Reason)
+				assert.Equal(t, "preview error rate too high", rolledOut.Message)
+			}
+			if tc.expectScrape {
+				analysis, ok := k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, dp.Status.RolloutStatus)
+				require.True(t, ok)
+				assert.Equal(t, string(DataPlaneConditionReasonRolloutAnalysisInProgress), analysis.Reason)
+				assert.Equal(t, dp.Generation, analysis.ObservedGeneration)
+			}
+		})
+	}
+}
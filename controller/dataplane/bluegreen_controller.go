@@ -16,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -48,6 +49,7 @@ import (
 // of Blue Green rollouts.
 type BlueGreenReconciler struct {
 	client.Client
+	eventRecorder record.EventRecorder
 
 	// DataPlaneController contains the DataPlaneReconciler to which we delegate
 	// the DataPlane reconciliation when it's not yet ready to accept BlueGreen
@@ -63,6 +65,12 @@ type BlueGreenReconciler struct {
 	// Deployment.
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
+	// ClusterCertificateRenewalConfig configures the lifetime and renewal of the
+	// DataPlane's Admin API certificate.
+	ClusterCertificateRenewalConfig secrets.CertificateRenewalConfig
+	// ClusterCertificateIssuer configures the issuer of the DataPlane's Admin API certificate.
+	// The cluster CA is used when it's not set.
+	ClusterCertificateIssuer secrets.IssuerConfig
 
 	SecretLabelSelector string
 
@@ -74,6 +82,12 @@ type BlueGreenReconciler struct {
 	EnforceConfig          bool
 	ValidateDataPlaneImage bool
 	LoggingMode            logging.Mode
+
+	// RolloutMetricsProvider provides metrics of live and preview Pods used
+	// to perform promotion analysis when it's enabled on a DataPlane.
+	RolloutMetricsProvider RolloutMetricsProvider
+
+	promotionAnalyses promotionAnalyses
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -82,7 +96,8 @@ func (r *BlueGreenReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Man
 	if !ok {
 		return fmt.Errorf("incorrect delegate controller type: %T", r.DataPlaneController)
 	}
-	delegate.eventRecorder = mgr.GetEventRecorderFor("dataplane")
+	r.eventRecorder = mgr.GetEventRecorderFor("dataplane")
+	delegate.eventRecorder = r.eventRecorder
 	return DataPlaneWatchBuilder(mgr, r.KonnectEnabled).
 		WithOptions(controller.Options{
 			CacheSyncTimeout: r.CacheSyncTimeout,
@@ -112,6 +127,14 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return r.DataPlaneController.Reconcile(ctx, req)
 	}
 
+	// Roll back to the previously live revision when requested or keep
+	// the DataPlane on the restored revision until its spec changes.
+	if handled, res, err := r.ensureRolledBackToPreviousRevision(ctx, logger, &dataplane); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed rolling back DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	} else if handled {
+		return res, nil
+	}
+
 	if shouldDelegateToDataPlaneController(&dataplane, logger) {
 		return r.DataPlaneController.Reconcile(ctx, req)
 	}
@@ -188,7 +211,19 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		},
 		r.SecretLabelSelector,
 		r.ClusterCAKeyConfig,
+		r.ClusterCertificateIssuer,
+		r.ClusterCertificateRenewalConfig,
+		r.eventRecorder,
 	)
+	if errors.Is(err, secrets.ErrCertificateNotReady) {
+		log.Debug(logger, "waiting for mTLS certificate to be issued")
+		return ctrl.Result{RequeueAfter: secrets.CertificateNotReadyRequeueAfter}, nil
+	}
+	if errors.Is(err, secrets.ErrCertificateIssuanceFailed) {
+		// The certificate is requested again when the request or its Secret changes.
+		log.Info(logger, "mTLS certificate issuance failed", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -220,7 +255,16 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, fmt.Errorf("failed to ensure Deployment for DataPlane: %w", errors.Join(cErr, err))
 	} else if res == op.Created || res == op.Updated {
 		return ctrl.Result{}, nil // dataplane deployment creation/update will trigger reconciliation
-	} else if replicas := deployment.Spec.Replicas; replicas != nil && *replicas == 0 {
+	}
+
+	// Roll back progressive traffic shifting if the preview Pods lost readiness.
+	if rolledBack, err := r.ensureTrafficShiftingRolledBack(ctx, logger, &dataplane, deployment); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed rolling back traffic shifting for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	} else if rolledBack {
+		return ctrl.Result{}, nil
+	}
+
+	if replicas := deployment.Spec.Replicas; replicas != nil && *replicas == 0 {
 		return ctrl.Result{}, r.ensureRolledOutCondition(ctx, logger, &dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutWaitingForChange, "")
 	}
 
@@ -233,9 +277,6 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
-	// TODO: Perform promotion condition checks to verify we can proceed
-	// Ref: https://github.com/kong/kong-operator/issues/170
-
 	if proceedWithPromotion, err := canProceedWithPromotion(dataplane); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed checking if DataPlane %s/%s can be promoted: %w", dataplane.Namespace, dataplane.Name, err)
 	} else if !proceedWithPromotion {
@@ -246,6 +287,23 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// Progressively shift the traffic to the preview Pods when configured.
+	if shifted, res, err := r.ensureTrafficShifted(ctx, logger, &dataplane, deployment); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed shifting traffic for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	} else if !shifted {
+		return res, nil
+	}
+
+	// Verify that the preview Pods do not regress compared to the live ones
+	// when metrics-driven promotion analysis is enabled. The analysis runs while
+	// the traffic is shifted so this only confirms the recorded verdict or
+	// reports the invalid analysis configuration.
+	if passed, res, err := r.ensurePromotionAnalysisPassed(ctx, logger, &dataplane); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed performing promotion analysis for DataPlane %s/%s: %w", dataplane.Namespace, dataplane.Name, err)
+	} else if !passed {
+		return res, nil
+	}
+
 	// If we've failed to promote previously, don't set the RolledOut reason to
 	// PromotionInProgress as the error can reoccur and the status can start flapping.
 	c, ok = k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, dataplane.Status.RolloutStatus)
@@ -301,6 +359,10 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		// reconciliation to create new preview.
 		old := dataplane.DeepCopy()
 		dataplane.Status.RolloutStatus.Deployment.Selector = ""
+		// The promotion analysis verdict applied to the promoted preview only.
+		dataplane.Status.RolloutStatus.Conditions = lo.Reject(dataplane.Status.RolloutStatus.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(DataPlaneConditionTypePromotionAnalysis)
+		})
 		if err := r.Client.Status().Patch(ctx, &dataplane, client.MergeFrom(old)); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed updating DataPlane's RolloutStatus: %w", err)
 		}
@@ -316,13 +378,19 @@ func (r *BlueGreenReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if err := r.resetPromoteWhenReadyAnnotation(ctx, &dataplane); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed resetting promote-when-ready annotation: %w", err)
 	}
+	r.promotionAnalyses.delete(dataplane.UID)
 
 	if err := r.reduceLiveDeployments(ctx, logger, &dataplane); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reduce live deployments: %w", err)
 	}
+	if err := r.ensureRevisionHistoryCondition(ctx, logger, &dataplane); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to record revision history: %w", err)
+	}
 
 	log.Debug(logger, "BlueGreen reconciliation complete for DataPlane resource")
-	return ctrl.Result{}, nil
+	return ctrl.Result{
+		RequeueAfter: secrets.CertificateRenewalRequeueAfter(certSecret, r.ClusterCertificateRenewalConfig),
+	}, nil
 }
 
 // ensureDataPlaneLiveReadyStatus ensures that the DataPlane has the Ready status
@@ -391,8 +459,8 @@ func shouldDelegateToDataPlaneController(
 	return false
 }
 
-// prunePreviewSubresources is used to prune DataPlane's preview subresources
-// when they are not necessary anymore, e.g. when rollout strategy is unset.
+// prunePreviewSubresources is used to prune DataPlane's preview (and retired)
+// subresources when they are not necessary anymore, e.g. when rollout strategy is unset.
 func (r *BlueGreenReconciler) prunePreviewSubresources(
 	ctx context.Context,
 	dataplane *operatorv1beta1.DataPlane,
@@ -419,6 +487,17 @@ func (r *BlueGreenReconciler) prunePreviewSubresources(
 		}
 	}
 
+	retiredDeployments, err := r.listRetiredDeployments(ctx, dataplane)
+	if err != nil {
+		return err
+	}
+	if len(retiredDeployments) > 0 {
+		log.Debug(logger, "removing retired Deployments")
+		if err := removeObjectSliceWithDataPlaneOwnedFinalizer(ctx, r.Client, retiredDeployments); err != nil {
+			return err
+		}
+	}
+
 	services, err := k8sutils.ListServicesForOwner(
 		ctx,
 		r.Client,
@@ -503,6 +582,7 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 ) (*appsv1.Deployment, op.Result, error) {
 	deploymentOpts := []k8sresources.DeploymentOpt{
 		labelSelectorFromDataPlaneRolloutStatusSelectorDeploymentOpt(dataplane),
+		clusterCertificateDeploymentOpt(certSecret),
 	}
 
 	// If we're running the exact same Generation as "live" version is then:
@@ -519,6 +599,13 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 		}
 		// TODO: implement DeleteOnPromotionRecreateOnRollout
 		// Ref: https://github.com/kong/kong-operator/issues/163
+	} else {
+		// Keep the preview Deployment's replicas managed by progressive traffic shifting.
+		trafficShiftingOpts, err := r.previewDeploymentTrafficShiftingOpts(ctx, dataplane)
+		if err != nil {
+			return nil, op.Noop, err
+		}
+		deploymentOpts = append(deploymentOpts, trafficShiftingOpts...)
 	}
 	deploymentLabels := client.MatchingLabels{
 		consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValuePreview,
@@ -530,6 +617,7 @@ func (r *BlueGreenReconciler) ensureDeploymentForDataPlane(
 
 	deploymentBuilder := NewDeploymentBuilder(logger.WithName("deployment_builder"), r.Client).
 		WithClusterCertificate(certSecret.Name).
+		WithClusterCertificateSerial(clusterCertificateSerial(certSecret)).
 		WithOpts(deploymentOpts...).
 		WithDefaultImage(r.DefaultImage).
 		WithAdditionalLabels(deploymentLabels).
@@ -568,6 +656,8 @@ func (r *BlueGreenReconciler) resetPromoteWhenReadyAnnotation(
 // reduceLiveDeployments reduces the number of live deployments to 1 by deleting the oldest ones.
 // It's used to reduce the number of live deployments that are not being used anymore after promotion (the old live
 // deployment gets "replaced" by the preview deployment).
+// When the DataPlane retains revision history the old live deployments are retired instead
+// and the retired deployments exceeding the limit are pruned.
 func (r *BlueGreenReconciler) reduceLiveDeployments(
 	ctx context.Context,
 	logger logr.Logger,
@@ -588,17 +678,29 @@ func (r *BlueGreenReconciler) reduceLiveDeployments(
 		return fmt.Errorf("failed listing live deployments: %w", err)
 	}
 
-	// If there's only one or no deployments, there's nothing to do.
+	limit, err := revisionHistoryLimitFromDataPlane(dataPlane)
+	if err != nil {
+		return err
+	}
+
+	// If there's only one or no deployments, there's nothing to reduce.
 	if len(deployments) < 2 {
-		return nil
+		return r.pruneRetiredDeployments(ctx, logger, dataPlane, limit)
 	}
 
 	// Sort deployments by creation timestamp, so that we can delete the oldest ones.
 	sort.Slice(deployments, func(i, j int) bool {
 		return deployments[i].CreationTimestamp.Before(&deployments[j].CreationTimestamp)
 	})
-	// Delete all but the last deployment.
+	// Delete (or retire when revision history is retained) all but the last deployment.
 	for _, deployment := range deployments[:len(deployments)-1] {
+		if limit > 0 {
+			if err := r.retireDeployment(ctx, logger, &deployment); err != nil {
+				return err
+			}
+			continue
+		}
+
 		log.Debug(logger, "reducing live deployment",
 			"deployment", client.ObjectKeyFromObject(&deployment),
 		)
@@ -610,7 +712,7 @@ func (r *BlueGreenReconciler) reduceLiveDeployments(
 			return fmt.Errorf("failed deleting live deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
 		}
 	}
-	return nil
+	return r.pruneRetiredDeployments(ctx, logger, dataPlane, limit)
 }
 
 // ensureRolledOutCondition ensures that DataPlane rollout status contains RolledOut
@@ -623,7 +725,20 @@ func (r *BlueGreenReconciler) ensureRolledOutCondition(
 	reason kcfgconsts.ConditionReason,
 	message string,
 ) error {
-	c, ok := k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, dataplane.Status.RolloutStatus)
+	return r.ensureRolloutStatusCondition(ctx, logger, dataplane, kcfgdataplane.DataPlaneConditionTypeRolledOut, status, reason, message)
+}
+
+// ensureRolloutStatusCondition ensures that the provided condition is set in DataPlane's Rollout Status.
+func (r *BlueGreenReconciler) ensureRolloutStatusCondition(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	conditionType kcfgconsts.ConditionType,
+	status metav1.ConditionStatus,
+	reason kcfgconsts.ConditionReason,
+	message string,
+) error {
+	c, ok := k8sutils.GetCondition(conditionType, dataplane.Status.RolloutStatus)
 	if ok && c.ObservedGeneration == dataplane.Generation && c.Status == status && c.Reason == string(reason) && c.Message == message {
 		// DataPlane rollout status already contains this condition.
 		return nil
@@ -631,7 +746,7 @@ func (r *BlueGreenReconciler) ensureRolledOutCondition(
 
 	oldDataPlane := dataplane.DeepCopy()
 	k8sutils.SetCondition(
-		k8sutils.NewConditionWithGeneration(kcfgdataplane.DataPlaneConditionTypeRolledOut, status, reason, message, dataplane.Generation),
+		k8sutils.NewConditionWithGeneration(conditionType, status, reason, message, dataplane.Generation),
 		dataplane.Status.RolloutStatus,
 	)
 	_, err := r.patchRolloutStatus(ctx, logger, oldDataPlane, dataplane)
@@ -750,6 +865,11 @@ func canProceedWithPromotion(dataplane operatorv1beta1.DataPlane) (bool, error)
 	promotionStrategy := dataplane.Spec.Deployment.Rollout.Strategy.BlueGreen.Promotion.Strategy
 	switch promotionStrategy {
 	case operatorv1beta1.BreakBeforePromotion:
+		// When the promotion analysis is enabled, its verdict gates the promotion
+		// and the preview is promoted automatically once the analysis passes.
+		if _, enabled, err := promotionAnalysisConfigFromDataPlane(&dataplane); err == nil && enabled {
+			return true, nil
+		}
 		// If the promotion strategy is BreakBeforePromotion then we need to wait for the user to explicitly
 		// mark the DataPlane with the promote-when-ready annotation.
 		return dataplane.Annotations[operatorv1beta1.DataPlanePromoteWhenReadyAnnotationKey] ==
@@ -421,6 +421,20 @@ func TestCanProceedWithPromotion(t *testing.T) {
 				Build(),
 			expectedCanProceed: true,
 		},
+		{
+			name: "BreakBeforePromotion strategy, promotion analysis enabled",
+			dataplane: *builder.NewDataPlaneBuilder().
+				WithObjectMeta(
+					metav1.ObjectMeta{
+						Annotations: map[string]string{
+							consts.DataPlanePromotionAnalysisAnnotation: "true",
+						},
+					},
+				).
+				WithPromotionStrategy(operatorv1beta1.BreakBeforePromotion).
+				Build(),
+			expectedCanProceed: true,
+		},
 		{
 			name: "unknown strategy",
 			dataplane: *builder.NewDataPlaneBuilder().
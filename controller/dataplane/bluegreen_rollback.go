@@ -0,0 +1,458 @@
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/v2/api/common/consts"
+	kcfgdataplane "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/pkg/dataplane"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlaneBlueGreenReconciler - Revision history and rollback
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneConditionReasonRolloutRevisionRestored is the reason of the
+	// RolledOut condition set when the DataPlane has been rolled back to
+	// a previously live revision. The DataPlane stays on the restored revision
+	// until its spec changes.
+	DataPlaneConditionReasonRolloutRevisionRestored kcfgconsts.ConditionReason = "RevisionRestored"
+
+	// DataPlaneConditionTypeRevisionHistory is the type of the DataPlane rollout
+	// status condition which records the revisions retained for a rollback.
+	// Its message lists the retired Deployments, from the most recently retired
+	// to the oldest one, together with their spec hashes and retirement times.
+	DataPlaneConditionTypeRevisionHistory kcfgconsts.ConditionType = "RevisionHistory"
+
+	// DataPlaneConditionReasonRevisionsRetained is the reason of the RevisionHistory
+	// condition set when there is at least one retained revision.
+	DataPlaneConditionReasonRevisionsRetained kcfgconsts.ConditionReason = "RevisionsRetained"
+
+	// DataPlaneConditionReasonNoRevisionsRetained is the reason of the RevisionHistory
+	// condition set when there is no retained revision to roll back to.
+	DataPlaneConditionReasonNoRevisionsRetained kcfgconsts.ConditionReason = "NoRevisionsRetained"
+)
+
+// revisionHistoryLimitFromDataPlane returns the number of previously live
+// Deployments that should be retained for the provided DataPlane.
+func revisionHistoryLimitFromDataPlane(dataplane *operatorv1beta1.DataPlane) (int, error) {
+	v, ok := dataplane.GetAnnotations()[consts.DataPlaneRolloutRevisionHistoryLimitAnnotation]
+	if !ok || v == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlaneRolloutRevisionHistoryLimitAnnotation, v, err)
+	}
+	if limit < 0 {
+		return 0, fmt.Errorf("invalid %s annotation value %q: must not be negative", consts.DataPlaneRolloutRevisionHistoryLimitAnnotation, v)
+	}
+	return limit, nil
+}
+
+// rollbackRequested returns true when the provided DataPlane has been annotated
+// to be rolled back to the most recently retired revision.
+func rollbackRequested(dataplane *operatorv1beta1.DataPlane) bool {
+	return dataplane.GetAnnotations()[consts.DataPlaneRollbackAnnotation] == "true"
+}
+
+// retiredAt returns the time at which the provided Deployment has been retired.
+// It falls back to the Deployment's creation timestamp when the annotation
+// is missing or malformed.
+func retiredAt(d *appsv1.Deployment) time.Time {
+	if v, ok := d.GetAnnotations()[consts.DataPlaneDeploymentRetiredAtAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t
+		}
+	}
+	return d.CreationTimestamp.Time
+}
+
+// listRetiredDeployments lists the retired Deployments of the provided DataPlane,
+// sorted from the most recently retired to the oldest one.
+func (r *BlueGreenReconciler) listRetiredDeployments(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]appsv1.Deployment, error) {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		dataplane.Namespace,
+		dataplane.UID,
+		client.MatchingLabels{
+			"app":                                dataplane.Name,
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValueRetired,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing retired deployments: %w", err)
+	}
+
+	sort.SliceStable(deployments, func(i, j int) bool {
+		return retiredAt(&deployments[i]).After(retiredAt(&deployments[j]))
+	})
+	return deployments, nil
+}
+
+// retireDeployment labels the provided Deployment as retired and scales it to 0
+// so that it can be restored when the DataPlane is rolled back.
+func (r *BlueGreenReconciler) retireDeployment(
+	ctx context.Context,
+	logger logr.Logger,
+	deployment *appsv1.Deployment,
+) error {
+	log.Debug(logger, "retiring live deployment",
+		"deployment", client.ObjectKeyFromObject(deployment),
+	)
+
+	old := deployment.DeepCopy()
+	if deployment.Labels == nil {
+		deployment.Labels = map[string]string{}
+	}
+	deployment.Labels[consts.DataPlaneDeploymentStateLabel] = consts.DataPlaneStateLabelValueRetired
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[consts.DataPlaneDeploymentRetiredAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	deployment.Spec.Replicas = lo.ToPtr(int32(0))
+	if err := r.Patch(ctx, deployment, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed retiring deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+	}
+	return nil
+}
+
+// pruneRetiredDeployments deletes the retired Deployments of the provided
+// DataPlane which exceed the provided revision history limit.
+func (r *BlueGreenReconciler) pruneRetiredDeployments(
+	ctx context.Context,
+	logger logr.Logger,
+	dataPlane *operatorv1beta1.DataPlane,
+	limit int,
+) error {
+	deployments, err := r.listRetiredDeployments(ctx, dataPlane)
+	if err != nil {
+		return err
+	}
+	if len(deployments) <= limit {
+		return nil
+	}
+
+	for _, deployment := range deployments[limit:] {
+		log.Debug(logger, "pruning retired deployment",
+			"deployment", client.ObjectKeyFromObject(&deployment),
+		)
+
+		if err := dataplane.OwnedObjectPreDeleteHook(ctx, r.Client, &deployment); err != nil {
+			return fmt.Errorf("failed executing pre delete hook: %w", err)
+		}
+		if err := r.Delete(ctx, &deployment); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed deleting retired deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+		}
+	}
+	return nil
+}
+
+// ensureRolledBackToPreviousRevision performs the rollback of the DataPlane
+// to the most recently retired revision when it has been requested through
+// the rollback annotation.
+// The restored Deployment is scaled up, and once it's ready the live Services
+// are pointed at it and the Deployment which was live so far gets retired.
+// After the rollback the DataPlane is kept on the restored revision until its
+// spec changes, which starts a new rollout cycle.
+//
+// It returns true when the reconciliation has been handled and should not proceed.
+func (r *BlueGreenReconciler) ensureRolledBackToPreviousRevision(
+	ctx context.Context,
+	logger logr.Logger,
+	dp *operatorv1beta1.DataPlane,
+) (bool, ctrl.Result, error) {
+	if !rollbackRequested(dp) {
+		c, ok := k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, dp.Status.RolloutStatus)
+		if !ok || c.ObservedGeneration != dp.Generation || c.Reason != string(DataPlaneConditionReasonRolloutRevisionRestored) {
+			return false, ctrl.Result{}, nil
+		}
+		// The DataPlane has been rolled back and its spec didn't change since
+		// so keep it on the restored revision.
+		res, err := ensureDataPlaneReadyStatus(ctx, r.Client, logger, dp, dp.Generation)
+		return true, res, err
+	}
+
+	initDataPlaneStatusRollout(dp)
+
+	liveDeployments, err := listDataPlaneLiveDeployments(ctx, r.Client, dp)
+	if err != nil {
+		return true, ctrl.Result{}, fmt.Errorf("failed listing live deployments: %w", err)
+	}
+
+	// More than 1 live Deployment means that the restored Deployment has
+	// already been labeled as live and we only need to retire the rest.
+	if len(liveDeployments) < 2 {
+		retired, err := r.listRetiredDeployments(ctx, dp)
+		if err != nil {
+			return true, ctrl.Result{}, err
+		}
+		if len(retired) == 0 {
+			log.Info(logger, "rollback requested but there is no retained revision to roll back to")
+			if err := r.ensureRolledOutCondition(ctx, logger, dp, metav1.ConditionFalse,
+				kcfgdataplane.DataPlaneConditionReasonRolloutFailed, "no retained revision to roll back to",
+			); err != nil {
+				return true, ctrl.Result{}, err
+			}
+			return true, ctrl.Result{}, r.resetRollbackAnnotation(ctx, dp)
+		}
+
+		// Prefer the retired Deployment which live Services have already been
+		// pointed at in case the previous attempt was interrupted.
+		target, ok := lo.Find(retired, func(d appsv1.Deployment) bool {
+			return d.Spec.Selector != nil && d.Spec.Selector.MatchLabels[consts.OperatorLabelSelector] == dp.Status.Selector
+		})
+		if !ok {
+			target = retired[0]
+		}
+		if target.Spec.Selector == nil || target.Spec.Selector.MatchLabels[consts.OperatorLabelSelector] == "" {
+			return true, ctrl.Result{}, fmt.Errorf("retired deployment %s/%s has no %s selector", target.Namespace, target.Name, consts.OperatorLabelSelector)
+		}
+		targetSelector := target.Spec.Selector.MatchLabels[consts.OperatorLabelSelector]
+
+		replicas, err := r.desiredLiveReplicas(ctx, dp)
+		if err != nil {
+			return true, ctrl.Result{}, err
+		}
+		if lo.FromPtr(target.Spec.Replicas) != replicas {
+			old := target.DeepCopy()
+			target.Spec.Replicas = lo.ToPtr(replicas)
+			if err := r.Patch(ctx, &target, client.MergeFrom(old)); err != nil {
+				return true, ctrl.Result{}, fmt.Errorf("failed scaling retired deployment %s/%s: %w", target.Namespace, target.Name, err)
+			}
+			err := r.ensureRolledOutCondition(ctx, logger, dp, metav1.ConditionFalse,
+				kcfgdataplane.DataPlaneConditionReasonRolloutProgressing,
+				fmt.Sprintf("restoring revision of Deployment %s", target.Name),
+			)
+			return true, ctrl.Result{}, err
+		}
+		if _, ready := isDeploymentReady(target.Status); !ready || target.Status.ObservedGeneration < target.Generation {
+			log.Trace(logger, "restored deployment for DataPlane not ready yet")
+			// Deployment status changes will trigger another reconciliation.
+			return true, ctrl.Result{}, nil
+		}
+
+		if dp.Status.Selector != targetSelector {
+			old := dp.DeepCopy()
+			dp.Status.Selector = targetSelector
+			if err := r.Client.Status().Patch(ctx, dp, client.MergeFrom(old)); err != nil {
+				return true, ctrl.Result{}, fmt.Errorf("failed to change live selector to the restored deployment: %w", err)
+			}
+		}
+		if err := r.ensureLiveServicesSelector(ctx, dp, targetSelector); err != nil {
+			return true, ctrl.Result{}, err
+		}
+
+		old := target.DeepCopy()
+		target.Labels[consts.DataPlaneDeploymentStateLabel] = consts.DataPlaneStateLabelValueLive
+		delete(target.Annotations, consts.DataPlaneDeploymentRetiredAtAnnotation)
+		if err := r.Patch(ctx, &target, client.MergeFrom(old)); err != nil {
+			return true, ctrl.Result{}, fmt.Errorf("failed labeling restored deployment %s/%s as live: %w", target.Namespace, target.Name, err)
+		}
+		liveDeployments = append(liveDeployments, target)
+	}
+
+	// Live Services point at the restored revision now, so the preview
+	// Deployment together with its traffic shifting state can be removed.
+	if err := r.cleanupPreviewForRollback(ctx, logger, dp); err != nil {
+		return true, ctrl.Result{}, err
+	}
+
+	var restoredSpecHash string
+	for i := range liveDeployments {
+		d := &liveDeployments[i]
+		if d.Spec.Selector != nil && d.Spec.Selector.MatchLabels[consts.OperatorLabelSelector] == dp.Status.Selector {
+			restoredSpecHash = d.Annotations[consts.AnnotationSpecHash]
+			continue
+		}
+		if err := r.retireDeployment(ctx, logger, d); err != nil {
+			return true, ctrl.Result{}, err
+		}
+	}
+
+	if err := r.ensureRolledOutCondition(ctx, logger, dp, metav1.ConditionFalse,
+		DataPlaneConditionReasonRolloutRevisionRestored,
+		fmt.Sprintf("rolled back to revision with spec hash %q", restoredSpecHash),
+	); err != nil {
+		return true, ctrl.Result{}, err
+	}
+	if err := r.resetRollbackAnnotation(ctx, dp); err != nil {
+		return true, ctrl.Result{}, err
+	}
+
+	limit, err := revisionHistoryLimitFromDataPlane(dp)
+	if err != nil {
+		log.Info(logger, "invalid revision history limit, retaining all retired deployments", "error", err)
+		return true, ctrl.Result{}, nil
+	}
+	// Keep the Deployment which has just been rolled back from even if the
+	// limit is 0 so that the rollback can be undone.
+	if err := r.pruneRetiredDeployments(ctx, logger, dp, max(limit, 1)); err != nil {
+		return true, ctrl.Result{}, err
+	}
+	if err := r.ensureRevisionHistoryCondition(ctx, logger, dp); err != nil {
+		return true, ctrl.Result{}, err
+	}
+
+	log.Info(logger, "DataPlane rolled back to previous revision", "spec_hash", restoredSpecHash)
+	return true, ctrl.Result{}, nil
+}
+
+// cleanupPreviewForRollback removes the preview Deployment of the provided
+// DataPlane, which also drops the progressive traffic shifting state stored on it,
+// and resets the preview selector and the promotion analysis verdict so that
+// the next spec change starts a fresh rollout cycle.
+func (r *BlueGreenReconciler) cleanupPreviewForRollback(
+	ctx context.Context,
+	logger logr.Logger,
+	dp *operatorv1beta1.DataPlane,
+) error {
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		dp.Namespace,
+		dp.UID,
+		client.MatchingLabels{
+			"app":                                dp.Name,
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValuePreview,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed listing preview deployments: %w", err)
+	}
+	if len(deployments) > 0 {
+		log.Debug(logger, "removing preview Deployments after rollback")
+		if err := removeObjectSliceWithDataPlaneOwnedFinalizer(ctx, r.Client, deployments); err != nil {
+			return fmt.Errorf("failed removing preview deployments: %w", err)
+		}
+	}
+	r.promotionAnalyses.delete(dp.UID)
+
+	old := dp.DeepCopy()
+	if dp.Status.RolloutStatus.Deployment != nil {
+		dp.Status.RolloutStatus.Deployment.Selector = ""
+	}
+	dp.Status.RolloutStatus.Conditions = lo.Reject(dp.Status.RolloutStatus.Conditions, func(c metav1.Condition, _ int) bool {
+		return c.Type == string(DataPlaneConditionTypePromotionAnalysis)
+	})
+	if _, err := r.patchRolloutStatus(ctx, logger, old, dp); err != nil {
+		return fmt.Errorf("failed resetting preview rollout status: %w", err)
+	}
+	return nil
+}
+
+// revisionHistoryMessage returns the RevisionHistory condition message listing
+// the provided retired Deployments, which are expected to be sorted from the
+// most recently retired to the oldest one.
+func revisionHistoryMessage(retired []appsv1.Deployment) string {
+	if len(retired) == 0 {
+		return "no retained revisions"
+	}
+	entries := lo.Map(retired, func(d appsv1.Deployment, _ int) string {
+		return fmt.Sprintf("%s (spec hash %q, retired at %s)",
+			d.Name, d.Annotations[consts.AnnotationSpecHash], retiredAt(&d).UTC().Format(time.RFC3339),
+		)
+	})
+	return "retained revisions: " + strings.Join(entries, ", ")
+}
+
+// ensureRevisionHistoryCondition records the revisions retained for the provided
+// DataPlane in the RevisionHistory condition of its rollout status.
+// The condition is not added when no revision has ever been retained.
+func (r *BlueGreenReconciler) ensureRevisionHistoryCondition(
+	ctx context.Context,
+	logger logr.Logger,
+	dp *operatorv1beta1.DataPlane,
+) error {
+	retired, err := r.listRetiredDeployments(ctx, dp)
+	if err != nil {
+		return err
+	}
+
+	_, ok := k8sutils.GetCondition(DataPlaneConditionTypeRevisionHistory, dp.Status.RolloutStatus)
+	if len(retired) == 0 {
+		if !ok {
+			return nil
+		}
+		return r.ensureRolloutStatusCondition(ctx, logger, dp, DataPlaneConditionTypeRevisionHistory, metav1.ConditionFalse,
+			DataPlaneConditionReasonNoRevisionsRetained, revisionHistoryMessage(retired),
+		)
+	}
+	return r.ensureRolloutStatusCondition(ctx, logger, dp, DataPlaneConditionTypeRevisionHistory, metav1.ConditionTrue,
+		DataPlaneConditionReasonRevisionsRetained, revisionHistoryMessage(retired),
+	)
+}
+
+// ensureLiveServicesSelector ensures that the live Admin API and ingress Services
+// of the provided DataPlane select the Pods with the provided selector.
+func (r *BlueGreenReconciler) ensureLiveServicesSelector(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	selector string,
+) error {
+	services, err := k8sutils.ListServicesForOwner(
+		ctx,
+		r.Client,
+		dataplane.Namespace,
+		dataplane.UID,
+		client.MatchingLabels{
+			"app":                             dataplane.Name,
+			consts.DataPlaneServiceStateLabel: consts.DataPlaneStateLabelValueLive,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed listing live services: %w", err)
+	}
+
+	for i := range services {
+		svc := &services[i]
+		if svc.Spec.Type == corev1.ServiceTypeExternalName || svc.Spec.Selector[consts.OperatorLabelSelector] == selector {
+			continue
+		}
+		old := svc.DeepCopy()
+		if svc.Spec.Selector == nil {
+			svc.Spec.Selector = map[string]string{}
+		}
+		svc.Spec.Selector[consts.OperatorLabelSelector] = selector
+		if err := r.Patch(ctx, svc, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed patching live service %s/%s selector: %w", svc.Namespace, svc.Name, err)
+		}
+	}
+	return nil
+}
+
+// resetRollbackAnnotation removes the rollback annotation from the provided DataPlane.
+func (r *BlueGreenReconciler) resetRollbackAnnotation(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) error {
+	oldDp := dataplane.DeepCopy()
+	delete(dataplane.Annotations, consts.DataPlaneRollbackAnnotation)
+	if err := r.Patch(ctx, dataplane, client.MergeFrom(oldDp)); err != nil {
+		return fmt.Errorf("failed resetting rollback annotation: %w", err)
+	}
+	return nil
+}
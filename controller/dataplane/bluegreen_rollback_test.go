@@ -0,0 +1,316 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kcfgdataplane "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+func TestRevisionHistoryLimitFromDataPlane(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		expectedLimit int
+		expectedErr   bool
+	}{
+		{
+			name:          "no annotation",
+			expectedLimit: 0,
+		},
+		{
+			name: "valid limit",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutRevisionHistoryLimitAnnotation: "3",
+			},
+			expectedLimit: 3,
+		},
+		{
+			name: "negative limit",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutRevisionHistoryLimitAnnotation: "-1",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid limit",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutRevisionHistoryLimitAnnotation: "many",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			limit, err := revisionHistoryLimitFromDataPlane(dp)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLimit, limit)
+		})
+	}
+}
+
+func TestReduceLiveDeploymentsRetainsRevisions(t *testing.T) {
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+			UID:       types.UID("dp-uid"),
+			Annotations: map[string]string{
+				consts.DataPlaneRolloutRevisionHistoryLimitAnnotation: "1",
+			},
+		},
+	}
+
+	now := time.Now()
+	deployment := func(name, state string, created time.Time, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         dp.Namespace,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					"app":                                dp.Name,
+					consts.DataPlaneDeploymentStateLabel: state,
+				},
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+						Kind:       "DataPlane",
+						Name:       dp.Name,
+						UID:        dp.UID,
+					},
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: lo.ToPtr(int32(2)),
+			},
+		}
+	}
+
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(
+			dp,
+			deployment("oldest-retired", consts.DataPlaneStateLabelValueRetired, now.Add(-3*time.Hour), map[string]string{
+				consts.DataPlaneDeploymentRetiredAtAnnotation: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+			}),
+			deployment("old-live", consts.DataPlaneStateLabelValueLive, now.Add(-2*time.Hour), nil),
+			deployment("new-live", consts.DataPlaneStateLabelValueLive, now.Add(-time.Hour), nil),
+		).
+		Build()
+
+	r := BlueGreenReconciler{Client: fakeClient}
+	require.NoError(t, r.reduceLiveDeployments(context.Background(), logr.Discard(), dp))
+
+	var deployments appsv1.DeploymentList
+	require.NoError(t, fakeClient.List(context.Background(), &deployments, client.InNamespace(dp.Namespace)))
+	byName := lo.SliceToMap(deployments.Items, func(d appsv1.Deployment) (string, appsv1.Deployment) {
+		return d.Name, d
+	})
+
+	require.Len(t, byName, 2, "oldest retired deployment should be pruned")
+	assert.Equal(t, consts.DataPlaneStateLabelValueLive, byName["new-live"].Labels[consts.DataPlaneDeploymentStateLabel])
+
+	retired, ok := byName["old-live"]
+	require.True(t, ok, "previous live deployment should be retained")
+	assert.Equal(t, consts.DataPlaneStateLabelValueRetired, retired.Labels[consts.DataPlaneDeploymentStateLabel])
+	assert.Equal(t, int32(0), lo.FromPtr(retired.Spec.Replicas))
+	assert.NotEmpty(t, retired.Annotations[consts.DataPlaneDeploymentRetiredAtAnnotation])
+}
+
+func TestEnsureRolledBackToPreviousRevisionCleansUpPreview(t *testing.T) {
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dp",
+			Namespace:  "default",
+			UID:        types.UID("dp-uid"),
+			Generation: 2,
+			Annotations: map[string]string{
+				consts.DataPlaneRolloutRevisionHistoryLimitAnnotation: "1",
+				consts.DataPlaneRollbackAnnotation:                    "true",
+			},
+		},
+		Status: operatorv1beta1.DataPlaneStatus{
+			Selector: "live",
+			RolloutStatus: &operatorv1beta1.DataPlaneRolloutStatus{
+				Deployment: &operatorv1beta1.DataPlaneRolloutStatusDeployment{
+					Selector: "preview",
+				},
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(DataPlaneConditionTypePromotionAnalysis),
+						Status:             metav1.ConditionTrue,
+						Reason:             string(DataPlaneConditionReasonRolloutAnalysisPassed),
+						ObservedGeneration: 2,
+					},
+				},
+			},
+		},
+	}
+	dp.Spec.Deployment.Replicas = lo.ToPtr(int32(2))
+
+	ownerRefs := []metav1.OwnerReference{
+		{
+			APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+			Kind:       "DataPlane",
+			Name:       dp.Name,
+			UID:        dp.UID,
+		},
+	}
+	now := time.Now()
+	deployment := func(name, state, selector string, replicas int32, annotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: dp.Namespace,
+				Labels: map[string]string{
+					"app":                                dp.Name,
+					consts.DataPlaneDeploymentStateLabel: state,
+				},
+				Annotations:     annotations,
+				OwnerReferences: ownerRefs,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: lo.ToPtr(replicas),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						consts.OperatorLabelSelector: selector,
+					},
+				},
+			},
+			Status: appsv1.DeploymentStatus{
+				Replicas:          replicas,
+				AvailableReplicas: replicas,
+			},
+		}
+	}
+	// The live Deployment has been scaled down by progressive traffic shifting.
+	live := deployment("live", consts.DataPlaneStateLabelValueLive, "live", 1, map[string]string{
+		consts.AnnotationSpecHash: "live-hash",
+	})
+	preview := deployment("preview", consts.DataPlaneStateLabelValuePreview, "preview", 1, map[string]string{
+		consts.DataPlaneRolloutTrafficShiftingStateAnnotation: `{"generation":2,"step":0,"totalReplicas":2}`,
+	})
+	retired := deployment("retired", consts.DataPlaneStateLabelValueRetired, "retired", 2, map[string]string{
+		consts.AnnotationSpecHash:                     "retired-hash",
+		consts.DataPlaneDeploymentRetiredAtAnnotation: now.Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+	// The live ingress Service is shared between live and preview Pods.
+	ingress := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress",
+			Namespace: dp.Namespace,
+			Labels: map[string]string{
+				"app":                             dp.Name,
+				consts.DataPlaneServiceStateLabel: consts.DataPlaneStateLabelValueLive,
+			},
+			OwnerReferences: ownerRefs,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"app": dp.Name,
+			},
+		},
+	}
+
+	fakeClient := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dp, live, preview, retired, ingress).
+		WithStatusSubresource(dp).
+		Build()
+	r := BlueGreenReconciler{Client: fakeClient}
+
+	ctx := t.Context()
+	handled, _, err := r.ensureRolledBackToPreviousRevision(ctx, logr.Discard(), dp)
+	require.NoError(t, err)
+	require.True(t, handled)
+
+	var deployments appsv1.DeploymentList
+	require.NoError(t, fakeClient.List(ctx, &deployments, client.InNamespace(dp.Namespace)))
+	byName := lo.SliceToMap(deployments.Items, func(d appsv1.Deployment) (string, appsv1.Deployment) {
+		return d.Name, d
+	})
+	require.NotContains(t, byName, "preview", "preview deployment should be removed on rollback")
+	assert.Equal(t, consts.DataPlaneStateLabelValueLive, byName["retired"].Labels[consts.DataPlaneDeploymentStateLabel])
+	assert.Equal(t, consts.DataPlaneStateLabelValueRetired, byName["live"].Labels[consts.DataPlaneDeploymentStateLabel])
+	assert.Equal(t, int32(0), lo.FromPtr(byName["live"].Spec.Replicas))
+
+	var svc corev1.Service
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(ingress), &svc))
+	assert.Equal(t, "retired", svc.Spec.Selector[consts.OperatorLabelSelector])
+
+	var updated operatorv1beta1.DataPlane
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(dp), &updated))
+	assert.NotContains(t, updated.Annotations, consts.DataPlaneRollbackAnnotation)
+	assert.Equal(t, "retired", updated.Status.Selector)
+	require.NotNil(t, updated.Status.RolloutStatus)
+	assert.Empty(t, updated.Status.RolloutStatus.Deployment.Selector)
+
+	_, ok := k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, updated.Status.RolloutStatus)
+	assert.False(t, ok, "promotion analysis verdict should be reset on rollback")
+
+	c, ok := k8sutils.GetCondition(kcfgdataplane.DataPlaneConditionTypeRolledOut, updated.Status.RolloutStatus)
+	require.True(t, ok)
+	assert.Equal(t, string(DataPlaneConditionReasonRolloutRevisionRestored), c.Reason)
+
+	c, ok = k8sutils.GetCondition(DataPlaneConditionTypeRevisionHistory, updated.Status.RolloutStatus)
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionTrue, c.Status)
+	assert.Equal(t, string(DataPlaneConditionReasonRevisionsRetained), c.Reason)
+	assert.Contains(t, c.Message, `live (spec hash "live-hash"`)
+}
+
+func TestRevisionHistoryMessage(t *testing.T) {
+	retiredAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	d := func(name, hash string, at time.Time) appsv1.Deployment {
+		return appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					consts.AnnotationSpecHash:                     hash,
+					consts.DataPlaneDeploymentRetiredAtAnnotation: at.Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	assert.Equal(t, "no retained revisions", revisionHistoryMessage(nil))
+	assert.Equal(t,
+		`retained revisions: b (spec hash "hash-b", retired at 2025-01-02T03:04:05Z), a (spec hash "hash-a", retired at 2025-01-02T02:04:05Z)`,
+		revisionHistoryMessage([]appsv1.Deployment{
+			d("b", "hash-b", retiredAt),
+			d("a", "hash-a", retiredAt.Add(-time.Hour)),
+		}),
+	)
+}
@@ -0,0 +1,678 @@
+package dataplane
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/v2/api/common/consts"
+	kcfgdataplane "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/dataplane"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
+)
+
+// -----------------------------------------------------------------------------
+// DataPlaneBlueGreenReconciler - Progressive traffic shifting
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneConditionReasonRolloutTrafficShifting is the reason of the
+	// RolledOut condition set when the traffic is being progressively shifted
+	// from the live to the preview Deployment.
+	DataPlaneConditionReasonRolloutTrafficShifting kcfgconsts.ConditionReason = "TrafficShifting"
+
+	// DataPlaneConditionReasonRolloutPaused is the reason of the RolledOut
+	// condition set when progressive traffic shifting has been paused.
+	DataPlaneConditionReasonRolloutPaused kcfgconsts.ConditionReason = "Paused"
+
+	// DataPlaneConditionReasonRolloutRolledBack is the reason of the RolledOut
+	// condition set when progressive traffic shifting has been rolled back
+	// because the preview Pods lost readiness.
+	DataPlaneConditionReasonRolloutRolledBack kcfgconsts.ConditionReason = "RolledBack"
+)
+
+const (
+	defaultRolloutTrafficStepPause = time.Minute
+
+	// defaultPromotionAnalysisTrafficWeight is the percentage of traffic sent to
+	// the preview Pods during the promotion analysis when none of the configured
+	// traffic steps leaves traffic to the live Pods.
+	defaultPromotionAnalysisTrafficWeight int32 = 10
+)
+
+// trafficShiftingConfig is the configuration of progressive traffic shifting
+// parsed from DataPlane's annotations.
+type trafficShiftingConfig struct {
+	// Steps contains the increasing percentages of traffic sent to the preview Pods.
+	Steps  []int32
+	Pause  time.Duration
+	Paused bool
+	// Analysis is set when the promotion analysis is enabled. The analysis is
+	// performed at the analysis step, while both the live and preview Pods
+	// receive traffic.
+	Analysis bool
+}
+
+// analysisStep returns the index of the last step which still sends traffic
+// to the live Pods. The promotion analysis is performed at this step.
+func (cfg trafficShiftingConfig) analysisStep() int {
+	for i := len(cfg.Steps) - 1; i >= 0; i-- {
+		if cfg.Steps[i] < 100 {
+			return i
+		}
+	}
+	return -1
+}
+
+// trafficShiftingConfigFromDataPlane parses the progressive traffic shifting
+// configuration from DataPlane's annotations. It returns false when traffic
+// shifting is not enabled.
+// When the promotion analysis is enabled, traffic shifting is always enabled
+// so that the preview Pods receive traffic while being analyzed: an analysis
+// step is added in front of the configured steps when none of them leaves
+// traffic to the live Pods which the preview Pods are compared against.
+func trafficShiftingConfigFromDataPlane(dataplane *operatorv1beta1.DataPlane) (trafficShiftingConfig, bool, error) {
+	cfg := trafficShiftingConfig{
+		Pause: defaultRolloutTrafficStepPause,
+	}
+	// Invalid promotion analysis configuration is reported when the analysis is performed.
+	_, cfg.Analysis, _ = promotionAnalysisConfigFromDataPlane(dataplane)
+
+	annotations := dataplane.GetAnnotations()
+	steps := strings.TrimSpace(annotations[consts.DataPlaneRolloutTrafficStepsAnnotation])
+	if steps == "" && !cfg.Analysis {
+		return cfg, false, nil
+	}
+
+	if steps != "" {
+		for _, s := range strings.Split(steps, ",") {
+			step, err := strconv.ParseInt(strings.TrimSpace(s), 10, 32)
+			if err != nil {
+				return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlaneRolloutTrafficStepsAnnotation, steps, err)
+			}
+			if step <= 0 || step > 100 {
+				return cfg, false, fmt.Errorf("invalid %s annotation value %q: steps must be in (0, 100] range", consts.DataPlaneRolloutTrafficStepsAnnotation, steps)
+			}
+			if len(cfg.Steps) > 0 && int32(step) <= cfg.Steps[len(cfg.Steps)-1] {
+				return cfg, false, fmt.Errorf("invalid %s annotation value %q: steps must be increasing", consts.DataPlaneRolloutTrafficStepsAnnotation, steps)
+			}
+			cfg.Steps = append(cfg.Steps, int32(step))
+		}
+	}
+	if cfg.Analysis && cfg.analysisStep() < 0 {
+		cfg.Steps = append([]int32{defaultPromotionAnalysisTrafficWeight}, cfg.Steps...)
+	}
+
+	if v, ok := annotations[consts.DataPlaneRolloutTrafficStepPauseAnnotation]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlaneRolloutTrafficStepPauseAnnotation, v, err)
+		}
+		if d < 0 {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: must not be negative", consts.DataPlaneRolloutTrafficStepPauseAnnotation, v)
+		}
+		cfg.Pause = d
+	}
+
+	if v, ok := annotations[consts.DataPlaneRolloutPausedAnnotation]; ok {
+		paused, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, false, fmt.Errorf("invalid %s annotation value %q: %w", consts.DataPlaneRolloutPausedAnnotation, v, err)
+		}
+		cfg.Paused = paused
+	}
+
+	return cfg, true, nil
+}
+
+// trafficStepReplicas returns the number of preview and live replicas which
+// approximate the requested percentage of traffic sent to the preview Pods.
+// Both Deployments keep at least one replica unless the whole traffic is
+// sent to the preview Pods.
+func trafficStepReplicas(total int32, weight int32) (preview int32, live int32) {
+	if total < 1 {
+		total = 1
+	}
+	preview = int32(math.Ceil(float64(total) * float64(weight) / 100))
+	preview = max(1, min(preview, total))
+	live = total - preview
+	if weight < 100 && live < 1 {
+		live = 1
+	}
+	return preview, live
+}
+
+// trafficStepPreviewReplicasAlongsideLive returns the number of preview replicas
+// which, added to the provided number of live replicas, approximate the requested
+// percentage of traffic sent to the preview Pods. It's used when the live replicas
+// are managed by a HorizontalPodAutoscaler and can't be scaled down by the operator.
+func trafficStepPreviewReplicasAlongsideLive(live int32, weight int32) int32 {
+	live = max(live, 1)
+	if weight >= 100 {
+		return live
+	}
+	preview := int32(math.Ceil(float64(live) * float64(weight) / float64(100-weight)))
+	return max(1, preview)
+}
+
+// dataPlaneHasHorizontalScaling returns true when the DataPlane's replicas are
+// managed by a HorizontalPodAutoscaler.
+func dataPlaneHasHorizontalScaling(dataplane *operatorv1beta1.DataPlane) bool {
+	scaling := dataplane.Spec.Deployment.Scaling
+	return scaling != nil && scaling.HorizontalScaling != nil
+}
+
+// trafficShiftingState is the state of progressive traffic shifting stored
+// in the preview Deployment's annotation.
+type trafficShiftingState struct {
+	// Generation is the DataPlane generation this state applies to.
+	Generation int64 `json:"generation"`
+	// Step is the index of the current traffic step.
+	Step int `json:"step"`
+	// TotalReplicas is the number of live replicas at the start of traffic
+	// shifting which are distributed between live and preview Deployments.
+	// When the live Deployment is scaled by a HorizontalPodAutoscaler, it's
+	// the live Deployment's current number of replicas instead.
+	TotalReplicas int32 `json:"totalReplicas"`
+	// LiveScaledByHPA is set when the live Deployment is scaled by a
+	// HorizontalPodAutoscaler. The live Deployment is then left untouched
+	// and the preview Deployment is scaled alongside it instead.
+	LiveScaledByHPA bool `json:"liveScaledByHPA,omitempty"`
+	// StepReadyAt is the time at which the preview Pods of the current step
+	// became ready and started receiving traffic.
+	StepReadyAt *metav1.Time `json:"stepReadyAt,omitempty"`
+	// Finalized is set when all the steps have been completed and the preview
+	// Deployment has been scaled up to the total number of replicas.
+	Finalized bool `json:"finalized,omitempty"`
+	// RolledBack is set when traffic shifting has been rolled back.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// previewReplicas returns the number of replicas that the preview Deployment
+// should have in the current state.
+func (s trafficShiftingState) previewReplicas(cfg trafficShiftingConfig) int32 {
+	switch {
+	case s.RolledBack:
+		return 0
+	case s.Finalized:
+		return s.TotalReplicas
+	default:
+		return s.stepPreviewReplicas(cfg)
+	}
+}
+
+// stepPreviewReplicas returns the number of replicas that the preview Deployment
+// should have in the current traffic step.
+func (s trafficShiftingState) stepPreviewReplicas(cfg trafficShiftingConfig) int32 {
+	weight := cfg.Steps[min(s.Step, len(cfg.Steps)-1)]
+	if s.LiveScaledByHPA {
+		return trafficStepPreviewReplicasAlongsideLive(s.TotalReplicas, weight)
+	}
+	preview, _ := trafficStepReplicas(s.TotalReplicas, weight)
+	return preview
+}
+
+// lastStep returns the index of the last traffic step which is held before the
+// preview Deployment is scaled to its full size and promoted.
+// Preview Pods scaled alongside the live Pods managed by a HorizontalPodAutoscaler
+// can't receive the whole traffic, so a 100% step is replaced with the promotion
+// which moves the live Services over to the preview Pods. -1 is returned when
+// there's no step to hold.
+func (s trafficShiftingState) lastStep(cfg trafficShiftingConfig) int {
+	last := len(cfg.Steps) - 1
+	if s.LiveScaledByHPA && last >= 0 && cfg.Steps[last] >= 100 {
+		return last - 1
+	}
+	return last
+}
+
+// previewTrafficRatio returns the number of preview Pods and the total number
+// of Pods that share the traffic in the current traffic step.
+func (s trafficShiftingState) previewTrafficRatio(cfg trafficShiftingConfig) (preview int32, total int32) {
+	preview = s.stepPreviewReplicas(cfg)
+	if s.LiveScaledByHPA {
+		return preview, preview + max(s.TotalReplicas, 1)
+	}
+	_, live := trafficStepReplicas(s.TotalReplicas, cfg.Steps[min(s.Step, len(cfg.Steps)-1)])
+	return preview, preview + live
+}
+
+// trafficShiftingStateFromDeployment returns the traffic shifting state stored
+// on the provided Deployment. It returns false when there's no state or when
+// it doesn't apply to the provided DataPlane's generation.
+func trafficShiftingStateFromDeployment(
+	dataplane *operatorv1beta1.DataPlane,
+	deployment *appsv1.Deployment,
+) (trafficShiftingState, bool, error) {
+	var state trafficShiftingState
+	if deployment == nil {
+		return state, false, nil
+	}
+	raw, ok := deployment.GetAnnotations()[consts.DataPlaneRolloutTrafficShiftingStateAnnotation]
+	if !ok {
+		return state, false, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return state, false, fmt.Errorf("failed parsing %s annotation on Deployment %s/%s: %w",
+			consts.DataPlaneRolloutTrafficShiftingStateAnnotation, deployment.Namespace, deployment.Name, err)
+	}
+	if state.Generation != dataplane.Generation {
+		return trafficShiftingState{}, false, nil
+	}
+	return state, true, nil
+}
+
+// trafficShiftingReplicasDeploymentOpt returns a DeploymentOpt which sets the
+// preview Deployment's replicas according to the traffic shifting state so
+// that the Deployment builder doesn't revert them.
+func trafficShiftingReplicasDeploymentOpt(state trafficShiftingState, cfg trafficShiftingConfig) k8sresources.DeploymentOpt {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Replicas = lo.ToPtr(state.previewReplicas(cfg))
+	}
+}
+
+// previewDeploymentTrafficShiftingOpts returns the DeploymentOpts which should
+// be applied to the preview Deployment when progressive traffic shifting is
+// in progress.
+func (r *BlueGreenReconciler) previewDeploymentTrafficShiftingOpts(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]k8sresources.DeploymentOpt, error) {
+	// Invalid configuration is reported when traffic shifting is performed.
+	cfg, enabled, _ := trafficShiftingConfigFromDataPlane(dataplane)
+	if !enabled {
+		return nil, nil
+	}
+
+	deployments, err := k8sutils.ListDeploymentsForOwner(
+		ctx,
+		r.Client,
+		dataplane.Namespace,
+		dataplane.UID,
+		client.MatchingLabels{
+			"app":                                dataplane.Name,
+			consts.DataPlaneDeploymentStateLabel: consts.DataPlaneStateLabelValuePreview,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing preview Deployments: %w", err)
+	}
+	if len(deployments) != 1 {
+		return nil, nil
+	}
+
+	state, ok, err := trafficShiftingStateFromDeployment(dataplane, &deployments[0])
+	if err != nil || !ok {
+		return nil, err
+	}
+	return []k8sresources.DeploymentOpt{trafficShiftingReplicasDeploymentOpt(state, cfg)}, nil
+}
+
+// ensureTrafficShiftingRolledBack rolls back progressive traffic shifting when
+// the preview Pods which have already been receiving traffic lose readiness.
+// It returns true when the traffic shifting has been (or has previously been)
+// rolled back for the current DataPlane generation.
+func (r *BlueGreenReconciler) ensureTrafficShiftingRolledBack(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	preview *appsv1.Deployment,
+) (bool, error) {
+	// Invalid configuration is reported when traffic shifting is performed.
+	cfg, enabled, _ := trafficShiftingConfigFromDataPlane(dataplane)
+	if !enabled {
+		return false, nil
+	}
+	state, ok, err := trafficShiftingStateFromDeployment(dataplane, preview)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if state.RolledBack {
+		// Traffic shifting rolled back because of the failed promotion analysis
+		// keeps reporting the analysis verdict.
+		if c, failed := promotionAnalysisFailed(dataplane); failed {
+			return true, r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutAnalysisFailed, c.Message)
+		}
+		return true, r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutRolledBack,
+			"preview Pods lost readiness while receiving traffic")
+	}
+
+	// Preview Pods are not serving traffic in the current step yet.
+	if state.StepReadyAt == nil {
+		return false, nil
+	}
+	stepReplicas := state.stepPreviewReplicas(cfg)
+	if preview.Status.ReadyReplicas >= stepReplicas {
+		return false, nil
+	}
+
+	log.Info(logger, "preview Pods lost readiness, rolling back traffic shifting",
+		"ready_replicas", preview.Status.ReadyReplicas, "expected_replicas", stepReplicas,
+	)
+
+	if err := r.rollBackTrafficShifting(ctx, dataplane, preview, state, cfg); err != nil {
+		return false, err
+	}
+
+	return true, r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutRolledBack,
+		"preview Pods lost readiness while receiving traffic")
+}
+
+// rollBackTrafficShifting sends the whole traffic back to the live Pods, restores
+// the live Deployment's replicas and scales the preview Deployment down.
+func (r *BlueGreenReconciler) rollBackTrafficShifting(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	preview *appsv1.Deployment,
+	state trafficShiftingState,
+	cfg trafficShiftingConfig,
+) error {
+	if err := r.ensureLiveIngressServiceSelectorShared(ctx, dataplane, false); err != nil {
+		return err
+	}
+	if !state.LiveScaledByHPA {
+		if err := r.ensureLiveDeploymentReplicas(ctx, dataplane, state.TotalReplicas); err != nil {
+			return err
+		}
+	}
+
+	state.RolledBack = true
+	state.StepReadyAt = nil
+	return r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg)
+}
+
+// ensureTrafficShifted progressively shifts the traffic from the live to the
+// preview Pods. It assumes that the preview Deployment is ready.
+// It returns true when traffic shifting is either not enabled or has been
+// completed and the promotion can proceed. Otherwise it returns false and
+// a ctrl.Result which should be returned from the reconciliation.
+func (r *BlueGreenReconciler) ensureTrafficShifted(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	preview *appsv1.Deployment,
+) (bool, ctrl.Result, error) {
+	cfg, enabled, err := trafficShiftingConfigFromDataPlane(dataplane)
+	if err != nil {
+		cErr := r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, kcfgdataplane.DataPlaneConditionReasonRolloutFailed, err.Error())
+		return false, ctrl.Result{}, cErr
+	}
+	if !enabled {
+		return true, ctrl.Result{}, nil
+	}
+
+	// Promotion has already started: live Services point to the preview Pods.
+	if dataplane.Status.Selector == dataplane.Status.RolloutStatus.Deployment.Selector {
+		return true, ctrl.Result{}, nil
+	}
+
+	state, ok, err := trafficShiftingStateFromDeployment(dataplane, preview)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+	if !ok {
+		total, err := r.desiredLiveReplicas(ctx, dataplane)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		state = trafficShiftingState{
+			Generation:      dataplane.Generation,
+			TotalReplicas:   total,
+			LiveScaledByHPA: dataPlaneHasHorizontalScaling(dataplane),
+		}
+		state.Finalized = state.lastStep(cfg) < 0
+		log.Debug(logger, "starting progressive traffic shifting", "steps", cfg.Steps, "total_replicas", state.TotalReplicas)
+		if err := r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{}, r.ensureTrafficShiftingCondition(ctx, logger, dataplane, state, cfg)
+	}
+
+	// The live Deployment scaled by a HorizontalPodAutoscaler can be scaled at any
+	// time: keep the preview Deployment proportional to its current replicas.
+	// The current step is held again once the rescaled preview Pods are ready.
+	if state.LiveScaledByHPA {
+		live, err := r.liveDeploymentReplicas(ctx, dataplane)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if live != state.TotalReplicas {
+			log.Debug(logger, "live Deployment has been scaled, rescaling preview Deployment",
+				"live_replicas", live, "previous_live_replicas", state.TotalReplicas)
+			state.TotalReplicas = live
+			state.StepReadyAt = nil
+			if err := r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg); err != nil {
+				return false, ctrl.Result{}, err
+			}
+			return false, ctrl.Result{}, r.ensureTrafficShiftingCondition(ctx, logger, dataplane, state, cfg)
+		}
+	}
+
+	// Wait for the preview Deployment to be scaled to the expected number of replicas.
+	// Readiness of the preview Pods is verified before this function is called.
+	if lo.FromPtrOr(preview.Spec.Replicas, 1) != state.previewReplicas(cfg) {
+		if err := r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{}, nil
+	}
+
+	if state.Finalized {
+		return true, ctrl.Result{}, nil
+	}
+
+	// Preview Pods of the current step are ready: send them their share of traffic.
+	if err := r.ensureLiveIngressServiceSelectorShared(ctx, dataplane, true); err != nil {
+		return false, ctrl.Result{}, err
+	}
+	// The live Deployment scaled by a HorizontalPodAutoscaler keeps its replicas:
+	// the preview Deployment has been scaled alongside it instead.
+	if !state.LiveScaledByHPA {
+		_, liveReplicas := trafficStepReplicas(state.TotalReplicas, cfg.Steps[state.Step])
+		if err := r.ensureLiveDeploymentReplicas(ctx, dataplane, liveReplicas); err != nil {
+			return false, ctrl.Result{}, err
+		}
+	}
+
+	if state.StepReadyAt == nil {
+		state.StepReadyAt = lo.ToPtr(metav1.Now())
+		if err := r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		err := r.ensureTrafficShiftingCondition(ctx, logger, dataplane, state, cfg)
+		return false, ctrl.Result{RequeueAfter: cfg.Pause}, err
+	}
+
+	if cfg.Paused {
+		err := r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutPaused,
+			fmt.Sprintf("paused at traffic step %d/%d (%d%%)", state.Step+1, len(cfg.Steps), cfg.Steps[state.Step]))
+		return false, ctrl.Result{}, err
+	}
+
+	if remaining := cfg.Pause - time.Since(state.StepReadyAt.Time); remaining > 0 {
+		err := r.ensureTrafficShiftingCondition(ctx, logger, dataplane, state, cfg)
+		return false, ctrl.Result{RequeueAfter: remaining}, err
+	}
+
+	// Analyze the preview Pods while they share the traffic with the live ones.
+	// A failed analysis rolls the traffic back to the live Pods.
+	if cfg.Analysis && state.Step == cfg.analysisStep() {
+		passed, res, err := r.ensurePromotionAnalysisPassed(ctx, logger, dataplane)
+		if err != nil {
+			return false, ctrl.Result{}, err
+		}
+		if !passed {
+			if _, failed := promotionAnalysisFailed(dataplane); failed {
+				log.Info(logger, "rolling back traffic shifting after failed promotion analysis")
+				return false, ctrl.Result{}, r.rollBackTrafficShifting(ctx, dataplane, preview, state, cfg)
+			}
+			return false, res, nil
+		}
+	}
+
+	if state.Step < state.lastStep(cfg) {
+		state.Step++
+		log.Debug(logger, "advancing traffic shifting step", "step", state.Step+1, "weight", cfg.Steps[state.Step])
+	} else {
+		// All steps are done: scale the preview Deployment to the full size before promotion.
+		state.Finalized = true
+		log.Debug(logger, "traffic shifting steps completed")
+	}
+	state.StepReadyAt = nil
+	if err := r.patchPreviewDeploymentTrafficShifting(ctx, preview, state, cfg); err != nil {
+		return false, ctrl.Result{}, err
+	}
+	return false, ctrl.Result{}, r.ensureTrafficShiftingCondition(ctx, logger, dataplane, state, cfg)
+}
+
+// desiredLiveReplicas returns the number of replicas that the live Pods of the
+// DataPlane should have, e.g. the number of replicas which are distributed between
+// the live and preview Deployments during progressive traffic shifting.
+// The DataPlane's replicas are used when set. Otherwise (e.g. when horizontal
+// scaling is used) the live Deployment's current replicas are used.
+func (r *BlueGreenReconciler) desiredLiveReplicas(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) (int32, error) {
+	if replicas := dataplane.Spec.Deployment.Replicas; replicas != nil {
+		return max(*replicas, 1), nil
+	}
+	return r.liveDeploymentReplicas(ctx, dataplane)
+}
+
+// liveDeploymentReplicas returns the current number of replicas of the DataPlane's
+// live Deployment.
+func (r *BlueGreenReconciler) liveDeploymentReplicas(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+) (int32, error) {
+	liveDeployments, err := listDataPlaneLiveDeployments(ctx, r.Client, dataplane)
+	if err != nil {
+		return 0, fmt.Errorf("failed listing live Deployments: %w", err)
+	}
+	if len(liveDeployments) != 1 {
+		return 0, fmt.Errorf("expected exactly 1 live Deployment, got %d", len(liveDeployments))
+	}
+	return max(lo.FromPtrOr(liveDeployments[0].Spec.Replicas, 1), 1), nil
+}
+
+func (r *BlueGreenReconciler) ensureTrafficShiftingCondition(
+	ctx context.Context,
+	logger logr.Logger,
+	dataplane *operatorv1beta1.DataPlane,
+	state trafficShiftingState,
+	cfg trafficShiftingConfig,
+) error {
+	// Report the actual share of the preview Pods which can differ from the step's
+	// weight due to rounding or when preview Pods are scaled alongside the live ones.
+	previewPods, totalPods := state.previewTrafficRatio(cfg)
+	msg := fmt.Sprintf("traffic step %d/%d (%d%%): %d of %d Pods are preview Pods",
+		state.Step+1, len(cfg.Steps), cfg.Steps[state.Step], previewPods, totalPods)
+	if state.Finalized {
+		msg = "traffic steps completed, scaling preview Deployment before promotion"
+	}
+	return r.ensureRolledOutCondition(ctx, logger, dataplane, metav1.ConditionFalse, DataPlaneConditionReasonRolloutTrafficShifting, msg)
+}
+
+// patchPreviewDeploymentTrafficShifting stores the traffic shifting state on
+// the preview Deployment and scales it accordingly.
+func (r *BlueGreenReconciler) patchPreviewDeploymentTrafficShifting(
+	ctx context.Context,
+	preview *appsv1.Deployment,
+	state trafficShiftingState,
+	cfg trafficShiftingConfig,
+) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed marshaling traffic shifting state: %w", err)
+	}
+
+	old := preview.DeepCopy()
+	if preview.Annotations == nil {
+		preview.Annotations = map[string]string{}
+	}
+	preview.Annotations[consts.DataPlaneRolloutTrafficShiftingStateAnnotation] = string(b)
+	preview.Spec.Replicas = lo.ToPtr(state.previewReplicas(cfg))
+	if err := r.Patch(ctx, preview, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed patching preview Deployment %s/%s: %w", preview.Namespace, preview.Name, err)
+	}
+	return nil
+}
+
+// ensureLiveIngressServiceSelectorShared sets the live ingress Service selector
+// so that it either selects both live and preview Pods (shared set to true)
+// or only the live ones.
+func (r *BlueGreenReconciler) ensureLiveIngressServiceSelectorShared(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	shared bool,
+) error {
+	services, err := listDataPlaneLiveServices(ctx, r.Client, dataplane)
+	if err != nil {
+		return fmt.Errorf("failed listing live ingress Services: %w", err)
+	}
+
+	for i := range services {
+		svc := &services[i]
+		current, ok := svc.Spec.Selector[consts.OperatorLabelSelector]
+		if (shared && !ok) || (!shared && current == dataplane.Status.Selector) {
+			continue
+		}
+
+		old := svc.DeepCopy()
+		if shared {
+			delete(svc.Spec.Selector, consts.OperatorLabelSelector)
+		} else {
+			if svc.Spec.Selector == nil {
+				svc.Spec.Selector = map[string]string{}
+			}
+			svc.Spec.Selector[consts.OperatorLabelSelector] = dataplane.Status.Selector
+		}
+		if err := r.Patch(ctx, svc, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed patching live ingress Service %s/%s selector: %w", svc.Namespace, svc.Name, err)
+		}
+	}
+	return nil
+}
+
+// ensureLiveDeploymentReplicas scales the live Deployments to the provided
+// number of replicas.
+func (r *BlueGreenReconciler) ensureLiveDeploymentReplicas(
+	ctx context.Context,
+	dataplane *operatorv1beta1.DataPlane,
+	replicas int32,
+) error {
+	deployments, err := listDataPlaneLiveDeployments(ctx, r.Client, dataplane)
+	if err != nil {
+		return fmt.Errorf("failed listing live Deployments: %w", err)
+	}
+
+	for i := range deployments {
+		d := &deployments[i]
+		if lo.FromPtrOr(d.Spec.Replicas, 1) == replicas {
+			continue
+		}
+		old := d.DeepCopy()
+		d.Spec.Replicas = lo.ToPtr(replicas)
+		if err := r.Patch(ctx, d, client.MergeFrom(old)); err != nil {
+			return fmt.Errorf("failed scaling live Deployment %s/%s: %w", d.Namespace, d.Name, err)
+		}
+	}
+	return nil
+}
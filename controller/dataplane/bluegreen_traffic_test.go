@@ -0,0 +1,529 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+func TestTrafficShiftingConfigFromDataPlane(t *testing.T) {
+	testCases := []struct {
+		name            string
+		annotations     map[string]string
+		expectedCfg     trafficShiftingConfig
+		expectedEnabled bool
+		expectedErr     bool
+	}{
+		{
+			name:        "no annotations",
+			expectedCfg: trafficShiftingConfig{Pause: defaultRolloutTrafficStepPause},
+		},
+		{
+			name: "steps with default pause",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "10, 25,50,100",
+			},
+			expectedCfg: trafficShiftingConfig{
+				Steps: []int32{10, 25, 50, 100},
+				Pause: defaultRolloutTrafficStepPause,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "steps with custom pause, paused",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation:     "20,50",
+				consts.DataPlaneRolloutTrafficStepPauseAnnotation: "30s",
+				consts.DataPlaneRolloutPausedAnnotation:           "true",
+			},
+			expectedCfg: trafficShiftingConfig{
+				Steps:  []int32{20, 50},
+				Pause:  30 * time.Second,
+				Paused: true,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "promotion analysis without steps",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation: "true",
+			},
+			expectedCfg: trafficShiftingConfig{
+				Steps:    []int32{defaultPromotionAnalysisTrafficWeight},
+				Pause:    defaultRolloutTrafficStepPause,
+				Analysis: true,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "promotion analysis with a single 100% step",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:   "true",
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "100",
+			},
+			expectedCfg: trafficShiftingConfig{
+				Steps:    []int32{defaultPromotionAnalysisTrafficWeight, 100},
+				Pause:    defaultRolloutTrafficStepPause,
+				Analysis: true,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "promotion analysis with intermediate steps",
+			annotations: map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:   "true",
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "25,50,100",
+			},
+			expectedCfg: trafficShiftingConfig{
+				Steps:    []int32{25, 50, 100},
+				Pause:    defaultRolloutTrafficStepPause,
+				Analysis: true,
+			},
+			expectedEnabled: true,
+		},
+		{
+			name: "steps not increasing",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "50,25",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "step out of range",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "10,150",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid step",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation: "10,abc",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid pause",
+			annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation:     "10",
+				consts.DataPlaneRolloutTrafficStepPauseAnnotation: "soon",
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+			cfg, enabled, err := trafficShiftingConfigFromDataPlane(dp)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEnabled, enabled)
+			assert.Equal(t, tc.expectedCfg, cfg)
+		})
+	}
+}
+
+func TestTrafficStepReplicas(t *testing.T) {
+	testCases := []struct {
+		total           int32
+		weight          int32
+		expectedPreview int32
+		expectedLive    int32
+	}{
+		{total: 10, weight: 10, expectedPreview: 1, expectedLive: 9},
+		{total: 10, weight: 25, expectedPreview: 3, expectedLive: 7},
+		{total: 10, weight: 50, expectedPreview: 5, expectedLive: 5},
+		{total: 10, weight: 100, expectedPreview: 10, expectedLive: 0},
+		{total: 4, weight: 99, expectedPreview: 4, expectedLive: 1},
+		{total: 1, weight: 10, expectedPreview: 1, expectedLive: 1},
+		{total: 0, weight: 100, expectedPreview: 1, expectedLive: 0},
+	}
+
+	for _, tc := range testCases {
+		preview, live := trafficStepReplicas(tc.total, tc.weight)
+		assert.Equal(t, tc.expectedPreview, preview, "total: %d, weight: %d", tc.total, tc.weight)
+		assert.Equal(t, tc.expectedLive, live, "total: %d, weight: %d", tc.total, tc.weight)
+	}
+}
+
+func TestTrafficStepPreviewReplicasAlongsideLive(t *testing.T) {
+	testCases := []struct {
+		live            int32
+		weight          int32
+		expectedPreview int32
+	}{
+		{live: 10, weight: 10, expectedPreview: 2},
+		{live: 10, weight: 50, expectedPreview: 10},
+		{live: 9, weight: 25, expectedPreview: 3},
+		{live: 1, weight: 1, expectedPreview: 1},
+		{live: 4, weight: 100, expectedPreview: 4},
+		{live: 0, weight: 50, expectedPreview: 1},
+	}
+
+	for _, tc := range testCases {
+		preview := trafficStepPreviewReplicasAlongsideLive(tc.live, tc.weight)
+		assert.Equal(t, tc.expectedPreview, preview, "live: %d, weight: %d", tc.live, tc.weight)
+	}
+}
+
+func TestTrafficShiftingState(t *testing.T) {
+	cfg := trafficShiftingConfig{Steps: []int32{10, 50}}
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dp",
+			Namespace:  "default",
+			Generation: 2,
+		},
+	}
+
+	t.Run("preview replicas", func(t *testing.T) {
+		state := trafficShiftingState{TotalReplicas: 10}
+		assert.Equal(t, int32(1), state.previewReplicas(cfg))
+		state.Step = 1
+		assert.Equal(t, int32(5), state.previewReplicas(cfg))
+		state.Finalized = true
+		assert.Equal(t, int32(10), state.previewReplicas(cfg))
+		state.RolledBack = true
+		assert.Equal(t, int32(0), state.previewReplicas(cfg))
+	})
+
+	t.Run("preview replicas alongside live scaled by HPA", func(t *testing.T) {
+		state := trafficShiftingState{TotalReplicas: 10, LiveScaledByHPA: true}
+		assert.Equal(t, int32(2), state.previewReplicas(cfg))
+		state.Step = 1
+		assert.Equal(t, int32(10), state.previewReplicas(cfg))
+		state.Finalized = true
+		assert.Equal(t, int32(10), state.previewReplicas(cfg))
+	})
+
+	t.Run("last step", func(t *testing.T) {
+		cfg := trafficShiftingConfig{Steps: []int32{10, 100}}
+		assert.Equal(t, 1, trafficShiftingState{}.lastStep(cfg))
+		assert.Equal(t, 0, trafficShiftingState{LiveScaledByHPA: true}.lastStep(cfg))
+		assert.Equal(t, -1, trafficShiftingState{LiveScaledByHPA: true}.lastStep(trafficShiftingConfig{Steps: []int32{100}}))
+	})
+
+	t.Run("preview traffic ratio", func(t *testing.T) {
+		preview, total := trafficShiftingState{TotalReplicas: 10, Step: 1}.previewTrafficRatio(cfg)
+		assert.Equal(t, int32(5), preview)
+		assert.Equal(t, int32(10), total)
+		preview, total = trafficShiftingState{TotalReplicas: 10, LiveScaledByHPA: true}.previewTrafficRatio(cfg)
+		assert.Equal(t, int32(2), preview)
+		assert.Equal(t, int32(12), total)
+	})
+
+	t.Run("no state on Deployment", func(t *testing.T) {
+		_, ok, err := trafficShiftingStateFromDeployment(dp, &appsv1.Deployment{})
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("state for current generation", func(t *testing.T) {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					consts.DataPlaneRolloutTrafficShiftingStateAnnotation: `{"generation":2,"step":1,"totalReplicas":4}`,
+				},
+			},
+		}
+		state, ok, err := trafficShiftingStateFromDeployment(dp, d)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, trafficShiftingState{Generation: 2, Step: 1, TotalReplicas: 4}, state)
+	})
+
+	t.Run("state for outdated generation", func(t *testing.T) {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					consts.DataPlaneRolloutTrafficShiftingStateAnnotation: `{"generation":1,"step":1,"totalReplicas":4}`,
+				},
+			},
+		}
+		_, ok, err := trafficShiftingStateFromDeployment(dp, d)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("malformed state", func(t *testing.T) {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					consts.DataPlaneRolloutTrafficShiftingStateAnnotation: `{`,
+				},
+			},
+		}
+		_, _, err := trafficShiftingStateFromDeployment(dp, d)
+		require.Error(t, err)
+	})
+}
+
+func TestEnsureTrafficShiftedLeavesLiveDeploymentScaledByHPA(t *testing.T) {
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dp",
+			Namespace:  "default",
+			UID:        types.UID("dp-uid"),
+			Generation: 1,
+			Annotations: map[string]string{
+				consts.DataPlaneRolloutTrafficStepsAnnotation:     "50,100",
+				consts.DataPlaneRolloutTrafficStepPauseAnnotation: "0s",
+			},
+		},
+		Status: operatorv1beta1.DataPlaneStatus{
+			Selector: "live",
+			RolloutStatus: &operatorv1beta1.DataPlaneRolloutStatus{
+				Deployment: &operatorv1beta1.DataPlaneRolloutStatusDeployment{
+					Selector: "preview",
+				},
+			},
+		},
+	}
+	dp.Spec.Deployment.Scaling = &operatorv1beta1.Scaling{
+		HorizontalScaling: &operatorv1beta1.HorizontalScaling{
+			MaxReplicas: 10,
+		},
+	}
+	deployment := func(name, state string, replicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: dp.Namespace,
+				Labels: map[string]string{
+					"app":                                dp.Name,
+					consts.DataPlaneDeploymentStateLabel: state,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+						Kind:       "DataPlane",
+						Name:       dp.Name,
+						UID:        dp.UID,
+					},
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: lo.ToPtr(replicas),
+			},
+		}
+	}
+	live := deployment("live", consts.DataPlaneStateLabelValueLive, 4)
+	preview := deployment("preview", consts.DataPlaneStateLabelValuePreview, 1)
+
+	fakeClient := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(dp, live, preview).
+		WithStatusSubresource(dp).
+		Build()
+	r := BlueGreenReconciler{Client: fakeClient}
+
+	ctx := t.Context()
+	getDeployment := func(name string) *appsv1.Deployment {
+		var d appsv1.Deployment
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: dp.Namespace, Name: name}, &d))
+		return &d
+	}
+
+	// Start traffic shifting: the preview is scaled alongside the live Deployment.
+	shifted, _, err := r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+	require.NoError(t, err)
+	require.False(t, shifted)
+	current := getDeployment("preview")
+	state, ok, err := trafficShiftingStateFromDeployment(dp, current)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.True(t, state.LiveScaledByHPA)
+	assert.Equal(t, int32(4), lo.FromPtr(current.Spec.Replicas))
+
+	// The preview Pods of the first step are ready: the traffic is shared without scaling the live Deployment down.
+	_, _, err = r.ensureTrafficShifted(ctx, logr.Discard(), dp, current)
+	require.NoError(t, err)
+	liveDeployment := getDeployment("live")
+	assert.Equal(t, int32(4), lo.FromPtr(liveDeployment.Spec.Replicas))
+
+	// The HorizontalPodAutoscaler scales the live Deployment up: the preview is rescaled and the step is held again.
+	liveDeployment.Spec.Replicas = lo.ToPtr(int32(8))
+	require.NoError(t, fakeClient.Update(ctx, liveDeployment))
+	_, _, err = r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+	require.NoError(t, err)
+	current = getDeployment("preview")
+	state, ok, err = trafficShiftingStateFromDeployment(dp, current)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int32(8), state.TotalReplicas)
+	assert.Nil(t, state.StepReadyAt)
+	assert.Equal(t, int32(8), lo.FromPtr(current.Spec.Replicas))
+
+	// The 100% step is not held alongside the live Pods: traffic shifting completes and the promotion cuts over.
+	_, _, err = r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+	require.NoError(t, err)
+	_, _, err = r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+	require.NoError(t, err)
+	state, ok, err = trafficShiftingStateFromDeployment(dp, getDeployment("preview"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, 0, state.Step)
+	assert.True(t, state.Finalized)
+	shifted, _, err = r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+	require.NoError(t, err)
+	assert.True(t, shifted)
+}
+
+func TestEnsureTrafficShiftedRunsPromotionAnalysis(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		steps                  string
+		expectedStepsAfterPass int
+		expectedFinalized      bool
+	}{
+		{
+			name:              "no traffic steps",
+			expectedFinalized: true,
+		},
+		{
+			name:                   "final 100% step",
+			steps:                  "100",
+			expectedStepsAfterPass: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations := map[string]string{
+				consts.DataPlanePromotionAnalysisAnnotation:            "true",
+				consts.DataPlanePromotionAnalysisWindowAnnotation:      "1ns",
+				consts.DataPlanePromotionAnalysisMinRequestsAnnotation: "10",
+				consts.DataPlaneRolloutTrafficStepPauseAnnotation:      "0s",
+			}
+			if tc.steps != "" {
+				annotations[consts.DataPlaneRolloutTrafficStepsAnnotation] = tc.steps
+			}
+			dp := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "dp",
+					Namespace:   "default",
+					UID:         types.UID("dp-uid"),
+					Generation:  1,
+					Annotations: annotations,
+				},
+				Status: operatorv1beta1.DataPlaneStatus{
+					Selector: "live",
+					RolloutStatus: &operatorv1beta1.DataPlaneRolloutStatus{
+						Deployment: &operatorv1beta1.DataPlaneRolloutStatusDeployment{
+							Selector: "preview",
+						},
+					},
+				},
+			}
+			dp.Spec.Deployment.Replicas = lo.ToPtr(int32(4))
+			deployment := func(name, state string, replicas int32) *appsv1.Deployment {
+				return &appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: dp.Namespace,
+						Labels: map[string]string{
+							"app":                                dp.Name,
+							consts.DataPlaneDeploymentStateLabel: state,
+						},
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+								Kind:       "DataPlane",
+								Name:       dp.Name,
+								UID:        dp.UID,
+							},
+						},
+					},
+					Spec: appsv1.DeploymentSpec{
+						Replicas: lo.ToPtr(replicas),
+					},
+				}
+			}
+
+			fakeClient := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Scheme).
+				WithObjects(
+					dp,
+					deployment("live", consts.DataPlaneStateLabelValueLive, 4),
+					deployment("preview", consts.DataPlaneStateLabelValuePreview, 4),
+				).
+				WithStatusSubresource(dp).
+				Build()
+
+			// Every scrape reports 50 more requests served by both live and preview Pods.
+			var scrapes float64
+			r := BlueGreenReconciler{
+				Client: fakeClient,
+				RolloutMetricsProvider: rolloutMetricsProviderFunc(func(context.Context, *operatorv1beta1.DataPlane) (metricsscraper.RolloutMetricsSummary, metricsscraper.RolloutMetricsSummary, error) {
+					scrapes++
+					summary := metricsscraper.RolloutMetricsSummary{Requests: 50 * scrapes}
+					return summary, summary, nil
+				}),
+			}
+
+			ctx := t.Context()
+			getDeployment := func(name string) *appsv1.Deployment {
+				var d appsv1.Deployment
+				require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: dp.Namespace, Name: name}, &d))
+				return &d
+			}
+			shift := func() {
+				t.Helper()
+				shifted, _, err := r.ensureTrafficShifted(ctx, logr.Discard(), dp, getDeployment("preview"))
+				require.NoError(t, err)
+				require.False(t, shifted)
+			}
+
+			// Start traffic shifting at the analysis step and send the preview Pods their share of traffic.
+			shift()
+			shift()
+			live := getDeployment("live")
+			assert.Equal(t, int32(3), lo.FromPtr(live.Spec.Replicas), "live Pods keep serving traffic during the analysis")
+			assert.Equal(t, int32(1), lo.FromPtr(getDeployment("preview").Spec.Replicas))
+
+			// The analysis records the baselines while the preview Pods receive traffic.
+			shift()
+			analysis, ok := k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, dp.Status.RolloutStatus)
+			require.True(t, ok)
+			assert.Equal(t, string(DataPlaneConditionReasonRolloutAnalysisInProgress), analysis.Reason)
+
+			// The analysis passes and traffic shifting proceeds towards the promotion.
+			shift()
+			analysis, ok = k8sutils.GetCondition(DataPlaneConditionTypePromotionAnalysis, dp.Status.RolloutStatus)
+			require.True(t, ok)
+			assert.Equal(t, string(DataPlaneConditionReasonRolloutAnalysisPassed), analysis.Reason)
+			state, ok, err := trafficShiftingStateFromDeployment(dp, getDeployment("preview"))
+			require.NoError(t, err)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedStepsAfterPass, state.Step)
+			assert.Equal(t, tc.expectedFinalized, state.Finalized)
+		})
+	}
+}
@@ -2,12 +2,14 @@ package dataplane
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,6 +43,12 @@ type Reconciler struct {
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
+	// ClusterCertificateRenewalConfig configures the lifetime and renewal of the
+	// DataPlane's Admin API certificate.
+	ClusterCertificateRenewalConfig secrets.CertificateRenewalConfig
+	// ClusterCertificateIssuer configures the issuer of the DataPlane's Admin API certificate.
+	// The cluster CA is used when it's not set.
+	ClusterCertificateIssuer secrets.IssuerConfig
 	SecretLabelSelector      string
 	// ConfigMapLabelSelector is the label selector configured at the oprator level.
 	// When not empty, it is used as the config map label selector of all reconcilers.
@@ -163,7 +171,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		},
 		r.SecretLabelSelector,
 		r.ClusterCAKeyConfig,
+		r.ClusterCertificateIssuer,
+		r.ClusterCertificateRenewalConfig,
+		r.eventRecorder,
 	)
+	if errors.Is(err, secrets.ErrCertificateNotReady) {
+		log.Debug(logger, "waiting for mTLS certificate to be issued")
+		return ctrl.Result{RequeueAfter: secrets.CertificateNotReadyRequeueAfter}, nil
+	}
+	if errors.Is(err, secrets.ErrCertificateIssuanceFailed) {
+		// The certificate is requested again when the request or its Secret changes.
+		log.Info(logger, "mTLS certificate issuance failed", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -190,6 +210,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 	deploymentOpts := []k8sresources.DeploymentOpt{
 		labelSelectorFromDataPlaneStatusSelectorDeploymentOpt(dataplane),
+		clusterCertificateDeploymentOpt(certSecret),
 	}
 
 	// if the dataplane is configured with Konnect, the status/ready endpoint should be set as the readiness probe.
@@ -209,6 +230,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	deploymentBuilder := NewDeploymentBuilder(logger.WithName("deployment_builder"), r.Client).
 		WithClusterCertificate(certSecret.Name).
+		WithClusterCertificateSerial(clusterCertificateSerial(certSecret)).
 		WithOpts(deploymentOpts...).
 		WithDefaultImage(r.DefaultImage).
 		WithAdditionalLabels(deploymentLabels).
@@ -246,7 +268,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	log.Debug(logger, "reconciliation complete for DataPlane resource")
-	return ctrl.Result{}, nil
+	return ctrl.Result{
+		RequeueAfter: secrets.CertificateRenewalRequeueAfter(certSecret, r.ClusterCertificateRenewalConfig),
+	}, nil
 }
 
 func (r *Reconciler) initSelectorInStatus(ctx context.Context, logger logr.Logger, dataplane *operatorv1beta1.DataPlane) error {
@@ -272,6 +296,28 @@ func labelSelectorFromDataPlaneStatusSelectorDeploymentOpt(dataplane *operatorv1
 	}
 }
 
+// clusterCertificateDeploymentOpt annotates the Pod template with the checksum of the trusted
+// CA certificates so that rotating the cluster CA rolls out the DataPlane Pods.
+func clusterCertificateDeploymentOpt(certSecret *corev1.Secret) func(s *appsv1.Deployment) {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Annotations == nil {
+			d.Spec.Template.Annotations = make(map[string]string)
+		}
+		d.Spec.Template.Annotations[consts.DataPlaneClusterCAChecksumAnnotation] = secrets.CATrustBundleChecksum(certSecret.Data["ca.crt"])
+	}
+}
+
+// clusterCertificateSerial returns the serial number of the Admin API certificate, which is used
+// to roll out the DataPlane Pods when the certificate is renewed. An empty string is returned
+// when the certificate can't be parsed, leaving the Pods as they are.
+func clusterCertificateSerial(certSecret *corev1.Secret) string {
+	cert, err := secrets.ParseSecretCertificate(certSecret)
+	if err != nil {
+		return ""
+	}
+	return cert.SerialNumber.Text(16)
+}
+
 func statusReadyEndpointDeploymentOpt(_ *operatorv1beta1.DataPlane) func(s *appsv1.Deployment) {
 	return func(d *appsv1.Deployment) {
 		if container := k8sutils.GetPodContainerByName(&d.Spec.Template.Spec, consts.DataPlaneProxyContainerName); container != nil {
@@ -21,6 +21,7 @@ import (
 	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
 
+	"github.com/kong/kong-operator/controller/kongplugininstallation/image"
 	"github.com/kong/kong-operator/controller/pkg/address"
 	"github.com/kong/kong-operator/controller/pkg/log"
 	"github.com/kong/kong-operator/pkg/consts"
@@ -167,6 +168,14 @@ func populateDedicatedConfigMapForKongPluginInstallation(
 	if err := c.Get(ctx, backingCMNN, &underlyingCM); err != nil {
 		return customPlugin{}, false, fmt.Errorf("could not fetch underlying ConfigMap to clone %s: %w", backingCMNN, err)
 	}
+	if imageRef := underlyingCM.Annotations[consts.AnnotationKongPluginInstallationImageReference]; imageRef != "" {
+		log.Trace(logger, fmt.Sprintf("KongPluginInstallation %s is delivered as image volume %s", kpiNN, imageRef))
+		return customPlugin{
+			Name:       kpi.Name,
+			Image:      imageRef,
+			Generation: kpi.Generation,
+		}, false, nil
+	}
 
 	log.Trace(logger, "Find ConfigMap mapped to KongPluginInstallation")
 	mappedConfigMapForKPI := lo.Filter(cms, func(cm corev1.ConfigMap, _ int) bool {
@@ -211,9 +220,11 @@ func populateDedicatedConfigMapForKongPluginInstallation(
 		return customPlugin{}, false, fmt.Errorf("unexpected error happened - more than one ConfigMap found: %s", names)
 	}
 	return customPlugin{
-		Name:        kpi.Name,
-		ConfigMapNN: client.ObjectKeyFromObject(&cm),
-		Generation:  kpi.Generation,
+		Name:           kpi.Name,
+		ConfigMapNN:    client.ObjectKeyFromObject(&cm),
+		ConfigMapItems: image.ConfigMapItems(underlyingCM.Data),
+		Generation:     kpi.Generation,
+		Digest:         underlyingCM.Annotations[consts.AnnotationKongPluginInstallationImageDigest],
 	}, false, nil
 }
 
@@ -18,8 +18,16 @@ type customPlugin struct {
 	Name string
 	// ConfigMapNN is the namespace/name of the ConfigMap that contains the plugin.
 	ConfigMapNN types.NamespacedName
+	// ConfigMapItems project the ConfigMap's keys to paths of the plugin's files.
+	// When empty, the whole ConfigMap is mounted as is.
+	ConfigMapItems []corev1.KeyToPath
+	// Image is the reference of the plugin image, pinned by digest, that is mounted
+	// as an image volume instead of the ConfigMap.
+	Image string
 	// Generation is the generation of the KongPluginInstallation that contains the plugin.
 	Generation int64
+	// Digest is the digest of the image the plugin has been fetched from.
+	Digest string
 }
 
 func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt {
@@ -31,12 +39,17 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 				d.Spec.Template.Annotations,
 				consts.AnnotationKongPluginInstallationGenerationInternal,
 			)
+			delete(
+				d.Spec.Template.Annotations,
+				consts.AnnotationKongPluginInstallationImageDigestsInternal,
+			)
 		}
 	}
 
 	var (
 		kpisNames        = make([]string, 0, len(customPlugins))
 		kpisGenerations  = make([]string, 0, len(customPlugins))
+		kpisDigests      = make([]string, 0, len(customPlugins))
 		kpisVolumeMounts = make([]corev1.VolumeMount, 0, len(customPlugins))
 		kpisVolumes      = make([]corev1.Volume, 0, len(customPlugins))
 	)
@@ -44,20 +57,33 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 	for _, cp := range customPlugins {
 		kpisNames = append(kpisNames, cp.Name)
 		kpisGenerations = append(kpisGenerations, fmt.Sprintf("%s:%d", cp.Name, cp.Generation))
+		// Plugins delivered as image volumes are rolled out with the change of the image reference,
+		// the ones delivered through ConfigMaps need the digest to be rolled out when the image
+		// behind a tag changes.
+		if cp.Digest != "" && cp.Image == "" {
+			kpisDigests = append(kpisDigests, fmt.Sprintf("%s@%s", cp.Name, cp.Digest))
+		}
 		kpisVolumeMounts = append(kpisVolumeMounts, corev1.VolumeMount{
 			Name:      cp.Name,
 			MountPath: "/opt/kong/plugins/" + cp.Name,
 		})
-		kpisVolumes = append(kpisVolumes, corev1.Volume{
+		volume := corev1.Volume{
 			Name: cp.Name,
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: cp.ConfigMapNN.Name,
-					},
+		}
+		if cp.Image != "" {
+			volume.Image = &corev1.ImageVolumeSource{
+				Reference:  cp.Image,
+				PullPolicy: corev1.PullIfNotPresent,
+			}
+		} else {
+			volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: cp.ConfigMapNN.Name,
 				},
-			},
-		})
+				Items: cp.ConfigMapItems,
+			}
+		}
+		kpisVolumes = append(kpisVolumes, volume)
 	}
 
 	return func(deployment *appsv1.Deployment) {
@@ -65,6 +91,11 @@ func withCustomPlugins(customPlugins ...customPlugin) k8sresources.DeploymentOpt
 			deployment.Spec.Template.Annotations = make(map[string]string)
 		}
 		deployment.Spec.Template.Annotations[consts.AnnotationKongPluginInstallationGenerationInternal] = strings.Join(kpisGenerations, ",")
+		if len(kpisDigests) > 0 {
+			deployment.Spec.Template.Annotations[consts.AnnotationKongPluginInstallationImageDigestsInternal] = strings.Join(kpisDigests, ",")
+		} else {
+			delete(deployment.Spec.Template.Annotations, consts.AnnotationKongPluginInstallationImageDigestsInternal)
+		}
 		deployment.Spec.Template.Spec.Containers[0].Env = append(
 			deployment.Spec.Template.Spec.Containers[0].Env,
 			config.ConfigureKongPluginRelatedEnvVars(kpisNames)...,
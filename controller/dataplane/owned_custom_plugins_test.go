@@ -132,6 +132,80 @@ func TestOptWithCustomPlugin(t *testing.T) {
 				consts.AnnotationKongPluginInstallationGenerationInternal: "plugin1:1,plugin2:2",
 			},
 		},
+		{
+			name: "plugin directory tree and plugin delivered as image volume",
+			customPlugins: []customPlugin{
+				{
+					Name: "plugin1",
+					ConfigMapNN: types.NamespacedName{
+						Name: "configmap1",
+					},
+					ConfigMapItems: []corev1.KeyToPath{
+						{Key: "handler.lua", Path: "handler.lua"},
+						{Key: "helpers_.util.lua", Path: "helpers/util.lua"},
+						{Key: "schema.lua", Path: "schema.lua"},
+					},
+					Generation: 1,
+					Digest:     "sha256:8f4e1c2b3a4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f",
+				},
+				{
+					Name:       "plugin2",
+					Image:      "registry.example.com/plugin2@sha256:2d7c2c4b0c4e5c0e8a8f5d0a5b3f9c3f6b1e0f7a8d9c0b1a2e3f4d5c6b7a8e9f",
+					Generation: 3,
+					Digest:     "sha256:2d7c2c4b0c4e5c0e8a8f5d0a5b3f9c3f6b1e0f7a8d9c0b1a2e3f4d5c6b7a8e9f",
+				},
+			},
+			expectedEnv: []corev1.EnvVar{
+				{
+					Name:  "KONG_PLUGINS",
+					Value: "bundled,plugin1,plugin2",
+				},
+				{
+					Name:  "KONG_LUA_PACKAGE_PATH",
+					Value: "/opt/?.lua;;",
+				},
+			},
+			expectedVolumes: []corev1.Volume{
+				{
+					Name: "plugin1",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{
+								Name: "configmap1",
+							},
+							Items: []corev1.KeyToPath{
+								{Key: "handler.lua", Path: "handler.lua"},
+								{Key: "helpers_.util.lua", Path: "helpers/util.lua"},
+								{Key: "schema.lua", Path: "schema.lua"},
+							},
+						},
+					},
+				},
+				{
+					Name: "plugin2",
+					VolumeSource: corev1.VolumeSource{
+						Image: &corev1.ImageVolumeSource{
+							Reference:  "registry.example.com/plugin2@sha256:2d7c2c4b0c4e5c0e8a8f5d0a5b3f9c3f6b1e0f7a8d9c0b1a2e3f4d5c6b7a8e9f",
+							PullPolicy: corev1.PullIfNotPresent,
+						},
+					},
+				},
+			},
+			expectedVolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "plugin1",
+					MountPath: "/opt/kong/plugins/plugin1",
+				},
+				{
+					Name:      "plugin2",
+					MountPath: "/opt/kong/plugins/plugin2",
+				},
+			},
+			expectedAnnotations: map[string]string{
+				consts.AnnotationKongPluginInstallationGenerationInternal:   "plugin1:1,plugin2:3",
+				consts.AnnotationKongPluginInstallationImageDigestsInternal: "plugin1@sha256:8f4e1c2b3a4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f",
+			},
+		},
 	}
 
 	for _, tt := range testCases {
@@ -170,8 +244,9 @@ func TestOptNoopWithCustomPlugin(t *testing.T) {
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						consts.AnnotationKongPluginInstallationGenerationInternal: "plugin1:1",
-						annotationThatShouldBePreservedKey:                        annotationThatShouldBePreservedValue,
+						consts.AnnotationKongPluginInstallationGenerationInternal:   "plugin1:1",
+						consts.AnnotationKongPluginInstallationImageDigestsInternal: "plugin1@sha256:8f4e1c2b3a4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f",
+						annotationThatShouldBePreservedKey:                          annotationThatShouldBePreservedValue,
 					},
 				},
 			},
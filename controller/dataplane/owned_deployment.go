@@ -39,12 +39,13 @@ const restartAnnotationKey = "kubectl.kubernetes.io/restartedAt"
 
 // DeploymentBuilder builds a Deployment for a DataPlane.
 type DeploymentBuilder struct {
-	clusterCertificateName string
-	logger                 logr.Logger
-	client                 client.Client
-	additionalLabels       client.MatchingLabels
-	defaultImage           string
-	opts                   []k8sresources.DeploymentOpt
+	clusterCertificateName   string
+	clusterCertificateSerial string
+	logger                   logr.Logger
+	client                   client.Client
+	additionalLabels         client.MatchingLabels
+	defaultImage             string
+	opts                     []k8sresources.DeploymentOpt
 
 	secretLabelSelector string
 }
@@ -63,6 +64,13 @@ func (d *DeploymentBuilder) WithClusterCertificate(name string) *DeploymentBuild
 	return d
 }
 
+// WithClusterCertificateSerial configures the serial number of the cluster certificate for a
+// DeploymentBuilder. The DataPlane Pods are rolled out when it changes.
+func (d *DeploymentBuilder) WithClusterCertificateSerial(serial string) *DeploymentBuilder {
+	d.clusterCertificateSerial = serial
+	return d
+}
+
 // WithAdditionalLabels configures additional labels for a DeploymentBuilder.
 func (d *DeploymentBuilder) WithAdditionalLabels(labels client.MatchingLabels) *DeploymentBuilder {
 	d.additionalLabels = labels
@@ -140,6 +148,11 @@ func (d *DeploymentBuilder) BuildAndDeploy(
 	// apply default envvars and restore the hacked-out ones
 	desiredDeployment = applyEnvForDataPlane(existingEnvVars, desiredDeployment, config.KongDefaults)
 
+	if d.clusterCertificateSerial != "" {
+		setRolloutAnnotation(desiredDeployment.Unwrap(), existingDeployment,
+			consts.DataPlaneClusterCertificateSerialAnnotation, d.clusterCertificateSerial)
+	}
+
 	if err := k8sresources.AnnotateObjWithHash(desiredDeployment.Unwrap(), dataplane.Spec); err != nil {
 		return nil, op.Noop, err
 	}
@@ -228,6 +241,36 @@ func setClusterCertVars(
 		)
 }
 
+// setRolloutAnnotation sets the annotation key to value on the desired Deployment and on its Pod
+// template, rolling out the Pods, but only when value differs from the one the existing Deployment
+// is annotated with. A Deployment which isn't annotated yet, e.g. because it was created by an older
+// version of the operator, is annotated without rolling out its Pods, which assumes they already
+// run with value. Otherwise the Pod template annotation of the existing Deployment is kept.
+func setRolloutAnnotation(desired, existing *appsv1.Deployment, key, value string) {
+	templateValue := value
+	if existing != nil {
+		templateValue = existing.Spec.Template.Annotations[key]
+		recorded, ok := existing.Annotations[key]
+		if !ok {
+			recorded = templateValue
+		}
+		if recorded != "" && recorded != value {
+			templateValue = value
+		}
+	}
+
+	if desired.Annotations == nil {
+		desired.Annotations = make(map[string]string)
+	}
+	desired.Annotations[key] = value
+	if templateValue != "" {
+		if desired.Spec.Template.Annotations == nil {
+			desired.Spec.Template.Annotations = make(map[string]string)
+		}
+		desired.Spec.Template.Annotations[key] = templateValue
+	}
+}
+
 // listOrReduceDataPlaneDeployments lists existing DataPlane Deployments. If only one is present, it returns it. If
 // multiple are present, it reduces them to one and notifies the caller it reduced, so that the caller can try its
 // operation again once there's only a single Deployment to work with.
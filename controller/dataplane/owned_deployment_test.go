@@ -0,0 +1,76 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestSetRolloutAnnotation(t *testing.T) {
+	const key = consts.DataPlaneClusterCertificateSerialAnnotation
+
+	deployment := func(annotations, templateAnnotations map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: annotations,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: templateAnnotations,
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                        string
+		existing                    *appsv1.Deployment
+		expectedTemplateAnnotations map[string]string
+	}{
+		{
+			name:                        "new Deployment is annotated",
+			expectedTemplateAnnotations: map[string]string{key: "2"},
+		},
+		{
+			name:                        "Deployment created before the annotation was introduced isn't rolled out",
+			existing:                    deployment(nil, nil),
+			expectedTemplateAnnotations: nil,
+		},
+		{
+			name:                        "Deployment annotated with the same value isn't rolled out",
+			existing:                    deployment(map[string]string{key: "2"}, nil),
+			expectedTemplateAnnotations: nil,
+		},
+		{
+			name:                        "Pod template annotation is kept when the value doesn't change",
+			existing:                    deployment(map[string]string{key: "2"}, map[string]string{key: "2"}),
+			expectedTemplateAnnotations: map[string]string{key: "2"},
+		},
+		{
+			name:                        "Deployment is rolled out when the value changes",
+			existing:                    deployment(map[string]string{key: "1"}, nil),
+			expectedTemplateAnnotations: map[string]string{key: "2"},
+		},
+		{
+			name:                        "Deployment annotated only on the Pod template is rolled out when the value changes",
+			existing:                    deployment(nil, map[string]string{key: "1"}),
+			expectedTemplateAnnotations: map[string]string{key: "2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			desired := deployment(nil, nil)
+			setRolloutAnnotation(desired, tc.existing, key, "2")
+			assert.Equal(t, map[string]string{key: "2"}, desired.Annotations)
+			assert.Equal(t, tc.expectedTemplateAnnotations, desired.Spec.Template.Annotations)
+		})
+	}
+}
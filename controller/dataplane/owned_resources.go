@@ -15,6 +15,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
@@ -39,6 +40,9 @@ func ensureDataPlaneCertificate(
 	adminServiceNN types.NamespacedName,
 	secretLabelSelector string,
 	keyConfig secrets.KeyConfig,
+	issuer secrets.IssuerConfig,
+	renewalConfig secrets.CertificateRenewalConfig,
+	eventRecorder record.EventRecorder,
 ) (op.Result, *corev1.Secret, error) {
 	usages := []certificatesv1.KeyUsage{
 		certificatesv1.UsageKeyEncipherment,
@@ -54,7 +58,10 @@ func ensureDataPlaneCertificate(
 		clusterCASecretNN,
 		usages,
 		keyConfig,
+		issuer,
+		renewalConfig,
 		cl,
+		eventRecorder,
 		matchingLabels,
 	)
 }
@@ -0,0 +1,20 @@
+package fullhybrid
+
+// -----------------------------------------------------------------------------
+// HTTPRouteReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways;gatewayclasses,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway-operator.konghq.com,resources=gatewayconfigurations,verbs=get;list;watch
+//+kubebuilder:rbac:groups=konnect.konghq.com,resources=konnectextensions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongservices;kongroutes;kongupstreams;kongtargets;kongpluginbindings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=configuration.konghq.com,resources=kongplugins,verbs=get;list;watch;create;update;patch;delete
+
+// -----------------------------------------------------------------------------
+// ServiceReconciler - RBAC Permissions
+// -----------------------------------------------------------------------------
+
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
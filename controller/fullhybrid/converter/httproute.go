@@ -0,0 +1,421 @@
+package converter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	configurationv1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+var _ APIConverter[gwtypes.HTTPRoute] = &httpRouteConverter{}
+
+// ErrUnsupportedHTTPRouteFeature is returned by the HTTPRoute converter when the
+// HTTPRoute uses a feature that cannot be expressed with Kong entities.
+var ErrUnsupportedHTTPRouteFeature = errors.New("unsupported HTTPRoute feature")
+
+// httpRouteConverter is the APIConverter implementation that translates an HTTPRoute
+// into the Konnect-backed Kong entities.
+//
+// For every rule of the HTTPRoute it generates:
+// - a KongUpstream, whose KongTargets are generated by the Service converter,
+// - a KongService pointing to the KongUpstream,
+// - a KongRoute for each match, bound to the KongService,
+// - a KongPlugin and a KongPluginBinding targeting the KongService for each filter.
+//
+// The Konnect control plane the entities are attached to is the one referenced by the
+// KonnectExtension configured in the GatewayConfiguration of the parent Gateways.
+// As cross-namespace control plane references are not supported yet, the control plane
+// has to live in the HTTPRoute namespace.
+type httpRouteConverter struct {
+	client.Client
+
+	route       gwtypes.HTTPRoute
+	store       httpRouteStore
+	outputStore HTTPRouteOutput
+}
+
+type httpRouteStore struct {
+	controlPlaneRef *commonv1alpha1.ControlPlaneRef
+	parentRef       *gwtypes.ParentReference
+	services        map[types.NamespacedName]corev1.Service
+}
+
+// HTTPRouteOutput groups the Kong entities generated from an HTTPRoute.
+type HTTPRouteOutput struct {
+	KongServices       []configurationv1alpha1.KongService
+	KongRoutes         []configurationv1alpha1.KongRoute
+	KongUpstreams      []configurationv1alpha1.KongUpstream
+	KongPlugins        []configurationv1.KongPlugin
+	KongPluginBindings []configurationv1alpha1.KongPluginBinding
+}
+
+// NewHTTPRouteConverter returns a new instance of httpRouteConverter.
+func NewHTTPRouteConverter(cl client.Client) *httpRouteConverter {
+	return &httpRouteConverter{
+		Client: cl,
+		store: httpRouteStore{
+			services: map[types.NamespacedName]corev1.Service{},
+		},
+	}
+}
+
+// SetRootObject implements APIConverter.
+func (c *httpRouteConverter) SetRootObject(obj gwtypes.HTTPRoute) {
+	c.route = obj
+}
+
+// LoadStore implements APIConverter.
+func (c *httpRouteConverter) LoadStore(ctx context.Context) error {
+	cpRef, parentRef, err := resolveHTTPRouteControlPlaneRef(ctx, c.Client, &c.route)
+	c.store.parentRef = parentRef
+	if err != nil {
+		return err
+	}
+	c.store.controlPlaneRef = cpRef
+
+	for _, rule := range c.route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			nn, ok := backendServiceName(&c.route, b.BackendObjectReference)
+			if !ok {
+				continue
+			}
+			if _, loaded := c.store.services[nn]; loaded {
+				continue
+			}
+			var svc corev1.Service
+			if err := c.Get(ctx, nn, &svc); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("failed to get Service %s: %w", nn, err)
+			}
+			c.store.services[nn] = svc
+		}
+	}
+	return nil
+}
+
+// KonnectParentRef returns the parentRef of the Gateway backed by the Konnect control plane
+// the entities are generated for. It is nil until LoadStore resolves such a Gateway.
+func (c *httpRouteConverter) KonnectParentRef() *gwtypes.ParentReference {
+	return c.store.parentRef
+}
+
+// Translate implements APIConverter.
+// The rules which cannot be translated (e.g. because they use an unsupported feature)
+// are skipped and the returned error lists them, while the output contains
+// the entities generated for all the other rules.
+func (c *httpRouteConverter) Translate() error {
+	c.outputStore = HTTPRouteOutput{}
+
+	// The HTTPRoute is not attached to any Gateway backed by a Konnect control plane:
+	// there is nothing to generate.
+	if c.store.controlPlaneRef == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, rule := range c.route.Spec.Rules {
+		// Keep the entities generated so far so that a rule which fails to be
+		// translated doesn't leave any of its entities behind.
+		translated := c.outputStore
+		if err := c.translateRule(i, rule); err != nil {
+			c.outputStore = translated
+			errs = append(errs, fmt.Errorf("failed to translate rule %d of HTTPRoute %s/%s: %w",
+				i, c.route.Namespace, c.route.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetOutputStore returns the Kong entities generated by the last Translate call.
+func (c *httpRouteConverter) GetOutputStore() HTTPRouteOutput {
+	return c.outputStore
+}
+
+func (c *httpRouteConverter) translateRule(ruleIndex int, rule gwtypes.HTTPRouteRule) error {
+	// Without any resolvable backend there is nothing to proxy the traffic to.
+	if !lo.ContainsBy(rule.BackendRefs, func(b gwtypes.HTTPBackendRef) bool {
+		nn, ok := backendServiceName(&c.route, b.BackendObjectReference)
+		_, found := c.store.services[nn]
+		return ok && found && b.Port != nil
+	}) {
+		return nil
+	}
+
+	name := HTTPRouteRuleUpstreamName(&c.route, ruleIndex)
+
+	// The KongTargets of the KongUpstream are generated from the EndpointSlices of the
+	// backend Services by the Service converter.
+	c.outputStore.KongUpstreams = append(c.outputStore.KongUpstreams, configurationv1alpha1.KongUpstream{
+		ObjectMeta: c.objectMeta(name),
+		Spec: configurationv1alpha1.KongUpstreamSpec{
+			ControlPlaneRef: c.store.controlPlaneRef.DeepCopy(),
+			KongUpstreamAPISpec: configurationv1alpha1.KongUpstreamAPISpec{
+				Name: name,
+			},
+		},
+	})
+
+	c.outputStore.KongServices = append(c.outputStore.KongServices, configurationv1alpha1.KongService{
+		ObjectMeta: c.objectMeta(name),
+		Spec: configurationv1alpha1.KongServiceSpec{
+			ControlPlaneRef: c.store.controlPlaneRef.DeepCopy(),
+			KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+				Name:     lo.ToPtr(name),
+				Host:     name,
+				Protocol: sdkkonnectcomp.ProtocolHTTP,
+			},
+		},
+	})
+
+	routes, err := c.translateMatches(name, rule.Matches)
+	if err != nil {
+		return err
+	}
+	c.outputStore.KongRoutes = append(c.outputStore.KongRoutes, routes...)
+
+	return c.translateFilters(name, rule.Filters)
+}
+
+func (c *httpRouteConverter) translateMatches(serviceName string, matches []gatewayv1.HTTPRouteMatch) ([]configurationv1alpha1.KongRoute, error) {
+	// A rule with no matches matches all the requests.
+	if len(matches) == 0 {
+		matches = []gatewayv1.HTTPRouteMatch{{}}
+	}
+
+	hosts := lo.Map(c.route.Spec.Hostnames, func(h gatewayv1.Hostname, _ int) string {
+		return string(h)
+	})
+
+	routes := make([]configurationv1alpha1.KongRoute, 0, len(matches))
+	for i, match := range matches {
+		if len(match.QueryParams) > 0 {
+			return nil, fmt.Errorf("%w: query parameters matching", ErrUnsupportedHTTPRouteFeature)
+		}
+
+		paths, err := pathsFromHTTPPathMatch(match.Path)
+		if err != nil {
+			return nil, err
+		}
+		headers, err := headersFromHTTPHeaderMatches(match.Headers)
+		if err != nil {
+			return nil, err
+		}
+
+		name := fmt.Sprintf("%s-%d", serviceName, i)
+		route := configurationv1alpha1.KongRoute{
+			ObjectMeta: c.objectMeta(name),
+			Spec: configurationv1alpha1.KongRouteSpec{
+				ServiceRef: &configurationv1alpha1.ServiceRef{
+					Type: configurationv1alpha1.ServiceRefNamespacedRef,
+					NamespacedRef: &commonv1alpha1.NameRef{
+						Name: serviceName,
+					},
+				},
+				KongRouteAPISpec: configurationv1alpha1.KongRouteAPISpec{
+					Name:         lo.ToPtr(name),
+					Hosts:        hosts,
+					Paths:        paths,
+					Headers:      headers,
+					PreserveHost: lo.ToPtr(true),
+					StripPath:    lo.ToPtr(false),
+					Protocols: []sdkkonnectcomp.RouteJSONProtocols{
+						sdkkonnectcomp.RouteJSONProtocolsHTTP,
+						sdkkonnectcomp.RouteJSONProtocolsHTTPS,
+					},
+				},
+			},
+		}
+		if match.Method != nil {
+			route.Spec.Methods = []string{string(*match.Method)}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// pathsFromHTTPPathMatch returns the Kong route paths for the provided path match,
+// following the Gateway API semantics of path prefixes matching whole path segments.
+func pathsFromHTTPPathMatch(match *gatewayv1.HTTPPathMatch) ([]string, error) {
+	if match == nil || match.Value == nil {
+		return []string{"/"}, nil
+	}
+
+	path := *match.Value
+	switch lo.FromPtrOr(match.Type, gatewayv1.PathMatchPathPrefix) {
+	case gatewayv1.PathMatchExact:
+		return []string{"~" + path + "$"}, nil
+	case gatewayv1.PathMatchPathPrefix:
+		if path == "/" {
+			return []string{"/"}, nil
+		}
+		path = strings.TrimSuffix(path, "/")
+		return []string{"~" + path + "$", path + "/"}, nil
+	case gatewayv1.PathMatchRegularExpression:
+		return []string{"~" + path}, nil
+	default:
+		return nil, fmt.Errorf("%w: path match type %s", ErrUnsupportedHTTPRouteFeature, *match.Type)
+	}
+}
+
+func headersFromHTTPHeaderMatches(matches []gatewayv1.HTTPHeaderMatch) (map[string][]string, error) {
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	headers := make(map[string][]string, len(matches))
+	for _, m := range matches {
+		switch lo.FromPtrOr(m.Type, gatewayv1.HeaderMatchExact) {
+		case gatewayv1.HeaderMatchExact:
+			headers[string(m.Name)] = []string{m.Value}
+		case gatewayv1.HeaderMatchRegularExpression:
+			headers[string(m.Name)] = []string{"~*" + m.Value}
+		default:
+			return nil, fmt.Errorf("%w: header match type %s", ErrUnsupportedHTTPRouteFeature, *m.Type)
+		}
+	}
+	return headers, nil
+}
+
+// translateFilters generates a KongPluginBinding targeting the KongService for each
+// filter of the rule. All filters but ExtensionRef ones also generate the KongPlugin
+// the binding refers to.
+func (c *httpRouteConverter) translateFilters(serviceName string, filters []gatewayv1.HTTPRouteFilter) error {
+	for i, filter := range filters {
+		name := fmt.Sprintf("%s-%d", serviceName, i)
+
+		var (
+			pluginName string
+			config     map[string]any
+		)
+		switch filter.Type {
+		case gatewayv1.HTTPRouteFilterRequestHeaderModifier:
+			pluginName, config = "request-transformer", headerModifierPluginConfig(filter.RequestHeaderModifier)
+		case gatewayv1.HTTPRouteFilterResponseHeaderModifier:
+			pluginName, config = "response-transformer", headerModifierPluginConfig(filter.ResponseHeaderModifier)
+		case gatewayv1.HTTPRouteFilterExtensionRef:
+			ref := filter.ExtensionRef
+			if ref == nil || string(ref.Group) != configurationv1.GroupVersion.Group || string(ref.Kind) != "KongPlugin" {
+				return fmt.Errorf("%w: ExtensionRef filter must reference a KongPlugin", ErrUnsupportedHTTPRouteFeature)
+			}
+			c.outputStore.KongPluginBindings = append(c.outputStore.KongPluginBindings, c.pluginBinding(name, string(ref.Name), serviceName))
+			continue
+		default:
+			return fmt.Errorf("%w: filter type %s", ErrUnsupportedHTTPRouteFeature, filter.Type)
+		}
+		if config == nil {
+			continue
+		}
+
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s plugin configuration: %w", pluginName, err)
+		}
+		c.outputStore.KongPlugins = append(c.outputStore.KongPlugins, configurationv1.KongPlugin{
+			ObjectMeta: c.objectMeta(name),
+			PluginName: pluginName,
+			Config: apiextensionsv1.JSON{
+				Raw: raw,
+			},
+		})
+		c.outputStore.KongPluginBindings = append(c.outputStore.KongPluginBindings, c.pluginBinding(name, name, serviceName))
+	}
+	return nil
+}
+
+// headerModifierPluginConfig returns the request-transformer or response-transformer
+// configuration equivalent to the provided header filter.
+// Setting a header is translated into both replacing and adding it, as the transformer
+// plugins only replace existing headers and only add missing ones.
+func headerModifierPluginConfig(filter *gatewayv1.HTTPHeaderFilter) map[string]any {
+	if filter == nil {
+		return nil
+	}
+
+	headerValues := func(headers []gatewayv1.HTTPHeader) []string {
+		return lo.Map(headers, func(h gatewayv1.HTTPHeader, _ int) string {
+			return fmt.Sprintf("%s:%s", h.Name, h.Value)
+		})
+	}
+
+	config := map[string]any{}
+	if len(filter.Set) > 0 {
+		config["replace"] = map[string]any{"headers": headerValues(filter.Set)}
+		config["add"] = map[string]any{"headers": headerValues(filter.Set)}
+	}
+	if len(filter.Add) > 0 {
+		config["append"] = map[string]any{"headers": headerValues(filter.Add)}
+	}
+	if len(filter.Remove) > 0 {
+		config["remove"] = map[string]any{"headers": filter.Remove}
+	}
+	return config
+}
+
+func (c *httpRouteConverter) pluginBinding(name, pluginName, serviceName string) configurationv1alpha1.KongPluginBinding {
+	return configurationv1alpha1.KongPluginBinding{
+		ObjectMeta: c.objectMeta(name),
+		Spec: configurationv1alpha1.KongPluginBindingSpec{
+			ControlPlaneRef: *c.store.controlPlaneRef.DeepCopy(),
+			PluginReference: configurationv1alpha1.PluginRef{
+				Name: pluginName,
+				Kind: lo.ToPtr("KongPlugin"),
+			},
+			Targets: &configurationv1alpha1.KongPluginBindingTargets{
+				ServiceReference: &configurationv1alpha1.TargetRefWithGroupKind{
+					Group: configurationv1alpha1.GroupVersion.Group,
+					Kind:  "KongService",
+					Name:  serviceName,
+				},
+			},
+		},
+	}
+}
+
+// objectMeta returns the metadata of a generated object: it lives in the HTTPRoute
+// namespace, is labeled as managed by the HTTPRoute and is owned by it.
+func (c *httpRouteConverter) objectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: c.route.Namespace,
+		Labels:    HTTPRouteManagedByLabels(&c.route),
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion:         gwtypes.GroupVersion.String(),
+				Kind:               "HTTPRoute",
+				Name:               c.route.Name,
+				UID:                c.route.UID,
+				Controller:         lo.ToPtr(true),
+				BlockOwnerDeletion: lo.ToPtr(true),
+			},
+		},
+	}
+}
+
+// HTTPRouteManagedByLabels returns the labels set on the objects generated from the provided HTTPRoute.
+func HTTPRouteManagedByLabels(route *gwtypes.HTTPRoute) map[string]string {
+	return map[string]string{
+		consts.GatewayOperatorManagedByLabel:          consts.HTTPRouteManagedByLabelValue,
+		consts.GatewayOperatorManagedByNameLabel:      route.Name,
+		consts.GatewayOperatorManagedByNamespaceLabel: route.Namespace,
+	}
+}
@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+// HTTPRouteRuleUpstreamName returns the name of the KongUpstream (and KongService)
+// generated for the rule with the provided index of the HTTPRoute.
+func HTTPRouteRuleUpstreamName(route *gwtypes.HTTPRoute, ruleIndex int) string {
+	return fmt.Sprintf("%s-%d", route.Name, ruleIndex)
+}
+
+// backendServiceName returns the namespaced name of the Service referenced by the
+// provided backendRef. Cross-namespace references are not supported.
+func backendServiceName(route *gwtypes.HTTPRoute, ref gwtypes.BackendObjectReference) (types.NamespacedName, bool) {
+	if (ref.Group != nil && *ref.Group != "") || (ref.Kind != nil && *ref.Kind != "Service") {
+		return types.NamespacedName{}, false
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != route.Namespace {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: route.Namespace, Name: string(ref.Name)}, true
+}
+
+// resolveHTTPRouteControlPlaneRef returns the reference to the Konnect control plane configured
+// through a KonnectExtension in the GatewayConfiguration of the first parent Gateway
+// that has one, together with the parentRef of that Gateway. It returns nil when no parent
+// Gateway is backed by Konnect, and an ErrUnsupportedHTTPRouteFeature error (along with the
+// parentRef) when the control plane is not in the HTTPRoute namespace.
+func resolveHTTPRouteControlPlaneRef(
+	ctx context.Context, cl client.Client, route *gwtypes.HTTPRoute,
+) (*commonv1alpha1.ControlPlaneRef, *gwtypes.ParentReference, error) {
+	for _, parentRef := range route.Spec.ParentRefs {
+		if (parentRef.Group != nil && string(*parentRef.Group) != gwtypes.GroupVersion.Group) ||
+			(parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+			continue
+		}
+
+		namespace := route.Namespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+
+		var gateway gwtypes.Gateway
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: string(parentRef.Name)}, &gateway); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to get Gateway %s/%s: %w", namespace, parentRef.Name, err)
+		}
+
+		var gatewayClass gwtypes.GatewayClass
+		if err := cl.Get(ctx, types.NamespacedName{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to get GatewayClass %s: %w", gateway.Spec.GatewayClassName, err)
+		}
+		paramsRef := gatewayClass.Spec.ParametersRef
+		if paramsRef == nil ||
+			string(paramsRef.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+			string(paramsRef.Kind) != "GatewayConfiguration" ||
+			paramsRef.Namespace == nil {
+			continue
+		}
+
+		var gatewayConfig gwtypes.GatewayConfiguration
+		if err := cl.Get(ctx, types.NamespacedName{Namespace: string(*paramsRef.Namespace), Name: paramsRef.Name}, &gatewayConfig); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("failed to get GatewayConfiguration %s/%s: %w", *paramsRef.Namespace, paramsRef.Name, err)
+		}
+
+		for _, extRef := range gatewayConfig.Spec.Extensions {
+			if extRef.Group != konnectv1alpha1.SchemeGroupVersion.Group || extRef.Kind != konnectv1alpha2.KonnectExtensionKind {
+				continue
+			}
+			extNamespace := lo.FromPtrOr(extRef.Namespace, gatewayConfig.Namespace)
+
+			var ext konnectv1alpha2.KonnectExtension
+			if err := cl.Get(ctx, types.NamespacedName{Namespace: extNamespace, Name: extRef.Name}, &ext); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				return nil, nil, fmt.Errorf("failed to get KonnectExtension %s/%s: %w", extNamespace, extRef.Name, err)
+			}
+
+			extCPRef := ext.Spec.Konnect.ControlPlane.Ref
+			if extCPRef.Type != commonv1alpha1.ControlPlaneRefKonnectNamespacedRef || extCPRef.KonnectNamespacedRef == nil {
+				continue
+			}
+			// The control plane lives in the KonnectExtension namespace unless the
+			// extension explicitly references another one. The generated entities
+			// live in the HTTPRoute namespace and cannot reference control planes
+			// from other namespaces yet.
+			cpNamespace := extCPRef.KonnectNamespacedRef.Namespace
+			if cpNamespace == "" {
+				cpNamespace = ext.Namespace
+			}
+			if cpNamespace != route.Namespace {
+				return nil, &parentRef, fmt.Errorf("%w: control plane %s/%s referenced by KonnectExtension %s/%s is not in the HTTPRoute namespace",
+					ErrUnsupportedHTTPRouteFeature, cpNamespace, extCPRef.KonnectNamespacedRef.Name, ext.Namespace, ext.Name)
+			}
+			return &commonv1alpha1.ControlPlaneRef{
+				Type: commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+				KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{
+					Name: extCPRef.KonnectNamespacedRef.Name,
+					// no cross-namespace references supported yet
+				},
+			}, &parentRef, nil
+		}
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,336 @@
+package converter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+	operatorv2beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v2beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	"github.com/kong/kong-operator/controller/fullhybrid/converter"
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+// konnectGatewayObjects returns a Gateway named konnect-gateway in the default namespace
+// whose GatewayConfiguration references a KonnectExtension for the cp control plane.
+func konnectGatewayObjects() []client.Object {
+	return []client.Object{
+		&gwtypes.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "konnect-gateway",
+				Namespace: "default",
+			},
+			Spec: gwtypes.GatewaySpec{
+				GatewayClassName: "konnect",
+			},
+		},
+		&gwtypes.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "konnect",
+			},
+			Spec: gatewayv1.GatewayClassSpec{
+				ParametersRef: &gatewayv1.ParametersReference{
+					Group:     gatewayv1.Group(operatorv1beta1.SchemeGroupVersion.Group),
+					Kind:      "GatewayConfiguration",
+					Namespace: lo.ToPtr(gatewayv1.Namespace("default")),
+					Name:      "konnect",
+				},
+			},
+		},
+		&gwtypes.GatewayConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "konnect",
+				Namespace: "default",
+			},
+			Spec: operatorv2beta1.GatewayConfigurationSpec{
+				Extensions: []commonv1alpha1.ExtensionRef{
+					{
+						Group: konnectv1alpha1.SchemeGroupVersion.Group,
+						Kind:  konnectv1alpha2.KonnectExtensionKind,
+						NamespacedRef: commonv1alpha1.NamespacedRef{
+							Name: "konnect",
+						},
+					},
+				},
+			},
+		},
+		&konnectv1alpha2.KonnectExtension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "konnect",
+				Namespace: "default",
+			},
+			Spec: konnectv1alpha2.KonnectExtensionSpec{
+				Konnect: konnectv1alpha2.KonnectExtensionKonnectSpec{
+					ControlPlane: konnectv1alpha2.KonnectExtensionControlPlane{
+						Ref: commonv1alpha1.KonnectExtensionControlPlaneRef{
+							Type: commonv1alpha1.ControlPlaneRefKonnectNamespacedRef,
+							KonnectNamespacedRef: &commonv1alpha1.KonnectNamespacedRef{
+								Name: "cp",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestHTTPRouteConverter(t *testing.T) {
+	objects := append(konnectGatewayObjects(),
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc-a",
+				Namespace: "default",
+			},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "svc-b",
+				Namespace: "default",
+			},
+		},
+	)
+
+	backendRef := func(name string, port int32, weight int32) gwtypes.HTTPBackendRef {
+		return gwtypes.HTTPBackendRef{
+			BackendRef: gwtypes.BackendRef{
+				BackendObjectReference: gwtypes.BackendObjectReference{
+					Name: gwtypes.ObjectName(name),
+					Port: lo.ToPtr(gwtypes.PortNumber(port)),
+				},
+				Weight: lo.ToPtr(weight),
+			},
+		}
+	}
+	httpRoute := func(parent string, rules ...gwtypes.HTTPRouteRule) gwtypes.HTTPRoute {
+		return gwtypes.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "route",
+				Namespace: "default",
+				UID:       "route-uid",
+			},
+			Spec: gwtypes.HTTPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{
+						{Name: gwtypes.ObjectName(parent)},
+					},
+				},
+				Hostnames: []gatewayv1.Hostname{"example.com"},
+				Rules:     rules,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		route       gwtypes.HTTPRoute
+		expectedErr bool
+		assertions  func(t *testing.T, output converter.HTTPRouteOutput)
+	}{
+		{
+			name: "route not attached to a Konnect Gateway",
+			route: httpRoute("other-gateway", gwtypes.HTTPRouteRule{
+				BackendRefs: []gwtypes.HTTPBackendRef{backendRef("svc-a", 80, 1)},
+			}),
+			assertions: func(t *testing.T, output converter.HTTPRouteOutput) {
+				assert.Empty(t, output.KongServices)
+				assert.Empty(t, output.KongRoutes)
+				assert.Empty(t, output.KongUpstreams)
+			},
+		},
+		{
+			name: "path match and header filter",
+			route: httpRoute("konnect-gateway", gwtypes.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{
+					{
+						Path: &gatewayv1.HTTPPathMatch{
+							Type:  lo.ToPtr(gatewayv1.PathMatchPathPrefix),
+							Value: lo.ToPtr("/api/"),
+						},
+						Method: lo.ToPtr(gatewayv1.HTTPMethodGet),
+					},
+				},
+				Filters: []gatewayv1.HTTPRouteFilter{
+					{
+						Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+						RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{
+							Remove: []string{"X-Internal"},
+						},
+					},
+				},
+				BackendRefs: []gwtypes.HTTPBackendRef{
+					backendRef("svc-a", 80, 90),
+					backendRef("svc-b", 8080, 10),
+					backendRef("missing", 80, 10),
+				},
+			}),
+			assertions: func(t *testing.T, output converter.HTTPRouteOutput) {
+				require.Len(t, output.KongUpstreams, 1)
+				require.Len(t, output.KongServices, 1)
+				svc := output.KongServices[0]
+				assert.Equal(t, "route-0", svc.Name)
+				assert.Equal(t, output.KongUpstreams[0].Spec.Name, svc.Spec.Host)
+				require.NotNil(t, svc.Spec.ControlPlaneRef)
+				assert.Equal(t, "cp", svc.Spec.ControlPlaneRef.KonnectNamespacedRef.Name)
+				assert.Equal(t, consts.HTTPRouteManagedByLabelValue, svc.Labels[consts.GatewayOperatorManagedByLabel])
+				require.Len(t, svc.OwnerReferences, 1)
+				assert.Equal(t, "route-uid", string(svc.OwnerReferences[0].UID))
+
+				require.Len(t, output.KongRoutes, 1)
+				route := output.KongRoutes[0]
+				assert.Equal(t, []string{"~/api$", "/api/"}, route.Spec.Paths)
+				assert.Equal(t, []string{"GET"}, route.Spec.Methods)
+				assert.Equal(t, []string{"example.com"}, route.Spec.Hosts)
+				require.NotNil(t, route.Spec.ServiceRef)
+				assert.Equal(t, "route-0", route.Spec.ServiceRef.NamespacedRef.Name)
+
+				require.Len(t, output.KongPlugins, 1)
+				assert.Equal(t, "request-transformer", output.KongPlugins[0].PluginName)
+				assert.JSONEq(t, `{"remove":{"headers":["X-Internal"]}}`, string(output.KongPlugins[0].Config.Raw))
+				require.Len(t, output.KongPluginBindings, 1)
+				binding := output.KongPluginBindings[0]
+				assert.Equal(t, output.KongPlugins[0].Name, binding.Spec.PluginReference.Name)
+				require.NotNil(t, binding.Spec.Targets)
+				require.NotNil(t, binding.Spec.Targets.ServiceReference)
+				assert.Equal(t, "route-0", binding.Spec.Targets.ServiceReference.Name)
+			},
+		},
+		{
+			name: "rule without resolvable backends is skipped",
+			route: httpRoute("konnect-gateway", gwtypes.HTTPRouteRule{
+				BackendRefs: []gwtypes.HTTPBackendRef{backendRef("missing", 80, 1)},
+			}),
+			assertions: func(t *testing.T, output converter.HTTPRouteOutput) {
+				assert.Empty(t, output.KongServices)
+				assert.Empty(t, output.KongRoutes)
+			},
+		},
+		{
+			name: "query parameter matching is not supported",
+			route: httpRoute("konnect-gateway", gwtypes.HTTPRouteRule{
+				Matches: []gatewayv1.HTTPRouteMatch{
+					{
+						QueryParams: []gatewayv1.HTTPQueryParamMatch{
+							{Name: "version", Value: "v1"},
+						},
+					},
+				},
+				BackendRefs: []gwtypes.HTTPBackendRef{backendRef("svc-a", 80, 1)},
+			}),
+			expectedErr: true,
+			assertions: func(t *testing.T, output converter.HTTPRouteOutput) {
+				assert.Empty(t, output.KongServices)
+				assert.Empty(t, output.KongRoutes)
+				assert.Empty(t, output.KongUpstreams)
+			},
+		},
+		{
+			name: "only rules with unsupported filters are skipped",
+			route: httpRoute("konnect-gateway",
+				gwtypes.HTTPRouteRule{
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{
+							Type: gatewayv1.HTTPRouteFilterRequestRedirect,
+							RequestRedirect: &gatewayv1.HTTPRequestRedirectFilter{
+								Hostname: lo.ToPtr(gatewayv1.PreciseHostname("redirect.example.com")),
+							},
+						},
+					},
+					BackendRefs: []gwtypes.HTTPBackendRef{backendRef("svc-a", 80, 1)},
+				},
+				gwtypes.HTTPRouteRule{
+					BackendRefs: []gwtypes.HTTPBackendRef{backendRef("svc-b", 8080, 1)},
+				},
+				gwtypes.HTTPRouteRule{
+					Filters: []gatewayv1.HTTPRouteFilter{
+						{
+							Type: gatewayv1.HTTPRouteFilterURLRewrite,
+							URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+								Hostname: lo.ToPtr(gatewayv1.PreciseHostname("rewrite.example.com")),
+							},
+						},
+					},
+					BackendRefs: []gwtypes.HTTPBackendRef{backendRef("svc-a", 80, 1)},
+				},
+			),
+			expectedErr: true,
+			assertions: func(t *testing.T, output converter.HTTPRouteOutput) {
+				require.Len(t, output.KongUpstreams, 1)
+				assert.Equal(t, "route-1", output.KongUpstreams[0].Name)
+				require.Len(t, output.KongServices, 1)
+				assert.Equal(t, "route-1", output.KongServices[0].Name)
+				require.Len(t, output.KongRoutes, 1)
+				assert.Equal(t, "route-1", output.KongRoutes[0].Spec.ServiceRef.NamespacedRef.Name)
+				assert.Empty(t, output.KongPlugins)
+				assert.Empty(t, output.KongPluginBindings)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(objects...).
+				Build()
+
+			conv := converter.NewHTTPRouteConverter(fakeClient)
+			conv.SetRootObject(tc.route)
+			require.NoError(t, conv.LoadStore(context.Background()))
+
+			err := conv.Translate()
+			if tc.expectedErr {
+				require.ErrorIs(t, err, converter.ErrUnsupportedHTTPRouteFeature)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.assertions != nil {
+				tc.assertions(t, conv.GetOutputStore())
+			}
+		})
+	}
+}
+
+func TestHTTPRouteConverterControlPlaneInAnotherNamespace(t *testing.T) {
+	objects := konnectGatewayObjects()
+	ext, ok := objects[len(objects)-1].(*konnectv1alpha2.KonnectExtension)
+	require.True(t, ok)
+	ext.Spec.Konnect.ControlPlane.Ref.KonnectNamespacedRef.Namespace = "konnect"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(objects...).
+		Build()
+
+	conv := converter.NewHTTPRouteConverter(fakeClient)
+	conv.SetRootObject(gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "route",
+			Namespace: "default",
+		},
+		Spec: gwtypes.HTTPRouteSpec{
+			CommonRouteSpec: gwtypes.CommonRouteSpec{
+				ParentRefs: []gwtypes.ParentReference{
+					{Name: "konnect-gateway"},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, conv.LoadStore(context.Background()), converter.ErrUnsupportedHTTPRouteFeature)
+	require.NotNil(t, conv.KonnectParentRef())
+	require.Equal(t, gwtypes.ObjectName("konnect-gateway"), conv.KonnectParentRef().Name)
+}
@@ -0,0 +1,303 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"strconv"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+const (
+	// targetWeightScale is the weight of all the ready endpoints of a backendRef
+	// with weight 1. Ready endpoints share the backendRef weight evenly, so that
+	// the traffic ratio between the backendRefs of a rule is preserved regardless
+	// of the number of endpoints of each backend.
+	targetWeightScale = 100
+	// maxTargetWeight is the maximum weight accepted by Kong for a target.
+	maxTargetWeight = 65535
+)
+
+var _ APIConverter[corev1.Service] = &serviceConverter{}
+
+// serviceConverter is the APIConverter implementation that keeps the KongTargets of the
+// KongUpstreams generated from HTTPRoute rules in sync with the EndpointSlices of a Service.
+//
+// For every rule of an HTTPRoute attached to a Konnect-backed Gateway which references the
+// Service, it generates a KongTarget for each ready endpoint of the referenced port, in the
+// KongUpstream generated for the rule by the HTTPRoute converter:
+// - ready endpoints share the weight of the backendRef evenly,
+// - terminating endpoints which are still serving get a weight of 0 so that they are drained,
+// - other endpoints are not part of the KongUpstream.
+type serviceConverter struct {
+	client.Client
+
+	service     corev1.Service
+	store       serviceStore
+	outputStore []configurationv1alpha1.KongTarget
+}
+
+type serviceStore struct {
+	backends       []serviceBackend
+	endpointSlices []discoveryv1.EndpointSlice
+}
+
+// serviceBackend is a reference to the Service from the rule of an HTTPRoute
+// attached to a Konnect-backed Gateway.
+type serviceBackend struct {
+	upstreamName string
+	port         int32
+	weight       int32
+}
+
+// NewServiceConverter returns a new instance of serviceConverter.
+func NewServiceConverter(cl client.Client) *serviceConverter {
+	return &serviceConverter{
+		Client: cl,
+	}
+}
+
+// SetRootObject implements APIConverter.
+func (c *serviceConverter) SetRootObject(obj corev1.Service) {
+	c.service = obj
+}
+
+// LoadStore implements APIConverter.
+func (c *serviceConverter) LoadStore(ctx context.Context) error {
+	c.store = serviceStore{}
+
+	// Cross-namespace backendRefs are not supported, hence only the HTTPRoutes
+	// in the Service namespace are considered.
+	var httpRoutes gwtypes.HTTPRouteList
+	if err := c.List(ctx, &httpRoutes, client.InNamespace(c.service.Namespace)); err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	for _, route := range httpRoutes.Items {
+		backends := c.backendsFromHTTPRoute(&route)
+		if len(backends) == 0 {
+			continue
+		}
+		upstreams, err := c.translatedHTTPRouteUpstreams(ctx, route)
+		if err != nil {
+			return err
+		}
+		// KongTargets are generated only for the KongUpstreams which are generated by the
+		// HTTPRoute converter, i.e. not for the rules skipped as unsupported.
+		c.store.backends = append(c.store.backends, lo.Filter(backends, func(b serviceBackend, _ int) bool {
+			return upstreams.Has(b.upstreamName)
+		})...)
+	}
+	if len(c.store.backends) == 0 {
+		return nil
+	}
+
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := c.List(ctx, &endpointSlices,
+		client.InNamespace(c.service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: c.service.Name},
+	); err != nil {
+		return fmt.Errorf("failed to list EndpointSlices: %w", err)
+	}
+	c.store.endpointSlices = endpointSlices.Items
+
+	return nil
+}
+
+// Translate implements APIConverter.
+func (c *serviceConverter) Translate() error {
+	c.outputStore = []configurationv1alpha1.KongTarget{}
+
+	names := sets.New[string]()
+	for _, b := range c.store.backends {
+		servicePort, found := lo.Find(c.service.Spec.Ports, func(p corev1.ServicePort) bool {
+			return p.Port == b.port
+		})
+		if !found {
+			continue
+		}
+
+		endpoints := c.endpointsForPort(servicePort)
+		ready := lo.CountBy(endpoints, func(e endpointTarget) bool { return !e.draining })
+		for _, e := range endpoints {
+			name := targetName(b.upstreamName, e.target)
+			if names.Has(name) {
+				continue
+			}
+			names.Insert(name)
+
+			weight := 0
+			if !e.draining {
+				weight = endpointWeight(b.weight, ready)
+			}
+			c.outputStore = append(c.outputStore, configurationv1alpha1.KongTarget{
+				ObjectMeta: c.objectMeta(name),
+				Spec: configurationv1alpha1.KongTargetSpec{
+					UpstreamRef: commonv1alpha1.NameRef{
+						Name: b.upstreamName,
+					},
+					KongTargetAPISpec: configurationv1alpha1.KongTargetAPISpec{
+						Target: e.target,
+						Weight: weight,
+					},
+				},
+			})
+		}
+	}
+	return nil
+}
+
+// GetOutputStore returns the KongTargets generated by the last Translate call.
+func (c *serviceConverter) GetOutputStore() []configurationv1alpha1.KongTarget {
+	return c.outputStore
+}
+
+// translatedHTTPRouteUpstreams returns the names of the KongUpstreams generated for the HTTPRoute
+// by the HTTPRoute converter. It's empty when the HTTPRoute is not attached to a Konnect-backed
+// Gateway or uses an unsupported feature preventing the generation of any entity.
+func (c *serviceConverter) translatedHTTPRouteUpstreams(ctx context.Context, route gwtypes.HTTPRoute) (sets.Set[string], error) {
+	conv := NewHTTPRouteConverter(c.Client)
+	conv.SetRootObject(route)
+	if err := conv.LoadStore(ctx); err != nil {
+		// No entities are generated for the HTTPRoute, its status reports why.
+		if errors.Is(err, ErrUnsupportedHTTPRouteFeature) {
+			return sets.New[string](), nil
+		}
+		return nil, err
+	}
+	if err := conv.Translate(); err != nil && !errors.Is(err, ErrUnsupportedHTTPRouteFeature) {
+		return nil, err
+	}
+	return sets.New(lo.Map(conv.GetOutputStore().KongUpstreams, func(u configurationv1alpha1.KongUpstream, _ int) string {
+		return u.Name
+	})...), nil
+}
+
+// backendsFromHTTPRoute returns the references to the Service from the rules of the HTTPRoute.
+func (c *serviceConverter) backendsFromHTTPRoute(route *gwtypes.HTTPRoute) []serviceBackend {
+	var backends []serviceBackend
+	for i, rule := range route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			nn, ok := backendServiceName(route, b.BackendObjectReference)
+			if !ok || b.Port == nil || nn.Name != c.service.Name {
+				continue
+			}
+			backends = append(backends, serviceBackend{
+				upstreamName: HTTPRouteRuleUpstreamName(route, i),
+				port:         int32(*b.Port),
+				// Gateway API defaults the weight to 1.
+				weight: lo.FromPtrOr(b.Weight, 1),
+			})
+		}
+	}
+	return backends
+}
+
+type endpointTarget struct {
+	target   string
+	draining bool
+}
+
+// endpointsForPort returns the targets of the endpoints backing the provided Service port.
+func (c *serviceConverter) endpointsForPort(servicePort corev1.ServicePort) []endpointTarget {
+	var targets []endpointTarget
+	for _, slice := range c.store.endpointSlices {
+		if slice.AddressType != discoveryv1.AddressTypeIPv4 && slice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+		// EndpointSlice ports are matched with the Service ports by name.
+		port, found := lo.Find(slice.Ports, func(p discoveryv1.EndpointPort) bool {
+			return lo.FromPtr(p.Name) == servicePort.Name && p.Port != nil
+		})
+		if !found {
+			continue
+		}
+
+		for _, endpoint := range slice.Endpoints {
+			// As per the EndpointSlice API, unknown readiness should be interpreted as ready.
+			ready := lo.FromPtrOr(endpoint.Conditions.Ready, true)
+			serving := lo.FromPtrOr(endpoint.Conditions.Serving, ready)
+			terminating := lo.FromPtr(endpoint.Conditions.Terminating)
+
+			var draining bool
+			switch {
+			case terminating && serving:
+				draining = true
+			case ready && !terminating:
+			default:
+				continue
+			}
+
+			for _, address := range endpoint.Addresses {
+				targets = append(targets, endpointTarget{
+					target:   net.JoinHostPort(address, strconv.Itoa(int(*port.Port))),
+					draining: draining,
+				})
+			}
+		}
+	}
+	return targets
+}
+
+// endpointWeight returns the weight of each of the ready endpoints of a backendRef
+// with the provided weight. Endpoints of a backendRef with a non-zero weight always get
+// a weight of at least 1, even when the share of each endpoint rounds down to 0, so that
+// they keep receiving traffic.
+func endpointWeight(backendWeight int32, readyEndpoints int) int {
+	if backendWeight <= 0 || readyEndpoints <= 0 {
+		return 0
+	}
+	weight := int64(backendWeight) * targetWeightScale / int64(readyEndpoints)
+	return int(max(1, min(weight, maxTargetWeight)))
+}
+
+// targetName returns the name of the KongTarget for the provided target in the provided
+// KongUpstream. The target is hashed as IPv6 addresses are not valid in object names.
+func targetName(upstreamName, target string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(target))
+	return fmt.Sprintf("%s-%08x", upstreamName, h.Sum32())
+}
+
+// objectMeta returns the metadata of a generated object: it lives in the Service
+// namespace, is labeled as managed by the Service and is owned by it.
+func (c *serviceConverter) objectMeta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: c.service.Namespace,
+		Labels:    ServiceManagedByLabels(&c.service),
+		OwnerReferences: []metav1.OwnerReference{
+			{
+				APIVersion:         "v1",
+				Kind:               "Service",
+				Name:               c.service.Name,
+				UID:                c.service.UID,
+				Controller:         lo.ToPtr(true),
+				BlockOwnerDeletion: lo.ToPtr(true),
+			},
+		},
+	}
+}
+
+// ServiceManagedByLabels returns the labels set on the objects generated from the provided Service.
+func ServiceManagedByLabels(service *corev1.Service) map[string]string {
+	return map[string]string{
+		consts.GatewayOperatorManagedByLabel:          consts.ServiceManagedByLabelValue,
+		consts.GatewayOperatorManagedByNameLabel:      service.Name,
+		consts.GatewayOperatorManagedByNamespaceLabel: service.Namespace,
+	}
+}
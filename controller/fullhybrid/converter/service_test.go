@@ -0,0 +1,222 @@
+package converter_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+
+	"github.com/kong/kong-operator/controller/fullhybrid/converter"
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestServiceConverter(t *testing.T) {
+	service := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "svc",
+			Namespace: "default",
+			UID:       "svc-uid",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name: "http",
+					Port: 80,
+				},
+			},
+		},
+	}
+
+	endpoint := func(address string, ready, serving, terminating bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses: []string{address},
+			Conditions: discoveryv1.EndpointConditions{
+				Ready:       lo.ToPtr(ready),
+				Serving:     lo.ToPtr(serving),
+				Terminating: lo.ToPtr(terminating),
+			},
+		}
+	}
+	endpointSlice := func(name string, addressType discoveryv1.AddressType, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					discoveryv1.LabelServiceName: service.Name,
+				},
+			},
+			AddressType: addressType,
+			Ports: []discoveryv1.EndpointPort{
+				{
+					Name: lo.ToPtr("http"),
+					Port: lo.ToPtr(int32(8080)),
+				},
+			},
+			Endpoints: endpoints,
+		}
+	}
+	httpRoute := func(parent string, weight int32) *gwtypes.HTTPRoute {
+		return &gwtypes.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "route",
+				Namespace: "default",
+			},
+			Spec: gwtypes.HTTPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{
+						{Name: gwtypes.ObjectName(parent)},
+					},
+				},
+				Rules: []gwtypes.HTTPRouteRule{
+					{
+						BackendRefs: []gwtypes.HTTPBackendRef{
+							{
+								BackendRef: gwtypes.BackendRef{
+									BackendObjectReference: gwtypes.BackendObjectReference{
+										Name: gwtypes.ObjectName(service.Name),
+										Port: lo.ToPtr(gwtypes.PortNumber(80)),
+									},
+									Weight: lo.ToPtr(weight),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	withUnsupportedRule := func(route *gwtypes.HTTPRoute) *gwtypes.HTTPRoute {
+		rule := *route.Spec.Rules[0].DeepCopy()
+		rule.Matches = []gatewayv1.HTTPRouteMatch{
+			{
+				QueryParams: []gatewayv1.HTTPQueryParamMatch{
+					{Name: "version", Value: "v2"},
+				},
+			},
+		}
+		route.Spec.Rules = append(route.Spec.Rules, rule)
+		return route
+	}
+
+	testCases := []struct {
+		name            string
+		objects         []client.Object
+		expectedTargets map[string]int
+	}{
+		{
+			name: "route not attached to a Konnect Gateway",
+			objects: []client.Object{
+				httpRoute("other-gateway", 1),
+				endpointSlice("svc-1", discoveryv1.AddressTypeIPv4,
+					endpoint("10.0.0.1", true, true, false),
+				),
+			},
+			expectedTargets: map[string]int{},
+		},
+		{
+			name: "ready endpoints share the backendRef weight and terminating ones are drained",
+			objects: []client.Object{
+				httpRoute("konnect-gateway", 3),
+				endpointSlice("svc-1", discoveryv1.AddressTypeIPv4,
+					endpoint("10.0.0.1", true, true, false),
+					endpoint("10.0.0.2", true, true, false),
+					endpoint("10.0.0.3", false, true, true),
+					endpoint("10.0.0.4", false, false, false),
+					endpoint("10.0.0.5", false, false, true),
+				),
+				endpointSlice("svc-2", discoveryv1.AddressTypeIPv6,
+					endpoint("fd00::1", true, true, false),
+				),
+				endpointSlice("svc-3", discoveryv1.AddressTypeFQDN,
+					endpoint("pod.example.com", true, true, false),
+				),
+			},
+			expectedTargets: map[string]int{
+				"10.0.0.1:8080":  100,
+				"10.0.0.2:8080":  100,
+				"[fd00::1]:8080": 100,
+				"10.0.0.3:8080":  0,
+			},
+		},
+		{
+			name: "backendRef with weight 0 gets no traffic",
+			objects: []client.Object{
+				httpRoute("konnect-gateway", 0),
+				endpointSlice("svc-1", discoveryv1.AddressTypeIPv4,
+					endpoint("10.0.0.1", true, true, false),
+				),
+			},
+			expectedTargets: map[string]int{
+				"10.0.0.1:8080": 0,
+			},
+		},
+		{
+			name: "endpoints of a backendRef with non-zero weight get a weight of at least 1",
+			objects: []client.Object{
+				httpRoute("konnect-gateway", 1),
+				endpointSlice("svc-1", discoveryv1.AddressTypeIPv4,
+					lo.Times(101, func(i int) discoveryv1.Endpoint {
+						return endpoint(fmt.Sprintf("10.0.%d.%d", i/250, i%250+1), true, true, false)
+					})...,
+				),
+			},
+			expectedTargets: lo.SliceToMap(lo.Range(101), func(i int) (string, int) {
+				return fmt.Sprintf("10.0.%d.%d:8080", i/250, i%250+1), 1
+			}),
+		},
+		{
+			name: "no targets are generated for rules skipped as unsupported",
+			objects: []client.Object{
+				withUnsupportedRule(httpRoute("konnect-gateway", 1)),
+				endpointSlice("svc-1", discoveryv1.AddressTypeIPv4,
+					endpoint("10.0.0.1", true, true, false),
+				),
+			},
+			expectedTargets: map[string]int{
+				"10.0.0.1:8080": 100,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(append(konnectGatewayObjects(), append(tc.objects, service.DeepCopy())...)...).
+				Build()
+
+			conv := converter.NewServiceConverter(fakeClient)
+			conv.SetRootObject(service)
+			require.NoError(t, conv.LoadStore(context.Background()))
+			require.NoError(t, conv.Translate())
+
+			targets := conv.GetOutputStore()
+			actual := lo.SliceToMap(targets, func(kt configurationv1alpha1.KongTarget) (string, int) {
+				return kt.Spec.Target, kt.Spec.Weight
+			})
+			assert.Equal(t, tc.expectedTargets, actual)
+			assert.Len(t, targets, len(tc.expectedTargets))
+			for _, kt := range targets {
+				assert.Equal(t, "route-0", kt.Spec.UpstreamRef.Name)
+				assert.Equal(t, consts.ServiceManagedByLabelValue, kt.Labels[consts.GatewayOperatorManagedByLabel])
+				require.Len(t, kt.OwnerReferences, 1)
+				assert.Equal(t, service.UID, kt.OwnerReferences[0].UID)
+			}
+		})
+	}
+}
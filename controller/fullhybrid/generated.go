@@ -0,0 +1,128 @@
+package fullhybrid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/controller/pkg/patch"
+)
+
+// ensureObjects creates the desired objects which do not exist yet and patches the
+// existing ones using the provided mutate function.
+// Existing objects which don't carry the desired managed-by labels (e.g. created by
+// users) are left untouched and reported in the returned error, while the other
+// desired objects are still enforced.
+func ensureObjects[T interface {
+	client.Object
+	DeepCopy() T
+}](
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	desired []T,
+	mutate func(existing, desired T),
+) error {
+	var conflicts []error
+	for _, d := range desired {
+		existing := d.DeepCopy()
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(d), existing); err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return fmt.Errorf("failed to get %T %s: %w", d, client.ObjectKeyFromObject(d), err)
+			}
+			if err := cl.Create(ctx, d); err != nil {
+				return fmt.Errorf("failed to create %T %s: %w", d, client.ObjectKeyFromObject(d), err)
+			}
+			log.Debug(logger, "generated object created", "kind", fmt.Sprintf("%T", d), "name", d.GetName())
+			continue
+		}
+
+		if !hasLabels(existing, d.GetLabels()) {
+			conflicts = append(conflicts, fmt.Errorf("%T %s already exists and is not managed by the operator",
+				d, client.ObjectKeyFromObject(d)))
+			continue
+		}
+
+		old := existing.DeepCopy()
+		mutate(existing, d)
+		existing.SetLabels(lo.Assign(existing.GetLabels(), d.GetLabels()))
+		// An object can have only one controller, so the desired owner (e.g. a recreated
+		// HTTPRoute with the same name) replaces the existing one.
+		existing.SetOwnerReferences(append(d.GetOwnerReferences(), lo.Reject(existing.GetOwnerReferences(),
+			func(ref metav1.OwnerReference, _ int) bool {
+				return lo.FromPtr(ref.Controller) || lo.ContainsBy(d.GetOwnerReferences(), func(desiredRef metav1.OwnerReference) bool {
+					return desiredRef.UID == ref.UID
+				})
+			},
+		)...))
+		if _, _, err := patch.ApplyPatchIfNotEmpty(ctx, cl, logger, existing, old, true); err != nil {
+			return err
+		}
+	}
+	return errors.Join(conflicts...)
+}
+
+// hasLabels returns true when the provided object has all the provided labels.
+func hasLabels(obj client.Object, labels map[string]string) bool {
+	objLabels := obj.GetLabels()
+	for k, v := range labels {
+		if l, ok := objLabels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteStaleObjects deletes the generated objects matching the provided labels which
+// are controlled by the provided owner and are not part of the desired set anymore.
+func deleteStaleObjects(
+	ctx context.Context,
+	cl client.Client,
+	logger logr.Logger,
+	owner client.Object,
+	managedByLabels map[string]string,
+	list client.ObjectList,
+	desired []string,
+) error {
+	if err := cl.List(ctx, list,
+		client.InNamespace(owner.GetNamespace()),
+		client.MatchingLabels(managedByLabels),
+	); err != nil {
+		return fmt.Errorf("failed to list %T: %w", list, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return fmt.Errorf("failed to extract items from %T: %w", list, err)
+	}
+
+	desiredNames := sets.New(desired...)
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || desiredNames.Has(obj.GetName()) || !obj.GetDeletionTimestamp().IsZero() || !metav1.IsControlledBy(obj, owner) {
+			continue
+		}
+		if err := cl.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete %T %s: %w", obj, client.ObjectKeyFromObject(obj), err)
+		}
+		log.Debug(logger, "stale generated object deleted", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName())
+	}
+	return nil
+}
+
+func objectNames[T any, TPtr interface {
+	*T
+	client.Object
+}](objs []T) []string {
+	return lo.Map(objs, func(obj T, _ int) string {
+		return TPtr(&obj).GetName()
+	})
+}
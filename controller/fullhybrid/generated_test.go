@@ -0,0 +1,106 @@
+package fullhybrid
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestEnsureObjectsAndDeleteStaleObjects(t *testing.T) {
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "route",
+			Namespace: "default",
+			UID:       "route-uid",
+		},
+	}
+	managedByLabels := map[string]string{
+		consts.GatewayOperatorManagedByLabel:          consts.HTTPRouteManagedByLabelValue,
+		consts.GatewayOperatorManagedByNameLabel:      route.Name,
+		consts.GatewayOperatorManagedByNamespaceLabel: route.Namespace,
+	}
+	controllerRef := func(uid types.UID) metav1.OwnerReference {
+		return metav1.OwnerReference{
+			APIVersion: gwtypes.GroupVersion.String(),
+			Kind:       "HTTPRoute",
+			Name:       route.Name,
+			UID:        uid,
+			Controller: lo.ToPtr(true),
+		}
+	}
+	service := func(name, host string, labels map[string]string, ownerRefs ...metav1.OwnerReference) *configurationv1alpha1.KongService {
+		return &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       route.Namespace,
+				Labels:          labels,
+				OwnerReferences: ownerRefs,
+			},
+			Spec: configurationv1alpha1.KongServiceSpec{
+				KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+					Host: host,
+				},
+			},
+		}
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			service("user", "user.example.com", map[string]string{"app": "user"}),
+			// Generated for a previous HTTPRoute with the same name.
+			service("managed", "old.example.com", managedByLabels, controllerRef("old-route-uid")),
+			service("user-labeled", "user.example.com", managedByLabels),
+		).
+		Build()
+
+	ctx := t.Context()
+	err := ensureObjects(ctx, fakeClient, logr.Discard(), []*configurationv1alpha1.KongService{
+		service("user", "route.example.com", managedByLabels, controllerRef(route.UID)),
+		service("managed", "route.example.com", managedByLabels, controllerRef(route.UID)),
+		service("new", "route.example.com", managedByLabels, controllerRef(route.UID)),
+	}, func(existing, desired *configurationv1alpha1.KongService) {
+		existing.Spec = desired.Spec
+	})
+	require.ErrorContains(t, err, "not managed by the operator")
+
+	get := func(name string) configurationv1alpha1.KongService {
+		var svc configurationv1alpha1.KongService
+		require.NoError(t, fakeClient.Get(ctx, client.ObjectKey{Namespace: route.Namespace, Name: name}, &svc))
+		return svc
+	}
+
+	user := get("user")
+	assert.Equal(t, "user.example.com", user.Spec.Host, "objects not managed by the operator should not be adopted")
+	assert.Empty(t, user.OwnerReferences)
+
+	managed := get("managed")
+	assert.Equal(t, "route.example.com", managed.Spec.Host)
+	require.Len(t, managed.OwnerReferences, 1, "an object can have only one controller")
+	assert.Equal(t, route.UID, managed.OwnerReferences[0].UID)
+
+	assert.Equal(t, "route.example.com", get("new").Spec.Host)
+
+	// Only the objects controlled by the HTTPRoute are deleted when stale.
+	require.NoError(t, deleteStaleObjects(ctx, fakeClient, logr.Discard(), route, managedByLabels,
+		&configurationv1alpha1.KongServiceList{}, []string{"managed"},
+	))
+	var services configurationv1alpha1.KongServiceList
+	require.NoError(t, fakeClient.List(ctx, &services, client.InNamespace(route.Namespace)))
+	assert.ElementsMatch(t, []string{"user", "managed", "user-labeled"}, lo.Map(services.Items, func(s configurationv1alpha1.KongService, _ int) string {
+		return s.Name
+	}))
+}
@@ -0,0 +1,165 @@
+package fullhybrid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+	operatorv2beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v2beta1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	"github.com/kong/kong-operator/controller/fullhybrid/converter"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/logging"
+)
+
+// HTTPRouteReconciler reconciles HTTPRoutes attached to Gateways backed by a Konnect
+// control plane into KongServices, KongRoutes, KongUpstreams, KongPlugins and
+// KongPluginBindings. The generated objects are owned by the HTTPRoute and the ones
+// which are not generated anymore are deleted.
+// Unsupported features are reported in the status of the Konnect-backed parent of the HTTPRoute.
+// The KongTargets of the generated KongUpstreams are managed by the ServiceReconciler.
+type HTTPRouteReconciler struct {
+	client.Client
+	CacheSyncTimeout time.Duration
+	LoggingMode      logging.Mode
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HTTPRouteReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("fullhybrid-httproute").
+		WithOptions(controller.Options{
+			CacheSyncTimeout: r.CacheSyncTimeout,
+		}).
+		For(&gwtypes.HTTPRoute{}).
+		Owns(&configurationv1alpha1.KongService{}).
+		Owns(&configurationv1alpha1.KongRoute{}).
+		Owns(&configurationv1alpha1.KongUpstream{}).
+		Owns(&configurationv1.KongPlugin{}).
+		Owns(&configurationv1alpha1.KongPluginBinding{}).
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.listHTTPRoutesForService),
+		).
+		Watches(
+			&gwtypes.Gateway{},
+			handler.EnqueueRequestsFromMapFunc(r.listHTTPRoutesForGateway),
+		).
+		Watches(
+			&operatorv2beta1.GatewayConfiguration{},
+			handler.EnqueueRequestsFromMapFunc(r.listHTTPRoutesForGatewayConfiguration),
+		).
+		Watches(
+			&konnectv1alpha2.KonnectExtension{},
+			handler.EnqueueRequestsFromMapFunc(r.listHTTPRoutesForKonnectExtension),
+		).
+		Complete(r)
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "fullhybrid-httproute", r.LoggingMode)
+
+	var route gwtypes.HTTPRoute
+	if err := r.Get(ctx, req.NamespacedName, &route); err != nil {
+		// Generated objects of deleted HTTPRoutes are garbage collected through owner references.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !route.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	log.Trace(logger, "reconciling HTTPRoute")
+
+	conv := converter.NewHTTPRouteConverter(r.Client)
+	conv.SetRootObject(route)
+	// Errors caused by unsupported features are reported in the HTTPRoute status and
+	// are not retried: that wouldn't help until the HTTPRoute or its dependencies change.
+	var output converter.HTTPRouteOutput
+	loadErr := conv.LoadStore(ctx)
+	if loadErr != nil {
+		if !errors.Is(loadErr, converter.ErrUnsupportedHTTPRouteFeature) {
+			return ctrl.Result{}, fmt.Errorf("failed to load store for HTTPRoute: %w", loadErr)
+		}
+		// No entities can be generated, the previously generated ones are deleted below.
+		log.Info(logger, "HTTPRoute not accepted", "reason", loadErr.Error())
+	}
+	var translateErr error
+	if loadErr == nil {
+		if translateErr = conv.Translate(); translateErr != nil {
+			if !errors.Is(translateErr, converter.ErrUnsupportedHTTPRouteFeature) {
+				return ctrl.Result{}, fmt.Errorf("failed to translate HTTPRoute: %w", translateErr)
+			}
+			// Rules using unsupported features are skipped, the entities of the other
+			// rules are still enforced.
+			log.Info(logger, "HTTPRoute rules skipped", "reason", translateErr.Error())
+		}
+		output = conv.GetOutputStore()
+	}
+
+	// Objects are enforced in dependency order so that references can be resolved as
+	// soon as possible by the Konnect controllers.
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(output.KongUpstreams), func(existing, desired *configurationv1alpha1.KongUpstream) {
+		existing.Spec = desired.Spec
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(output.KongServices), func(existing, desired *configurationv1alpha1.KongService) {
+		existing.Spec = desired.Spec
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(output.KongRoutes), func(existing, desired *configurationv1alpha1.KongRoute) {
+		existing.Spec = desired.Spec
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(output.KongPlugins), func(existing, desired *configurationv1.KongPlugin) {
+		existing.PluginName = desired.PluginName
+		existing.Config = desired.Config
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(output.KongPluginBindings), func(existing, desired *configurationv1alpha1.KongPluginBinding) {
+		existing.Spec = desired.Spec
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Stale objects are deleted in reverse dependency order.
+	for _, stale := range []struct {
+		list    client.ObjectList
+		desired []string
+	}{
+		{&configurationv1alpha1.KongPluginBindingList{}, objectNames(output.KongPluginBindings)},
+		{&configurationv1.KongPluginList{}, objectNames(output.KongPlugins)},
+		{&configurationv1alpha1.KongRouteList{}, objectNames(output.KongRoutes)},
+		{&configurationv1alpha1.KongServiceList{}, objectNames(output.KongServices)},
+		{&configurationv1alpha1.KongUpstreamList{}, objectNames(output.KongUpstreams)},
+	} {
+		if err := deleteStaleObjects(ctx, r.Client, logger, &route, converter.HTTPRouteManagedByLabels(&route), stale.list, stale.desired); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if parentRef := conv.KonnectParentRef(); parentRef != nil {
+		if err := enforceHTTPRouteParentStatus(ctx, r.Client, &route, *parentRef, loadErr, translateErr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	log.Debug(logger, "HTTPRoute reconciled")
+	return ctrl.Result{}, nil
+}
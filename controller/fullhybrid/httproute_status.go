@@ -0,0 +1,83 @@
+package fullhybrid
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/pkg/vars"
+)
+
+// enforceHTTPRouteParentStatus sets the conditions of the HTTPRoute status for the provided
+// Konnect-backed parentRef:
+//   - Accepted is False with the UnsupportedValue reason when loadErr is set (e.g. the
+//     control plane cannot be referenced from the HTTPRoute namespace), True otherwise,
+//   - PartiallyInvalid is True with the UnsupportedValue reason when translateErr is set
+//     (i.e. some rules were skipped), and it's removed otherwise.
+//
+// The status is patched only when it changes.
+func enforceHTTPRouteParentStatus(
+	ctx context.Context,
+	cl client.Client,
+	route *gwtypes.HTTPRoute,
+	parentRef gwtypes.ParentReference,
+	loadErr error,
+	translateErr error,
+) error {
+	old := route.DeepCopy()
+
+	idx := -1
+	for i, p := range route.Status.Parents {
+		if string(p.ControllerName) == vars.ControllerName() && reflect.DeepEqual(p.ParentRef, parentRef) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		route.Status.Parents = append(route.Status.Parents, gatewayv1.RouteParentStatus{
+			ParentRef:      parentRef,
+			ControllerName: gatewayv1.GatewayController(vars.ControllerName()),
+		})
+		idx = len(route.Status.Parents) - 1
+	}
+	parentStatus := &route.Status.Parents[idx]
+
+	accepted := metav1.Condition{
+		Type:               string(gatewayv1.RouteConditionAccepted),
+		Status:             metav1.ConditionTrue,
+		Reason:             string(gatewayv1.RouteReasonAccepted),
+		ObservedGeneration: route.Generation,
+	}
+	if loadErr != nil {
+		accepted.Status = metav1.ConditionFalse
+		accepted.Reason = string(gatewayv1.RouteReasonUnsupportedValue)
+		accepted.Message = loadErr.Error()
+	}
+	meta.SetStatusCondition(&parentStatus.Conditions, accepted)
+
+	if translateErr != nil {
+		meta.SetStatusCondition(&parentStatus.Conditions, metav1.Condition{
+			Type:               string(gatewayv1.RouteConditionPartiallyInvalid),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gatewayv1.RouteReasonUnsupportedValue),
+			Message:            translateErr.Error(),
+			ObservedGeneration: route.Generation,
+		})
+	} else {
+		meta.RemoveStatusCondition(&parentStatus.Conditions, string(gatewayv1.RouteConditionPartiallyInvalid))
+	}
+
+	if reflect.DeepEqual(old.Status, route.Status) {
+		return nil
+	}
+	if err := cl.Status().Patch(ctx, route, client.MergeFrom(old)); err != nil {
+		return fmt.Errorf("failed to patch HTTPRoute status: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package fullhybrid
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/kong-operator/controller/fullhybrid/converter"
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+)
+
+func TestEnforceHTTPRouteParentStatus(t *testing.T) {
+	ctx := context.Background()
+	parentRef := gwtypes.ParentReference{Name: "konnect-gateway"}
+	route := &gwtypes.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "route",
+			Namespace:  "default",
+			Generation: 2,
+		},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(route).
+		WithStatusSubresource(route).
+		Build()
+
+	getConditions := func(t *testing.T) []metav1.Condition {
+		t.Helper()
+		var current gwtypes.HTTPRoute
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(route), &current))
+		require.Len(t, current.Status.Parents, 1)
+		require.Equal(t, parentRef, current.Status.Parents[0].ParentRef)
+		return current.Status.Parents[0].Conditions
+	}
+
+	t.Run("unsupported rules set PartiallyInvalid", func(t *testing.T) {
+		translateErr := fmt.Errorf("%w: rule 1", converter.ErrUnsupportedHTTPRouteFeature)
+		require.NoError(t, enforceHTTPRouteParentStatus(ctx, cl, route, parentRef, nil, translateErr))
+
+		conditions := getConditions(t)
+		require.True(t, meta.IsStatusConditionTrue(conditions, string(gatewayv1.RouteConditionAccepted)))
+		partiallyInvalid := meta.FindStatusCondition(conditions, string(gatewayv1.RouteConditionPartiallyInvalid))
+		require.NotNil(t, partiallyInvalid)
+		require.Equal(t, metav1.ConditionTrue, partiallyInvalid.Status)
+		require.Equal(t, string(gatewayv1.RouteReasonUnsupportedValue), partiallyInvalid.Reason)
+		require.Equal(t, int64(2), partiallyInvalid.ObservedGeneration)
+	})
+
+	t.Run("unsupported control plane sets Accepted to False", func(t *testing.T) {
+		loadErr := fmt.Errorf("%w: control plane in another namespace", converter.ErrUnsupportedHTTPRouteFeature)
+		require.NoError(t, enforceHTTPRouteParentStatus(ctx, cl, route, parentRef, loadErr, nil))
+
+		conditions := getConditions(t)
+		accepted := meta.FindStatusCondition(conditions, string(gatewayv1.RouteConditionAccepted))
+		require.NotNil(t, accepted)
+		require.Equal(t, metav1.ConditionFalse, accepted.Status)
+		require.Equal(t, string(gatewayv1.RouteReasonUnsupportedValue), accepted.Reason)
+		require.Nil(t, meta.FindStatusCondition(conditions, string(gatewayv1.RouteConditionPartiallyInvalid)))
+	})
+
+	t.Run("supported HTTPRoute is accepted", func(t *testing.T) {
+		require.NoError(t, enforceHTTPRouteParentStatus(ctx, cl, route, parentRef, nil, nil))
+
+		conditions := getConditions(t)
+		require.Len(t, conditions, 1)
+		require.True(t, meta.IsStatusConditionTrue(conditions, string(gatewayv1.RouteConditionAccepted)))
+	})
+}
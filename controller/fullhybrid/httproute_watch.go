@@ -0,0 +1,101 @@
+package fullhybrid
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	operatorv2beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v2beta1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/internal/utils/index"
+)
+
+// -----------------------------------------------------------------------------
+// HTTPRouteReconciler - Watch Map Funcs
+// -----------------------------------------------------------------------------
+
+// listHTTPRoutesForService returns the HTTPRoutes referencing the Service in their backendRefs.
+func (r *HTTPRouteReconciler) listHTTPRoutesForService(ctx context.Context, obj client.Object) []reconcile.Request {
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return nil
+	}
+	return r.listHTTPRoutesMatchingField(ctx, index.BackendServiceIndex, client.ObjectKeyFromObject(svc).String())
+}
+
+// listHTTPRoutesForGateway returns the HTTPRoutes attached to the Gateway.
+func (r *HTTPRouteReconciler) listHTTPRoutesForGateway(ctx context.Context, obj client.Object) []reconcile.Request {
+	gateway, ok := obj.(*gwtypes.Gateway)
+	if !ok {
+		return nil
+	}
+	return r.listHTTPRoutesMatchingField(ctx, index.ParentGatewayIndex, client.ObjectKeyFromObject(gateway).String())
+}
+
+// listHTTPRoutesForGatewayConfiguration returns the HTTPRoutes attached to the Gateways
+// of the GatewayClasses using the GatewayConfiguration.
+func (r *HTTPRouteReconciler) listHTTPRoutesForGatewayConfiguration(ctx context.Context, obj client.Object) []reconcile.Request {
+	gatewayConfig, ok := obj.(*operatorv2beta1.GatewayConfiguration)
+	if !ok {
+		return nil
+	}
+	logger := ctrllog.FromContext(ctx)
+
+	var gatewayClasses gwtypes.GatewayClassList
+	if err := r.List(ctx, &gatewayClasses, client.MatchingFields{
+		index.GatewayConfigurationOnGatewayClassIndex: client.ObjectKeyFromObject(gatewayConfig).String(),
+	}); err != nil {
+		logger.Error(err, "Failed to list GatewayClasses in watch", "kind", "GatewayConfiguration")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, gatewayClass := range gatewayClasses.Items {
+		var gateways gwtypes.GatewayList
+		if err := r.List(ctx, &gateways, client.MatchingFields{
+			index.GatewayClassOnGatewayIndex: gatewayClass.Name,
+		}); err != nil {
+			logger.Error(err, "Failed to list Gateways in watch", "kind", "GatewayConfiguration")
+			return nil
+		}
+		for _, gateway := range gateways.Items {
+			requests = append(requests, r.listHTTPRoutesForGateway(ctx, &gateway)...)
+		}
+	}
+	return lo.Uniq(requests)
+}
+
+// listHTTPRoutesForKonnectExtension returns the HTTPRoutes attached to the Gateways
+// configured through the GatewayConfigurations referencing the KonnectExtension.
+func (r *HTTPRouteReconciler) listHTTPRoutesForKonnectExtension(ctx context.Context, obj client.Object) []reconcile.Request {
+	ext, ok := obj.(*konnectv1alpha2.KonnectExtension)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	listGatewayConfigs := index.ListObjectsReferencingKonnectExtension(r.Client, &operatorv2beta1.GatewayConfigurationList{})
+	for _, req := range listGatewayConfigs(ctx, ext) {
+		gatewayConfig := &operatorv2beta1.GatewayConfiguration{}
+		gatewayConfig.Namespace, gatewayConfig.Name = req.Namespace, req.Name
+		requests = append(requests, r.listHTTPRoutesForGatewayConfiguration(ctx, gatewayConfig)...)
+	}
+	return lo.Uniq(requests)
+}
+
+func (r *HTTPRouteReconciler) listHTTPRoutesMatchingField(ctx context.Context, field, value string) []reconcile.Request {
+	var routes gwtypes.HTTPRouteList
+	if err := r.List(ctx, &routes, client.MatchingFields{field: value}); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Failed to list HTTPRoutes in watch", "index", field)
+		return nil
+	}
+	return lo.Map(routes.Items, func(route gwtypes.HTTPRoute, _ int) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&route)}
+	})
+}
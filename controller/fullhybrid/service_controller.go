@@ -0,0 +1,136 @@
+package fullhybrid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+
+	"github.com/kong/kong-operator/controller/fullhybrid/converter"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/logging"
+)
+
+// ServiceReconciler keeps the KongTargets of the KongUpstreams generated from HTTPRoute
+// rules in sync with the EndpointSlices of the referenced Services. The generated
+// KongTargets are owned by the Service and the ones which are not generated anymore
+// are deleted.
+type ServiceReconciler struct {
+	client.Client
+	CacheSyncTimeout time.Duration
+	LoggingMode      logging.Mode
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ServiceReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("fullhybrid-service").
+		WithOptions(controller.Options{
+			CacheSyncTimeout: r.CacheSyncTimeout,
+		}).
+		For(&corev1.Service{}).
+		Owns(&configurationv1alpha1.KongTarget{}).
+		Watches(
+			&discoveryv1.EndpointSlice{},
+			handler.EnqueueRequestsFromMapFunc(r.listServicesForEndpointSlice),
+		).
+		Watches(
+			&gwtypes.HTTPRoute{},
+			handler.EnqueueRequestsFromMapFunc(r.listServicesForHTTPRoute),
+		).
+		Complete(r)
+}
+
+// Reconcile moves the current state of an object to the intended state.
+func (r *ServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.GetLogger(ctx, "fullhybrid-service", r.LoggingMode)
+
+	var service corev1.Service
+	if err := r.Get(ctx, req.NamespacedName, &service); err != nil {
+		// Generated objects of deleted Services are garbage collected through owner references.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !service.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	log.Trace(logger, "reconciling Service")
+
+	conv := converter.NewServiceConverter(r.Client)
+	conv.SetRootObject(service)
+	if err := conv.LoadStore(ctx); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to load store for Service: %w", err)
+	}
+	if err := conv.Translate(); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to translate Service: %w", err)
+	}
+	targets := conv.GetOutputStore()
+
+	if err := ensureObjects(ctx, r.Client, logger, lo.ToSlicePtr(targets), func(existing, desired *configurationv1alpha1.KongTarget) {
+		existing.Spec = desired.Spec
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := deleteStaleObjects(ctx, r.Client, logger,
+		&service, converter.ServiceManagedByLabels(&service),
+		&configurationv1alpha1.KongTargetList{}, objectNames(targets),
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.Debug(logger, "Service reconciled")
+	return ctrl.Result{}, nil
+}
+
+func (r *ServiceReconciler) listServicesForEndpointSlice(_ context.Context, obj client.Object) []reconcile.Request {
+	serviceName, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Namespace: obj.GetNamespace(),
+				Name:      serviceName,
+			},
+		},
+	}
+}
+
+func (r *ServiceReconciler) listServicesForHTTPRoute(_ context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gwtypes.HTTPRoute)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, rule := range route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			// Cross-namespace backendRefs are not supported.
+			if (b.Group != nil && *b.Group != "") ||
+				(b.Kind != nil && *b.Kind != "Service") ||
+				(b.Namespace != nil && string(*b.Namespace) != route.Namespace) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: route.Namespace,
+					Name:      string(b.Name),
+				},
+			})
+		}
+	}
+	return lo.Uniq(requests)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -108,10 +109,34 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 			&gatewayv1beta1.ReferenceGrant{},
 			handler.EnqueueRequestsFromMapFunc(r.listReferenceGrantsForGateway),
 			builder.WithPredicates(ref.ReferenceGrantForSecretFrom(gatewayv1.GroupName, gatewayv1beta1.Kind("Gateway")))).
+		// watch ReferenceGrants allowing routes to reference Services in other namespaces, so that
+		// the DataPlane's egress derived from the routes' backends follows the granted backends.
+		Watches(
+			&gatewayv1beta1.ReferenceGrant{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForBackendReferenceGrant)).
 		// watch HTTPRoutes so that Gateway listener status can be updated.
 		Watches(
 			&gatewayv1beta1.HTTPRoute{},
-			handler.EnqueueRequestsFromMapFunc(r.listGatewaysAttachedByHTTPRoute)).
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysAttachedByRoute)).
+		// watch Services so that the DataPlane's egress derived from the routes' backends
+		// follows the changes of their selectors and ports.
+		Watches(
+			&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForBackendService)).
+		// watch EndpointSlices of Services without a selector, as the DataPlane's egress to them
+		// is derived from their endpoints' addresses.
+		Watches(
+			&discoveryv1.EndpointSlice{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForBackendEndpointSlice),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isEndpointSliceOfServiceWithoutSelector))).
+		// watch ConfigMaps and Secrets referenced by the env and envFrom of the DataPlanes' proxy
+		// container, so that the network policies follow the listen ports configured through them.
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForProxyEnvConfigMap)).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysForProxyEnvSecret)).
 		// watch Namespaces so that managed routes have correct status reflected in Gateway's
 		// status in status.listeners.attachedRoutes
 		// This is required to properly support Gateway's listeners.allowedRoutes.namespaces.selector.
@@ -119,6 +144,22 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 			&corev1.Namespace{},
 			handler.EnqueueRequestsFromMapFunc(r.listManagedGatewaysInNamespace))
 
+	// watch the routes of the other kinds so that the DataPlane's egress derived from
+	// the routes' backends follows their changes, when their CRDs are installed.
+	checker := k8sutils.CRDChecker{Client: mgr.GetClient()}
+	for _, route := range optionalBackendRoutes {
+		ok, err := checker.CRDExists(route.resource)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		builder.Watches(
+			route.newObject(),
+			handler.EnqueueRequestsFromMapFunc(r.listGatewaysAttachedByRoute))
+	}
+
 	if r.KonnectEnabled {
 		// Watch for changes in KonnectExtension objects that are referenced by GatewayConfigurations used by Gateways objects.
 		// They may trigger reconciliation of DataPlane resources.
@@ -399,7 +440,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	// If the code is run outside of k8s (like in envtest or integration test), do not create network policies.
 	if k8sutils.RunningOnKubernetes() {
 		log.Trace(logger, "ensuring DataPlane's NetworkPolicy exists")
-		createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, &gateway, dataplane)
+		createdOrUpdated, err := r.ensureDataPlaneHasNetworkPolicy(ctx, &gateway, dataplane, gatewayConfig)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
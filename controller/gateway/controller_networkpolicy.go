@@ -0,0 +1,1165 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/pkg/consts"
+	gatewayutils "github.com/kong/kong-operator/pkg/utils/gateway"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+	k8sreduce "github.com/kong/kong-operator/pkg/utils/kubernetes/reduce"
+	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
+)
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - DataPlane NetworkPolicy
+// -----------------------------------------------------------------------------
+
+const (
+	// networkPolicyKindNetworkPolicy generates a Kubernetes NetworkPolicy for the DataPlane.
+	networkPolicyKindNetworkPolicy = "NetworkPolicy"
+	// networkPolicyKindCiliumNetworkPolicy generates a CiliumNetworkPolicy for the DataPlane.
+	networkPolicyKindCiliumNetworkPolicy = "CiliumNetworkPolicy"
+
+	// namespaceNameLabel is the label set by Kubernetes on all namespaces with their name.
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+// ciliumNetworkPolicyGVK is the GroupVersionKind of CiliumNetworkPolicies. They're managed
+// as unstructured objects, so that the operator doesn't depend on Cilium's API.
+var ciliumNetworkPolicyGVK = schema.GroupVersionKind{
+	Group:   "cilium.io",
+	Version: "v2",
+	Kind:    "CiliumNetworkPolicy",
+}
+
+// dataPlaneNetworkPolicyConfig is the configuration of the policy generated for a Gateway's
+// DataPlane, read from the annotations of the GatewayConfiguration.
+type dataPlaneNetworkPolicyConfig struct {
+	// Kind is the kind of the generated policy.
+	Kind string `json:"-"`
+	// Ingress are the ingress rules added to the generated policy.
+	Ingress []networkingv1.NetworkPolicyIngressRule `json:"ingress,omitempty"`
+	// Egress are the egress rules added to the generated policy. The DataPlane's egress
+	// is only restricted when at least one is set.
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
+	// MetricsNamespaces are the namespaces allowed to access the DataPlane's metrics port
+	// next to the operator. It's open to all sources when empty.
+	MetricsNamespaces []string `json:"-"`
+	// EgressFromRoutes restricts the DataPlane's egress to the backends of the routes
+	// attached to the Gateway, next to the Egress rules.
+	EgressFromRoutes bool `json:"-"`
+}
+
+// dataPlaneNetworkPolicyConfigFromGatewayConfiguration reads the configuration of the DataPlane's
+// policy from the annotations of the GatewayConfiguration.
+func dataPlaneNetworkPolicyConfigFromGatewayConfiguration(gatewayConfig *GatewayConfiguration) (dataPlaneNetworkPolicyConfig, error) {
+	cfg := dataPlaneNetworkPolicyConfig{
+		Kind: networkPolicyKindNetworkPolicy,
+	}
+	annotations := gatewayConfig.GetAnnotations()
+
+	if kind, ok := annotations[consts.GatewayConfigurationNetworkPolicyKindAnnotation]; ok {
+		switch kind {
+		case networkPolicyKindNetworkPolicy, networkPolicyKindCiliumNetworkPolicy:
+			cfg.Kind = kind
+		default:
+			return cfg, fmt.Errorf("unsupported %s annotation value %q (possible values: %s, %s)",
+				consts.GatewayConfigurationNetworkPolicyKindAnnotation, kind,
+				networkPolicyKindNetworkPolicy, networkPolicyKindCiliumNetworkPolicy,
+			)
+		}
+	}
+
+	if rules, ok := annotations[consts.GatewayConfigurationNetworkPolicyRulesAnnotation]; ok {
+		dec := json.NewDecoder(bytes.NewBufferString(rules))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return cfg, fmt.Errorf("failed parsing %s annotation: %w", consts.GatewayConfigurationNetworkPolicyRulesAnnotation, err)
+		}
+	}
+
+	if namespaces, ok := annotations[consts.GatewayConfigurationNetworkPolicyMetricsNamespacesAnnotation]; ok {
+		for ns := range strings.SplitSeq(namespaces, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				cfg.MetricsNamespaces = append(cfg.MetricsNamespaces, ns)
+			}
+		}
+	}
+
+	if egressFromRoutes, ok := annotations[consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation]; ok {
+		enabled, err := strconv.ParseBool(egressFromRoutes)
+		if err != nil {
+			return cfg, fmt.Errorf("failed parsing %s annotation: %w", consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation, err)
+		}
+		cfg.EgressFromRoutes = enabled
+	}
+
+	return cfg, nil
+}
+
+// dataPlaneListenPorts are the ports the DataPlane listens on.
+type dataPlaneListenPorts struct {
+	adminAPISSL intstr.IntOrString
+	proxy       intstr.IntOrString
+	proxySSL    intstr.IntOrString
+}
+
+// resolveDataPlaneListenPorts returns the ports the DataPlane listens on. The defaults are
+// overridden by KONG_PROXY_LISTEN and KONG_ADMIN_LISTEN set in the proxy container, either
+// directly or through ConfigMaps and Secrets referenced by its env or envFrom.
+func resolveDataPlaneListenPorts(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) (dataPlaneListenPorts, error) {
+	ports := dataPlaneListenPorts{
+		adminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+		proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+		proxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+	}
+
+	podTemplateSpec := dataplane.Spec.Deployment.PodTemplateSpec
+	if podTemplateSpec == nil {
+		return ports, nil
+	}
+	container := k8sutils.GetPodContainerByName(&podTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
+	if container == nil {
+		return ports, nil
+	}
+
+	proxyListen, _, err := k8sutils.GetEnvValueFromContainer(ctx, container, dataplane.Namespace, "KONG_PROXY_LISTEN", cl)
+	if err != nil {
+		return ports, fmt.Errorf("failed getting KONG_PROXY_LISTEN env: %w", err)
+	}
+	if proxyListen != "" {
+		kongListenConfig, err := parseKongListenEnv(proxyListen)
+		if err != nil {
+			return ports, fmt.Errorf("failed parsing KONG_PROXY_LISTEN env: %w", err)
+		}
+		if kongListenConfig.Endpoint != nil {
+			ports.proxy = intstr.FromInt(kongListenConfig.Endpoint.Port)
+		}
+		if kongListenConfig.SSLEndpoint != nil {
+			ports.proxySSL = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
+		}
+	}
+
+	adminListen, _, err := k8sutils.GetEnvValueFromContainer(ctx, container, dataplane.Namespace, "KONG_ADMIN_LISTEN", cl)
+	if err != nil {
+		return ports, fmt.Errorf("failed getting KONG_ADMIN_LISTEN env: %w", err)
+	}
+	if adminListen != "" {
+		kongListenConfig, err := parseKongListenEnv(adminListen)
+		if err != nil {
+			return ports, fmt.Errorf("failed parsing KONG_ADMIN_LISTEN env: %w", err)
+		}
+		if kongListenConfig.SSLEndpoint != nil {
+			ports.adminAPISSL = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
+		}
+	}
+
+	return ports, nil
+}
+
+func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	dataplane *operatorv1beta1.DataPlane,
+	gatewayConfig *GatewayConfiguration,
+) (createdOrUpdate bool, err error) {
+	cfg, err := dataPlaneNetworkPolicyConfigFromGatewayConfiguration(gatewayConfig)
+	if err != nil {
+		return false, fmt.Errorf("invalid network policy configuration in GatewayConfiguration %s/%s: %w",
+			gatewayConfig.Namespace, gatewayConfig.Name, err,
+		)
+	}
+	ports, err := resolveDataPlaneListenPorts(ctx, r.Client, dataplane)
+	if err != nil {
+		return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
+	}
+	if cfg.EgressFromRoutes {
+		backendsEgress, err := dataPlaneEgressRulesForRoutes(ctx, r.Client, gateway)
+		if err != nil {
+			return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
+		}
+		cfg.Egress = append(cfg.Egress, backendsEgress...)
+	}
+	if len(cfg.Egress) > 0 || cfg.EgressFromRoutes {
+		konnectEgress, err := dataPlaneEgressRulesForKonnect(ctx, r.Client, dataplane)
+		if err != nil {
+			return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
+		}
+		cfg.Egress = append(cfg.Egress, konnectEgress...)
+	}
+
+	// generate the network policy that allows the KO pod to access the admin APIs of dataplane pods.
+	generatedPolicy := generateDataPlaneNetworkPolicy(r.Namespace, dataplane, r.PodLabels, ports, cfg)
+	k8sutils.SetOwnerForObject(generatedPolicy, gateway)
+	gatewayutils.LabelObjectAsGatewayManaged(generatedPolicy)
+
+	if cfg.Kind == networkPolicyKindCiliumNetworkPolicy {
+		// Remove the NetworkPolicy generated before switching to a CiliumNetworkPolicy.
+		if deleted, err := r.ensureOwnedNetworkPoliciesDeleted(ctx, gateway); err != nil || deleted {
+			return deleted, err
+		}
+		return r.ensureDataPlaneHasCiliumNetworkPolicy(ctx, gateway, generateCiliumNetworkPolicy(generatedPolicy))
+	}
+	if err := r.ensureOwnedCiliumNetworkPoliciesDeleted(ctx, gateway); err != nil {
+		return false, err
+	}
+
+	networkPolicies, err := gatewayutils.ListNetworkPoliciesForGateway(ctx, r.Client, gateway)
+	if err != nil {
+		return false, err
+	}
+
+	count := len(networkPolicies)
+	if count > 1 {
+		if err := k8sreduce.ReduceNetworkPolicies(ctx, r.Client, networkPolicies); err != nil {
+			return false, err
+		}
+		return false, errors.New("number of networkPolicies reduced")
+	}
+
+	if count == 1 {
+		var (
+			metaUpdated    bool
+			existingPolicy = &networkPolicies[0]
+			old            = existingPolicy.DeepCopy()
+		)
+		metaUpdated, existingPolicy.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingPolicy.ObjectMeta, generatedPolicy.ObjectMeta)
+
+		if k8sresources.EnsureNetworkPolicyIsUpdated(existingPolicy, generatedPolicy) || metaUpdated {
+			if err := r.Patch(ctx, existingPolicy, client.MergeFrom(old)); err != nil {
+				return false, fmt.Errorf("failed updating DataPlane's NetworkPolicy %s: %w", existingPolicy.Name, err)
+			}
+			return true, nil
+		}
+		return false, nil
+	}
+
+	return true, r.Create(ctx, generatedPolicy)
+}
+
+// generateDataPlaneNetworkPolicy generates the NetworkPolicy that allows the KO pod to access admin API of dataplane pods.
+// the params `namespace` and `podLabels` are namespace and labels of the KO pod itself, and `dataplane` is the target dataplane.
+// The rules configured in cfg are added to the generated ones.
+func generateDataPlaneNetworkPolicy(
+	namespace string,
+	dataplane *operatorv1beta1.DataPlane,
+	podLabels map[string]string,
+	ports dataPlaneListenPorts,
+	cfg dataPlaneNetworkPolicyConfig,
+) *networkingv1.NetworkPolicy {
+	var (
+		protocolTCP = corev1.ProtocolTCP
+		protocolUDP = corev1.ProtocolUDP
+		metricsPort = intstr.FromInt(consts.DataPlaneMetricsPort)
+		dnsPort     = intstr.FromInt(53)
+		// The label keys to match Kong operator pod.
+		// To not create new NetworkPolicy on upgrade of , we just keep the keys marking the application
+		// and remove the keys related to versions such as `version`,`pod-template-hash`,`helm.sh/chart`.
+		podLabelSelectorKeys = []string{
+			"app",
+			"app.kubernetes.io/component",
+			"app.kubernetes.io/instance",
+			"app.kubernetes.io/name",
+			"control-plane",
+		}
+	)
+
+	// Construct the policy to allow the KO pod to access DataPlane admin APIs.
+	policyPeerForControllerPod := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				namespaceNameLabel: namespace,
+			},
+		},
+	}
+
+	if len(podLabels) > 0 {
+		matchPodLabels := map[string]string{}
+		for _, key := range podLabelSelectorKeys {
+			value, ok := podLabels[key]
+			if ok {
+				matchPodLabels[key] = value
+			}
+		}
+		policyPeerForControllerPod.PodSelector = &metav1.LabelSelector{
+			MatchLabels: matchPodLabels,
+		}
+	}
+	limitAdminAPIIngress := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &protocolTCP, Port: &ports.adminAPISSL},
+		},
+		From: []networkingv1.NetworkPolicyPeer{
+			policyPeerForControllerPod,
+		},
+	}
+
+	allowProxyIngress := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &protocolTCP, Port: &ports.proxy},
+			{Protocol: &protocolTCP, Port: &ports.proxySSL},
+		},
+	}
+
+	allowMetricsIngress := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &protocolTCP, Port: &metricsPort},
+		},
+	}
+	// The operator scrapes the DataPlane's metrics itself, so it keeps access to them.
+	if len(cfg.MetricsNamespaces) > 0 {
+		allowMetricsIngress.From = []networkingv1.NetworkPolicyPeer{
+			policyPeerForControllerPod,
+			{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      namespaceNameLabel,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   cfg.MetricsNamespaces,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    dataplane.Namespace,
+			GenerateName: k8sutils.TrimGenerateName(fmt.Sprintf("%s-limit-admin-api-", dataplane.Name)),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": dataplane.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+			},
+			Ingress: append([]networkingv1.NetworkPolicyIngressRule{
+				limitAdminAPIIngress,
+				allowProxyIngress,
+				allowMetricsIngress,
+			}, cfg.Ingress...),
+		},
+	}
+
+	if len(cfg.Egress) > 0 || cfg.EgressFromRoutes {
+		// Once the egress is restricted the DataPlane still has to resolve the upstreams' names.
+		allowDNSEgress := networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolUDP, Port: &dnsPort},
+				{Protocol: &protocolTCP, Port: &dnsPort},
+			},
+		}
+		policy.Spec.PolicyTypes = append(policy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		policy.Spec.Egress = append([]networkingv1.NetworkPolicyEgressRule{allowDNSEgress}, cfg.Egress...)
+	}
+
+	return policy
+}
+
+// backendRoute is a route of any of the kinds the DataPlane's egress is derived from.
+type backendRoute struct {
+	kind        gwtypes.Kind
+	namespace   string
+	name        string
+	parentRefs  []gwtypes.ParentReference
+	backendRefs []gwtypes.BackendObjectReference
+}
+
+const (
+	routeKindHTTPRoute = gwtypes.Kind("HTTPRoute")
+	routeKindGRPCRoute = gwtypes.Kind("GRPCRoute")
+	routeKindTCPRoute  = gwtypes.Kind("TCPRoute")
+	routeKindTLSRoute  = gwtypes.Kind("TLSRoute")
+	routeKindUDPRoute  = gwtypes.Kind("UDPRoute")
+
+	// endpointSliceControllerName is the name of the controller managing the EndpointSlices
+	// of Services with a selector, set in their discoveryv1.LabelManagedBy label.
+	endpointSliceControllerName = "endpointslice-controller.k8s.io"
+)
+
+// backendRouteKinds are the kinds of the routes the DataPlane's egress is derived from.
+var backendRouteKinds = []gwtypes.Kind{
+	routeKindHTTPRoute,
+	routeKindGRPCRoute,
+	routeKindTCPRoute,
+	routeKindTLSRoute,
+	routeKindUDPRoute,
+}
+
+// backendRouteKindsByProtocol are the kinds of the routes attached to listeners of each
+// protocol when the listener doesn't restrict the kinds of the routes it allows.
+var backendRouteKindsByProtocol = map[gatewayv1.ProtocolType][]gwtypes.Kind{
+	gatewayv1.HTTPProtocolType:  {routeKindHTTPRoute, routeKindGRPCRoute},
+	gatewayv1.HTTPSProtocolType: {routeKindHTTPRoute, routeKindGRPCRoute},
+	gatewayv1.TLSProtocolType:   {routeKindTLSRoute, routeKindTCPRoute},
+	gatewayv1.TCPProtocolType:   {routeKindTCPRoute},
+	gatewayv1.UDPProtocolType:   {routeKindUDPRoute},
+}
+
+// optionalBackendRoutes are the routes which CRDs might not be installed, as they're
+// not part of all the Gateway API channels or versions.
+var optionalBackendRoutes = []struct {
+	resource  schema.GroupVersionResource
+	newObject func() client.Object
+}{
+	{
+		resource:  gatewayv1.SchemeGroupVersion.WithResource("grpcroutes"),
+		newObject: func() client.Object { return &gatewayv1.GRPCRoute{} },
+	},
+	{
+		resource:  gatewayv1alpha2.SchemeGroupVersion.WithResource("tcproutes"),
+		newObject: func() client.Object { return &gatewayv1alpha2.TCPRoute{} },
+	},
+	{
+		resource:  gatewayv1alpha2.SchemeGroupVersion.WithResource("tlsroutes"),
+		newObject: func() client.Object { return &gatewayv1alpha2.TLSRoute{} },
+	},
+	{
+		resource:  gatewayv1alpha2.SchemeGroupVersion.WithResource("udproutes"),
+		newObject: func() client.Object { return &gatewayv1alpha2.UDPRoute{} },
+	},
+}
+
+// backendRouteFor returns the backendRoute of a route object. It returns false
+// if the object isn't a route of a supported kind.
+func backendRouteFor(obj client.Object) (backendRoute, bool) {
+	route := backendRoute{
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+	switch o := obj.(type) {
+	case *gatewayv1beta1.HTTPRoute:
+		return backendRouteFor((*gatewayv1.HTTPRoute)(o))
+	case *gatewayv1.HTTPRoute:
+		route.kind = routeKindHTTPRoute
+		route.parentRefs = o.Spec.ParentRefs
+		for _, rule := range o.Spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				route.backendRefs = append(route.backendRefs, ref.BackendObjectReference)
+			}
+		}
+	case *gatewayv1.GRPCRoute:
+		route.kind = routeKindGRPCRoute
+		route.parentRefs = o.Spec.ParentRefs
+		for _, rule := range o.Spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				route.backendRefs = append(route.backendRefs, ref.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.TCPRoute:
+		route.kind = routeKindTCPRoute
+		route.parentRefs = o.Spec.ParentRefs
+		for _, rule := range o.Spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				route.backendRefs = append(route.backendRefs, ref.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.TLSRoute:
+		route.kind = routeKindTLSRoute
+		route.parentRefs = o.Spec.ParentRefs
+		for _, rule := range o.Spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				route.backendRefs = append(route.backendRefs, ref.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.UDPRoute:
+		route.kind = routeKindUDPRoute
+		route.parentRefs = o.Spec.ParentRefs
+		for _, rule := range o.Spec.Rules {
+			for _, ref := range rule.BackendRefs {
+				route.backendRefs = append(route.backendRefs, ref.BackendObjectReference)
+			}
+		}
+	default:
+		return route, false
+	}
+	return route, true
+}
+
+// listBackendRoutes lists the routes of the provided kind. Routes which CRDs aren't
+// installed in the cluster are skipped.
+func listBackendRoutes(ctx context.Context, cl client.Client, kind gwtypes.Kind, opts ...client.ListOption) ([]backendRoute, error) {
+	var list client.ObjectList
+	switch kind {
+	case routeKindHTTPRoute:
+		list = &gatewayv1.HTTPRouteList{}
+	case routeKindGRPCRoute:
+		list = &gatewayv1.GRPCRouteList{}
+	case routeKindTCPRoute:
+		list = &gatewayv1alpha2.TCPRouteList{}
+	case routeKindTLSRoute:
+		list = &gatewayv1alpha2.TLSRouteList{}
+	case routeKindUDPRoute:
+		list = &gatewayv1alpha2.UDPRouteList{}
+	default:
+		return nil, fmt.Errorf("unsupported route kind: %s", kind)
+	}
+	if err := cl.List(ctx, list, opts...); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed listing %ss: %w", kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing %ss: %w", kind, err)
+	}
+
+	routes := make([]backendRoute, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		if route, ok := backendRouteFor(obj); ok {
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// listenerBackendRouteKinds returns the kinds of the routes allowed by the listener.
+func listenerBackendRouteKinds(listener gwtypes.Listener) []gwtypes.Kind {
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return backendRouteKindsByProtocol[listener.Protocol]
+	}
+	var kinds []gwtypes.Kind
+	for _, rgk := range listener.AllowedRoutes.Kinds {
+		if rgk.Group != nil && *rgk.Group != gatewayv1.GroupName {
+			continue
+		}
+		if slices.Contains(backendRouteKinds, rgk.Kind) && !slices.Contains(kinds, rgk.Kind) {
+			kinds = append(kinds, rgk.Kind)
+		}
+	}
+	return kinds
+}
+
+// backendNamespace returns the namespace of the backend referenced by the route.
+func (route backendRoute) backendNamespace(ref gwtypes.BackendObjectReference) string {
+	if ref.Namespace != nil {
+		return string(*ref.Namespace)
+	}
+	return route.namespace
+}
+
+// isAttachedToListener returns true if one of the route's parentRefs targets the listener.
+func (route backendRoute) isAttachedToListener(gateway *gwtypes.Gateway, listener gwtypes.Listener) bool {
+	return lo.ContainsBy(route.parentRefs, func(parentRef gwtypes.ParentReference) bool {
+		return isParentRefForGateway(parentRef, route.namespace, gateway) &&
+			(parentRef.SectionName == nil || *parentRef.SectionName == listener.Name) &&
+			(parentRef.Port == nil || *parentRef.Port == listener.Port)
+	})
+}
+
+// isParentRefForGateway returns true if the parentRef of a route in the provided
+// namespace targets the Gateway.
+func isParentRefForGateway(parentRef gwtypes.ParentReference, routeNamespace string, gateway *gwtypes.Gateway) bool {
+	namespace := routeNamespace
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+	return (parentRef.Group == nil || *parentRef.Group == gatewayv1.GroupName) &&
+		(parentRef.Kind == nil || *parentRef.Kind == "Gateway") &&
+		string(parentRef.Name) == gateway.Name &&
+		namespace == gateway.Namespace
+}
+
+// listenerAllowsRouteNamespace returns true if the listener allows routes from the namespace.
+func listenerAllowsRouteNamespace(
+	ctx context.Context,
+	cl client.Client,
+	gateway *gwtypes.Gateway,
+	listener gwtypes.Listener,
+	namespace string,
+) (bool, error) {
+	// Gateway API defaults to only allowing routes from the Gateway's namespace.
+	if listener.AllowedRoutes == nil || listener.AllowedRoutes.Namespaces == nil || listener.AllowedRoutes.Namespaces.From == nil {
+		return namespace == gateway.Namespace, nil
+	}
+	switch *listener.AllowedRoutes.Namespaces.From {
+	case gatewayv1.NamespacesFromAll:
+		return true, nil
+	case gatewayv1.NamespacesFromSame:
+		return namespace == gateway.Namespace, nil
+	case gatewayv1.NamespacesFromSelector:
+		selector, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
+		if err != nil {
+			return false, fmt.Errorf("failed to create namespace selector of listener %s: %w", listener.Name, err)
+		}
+		var ns corev1.Namespace
+		if err := cl.Get(ctx, k8stypes.NamespacedName{Name: namespace}, &ns); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed getting namespace %s: %w", namespace, err)
+		}
+		return selector.Matches(labels.Set(ns.Labels)), nil
+	default:
+		return false, nil
+	}
+}
+
+// dataPlaneEgressRulesForRoutes returns the egress rules allowing the DataPlane to reach the
+// backends of the HTTPRoutes, GRPCRoutes, TCPRoutes, TLSRoutes and UDPRoutes attached to the
+// Gateway's listeners.
+// Only backendRefs to Services are resolved: backendRefs to other kinds, to missing Services
+// or Service ports, or to other namespaces without a ReferenceGrant are skipped, as they aren't
+// routed to anyway. Services without a selector are resolved through their EndpointSlices.
+// The rules are sorted so that they don't change as long as the backends don't.
+func dataPlaneEgressRulesForRoutes(
+	ctx context.Context,
+	cl client.Client,
+	gateway *gwtypes.Gateway,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	var (
+		backendPorts = map[k8stypes.NamespacedName]map[gwtypes.PortNumber]struct{}{}
+		routesByKind = map[gwtypes.Kind][]backendRoute{}
+	)
+	for _, listener := range gateway.Spec.Listeners {
+		for _, kind := range listenerBackendRouteKinds(listener) {
+			routes, ok := routesByKind[kind]
+			if !ok {
+				var err error
+				if routes, err = listBackendRoutes(ctx, cl, kind); err != nil {
+					return nil, err
+				}
+				routesByKind[kind] = routes
+			}
+
+			for _, route := range routes {
+				if !route.isAttachedToListener(gateway, listener) {
+					continue
+				}
+				allowed, err := listenerAllowsRouteNamespace(ctx, cl, gateway, listener, route.namespace)
+				if err != nil {
+					return nil, err
+				}
+				if !allowed {
+					continue
+				}
+
+				for _, ref := range route.backendRefs {
+					if (ref.Group != nil && *ref.Group != "" && *ref.Group != "core") ||
+						(ref.Kind != nil && *ref.Kind != "Service") ||
+						ref.Port == nil {
+						continue
+					}
+					nn := k8stypes.NamespacedName{
+						Namespace: route.backendNamespace(ref),
+						Name:      string(ref.Name),
+					}
+					allowed, err := k8sutils.AllowedByReferenceGrants(ctx, cl,
+						gatewayv1beta1.ReferenceGrantFrom{
+							Group:     gatewayv1beta1.GroupName,
+							Kind:      route.kind,
+							Namespace: gatewayv1beta1.Namespace(route.namespace),
+						},
+						nn.Namespace,
+						gatewayv1beta1.ReferenceGrantTo{
+							Kind: "Service",
+							Name: &ref.Name,
+						},
+					)
+					if err != nil {
+						return nil, fmt.Errorf("failed checking ReferenceGrants for %s %s/%s backend %s: %w",
+							route.kind, route.namespace, route.name, nn, err,
+						)
+					}
+					if !allowed {
+						continue
+					}
+					if backendPorts[nn] == nil {
+						backendPorts[nn] = map[gwtypes.PortNumber]struct{}{}
+					}
+					backendPorts[nn][*ref.Port] = struct{}{}
+				}
+			}
+		}
+	}
+
+	backends := make([]k8stypes.NamespacedName, 0, len(backendPorts))
+	for nn := range backendPorts {
+		backends = append(backends, nn)
+	}
+	slices.SortFunc(backends, func(a, b k8stypes.NamespacedName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	rules := make([]networkingv1.NetworkPolicyEgressRule, 0, len(backends))
+	for _, nn := range backends {
+		var service corev1.Service
+		if err := cl.Get(ctx, nn, &service); err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed getting backend Service %s: %w", nn, err)
+		}
+		if len(service.Spec.Selector) == 0 {
+			endpointsRules, err := egressRulesForServiceEndpointSlices(ctx, cl, &service, backendPorts[nn])
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, endpointsRules...)
+			continue
+		}
+
+		var ports []networkingv1.NetworkPolicyPort
+		for _, servicePort := range service.Spec.Ports {
+			if _, ok := backendPorts[nn][gwtypes.PortNumber(servicePort.Port)]; !ok {
+				continue
+			}
+			protocol := corev1.ProtocolTCP
+			if servicePort.Protocol != "" {
+				protocol = servicePort.Protocol
+			}
+			targetPort := servicePort.TargetPort
+			if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+				targetPort = intstr.FromInt32(servicePort.Port)
+			}
+			ports = append(ports, networkingv1.NetworkPolicyPort{
+				Protocol: &protocol,
+				Port:     &targetPort,
+			})
+		}
+		if len(ports) == 0 {
+			continue
+		}
+
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			Ports: ports,
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							namespaceNameLabel: nn.Namespace,
+						},
+					},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: service.Spec.Selector,
+					},
+				},
+			},
+		})
+	}
+
+	return rules, nil
+}
+
+// egressRulesForServiceEndpointSlices returns the egress rules allowing the DataPlane to reach
+// the endpoints of a Service without a selector, on the provided Service ports. The endpoints
+// of such Services are managed in EndpointSlices by their owners and can't be selected by labels,
+// so the rules are made of their IP addresses. Endpoints with FQDN addresses are skipped.
+func egressRulesForServiceEndpointSlices(
+	ctx context.Context,
+	cl client.Client,
+	service *corev1.Service,
+	servicePorts map[gwtypes.PortNumber]struct{},
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	var endpointSlices discoveryv1.EndpointSliceList
+	if err := cl.List(ctx, &endpointSlices,
+		client.InNamespace(service.Namespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: service.Name},
+	); err != nil {
+		return nil, fmt.Errorf("failed listing EndpointSlices of backend Service %s: %w", client.ObjectKeyFromObject(service), err)
+	}
+	slices.SortFunc(endpointSlices.Items, func(a, b discoveryv1.EndpointSlice) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var rules []networkingv1.NetworkPolicyEgressRule
+	for _, endpointSlice := range endpointSlices.Items {
+		if endpointSlice.AddressType != discoveryv1.AddressTypeIPv4 && endpointSlice.AddressType != discoveryv1.AddressTypeIPv6 {
+			continue
+		}
+
+		// EndpointSlice ports are matched with the Service ports by their names.
+		var ports []networkingv1.NetworkPolicyPort
+		for _, servicePort := range service.Spec.Ports {
+			if _, ok := servicePorts[gwtypes.PortNumber(servicePort.Port)]; !ok {
+				continue
+			}
+			for _, endpointPort := range endpointSlice.Ports {
+				if lo.FromPtr(endpointPort.Name) != servicePort.Name || endpointPort.Port == nil {
+					continue
+				}
+				protocol := lo.FromPtrOr(endpointPort.Protocol, corev1.ProtocolTCP)
+				port := intstr.FromInt32(*endpointPort.Port)
+				ports = append(ports, networkingv1.NetworkPolicyPort{
+					Protocol: &protocol,
+					Port:     &port,
+				})
+			}
+		}
+		if len(ports) == 0 {
+			continue
+		}
+
+		var cidrs []string
+		for _, endpoint := range endpointSlice.Endpoints {
+			for _, address := range endpoint.Addresses {
+				addr, err := netip.ParseAddr(address)
+				if err != nil {
+					continue
+				}
+				cidrs = append(cidrs, netip.PrefixFrom(addr, addr.BitLen()).String())
+			}
+		}
+		slices.Sort(cidrs)
+		cidrs = slices.Compact(cidrs)
+		if len(cidrs) == 0 {
+			continue
+		}
+
+		rule := networkingv1.NetworkPolicyEgressRule{
+			Ports: ports,
+		}
+		for _, cidr := range cidrs {
+			rule.To = append(rule.To, networkingv1.NetworkPolicyPeer{
+				IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+			})
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// dataPlaneEgressRulesForKonnect returns the egress rules allowing the DataPlane to reach Konnect
+// when it's connected to it through a KonnectExtension. Konnect endpoints are only known by their
+// host names, which NetworkPolicies can't select, so all destinations are allowed on the ports of
+// the endpoints.
+func dataPlaneEgressRulesForKonnect(
+	ctx context.Context,
+	cl client.Client,
+	dataplane *operatorv1beta1.DataPlane,
+) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	extensionRef, ok := lo.Find(dataplane.Spec.Extensions, func(ref commonv1alpha1.ExtensionRef) bool {
+		return ref.Group == konnectv1alpha1.SchemeGroupVersion.Group && ref.Kind == konnectv1alpha2.KonnectExtensionKind
+	})
+	if !ok {
+		return nil, nil
+	}
+
+	var endpoints []string
+	var ext konnectv1alpha2.KonnectExtension
+	if err := cl.Get(ctx, k8stypes.NamespacedName{Namespace: dataplane.Namespace, Name: extensionRef.Name}, &ext); err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed getting KonnectExtension %s: %w", extensionRef.Name, err)
+		}
+	} else if ext.Status.Konnect != nil {
+		endpoints = append(endpoints,
+			ext.Status.Konnect.Endpoints.ControlPlaneEndpoint,
+			ext.Status.Konnect.Endpoints.TelemetryEndpoint,
+		)
+	}
+
+	var portNumbers []int32
+	for _, endpoint := range endpoints {
+		if endpoint == "" {
+			continue
+		}
+		portNumbers = append(portNumbers, konnectEndpointPort(endpoint))
+	}
+	// Konnect endpoints are served on the HTTPS port.
+	if len(portNumbers) == 0 {
+		portNumbers = append(portNumbers, konnectEndpointPort(""))
+	}
+	slices.Sort(portNumbers)
+	portNumbers = slices.Compact(portNumbers)
+
+	protocolTCP := corev1.ProtocolTCP
+	rule := networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{
+			{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+			{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+		},
+	}
+	for _, portNumber := range portNumbers {
+		port := intstr.FromInt32(portNumber)
+		rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{
+			Protocol: &protocolTCP,
+			Port:     &port,
+		})
+	}
+	return []networkingv1.NetworkPolicyEgressRule{rule}, nil
+}
+
+// konnectEndpointPort returns the port of a Konnect endpoint, e.g. https://7b46471d3b.us.cp0.konghq.com:443.
+// It defaults to the HTTPS port.
+func konnectEndpointPort(endpoint string) int32 {
+	const httpsPort = 443
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Port() == "" {
+		return httpsPort
+	}
+	port, err := strconv.ParseInt(u.Port(), 10, 32)
+	if err != nil {
+		return httpsPort
+	}
+	return int32(port)
+}
+
+// -----------------------------------------------------------------------------
+// GatewayReconciler - DataPlane CiliumNetworkPolicy
+// -----------------------------------------------------------------------------
+
+func (r *Reconciler) ensureDataPlaneHasCiliumNetworkPolicy(
+	ctx context.Context,
+	gateway *gwtypes.Gateway,
+	generatedPolicy *unstructured.Unstructured,
+) (createdOrUpdate bool, err error) {
+	policies, err := r.listCiliumNetworkPoliciesForGateway(ctx, gateway)
+	if err != nil {
+		return false, fmt.Errorf("failed listing CiliumNetworkPolicies: %w", err)
+	}
+
+	// CiliumNetworkPolicies aren't watched, so changes don't requeue the Gateway
+	// and the reconciliation carries on.
+	switch len(policies) {
+	case 0:
+		if err := r.Create(ctx, generatedPolicy); err != nil {
+			return false, fmt.Errorf("failed creating DataPlane's CiliumNetworkPolicy: %w", err)
+		}
+		return false, nil
+	case 1:
+		existingPolicy := &policies[0]
+		if equality.Semantic.DeepEqual(existingPolicy.Object["spec"], generatedPolicy.Object["spec"]) &&
+			equality.Semantic.DeepEqual(existingPolicy.GetLabels(), generatedPolicy.GetLabels()) {
+			return false, nil
+		}
+		existingPolicy.Object["spec"] = generatedPolicy.Object["spec"]
+		existingPolicy.SetLabels(generatedPolicy.GetLabels())
+		if err := r.Update(ctx, existingPolicy); err != nil {
+			return false, fmt.Errorf("failed updating DataPlane's CiliumNetworkPolicy %s: %w", existingPolicy.GetName(), err)
+		}
+		return false, nil
+	default:
+		for i := range policies[1:] {
+			if err := r.Delete(ctx, &policies[i+1]); client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+		}
+		return false, errors.New("number of CiliumNetworkPolicies reduced")
+	}
+}
+
+// ensureOwnedCiliumNetworkPoliciesDeleted deletes the CiliumNetworkPolicies owned by the Gateway.
+// It does nothing when CiliumNetworkPolicies aren't available in the cluster or the operator
+// isn't allowed to manage them.
+func (r *Reconciler) ensureOwnedCiliumNetworkPoliciesDeleted(ctx context.Context, gateway *gwtypes.Gateway) error {
+	policies, err := r.listCiliumNetworkPoliciesForGateway(ctx, gateway)
+	if err != nil {
+		if meta.IsNoMatchError(err) || k8serrors.IsForbidden(err) {
+			return nil
+		}
+		return fmt.Errorf("failed listing CiliumNetworkPolicies: %w", err)
+	}
+
+	var errs []error
+	for i := range policies {
+		if err := r.Delete(ctx, &policies[i]); client.IgnoreNotFound(err) != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) listCiliumNetworkPoliciesForGateway(ctx context.Context, gateway *gwtypes.Gateway) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(ciliumNetworkPolicyGVK.GroupVersion().WithKind(ciliumNetworkPolicyGVK.Kind + "List"))
+	if err := r.List(ctx, list,
+		client.InNamespace(gateway.Namespace),
+		client.MatchingLabels{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+	); err != nil {
+		return nil, err
+	}
+
+	policies := make([]unstructured.Unstructured, 0, len(list.Items))
+	for _, policy := range list.Items {
+		if k8sutils.IsOwnedByRefUID(&policy, gateway.UID) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+// generateCiliumNetworkPolicy converts the NetworkPolicy to an equivalent CiliumNetworkPolicy.
+func generateCiliumNetworkPolicy(policy *networkingv1.NetworkPolicy) *unstructured.Unstructured {
+	cnp := &unstructured.Unstructured{}
+	cnp.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+	cnp.SetNamespace(policy.Namespace)
+	cnp.SetGenerateName(policy.GenerateName)
+	cnp.SetLabels(policy.Labels)
+	cnp.SetOwnerReferences(policy.OwnerReferences)
+
+	spec := map[string]any{
+		"endpointSelector": ciliumEndpointSelector(&policy.Spec.PodSelector, nil),
+	}
+	if len(policy.Spec.Ingress) > 0 {
+		ingress := make([]any, 0, len(policy.Spec.Ingress))
+		for _, rule := range policy.Spec.Ingress {
+			ingress = append(ingress, ciliumRule("from", rule.From, rule.Ports))
+		}
+		spec["ingress"] = ingress
+	}
+	if len(policy.Spec.Egress) > 0 {
+		egress := make([]any, 0, len(policy.Spec.Egress))
+		for _, rule := range policy.Spec.Egress {
+			egress = append(egress, ciliumRule("to", rule.To, rule.Ports))
+		}
+		spec["egress"] = egress
+	}
+	cnp.Object["spec"] = spec
+
+	return cnp
+}
+
+// ciliumRule converts the peers and ports of a NetworkPolicy rule to a CiliumNetworkPolicy rule.
+// direction is either "from" for ingress rules or "to" for egress rules.
+func ciliumRule(direction string, peers []networkingv1.NetworkPolicyPeer, ports []networkingv1.NetworkPolicyPort) map[string]any {
+	rule := map[string]any{}
+
+	// A NetworkPolicy rule without peers matches all sources or destinations.
+	if len(peers) == 0 {
+		rule[direction+"Entities"] = []any{"all"}
+	}
+	var endpoints, cidrs []any
+	for _, peer := range peers {
+		if peer.IPBlock != nil {
+			cidr := map[string]any{"cidr": peer.IPBlock.CIDR}
+			if len(peer.IPBlock.Except) > 0 {
+				cidr["except"] = toAnySlice(peer.IPBlock.Except)
+			}
+			cidrs = append(cidrs, cidr)
+			continue
+		}
+		endpoints = append(endpoints, ciliumEndpointSelector(peer.PodSelector, peer.NamespaceSelector))
+	}
+	if len(endpoints) > 0 {
+		rule[direction+"Endpoints"] = endpoints
+	}
+	if len(cidrs) > 0 {
+		rule[direction+"CIDRSet"] = cidrs
+	}
+
+	if len(ports) > 0 {
+		ciliumPorts := make([]any, 0, len(ports))
+		for _, p := range ports {
+			// NetworkPolicy ports default to TCP and to all port numbers.
+			port := map[string]any{
+				"port":     "0",
+				"protocol": string(corev1.ProtocolTCP),
+			}
+			if p.Port != nil {
+				port["port"] = p.Port.String()
+			}
+			if p.Protocol != nil {
+				port["protocol"] = string(*p.Protocol)
+			}
+			if p.EndPort != nil {
+				port["endPort"] = int64(*p.EndPort)
+			}
+			ciliumPorts = append(ciliumPorts, port)
+		}
+		rule["toPorts"] = []any{
+			map[string]any{"ports": ciliumPorts},
+		}
+	}
+
+	return rule
+}
+
+// ciliumEndpointSelector converts NetworkPolicy pod and namespace selectors to a Cilium endpoint selector.
+// Cilium endpoint selectors only match endpoints in the policy's namespace unless they select
+// namespaces, which is done through the namespace labels Cilium sets on all endpoints.
+func ciliumEndpointSelector(podSelector, namespaceSelector *metav1.LabelSelector) map[string]any {
+	var (
+		matchLabels      = map[string]any{}
+		matchExpressions []any
+	)
+	addSelector := func(selector *metav1.LabelSelector, keyPrefix string) {
+		for k, v := range selector.MatchLabels {
+			matchLabels[keyPrefix+k] = v
+		}
+		for _, expr := range selector.MatchExpressions {
+			e := map[string]any{
+				"key":      keyPrefix + expr.Key,
+				"operator": string(expr.Operator),
+			}
+			if len(expr.Values) > 0 {
+				e["values"] = toAnySlice(expr.Values)
+			}
+			matchExpressions = append(matchExpressions, e)
+		}
+	}
+	if podSelector != nil {
+		addSelector(podSelector, "")
+	}
+	if namespaceSelector != nil {
+		addSelector(namespaceSelector, "k8s:io.cilium.k8s.namespace.labels.")
+		matchExpressions = append(matchExpressions, map[string]any{
+			"key":      "k8s:io.kubernetes.pod.namespace",
+			"operator": string(metav1.LabelSelectorOpExists),
+		})
+	}
+
+	selector := map[string]any{}
+	if len(matchLabels) > 0 {
+		selector["matchLabels"] = matchLabels
+	}
+	if len(matchExpressions) > 0 {
+		selector["matchExpressions"] = matchExpressions
+	}
+	return selector
+}
+
+func toAnySlice(s []string) []any {
+	out := make([]any, 0, len(s))
+	for _, v := range s {
+		out = append(out, v)
+	}
+	return out
+}
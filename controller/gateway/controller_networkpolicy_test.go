@@ -0,0 +1,764 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	commonv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/common/v1alpha1"
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestDataPlaneNetworkPolicyConfigFromGatewayConfiguration(t *testing.T) {
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		expected    dataPlaneNetworkPolicyConfig
+		expectedErr bool
+	}{
+		{
+			name:     "no annotations",
+			expected: dataPlaneNetworkPolicyConfig{Kind: networkPolicyKindNetworkPolicy},
+		},
+		{
+			name: "all annotations",
+			annotations: map[string]string{
+				consts.GatewayConfigurationNetworkPolicyKindAnnotation:              "CiliumNetworkPolicy",
+				consts.GatewayConfigurationNetworkPolicyRulesAnnotation:             `{"egress":[{"to":[{"namespaceSelector":{"matchLabels":{"team":"payments"}}}]}]}`,
+				consts.GatewayConfigurationNetworkPolicyMetricsNamespacesAnnotation: "monitoring, prometheus",
+				consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation:  "true",
+			},
+			expected: dataPlaneNetworkPolicyConfig{
+				Kind: networkPolicyKindCiliumNetworkPolicy,
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					{
+						To: []networkingv1.NetworkPolicyPeer{
+							{
+								NamespaceSelector: &metav1.LabelSelector{
+									MatchLabels: map[string]string{"team": "payments"},
+								},
+							},
+						},
+					},
+				},
+				MetricsNamespaces: []string{"monitoring", "prometheus"},
+				EgressFromRoutes:  true,
+			},
+		},
+		{
+			name: "unsupported kind",
+			annotations: map[string]string{
+				consts.GatewayConfigurationNetworkPolicyKindAnnotation: "AdminNetworkPolicy",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "invalid egress from routes",
+			annotations: map[string]string{
+				consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation: "yes",
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unknown field in rules",
+			annotations: map[string]string{
+				consts.GatewayConfigurationNetworkPolicyRulesAnnotation: `{"egres":[]}`,
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gatewayConfig := &GatewayConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			}
+			cfg, err := dataPlaneNetworkPolicyConfigFromGatewayConfiguration(gatewayConfig)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
+
+func TestResolveDataPlaneListenPorts(t *testing.T) {
+	listenConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kong-listen",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"PROXY_LISTEN": "0.0.0.0:8001, 0.0.0.0:8444 http2 ssl",
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		container corev1.Container
+		expected  dataPlaneListenPorts
+	}{
+		{
+			name: "defaults",
+			container: corev1.Container{
+				Name: consts.DataPlaneProxyContainerName,
+			},
+			expected: dataPlaneListenPorts{
+				adminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+				proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+				proxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+			},
+		},
+		{
+			name: "env",
+			container: corev1.Container{
+				Name: consts.DataPlaneProxyContainerName,
+				Env: []corev1.EnvVar{
+					{Name: "KONG_ADMIN_LISTEN", Value: "0.0.0.0:9444 ssl"},
+				},
+			},
+			expected: dataPlaneListenPorts{
+				adminAPISSL: intstr.FromInt(9444),
+				proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+				proxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+			},
+		},
+		{
+			name: "envFrom",
+			container: corev1.Container{
+				Name: consts.DataPlaneProxyContainerName,
+				EnvFrom: []corev1.EnvFromSource{
+					{
+						Prefix: "KONG_",
+						ConfigMapRef: &corev1.ConfigMapEnvSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: listenConfig.Name},
+						},
+					},
+				},
+			},
+			expected: dataPlaneListenPorts{
+				adminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+				proxy:       intstr.FromInt(8001),
+				proxySSL:    intstr.FromInt(8444),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(listenConfig).
+				Build()
+			dataplane := &operatorv1beta1.DataPlane{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dp",
+					Namespace: "default",
+				},
+			}
+			dataplane.Spec.Deployment.PodTemplateSpec = &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{tc.container},
+				},
+			}
+
+			ports, err := resolveDataPlaneListenPorts(t.Context(), cl, dataplane)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ports)
+		})
+	}
+}
+
+func TestGenerateDataPlaneNetworkPolicy(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp",
+			Namespace: "default",
+		},
+	}
+	ports := dataPlaneListenPorts{
+		adminAPISSL: intstr.FromInt(consts.DataPlaneAdminAPIPort),
+		proxy:       intstr.FromInt(consts.DataPlaneProxyPort),
+		proxySSL:    intstr.FromInt(consts.DataPlaneProxySSLPort),
+	}
+	podLabels := map[string]string{
+		"app":               "kong-operator",
+		"pod-template-hash": "abc",
+	}
+	backendsEgress := networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{
+			{
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "payments"},
+				},
+			},
+		},
+	}
+
+	t.Run("default policy only restricts ingress", func(t *testing.T) {
+		policy := generateDataPlaneNetworkPolicy("kong-system", dataplane, podLabels, ports, dataPlaneNetworkPolicyConfig{})
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 3)
+		assert.Equal(t, map[string]string{"app": "kong-operator"}, policy.Spec.Ingress[0].From[0].PodSelector.MatchLabels)
+		assert.Empty(t, policy.Spec.Ingress[2].From, "metrics should be open to all sources")
+		assert.Empty(t, policy.Spec.Egress)
+	})
+
+	t.Run("configured rules are added", func(t *testing.T) {
+		cfg := dataPlaneNetworkPolicyConfig{
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}}},
+			},
+			Egress:            []networkingv1.NetworkPolicyEgressRule{backendsEgress},
+			MetricsNamespaces: []string{"monitoring"},
+		}
+		policy := generateDataPlaneNetworkPolicy("kong-system", dataplane, podLabels, ports, cfg)
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Ingress, 4)
+		assert.Equal(t, cfg.Ingress[0], policy.Spec.Ingress[3])
+
+		metricsFrom := policy.Spec.Ingress[2].From
+		require.Len(t, metricsFrom, 2, "metrics should be accessible by the operator and the configured namespaces")
+		assert.Equal(t, policy.Spec.Ingress[0].From[0], metricsFrom[0])
+		assert.Equal(t, []string{"monitoring"}, metricsFrom[1].NamespaceSelector.MatchExpressions[0].Values)
+
+		require.Len(t, policy.Spec.Egress, 2)
+		require.Len(t, policy.Spec.Egress[0].Ports, 2, "DNS should be allowed")
+		assert.Equal(t, 53, policy.Spec.Egress[0].Ports[0].Port.IntValue())
+		assert.Equal(t, backendsEgress, policy.Spec.Egress[1])
+	})
+
+	t.Run("egress from routes without backends only allows DNS", func(t *testing.T) {
+		policy := generateDataPlaneNetworkPolicy("kong-system", dataplane, podLabels, ports, dataPlaneNetworkPolicyConfig{EgressFromRoutes: true})
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+		require.Len(t, policy.Spec.Egress, 1)
+		assert.Empty(t, policy.Spec.Egress[0].To)
+	})
+}
+
+func TestDataPlaneEgressRulesForRoutes(t *testing.T) {
+	gateway := &gwtypes.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gatewayv1.GroupVersion.String(),
+			Kind:       "Gateway",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-gw",
+			Namespace: "test-namespace",
+		},
+		Spec: gwtypes.GatewaySpec{
+			Listeners: []gwtypes.Listener{
+				{
+					Name:     gatewayv1.SectionName("http"),
+					Protocol: gwtypes.HTTPProtocolType,
+					AllowedRoutes: &gwtypes.AllowedRoutes{
+						Namespaces: &gwtypes.RouteNamespaces{
+							From: lo.ToPtr(gwtypes.NamespacesFromSame),
+						},
+					},
+				},
+				{
+					Name:     gatewayv1.SectionName("tcp"),
+					Protocol: gatewayv1.TCPProtocolType,
+					Port:     5432,
+					AllowedRoutes: &gwtypes.AllowedRoutes{
+						Namespaces: &gwtypes.RouteNamespaces{
+							From: lo.ToPtr(gwtypes.NamespacesFromSame),
+						},
+					},
+				},
+			},
+		},
+	}
+	parentRef := func(sectionName string) gwtypes.ParentReference {
+		ref := gwtypes.ParentReference{
+			Name:  gwtypes.ObjectName("test-gw"),
+			Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+			Kind:  lo.ToPtr(gwtypes.Kind("Gateway")),
+		}
+		if sectionName != "" {
+			ref.SectionName = lo.ToPtr(gwtypes.SectionName(sectionName))
+		}
+		return ref
+	}
+	backendRef := func(namespace, name string, port gwtypes.PortNumber) gwtypes.HTTPBackendRef {
+		ref := gwtypes.HTTPBackendRef{
+			BackendRef: gwtypes.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gwtypes.ObjectName(name),
+					Port: lo.ToPtr(port),
+				},
+			},
+		}
+		if namespace != "" {
+			ref.Namespace = lo.ToPtr(gwtypes.Namespace(namespace))
+		}
+		return ref
+	}
+	service := func(namespace, name string, selector map[string]string, ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+				Ports:    ports,
+			},
+		}
+	}
+
+	objects := []client.Object{
+		&gwtypes.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "route",
+				Namespace: "test-namespace",
+			},
+			Spec: gwtypes.HTTPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{
+						{
+							Name:  gwtypes.ObjectName("test-gw"),
+							Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+							Kind:  lo.ToPtr(gwtypes.Kind("Gateway")),
+						},
+					},
+				},
+				Rules: []gwtypes.HTTPRouteRule{
+					{
+						BackendRefs: []gwtypes.HTTPBackendRef{
+							backendRef("", "echo", 80),
+							backendRef("", "echo", 443),
+							backendRef("", "echo", 8080),
+							backendRef("granted", "api", 80),
+							backendRef("not-granted", "api", 80),
+							backendRef("", "external", 80),
+							backendRef("", "missing", 80),
+						},
+					},
+				},
+			},
+		},
+		&gwtypes.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "route",
+				Namespace: "other-namespace",
+			},
+			Spec: gwtypes.HTTPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{
+						func() gwtypes.ParentReference {
+							ref := parentRef("")
+							ref.Namespace = lo.ToPtr(gwtypes.Namespace("test-namespace"))
+							return ref
+						}(),
+					},
+				},
+				Rules: []gwtypes.HTTPRouteRule{
+					{
+						BackendRefs: []gwtypes.HTTPBackendRef{
+							backendRef("test-namespace", "not-allowed", 80),
+						},
+					},
+				},
+			},
+		},
+		&gatewayv1.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "grpc-route",
+				Namespace: "test-namespace",
+			},
+			Spec: gatewayv1.GRPCRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{parentRef("http")},
+				},
+				Rules: []gatewayv1.GRPCRouteRule{
+					{
+						BackendRefs: []gatewayv1.GRPCBackendRef{
+							{BackendRef: backendRef("", "grpc", 9090).BackendRef},
+						},
+					},
+				},
+			},
+		},
+		&gatewayv1alpha2.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tcp-route",
+				Namespace: "test-namespace",
+			},
+			Spec: gatewayv1alpha2.TCPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{parentRef("tcp")},
+				},
+				Rules: []gatewayv1alpha2.TCPRouteRule{
+					{
+						BackendRefs: []gwtypes.BackendRef{
+							backendRef("", "db", 5432).BackendRef,
+						},
+					},
+				},
+			},
+		},
+		&gatewayv1alpha2.UDPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "udp-route",
+				Namespace: "test-namespace",
+			},
+			Spec: gatewayv1alpha2.UDPRouteSpec{
+				CommonRouteSpec: gwtypes.CommonRouteSpec{
+					ParentRefs: []gwtypes.ParentReference{parentRef("tcp")},
+				},
+				Rules: []gatewayv1alpha2.UDPRouteRule{
+					{
+						BackendRefs: []gwtypes.BackendRef{
+							backendRef("", "not-allowed", 53).BackendRef,
+						},
+					},
+				},
+			},
+		},
+		&gatewayv1beta1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "allow-routes",
+				Namespace: "granted",
+			},
+			Spec: gatewayv1beta1.ReferenceGrantSpec{
+				From: []gatewayv1beta1.ReferenceGrantFrom{
+					{
+						Group:     gatewayv1beta1.GroupName,
+						Kind:      "HTTPRoute",
+						Namespace: "test-namespace",
+					},
+				},
+				To: []gatewayv1beta1.ReferenceGrantTo{
+					{Kind: "Service"},
+				},
+			},
+		},
+		service("test-namespace", "echo", map[string]string{"app": "echo"},
+			corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")},
+			corev1.ServicePort{Port: 443, TargetPort: intstr.FromInt(8443)},
+			corev1.ServicePort{Port: 9000, TargetPort: intstr.FromInt(9000)},
+		),
+		service("granted", "api", map[string]string{"app": "api"},
+			corev1.ServicePort{Port: 80, Protocol: corev1.ProtocolTCP},
+		),
+		service("not-granted", "api", map[string]string{"app": "api"},
+			corev1.ServicePort{Port: 80},
+		),
+		service("test-namespace", "external", nil,
+			corev1.ServicePort{Port: 80},
+		),
+		service("test-namespace", "grpc", map[string]string{"app": "grpc"},
+			corev1.ServicePort{Port: 9090},
+		),
+		service("test-namespace", "db", nil,
+			corev1.ServicePort{Name: "postgres", Port: 5432},
+		),
+		service("test-namespace", "not-allowed", map[string]string{"app": "not-allowed"},
+			corev1.ServicePort{Port: 53, Protocol: corev1.ProtocolUDP},
+			corev1.ServicePort{Port: 80},
+		),
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-ipv4",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "db"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"10.0.0.2"}},
+				{Addresses: []string{"10.0.0.1"}},
+			},
+			Ports: []discoveryv1.EndpointPort{
+				{Name: lo.ToPtr("postgres"), Port: lo.ToPtr(int32(5432))},
+				{Name: lo.ToPtr("metrics"), Port: lo.ToPtr(int32(9187))},
+			},
+		},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "db-fqdn",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "db"},
+			},
+			AddressType: discoveryv1.AddressTypeFQDN,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"db.example.com"}},
+			},
+			Ports: []discoveryv1.EndpointPort{
+				{Name: lo.ToPtr("postgres"), Port: lo.ToPtr(int32(5432))},
+			},
+		},
+	}
+
+	cl := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(gateway).
+		WithObjects(objects...).
+		Build()
+
+	rules, err := dataPlaneEgressRulesForRoutes(t.Context(), cl, gateway)
+	require.NoError(t, err)
+
+	protocolTCP := corev1.ProtocolTCP
+	assert.Equal(t, []networkingv1.NetworkPolicyEgressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromInt32(80))},
+			},
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{namespaceNameLabel: "granted"},
+					},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "api"},
+					},
+				},
+			},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromInt32(5432))},
+			},
+			To: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.1/32"}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.2/32"}},
+			},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromString("http"))},
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromInt(8443))},
+			},
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{namespaceNameLabel: "test-namespace"},
+					},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "echo"},
+					},
+				},
+			},
+		},
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{Protocol: &protocolTCP, Port: lo.ToPtr(intstr.FromInt32(9090))},
+			},
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{namespaceNameLabel: "test-namespace"},
+					},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "grpc"},
+					},
+				},
+			},
+		},
+	}, rules)
+}
+
+func TestDataPlaneEgressRulesForKonnect(t *testing.T) {
+	dataplane := func(extensions ...commonv1alpha1.ExtensionRef) *operatorv1beta1.DataPlane {
+		return &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp",
+				Namespace: "test-namespace",
+			},
+			Spec: operatorv1beta1.DataPlaneSpec{
+				DataPlaneOptions: operatorv1beta1.DataPlaneOptions{
+					Extensions: extensions,
+				},
+			},
+		}
+	}
+	konnectExtensionRef := commonv1alpha1.ExtensionRef{
+		Group: konnectv1alpha1.SchemeGroupVersion.Group,
+		Kind:  konnectv1alpha2.KonnectExtensionKind,
+		NamespacedRef: commonv1alpha1.NamespacedRef{
+			Name: "konnect",
+		},
+	}
+	protocolTCP := corev1.ProtocolTCP
+	konnectRule := func(ports ...int32) []networkingv1.NetworkPolicyEgressRule {
+		rule := networkingv1.NetworkPolicyEgressRule{
+			To: []networkingv1.NetworkPolicyPeer{
+				{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}},
+				{IPBlock: &networkingv1.IPBlock{CIDR: "::/0"}},
+			},
+		}
+		for _, port := range ports {
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{
+				Protocol: &protocolTCP,
+				Port:     lo.ToPtr(intstr.FromInt32(port)),
+			})
+		}
+		return []networkingv1.NetworkPolicyEgressRule{rule}
+	}
+
+	testCases := []struct {
+		name          string
+		dataplane     *operatorv1beta1.DataPlane
+		objects       []client.Object
+		expectedRules []networkingv1.NetworkPolicyEgressRule
+	}{
+		{
+			name:      "DataPlane not connected to Konnect",
+			dataplane: dataplane(),
+		},
+		{
+			name:          "KonnectExtension without endpoints defaults to the HTTPS port",
+			dataplane:     dataplane(konnectExtensionRef),
+			expectedRules: konnectRule(443),
+		},
+		{
+			name:      "ports of the KonnectExtension's endpoints",
+			dataplane: dataplane(konnectExtensionRef),
+			objects: []client.Object{
+				&konnectv1alpha2.KonnectExtension{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "konnect",
+						Namespace: "test-namespace",
+					},
+					Status: konnectv1alpha2.KonnectExtensionStatus{
+						Konnect: &konnectv1alpha2.KonnectExtensionControlPlaneStatus{
+							Endpoints: konnectv1alpha2.KonnectEndpoints{
+								ControlPlaneEndpoint: "https://7b46471d3b.us.cp0.konghq.com:8443",
+								TelemetryEndpoint:    "https://7b46471d3b.us.tp0.konghq.com",
+							},
+						},
+					},
+				},
+			},
+			expectedRules: konnectRule(443, 8443),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cl := fakectrlruntimeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tc.objects...).
+				Build()
+
+			rules, err := dataPlaneEgressRulesForKonnect(t.Context(), cl, tc.dataplane)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRules, rules)
+		})
+	}
+}
+
+func TestGenerateCiliumNetworkPolicy(t *testing.T) {
+	protocolUDP := corev1.ProtocolUDP
+	port := intstr.FromInt(8000)
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    "default",
+			GenerateName: "dp-limit-admin-api-",
+			Labels:       map[string]string{consts.GatewayOperatorManagedByLabel: consts.GatewayManagedLabelValue},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "dp"},
+			},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Port: &port}},
+					From: []networkingv1.NetworkPolicyPeer{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{namespaceNameLabel: "kong-system"},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"app": "kong-operator"},
+							},
+						},
+					},
+				},
+			},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocolUDP}},
+					To: []networkingv1.NetworkPolicyPeer{
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}},
+					},
+				},
+				{},
+			},
+		},
+	}
+
+	cnp := generateCiliumNetworkPolicy(policy)
+	assert.Equal(t, "cilium.io/v2", cnp.GetAPIVersion())
+	assert.Equal(t, "CiliumNetworkPolicy", cnp.GetKind())
+	assert.Equal(t, "dp-limit-admin-api-", cnp.GetGenerateName())
+	assert.Equal(t, policy.Labels, cnp.GetLabels())
+	assert.Equal(t, map[string]any{
+		"endpointSelector": map[string]any{
+			"matchLabels": map[string]any{"app": "dp"},
+		},
+		"ingress": []any{
+			map[string]any{
+				"fromEndpoints": []any{
+					map[string]any{
+						"matchLabels": map[string]any{
+							"app": "kong-operator",
+							"k8s:io.cilium.k8s.namespace.labels.kubernetes.io/metadata.name": "kong-system",
+						},
+						"matchExpressions": []any{
+							map[string]any{"key": "k8s:io.kubernetes.pod.namespace", "operator": "Exists"},
+						},
+					},
+				},
+				"toPorts": []any{
+					map[string]any{
+						"ports": []any{
+							map[string]any{"port": "8000", "protocol": "TCP"},
+						},
+					},
+				},
+			},
+		},
+		"egress": []any{
+			map[string]any{
+				"toCIDRSet": []any{
+					map[string]any{"cidr": "10.0.0.0/8", "except": []any{"10.1.0.0/16"}},
+				},
+				"toPorts": []any{
+					map[string]any{
+						"ports": []any{
+							map[string]any{"port": "0", "protocol": "UDP"},
+						},
+					},
+				},
+			},
+			map[string]any{
+				"toEntities": []any{"all"},
+			},
+		},
+	}, cnp.Object["spec"])
+}
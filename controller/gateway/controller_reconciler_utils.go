@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -14,8 +15,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,8 +38,6 @@ import (
 	"github.com/kong/kong-operator/pkg/consts"
 	gatewayutils "github.com/kong/kong-operator/pkg/utils/gateway"
 	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
-	k8sreduce "github.com/kong/kong-operator/pkg/utils/kubernetes/reduce"
-	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
 )
 
 // -----------------------------------------------------------------------------
@@ -293,174 +292,6 @@ func (r *Reconciler) getGatewayConfigForGatewayClass(
 	return &gatewayConfig, nil
 }
 
-func (r *Reconciler) ensureDataPlaneHasNetworkPolicy(
-	ctx context.Context,
-	gateway *gwtypes.Gateway,
-	dataplane *operatorv1beta1.DataPlane,
-) (createdOrUpdate bool, err error) {
-	networkPolicies, err := gatewayutils.ListNetworkPoliciesForGateway(ctx, r.Client, gateway)
-	if err != nil {
-		return false, err
-	}
-
-	count := len(networkPolicies)
-	if count > 1 {
-		if err := k8sreduce.ReduceNetworkPolicies(ctx, r.Client, networkPolicies); err != nil {
-			return false, err
-		}
-		return false, errors.New("number of networkPolicies reduced")
-	}
-
-	// generate the network policy that allows the KO pod to access the admin APIs of dataplane pods.
-	generatedPolicy, err := generateDataPlaneNetworkPolicy(r.Namespace, dataplane, r.PodLabels)
-	if err != nil {
-		return false, fmt.Errorf("failed generating network policy for DataPlane %s: %w", dataplane.Name, err)
-	}
-	k8sutils.SetOwnerForObject(generatedPolicy, gateway)
-	gatewayutils.LabelObjectAsGatewayManaged(generatedPolicy)
-
-	if count == 1 {
-		var (
-			metaUpdated    bool
-			existingPolicy = &networkPolicies[0]
-			old            = existingPolicy.DeepCopy()
-		)
-		metaUpdated, existingPolicy.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingPolicy.ObjectMeta, generatedPolicy.ObjectMeta)
-
-		if k8sresources.EnsureNetworkPolicyIsUpdated(existingPolicy, generatedPolicy) || metaUpdated {
-			if err := r.Patch(ctx, existingPolicy, client.MergeFrom(old)); err != nil {
-				return false, fmt.Errorf("failed updating DataPlane's NetworkPolicy %s: %w", existingPolicy.Name, err)
-			}
-			return true, nil
-		}
-		return false, nil
-	}
-
-	return true, r.Create(ctx, generatedPolicy)
-}
-
-// generateDataPlaneNetworkPolicy generates the NetworkPolicy that allows the KO pod to access admin API of dataplane pods.
-// the params `namespace` and `podLabels` are namespace and labels of the KO pod itself, and `dataplane` is the target dataplane.
-func generateDataPlaneNetworkPolicy(
-	namespace string,
-	dataplane *operatorv1beta1.DataPlane,
-	podLabels map[string]string,
-) (*networkingv1.NetworkPolicy, error) {
-	var (
-		protocolTCP     = corev1.ProtocolTCP
-		adminAPISSLPort = intstr.FromInt(consts.DataPlaneAdminAPIPort)
-		proxyPort       = intstr.FromInt(consts.DataPlaneProxyPort)
-		proxySSLPort    = intstr.FromInt(consts.DataPlaneProxySSLPort)
-		metricsPort     = intstr.FromInt(consts.DataPlaneMetricsPort)
-		// The label keys to match Kong operator pod.
-		// To not create new NetworkPolicy on upgrade of , we just keep the keys marking the application
-		// and remove the keys related to versions such as `version`,`pod-template-hash`,`helm.sh/chart`.
-		podLabelSelectorKeys = []string{
-			"app",
-			"app.kubernetes.io/component",
-			"app.kubernetes.io/instance",
-			"app.kubernetes.io/name",
-			"control-plane",
-		}
-	)
-
-	// Check if KONG_PROXY_LISTEN and/or KONG_ADMIN_LISTEN are set in
-	// DataPlaneDeploymentOptions and in that's the case then update NetworkPolicy
-	// ports accordingly to allow communication on those ports.
-	//
-	// Note: for now only direct env variable manipulation is allowed (through
-	// the .Env field in DataPlaneDeploymentOptions). EnvFrom is not taken into
-	// account when updating NetworkPolicy ports.
-	dpOpts := dataplane.Spec.DataPlaneOptions
-	container := k8sutils.GetPodContainerByName(&dpOpts.Deployment.PodTemplateSpec.Spec, consts.DataPlaneProxyContainerName)
-	if proxyListen := k8sutils.EnvValueByName(container.Env, "KONG_PROXY_LISTEN"); proxyListen != "" {
-		kongListenConfig, err := parseKongListenEnv(proxyListen)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing KONG_PROXY_LISTEN env: %w", err)
-		}
-		if kongListenConfig.Endpoint != nil {
-			proxyPort = intstr.FromInt(kongListenConfig.Endpoint.Port)
-		}
-		if kongListenConfig.SSLEndpoint != nil {
-			proxySSLPort = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
-		}
-	}
-	if adminListen := k8sutils.EnvValueByName(container.Env, "KONG_ADMIN_LISTEN"); adminListen != "" {
-		kongListenConfig, err := parseKongListenEnv(adminListen)
-		if err != nil {
-			return nil, fmt.Errorf("failed parsing KONG_ADMIN_LISTEN env: %w", err)
-		}
-		if kongListenConfig.SSLEndpoint != nil {
-			adminAPISSLPort = intstr.FromInt(kongListenConfig.SSLEndpoint.Port)
-		}
-	}
-
-	// Construct the policy to allow the KO pod to access DataPlane admin APIs.
-	policyPeerForControllerPod := networkingv1.NetworkPolicyPeer{
-		NamespaceSelector: &metav1.LabelSelector{
-			MatchLabels: map[string]string{
-				"kubernetes.io/metadata.name": namespace,
-			},
-		},
-	}
-
-	if len(podLabels) > 0 {
-		matchPodLabels := map[string]string{}
-		for _, key := range podLabelSelectorKeys {
-			value, ok := podLabels[key]
-			if ok {
-				matchPodLabels[key] = value
-			}
-		}
-		policyPeerForControllerPod.PodSelector = &metav1.LabelSelector{
-			MatchLabels: matchPodLabels,
-		}
-	}
-	limitAdminAPIIngress := networkingv1.NetworkPolicyIngressRule{
-		Ports: []networkingv1.NetworkPolicyPort{
-			{Protocol: &protocolTCP, Port: &adminAPISSLPort},
-		},
-		From: []networkingv1.NetworkPolicyPeer{
-			policyPeerForControllerPod,
-		},
-	}
-
-	allowProxyIngress := networkingv1.NetworkPolicyIngressRule{
-		Ports: []networkingv1.NetworkPolicyPort{
-			{Protocol: &protocolTCP, Port: &proxyPort},
-			{Protocol: &protocolTCP, Port: &proxySSLPort},
-		},
-	}
-
-	allowMetricsIngress := networkingv1.NetworkPolicyIngressRule{
-		Ports: []networkingv1.NetworkPolicyPort{
-			{Protocol: &protocolTCP, Port: &metricsPort},
-		},
-	}
-
-	return &networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace:    dataplane.Namespace,
-			GenerateName: k8sutils.TrimGenerateName(fmt.Sprintf("%s-limit-admin-api-", dataplane.Name)),
-		},
-		Spec: networkingv1.NetworkPolicySpec{
-			PodSelector: metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"app": dataplane.Name,
-				},
-			},
-			PolicyTypes: []networkingv1.PolicyType{
-				networkingv1.PolicyTypeIngress,
-			},
-			Ingress: []networkingv1.NetworkPolicyIngressRule{
-				limitAdminAPIIngress,
-				allowProxyIngress,
-				allowMetricsIngress,
-			},
-		},
-	}, nil
-}
-
 // ensureOwnedControlPlanesDeleted deletes all controlplanes owned by gateway.
 // returns true if at least one controlplane resource is deleted.
 func (r *Reconciler) ensureOwnedControlPlanesDeleted(ctx context.Context, gateway *gwtypes.Gateway) (bool, error) {
@@ -594,13 +425,10 @@ func (l listenerConditionAwareT) SetConditions(conditions []metav1.Condition) {
 
 // supportedRoutesByProtocol returns a map of maps to relate each protocolType with the
 // set of supported Routes.
-//
-// Note: the inner maps have only one element as for now, but in future they will be improved,
-// as each protocolType can be compatible with many different route types.
 func supportedRoutesByProtocol() map[gatewayv1.ProtocolType]map[gatewayv1.Kind]struct{} {
 	return map[gatewayv1.ProtocolType]map[gatewayv1.Kind]struct{}{
-		gatewayv1.HTTPProtocolType:  {"HTTPRoute": {}},
-		gatewayv1.HTTPSProtocolType: {"HTTPRoute": {}},
+		gatewayv1.HTTPProtocolType:  {"HTTPRoute": {}, "GRPCRoute": {}},
+		gatewayv1.HTTPSProtocolType: {"HTTPRoute": {}, "GRPCRoute": {}},
 
 		// L4 routes not supported yet
 		// gatewayv1.TLSProtocolType:   {"TLSRoute": {}},
@@ -699,24 +527,34 @@ func (g *gatewayConditionsAndListenersAwareT) setAcceptedAndAttachedRoutes(ctx c
 // It takes into account the AllowedRoutes field in the listener spec and route's ParentRefs.
 // It returns the number of attached routes and an error.
 func countAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gateway, listener gwtypes.Listener, cl client.Client) (int32, error) {
+	routes, err := listAttachedRoutesForGatewayListener(ctx, g, listener, cl)
+	if err != nil {
+		return 0, err
+	}
+	return int32(len(routes)), nil
+}
+
+// listAttachedRoutesForGatewayListener lists the routes (HTTPRoutes and GRPCRoutes) attached to a given listener.
+// It takes into account the AllowedRoutes field in the listener spec and route's ParentRefs.
+func listAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gateway, listener gwtypes.Listener, cl client.Client) ([]client.Object, error) {
 	allowedRoutes := listener.AllowedRoutes
 	// Gateway API defines a default value for AllowedRoutes, so if this is nil there's something wrong.
 	if allowedRoutes == nil {
-		return 0, fmt.Errorf("AllowedRoutes is nil for listener %s in gateway %s",
+		return nil, fmt.Errorf("AllowedRoutes is nil for listener %s in gateway %s",
 			listener.Name, client.ObjectKeyFromObject(g),
 		)
 	}
 
 	var (
-		count int32
-		opts  []client.ListOption
+		attached []client.Object
+		opts     []client.ListOption
 	)
 
 	namespaces := allowedRoutes.Namespaces
 	// Gateway API defines a default value for AllowedRoutes.Namespaces, so
 	// if this is nil there's something wrong.
 	if namespaces == nil || namespaces.From == nil {
-		return 0, fmt.Errorf("AllowedRoutes.Namespaces is nil for listener %s in gateway %s",
+		return nil, fmt.Errorf("AllowedRoutes.Namespaces is nil for listener %s in gateway %s",
 			listener.Name, client.ObjectKeyFromObject(g),
 		)
 	}
@@ -724,7 +562,7 @@ func countAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gatew
 	switch *namespaces.From {
 	case gatewayv1.NamespacesFromNone:
 		// No namespaces are allowed, so no routes can be attached.
-		return 0, nil
+		return nil, nil
 	case gatewayv1.NamespacesFromAll:
 	case gatewayv1.NamespacesFromSame:
 		opts = append(opts, client.InNamespace(g.Namespace))
@@ -733,13 +571,13 @@ func countAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gatew
 
 		s, err := metav1.LabelSelectorAsSelector(listener.AllowedRoutes.Namespaces.Selector)
 		if err != nil {
-			return 0, fmt.Errorf("failed to create requirement for namespace selector (for Gateway %s): %w",
+			return nil, fmt.Errorf("failed to create requirement for namespace selector (for Gateway %s): %w",
 				client.ObjectKeyFromObject(g), err,
 			)
 		}
 		reqs, selectable := s.Requirements()
 		if !selectable {
-			return 0, fmt.Errorf("namespace selector is not selectable (for Gateway %s)", client.ObjectKeyFromObject(g))
+			return nil, fmt.Errorf("namespace selector is not selectable (for Gateway %s)", client.ObjectKeyFromObject(g))
 		}
 		labelSelector := labels.NewSelector()
 		for _, req := range reqs {
@@ -749,16 +587,16 @@ func countAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gatew
 			LabelSelector: labelSelector,
 		}); err != nil {
 			if k8serrors.IsNotFound(err) {
-				return 0, nil
+				return nil, nil
 			}
-			return 0, fmt.Errorf("failed to list namespaces for gateway %s: %w", g.Name, err)
+			return nil, fmt.Errorf("failed to list namespaces for gateway %s: %w", g.Name, err)
 		}
 
 		switch len(nsList.Items) {
 		case 0:
 			// If no namespaces matching the selector are found, set the AttachedRoutes to 0 as
 			// there are no routes to attach.
-			return 0, nil
+			return nil, nil
 
 		default:
 			for _, ns := range nsList.Items {
@@ -767,64 +605,63 @@ func countAttachedRoutesForGatewayListener(ctx context.Context, g *gwtypes.Gatew
 		}
 	}
 
-	kindsForProtocol, protocolSupported := supportedRoutesByProtocol()[listener.Protocol]
-	switch len(allowedRoutes.Kinds) {
-	case 0:
-		if protocolSupported {
-			for k := range kindsForProtocol {
-				// NOTE: Count other types of routes when they are supported.
-
-				switch k {
-				case "HTTPRoute":
-					httpRoutes, err := gatewayutils.ListHTTPRoutesForGateway(ctx, cl, g, opts...)
-					if err != nil {
-						return 0, fmt.Errorf(
-							"failed to list HTTPRoutes for Gateway %s when counting AttachedRoutes: %w",
-							client.ObjectKeyFromObject(g), err,
-						)
-					}
-					count += countAttachedHTTPRoutes(listener.Name, httpRoutes)
-				default:
-					return 0, fmt.Errorf("unsupported route kind: %T", k)
+	kindsForProtocol := supportedRoutesByProtocol()[listener.Protocol]
+	kinds := lo.Keys(kindsForProtocol)
+	if len(allowedRoutes.Kinds) > 0 {
+		kinds = lo.Filter(kinds, func(kind gatewayv1.Kind, _ int) bool {
+			return lo.ContainsBy(allowedRoutes.Kinds, func(gvk gatewayv1.RouteGroupKind) bool {
+				return gvk.Kind == kind && gvk.Group != nil && *gvk.Group == gatewayv1.Group(gatewayv1.GroupVersion.Group)
+			})
+		})
+	}
+	slices.Sort(kinds)
+
+	for _, kind := range kinds {
+		switch kind {
+		case "HTTPRoute":
+			httpRoutes, err := gatewayutils.ListHTTPRoutesForGateway(ctx, cl, g, opts...)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to list HTTPRoutes for Gateway %s when listing attached routes: %w",
+					client.ObjectKeyFromObject(g), err,
+				)
+			}
+			for i := range httpRoutes {
+				if isRouteAttachedToListener(listener.Name, httpRoutes[i].Spec.ParentRefs) {
+					attached = append(attached, &httpRoutes[i])
 				}
 			}
-		}
-	default:
-		if lo.ContainsBy(allowedRoutes.Kinds, func(gvk gatewayv1.RouteGroupKind) bool {
-			if _, ok := kindsForProtocol[gvk.Kind]; !ok {
-				return false
+		case "GRPCRoute":
+			grpcRoutes, err := gatewayutils.ListGRPCRoutesForGateway(ctx, cl, g, opts...)
+			// GRPCRoute CRD is optional: no GRPCRoutes can be attached when it's not installed.
+			if meta.IsNoMatchError(err) {
+				continue
 			}
-			return gvk.Group != nil && *gvk.Group == gatewayv1.Group(gatewayv1.GroupVersion.Group)
-		}) {
-			httpRoutes, err := gatewayutils.ListHTTPRoutesForGateway(ctx, cl, g, opts...)
 			if err != nil {
-				return 0, fmt.Errorf(
-					"failed to list HTTPRoutes for Gateway %s when counting AttachedRoutes: %w",
+				return nil, fmt.Errorf(
+					"failed to list GRPCRoutes for Gateway %s when listing attached routes: %w",
 					client.ObjectKeyFromObject(g), err,
 				)
 			}
-
-			count += countAttachedHTTPRoutes(listener.Name, httpRoutes)
+			for i := range grpcRoutes {
+				if isRouteAttachedToListener(listener.Name, grpcRoutes[i].Spec.ParentRefs) {
+					attached = append(attached, &grpcRoutes[i])
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported route kind: %s", kind)
 		}
 	}
 
-	return count, nil
+	return attached, nil
 }
 
-// countAttachedHTTPRoutes counts the number of attached HTTPRoutes for a given listener,
+// isRouteAttachedToListener returns true if the route's parentRefs attach it to a given listener,
 // taking into account the ParentRefs' sectionName.
-func countAttachedHTTPRoutes(listenerName gatewayv1.SectionName, httpRoutes []gatewayv1.HTTPRoute) int32 {
-	var count int32
-
-	for _, httpRoute := range httpRoutes {
-		if lo.ContainsBy(httpRoute.Spec.ParentRefs, func(parentRef gatewayv1.ParentReference) bool {
-			return parentRef.SectionName == nil || *parentRef.SectionName == listenerName
-		}) {
-			count++
-		}
-	}
-
-	return count
+func isRouteAttachedToListener(listenerName gatewayv1.SectionName, parentRefs []gatewayv1.ParentReference) bool {
+	return lo.ContainsBy(parentRefs, func(parentRef gatewayv1.ParentReference) bool {
+		return parentRef.SectionName == nil || *parentRef.SectionName == listenerName
+	})
 }
 
 // setConflicted sets the gateway Conflicted condition according to the Gateway API specification.
@@ -1028,8 +865,9 @@ func getSupportedKindsWithResolvedRefsCondition(ctx context.Context, c client.Cl
 	}
 
 	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
-		supportedRoutes := supportedRoutesByProtocol()[listener.Protocol]
-		for routeKind := range supportedRoutes {
+		supportedRoutes := lo.Keys(supportedRoutesByProtocol()[listener.Protocol])
+		slices.Sort(supportedRoutes)
+		for _, routeKind := range supportedRoutes {
 			supportedKinds = append(supportedKinds, gatewayv1.RouteGroupKind{
 				Group: (*gatewayv1.Group)(&gatewayv1.GroupVersion.Group),
 				Kind:  routeKind,
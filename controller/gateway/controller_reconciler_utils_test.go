@@ -808,6 +808,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				Protocol: gwtypes.HTTPProtocolType,
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -866,6 +870,41 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				ObservedGeneration: generation,
 			},
 		},
+		{
+			name: "no tls, HTTP protocol, HTTP and GRPC routes",
+			listener: gwtypes.Listener{
+				Protocol: gwtypes.HTTPProtocolType,
+				AllowedRoutes: &gwtypes.AllowedRoutes{
+					Kinds: []gwtypes.RouteGroupKind{
+						{
+							Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+							Kind:  "HTTPRoute",
+						},
+						{
+							Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+							Kind:  "GRPCRoute",
+						},
+					},
+				},
+			},
+			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "HTTPRoute",
+				},
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
+			},
+			expectedResolvedRefsCondition: metav1.Condition{
+				Type:               string(gatewayv1.ListenerConditionResolvedRefs),
+				Status:             metav1.ConditionTrue,
+				Reason:             string(gatewayv1.ListenerReasonResolvedRefs),
+				Message:            "Listeners' references are accepted.",
+				ObservedGeneration: generation,
+			},
+		},
 		{
 			name:             "tls well-formed, no cross-namespace reference",
 			gatewayNamespace: "default",
@@ -893,6 +932,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -933,6 +976,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -964,6 +1011,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -992,6 +1043,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -1022,6 +1077,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -1062,6 +1121,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -1126,6 +1189,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -1167,6 +1234,10 @@ func TestGetSupportedKindsWithResolvedRefsCondition(t *testing.T) {
 				},
 			},
 			expectedSupportedKinds: []gwtypes.RouteGroupKind{
+				{
+					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+					Kind:  "GRPCRoute",
+				},
 				{
 					Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
 					Kind:  "HTTPRoute",
@@ -1464,6 +1535,85 @@ func TestCountAttachedRoutesForGatewayListener(t *testing.T) {
 			ExpectedRoutes: []int32{1},
 			ExpectedError:  []error{nil},
 		},
+		{
+			Name: "1 HTTPRoute and 1 GRPCRoute in the same namespace as the Gateway",
+			Gateway: gwtypes.Gateway{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: gatewayv1.GroupVersion.String(),
+					Kind:       "Gateway",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gw",
+					Namespace: "test-namespace",
+				},
+				Spec: gwtypes.GatewaySpec{
+					Listeners: []gwtypes.Listener{
+						{
+							Name:     gatewayv1.SectionName("http"),
+							Protocol: gwtypes.HTTPProtocolType,
+							AllowedRoutes: &gwtypes.AllowedRoutes{
+								Namespaces: &gwtypes.RouteNamespaces{
+									From: lo.ToPtr(gwtypes.NamespacesFromSame),
+								},
+							},
+						},
+						{
+							Name:     gatewayv1.SectionName("http-only"),
+							Protocol: gwtypes.HTTPProtocolType,
+							AllowedRoutes: &gwtypes.AllowedRoutes{
+								Namespaces: &gwtypes.RouteNamespaces{
+									From: lo.ToPtr(gwtypes.NamespacesFromSame),
+								},
+								Kinds: []gwtypes.RouteGroupKind{
+									{
+										Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+										Kind:  "HTTPRoute",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Objects: []client.Object{
+				&gwtypes.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "route-1",
+						Namespace: "test-namespace",
+					},
+					Spec: gwtypes.HTTPRouteSpec{
+						CommonRouteSpec: gwtypes.CommonRouteSpec{
+							ParentRefs: []gwtypes.ParentReference{
+								{
+									Name:  gwtypes.ObjectName("test-gw"),
+									Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+									Kind:  lo.ToPtr(gwtypes.Kind("Gateway")),
+								},
+							},
+						},
+					},
+				},
+				&gwtypes.GRPCRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "grpc-route-1",
+						Namespace: "test-namespace",
+					},
+					Spec: gatewayv1.GRPCRouteSpec{
+						CommonRouteSpec: gwtypes.CommonRouteSpec{
+							ParentRefs: []gwtypes.ParentReference{
+								{
+									Name:  gwtypes.ObjectName("test-gw"),
+									Group: (*gwtypes.Group)(&gatewayv1.GroupVersion.Group),
+									Kind:  lo.ToPtr(gwtypes.Kind("Gateway")),
+								},
+							},
+						},
+					},
+				},
+			},
+			ExpectedRoutes: []int32{2, 1},
+			ExpectedError:  []error{nil, nil},
+		},
 		{
 			Name: "1 HTTPRoute in a different namespace than the Gateway",
 			Gateway: gwtypes.Gateway{
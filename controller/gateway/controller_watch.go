@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,6 +30,7 @@ import (
 	gwtypes "github.com/kong/kong-operator/internal/types"
 	"github.com/kong/kong-operator/internal/utils/gatewayclass"
 	"github.com/kong/kong-operator/internal/utils/index"
+	"github.com/kong/kong-operator/pkg/consts"
 	"github.com/kong/kong-operator/pkg/vars"
 )
 
@@ -276,43 +281,209 @@ func (r *Reconciler) listManagedGatewaysInNamespace(ctx context.Context, obj cli
 	return recs
 }
 
-// listGatewaysAttachedByHTTPRoute is a watch predicate which finds all Gateways mentioned
-// in HTTPRoutes' Parents field.
-func (r *Reconciler) listGatewaysAttachedByHTTPRoute(ctx context.Context, obj client.Object) []reconcile.Request {
+// listGatewaysAttachedByRoute is a watch predicate which finds all Gateways mentioned
+// in the Parents field of HTTPRoutes, GRPCRoutes, TCPRoutes, TLSRoutes and UDPRoutes.
+func (r *Reconciler) listGatewaysAttachedByRoute(ctx context.Context, obj client.Object) []reconcile.Request {
 	logger := ctrllog.FromContext(ctx)
 
-	httpRoute, ok := obj.(*gatewayv1beta1.HTTPRoute)
+	route, ok := backendRouteFor(obj)
 	if !ok {
 		logger.Error(
 			fmt.Errorf("unexpected object type"),
-			"HTTPRoute watch predicate received unexpected object type",
-			"expected", "*gatewayapi.HTTPRoute", "found", reflect.TypeOf(obj),
+			"Route watch predicate received unexpected object type",
+			"expected", "*gatewayapi.HTTPRoute, *gatewayapi.GRPCRoute, *gatewayapi.TCPRoute, *gatewayapi.TLSRoute or *gatewayapi.UDPRoute",
+			"found", reflect.TypeOf(obj),
 		)
 		return nil
 	}
+	return r.listGatewaysAttachedByBackendRoute(ctx, route)
+}
+
+func (r *Reconciler) listGatewaysAttachedByBackendRoute(ctx context.Context, route backendRoute) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
 	gateways := &gatewayv1.GatewayList{}
 	if err := r.List(ctx, gateways); err != nil {
-		logger.Error(err, "Failed to list gateways in watch", "HTTPRoute", httpRoute.Name)
+		logger.Error(err, "Failed to list gateways in watch", string(route.kind), route.name)
 		return nil
 	}
 	var recs []reconcile.Request
-	for _, gateway := range gateways.Items {
-		for _, parentRef := range httpRoute.Spec.ParentRefs {
-			if parentRef.Group != nil && string(*parentRef.Group) == gatewayv1.GroupName &&
-				parentRef.Kind != nil && string(*parentRef.Kind) == "Gateway" &&
-				string(parentRef.Name) == gateway.Name {
-				recs = append(recs, reconcile.Request{
-					NamespacedName: types.NamespacedName{
-						Namespace: gateway.Namespace,
-						Name:      gateway.Name,
-					},
-				})
-			}
+	for i := range gateways.Items {
+		gateway := &gateways.Items[i]
+		if lo.ContainsBy(route.parentRefs, func(parentRef gatewayv1.ParentReference) bool {
+			return isParentRefForGateway(parentRef, route.namespace, gateway)
+		}) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(gateway),
+			})
 		}
 	}
 	return recs
 }
 
+// listGatewaysForBackendService is a watch predicate which finds all Gateways mentioned
+// in the Parents field of routes referencing the Service in their backendRefs, so that
+// the egress of their DataPlanes follows the changes of the Service.
+func (r *Reconciler) listGatewaysForBackendService(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	service, ok := obj.(*corev1.Service)
+	if !ok {
+		logger.Error(
+			fmt.Errorf("unexpected object type"),
+			"Service watch predicate received unexpected object type",
+			"expected", "*corev1.Service", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+	return r.listGatewaysRoutingToService(ctx, client.ObjectKeyFromObject(service))
+}
+
+// listGatewaysForBackendEndpointSlice is a watch predicate which finds all Gateways mentioned
+// in the Parents field of routes referencing the EndpointSlice's Service in their backendRefs,
+// so that the egress of their DataPlanes follows the endpoints of Services without a selector.
+func (r *Reconciler) listGatewaysForBackendEndpointSlice(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	endpointSlice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		logger.Error(
+			fmt.Errorf("unexpected object type"),
+			"EndpointSlice watch predicate received unexpected object type",
+			"expected", "*discoveryv1.EndpointSlice", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+	serviceName, ok := endpointSlice.Labels[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil
+	}
+	return r.listGatewaysRoutingToService(ctx, types.NamespacedName{
+		Namespace: endpointSlice.Namespace,
+		Name:      serviceName,
+	})
+}
+
+// isEndpointSliceOfServiceWithoutSelector returns true if the EndpointSlice isn't managed
+// by the EndpointSlice controller, which only manages the EndpointSlices of Services with
+// a selector. Their endpoints are already selected by the Pods' labels.
+func isEndpointSliceOfServiceWithoutSelector(obj client.Object) bool {
+	return obj.GetLabels()[discoveryv1.LabelManagedBy] != endpointSliceControllerName
+}
+
+func (r *Reconciler) listGatewaysRoutingToService(ctx context.Context, service types.NamespacedName) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	if !r.egressFromRoutesEnabled(ctx) {
+		return nil
+	}
+
+	var recs []reconcile.Request
+	for _, kind := range backendRouteKinds {
+		routes, err := listBackendRoutes(ctx, r.Client, kind, client.MatchingFields{
+			index.BackendServiceIndex: service.String(),
+		})
+		if err != nil {
+			logger.Error(err, "Failed to list routes in watch", "Service", service)
+			continue
+		}
+		for _, route := range routes {
+			recs = append(recs, r.listGatewaysAttachedByBackendRoute(ctx, route)...)
+		}
+	}
+	return lo.Uniq(recs)
+}
+
+// egressFromRoutesEnabled returns true if any GatewayConfiguration derives the DataPlane's egress
+// from the routes' backends. The watches of the routes' backends have nothing to enqueue otherwise.
+func (r *Reconciler) egressFromRoutesEnabled(ctx context.Context) bool {
+	var gatewayConfigs operatorv2beta1.GatewayConfigurationList
+	if err := r.List(ctx, &gatewayConfigs); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Failed to list GatewayConfigurations in watch")
+		return true
+	}
+	return lo.ContainsBy(gatewayConfigs.Items, func(gatewayConfig operatorv2beta1.GatewayConfiguration) bool {
+		enabled, err := strconv.ParseBool(gatewayConfig.Annotations[consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation])
+		return err == nil && enabled
+	})
+}
+
+// listGatewaysForBackendReferenceGrant is a watch predicate which finds all Gateways mentioned
+// in the Parents field of routes from the namespaces the ReferenceGrant allows to reference
+// Services in its namespace, so that the egress of their DataPlanes follows the granted backends.
+func (r *Reconciler) listGatewaysForBackendReferenceGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	grant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+	if !ok {
+		logger.Error(
+			fmt.Errorf("unexpected object type"),
+			"Referencegrant watch predicate received unexpected object type",
+			"expected", "*gatewayapi.ReferenceGrant", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+	if !lo.ContainsBy(grant.Spec.To, func(to gatewayv1beta1.ReferenceGrantTo) bool {
+		return (to.Group == "" || to.Group == "core") && to.Kind == "Service"
+	}) || !r.egressFromRoutesEnabled(ctx) {
+		return nil
+	}
+
+	var recs []reconcile.Request
+	for _, from := range grant.Spec.From {
+		if from.Group != gatewayv1beta1.GroupName || !slices.Contains(backendRouteKinds, from.Kind) {
+			continue
+		}
+		routes, err := listBackendRoutes(ctx, r.Client, from.Kind, client.InNamespace(string(from.Namespace)))
+		if err != nil {
+			logger.Error(err, "Failed to list routes in watch", "referencegrant", grant.Name)
+			continue
+		}
+		for _, route := range routes {
+			if !lo.ContainsBy(route.backendRefs, func(ref gatewayv1.BackendObjectReference) bool {
+				return route.backendNamespace(ref) == grant.Namespace
+			}) {
+				continue
+			}
+			recs = append(recs, r.listGatewaysAttachedByBackendRoute(ctx, route)...)
+		}
+	}
+	return lo.Uniq(recs)
+}
+
+// listGatewaysForProxyEnvConfigMap is a watch predicate which finds all Gateways owning
+// DataPlanes which proxy container references the ConfigMap in its env or envFrom, so that
+// their network policies follow the listen ports configured through it.
+func (r *Reconciler) listGatewaysForProxyEnvConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.listGatewaysForDataPlanesMatchingField(ctx, index.DataPlaneOnProxyEnvConfigMapIndex, client.ObjectKeyFromObject(obj).String())
+}
+
+// listGatewaysForProxyEnvSecret is a watch predicate which finds all Gateways owning
+// DataPlanes which proxy container references the Secret in its env or envFrom, so that
+// their network policies follow the listen ports configured through it.
+func (r *Reconciler) listGatewaysForProxyEnvSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.listGatewaysForDataPlanesMatchingField(ctx, index.DataPlaneOnProxyEnvSecretIndex, client.ObjectKeyFromObject(obj).String())
+}
+
+func (r *Reconciler) listGatewaysForDataPlanesMatchingField(ctx context.Context, field, value string) []reconcile.Request {
+	var dataplanes operatorv1beta1.DataPlaneList
+	if err := r.List(ctx, &dataplanes, client.MatchingFields{field: value}); err != nil {
+		ctrllog.FromContext(ctx).Error(err, "Failed to list DataPlanes in watch", "index", field)
+		return nil
+	}
+
+	var recs []reconcile.Request
+	for i := range dataplanes.Items {
+		for _, gateway := range index.OwnerGatewayOnDataPlane(&dataplanes.Items[i]) {
+			namespace, name, _ := strings.Cut(gateway, "/")
+			recs = append(recs, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: namespace, Name: name},
+			})
+		}
+	}
+	return lo.Uniq(recs)
+}
+
 // -----------------------------------------------------------------------------
 // GatewayReconciler - Config Defaults
 // -----------------------------------------------------------------------------
@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+	operatorv2beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v2beta1"
+
+	"github.com/kong/kong-operator/internal/utils/index"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func newWatchTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	routeOptions, err := index.OptionsForRoutes(func(schema.GroupVersionResource) (bool, error) { return true, nil })
+	require.NoError(t, err)
+	builder := fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(objs...)
+	for _, opt := range slices.Concat(
+		index.OptionsForHTTPRoute(),
+		routeOptions,
+		index.OptionsForDataPlane(index.DataPlaneFlags{GatewayAPIGatewayControllerEnabled: true}),
+	) {
+		builder = builder.WithIndex(opt.Object, opt.Field, opt.ExtractValueFn)
+	}
+	return builder.Build()
+}
+
+func TestListGatewaysRoutingToService(t *testing.T) {
+	gateway := &gatewayv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "default"},
+	}
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "default"},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{{Name: "gateway"}},
+			},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{BackendRef: gatewayv1.BackendRef{BackendObjectReference: gatewayv1.BackendObjectReference{Name: "svc"}}},
+					},
+				},
+			},
+		},
+	}
+	gatewayConfig := func(egressFromRoutes string) *operatorv2beta1.GatewayConfiguration {
+		return &operatorv2beta1.GatewayConfiguration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "config",
+				Namespace: "default",
+				Annotations: map[string]string{
+					consts.GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation: egressFromRoutes,
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		objs     []client.Object
+		service  types.NamespacedName
+		expected []reconcile.Request
+	}{
+		{
+			name:    "nothing is enqueued when no GatewayConfiguration derives the egress from routes",
+			objs:    []client.Object{gateway, route, gatewayConfig("false")},
+			service: types.NamespacedName{Namespace: "default", Name: "svc"},
+		},
+		{
+			name:    "Gateways of the routes referencing the Service are enqueued",
+			objs:    []client.Object{gateway, route, gatewayConfig("true")},
+			service: types.NamespacedName{Namespace: "default", Name: "svc"},
+			expected: []reconcile.Request{
+				{NamespacedName: client.ObjectKeyFromObject(gateway)},
+			},
+		},
+		{
+			name:    "nothing is enqueued for Services not referenced by routes",
+			objs:    []client.Object{gateway, route, gatewayConfig("true")},
+			service: types.NamespacedName{Namespace: "default", Name: "other"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{Client: newWatchTestClient(t, tc.objs...)}
+			assert.ElementsMatch(t, tc.expected, r.listGatewaysRoutingToService(t.Context(), tc.service))
+		})
+	}
+}
+
+func TestListGatewaysForProxyEnvConfigMap(t *testing.T) {
+	dataplane := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dataplane",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: gatewayv1.GroupVersion.String(),
+					Kind:       "Gateway",
+					Name:       "gateway",
+				},
+			},
+		},
+	}
+	dataplane.Spec.Deployment.PodTemplateSpec = &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: consts.DataPlaneProxyContainerName,
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "listen"}}},
+					},
+				},
+			},
+		},
+	}
+	r := &Reconciler{Client: newWatchTestClient(t, dataplane)}
+
+	assert.Equal(t,
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "default", Name: "gateway"}}},
+		r.listGatewaysForProxyEnvConfigMap(t.Context(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "listen", Namespace: "default"},
+		}),
+	)
+	assert.Empty(t, r.listGatewaysForProxyEnvConfigMap(t.Context(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+	}))
+}
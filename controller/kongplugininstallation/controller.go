@@ -12,6 +12,7 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -31,6 +32,7 @@ import (
 	"github.com/kong/kong-operator/controller/pkg/log"
 	"github.com/kong/kong-operator/controller/pkg/secrets/ref"
 	"github.com/kong/kong-operator/modules/manager/logging"
+	"github.com/kong/kong-operator/pkg/consts"
 	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
 	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
 )
@@ -46,6 +48,19 @@ type Reconciler struct {
 	// ConfigMapLabelSelector is the label selector configured at the oprator level.
 	// When not empty, it is used as the config map label selector of all reconcilers.
 	ConfigMapLabelSelector string
+	// RequireImageVerification requires the cosign signature of all plugin images
+	// to be verified before plugins are installed.
+	RequireImageVerification bool
+	// TrustedCosignSecretNN is the Secret holding the cosign public keys, Fulcio
+	// roots and Rekor public keys trusted at the operator level. When set, all
+	// plugin images are verified with it and the cosign annotations of
+	// KongPluginInstallations are ignored so that they can't override it.
+	TrustedCosignSecretNN types.NamespacedName
+	// TrustedCosignIdentity and TrustedCosignIssuer are the identity and OIDC issuer
+	// of the keyless signatures trusted at the operator level. They are only used
+	// with TrustedCosignSecretNN.
+	TrustedCosignIdentity string
+	TrustedCosignIssuer   string
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -55,7 +70,8 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 			CacheSyncTimeout: r.CacheSyncTimeout,
 		}).
 		For(&operatorv1alpha1.KongPluginInstallation{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		// Plugin image verification is configured with annotations.
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})).
 		Owns(&corev1.ConfigMap{}, builder.WithPredicates(
 			predicate.Funcs{
 				DeleteFunc: func(e event.DeleteEvent) bool {
@@ -78,7 +94,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) err
 					if !ok {
 						return false
 					}
-					return secret.Type == corev1.SecretTypeDockerConfigJson
+					return secret.Type == corev1.SecretTypeDockerConfigJson || isCosignSecret(secret)
 				}),
 			),
 		).
@@ -103,12 +119,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if err := r.Get(ctx, req.NamespacedName, &kpi); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	if err := setStatusConditionForKongPluginInstallation(
-		ctx, r.Client, &kpi, metav1.ConditionFalse, operatorv1alpha1.KongPluginInstallationReasonPending, "fetching plugin is in progress",
-	); err != nil {
-		return ctrl.Result{}, err
-	}
-
 	log.Trace(logger, "managing KongPluginInstallation resource")
 	var credentialsStore orascreds.Store
 	if kpi.Spec.ImagePullSecretRef != nil {
@@ -159,10 +169,94 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
+	pollInterval, err := pollIntervalForKongPluginInstallation(&kpi)
+	if err != nil {
+		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, err.Error())
+	}
+	// When polling is configured, failures of fetching or verifying the plugin image
+	// are retried with the next poll as they may be caused by the registry or by
+	// an image not pushed or signed yet.
+	retryResult := ctrl.Result{RequeueAfter: pollInterval}
+
+	log.Trace(logger, "getting image verification configuration for KongPluginInstallation resource")
+	verification, whyInvalidMsg, err := r.verificationForKongPluginInstallation(ctx, &kpi)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if whyInvalidMsg != "" {
+		return retryResult, setStatusConditionsVerificationFailedForKongPluginInstallation(ctx, r.Client, &kpi, whyInvalidMsg)
+	}
+
+	delivery, err := pluginDeliveryForKongPluginInstallation(&kpi)
+	if err != nil {
+		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, err.Error())
+	}
+	sourceHash, err := r.pluginSourceHash(ctx, &kpi)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pollInterval > 0 {
+		log.Trace(logger, "checking if plugin image of KongPluginInstallation resource changed")
+		upToDate, err := r.isPluginUpToDate(ctx, logger, &kpi, credentialsStore, sourceHash)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if upToDate {
+			return ctrl.Result{RequeueAfter: pollInterval}, nil
+		}
+	}
+
+	if err := setStatusConditionForKongPluginInstallation(
+		ctx, r.Client, &kpi, metav1.ConditionFalse, operatorv1alpha1.KongPluginInstallationReasonPending, "fetching plugin is in progress",
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	fetchOpts := image.FetchOptions{
+		Verification: verification,
+	}
+	if delivery == consts.KongPluginInstallationDeliveryImageVolume {
+		fetchOpts.SizeLimit = image.ImageVolumeSizeLimit
+	}
+
 	log.Trace(logger, "fetch plugin for KongPluginInstallation resource")
-	plugin, err := image.FetchPlugin(ctx, kpi.Spec.Image, credentialsStore)
+	plugin, err := image.FetchPluginWithOptions(ctx, kpi.Spec.Image, credentialsStore, fetchOpts)
 	if err != nil {
-		return ctrl.Result{}, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+		if image.IsVerificationError(err) {
+			return retryResult, setStatusConditionsVerificationFailedForKongPluginInstallation(
+				ctx, r.Client, &kpi, fmt.Sprintf("verification of the image: %q failed: %s", kpi.Spec.Image, err),
+			)
+		}
+		return retryResult, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+	}
+	// The verification result is reported before the plugin is installed.
+	if isVerificationConfigured(verification) {
+		if err := setStatusConditionsForKongPluginInstallation(ctx, r.Client, &kpi,
+			newKongPluginInstallationCondition(
+				&kpi, KongPluginInstallationConditionVerified, metav1.ConditionTrue, KongPluginInstallationReasonVerified,
+				fmt.Sprintf("image digest %s verified", plugin.Digest),
+			),
+		); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Plugins delivered as image volumes are mounted by DataPlanes straight from the
+	// image, the ConfigMap holds only the reference of the verified image then.
+	var data map[string]string
+	switch delivery {
+	case consts.KongPluginInstallationDeliveryConfigMap:
+		if data, err = plugin.Files.ConfigMapData(); err != nil {
+			return retryResult, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf("problem with the image: %q error: %s", kpi.Spec.Image, err))
+		}
+	case consts.KongPluginInstallationDeliveryImageVolume:
+		// The whole image is mounted as the plugin's directory.
+		if plugin.Directory != "." {
+			return retryResult, setStatusConditionFailedForKongPluginInstallation(ctx, r.Client, &kpi, fmt.Sprintf(
+				"problem with the image: %q error: plugin files have to be placed at the root of the image to be delivered as image volume, found in %q",
+				kpi.Spec.Image, plugin.Directory,
+			))
+		}
 	}
 
 	cms, err := k8sutils.ListConfigMapsForOwner(ctx, r.Client, kpi.GetUID())
@@ -181,7 +275,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		k8sresources.LabelObjectAsKongPluginInstallationManaged(&cm)
 		k8sresources.AnnotateConfigMapWithKongPluginInstallation(&cm, kpi)
 		cm.Namespace = kpi.Namespace
-		cm.Data = plugin
+		cm.Data = data
+		setImageAnnotations(&cm, plugin, delivery, sourceHash)
 		if err := ctrl.SetControllerReference(&kpi, &cm, r.Scheme); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -191,7 +286,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		kpi.Status.UnderlyingConfigMapName = cm.Name
 	case 1:
 		cm = cms[0]
-		cm.Data = plugin
+		cm.Data = data
+		setImageAnnotations(&cm, plugin, delivery, sourceHash)
 		if err := r.Update(ctx, &cm); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -200,8 +296,17 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, errors.New("unexpected error happened - more than one ConfigMap found")
 	}
 
-	return ctrl.Result{}, setStatusConditionForKongPluginInstallation(
-		ctx, r.Client, &kpi, metav1.ConditionTrue, operatorv1alpha1.KongPluginInstallationReasonReady, "plugin successfully saved in cluster as ConfigMap",
+	if pollInterval == 0 {
+		return ctrl.Result{}, setStatusConditionForKongPluginInstallation(
+			ctx, r.Client, &kpi, metav1.ConditionTrue, operatorv1alpha1.KongPluginInstallationReasonReady, "plugin successfully saved in cluster as ConfigMap",
+		)
+	}
+	return ctrl.Result{RequeueAfter: pollInterval}, setStatusConditionsForKongPluginInstallation(ctx, r.Client, &kpi,
+		newKongPluginInstallationCondition(
+			&kpi, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted), metav1.ConditionTrue,
+			string(operatorv1alpha1.KongPluginInstallationReasonReady), "plugin successfully saved in cluster as ConfigMap",
+		),
+		newImageResolvedCondition(&kpi, plugin.Digest),
 	)
 }
 
@@ -219,6 +324,18 @@ func (r *Reconciler) listKongPluginInstallationsForSecret(ctx context.Context, o
 
 	var recs []reconcile.Request
 	for _, kpi := range kpiList.Items {
+		if r.TrustedCosignSecretNN == (types.NamespacedName{Namespace: namespace, Name: name}) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&kpi),
+			})
+			continue
+		}
+		if kpi.Namespace == namespace && kpi.Annotations[consts.KongPluginInstallationCosignSecretAnnotation] == name {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&kpi),
+			})
+			continue
+		}
 		if kpi.Spec.ImagePullSecretRef == nil {
 			continue
 		}
@@ -272,25 +389,98 @@ func setStatusConditionFailedForKongPluginInstallation(
 func setStatusConditionForKongPluginInstallation(
 	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, conditionStatus metav1.ConditionStatus, reason operatorv1alpha1.KongPluginInstallationConditionReason, msg string,
 ) error {
-	status := metav1.Condition{
-		Type:               string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+	return setStatusConditionsForKongPluginInstallation(ctx, client, kpi,
+		newKongPluginInstallationCondition(
+			kpi, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted), conditionStatus, string(reason), msg,
+		),
+	)
+}
+
+func newKongPluginInstallationCondition(
+	kpi *operatorv1alpha1.KongPluginInstallation, conditionType string, conditionStatus metav1.ConditionStatus, reason string, msg string,
+) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionType,
 		Status:             conditionStatus,
 		ObservedGeneration: kpi.Generation,
 		LastTransitionTime: metav1.Now(),
-		Reason:             string(reason),
+		Reason:             reason,
 		Message:            msg,
 	}
-	_, index, found := lo.FindIndexOf(kpi.Status.Conditions, func(c metav1.Condition) bool {
-		return c.Type == string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted)
-	})
-	if found {
-		// Nothing changed, condition doesn't need to be updated.
-		if c := kpi.Status.Conditions[index]; c.Status == status.Status && c.Reason == status.Reason && c.Message == status.Message {
-			return nil
+}
+
+// setStatusConditionsForKongPluginInstallation sets the conditions in the KongPluginInstallation's
+// status and updates it when any of them changed.
+func setStatusConditionsForKongPluginInstallation(
+	ctx context.Context, client client.Client, kpi *operatorv1alpha1.KongPluginInstallation, conditions ...metav1.Condition,
+) error {
+	var changed bool
+	for _, status := range conditions {
+		_, index, found := lo.FindIndexOf(kpi.Status.Conditions, func(c metav1.Condition) bool {
+			return c.Type == status.Type
+		})
+		if found {
+			// Nothing changed, condition doesn't need to be updated.
+			if c := kpi.Status.Conditions[index]; c.Status == status.Status && c.Reason == status.Reason && c.Message == status.Message {
+				continue
+			}
+			kpi.Status.Conditions[index] = status
+		} else {
+			kpi.Status.Conditions = append(kpi.Status.Conditions, status)
 		}
-		kpi.Status.Conditions[index] = status
-	} else {
-		kpi.Status.Conditions = append(kpi.Status.Conditions, status)
+		changed = true
+	}
+	if !changed {
+		return nil
 	}
 	return client.Status().Update(ctx, kpi)
 }
+
+// isCosignSecret returns true when the Secret holds any of the keys used to
+// verify plugin images' signatures.
+func isCosignSecret(secret *corev1.Secret) bool {
+	for _, key := range []string{
+		consts.KongPluginInstallationCosignPublicKeySecretKey,
+		consts.KongPluginInstallationCosignFulcioRootsSecretKey,
+		consts.KongPluginInstallationCosignRekorPublicKeySecretKey,
+	} {
+		if _, ok := secret.Data[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setImageAnnotations records the digest of the image the plugin has been fetched from, the hash
+// of the inputs it has been fetched with and, for plugins delivered as image volumes, the image
+// reference DataPlanes mount.
+func setImageAnnotations(cm *corev1.ConfigMap, plugin image.Plugin, delivery string, sourceHash string) {
+	annotations := cm.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[consts.AnnotationKongPluginInstallationImageDigest] = plugin.Digest
+	annotations[consts.AnnotationKongPluginInstallationSourceHash] = sourceHash
+	if delivery == consts.KongPluginInstallationDeliveryImageVolume {
+		annotations[consts.AnnotationKongPluginInstallationImageReference] = plugin.Reference
+	} else {
+		delete(annotations, consts.AnnotationKongPluginInstallationImageReference)
+	}
+	cm.SetAnnotations(annotations)
+}
+
+// pluginDeliveryForKongPluginInstallation returns how the plugin is delivered to DataPlanes
+// as configured by the KongPluginInstallation's annotation.
+func pluginDeliveryForKongPluginInstallation(kpi *operatorv1alpha1.KongPluginInstallation) (string, error) {
+	switch delivery, ok := kpi.Annotations[consts.KongPluginInstallationDeliveryAnnotation]; {
+	case !ok:
+		return consts.KongPluginInstallationDeliveryConfigMap, nil
+	case delivery == consts.KongPluginInstallationDeliveryConfigMap, delivery == consts.KongPluginInstallationDeliveryImageVolume:
+		return delivery, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid value of annotation %s: %q, supported values are %s and %s", consts.KongPluginInstallationDeliveryAnnotation,
+			delivery, consts.KongPluginInstallationDeliveryConfigMap, consts.KongPluginInstallationDeliveryImageVolume,
+		)
+	}
+}
@@ -0,0 +1,81 @@
+package image
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Keys of a ConfigMap can't contain "/", hence paths of a plugin's files stored in
+// a ConfigMap are encoded. The escape character "_" is followed either by "_"
+// (for "_" in the path) or by "." (for "/" in the path), which makes the encoding
+// reversible while the names of files placed directly in the plugin's directory
+// (e.g. handler.lua and schema.lua) are kept unchanged.
+const (
+	configMapKeyEscape       = "_"
+	configMapKeyEscapedSlash = configMapKeyEscape + "."
+)
+
+// ConfigMapData returns the plugin's files encoded as data of a ConfigMap.
+// Use ConfigMapItems to mount the ConfigMap with the plugin's directory structure.
+func (pf PluginFiles) ConfigMapData() (map[string]string, error) {
+	data := make(map[string]string, len(pf))
+	for p, content := range pf {
+		key := configMapKeyForPath(p)
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return nil, fmt.Errorf("file %q can't be stored in a ConfigMap: %s", p, strings.Join(errs, ", "))
+		}
+		data[key] = content
+	}
+	return data, nil
+}
+
+// ConfigMapItems returns the items of a ConfigMap volume that project data created by
+// PluginFiles.ConfigMapData to the plugin's directory structure. It returns nil when
+// all files are placed directly in the plugin's directory, then the whole ConfigMap
+// can be mounted as is.
+func ConfigMapItems(data map[string]string) []corev1.KeyToPath {
+	var (
+		items  = make([]corev1.KeyToPath, 0, len(data))
+		nested bool
+	)
+	for key := range data {
+		p := pathForConfigMapKey(key)
+		if p != key {
+			nested = true
+		}
+		items = append(items, corev1.KeyToPath{Key: key, Path: p})
+	}
+	if !nested {
+		return nil
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+	return items
+}
+
+func configMapKeyForPath(p string) string {
+	return strings.NewReplacer(
+		configMapKeyEscape, configMapKeyEscape+configMapKeyEscape,
+		"/", configMapKeyEscapedSlash,
+	).Replace(p)
+}
+
+func pathForConfigMapKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		if key[i] == configMapKeyEscape[0] && i+1 < len(key) {
+			i++
+			if key[i] == configMapKeyEscapedSlash[1] {
+				b.WriteByte('/')
+				continue
+			}
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
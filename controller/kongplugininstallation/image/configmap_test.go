@@ -0,0 +1,77 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPluginFilesConfigMapData(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         PluginFiles
+		expectedData  map[string]string
+		expectedItems []corev1.KeyToPath
+		expectedError string
+	}{
+		{
+			name: "files in the plugin's directory",
+			files: PluginFiles{
+				"handler.lua": "handler",
+				"schema.lua":  "schema",
+			},
+			expectedData: map[string]string{
+				"handler.lua": "handler",
+				"schema.lua":  "schema",
+			},
+		},
+		{
+			name: "files in subdirectories",
+			files: PluginFiles{
+				"handler.lua":           "handler",
+				"schema.lua":            "schema",
+				"daos.lua":              "daos",
+				"helpers/my_util.lua":   "util",
+				"helpers/nested/io.lua": "io",
+			},
+			expectedData: map[string]string{
+				"handler.lua":             "handler",
+				"schema.lua":              "schema",
+				"daos.lua":                "daos",
+				"helpers_.my__util.lua":   "util",
+				"helpers_.nested_.io.lua": "io",
+			},
+			expectedItems: []corev1.KeyToPath{
+				{Key: "daos.lua", Path: "daos.lua"},
+				{Key: "handler.lua", Path: "handler.lua"},
+				{Key: "helpers_.my__util.lua", Path: "helpers/my_util.lua"},
+				{Key: "helpers_.nested_.io.lua", Path: "helpers/nested/io.lua"},
+				{Key: "schema.lua", Path: "schema.lua"},
+			},
+		},
+		{
+			name: "file name not allowed in a ConfigMap",
+			files: PluginFiles{
+				"handler.lua": "handler",
+				"schema.lua":  "schema",
+				"read me.md":  "readme",
+			},
+			expectedError: `file "read me.md" can't be stored in a ConfigMap`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := tc.files.ConfigMapData()
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedData, data)
+			assert.Equal(t, tc.expectedItems, ConfigMapItems(data))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type layerEntry struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+func newLayer(t *testing.T, entries ...layerEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		h := &tar.Header{
+			Name:     e.name,
+			Typeflag: e.typeflag,
+			Mode:     0o644,
+			Size:     int64(len(e.content)),
+		}
+		if e.typeflag == tar.TypeSymlink {
+			h.Linkname, h.Size = e.content, 0
+		}
+		require.NoError(t, tw.WriteHeader(h))
+		if e.typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(e.content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return &buf
+}
+
+func TestExtractKongPluginFromLayer(t *testing.T) {
+	testCases := []struct {
+		name          string
+		entries       []layerEntry
+		sizeLimit     sizeLimitBytes
+		expected      PluginFiles
+		expectedDir   string
+		expectedError string
+	}{
+		{
+			name: "plugin at the root of the image",
+			entries: []layerEntry{
+				{name: "handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "schema.lua", typeflag: tar.TypeReg, content: "schema"},
+			},
+			expected: PluginFiles{
+				"handler.lua": "handler",
+				"schema.lua":  "schema",
+			},
+			expectedDir: ".",
+		},
+		{
+			name: "plugin directory tree",
+			entries: []layerEntry{
+				{name: "myplugin/", typeflag: tar.TypeDir},
+				{name: "myplugin/handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "./myplugin/schema.lua", typeflag: tar.TypeReg, content: "schema"},
+				{name: "myplugin/daos.lua", typeflag: tar.TypeReg, content: "daos"},
+				{name: "myplugin/helpers/", typeflag: tar.TypeDir},
+				{name: "myplugin/helpers/util.lua", typeflag: tar.TypeReg, content: "util"},
+				{name: "myplugin/vendor/handler.lua", typeflag: tar.TypeReg, content: "vendored"},
+			},
+			expected: PluginFiles{
+				"handler.lua":        "handler",
+				"schema.lua":         "schema",
+				"daos.lua":           "daos",
+				"helpers/util.lua":   "util",
+				"vendor/handler.lua": "vendored",
+			},
+			expectedDir: "myplugin",
+		},
+		{
+			name: "file outside of the plugin's directory",
+			entries: []layerEntry{
+				{name: "myplugin/handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "myplugin/schema.lua", typeflag: tar.TypeReg, content: "schema"},
+				{name: "README.md", typeflag: tar.TypeReg, content: "readme"},
+			},
+			expectedError: `file "README.md" is outside of the plugin directory "myplugin"`,
+		},
+		{
+			name: "ambiguous plugin directory",
+			entries: []layerEntry{
+				{name: "a/handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "b/handler.lua", typeflag: tar.TypeReg, content: "handler"},
+			},
+			expectedError: "ambiguous plugin directory",
+		},
+		{
+			name: "symlink",
+			entries: []layerEntry{
+				{name: "handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "schema.lua", typeflag: tar.TypeSymlink, content: "/etc/passwd"},
+			},
+			expectedError: `file "schema.lua" is not a regular file`,
+		},
+		{
+			name: "path traversal",
+			entries: []layerEntry{
+				{name: "handler.lua", typeflag: tar.TypeReg, content: "handler"},
+				{name: "../schema.lua", typeflag: tar.TypeReg, content: "schema"},
+			},
+			expectedError: `invalid file path "../schema.lua"`,
+		},
+		{
+			name: "size limit exceeded",
+			entries: []layerEntry{
+				{name: "handler.lua", typeflag: tar.TypeReg, content: string(make([]byte, 4096))},
+				{name: "schema.lua", typeflag: tar.TypeReg, content: "schema"},
+			},
+			sizeLimit:     2048,
+			expectedError: "plugin size limit of",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sizeLimit := tc.sizeLimit
+			if sizeLimit == 0 {
+				sizeLimit = DefaultSizeLimit
+			}
+			files, dir, err := extractKongPluginFromLayer(newLayer(t, tc.entries...), sizeLimit)
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, files)
+			assert.Equal(t, tc.expectedDir, dir)
+		})
+	}
+}
@@ -7,16 +7,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
+	"path"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
-	"github.com/samber/lo"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/registry"
 	"oras.land/oras-go/v2/registry/remote"
 	"oras.land/oras-go/v2/registry/remote/auth"
 	"oras.land/oras-go/v2/registry/remote/credentials"
@@ -30,14 +31,60 @@ const (
 	kongPluginSchema  = "schema.lua"
 )
 
-// PluginFiles maps a plugin's file names to their content.
-// It's expected that each plugin consists of `schema.lua` and `handler.lua` files.
+// Size limits of plugins' files combined.
+const (
+	// DefaultSizeLimit is the size limit of a plugin delivered through a ConfigMap,
+	// it's the limit of the size of a ConfigMap in Kubernetes.
+	DefaultSizeLimit sizeLimitBytes = 1024 * 1024
+	// ImageVolumeSizeLimit is the size limit of a plugin delivered to DataPlanes
+	// by mounting its image as a volume.
+	ImageVolumeSizeLimit sizeLimitBytes = 64 * 1024 * 1024
+)
+
+// PluginFiles maps paths of a plugin's files, relative to the plugin's directory,
+// to their content. It's expected that each plugin consists of at least `schema.lua`
+// and `handler.lua` files placed in the plugin's directory, any other files (e.g.
+// `daos.lua`, `api.lua` or helper modules in subdirectories) are optional.
 type PluginFiles map[string]string
 
-// newPluginFilesFromMap creates PluginFiles from a map of files with content.
-// It ensures that the required files handler.lua and schema.lua are only present
-// in the map.
-func newPluginFilesFromMap(pluginFiles map[string]string) (PluginFiles, error) {
+// newPluginFilesFromMap creates PluginFiles from a map of files (paths relative to the
+// root of an image) with content. The plugin's directory is the one that contains
+// handler.lua closest to the root of the image, its path is returned too. It ensures
+// that the required files handler.lua and schema.lua are present in the plugin's
+// directory and that there are no files outside of it.
+func newPluginFilesFromMap(files map[string]string) (PluginFiles, string, error) {
+	var (
+		pluginDir string
+		found     bool
+	)
+	for p := range files {
+		if path.Base(p) != kongPluginHandler {
+			continue
+		}
+		dir := path.Dir(p)
+		switch depth, pluginDirDepth := dirDepth(dir), dirDepth(pluginDir); {
+		case !found, depth < pluginDirDepth:
+			pluginDir, found = dir, true
+		case depth == pluginDirDepth && dir != pluginDir:
+			return nil, "", fmt.Errorf("ambiguous plugin directory, %s found in %s and %s", kongPluginHandler, pluginDir, dir)
+		}
+	}
+	if !found {
+		pluginDir = "."
+	}
+
+	pluginFiles := make(PluginFiles, len(files))
+	for p, content := range files {
+		rel := p
+		if pluginDir != "." {
+			var ok bool
+			if rel, ok = strings.CutPrefix(p, pluginDir+"/"); !ok {
+				return nil, "", fmt.Errorf("file %q is outside of the plugin directory %q", p, pluginDir)
+			}
+		}
+		pluginFiles[rel] = content
+	}
+
 	var missingFiles []string
 	for _, f := range []string{kongPluginHandler, kongPluginSchema} {
 		if _, ok := pluginFiles[f]; !ok {
@@ -45,23 +92,121 @@ func newPluginFilesFromMap(pluginFiles map[string]string) (PluginFiles, error) {
 		}
 	}
 	if len(missingFiles) > 0 {
-		return nil, fmt.Errorf("required files not found in the image: %s", strings.Join(missingFiles, ", "))
+		return nil, "", fmt.Errorf("required files not found in the image: %s", strings.Join(missingFiles, ", "))
 	}
-	if len(pluginFiles) != 2 {
-		return nil, fmt.Errorf("expected exactly 2 files, got %d: %s", len(pluginFiles), strings.Join(lo.Keys(pluginFiles), ","))
-	}
-	return PluginFiles(pluginFiles), nil
+	return pluginFiles, pluginDir, nil
+}
+
+// Plugin is a plugin fetched from an image.
+type Plugin struct {
+	// Files are the plugin's files.
+	Files PluginFiles
+	// Digest is the digest of the image manifest the plugin has been fetched from.
+	Digest string
+	// Directory is the path of the plugin's directory in the image, "." when
+	// the plugin's files are placed at the root of the image.
+	Directory string
+	// Reference is the reference of the image the plugin has been fetched from,
+	// pinned by Digest, e.g. registry.example.com/plugin@sha256:<hex>.
+	Reference string
+}
+
+// FetchOptions configures fetching of a plugin.
+type FetchOptions struct {
+	// Verification configures the checks the image has to pass.
+	Verification Verification
+	// SizeLimit is the size limit of the plugin's files combined.
+	// When not set DefaultSizeLimit is used.
+	SizeLimit sizeLimitBytes
 }
 
 // FetchPlugin fetches the content of the plugin from the image URL. When authentication is not needed pass nil.
 func FetchPlugin(ctx context.Context, imageURL string, credentialsStore credentials.Store) (PluginFiles, error) {
+	plugin, err := FetchPluginWithOptions(ctx, imageURL, credentialsStore, FetchOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return plugin.Files, nil
+}
+
+// FetchPluginWithOptions fetches the content of the plugin from the image URL once the
+// image passed the checks configured by opts.Verification. The image is resolved to
+// its manifest digest first, so that the verified content is the one the plugin
+// is extracted from, even when the tag is moved in the meantime.
+// Failed checks are reported as VerificationError. When authentication is not needed
+// pass nil as credentialsStore.
+func FetchPluginWithOptions(
+	ctx context.Context, imageURL string, credentialsStore credentials.Store, opts FetchOptions,
+) (Plugin, error) {
+	verification := opts.Verification
+	sizeLimit := opts.SizeLimit
+	if sizeLimit <= 0 {
+		sizeLimit = DefaultSizeLimit
+	}
 	ref, err := name.ParseReference(imageURL)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected format of image url: %w", err)
+		return Plugin{}, fmt.Errorf("unexpected format of image url: %w", err)
 	}
-	registryName, repositoryName, imageTag := ref.Context().RegistryStr(), ref.Context().RepositoryStr(), ref.Identifier()
-	// Errors for NewRegistry(..) and Repository(..) should never happen because the image URL has been already validated above.
-	registry, err := remote.NewRegistry(registryName)
+	_, pinnedByDigest := ref.(name.Digest)
+	if verification.RequireDigest && !pinnedByDigest {
+		return Plugin{}, newVerificationError("image %s is not pinned by digest", imageURL)
+	}
+	imageTag := ref.Identifier()
+	repository, err := newRepository(ctx, ref, imageURL, credentialsStore)
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	manifest, err := repository.Resolve(ctx, imageTag)
+	if err != nil {
+		return Plugin{}, fmt.Errorf("can't resolve image: %s, because: %w", imageURL, err)
+	}
+	if pinnedByDigest && manifest.Digest.String() != imageTag {
+		return Plugin{}, newVerificationError("image %s resolved to unexpected digest %s", imageURL, manifest.Digest)
+	}
+	if verification.signatureRequired() {
+		if err := verifySignature(ctx, repository, manifest, verification); err != nil {
+			return Plugin{}, err
+		}
+	}
+
+	files, dir, err := fetchPluginFiles(ctx, repository, manifest, imageURL, sizeLimit)
+	if err != nil {
+		return Plugin{}, err
+	}
+	return Plugin{
+		Files:     files,
+		Directory: dir,
+		Digest:    manifest.Digest.String(),
+		Reference: ref.Context().Digest(manifest.Digest.String()).String(),
+	}, nil
+}
+
+// ResolveDigest resolves the image URL, e.g. a mutable tag, to the digest of the image
+// manifest without fetching the image. When authentication is not needed pass nil.
+func ResolveDigest(ctx context.Context, imageURL string, credentialsStore credentials.Store) (string, error) {
+	ref, err := name.ParseReference(imageURL)
+	if err != nil {
+		return "", fmt.Errorf("unexpected format of image url: %w", err)
+	}
+	repository, err := newRepository(ctx, ref, imageURL, credentialsStore)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := repository.Resolve(ctx, ref.Identifier())
+	if err != nil {
+		return "", fmt.Errorf("can't resolve image: %s, because: %w", imageURL, err)
+	}
+	return manifest.Digest.String(), nil
+}
+
+// newRepository returns the client of the repository of the image.
+func newRepository(
+	ctx context.Context, ref name.Reference, imageURL string, credentialsStore credentials.Store,
+) (registry.Repository, error) {
+	registryName, repositoryName := ref.Context().RegistryStr(), ref.Context().RepositoryStr()
+	// Errors for NewRegistry(..) and Repository(..) should never happen because the image URL has been already validated.
+	reg, err := remote.NewRegistry(registryName)
 	if err != nil {
 		return nil, fmt.Errorf("for image: %s unexpected registry: %s, because: %w", imageURL, registryName, err)
 	}
@@ -69,52 +214,58 @@ func FetchPlugin(ctx context.Context, imageURL string, credentialsStore credenti
 	if credentialsStore != nil {
 		credentialFunc = credentials.Credential(credentialsStore)
 	}
-	registry.Client = &auth.Client{
+	reg.Client = &auth.Client{
 		Client:     auth.DefaultClient.Client,
 		Header:     map[string][]string{"User-Agent": {metadata.Metadata().UserAgent()}},
 		Cache:      auth.NewCache(),
 		Credential: credentialFunc,
 	}
 
-	repository, err := registry.Repository(ctx, repositoryName)
+	repository, err := reg.Repository(ctx, repositoryName)
 	if err != nil {
 		return nil, fmt.Errorf("for image: %s unexpected repository: %s, because: %w", imageURL, registryName, err)
 	}
+	return repository, nil
+}
 
+// fetchPluginFiles fetches the graph of the image manifest and extracts the plugin
+// from the only layer of the image. It returns the plugin's files and the path of
+// the plugin's directory in the image.
+func fetchPluginFiles(
+	ctx context.Context, repository oras.ReadOnlyTarget, manifest ociv1.Descriptor, imageURL string, sizeLimit sizeLimitBytes,
+) (PluginFiles, string, error) {
 	var (
 		mut                        sync.Mutex
 		layersThatMayContainPlugin []ociv1.Descriptor
 	)
 	inMemoryStore := memory.New()
-	if _, err := oras.Copy(ctx, repository, imageTag, inMemoryStore, imageTag, oras.CopyOptions{
-		CopyGraphOptions: oras.CopyGraphOptions{
-			PostCopy: func(ctx context.Context, desc ociv1.Descriptor) error {
-				// Look for OCI or Docker layer media type (they are fully compatible, see:
-				// https://github.com/opencontainers/image-spec/blob/39ab2d54cfa8fe1bee1ff20001264986d92ab85a/media-types.md?plain=1#L60-L64)
-				// Such object in the graph represents an actual layer that contains a plugin.
-				if mediaType := types.MediaType(desc.MediaType); mediaType == types.OCILayer || mediaType == types.DockerLayer {
-					mut.Lock()
-					layersThatMayContainPlugin = append(layersThatMayContainPlugin, desc)
-					mut.Unlock()
-				}
-				return nil
-			},
+	if err := oras.CopyGraph(ctx, repository, inMemoryStore, manifest, oras.CopyGraphOptions{
+		PostCopy: func(ctx context.Context, desc ociv1.Descriptor) error {
+			// Look for OCI or Docker layer media type (they are fully compatible, see:
+			// https://github.com/opencontainers/image-spec/blob/39ab2d54cfa8fe1bee1ff20001264986d92ab85a/media-types.md?plain=1#L60-L64)
+			// Such object in the graph represents an actual layer that contains a plugin.
+			if mediaType := types.MediaType(desc.MediaType); mediaType == types.OCILayer || mediaType == types.DockerLayer {
+				mut.Lock()
+				layersThatMayContainPlugin = append(layersThatMayContainPlugin, desc)
+				mut.Unlock()
+			}
+			return nil
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("can't fetch image: %s, because: %w", imageURL, err)
+		return nil, "", fmt.Errorf("can't fetch image: %s, because: %w", imageURL, err)
 	}
 	// Image with plugin should have exactly one layer that contains a plugin with the name plugin.lua.
 	// This is requirement described in details in the documentation. Any mismatch is treated as invalid image.
 	if numOfLayers := len(layersThatMayContainPlugin); numOfLayers != 1 {
-		return nil, fmt.Errorf("expected exactly one layer with plugin, found %d layers", numOfLayers)
+		return nil, "", fmt.Errorf("expected exactly one layer with plugin, found %d layers", numOfLayers)
 	}
 	layerWithPlugin := layersThatMayContainPlugin[0]
 	contentOfLayerWithPlugin, err := inMemoryStore.Fetch(ctx, layerWithPlugin)
 	if err != nil {
-		return nil, fmt.Errorf("can't get layer of image: %w", err)
+		return nil, "", fmt.Errorf("can't get layer of image: %w", err)
 	}
 
-	return extractKongPluginFromLayer(contentOfLayerWithPlugin)
+	return extractKongPluginFromLayer(contentOfLayerWithPlugin, sizeLimit)
 }
 
 type sizeLimitBytes int64
@@ -127,41 +278,68 @@ func (sl sizeLimitBytes) String() string {
 	return fmt.Sprintf("%.2f MiB", float64(sl)/(1024*1024))
 }
 
-func extractKongPluginFromLayer(r io.Reader) (PluginFiles, error) {
+func extractKongPluginFromLayer(r io.Reader, sizeLimit sizeLimitBytes) (PluginFiles, string, error) {
 	// Search for the files walking through the archive.
-	// The size of a plugin is limited to the size of a ConfigMap in Kubernetes.
-	const sizeLimit1MiB sizeLimitBytes = 1024 * 1024
-
 	gr, err := gzip.NewReader(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse layer as tar.gz: %w", err)
+		return nil, "", fmt.Errorf("failed to parse layer as tar.gz: %w", err)
 	}
-	pluginFiles := make(map[string]string)
-	for tr := tar.NewReader(io.LimitReader(gr, sizeLimit1MiB.int64())); ; {
+	files := make(map[string]string)
+	for tr := tar.NewReader(io.LimitReader(gr, sizeLimit.int64())); ; {
 		h, err := tr.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("unexpected error during looking for plugin: %w", err)
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, "", fmt.Errorf("plugin size limit of %s exceeded", sizeLimit)
+			}
+			return nil, "", fmt.Errorf("unexpected error during looking for plugin: %w", err)
 		}
 
-		switch fileName := filepath.Base(h.Name); fileName {
-		case kongPluginHandler, kongPluginSchema:
-			file := make([]byte, h.Size)
-			if _, err := io.ReadFull(tr, file); err != nil {
-				if errors.Is(err, io.ErrUnexpectedEOF) {
-					return nil, fmt.Errorf("plugin size limit of %s exceeded", sizeLimit1MiB)
-				}
-				return nil, fmt.Errorf("failed to read %s from image: %w", fileName, err)
-			}
-			pluginFiles[fileName] = string(file)
+		switch h.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeReg:
 		default:
-			return nil, fmt.Errorf(
-				"file %q is unexpected, required files are %s and %s", fileName, kongPluginHandler, kongPluginSchema,
-			)
+			// Links could point outside of the plugin's directory once the plugin
+			// is mounted, hence only regular files are allowed.
+			return nil, "", fmt.Errorf("file %q is not a regular file, only regular files and directories are allowed", h.Name)
+		}
+		filePath, err := sanitizeFilePath(h.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		file := make([]byte, h.Size)
+		if _, err := io.ReadFull(tr, file); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, "", fmt.Errorf("plugin size limit of %s exceeded", sizeLimit)
+			}
+			return nil, "", fmt.Errorf("failed to read %s from image: %w", filePath, err)
 		}
+		files[filePath] = string(file)
 	}
 
-	return newPluginFilesFromMap(pluginFiles)
+	return newPluginFilesFromMap(files)
+}
+
+// sanitizeFilePath returns the path of a file from a layer relative to the root of
+// the image. Paths that point outside of the image are rejected.
+func sanitizeFilePath(name string) (string, error) {
+	if slices.Contains(strings.Split(name, "/"), "..") {
+		return "", fmt.Errorf("invalid file path %q in the image", name)
+	}
+	p := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if p == "" {
+		return "", fmt.Errorf("invalid file path %q in the image", name)
+	}
+	return p, nil
+}
+
+// dirDepth returns the number of directories between the root of an image and dir.
+func dirDepth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
 }
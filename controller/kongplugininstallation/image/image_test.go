@@ -18,6 +18,16 @@ func TestFetchPluginContent(t *testing.T) {
 		require.ErrorContains(t, err, "unexpected format of image url: could not parse reference: foo bar")
 	})
 
+	t.Run("image not pinned by digest when required", func(t *testing.T) {
+		_, err := image.FetchPluginWithOptions(
+			t.Context(), "registry.example.com/plugin:0.1.0", nil, image.FetchOptions{
+				Verification: image.Verification{RequireDigest: true},
+			},
+		)
+		require.ErrorContains(t, err, "image registry.example.com/plugin:0.1.0 is not pinned by digest")
+		require.True(t, image.IsVerificationError(err))
+	})
+
 	// Learn more how images were build and pushed to the registry in hack/plugin-images/README.md.
 	const registryURL = "northamerica-northeast1-docker.pkg.dev/k8s-team-playground/"
 
@@ -70,7 +80,7 @@ func TestFetchPluginContent(t *testing.T) {
 		_, err := image.FetchPlugin(
 			t.Context(), registryURL+"plugin-example/invalid-name", nil,
 		)
-		require.ErrorContains(t, err, `file "add-header.lua" is unexpected, required files are handler.lua and schema.lua`)
+		require.ErrorContains(t, err, `required files not found in the image: handler.lua`)
 	})
 
 	// Source: hack/plugin-images/missing-file.Dockerfile.
@@ -0,0 +1,456 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+)
+
+// Verification configures the integrity checks performed on a plugin image
+// before its content is extracted. The zero value performs no checks.
+type Verification struct {
+	// RequireDigest requires the image to be pinned by digest,
+	// e.g. registry.example.com/plugin@sha256:<hex>.
+	RequireDigest bool
+	// PublicKeys are the keys trusted to sign the image with cosign.
+	// A signature made with any of them is accepted.
+	PublicKeys []crypto.PublicKey
+	// Keyless configures the verification of cosign keyless (Sigstore)
+	// signatures. When set, a signature made with a certificate matching it
+	// is accepted as well.
+	Keyless *KeylessVerification
+}
+
+// KeylessVerification configures the verification of cosign keyless signatures
+// which are made with short-lived certificates issued by Fulcio and recorded in
+// the Rekor transparency log.
+type KeylessVerification struct {
+	// Roots are the trusted Fulcio root certificates.
+	Roots *x509.CertPool
+	// Intermediates are Fulcio intermediate certificates used, in addition to the
+	// chain attached to the signature, to build the certificate chain.
+	Intermediates *x509.CertPool
+	// RekorPublicKeys are the keys of the Rekor transparency log used to verify
+	// the signed entry timestamps of the signatures.
+	RekorPublicKeys []crypto.PublicKey
+	// Identity is the expected subject of the signing certificate: an email
+	// address or a URI, e.g. a CI workflow identity.
+	Identity string
+	// Issuer is the expected OIDC issuer of the signing certificate,
+	// e.g. https://token.actions.githubusercontent.com.
+	Issuer string
+}
+
+// signatureRequired returns true when the image has to have a valid cosign signature.
+func (v Verification) signatureRequired() bool {
+	return len(v.PublicKeys) > 0 || v.Keyless != nil
+}
+
+// VerificationError is returned when a plugin image does not pass the
+// configured integrity checks.
+type VerificationError struct {
+	msg string
+}
+
+func (e *VerificationError) Error() string {
+	return e.msg
+}
+
+func newVerificationError(format string, args ...any) error {
+	return &VerificationError{msg: fmt.Sprintf(format, args...)}
+}
+
+// IsVerificationError returns true if the error is or wraps a VerificationError.
+func IsVerificationError(err error) bool {
+	var verr *VerificationError
+	return errors.As(err, &verr)
+}
+
+// ParsePublicKeys parses all the PEM encoded public keys from the provided data.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	return keys, nil
+}
+
+// Well known names used by cosign to store signatures in OCI registries.
+const (
+	cosignSignatureTagSuffix       = ".sig"
+	cosignSimpleSigningMediaType   = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation      = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation    = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation          = "dev.sigstore.cosign/chain"
+	cosignBundleAnnotation         = "dev.sigstore.cosign/bundle"
+	cosignContainerImageSignature  = "cosign container image signature"
+	maxCosignSignatureLayerBytes   = 64 * 1024
+	maxCosignSignatureManifestSize = 4 * 1024 * 1024
+)
+
+// Fulcio certificate extensions holding the OIDC issuer of the identity
+// the certificate has been issued for.
+var (
+	oidFulcioIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// cosignPayload is the simple signing payload signed by cosign.
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+// rekorBundle is the transparency log entry attached to keyless signatures.
+type rekorBundle struct {
+	SignedEntryTimestamp []byte `json:"SignedEntryTimestamp"`
+	Payload              struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogIndex       int64  `json:"logIndex"`
+		LogID          string `json:"logID"`
+	} `json:"Payload"`
+}
+
+// hashedRekord is the body of a Rekor hashedrekord entry.
+type hashedRekord struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Data struct {
+			Hash struct {
+				Algorithm string `json:"algorithm"`
+				Value     string `json:"value"`
+			} `json:"hash"`
+		} `json:"data"`
+		Signature struct {
+			Content   string `json:"content"`
+			PublicKey struct {
+				Content string `json:"content"`
+			} `json:"publicKey"`
+		} `json:"signature"`
+	} `json:"spec"`
+}
+
+// verifySignature verifies that the image manifest described by desc has at least
+// one cosign signature, stored in the repository next to the image, which is
+// accepted by the verification.
+func verifySignature(
+	ctx context.Context, repository oras.ReadOnlyTarget, desc ociv1.Descriptor, verification Verification,
+) error {
+	// cosign stores signatures in the image tagged sha256-<hex>.sig.
+	sigTag := strings.Replace(desc.Digest.String(), ":", "-", 1) + cosignSignatureTagSuffix
+	_, manifestContent, err := oras.FetchBytes(ctx, repository, sigTag, oras.FetchBytesOptions{
+		MaxBytes: maxCosignSignatureManifestSize,
+	})
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return newVerificationError("no cosign signature found for image digest %s", desc.Digest)
+		}
+		return fmt.Errorf("failed to fetch signatures of image digest %s: %w", desc.Digest, err)
+	}
+	var manifest ociv1.Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return fmt.Errorf("failed to parse signatures manifest of image digest %s: %w", desc.Digest, err)
+	}
+
+	var failures []string
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != cosignSimpleSigningMediaType {
+			continue
+		}
+		if layer.Size > maxCosignSignatureLayerBytes {
+			failures = append(failures, fmt.Sprintf("signature payload %s too large", layer.Digest))
+			continue
+		}
+		payload, err := content.FetchAll(ctx, repository, layer)
+		if err != nil {
+			return fmt.Errorf("failed to fetch signature payload %s: %w", layer.Digest, err)
+		}
+		if err := verifySignatureLayer(layer, payload, desc, verification); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		return nil
+	}
+	if len(failures) == 0 {
+		return newVerificationError("no cosign signature found for image digest %s", desc.Digest)
+	}
+	return newVerificationError("no valid cosign signature found for image digest %s: %s",
+		desc.Digest, strings.Join(failures, "; "),
+	)
+}
+
+// verifySignatureLayer verifies a single cosign signature: the signed payload has
+// to reference the image digest and the signature has to be made either with one
+// of the trusted keys or with a certificate matching the keyless configuration.
+func verifySignatureLayer(layer ociv1.Descriptor, payload []byte, desc ociv1.Descriptor, verification Verification) error {
+	var p cosignPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if p.Critical.Type != cosignContainerImageSignature {
+		return fmt.Errorf("unexpected signature payload type %q", p.Critical.Type)
+	}
+	if p.Critical.Image.DockerManifestDigest != desc.Digest.String() {
+		return fmt.Errorf("signature is for image digest %s", p.Critical.Image.DockerManifestDigest)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+	if err != nil || len(sig) == 0 {
+		return errors.New("signature missing or not base64 encoded")
+	}
+
+	for _, key := range verification.PublicKeys {
+		if verifyWithPublicKey(key, payload, sig) == nil {
+			return nil
+		}
+	}
+	if verification.Keyless != nil && layer.Annotations[cosignCertificateAnnotation] != "" {
+		return verifyKeyless(*verification.Keyless, layer.Annotations, payload, sig)
+	}
+	return errors.New("signature is not made with any of the trusted keys")
+}
+
+// verifyKeyless verifies a signature made with a Fulcio certificate. The certificate
+// has to chain to the trusted roots, match the expected identity and issuer and
+// be valid at the time the signature has been recorded in the transparency log.
+func verifyKeyless(keyless KeylessVerification, annotations map[string]string, payload, sig []byte) error {
+	certs, err := parseCertificates([]byte(annotations[cosignCertificateAnnotation]))
+	if err != nil {
+		return fmt.Errorf("invalid signing certificate: %w", err)
+	}
+	cert := certs[0]
+
+	bundleJSON, ok := annotations[cosignBundleAnnotation]
+	if !ok {
+		return errors.New("keyless signature has no transparency log bundle")
+	}
+	var bundle rekorBundle
+	if err := json.Unmarshal([]byte(bundleJSON), &bundle); err != nil {
+		return fmt.Errorf("invalid transparency log bundle: %w", err)
+	}
+	if err := verifyRekorBundle(keyless.RekorPublicKeys, bundle, payload, sig, cert); err != nil {
+		return err
+	}
+
+	intermediates := x509.NewCertPool()
+	if keyless.Intermediates != nil {
+		intermediates = keyless.Intermediates.Clone()
+	}
+	if chain, ok := annotations[cosignChainAnnotation]; ok {
+		chainCerts, err := parseCertificates([]byte(chain))
+		if err != nil {
+			return fmt.Errorf("invalid certificate chain: %w", err)
+		}
+		for _, c := range chainCerts {
+			intermediates.AddCert(c)
+		}
+	}
+	// Fulcio certificates are short-lived so they are verified at the time the
+	// signature has been recorded in the transparency log.
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         keyless.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   time.Unix(bundle.Payload.IntegratedTime, 0),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return fmt.Errorf("signing certificate is not trusted: %w", err)
+	}
+
+	if !slices.Contains(certificateIdentities(cert), keyless.Identity) {
+		return fmt.Errorf("signing certificate identities %v do not match %s",
+			certificateIdentities(cert), keyless.Identity,
+		)
+	}
+	if issuer := certificateIssuer(cert); issuer != keyless.Issuer {
+		return fmt.Errorf("signing certificate issuer %q does not match %s", issuer, keyless.Issuer)
+	}
+
+	if err := verifyWithPublicKey(cert.PublicKey, payload, sig); err != nil {
+		return fmt.Errorf("signature does not match the signing certificate: %w", err)
+	}
+	return nil
+}
+
+// verifyRekorBundle verifies that the transparency log entry is signed by Rekor
+// and that it records the signature of the payload made with the signing certificate.
+func verifyRekorBundle(
+	rekorKeys []crypto.PublicKey, bundle rekorBundle, payload, sig []byte, signer *x509.Certificate,
+) error {
+	// The signed entry timestamp is a signature over the canonical JSON of the
+	// payload, i.e. with the keys sorted and without whitespaces.
+	canonical, err := json.Marshal(struct {
+		Body           string `json:"body"`
+		IntegratedTime int64  `json:"integratedTime"`
+		LogID          string `json:"logID"`
+		LogIndex       int64  `json:"logIndex"`
+	}{
+		Body:           bundle.Payload.Body,
+		IntegratedTime: bundle.Payload.IntegratedTime,
+		LogID:          bundle.Payload.LogID,
+		LogIndex:       bundle.Payload.LogIndex,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode transparency log entry: %w", err)
+	}
+	if !slices.ContainsFunc(rekorKeys, func(key crypto.PublicKey) bool {
+		return verifyWithPublicKey(key, canonical, bundle.SignedEntryTimestamp) == nil
+	}) {
+		return errors.New("transparency log entry is not signed by a trusted Rekor key")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(bundle.Payload.Body)
+	if err != nil {
+		return fmt.Errorf("invalid transparency log entry body: %w", err)
+	}
+	var entry hashedRekord
+	if err := json.Unmarshal(body, &entry); err != nil {
+		return fmt.Errorf("invalid transparency log entry body: %w", err)
+	}
+	payloadHash := sha256.Sum256(payload)
+	if entry.Kind != "hashedrekord" ||
+		entry.Spec.Data.Hash.Algorithm != "sha256" ||
+		entry.Spec.Data.Hash.Value != hex.EncodeToString(payloadHash[:]) {
+		return errors.New("transparency log entry does not record the signed payload")
+	}
+	entrySig, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.Content)
+	if err != nil || !bytes.Equal(entrySig, sig) {
+		return errors.New("transparency log entry does not record the signature")
+	}
+	// Without checking the key recorded in the entry, an entry of the same
+	// signature made by any other signer would be accepted.
+	entryKey, err := rekorEntryPublicKey(entry)
+	if err != nil {
+		return fmt.Errorf("invalid transparency log entry public key: %w", err)
+	}
+	if k, ok := entryKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(signer.PublicKey) {
+		return errors.New("transparency log entry does not record the signing certificate")
+	}
+	return nil
+}
+
+// rekorEntryPublicKey returns the public key of the signer recorded in the hashedrekord
+// entry, which holds either the signing certificate or the public key, PEM encoded.
+func rekorEntryPublicKey(entry hashedRekord) (crypto.PublicKey, error) {
+	data, err := base64.StdEncoding.DecodeString(entry.Spec.Signature.PublicKey.Content)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded data found")
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unexpected PEM block type %q", block.Type)
+	}
+}
+
+// verifyWithPublicKey verifies the signature of the message made with the private
+// counterpart of the key the way cosign signs: ECDSA and RSA PKCS #1 v1.5
+// signatures over the SHA-256 digest of the message and Ed25519 signatures over
+// the message itself.
+func verifyWithPublicKey(key crypto.PublicKey, message, sig []byte) error {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, digest[:], sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, message, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// certificateIdentities returns the email and URI subject alternative names of the certificate.
+func certificateIdentities(cert *x509.Certificate) []string {
+	identities := slices.Clone(cert.EmailAddresses)
+	for _, u := range cert.URIs {
+		identities = append(identities, u.String())
+	}
+	return identities
+}
+
+// certificateIssuer returns the OIDC issuer recorded by Fulcio in the certificate.
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		case ext.Id.Equal(oidFulcioIssuer):
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,323 @@
+package image
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	ocidigest "github.com/opencontainers/go-digest"
+	ociv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+// pushBlob pushes the content to the store and returns its descriptor.
+func pushBlob(t *testing.T, store *memory.Store, mediaType string, data []byte) ociv1.Descriptor {
+	t.Helper()
+	desc := ociv1.Descriptor{
+		MediaType: mediaType,
+		Digest:    ocidigest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	exists, err := store.Exists(t.Context(), desc)
+	require.NoError(t, err)
+	if !exists {
+		require.NoError(t, store.Push(t.Context(), desc, bytes.NewReader(data)))
+	}
+	return desc
+}
+
+// pushSignature pushes a cosign signature manifest for the image with a single
+// signature layer holding the payload and the annotations.
+func pushSignature(
+	t *testing.T, store *memory.Store, image ociv1.Descriptor, payload []byte, annotations map[string]string,
+) {
+	t.Helper()
+	layer := pushBlob(t, store, cosignSimpleSigningMediaType, payload)
+	layer.Annotations = annotations
+	manifest, err := json.Marshal(ociv1.Manifest{
+		MediaType: ociv1.MediaTypeImageManifest,
+		Config:    pushBlob(t, store, "application/vnd.oci.image.config.v1+json", []byte("{}")),
+		Layers:    []ociv1.Descriptor{layer},
+	})
+	require.NoError(t, err)
+	manifestDesc := pushBlob(t, store, ociv1.MediaTypeImageManifest, manifest)
+	tag := fmt.Sprintf("sha256-%s.sig", image.Digest.Encoded())
+	require.NoError(t, store.Tag(t.Context(), manifestDesc, tag))
+}
+
+func cosignPayloadFor(t *testing.T, image ociv1.Descriptor) []byte {
+	t.Helper()
+	payload, err := json.Marshal(map[string]any{
+		"critical": map[string]any{
+			"identity": map[string]string{"docker-reference": "registry.example.com/plugin"},
+			"image":    map[string]string{"docker-manifest-digest": image.Digest.String()},
+			"type":     cosignContainerImageSignature,
+		},
+		"optional": nil,
+	})
+	require.NoError(t, err)
+	return payload
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, message []byte) []byte {
+	t.Helper()
+	digest := sha256.Sum256(message)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return sig
+}
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func TestVerifySignature_PublicKey(t *testing.T) {
+	store := memory.New()
+	image := pushBlob(t, store, ociv1.MediaTypeImageManifest, []byte(`{"schemaVersion":2}`))
+	key := newKey(t)
+	payload := cosignPayloadFor(t, image)
+	pushSignature(t, store, image, payload, map[string]string{
+		cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+	})
+
+	t.Run("signed with a trusted key", func(t *testing.T) {
+		err := verifySignature(t.Context(), store, image, Verification{
+			PublicKeys: []crypto.PublicKey{newKey(t).Public(), key.Public()},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("signed with an untrusted key", func(t *testing.T) {
+		err := verifySignature(t.Context(), store, image, Verification{
+			PublicKeys: []crypto.PublicKey{newKey(t).Public()},
+		})
+		require.Error(t, err)
+		assert.True(t, IsVerificationError(err))
+		assert.ErrorContains(t, err, "not made with any of the trusted keys")
+	})
+
+	t.Run("not signed", func(t *testing.T) {
+		unsigned := pushBlob(t, store, ociv1.MediaTypeImageManifest, []byte(`{"schemaVersion":2,"unsigned":true}`))
+		err := verifySignature(t.Context(), store, unsigned, Verification{
+			PublicKeys: []crypto.PublicKey{key.Public()},
+		})
+		require.Error(t, err)
+		assert.True(t, IsVerificationError(err))
+		assert.ErrorContains(t, err, "no cosign signature found")
+	})
+
+	t.Run("signature of another image", func(t *testing.T) {
+		other := pushBlob(t, store, ociv1.MediaTypeImageManifest, []byte(`{"schemaVersion":2,"other":true}`))
+		// The payload references the digest of the first image.
+		pushSignature(t, store, other, payload, map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sign(t, key, payload)),
+		})
+		err := verifySignature(t.Context(), store, other, Verification{
+			PublicKeys: []crypto.PublicKey{key.Public()},
+		})
+		require.Error(t, err)
+		assert.True(t, IsVerificationError(err))
+		assert.ErrorContains(t, err, "signature is for image digest "+image.Digest.String())
+	})
+}
+
+func TestVerifySignature_Keyless(t *testing.T) {
+	const (
+		identity = "https://github.com/kong/plugins/.github/workflows/release.yaml@refs/heads/main"
+		issuer   = "https://token.actions.githubusercontent.com"
+	)
+
+	rootKey := newKey(t)
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fulcio-root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	rootDER, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	require.NoError(t, err)
+	root, err := x509.ParseCertificate(rootDER)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	// Fulcio certificates are valid for a few minutes only.
+	signedAt := time.Now().Add(-30 * time.Minute)
+	signingKey := newKey(t)
+	identityURI, err := url.Parse(identity)
+	require.NoError(t, err)
+	issuerExt, err := asn1.Marshal(issuer)
+	require.NoError(t, err)
+	leafDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       signedAt.Add(-time.Minute),
+		NotAfter:        signedAt.Add(9 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		URIs:            []*url.URL{identityURI},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuerExt}},
+	}, root, signingKey.Public(), rootKey)
+	require.NoError(t, err)
+	leafPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})
+
+	manifest := []byte(`{"schemaVersion":2}`)
+	image := pushBlob(t, memory.New(), ociv1.MediaTypeImageManifest, manifest)
+	payload := cosignPayloadFor(t, image)
+	sig := sign(t, signingKey, payload)
+
+	rekorKey := newKey(t)
+	payloadHash := sha256.Sum256(payload)
+	// newBundle returns the transparency log bundle of the signature made by the signer.
+	newBundle := func(signerPEM []byte) string {
+		body, err := json.Marshal(map[string]any{
+			"apiVersion": "0.0.1",
+			"kind":       "hashedrekord",
+			"spec": map[string]any{
+				"data": map[string]any{
+					"hash": map[string]string{"algorithm": "sha256", "value": hex.EncodeToString(payloadHash[:])},
+				},
+				"signature": map[string]any{
+					"content":   base64.StdEncoding.EncodeToString(sig),
+					"publicKey": map[string]string{"content": base64.StdEncoding.EncodeToString(signerPEM)},
+				},
+			},
+		})
+		require.NoError(t, err)
+		var bundle rekorBundle
+		bundle.Payload.Body = base64.StdEncoding.EncodeToString(body)
+		bundle.Payload.IntegratedTime = signedAt.Unix()
+		bundle.Payload.LogIndex = 42
+		bundle.Payload.LogID = "c0d23d6ad406973f9559f3ba2d1ca01f84147d8ffc5b8445c224f98b9591801d"
+		canonical := fmt.Sprintf(`{"body":%q,"integratedTime":%d,"logID":%q,"logIndex":%d}`,
+			bundle.Payload.Body, bundle.Payload.IntegratedTime, bundle.Payload.LogID, bundle.Payload.LogIndex,
+		)
+		bundle.SignedEntryTimestamp = sign(t, rekorKey, []byte(canonical))
+		bundleJSON, err := json.Marshal(bundle)
+		require.NoError(t, err)
+		return string(bundleJSON)
+	}
+	otherSignerDER, err := x509.MarshalPKIXPublicKey(newKey(t).Public())
+	require.NoError(t, err)
+	otherSignerPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: otherSignerDER})
+
+	keyless := func(modify func(*KeylessVerification)) Verification {
+		k := &KeylessVerification{
+			Roots:           roots,
+			RekorPublicKeys: []crypto.PublicKey{rekorKey.Public()},
+			Identity:        identity,
+			Issuer:          issuer,
+		}
+		if modify != nil {
+			modify(k)
+		}
+		return Verification{Keyless: k}
+	}
+
+	testCases := []struct {
+		name          string
+		verification  Verification
+		bundle        string
+		expectedError string
+	}{
+		{
+			name:         "valid keyless signature",
+			verification: keyless(nil),
+		},
+		{
+			name:          "transparency log entry of another signer",
+			verification:  keyless(nil),
+			bundle:        newBundle(otherSignerPEM),
+			expectedError: "does not record the signing certificate",
+		},
+		{
+			name: "unexpected identity",
+			verification: keyless(func(k *KeylessVerification) {
+				k.Identity = "someone@example.com"
+			}),
+			expectedError: "do not match someone@example.com",
+		},
+		{
+			name: "unexpected issuer",
+			verification: keyless(func(k *KeylessVerification) {
+				k.Issuer = "https://accounts.google.com"
+			}),
+			expectedError: "does not match https://accounts.google.com",
+		},
+		{
+			name: "untrusted root",
+			verification: keyless(func(k *KeylessVerification) {
+				k.Roots = x509.NewCertPool()
+			}),
+			expectedError: "signing certificate is not trusted",
+		},
+		{
+			name: "untrusted transparency log",
+			verification: keyless(func(k *KeylessVerification) {
+				k.RekorPublicKeys = []crypto.PublicKey{newKey(t).Public()}
+			}),
+			expectedError: "not signed by a trusted Rekor key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := tc.bundle
+			if bundle == "" {
+				bundle = newBundle(leafPEM)
+			}
+			store := memory.New()
+			image := pushBlob(t, store, ociv1.MediaTypeImageManifest, manifest)
+			pushSignature(t, store, image, payload, map[string]string{
+				cosignSignatureAnnotation:   base64.StdEncoding.EncodeToString(sig),
+				cosignCertificateAnnotation: string(leafPEM),
+				cosignBundleAnnotation:      bundle,
+			})
+
+			err := verifySignature(t.Context(), store, image, tc.verification)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, IsVerificationError(err))
+			assert.ErrorContains(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	key := newKey(t)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	keys, err := ParsePublicKeys(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.True(t, key.PublicKey.Equal(keys[0]))
+
+	_, err = ParsePublicKeys([]byte("not a key"))
+	require.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	orascreds "oras.land/oras-go/v2/registry/remote/credentials"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+
+	"github.com/kong/kong-operator/controller/kongplugininstallation/image"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
+)
+
+// minPollInterval is the shortest interval of resolving plugin images allowed, to not
+// exceed rate limits of registries.
+const minPollInterval = 30 * time.Second
+
+const (
+	// KongPluginInstallationConditionImageResolved is the type of the condition reporting the
+	// digest the plugin image has been resolved to. It's set only when polling is configured.
+	KongPluginInstallationConditionImageResolved = "ImageResolved"
+	// KongPluginInstallationReasonImageResolved is the reason of the ImageResolved condition.
+	KongPluginInstallationReasonImageResolved = "Resolved"
+)
+
+// pollIntervalForKongPluginInstallation returns the interval of resolving the plugin image
+// configured in the KongPluginInstallation's annotation. 0 is returned when polling is
+// not configured.
+func pollIntervalForKongPluginInstallation(kpi *operatorv1alpha1.KongPluginInstallation) (time.Duration, error) {
+	v, ok := kpi.Annotations[consts.KongPluginInstallationPollIntervalAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value of annotation %s: %q: %w", consts.KongPluginInstallationPollIntervalAnnotation, v, err)
+	}
+	if interval < minPollInterval {
+		return 0, fmt.Errorf(
+			"invalid value of annotation %s: %q, it can't be shorter than %s", consts.KongPluginInstallationPollIntervalAnnotation, v, minPollInterval,
+		)
+	}
+	return interval, nil
+}
+
+// pluginSourceHash returns the hash of the inputs the plugin is fetched with: the
+// KongPluginInstallation's generation and annotations and versions of the Secrets
+// it references or holding the cosign keys trusted at the operator level.
+func (r *Reconciler) pluginSourceHash(ctx context.Context, kpi *operatorv1alpha1.KongPluginInstallation) (string, error) {
+	var secretsNN []client.ObjectKey
+	if secretRef := kpi.Spec.ImagePullSecretRef; secretRef != nil {
+		secretNN := client.ObjectKey{Namespace: kpi.Namespace, Name: string(secretRef.Name)}
+		if secretRef.Namespace != nil {
+			secretNN.Namespace = string(*secretRef.Namespace)
+		}
+		secretsNN = append(secretsNN, secretNN)
+	}
+	if r.TrustedCosignSecretNN.Name != "" {
+		secretsNN = append(secretsNN, r.TrustedCosignSecretNN)
+	} else if name, ok := kpi.Annotations[consts.KongPluginInstallationCosignSecretAnnotation]; ok {
+		secretsNN = append(secretsNN, client.ObjectKey{Namespace: kpi.Namespace, Name: name})
+	}
+
+	secretsVersions := make(map[string]string, len(secretsNN))
+	for _, secretNN := range secretsNN {
+		var secret corev1.Secret
+		if err := r.Get(ctx, secretNN, &secret); err != nil {
+			if k8serrors.IsNotFound(err) {
+				secretsVersions[secretNN.String()] = ""
+				continue
+			}
+			return "", fmt.Errorf("something unexpected during fetching secret %s: %w", secretNN, err)
+		}
+		secretsVersions[secretNN.String()] = secret.ResourceVersion
+	}
+
+	return k8sresources.CalculateHash(struct {
+		Generation      int64
+		Annotations     map[string]string
+		SecretsVersions map[string]string
+	}{
+		Generation:      kpi.Generation,
+		Annotations:     kpi.Annotations,
+		SecretsVersions: secretsVersions,
+	})
+}
+
+// isPluginUpToDate returns true when the plugin has been successfully fetched with the
+// inputs described by sourceHash and its image still resolves to the same digest.
+func (r *Reconciler) isPluginUpToDate(
+	ctx context.Context,
+	logger logr.Logger,
+	kpi *operatorv1alpha1.KongPluginInstallation,
+	credentialsStore orascreds.Store,
+	sourceHash string,
+) (bool, error) {
+	ready := lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
+		return c.Type == string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted) &&
+			c.Status == metav1.ConditionTrue &&
+			c.ObservedGeneration == kpi.Generation
+	})
+	if !ready {
+		return false, nil
+	}
+	cms, err := k8sutils.ListConfigMapsForOwner(ctx, r.Client, kpi.GetUID())
+	if err != nil {
+		return false, err
+	}
+	if len(cms) != 1 || cms[0].Annotations[consts.AnnotationKongPluginInstallationSourceHash] != sourceHash {
+		return false, nil
+	}
+
+	digest, err := image.ResolveDigest(ctx, kpi.Spec.Image, credentialsStore)
+	if err != nil {
+		// The installed plugin is kept when the registry is unavailable, it's retried with the next poll.
+		log.Info(logger, "failed to resolve plugin image, keeping the installed plugin", "image", kpi.Spec.Image, "error", err.Error())
+		return true, nil
+	}
+	if installed := cms[0].Annotations[consts.AnnotationKongPluginInstallationImageDigest]; digest != installed {
+		log.Info(logger, "plugin image digest changed", "image", kpi.Spec.Image, "installed", installed, "resolved", digest)
+		return false, nil
+	}
+	return true, setStatusConditionsForKongPluginInstallation(ctx, r.Client, kpi, newImageResolvedCondition(kpi, digest))
+}
+
+func newImageResolvedCondition(kpi *operatorv1alpha1.KongPluginInstallation, digest string) metav1.Condition {
+	return newKongPluginInstallationCondition(
+		kpi, KongPluginInstallationConditionImageResolved, metav1.ConditionTrue, KongPluginInstallationReasonImageResolved,
+		fmt.Sprintf("image %s resolved to digest %s", kpi.Spec.Image, digest),
+	)
+}
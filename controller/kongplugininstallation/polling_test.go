@@ -0,0 +1,208 @@
+package kongplugininstallation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestPollIntervalForKongPluginInstallation(t *testing.T) {
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		expected      time.Duration
+		expectedError string
+	}{
+		{
+			name: "polling not configured",
+		},
+		{
+			name: "valid interval",
+			annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: "5m",
+			},
+			expected: 5 * time.Minute,
+		},
+		{
+			name: "malformed interval",
+			annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: "often",
+			},
+			expectedError: `invalid value of annotation gateway-operator.konghq.com/plugin-image-poll-interval: "often"`,
+		},
+		{
+			name: "too short interval",
+			annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: "1s",
+			},
+			expectedError: "it can't be shorter than 30s",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			interval, err := pollIntervalForKongPluginInstallation(&operatorv1alpha1.KongPluginInstallation{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: tc.annotations,
+				},
+			})
+			if tc.expectedError != "" {
+				require.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, interval)
+		})
+	}
+}
+
+func TestPluginSourceHash(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "registry-credentials",
+			Namespace: "default",
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+	}
+	kpi := &operatorv1alpha1.KongPluginInstallation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "plugin",
+			Namespace:  "default",
+			Generation: 1,
+			Annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: "5m",
+			},
+		},
+		Spec: operatorv1alpha1.KongPluginInstallationSpec{
+			Image: "registry.example.com/plugin:stable",
+			ImagePullSecretRef: &gatewayv1.SecretObjectReference{
+				Name: "registry-credentials",
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(secret).
+		Build()
+	r := &Reconciler{Client: cl}
+
+	hash, err := r.pluginSourceHash(t.Context(), kpi)
+	require.NoError(t, err)
+	sameHash, err := r.pluginSourceHash(t.Context(), kpi)
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameHash, "hash is stable")
+
+	t.Run("doesn't change with status", func(t *testing.T) {
+		kpi := kpi.DeepCopy()
+		kpi.Status.UnderlyingConfigMapName = "plugin-abcde"
+		kpi.Status.Conditions = append(kpi.Status.Conditions, metav1.Condition{
+			Type:   string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+			Status: metav1.ConditionTrue,
+		})
+		unchanged, err := r.pluginSourceHash(t.Context(), kpi)
+		require.NoError(t, err)
+		assert.Equal(t, hash, unchanged)
+	})
+
+	t.Run("changes with annotations", func(t *testing.T) {
+		kpi := kpi.DeepCopy()
+		kpi.Annotations[consts.KongPluginInstallationRequireDigestAnnotation] = "true"
+		changed, err := r.pluginSourceHash(t.Context(), kpi)
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, changed)
+	})
+
+	t.Run("changes with referenced Secrets", func(t *testing.T) {
+		secret := secret.DeepCopy()
+		secret.Data = map[string][]byte{".dockerconfigjson": []byte("{}")}
+		require.NoError(t, cl.Update(t.Context(), secret))
+		changed, err := r.pluginSourceHash(t.Context(), kpi)
+		require.NoError(t, err)
+		assert.NotEqual(t, hash, changed)
+	})
+}
+
+func TestReconcileRetriesFailuresWithPollInterval(t *testing.T) {
+	const pollInterval = 5 * time.Minute
+
+	testCases := []struct {
+		name                  string
+		annotations           map[string]string
+		image                 string
+		expectedRequeueAfter  time.Duration
+		expectedConditionType string
+	}{
+		{
+			name: "verification failure with polling",
+			annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: pollInterval.String(),
+				consts.KongPluginInstallationCosignSecretAnnotation: "missing",
+			},
+			image:                 "localhost:1/plugin:1.0",
+			expectedRequeueAfter:  pollInterval,
+			expectedConditionType: KongPluginInstallationConditionVerified,
+		},
+		{
+			name: "fetch failure with polling",
+			annotations: map[string]string{
+				consts.KongPluginInstallationPollIntervalAnnotation: pollInterval.String(),
+			},
+			image:                 "localhost:1/plugin:1.0",
+			expectedRequeueAfter:  pollInterval,
+			expectedConditionType: string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+		},
+		{
+			name:                  "fetch failure without polling",
+			image:                 "localhost:1/plugin:1.0",
+			expectedConditionType: string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kpi := &operatorv1alpha1.KongPluginInstallation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "plugin",
+					Namespace:   "default",
+					Generation:  1,
+					Annotations: tc.annotations,
+				},
+				Spec: operatorv1alpha1.KongPluginInstallationSpec{
+					Image: tc.image,
+				},
+			}
+			cl := fake.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(kpi).
+				WithStatusSubresource(kpi).
+				Build()
+			r := &Reconciler{
+				Client: cl,
+				Scheme: scheme.Get(),
+			}
+
+			res, err := r.Reconcile(t.Context(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(kpi)})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRequeueAfter, res.RequeueAfter)
+
+			require.NoError(t, cl.Get(t.Context(), client.ObjectKeyFromObject(kpi), kpi))
+			assert.True(t, lo.ContainsBy(kpi.Status.Conditions, func(c metav1.Condition) bool {
+				return c.Type == tc.expectedConditionType && c.Status == metav1.ConditionFalse
+			}), "expected condition %s to be False, got %v", tc.expectedConditionType, kpi.Status.Conditions)
+		})
+	}
+}
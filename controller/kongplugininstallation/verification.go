@@ -0,0 +1,162 @@
+package kongplugininstallation
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+
+	"github.com/kong/kong-operator/controller/kongplugininstallation/image"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+const (
+	// KongPluginInstallationConditionVerified is the type of the condition reporting the
+	// result of the plugin image verification. It's set only when the verification
+	// is configured.
+	KongPluginInstallationConditionVerified = "Verified"
+	// KongPluginInstallationReasonVerified is the reason of the Verified condition when
+	// the plugin image passed all the configured checks.
+	KongPluginInstallationReasonVerified = "Verified"
+	// KongPluginInstallationReasonVerificationFailed is the reason of the Verified condition
+	// when the plugin image didn't pass one of the configured checks or when the
+	// verification is misconfigured.
+	KongPluginInstallationReasonVerificationFailed = "VerificationFailed"
+)
+
+// verificationForKongPluginInstallation returns the plugin image verification configured
+// in the KongPluginInstallation's annotations or, for the cosign signatures, at the
+// operator level. When the configuration is invalid, the
+// returned message describes why while the error is returned only for unexpected errors.
+func (r *Reconciler) verificationForKongPluginInstallation(
+	ctx context.Context, kpi *operatorv1alpha1.KongPluginInstallation,
+) (image.Verification, string, error) {
+	var verification image.Verification
+
+	if v, ok := kpi.Annotations[consts.KongPluginInstallationRequireDigestAnnotation]; ok {
+		requireDigest, err := strconv.ParseBool(v)
+		if err != nil {
+			return image.Verification{}, fmt.Sprintf("invalid value of annotation %s: %q", consts.KongPluginInstallationRequireDigestAnnotation, v), nil
+		}
+		verification.RequireDigest = requireDigest
+	}
+
+	if r.TrustedCosignSecretNN.Name != "" {
+		// The trust configured at the operator level can't be overridden by
+		// KongPluginInstallations, their cosign annotations are ignored.
+		trusted, invalidMsg, err := r.cosignVerificationFromSecret(
+			ctx, r.TrustedCosignSecretNN, r.TrustedCosignIdentity, r.TrustedCosignIssuer,
+		)
+		if err != nil || invalidMsg != "" {
+			return image.Verification{}, invalidMsg, err
+		}
+		trusted.RequireDigest = verification.RequireDigest
+		return trusted, "", nil
+	}
+
+	secretName, ok := kpi.Annotations[consts.KongPluginInstallationCosignSecretAnnotation]
+	if !ok {
+		if r.RequireImageVerification {
+			return image.Verification{}, fmt.Sprintf(
+				"image verification is required by the operator, set annotation %s", consts.KongPluginInstallationCosignSecretAnnotation,
+			), nil
+		}
+		return verification, "", nil
+	}
+
+	identity := kpi.Annotations[consts.KongPluginInstallationCosignIdentityAnnotation]
+	issuer := kpi.Annotations[consts.KongPluginInstallationCosignIssuerAnnotation]
+	if (identity == "") != (issuer == "") {
+		return image.Verification{}, fmt.Sprintf(
+			"both annotations %s and %s have to be set to verify keyless signatures",
+			consts.KongPluginInstallationCosignIdentityAnnotation, consts.KongPluginInstallationCosignIssuerAnnotation,
+		), nil
+	}
+
+	cosign, invalidMsg, err := r.cosignVerificationFromSecret(
+		ctx, client.ObjectKey{Namespace: kpi.Namespace, Name: secretName}, identity, issuer,
+	)
+	if err != nil || invalidMsg != "" {
+		return image.Verification{}, invalidMsg, err
+	}
+	cosign.RequireDigest = verification.RequireDigest
+	return cosign, "", nil
+}
+
+// cosignVerificationFromSecret returns the cosign signature verification trusting
+// the public keys and, when identity and issuer are set, the keyless signatures
+// chaining to the Fulcio roots stored in the Secret.
+func (r *Reconciler) cosignVerificationFromSecret(
+	ctx context.Context, secretNN client.ObjectKey, identity, issuer string,
+) (image.Verification, string, error) {
+	var verification image.Verification
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, secretNN, &secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return image.Verification{}, fmt.Sprintf("referenced Secret %q with cosign keys not found", secretNN), nil
+		}
+		return image.Verification{}, "", fmt.Errorf("something unexpected during fetching secret %s: %w", secretNN, err)
+	}
+
+	if data, ok := secret.Data[consts.KongPluginInstallationCosignPublicKeySecretKey]; ok {
+		keys, err := image.ParsePublicKeys(data)
+		if err != nil {
+			return image.Verification{}, fmt.Sprintf("can't parse key %s of secret %q: %s", consts.KongPluginInstallationCosignPublicKeySecretKey, secretNN, err), nil
+		}
+		verification.PublicKeys = keys
+	}
+
+	if identity != "" && issuer != "" {
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(secret.Data[consts.KongPluginInstallationCosignFulcioRootsSecretKey]) {
+			return image.Verification{}, fmt.Sprintf(
+				"key %s of secret %q doesn't contain PEM encoded certificates", consts.KongPluginInstallationCosignFulcioRootsSecretKey, secretNN,
+			), nil
+		}
+		rekorKeys, err := image.ParsePublicKeys(secret.Data[consts.KongPluginInstallationCosignRekorPublicKeySecretKey])
+		if err != nil {
+			return image.Verification{}, fmt.Sprintf("can't parse key %s of secret %q: %s", consts.KongPluginInstallationCosignRekorPublicKeySecretKey, secretNN, err), nil
+		}
+		verification.Keyless = &image.KeylessVerification{
+			Roots:           roots,
+			RekorPublicKeys: rekorKeys,
+			Identity:        identity,
+			Issuer:          issuer,
+		}
+	}
+
+	if len(verification.PublicKeys) == 0 && verification.Keyless == nil {
+		return image.Verification{}, fmt.Sprintf(
+			"secret %q has no key %s and keyless verification is not configured", secretNN, consts.KongPluginInstallationCosignPublicKeySecretKey,
+		), nil
+	}
+	return verification, "", nil
+}
+
+// isVerificationConfigured returns true when the KongPluginInstallation's plugin image is verified.
+func isVerificationConfigured(verification image.Verification) bool {
+	return verification.RequireDigest || len(verification.PublicKeys) > 0 || verification.Keyless != nil
+}
+
+func setStatusConditionsVerificationFailedForKongPluginInstallation(
+	ctx context.Context, cl client.Client, kpi *operatorv1alpha1.KongPluginInstallation, msg string,
+) error {
+	return setStatusConditionsForKongPluginInstallation(ctx, cl, kpi,
+		newKongPluginInstallationCondition(
+			kpi, string(operatorv1alpha1.KongPluginInstallationConditionStatusAccepted),
+			metav1.ConditionFalse, string(operatorv1alpha1.KongPluginInstallationReasonFailed), msg,
+		),
+		newKongPluginInstallationCondition(
+			kpi, KongPluginInstallationConditionVerified,
+			metav1.ConditionFalse, KongPluginInstallationReasonVerificationFailed, msg,
+		),
+	)
+}
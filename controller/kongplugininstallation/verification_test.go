@@ -0,0 +1,200 @@
+package kongplugininstallation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1alpha1"
+
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestVerificationForKongPluginInstallation(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "plugin-signing-keys",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			consts.KongPluginInstallationCosignPublicKeySecretKey: publicKeyPEM,
+		},
+	}
+	kpi := func(annotations map[string]string) *operatorv1alpha1.KongPluginInstallation {
+		return &operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "plugin",
+				Namespace:   "default",
+				Annotations: annotations,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                     string
+		kpi                      *operatorv1alpha1.KongPluginInstallation
+		requireImageVerification bool
+		expectedPublicKeys       int
+		expectedRequireDigest    bool
+		expectedInvalidMsg       string
+	}{
+		{
+			name: "no verification configured",
+			kpi:  kpi(nil),
+		},
+		{
+			name:                     "verification required by the operator but not configured",
+			kpi:                      kpi(nil),
+			requireImageVerification: true,
+			expectedInvalidMsg:       "image verification is required by the operator",
+		},
+		{
+			name: "digest and public key",
+			kpi: kpi(map[string]string{
+				consts.KongPluginInstallationRequireDigestAnnotation: "true",
+				consts.KongPluginInstallationCosignSecretAnnotation:  "plugin-signing-keys",
+			}),
+			requireImageVerification: true,
+			expectedPublicKeys:       1,
+			expectedRequireDigest:    true,
+		},
+		{
+			name: "missing Secret",
+			kpi: kpi(map[string]string{
+				consts.KongPluginInstallationCosignSecretAnnotation: "missing",
+			}),
+			expectedInvalidMsg: `referenced Secret "default/missing" with cosign keys not found`,
+		},
+		{
+			name: "keyless without issuer",
+			kpi: kpi(map[string]string{
+				consts.KongPluginInstallationCosignSecretAnnotation:   "plugin-signing-keys",
+				consts.KongPluginInstallationCosignIdentityAnnotation: "someone@example.com",
+			}),
+			expectedInvalidMsg: "have to be set to verify keyless signatures",
+		},
+		{
+			name: "keyless without Fulcio roots",
+			kpi: kpi(map[string]string{
+				consts.KongPluginInstallationCosignSecretAnnotation:   "plugin-signing-keys",
+				consts.KongPluginInstallationCosignIdentityAnnotation: "someone@example.com",
+				consts.KongPluginInstallationCosignIssuerAnnotation:   "https://accounts.google.com",
+			}),
+			expectedInvalidMsg: "doesn't contain PEM encoded certificates",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme.Get()).
+					WithObjects(secret).
+					Build(),
+				RequireImageVerification: tc.requireImageVerification,
+			}
+
+			verification, invalidMsg, err := r.verificationForKongPluginInstallation(t.Context(), tc.kpi)
+			require.NoError(t, err)
+			if tc.expectedInvalidMsg != "" {
+				assert.Contains(t, invalidMsg, tc.expectedInvalidMsg)
+				return
+			}
+			assert.Empty(t, invalidMsg)
+			assert.Len(t, verification.PublicKeys, tc.expectedPublicKeys)
+			assert.Equal(t, tc.expectedRequireDigest, verification.RequireDigest)
+			assert.Nil(t, verification.Keyless)
+		})
+	}
+}
+
+func TestVerificationForKongPluginInstallationTrustedAtOperatorLevel(t *testing.T) {
+	publicKeyPEM := func(t *testing.T, key *ecdsa.PrivateKey) []byte {
+		der, err := x509.MarshalPKIXPublicKey(key.Public())
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	}
+	operatorKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	kpiKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	operatorSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "operator-signing-keys",
+			Namespace: "kong-system",
+		},
+		Data: map[string][]byte{
+			consts.KongPluginInstallationCosignPublicKeySecretKey: publicKeyPEM(t, operatorKey),
+		},
+	}
+	kpiSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "plugin-signing-keys",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			consts.KongPluginInstallationCosignPublicKeySecretKey: publicKeyPEM(t, kpiKey),
+		},
+	}
+	r := &Reconciler{
+		Client: fake.NewClientBuilder().
+			WithScheme(scheme.Get()).
+			WithObjects(operatorSecret, kpiSecret).
+			Build(),
+		TrustedCosignSecretNN: client.ObjectKeyFromObject(operatorSecret),
+	}
+
+	t.Run("KongPluginInstallation without cosign annotations", func(t *testing.T) {
+		verification, invalidMsg, err := r.verificationForKongPluginInstallation(t.Context(), &operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "plugin",
+				Namespace: "default",
+				Annotations: map[string]string{
+					consts.KongPluginInstallationRequireDigestAnnotation: "true",
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, invalidMsg)
+		require.Len(t, verification.PublicKeys, 1)
+		assert.True(t, operatorKey.PublicKey.Equal(verification.PublicKeys[0]))
+		assert.True(t, verification.RequireDigest)
+	})
+
+	t.Run("KongPluginInstallation can't override the trusted keys and identities", func(t *testing.T) {
+		verification, invalidMsg, err := r.verificationForKongPluginInstallation(t.Context(), &operatorv1alpha1.KongPluginInstallation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "plugin",
+				Namespace: "default",
+				Annotations: map[string]string{
+					consts.KongPluginInstallationCosignSecretAnnotation:   kpiSecret.Name,
+					consts.KongPluginInstallationCosignIdentityAnnotation: "someone@example.com",
+					consts.KongPluginInstallationCosignIssuerAnnotation:   "https://accounts.google.com",
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, invalidMsg)
+		require.Len(t, verification.PublicKeys, 1)
+		assert.True(t, operatorKey.PublicKey.Equal(verification.PublicKeys[0]))
+		assert.Nil(t, verification.Keyless)
+	})
+}
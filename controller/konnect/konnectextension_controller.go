@@ -52,6 +52,7 @@ type KonnectExtensionReconciler struct {
 	ClusterCASecretName      string
 	ClusterCASecretNamespace string
 	ClusterCAKeyConfig       secrets.KeyConfig
+	ClusterCertificateIssuer secrets.IssuerConfig
 	SecretLabelSelector      string
 }
 
@@ -342,6 +343,15 @@ func (r *KonnectExtensionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 
 	// get the Kubernetes secret holding the certificate.
 	opRes, certificateSecret, err := r.getCertificateSecret(ctx, ext, false)
+	if errors.Is(err, secrets.ErrCertificateNotReady) {
+		log.Debug(logger, "waiting for DataPlane client certificate to be issued")
+		return ctrl.Result{RequeueAfter: secrets.CertificateNotReadyRequeueAfter}, nil
+	}
+	if errors.Is(err, secrets.ErrCertificateIssuanceFailed) {
+		// The certificate is requested again when the request or its Secret changes.
+		log.Info(logger, "DataPlane client certificate issuance failed", "reason", err.Error())
+		return ctrl.Result{}, nil
+	}
 	if client.IgnoreNotFound(err) != nil {
 		return ctrl.Result{}, err
 	}
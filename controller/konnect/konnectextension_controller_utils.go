@@ -224,7 +224,13 @@ func (r *KonnectExtensionReconciler) ensureCertificateSecret(ctx context.Context
 		},
 		usages,
 		r.ClusterCAKeyConfig,
+		// Certificates issued by an external issuer are registered in Konnect like the ones
+		// signed by the cluster CA, as they're matched by their content.
+		r.ClusterCertificateIssuer,
+		// The certificate is registered in Konnect, renewing it requires registering the new one.
+		secrets.CertificateRenewalConfig{},
 		r.Client,
+		nil,
 		matchingLabels,
 	)
 }
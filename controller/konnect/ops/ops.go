@@ -408,6 +408,12 @@ func Update[
 		)
 	}
 
+	// When the spec has already been applied, the entity is re-synced periodically.
+	// Check whether it has drifted in Konnect and whether the drift should be reverted.
+	if isProgrammedWithCurrentGeneration(e) && !checkDrift(ctx, sdk, e) {
+		return ctrl.Result{RequeueAfter: syncPeriod}, nil
+	}
+
 	var (
 		err        error
 		entityType = e.GetTypeName()
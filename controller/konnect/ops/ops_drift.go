@@ -0,0 +1,318 @@
+package ops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	kcfgconsts "github.com/kong/kubernetes-configuration/v2/api/common/consts"
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+
+	"github.com/kong/kong-operator/controller/konnect/constraints"
+	sdkops "github.com/kong/kong-operator/controller/konnect/ops/sdk"
+	"github.com/kong/kong-operator/controller/pkg/log"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+// DriftPolicy configures what happens when the state of an entity in Konnect differs from the desired one.
+type DriftPolicy string
+
+const (
+	// DriftPolicyEnforce reports the drift and periodically re-applies the desired state.
+	DriftPolicyEnforce DriftPolicy = consts.KonnectDriftPolicyEnforce
+	// DriftPolicyReportOnly reports the drift without reverting it.
+	DriftPolicyReportOnly DriftPolicy = consts.KonnectDriftPolicyReportOnly
+	// DriftPolicyIgnore neither reports nor reverts the drift.
+	DriftPolicyIgnore DriftPolicy = consts.KonnectDriftPolicyIgnore
+)
+
+const (
+	// KonnectEntityDriftedConditionType is the type of the condition set on Konnect entities
+	// which state in Konnect differs from their spec, e.g. because of changes made in the Konnect UI.
+	// The condition is removed once no drift is detected.
+	KonnectEntityDriftedConditionType kcfgconsts.ConditionType = "Drifted"
+	// KonnectEntityDriftedReasonDriftDetected indicates that the drift was detected and left in place
+	// because of the ReportOnly drift policy.
+	KonnectEntityDriftedReasonDriftDetected kcfgconsts.ConditionReason = "DriftDetected"
+	// KonnectEntityDriftedReasonDriftReverted indicates that the drift was detected and the desired
+	// state was re-applied because of the Enforce drift policy.
+	KonnectEntityDriftedReasonDriftReverted kcfgconsts.ConditionReason = "DriftReverted"
+	// KonnectEntityDriftedReasonDriftDetectionUnsupported indicates that the entity's drift policy
+	// is not honored because drift detection is not supported for its type: the desired state is
+	// periodically re-applied as with the Enforce drift policy.
+	KonnectEntityDriftedReasonDriftDetectionUnsupported kcfgconsts.ConditionReason = "DriftDetectionUnsupported"
+)
+
+// maxReportedFieldDrifts is the maximum number of drifted fields described in the Drifted condition message.
+const maxReportedFieldDrifts = 10
+
+// FieldDrift describes a field of a Konnect entity which value differs from the desired one.
+type FieldDrift struct {
+	// Path is the path of the field in the Konnect API representation of the entity, e.g. "healthchecks.active.timeout".
+	Path string
+	// Desired is the desired value of the field.
+	Desired any
+	// Actual is the value of the field in Konnect.
+	Actual any
+}
+
+// EntityDrift describes how the state of an entity in Konnect differs from the desired one.
+type EntityDrift struct {
+	// Missing is true when the entity was not found in Konnect.
+	Missing bool
+	// Fields are the drifted fields of the entity.
+	Fields []FieldDrift
+}
+
+// Empty returns true if there's no drift.
+func (d EntityDrift) Empty() bool {
+	return !d.Missing && len(d.Fields) == 0
+}
+
+// String returns a human readable description of the drift.
+func (d EntityDrift) String() string {
+	if d.Missing {
+		return "entity not found in Konnect"
+	}
+	msgs := lo.Map(lo.Slice(d.Fields, 0, maxReportedFieldDrifts), func(f FieldDrift, _ int) string {
+		return fmt.Sprintf("%s: desired %s, actual %s", f.Path, formatFieldValue(f.Desired), formatFieldValue(f.Actual))
+	})
+	if rest := len(d.Fields) - maxReportedFieldDrifts; rest > 0 {
+		msgs = append(msgs, fmt.Sprintf("and %d more", rest))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func formatFieldValue(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+// DriftPolicyForEntity returns the drift policy configured for the entity with the
+// konnect.konghq.com/drift-policy annotation. It defaults to DriftPolicyEnforce.
+func DriftPolicyForEntity(obj metav1.Object) DriftPolicy {
+	switch p := DriftPolicy(obj.GetAnnotations()[consts.KonnectDriftPolicyAnnotationKey]); p {
+	case DriftPolicyReportOnly, DriftPolicyIgnore:
+		return p
+	default:
+		return DriftPolicyEnforce
+	}
+}
+
+// checkDrift detects the drift of an entity which spec has already been applied in Konnect and reports it
+// with the Drifted condition according to the entity's drift policy. It returns true if the desired state
+// should be re-applied. Entities of types drift detection is not supported for are always re-applied,
+// i.e. they're treated as with DriftPolicyEnforce regardless of their drift policy, and a drift policy
+// other than DriftPolicyEnforce is reported as unsupported with the Drifted condition.
+func checkDrift[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	e TEnt,
+) bool {
+	policy := DriftPolicyForEntity(e)
+	if !supportsDriftDetection(e) {
+		if policy == DriftPolicyEnforce {
+			removeKonnectEntityDriftedCondition(e)
+		} else {
+			setKonnectEntityDriftDetectionUnsupportedCondition(e, e.GetTypeName(), policy)
+		}
+		return true
+	}
+
+	if policy == DriftPolicyIgnore {
+		removeKonnectEntityDriftedCondition(e)
+		return false
+	}
+
+	drift, err := detectDrift(ctx, sdk, e)
+	switch {
+	case err != nil:
+		log.Error(ctrllog.FromContext(ctx), err, "failed to detect drift of the entity in Konnect")
+	case drift.Empty():
+		removeKonnectEntityDriftedCondition(e)
+	case policy == DriftPolicyReportOnly:
+		setKonnectEntityDriftedCondition(e, KonnectEntityDriftedReasonDriftDetected, drift)
+	default:
+		setKonnectEntityDriftedCondition(e, KonnectEntityDriftedReasonDriftReverted, drift)
+	}
+	return policy == DriftPolicyEnforce
+}
+
+// driftStatesFunc returns the desired state of an entity and its actual state in Konnect,
+// in their Konnect API representations.
+type driftStatesFunc func(ctx context.Context, sdk sdkops.SDKWrapper, e any) (desired, actual any, err error)
+
+// driftDetectors holds the entity types drift detection is supported for.
+var driftDetectors = map[reflect.Type]driftStatesFunc{
+	reflect.TypeFor[*configurationv1alpha1.KongService](): func(ctx context.Context, sdk sdkops.SDKWrapper, e any) (any, any, error) {
+		svc := e.(*configurationv1alpha1.KongService)
+		desired, err := kongServiceToDesiredSDKService(svc)
+		if err != nil {
+			return nil, nil, err
+		}
+		actual, err := getService(ctx, sdk.GetServicesSDK(), svc)
+		return desired, actual, err
+	},
+	reflect.TypeFor[*configurationv1alpha1.KongRoute](): func(ctx context.Context, sdk sdkops.SDKWrapper, e any) (any, any, error) {
+		route := e.(*configurationv1alpha1.KongRoute)
+		actual, err := getRoute(ctx, sdk.GetRoutesSDK(), route)
+		return kongRouteToSDKRouteInput(route), actual, err
+	},
+	reflect.TypeFor[*configurationv1alpha1.KongUpstream](): func(ctx context.Context, sdk sdkops.SDKWrapper, e any) (any, any, error) {
+		upstream := e.(*configurationv1alpha1.KongUpstream)
+		actual, err := getUpstream(ctx, sdk.GetUpstreamsSDK(), upstream)
+		return kongUpstreamToSDKUpstreamInput(upstream), actual, err
+	},
+}
+
+// supportsDriftDetection returns true if drift detection is supported for the entity's type.
+func supportsDriftDetection(e any) bool {
+	_, ok := driftDetectors[reflect.TypeOf(e)]
+	return ok
+}
+
+// detectDrift fetches the entity from Konnect and compares it with the entity's desired state.
+// Only fields set in the desired state are compared, so that defaults filled in by Konnect are not
+// reported as a drift. It returns an error if drift detection is not supported for the entity type.
+func detectDrift[
+	T constraints.SupportedKonnectEntityType,
+	TEnt constraints.EntityType[T],
+](
+	ctx context.Context,
+	sdk sdkops.SDKWrapper,
+	e TEnt,
+) (EntityDrift, error) {
+	states, ok := driftDetectors[reflect.TypeOf(e)]
+	if !ok {
+		return EntityDrift{}, fmt.Errorf("drift detection is not supported for %T", e)
+	}
+	desired, actual, err := states(ctx, sdk, e)
+	if errIsNotFound(err) {
+		return EntityDrift{Missing: true}, nil
+	}
+	if err != nil {
+		return EntityDrift{}, err
+	}
+
+	fields, err := diffFields(desired, actual)
+	if err != nil {
+		return EntityDrift{}, err
+	}
+	return EntityDrift{Fields: fields}, nil
+}
+
+// diffFields compares the JSON representations of the desired and actual states of an entity.
+// Fields which are not set in the desired state are ignored.
+func diffFields(desired, actual any) ([]FieldDrift, error) {
+	desiredValue, err := toJSONValue(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert desired state: %w", err)
+	}
+	actualValue, err := toJSONValue(actual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert actual state: %w", err)
+	}
+
+	var drifts []FieldDrift
+	collectFieldDrifts("", desiredValue, actualValue, &drifts)
+	return drifts, nil
+}
+
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func collectFieldDrifts(path string, desired, actual any, drifts *[]FieldDrift) {
+	if desired == nil || (isEmptyJSONValue(desired) && actual == nil) {
+		return
+	}
+	desiredObj, desiredIsObj := desired.(map[string]any)
+	actualObj, actualIsObj := actual.(map[string]any)
+	if desiredIsObj && actualIsObj {
+		for _, key := range slices.Sorted(maps.Keys(desiredObj)) {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			collectFieldDrifts(fieldPath, desiredObj[key], actualObj[key], drifts)
+		}
+		return
+	}
+	if !reflect.DeepEqual(desired, actual) {
+		*drifts = append(*drifts, FieldDrift{Path: path, Desired: desired, Actual: actual})
+	}
+}
+
+func isEmptyJSONValue(v any) bool {
+	switch v := v.(type) {
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// isProgrammedWithCurrentGeneration returns true if the current generation of the entity's spec
+// has been successfully applied in Konnect.
+func isProgrammedWithCurrentGeneration(ent entityType) bool {
+	cond, ok := k8sutils.GetCondition(konnectv1alpha1.KonnectEntityProgrammedConditionType, ent)
+	return ok &&
+		cond.Status == metav1.ConditionTrue &&
+		cond.Reason == konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed &&
+		cond.ObservedGeneration == ent.GetGeneration()
+}
+
+func setKonnectEntityDriftedCondition(obj entityType, reason kcfgconsts.ConditionReason, drift EntityDrift) {
+	_setKonnectEntityConditon(
+		obj,
+		KonnectEntityDriftedConditionType,
+		metav1.ConditionTrue,
+		reason,
+		drift.String(),
+	)
+}
+
+func setKonnectEntityDriftDetectionUnsupportedCondition(obj entityType, typeName string, policy DriftPolicy) {
+	_setKonnectEntityConditon(
+		obj,
+		KonnectEntityDriftedConditionType,
+		metav1.ConditionUnknown,
+		KonnectEntityDriftedReasonDriftDetectionUnsupported,
+		fmt.Sprintf("drift policy %s is not supported for %s, the spec is enforced", policy, typeName),
+	)
+}
+
+func removeKonnectEntityDriftedCondition(obj entityType) {
+	obj.SetConditions(lo.Reject(obj.GetConditions(), func(c metav1.Condition, _ int) bool {
+		return c.Type == string(KonnectEntityDriftedConditionType)
+	}))
+}
@@ -0,0 +1,250 @@
+package ops
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
+	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
+	sdkkonnecterrs "github.com/Kong/sdk-konnect-go/models/sdkerrors"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
+	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
+
+	"github.com/kong/kong-operator/modules/manager/scheme"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+	"github.com/kong/kong-operator/test/mocks/metricsmocks"
+	"github.com/kong/kong-operator/test/mocks/sdkmocks"
+)
+
+func TestUpdateDetectsDrift(t *testing.T) {
+	const (
+		cpID  = "cp-id"
+		svcID = "svc-id"
+	)
+	kongService := func(driftPolicy string, observedGeneration int64) *configurationv1alpha1.KongService {
+		svc := &configurationv1alpha1.KongService{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "svc",
+				Namespace:  "default",
+				Generation: 2,
+			},
+			Spec: configurationv1alpha1.KongServiceSpec{
+				KongServiceAPISpec: configurationv1alpha1.KongServiceAPISpec{
+					Name: lo.ToPtr("svc"),
+					URL:  lo.ToPtr("https://example.com:8443/api"),
+				},
+			},
+			Status: configurationv1alpha1.KongServiceStatus{
+				Konnect: &konnectv1alpha2.KonnectEntityStatusWithControlPlaneRef{
+					KonnectEntityStatus: konnectv1alpha2.KonnectEntityStatus{
+						ID: svcID,
+					},
+					ControlPlaneID: cpID,
+				},
+				Conditions: []metav1.Condition{
+					{
+						Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+						Status:             metav1.ConditionTrue,
+						Reason:             konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed,
+						ObservedGeneration: observedGeneration,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+					},
+				},
+			},
+		}
+		if driftPolicy != "" {
+			svc.Annotations = map[string]string{
+				consts.KonnectDriftPolicyAnnotationKey: driftPolicy,
+			}
+		}
+		return svc
+	}
+	serviceInKonnect := func(svc *configurationv1alpha1.KongService, host string) *sdkkonnectops.GetServiceResponse {
+		return &sdkkonnectops.GetServiceResponse{
+			Service: &sdkkonnectcomp.ServiceOutput{
+				ID:        lo.ToPtr(svcID),
+				Name:      lo.ToPtr("svc"),
+				Host:      host,
+				Port:      lo.ToPtr(int64(8443)),
+				Path:      lo.ToPtr("/api"),
+				Protocol:  lo.ToPtr(sdkkonnectcomp.ProtocolHTTPS),
+				Retries:   lo.ToPtr(int64(5)),
+				Tags:      GenerateTagsForObject(svc),
+				CreatedAt: lo.ToPtr(int64(1700000000)),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		svc                  *configurationv1alpha1.KongService
+		sdkFunc              func(*testing.T, *sdkmocks.MockServicesSDK, *configurationv1alpha1.KongService)
+		expectedDriftReason  string
+		expectedDriftMessage string
+	}{
+		{
+			name: "Enforce: no drift, desired state is re-applied",
+			svc:  kongService("", 2),
+			sdkFunc: func(t *testing.T, sdk *sdkmocks.MockServicesSDK, svc *configurationv1alpha1.KongService) {
+				sdk.EXPECT().GetService(mock.Anything, svcID, cpID).Return(serviceInKonnect(svc, "example.com"), nil)
+				sdk.EXPECT().UpsertService(mock.Anything, mock.Anything).Return(&sdkkonnectops.UpsertServiceResponse{}, nil)
+			},
+		},
+		{
+			name: "Enforce: drift is reported and reverted",
+			svc:  kongService(consts.KonnectDriftPolicyEnforce, 2),
+			sdkFunc: func(t *testing.T, sdk *sdkmocks.MockServicesSDK, svc *configurationv1alpha1.KongService) {
+				sdk.EXPECT().GetService(mock.Anything, svcID, cpID).Return(serviceInKonnect(svc, "changed.example.com"), nil)
+				sdk.EXPECT().UpsertService(mock.Anything, mock.Anything).Return(&sdkkonnectops.UpsertServiceResponse{}, nil)
+			},
+			expectedDriftReason:  string(KonnectEntityDriftedReasonDriftReverted),
+			expectedDriftMessage: `host: desired "example.com", actual "changed.example.com"`,
+		},
+		{
+			name: "ReportOnly: drift is reported and not reverted",
+			svc:  kongService(consts.KonnectDriftPolicyReportOnly, 2),
+			sdkFunc: func(t *testing.T, sdk *sdkmocks.MockServicesSDK, svc *configurationv1alpha1.KongService) {
+				sdk.EXPECT().GetService(mock.Anything, svcID, cpID).Return(serviceInKonnect(svc, "changed.example.com"), nil)
+			},
+			expectedDriftReason:  string(KonnectEntityDriftedReasonDriftDetected),
+			expectedDriftMessage: `host: desired "example.com", actual "changed.example.com"`,
+		},
+		{
+			name: "ReportOnly: missing entity is reported",
+			svc:  kongService(consts.KonnectDriftPolicyReportOnly, 2),
+			sdkFunc: func(t *testing.T, sdk *sdkmocks.MockServicesSDK, _ *configurationv1alpha1.KongService) {
+				sdk.EXPECT().GetService(mock.Anything, svcID, cpID).Return(nil, &sdkkonnecterrs.SDKError{
+					StatusCode: http.StatusNotFound,
+				})
+			},
+			expectedDriftReason:  string(KonnectEntityDriftedReasonDriftDetected),
+			expectedDriftMessage: "entity not found in Konnect",
+		},
+		{
+			name: "ReportOnly: changed spec is applied without checking the drift",
+			svc:  kongService(consts.KonnectDriftPolicyReportOnly, 1),
+			sdkFunc: func(t *testing.T, sdk *sdkmocks.MockServicesSDK, _ *configurationv1alpha1.KongService) {
+				sdk.EXPECT().UpsertService(mock.Anything, mock.Anything).Return(&sdkkonnectops.UpsertServiceResponse{}, nil)
+			},
+		},
+		{
+			name: "Ignore: drift is neither checked nor reverted",
+			svc: func() *configurationv1alpha1.KongService {
+				svc := kongService(consts.KonnectDriftPolicyIgnore, 2)
+				setKonnectEntityDriftedCondition(svc, KonnectEntityDriftedReasonDriftDetected, EntityDrift{Missing: true})
+				return svc
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Get()).
+				Build()
+			sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+			if tc.sdkFunc != nil {
+				tc.sdkFunc(t, sdk.ServicesSDK, tc.svc)
+			}
+
+			_, err := Update(t.Context(), sdk, time.Minute, fakeClient, &metricsmocks.MockRecorder{}, tc.svc)
+			require.NoError(t, err)
+
+			cond, ok := k8sutils.GetCondition(KonnectEntityDriftedConditionType, tc.svc)
+			if tc.expectedDriftReason == "" {
+				assert.False(t, ok, "Drifted condition should not be set")
+				return
+			}
+			require.True(t, ok, "Drifted condition should be set")
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, tc.expectedDriftReason, cond.Reason)
+			assert.Equal(t, tc.expectedDriftMessage, cond.Message)
+		})
+	}
+}
+
+func TestUpdateEnforcesEntitiesWithoutDriftDetection(t *testing.T) {
+	const (
+		cpID     = "cp-id"
+		caCertID = "ca-cert-id"
+	)
+	cert := &configurationv1alpha1.KongCACertificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ca-cert",
+			Namespace:  "default",
+			Generation: 2,
+			Annotations: map[string]string{
+				consts.KonnectDriftPolicyAnnotationKey: consts.KonnectDriftPolicyReportOnly,
+			},
+		},
+		Spec: configurationv1alpha1.KongCACertificateSpec{
+			KongCACertificateAPISpec: configurationv1alpha1.KongCACertificateAPISpec{
+				Cert: "cert",
+			},
+		},
+		Status: configurationv1alpha1.KongCACertificateStatus{
+			Konnect: &konnectv1alpha2.KonnectEntityStatusWithControlPlaneRef{
+				KonnectEntityStatus: konnectv1alpha2.KonnectEntityStatus{
+					ID: caCertID,
+				},
+				ControlPlaneID: cpID,
+			},
+			Conditions: []metav1.Condition{
+				{
+					Type:               konnectv1alpha1.KonnectEntityProgrammedConditionType,
+					Status:             metav1.ConditionTrue,
+					Reason:             konnectv1alpha1.KonnectEntityProgrammedReasonProgrammed,
+					ObservedGeneration: 2,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+				},
+			},
+		},
+	}
+	fakeClient := fakectrlruntimeclient.
+		NewClientBuilder().
+		WithScheme(scheme.Get()).
+		Build()
+	sdk := sdkmocks.NewMockSDKWrapperWithT(t)
+	sdk.CACertificatesSDK.EXPECT().
+		UpsertCaCertificate(mock.Anything, mock.MatchedBy(func(req sdkkonnectops.UpsertCaCertificateRequest) bool {
+			return req.ControlPlaneID == cpID && req.CACertificateID == caCertID
+		})).
+		Return(&sdkkonnectops.UpsertCaCertificateResponse{}, nil)
+
+	_, err := Update(t.Context(), sdk, time.Minute, fakeClient, &metricsmocks.MockRecorder{}, cert)
+	require.NoError(t, err)
+
+	cond, ok := k8sutils.GetCondition(KonnectEntityDriftedConditionType, cert)
+	require.True(t, ok, "Drifted condition should report the drift policy as unsupported")
+	assert.Equal(t, metav1.ConditionUnknown, cond.Status)
+	assert.Equal(t, string(KonnectEntityDriftedReasonDriftDetectionUnsupported), cond.Reason)
+	assert.Equal(t, "drift policy ReportOnly is not supported for KongCACertificate, the spec is enforced", cond.Message)
+
+	t.Log("switching the drift policy to Enforce removes the condition")
+	cert.Annotations[consts.KonnectDriftPolicyAnnotationKey] = consts.KonnectDriftPolicyEnforce
+	_, err = Update(t.Context(), sdk, time.Minute, fakeClient, &metricsmocks.MockRecorder{}, cert)
+	require.NoError(t, err)
+	_, ok = k8sutils.GetCondition(KonnectEntityDriftedConditionType, cert)
+	assert.False(t, ok, "Drifted condition should not be set")
+}
+
+func TestEntityDriftString(t *testing.T) {
+	fields := make([]FieldDrift, 0, maxReportedFieldDrifts+2)
+	for range maxReportedFieldDrifts + 2 {
+		fields = append(fields, FieldDrift{Path: "retries", Desired: float64(1), Actual: nil})
+	}
+	msg := EntityDrift{Fields: fields}.String()
+	assert.Contains(t, msg, "retries: desired 1, actual <unset>")
+	assert.Contains(t, msg, "; and 2 more")
+}
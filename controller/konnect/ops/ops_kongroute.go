@@ -83,6 +83,23 @@ func deleteRoute(
 	return nil
 }
 
+// getRoute fetches the Konnect Route of the KongRoute.
+// It is assumed that provided KongRoute has Konnect ID set in status.
+func getRoute(
+	ctx context.Context,
+	sdk sdkops.RoutesSDK,
+	route *configurationv1alpha1.KongRoute,
+) (*sdkkonnectcomp.Route, error) {
+	resp, err := sdk.GetRoute(ctx, route.GetKonnectStatus().GetKonnectID(), route.GetControlPlaneID())
+	if errWrap := wrapErrIfKonnectOpFailed(err, GetOp, route); errWrap != nil {
+		return nil, errWrap
+	}
+	if resp == nil || resp.Route == nil {
+		return nil, fmt.Errorf("failed getting %s: %w", route.GetTypeName(), ErrNilResponse)
+	}
+	return resp.Route, nil
+}
+
 func kongRouteToSDKRouteInput(
 	route *configurationv1alpha1.KongRoute,
 ) sdkkonnectcomp.Route {
@@ -3,6 +3,8 @@ package ops
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 
 	sdkkonnectcomp "github.com/Kong/sdk-konnect-go/models/components"
 	sdkkonnectops "github.com/Kong/sdk-konnect-go/models/operations"
@@ -86,6 +88,59 @@ func deleteService(
 	return nil
 }
 
+// getService fetches the Konnect Service of the KongService.
+// It is assumed that provided KongService has Konnect ID set in status.
+func getService(
+	ctx context.Context,
+	sdk sdkops.ServicesSDK,
+	svc *configurationv1alpha1.KongService,
+) (*sdkkonnectcomp.ServiceOutput, error) {
+	resp, err := sdk.GetService(ctx, svc.GetKonnectStatus().GetKonnectID(), svc.GetControlPlaneID())
+	if errWrap := wrapErrIfKonnectOpFailed(err, GetOp, svc); errWrap != nil {
+		return nil, errWrap
+	}
+	if resp == nil || resp.Service == nil {
+		return nil, fmt.Errorf("failed getting %s: %w", svc.GetTypeName(), ErrNilResponse)
+	}
+	return resp.Service, nil
+}
+
+// kongServiceToDesiredSDKService returns the desired state of the Konnect Service comparable with
+// the Service fetched from Konnect, which doesn't have the URL field expanded into protocol,
+// host, port and path.
+func kongServiceToDesiredSDKService(
+	svc *configurationv1alpha1.KongService,
+) (sdkkonnectcomp.Service, error) {
+	s := kongServiceToSDKServiceInput(svc)
+	if s.URL == nil {
+		return s, nil
+	}
+
+	u, err := url.Parse(*s.URL)
+	if err != nil {
+		return s, fmt.Errorf("failed to parse URL of %s: %w", svc.GetTypeName(), err)
+	}
+	s.URL = nil
+	s.Protocol = lo.ToPtr(sdkkonnectcomp.Protocol(u.Scheme))
+	s.Host = u.Hostname()
+	if u.Path != "" {
+		s.Path = lo.ToPtr(u.Path)
+	}
+	switch port := u.Port(); {
+	case port != "":
+		p, err := strconv.ParseInt(port, 10, 64)
+		if err != nil {
+			return s, fmt.Errorf("failed to parse port in URL of %s: %w", svc.GetTypeName(), err)
+		}
+		s.Port = lo.ToPtr(p)
+	case u.Scheme == "https":
+		s.Port = lo.ToPtr(int64(443))
+	case u.Scheme == "http":
+		s.Port = lo.ToPtr(int64(80))
+	}
+	return s, nil
+}
+
 func kongServiceToSDKServiceInput(
 	svc *configurationv1alpha1.KongService,
 ) sdkkonnectcomp.Service {
@@ -86,6 +86,23 @@ func deleteUpstream(
 	return nil
 }
 
+// getUpstream fetches the Konnect Upstream of the KongUpstream.
+// It is assumed that provided KongUpstream has Konnect ID set in status.
+func getUpstream(
+	ctx context.Context,
+	sdk sdkops.UpstreamsSDK,
+	upstream *configurationv1alpha1.KongUpstream,
+) (*sdkkonnectcomp.Upstream, error) {
+	resp, err := sdk.GetUpstream(ctx, upstream.GetKonnectStatus().GetKonnectID(), upstream.GetControlPlaneID())
+	if errWrap := wrapErrIfKonnectOpFailed(err, GetOp, upstream); errWrap != nil {
+		return nil, errWrap
+	}
+	if resp == nil || resp.Upstream == nil {
+		return nil, fmt.Errorf("failed getting %s: %w", upstream.GetTypeName(), ErrNilResponse)
+	}
+	return resp.Upstream, nil
+}
+
 func kongUpstreamToSDKUpstreamInput(
 	upstream *configurationv1alpha1.KongUpstream,
 ) sdkkonnectcomp.Upstream {
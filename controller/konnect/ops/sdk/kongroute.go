@@ -12,5 +12,6 @@ type RoutesSDK interface {
 	CreateRoute(ctx context.Context, controlPlaneID string, route sdkkonnectcomp.Route, opts ...sdkkonnectops.Option) (*sdkkonnectops.CreateRouteResponse, error)
 	UpsertRoute(ctx context.Context, req sdkkonnectops.UpsertRouteRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.UpsertRouteResponse, error)
 	DeleteRoute(ctx context.Context, controlPlaneID, routeID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.DeleteRouteResponse, error)
+	GetRoute(ctx context.Context, routeID, controlPlaneID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.GetRouteResponse, error)
 	ListRoute(ctx context.Context, request sdkkonnectops.ListRouteRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.ListRouteResponse, error)
 }
@@ -12,5 +12,6 @@ type ServicesSDK interface {
 	CreateService(ctx context.Context, controlPlaneID string, service sdkkonnectcomp.Service, opts ...sdkkonnectops.Option) (*sdkkonnectops.CreateServiceResponse, error)
 	UpsertService(ctx context.Context, req sdkkonnectops.UpsertServiceRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.UpsertServiceResponse, error)
 	DeleteService(ctx context.Context, controlPlaneID, serviceID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.DeleteServiceResponse, error)
+	GetService(ctx context.Context, serviceID, controlPlaneID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.GetServiceResponse, error)
 	ListService(ctx context.Context, request sdkkonnectops.ListServiceRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.ListServiceResponse, error)
 }
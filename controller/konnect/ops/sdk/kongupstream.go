@@ -12,5 +12,6 @@ type UpstreamsSDK interface {
 	CreateUpstream(ctx context.Context, controlPlaneID string, upstream sdkkonnectcomp.Upstream, opts ...sdkkonnectops.Option) (*sdkkonnectops.CreateUpstreamResponse, error)
 	UpsertUpstream(ctx context.Context, req sdkkonnectops.UpsertUpstreamRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.UpsertUpstreamResponse, error)
 	DeleteUpstream(ctx context.Context, controlPlaneID, upstreamID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.DeleteUpstreamResponse, error)
+	GetUpstream(ctx context.Context, upstreamID, controlPlaneID string, opts ...sdkkonnectops.Option) (*sdkkonnectops.GetUpstreamResponse, error)
 	ListUpstream(ctx context.Context, request sdkkonnectops.ListUpstreamRequest, opts ...sdkkonnectops.Option) (*sdkkonnectops.ListUpstreamResponse, error)
 }
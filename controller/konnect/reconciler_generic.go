@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -49,6 +51,7 @@ type KonnectEntityReconciler[T constraints.SupportedKonnectEntityType, TEnt cons
 	SyncPeriod              time.Duration
 
 	MetricRecorder metrics.Recorder
+	eventRecorder  record.EventRecorder
 }
 
 // KonnectEntityReconcilerOption is a functional option for the KonnectEntityReconciler.
@@ -129,6 +132,7 @@ func (r *KonnectEntityReconciler[T, TEnt]) SetupWithManager(ctx context.Context,
 	for _, dep := range ReconciliationWatchOptionsForEntity(r.Client, ent) {
 		b = dep(b)
 	}
+	r.eventRecorder = mgr.GetEventRecorderFor(entityTypeName)
 	return b.Complete(r)
 }
 
@@ -473,7 +477,9 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 		return ctrl.Result{}, nil
 	}
 
+	driftedCond, _ := k8sutils.GetCondition(ops.KonnectEntityDriftedConditionType, ent)
 	res, err = ops.Update(ctx, sdk, r.SyncPeriod, r.Client, r.MetricRecorder, ent)
+	r.recordDriftEvent(ent, driftedCond)
 	// Set the server URL and org ID regardless of the error.
 	setStatusServerURLAndOrgID(ent, server, apiAuth.Status.OrganizationID)
 	// Update the status of the object regardless of the error.
@@ -505,6 +511,19 @@ func (r *KonnectEntityReconciler[T, TEnt]) Reconcile(
 	}, nil
 }
 
+// recordDriftEvent emits an event when the entity has drifted in Konnect since the previous reconciliation
+// or its drift has changed.
+func (r *KonnectEntityReconciler[T, TEnt]) recordDriftEvent(ent TEnt, prevDriftedCond metav1.Condition) {
+	cond, ok := k8sutils.GetCondition(ops.KonnectEntityDriftedConditionType, ent)
+	if r.eventRecorder == nil || !ok || cond.Status != metav1.ConditionTrue ||
+		(cond.Reason == prevDriftedCond.Reason && cond.Message == prevDriftedCond.Message) {
+		return
+	}
+	r.eventRecorder.Eventf(ent, corev1.EventTypeWarning, cond.Reason,
+		"%s drifted in Konnect (drift policy %s): %s", constraints.EntityTypeName[T](), ops.DriftPolicyForEntity(ent), cond.Message,
+	)
+}
+
 func setStatusServerURLAndOrgID(
 	ent interface {
 		GetKonnectStatus() *konnectv1alpha2.KonnectEntityStatus
@@ -1,6 +1,7 @@
 package secrets
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/rand"
@@ -24,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -31,6 +33,7 @@ import (
 
 	"github.com/kong/kong-operator/controller/pkg/dataplane"
 	"github.com/kong/kong-operator/controller/pkg/op"
+	"github.com/kong/kong-operator/internal/metrics"
 	"github.com/kong/kong-operator/modules/manager/logging"
 	"github.com/kong/kong-operator/pkg/consts"
 	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
@@ -175,7 +178,14 @@ func IsTLSSecretValid(secret *corev1.Secret) bool {
 
 // EnsureCertificate creates a namespace/name Secret for subject signed by the CA in the
 // mtlsCASecretNamespace/mtlsCASecretName Secret, or does nothing if a namespace/name Secret is
-// already present. It returns a boolean indicating if it created a Secret and an error indicating
+// already present. The certificate in an existing Secret is renewed in place, keeping the Secret's
+// name, when the fraction of its lifetime configured in renewalConfig has passed. Events about
+// renewals and upcoming expiries are emitted for the owner when eventRecorder is not nil.
+// When renewalConfig follows the CA rotation, certificates not signed by the current CA are
+// re-issued and the trust bundle stored in the Secret is kept in sync with the CA Secret.
+// When issuer is external, the certificate is issued by a cert-manager issuer or through
+// a CertificateSigningRequest instead, and ErrCertificateNotReady is returned until it's issued.
+// It returns a boolean indicating if it created a Secret and an error indicating
 // any failures it encountered.
 func EnsureCertificate[
 	T interface {
@@ -189,12 +199,22 @@ func EnsureCertificate[
 	mtlsCASecretNN types.NamespacedName,
 	usages []certificatesv1.KeyUsage,
 	keyConfig KeyConfig,
+	issuer IssuerConfig,
+	renewalConfig CertificateRenewalConfig,
 	cl client.Client,
+	eventRecorder record.EventRecorder,
 	additionalMatchingLabels client.MatchingLabels,
 ) (op.Result, *corev1.Secret, error) {
 	// Get the Secrets for the DataPlane using new labels.
 	matchingLabels := k8sresources.GetManagedLabelForOwner(owner)
 	maps.Copy(matchingLabels, additionalMatchingLabels)
+	secretOpts := append(getSecretOpts(owner), matchingLabelsToSecretOpt(matchingLabels))
+
+	switch issuer.Kind {
+	case IssuerKindCertManagerIssuer, IssuerKindCertManagerClusterIssuer:
+		generatedSecret := k8sresources.GenerateNewTLSSecret(owner, secretOpts...)
+		return ensureCertManagerCertificate(ctx, owner, subject, usages, keyConfig, renewalConfig, issuer, cl, eventRecorder, generatedSecret)
+	}
 
 	secrets, err := k8sutils.ListSecretsForOwner(ctx, cl, owner.GetUID(), matchingLabels)
 	if err != nil {
@@ -209,13 +229,19 @@ func EnsureCertificate[
 		return op.Noop, nil, errors.New("number of secrets reduced")
 	}
 
-	secretOpts := append(getSecretOpts(owner), matchingLabelsToSecretOpt(matchingLabels))
-
 	generatedSecret := k8sresources.GenerateNewTLSSecret(owner, secretOpts...)
 
+	if issuer.Kind == IssuerKindCertificateSigningRequest {
+		var existingSecret *corev1.Secret
+		if count == 1 {
+			existingSecret = &secrets[0]
+		}
+		return ensureCSRIssuedCertificate(ctx, owner, subject, mtlsCASecretNN, usages, keyConfig, renewalConfig, issuer, cl, eventRecorder, generatedSecret, existingSecret)
+	}
+
 	// If there are no secrets yet, then create one.
 	if count == 0 {
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, renewalConfig, cl)
 	}
 
 	// Otherwise there is already 1 certificate matching specified selectors.
@@ -227,8 +253,9 @@ func EnsureCertificate[
 		if err := cl.Delete(ctx, existingSecret); err != nil {
 			return op.Noop, nil, err
 		}
+		metrics.DeleteCertificateExpiration(existingSecret.Namespace, existingSecret.Name)
 
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, renewalConfig, cl)
 	}
 
 	// Check if existing certificate is for a different subject.
@@ -241,12 +268,65 @@ func EnsureCertificate[
 		if err := cl.Delete(ctx, existingSecret); err != nil {
 			return op.Noop, nil, err
 		}
+		metrics.DeleteCertificateExpiration(existingSecret.Namespace, existingSecret.Name)
+
+		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, renewalConfig, cl)
+	}
 
-		return generateTLSDataSecret(ctx, generatedSecret, owner, subject, mtlsCASecretNN, usages, keyConfig, cl)
+	var ca *corev1.Secret
+	if renewalConfig.FollowCARotation {
+		ca = &corev1.Secret{}
+		if err := cl.Get(ctx, mtlsCASecretNN, ca); err != nil {
+			return op.Noop, nil, fmt.Errorf("failed getting CA secret %s: %w", mtlsCASecretNN, err)
+		}
 	}
 
+	// Renew the certificate in place so that the Secret's name referenced by Deployments
+	// doesn't change. Components trusting the CA keep accepting the old certificate until
+	// it expires, so the new one can be rolled out without dropping connections.
+	// The same applies to certificates signed by a rotated CA, which stays trusted until
+	// all certificates are re-issued.
+	now := time.Now()
+	renew := renewalConfig.needsRenewal(cert, now)
+	reissue := ca != nil && !isSignedByCA(cert, ca)
+	if renew || reissue {
+		data, err := generateTLSData(ctx, owner, subject, mtlsCASecretNN, usages, keyConfig, renewalConfig, cl)
+		if err != nil {
+			return op.Noop, nil, fmt.Errorf("failed renewing certificate in secret %s: %w", existingSecret.Name, err)
+		}
+		_, existingSecret.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingSecret.ObjectMeta, generatedSecret.ObjectMeta)
+		existingSecret.Data = data
+		if err := cl.Update(ctx, existingSecret); err != nil {
+			return op.Noop, existingSecret, fmt.Errorf("failed updating secret %s: %w", existingSecret.Name, err)
+		}
+		if renewed, err := ParseSecretCertificate(existingSecret); err == nil {
+			metrics.RecordCertificateExpiration(existingSecret.Namespace, existingSecret.Name, renewed.NotAfter)
+		}
+		switch {
+		case eventRecorder == nil:
+		case reissue:
+			eventRecorder.Eventf(owner, corev1.EventTypeNormal, CertificateReissuedEventReason,
+				"Re-issued certificate in Secret %s which was signed by a rotated CA",
+				existingSecret.Name,
+			)
+		default:
+			eventRecorder.Eventf(owner, corev1.EventTypeNormal, CertificateRenewedEventReason,
+				"Renewed certificate in Secret %s which was valid until %s",
+				existingSecret.Name, cert.NotAfter.UTC().Format(time.RFC3339),
+			)
+		}
+		return op.Updated, existingSecret, nil
+	}
+	observeCertificate(owner, existingSecret, cert, renewalConfig, eventRecorder, now)
+
 	var updated bool
 	updated, existingSecret.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingSecret.ObjectMeta, generatedSecret.ObjectMeta)
+	if ca != nil {
+		if bundle := CATrustBundle(ca); !bytes.Equal(existingSecret.Data["ca.crt"], bundle) {
+			existingSecret.Data["ca.crt"] = bundle
+			updated = true
+		}
+	}
 	if updated {
 		if err := cl.Update(ctx, existingSecret); err != nil {
 			return op.Noop, existingSecret, fmt.Errorf("failed updating secret %s: %w", existingSecret.Name, err)
@@ -310,77 +390,116 @@ func generateTLSDataSecret(
 	mtlsCASecret types.NamespacedName,
 	usages []certificatesv1.KeyUsage,
 	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
 	k8sClient client.Client,
 ) (op.Result, *corev1.Secret, error) {
-	priv, pemBlock, signatureAlgorithm, err := CreatePrivateKey(keyConfig)
+	data, err := generateTLSData(ctx, owner, subject, mtlsCASecret, usages, keyConfig, renewalConfig, k8sClient)
 	if err != nil {
 		return op.Noop, nil, err
 	}
+	generatedSecret.Data = data
 
-	template := x509.CertificateRequest{
-		Subject: pkix.Name{
-			CommonName:   subject,
-			Organization: []string{"Kong, Inc."},
-			Country:      []string{"US"},
-		},
-		SignatureAlgorithm: signatureAlgorithm,
-		DNSNames:           []string{subject},
+	err = k8sClient.Create(ctx, generatedSecret)
+	if err != nil {
+		return op.Noop, nil, err
 	}
 
-	der, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
+	return op.Created, generatedSecret, nil
+}
+
+// generateTLSData generates a private key and a certificate for subject signed by the CA
+// in the mtlsCASecret Secret. It returns them as TLS Secret data.
+func generateTLSData(
+	ctx context.Context,
+	owner client.Object,
+	subject string,
+	mtlsCASecret types.NamespacedName,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	k8sClient client.Client,
+) (map[string][]byte, error) {
+	request, key, err := createCertificateRequest(subject, keyConfig)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, err
 	}
 
 	// This is effectively a placeholder so long as we handle signing internally. When actually creating CSR resources,
 	// this string is used by signers to filter which resources they pay attention to
 	signerName := "gateway-operator.konghq.com/mtls"
-	// TODO This creates certificates that last for 10 years as an arbitrarily long period for the alpha. A production-
-	// ready implementation should use a shorter lifetime and rotate certificates. Rotation requires some mechanism to
-	// recognize that certificates have expired (ideally without permissions to read Secrets across the cluster) and
-	// to get Deployments to acknowledge them. For Kong, this requires a restart, as there's no way to force a reload
-	// of updated files on disk.
-	expiration := int32(315400000)
-
 	csr := certificatesv1.CertificateSigningRequest{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: owner.GetNamespace(),
 			Name:      owner.GetName(),
 		},
-		Spec: certificatesv1.CertificateSigningRequestSpec{
-			Request: pem.EncodeToMemory(&pem.Block{
-				Type:  "CERTIFICATE REQUEST",
-				Bytes: der,
-			}),
-			SignerName:        signerName,
-			ExpirationSeconds: &expiration,
-			Usages:            usages,
-		},
+		Spec: newCertificateSigningRequestSpec(request, signerName, usages, renewalConfig),
 	}
 
 	var ca corev1.Secret
 	err = k8sClient.Get(ctx, mtlsCASecret, &ca)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, err
 	}
 
 	signed, err := signCertificate(csr, &ca)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, err
 	}
 
-	generatedSecret.Data = map[string][]byte{
-		"ca.crt":  ca.Data["tls.crt"],
+	return map[string][]byte{
+		"ca.crt":  CATrustBundle(&ca),
 		"tls.crt": signed,
-		"tls.key": pem.EncodeToMemory(pemBlock),
+		"tls.key": key,
+	}, nil
+}
+
+// createCertificateRequest generates a private key and a PEM encoded certificate request for subject.
+// It returns the request and the PEM encoded private key.
+func createCertificateRequest(subject string, keyConfig KeyConfig) ([]byte, []byte, error) {
+	priv, pemBlock, signatureAlgorithm, err := CreatePrivateKey(keyConfig)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	err = k8sClient.Create(ctx, generatedSecret)
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   subject,
+			Organization: []string{"Kong, Inc."},
+			Country:      []string{"US"},
+		},
+		SignatureAlgorithm: signatureAlgorithm,
+		DNSNames:           []string{subject},
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &template, priv)
 	if err != nil {
-		return op.Noop, nil, err
+		return nil, nil, err
 	}
 
-	return op.Created, generatedSecret, nil
+	request := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: der,
+	})
+	return request, pem.EncodeToMemory(pemBlock), nil
+}
+
+// newCertificateSigningRequestSpec returns the spec of a CertificateSigningRequest for the PEM encoded request.
+func newCertificateSigningRequestSpec(
+	request []byte,
+	signerName string,
+	usages []certificatesv1.KeyUsage,
+	renewalConfig CertificateRenewalConfig,
+) certificatesv1.CertificateSigningRequestSpec {
+	// Certificates are renewed by EnsureCertificate according to the renewal configuration. Kong doesn't reload
+	// certificates from disk, so the DataPlane Deployments roll out their Pods when the certificate changes.
+	expiration := int32(renewalConfig.lifetime().Seconds())
+
+	return certificatesv1.CertificateSigningRequestSpec{
+		Request:           request,
+		SignerName:        signerName,
+		ExpirationSeconds: &expiration,
+		Usages:            usages,
+	}
 }
 
 // GetManagedLabelForServiceSecret returns a label selector for the ServiceSecret.
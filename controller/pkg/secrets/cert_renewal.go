@@ -0,0 +1,155 @@
+package secrets
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/internal/metrics"
+)
+
+const (
+	// DefaultCertificateLifetime is the default lifetime of certificates issued by the operator.
+	DefaultCertificateLifetime = time.Second * 315400000
+
+	// DefaultCertificateExpiryWarningThreshold is the default remaining lifetime of a certificate
+	// below which warning events about its upcoming expiry are emitted.
+	DefaultCertificateExpiryWarningThreshold = 30 * 24 * time.Hour
+)
+
+const (
+	// CertificateRenewedEventReason is the reason of the event emitted when a certificate is renewed.
+	CertificateRenewedEventReason = "CertificateRenewed"
+
+	// CertificateReissuedEventReason is the reason of the event emitted when a certificate
+	// is re-issued because the CA which signed it has been rotated.
+	CertificateReissuedEventReason = "CertificateReissued"
+
+	// CertificateExpiringEventReason is the reason of the event emitted when a certificate
+	// expires soon and it hasn't been renewed.
+	CertificateExpiringEventReason = "CertificateExpiring"
+)
+
+// CertificateRenewalConfig configures the lifetime and the renewal of certificates issued by the operator.
+// The zero value issues certificates with the default lifetime and disables their renewal.
+type CertificateRenewalConfig struct {
+	// Lifetime is the lifetime of issued certificates. DefaultCertificateLifetime is used when it's 0.
+	// The lifetime is capped by the expiry of the CA certificate.
+	Lifetime time.Duration
+
+	// RenewalFraction is the fraction of a certificate's lifetime after which the certificate is renewed,
+	// e.g. 0.67 renews certificates when two thirds of their lifetime have passed. Renewal is disabled when it's 0.
+	RenewalFraction float64
+
+	// ExpiryWarningThreshold is the remaining lifetime of a certificate below which warning events
+	// are emitted for its owner. DefaultCertificateExpiryWarningThreshold is used when it's 0.
+	ExpiryWarningThreshold time.Duration
+
+	// FollowCARotation makes issued certificates follow the rotation of the CA: the trust bundle
+	// stored in their Secrets is kept in sync with the CA Secret and certificates which aren't
+	// signed by the CA currently signing certificates are re-issued.
+	FollowCARotation bool
+}
+
+// Validate validates the configuration.
+func (c CertificateRenewalConfig) Validate() error {
+	if c.Lifetime < 0 {
+		return fmt.Errorf("certificate lifetime must not be negative, got %s", c.Lifetime)
+	}
+	// CertificateSigningRequests express the expiration in int32 seconds.
+	if c.Lifetime.Seconds() > float64(1<<31-1) {
+		return fmt.Errorf("certificate lifetime must not exceed %s, got %s", time.Duration(1<<31-1)*time.Second, c.Lifetime)
+	}
+	if c.RenewalFraction < 0 || c.RenewalFraction >= 1 {
+		return fmt.Errorf("certificate renewal fraction must be in the [0, 1) range, got %v", c.RenewalFraction)
+	}
+	if c.ExpiryWarningThreshold < 0 {
+		return fmt.Errorf("certificate expiry warning threshold must not be negative, got %s", c.ExpiryWarningThreshold)
+	}
+	return nil
+}
+
+func (c CertificateRenewalConfig) lifetime() time.Duration {
+	if c.Lifetime == 0 {
+		return DefaultCertificateLifetime
+	}
+	return c.Lifetime
+}
+
+func (c CertificateRenewalConfig) expiryWarningThreshold() time.Duration {
+	if c.ExpiryWarningThreshold == 0 {
+		return DefaultCertificateExpiryWarningThreshold
+	}
+	return c.ExpiryWarningThreshold
+}
+
+// RenewalTime returns the time after which the certificate should be renewed.
+// It returns false if renewal is disabled.
+func (c CertificateRenewalConfig) RenewalTime(cert *x509.Certificate) (time.Time, bool) {
+	if c.RenewalFraction == 0 {
+		return time.Time{}, false
+	}
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * c.RenewalFraction)), true
+}
+
+// CertificateRenewalRequeueAfter returns the duration after which the owner of the provided TLS Secret
+// should be reconciled again for its certificate to be renewed. It returns 0 if renewal is disabled
+// or the certificate can't be parsed.
+func CertificateRenewalRequeueAfter(secret *corev1.Secret, cfg CertificateRenewalConfig) time.Duration {
+	cert, err := ParseSecretCertificate(secret)
+	if err != nil {
+		return 0
+	}
+	renewalTime, ok := cfg.RenewalTime(cert)
+	if !ok {
+		return 0
+	}
+	// Requeue at least after a second, so that the owner isn't requeued in a hot loop
+	// when the renewal keeps failing.
+	return max(time.Until(renewalTime), time.Second)
+}
+
+// ParseSecretCertificate parses the certificate stored in the TLS Secret.
+func ParseSecretCertificate(secret *corev1.Secret) (*x509.Certificate, error) {
+	block, _ := pem.Decode(secret.Data["tls.crt"])
+	if block == nil {
+		return nil, errors.New("failed decoding 'tls.crt' data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// needsRenewal returns true if the certificate should be renewed at the provided time.
+func (c CertificateRenewalConfig) needsRenewal(cert *x509.Certificate, now time.Time) bool {
+	renewalTime, ok := c.RenewalTime(cert)
+	return ok && !now.Before(renewalTime)
+}
+
+// observeCertificate records the expiry of the certificate stored in the Secret and emits
+// a warning event for its owner if the certificate expires soon.
+func observeCertificate(
+	owner client.Object,
+	secret *corev1.Secret,
+	cert *x509.Certificate,
+	cfg CertificateRenewalConfig,
+	eventRecorder record.EventRecorder,
+	now time.Time,
+) {
+	metrics.RecordCertificateExpiration(secret.Namespace, secret.Name, cert.NotAfter)
+
+	if eventRecorder == nil {
+		return
+	}
+	if remaining := cert.NotAfter.Sub(now); remaining < cfg.expiryWarningThreshold() {
+		eventRecorder.Eventf(owner, corev1.EventTypeWarning, CertificateExpiringEventReason,
+			"Certificate in Secret %s expires at %s (in %s)",
+			secret.Name, cert.NotAfter.UTC().Format(time.RFC3339), remaining.Round(time.Second),
+		)
+	}
+}
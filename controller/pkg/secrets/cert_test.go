@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	ctrlruntimelog "sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -368,7 +369,10 @@ func TestMaybeCreateCertificateSecret(t *testing.T) {
 					certificatesv1.UsageServerAuth,
 				},
 				tc.keyConfig,
+				IssuerConfig{},
+				CertificateRenewalConfig{},
 				fakeClient,
+				nil,
 				tc.additionalMatchingLabels,
 			)
 
@@ -394,6 +398,180 @@ func TestMaybeCreateCertificateSecret(t *testing.T) {
 	}
 }
 
+func TestEnsureCertificateRenewal(t *testing.T) {
+	const subject = "test-subject"
+	var (
+		caNN = types.NamespacedName{Name: "test-mtls-secret", Namespace: "ns"}
+		dp   = &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp-1",
+				Namespace: "ns",
+				UID:       types.UID("1234"),
+			},
+		}
+	)
+
+	testCases := []struct {
+		name           string
+		notBefore      time.Time
+		notAfter       time.Time
+		renewalConfig  CertificateRenewalConfig
+		expectedResult op.Result
+		expectRenewed  bool
+		expectedEvent  string
+	}{
+		{
+			name:      "certificate is not renewed before the configured fraction of its lifetime passes",
+			notBefore: time.Now().Add(-time.Hour),
+			notAfter:  time.Now().Add(3 * time.Hour),
+			renewalConfig: CertificateRenewalConfig{
+				RenewalFraction:        0.5,
+				ExpiryWarningThreshold: time.Hour,
+			},
+			expectedResult: op.Noop,
+		},
+		{
+			name:      "certificate is renewed in place after the configured fraction of its lifetime passes",
+			notBefore: time.Now().Add(-3 * time.Hour),
+			notAfter:  time.Now().Add(time.Hour),
+			renewalConfig: CertificateRenewalConfig{
+				Lifetime:        24 * time.Hour,
+				RenewalFraction: 0.5,
+			},
+			expectedResult: op.Updated,
+			expectRenewed:  true,
+			expectedEvent:  "Normal " + CertificateRenewedEventReason,
+		},
+		{
+			name:           "expiring certificate is reported when renewal is disabled",
+			notBefore:      time.Now().Add(-3 * time.Hour),
+			notAfter:       time.Now().Add(time.Hour),
+			renewalConfig:  CertificateRenewalConfig{},
+			expectedResult: op.Noop,
+			expectedEvent:  "Warning " + CertificateExpiringEventReason,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, operatorv1beta1.AddToScheme(scheme))
+
+			caSecret, err := generateCACert(caNN)
+			require.NoError(t, err)
+			existingCert, err := generateCertSignedByCA(caSecret, subject, tc.notBefore, tc.notAfter)
+			require.NoError(t, err)
+			existingSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret-1",
+					Namespace: "ns",
+					Labels:    k8sresources.GetManagedLabelForOwner(dp),
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "DataPlane",
+							APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+							Name:       dp.Name,
+							UID:        dp.UID,
+						},
+					},
+				},
+				Data: map[string][]byte{
+					"ca.crt":  caSecret.Data["tls.crt"],
+					"tls.crt": existingCert,
+					"tls.key": caSecret.Data["tls.key"],
+				},
+			}
+
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(dp, caSecret, existingSecret).
+				Build()
+			eventRecorder := record.NewFakeRecorder(10)
+
+			res, secret, err := EnsureCertificate(
+				ctx,
+				dp,
+				subject,
+				caNN,
+				[]certificatesv1.KeyUsage{
+					certificatesv1.UsageServerAuth,
+				},
+				KeyConfig{Type: x509.ECDSA},
+				IssuerConfig{},
+				tc.renewalConfig,
+				fakeClient,
+				eventRecorder,
+				nil,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedResult, res)
+			require.Equal(t, existingSecret.Name, secret.Name, "certificate should be kept in the same Secret")
+
+			cert, err := ParseSecretCertificate(secret)
+			require.NoError(t, err)
+			if tc.expectRenewed {
+				assert.NotEqual(t, existingCert, secret.Data["tls.crt"])
+				assert.Equal(t, subject, cert.Subject.CommonName)
+				assert.WithinDuration(t, time.Now().Add(tc.renewalConfig.Lifetime), cert.NotAfter, time.Minute)
+				assert.Greater(t, CertificateRenewalRequeueAfter(secret, tc.renewalConfig), 10*time.Hour)
+			} else {
+				assert.Equal(t, existingCert, secret.Data["tls.crt"])
+			}
+
+			if tc.expectedEvent == "" {
+				assert.Empty(t, eventRecorder.Events)
+				return
+			}
+			require.Len(t, eventRecorder.Events, 1)
+			assert.Contains(t, <-eventRecorder.Events, tc.expectedEvent)
+		})
+	}
+}
+
+func TestCertificateRenewalConfigValidate(t *testing.T) {
+	assert.NoError(t, CertificateRenewalConfig{}.Validate())
+	assert.NoError(t, CertificateRenewalConfig{Lifetime: 24 * time.Hour, RenewalFraction: 0.67}.Validate())
+	assert.Error(t, CertificateRenewalConfig{Lifetime: -time.Hour}.Validate())
+	assert.Error(t, CertificateRenewalConfig{Lifetime: 100 * 365 * 24 * time.Hour}.Validate())
+	assert.Error(t, CertificateRenewalConfig{RenewalFraction: 1}.Validate())
+	assert.Error(t, CertificateRenewalConfig{RenewalFraction: -0.1}.Validate())
+}
+
+func generateCertSignedByCA(ca *corev1.Secret, subject string, notBefore, notAfter time.Time) ([]byte, error) {
+	caCertBlock, _ := pem.Decode(ca.Data["tls.crt"])
+	caCert, err := x509.ParseCertificate(caCertBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	caKeyBlock, _ := pem.Decode(ca.Data["tls.key"])
+	caKey, err := x509.ParseECPrivateKey(caKeyBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+	template := x509.Certificate{
+		Subject:      pkix.Name{CommonName: subject},
+		SerialNumber: serial,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, caCert, caKey.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
 func generateCACert(nn types.NamespacedName) (*corev1.Secret, error) {
 	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
 	if err != nil {
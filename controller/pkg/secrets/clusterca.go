@@ -1,11 +1,15 @@
 package secrets
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"math"
 	"math/big"
 	"time"
@@ -14,18 +18,92 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+// DefaultClusterCALifetime is the default lifetime of the cluster CA certificate.
+const DefaultClusterCALifetime = time.Second * 315400000
+
+const (
+	// clusterCATrustBundleKey is the cluster CA Secret key holding the CA certificates
+	// trusted for the Admin API mTLS. When it's not set, only the certificate in
+	// 'tls.crt' is trusted.
+	clusterCATrustBundleKey = "ca.crt"
+	// clusterCANextCertKey and clusterCANextKeyKey are the cluster CA Secret keys holding
+	// the CA which replaces the current one when a rotation is in progress.
+	clusterCANextCertKey = "next-tls.crt"
+	clusterCANextKeyKey  = "next-tls.key"
+	// clusterCAPreviousCertKey is the cluster CA Secret key holding the certificate of
+	// the CA replaced during a rotation, until it's retired.
+	clusterCAPreviousCertKey = "previous-tls.crt"
+)
+
+// ClusterCARotationPhase is the phase of the cluster CA rotation.
+type ClusterCARotationPhase string
+
+const (
+	// ClusterCARotationPhaseNone indicates that no rotation is in progress.
+	ClusterCARotationPhaseNone ClusterCARotationPhase = ""
+	// ClusterCARotationPhaseRequested indicates that a rotation has been requested
+	// and a new CA has yet to be generated.
+	ClusterCARotationPhaseRequested ClusterCARotationPhase = "Requested"
+	// ClusterCARotationPhaseTrusting indicates that a new CA has been generated and
+	// is being added to the trust bundle of all components, while certificates
+	// are still signed by the old CA.
+	ClusterCARotationPhaseTrusting ClusterCARotationPhase = "Trusting"
+	// ClusterCARotationPhaseReissuing indicates that certificates are being re-issued
+	// by the new CA, while the old CA is still trusted.
+	ClusterCARotationPhaseReissuing ClusterCARotationPhase = "Reissuing"
+	// ClusterCARotationPhaseRetiring indicates that the old CA is being removed from
+	// the trust bundle of all components.
+	ClusterCARotationPhaseRetiring ClusterCARotationPhase = "Retiring"
 )
 
 // CreateClusterCACertificate creates a cluster CA certificate Secret.
-func CreateClusterCACertificate(ctx context.Context, cl client.Client, secretNN types.NamespacedName, secretLabels map[string]string, keyConfig KeyConfig) error {
-	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+// DefaultClusterCALifetime is used when lifetime is 0.
+func CreateClusterCACertificate(
+	ctx context.Context,
+	cl client.Client,
+	secretNN types.NamespacedName,
+	secretLabels map[string]string,
+	keyConfig KeyConfig,
+	lifetime time.Duration,
+) error {
+	crt, key, err := generateClusterCA(keyConfig, lifetime)
 	if err != nil {
 		return err
 	}
 
+	signedSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: secretNN.Namespace,
+			Name:      secretNN.Name,
+			Labels:    secretLabels,
+		},
+		Type: v1.SecretTypeTLS,
+		StringData: map[string]string{
+			"tls.crt": string(crt),
+			"tls.key": string(key),
+		},
+	}
+	return cl.Create(ctx, signedSecret)
+}
+
+// generateClusterCA generates a self-signed CA certificate and its private key, PEM encoded.
+func generateClusterCA(keyConfig KeyConfig, lifetime time.Duration) ([]byte, []byte, error) {
+	if lifetime == 0 {
+		lifetime = DefaultClusterCALifetime
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(math.MaxInt64))
+	if err != nil {
+		return nil, nil, err
+	}
+
 	priv, pemBlock, signatureAlgorithm, err := CreatePrivateKey(keyConfig)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	template := x509.Certificate{
@@ -37,32 +115,152 @@ func CreateClusterCACertificate(ctx context.Context, cl client.Client, secretNN
 		SerialNumber:          serial,
 		SignatureAlgorithm:    signatureAlgorithm,
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().Add(time.Second * 315400000),
+		NotAfter:              time.Now().Add(lifetime),
 		KeyUsage:              x509.KeyUsageCertSign + x509.KeyUsageKeyEncipherment + x509.KeyUsageDigitalSignature,
 		BasicConstraintsValid: true,
 		IsCA:                  true,
 	}
 
 	der, err := x509.CreateCertificate(rand.Reader, &template, &template, priv.Public(), priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	crt := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: der,
+	})
+	return crt, pem.EncodeToMemory(pemBlock), nil
+}
+
+// CATrustBundle returns the PEM encoded CA certificates which are trusted for the Admin API mTLS.
+// During a rotation of the cluster CA it contains both the old and the new CA certificates.
+func CATrustBundle(ca *v1.Secret) []byte {
+	if bundle := ca.Data[clusterCATrustBundleKey]; len(bundle) > 0 {
+		return bundle
+	}
+	return ca.Data["tls.crt"]
+}
+
+// CATrustBundleChecksum returns the hex encoded SHA256 checksum of the trust bundle.
+func CATrustBundleChecksum(bundle []byte) string {
+	sum := sha256.Sum256(bundle)
+	return hex.EncodeToString(sum[:])
+}
+
+// GetClusterCARotationPhase returns the phase of the rotation of the cluster CA stored in the Secret.
+func GetClusterCARotationPhase(ca *v1.Secret) ClusterCARotationPhase {
+	return ClusterCARotationPhase(ca.Annotations[consts.ClusterCARotationPhaseAnnotation])
+}
+
+// GetClusterCARotationPhaseStartTime returns the time the current phase of the rotation of
+// the cluster CA started at. It returns false if it's not recorded in the Secret.
+func GetClusterCARotationPhaseStartTime(ca *v1.Secret) (time.Time, bool) {
+	started, err := time.Parse(time.RFC3339, ca.Annotations[consts.ClusterCARotationPhaseStartedAnnotation])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return started, true
+}
+
+// SetClusterCARotationPhaseStartTime records the time the current phase of the rotation of
+// the cluster CA started at. The caller is responsible for updating the Secret.
+func SetClusterCARotationPhaseStartTime(ca *v1.Secret, started time.Time) {
+	if ca.Annotations == nil {
+		ca.Annotations = make(map[string]string)
+	}
+	ca.Annotations[consts.ClusterCARotationPhaseStartedAnnotation] = started.UTC().Format(time.RFC3339)
+}
+
+func setClusterCARotationPhase(ca *v1.Secret, phase ClusterCARotationPhase) {
+	delete(ca.Annotations, consts.ClusterCARotationPendingDataPlanesAnnotation)
+	if phase == ClusterCARotationPhaseNone {
+		delete(ca.Annotations, consts.ClusterCARotationPhaseAnnotation)
+		delete(ca.Annotations, consts.ClusterCARotationProgressAnnotation)
+		delete(ca.Annotations, consts.ClusterCARotationPhaseStartedAnnotation)
+		return
+	}
+	SetClusterCARotationPhaseStartTime(ca, time.Now())
+	ca.Annotations[consts.ClusterCARotationPhaseAnnotation] = string(phase)
+}
+
+// StartClusterCARotation generates a new CA and adds it to the trust bundle stored
+// in the cluster CA Secret. Certificates are still signed by the current CA until
+// PromoteClusterCA is called. It moves the rotation to the Trusting phase.
+// The caller is responsible for updating the Secret.
+func StartClusterCARotation(ca *v1.Secret, keyConfig KeyConfig, lifetime time.Duration) error {
+	crt, key, err := generateClusterCA(keyConfig, lifetime)
 	if err != nil {
 		return err
 	}
+	if ca.Data == nil {
+		ca.Data = make(map[string][]byte)
+	}
+	ca.Data[clusterCANextCertKey] = crt
+	ca.Data[clusterCANextKeyKey] = key
+	ca.Data[clusterCATrustBundleKey] = concatPEM(ca.Data["tls.crt"], crt)
+	setClusterCARotationPhase(ca, ClusterCARotationPhaseTrusting)
+	return nil
+}
 
-	signedSecret := &v1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: secretNN.Namespace,
-			Name:      secretNN.Name,
-			Labels:    secretLabels,
-		},
-		Type: v1.SecretTypeTLS,
-		StringData: map[string]string{
-			"tls.crt": string(pem.EncodeToMemory(&pem.Block{
-				Type:  "CERTIFICATE",
-				Bytes: der,
-			})),
+// PromoteClusterCA makes the CA generated by StartClusterCARotation sign the certificates,
+// keeping the old CA in the trust bundle. It moves the rotation to the Reissuing phase.
+// The caller is responsible for updating the Secret.
+func PromoteClusterCA(ca *v1.Secret) error {
+	crt, key := ca.Data[clusterCANextCertKey], ca.Data[clusterCANextKeyKey]
+	if len(crt) == 0 || len(key) == 0 {
+		return errors.New("no new CA certificate to promote in the cluster CA secret")
+	}
+	ca.Data[clusterCAPreviousCertKey] = ca.Data["tls.crt"]
+	ca.Data["tls.crt"] = crt
+	ca.Data["tls.key"] = key
+	delete(ca.Data, clusterCANextCertKey)
+	delete(ca.Data, clusterCANextKeyKey)
+	setClusterCARotationPhase(ca, ClusterCARotationPhaseReissuing)
+	return nil
+}
 
-			"tls.key": string(pem.EncodeToMemory(pemBlock)),
-		},
+// RetireClusterCA removes the old CA from the trust bundle stored in the cluster CA Secret.
+// It moves the rotation to the Retiring phase. The caller is responsible for updating the Secret.
+func RetireClusterCA(ca *v1.Secret) {
+	delete(ca.Data, clusterCAPreviousCertKey)
+	ca.Data[clusterCATrustBundleKey] = ca.Data["tls.crt"]
+	setClusterCARotationPhase(ca, ClusterCARotationPhaseRetiring)
+}
+
+// CompleteClusterCARotation marks the rotation of the cluster CA as completed.
+// The caller is responsible for updating the Secret.
+func CompleteClusterCARotation(ca *v1.Secret) {
+	setClusterCARotationPhase(ca, ClusterCARotationPhaseNone)
+}
+
+// IsCertificateSecretInSyncWithClusterCA returns true if the certificate in the TLS Secret
+// is signed by the CA currently signing certificates and the Secret holds the current trust bundle.
+func IsCertificateSecretInSyncWithClusterCA(secret *v1.Secret, ca *v1.Secret) bool {
+	if !bytes.Equal(secret.Data["ca.crt"], CATrustBundle(ca)) {
+		return false
 	}
-	return cl.Create(ctx, signedSecret)
+	cert, err := ParseSecretCertificate(secret)
+	if err != nil {
+		return false
+	}
+	return isSignedByCA(cert, ca)
+}
+
+// isSignedByCA returns true if the certificate is signed by the CA currently signing certificates.
+func isSignedByCA(cert *x509.Certificate, ca *v1.Secret) bool {
+	caCert, err := ParseSecretCertificate(ca)
+	if err != nil {
+		return false
+	}
+	return cert.CheckSignatureFrom(caCert) == nil
+}
+
+func concatPEM(certs ...[]byte) []byte {
+	var b bytes.Buffer
+	for _, c := range certs {
+		b.Write(bytes.TrimSpace(c))
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
 }
@@ -0,0 +1,237 @@
+package secrets
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/controller/pkg/op"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sresources "github.com/kong/kong-operator/pkg/utils/kubernetes/resources"
+)
+
+func TestClusterCARotation(t *testing.T) {
+	ca, err := generateCACert(types.NamespacedName{Name: "ca", Namespace: "ns"})
+	require.NoError(t, err)
+	oldCACert := ca.Data["tls.crt"]
+	leafCert, err := generateCertSignedByCA(ca, "leaf", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	leaf := &corev1.Secret{
+		Data: map[string][]byte{
+			"ca.crt":  CATrustBundle(ca),
+			"tls.crt": leafCert,
+		},
+	}
+
+	t.Log("before the rotation only the current CA is trusted")
+	assert.Equal(t, ClusterCARotationPhaseNone, GetClusterCARotationPhase(ca))
+	assert.Equal(t, oldCACert, CATrustBundle(ca))
+	assert.True(t, IsCertificateSecretInSyncWithClusterCA(leaf, ca))
+
+	t.Log("starting the rotation adds a new CA to the trust bundle and keeps signing with the old one")
+	require.NoError(t, StartClusterCARotation(ca, KeyConfig{Type: x509.ECDSA}, 24*time.Hour))
+	assert.Equal(t, ClusterCARotationPhaseTrusting, GetClusterCARotationPhase(ca))
+	_, ok := GetClusterCARotationPhaseStartTime(ca)
+	assert.True(t, ok, "phase start time should be recorded")
+	assert.Equal(t, oldCACert, ca.Data["tls.crt"])
+	bundleCerts := parsePEMCertificates(t, CATrustBundle(ca))
+	require.Len(t, bundleCerts, 2)
+	newCACert := ca.Data[clusterCANextCertKey]
+	assert.Equal(t, parsePEMCertificates(t, newCACert)[0].Raw, bundleCerts[1].Raw)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), bundleCerts[1].NotAfter, time.Minute)
+	assert.False(t, IsCertificateSecretInSyncWithClusterCA(leaf, ca), "leaf doesn't hold the new trust bundle")
+	leaf.Data["ca.crt"] = CATrustBundle(ca)
+	assert.True(t, IsCertificateSecretInSyncWithClusterCA(leaf, ca))
+
+	t.Log("promoting the new CA makes it sign certificates while the old one stays trusted")
+	require.NoError(t, PromoteClusterCA(ca))
+	assert.Equal(t, ClusterCARotationPhaseReissuing, GetClusterCARotationPhase(ca))
+	assert.Equal(t, newCACert, ca.Data["tls.crt"])
+	assert.Equal(t, oldCACert, ca.Data[clusterCAPreviousCertKey])
+	assert.NotContains(t, ca.Data, clusterCANextCertKey)
+	assert.NotContains(t, ca.Data, clusterCANextKeyKey)
+	assert.Len(t, parsePEMCertificates(t, CATrustBundle(ca)), 2)
+	assert.False(t, IsCertificateSecretInSyncWithClusterCA(leaf, ca), "leaf is signed by the old CA")
+	require.Error(t, PromoteClusterCA(ca), "there's no new CA to promote anymore")
+
+	t.Log("retiring the old CA removes it from the trust bundle")
+	RetireClusterCA(ca)
+	assert.Equal(t, ClusterCARotationPhaseRetiring, GetClusterCARotationPhase(ca))
+	assert.Equal(t, newCACert, CATrustBundle(ca))
+	assert.NotContains(t, ca.Data, clusterCAPreviousCertKey)
+
+	t.Log("completing the rotation removes its annotations")
+	ca.Annotations[consts.ClusterCARotationProgressAnnotation] = "1/1"
+	ca.Annotations[consts.ClusterCARotationPendingDataPlanesAnnotation] = "default/dp"
+	CompleteClusterCARotation(ca)
+	assert.Equal(t, ClusterCARotationPhaseNone, GetClusterCARotationPhase(ca))
+	assert.NotContains(t, ca.Annotations, consts.ClusterCARotationProgressAnnotation)
+	assert.NotContains(t, ca.Annotations, consts.ClusterCARotationPendingDataPlanesAnnotation)
+	_, ok = GetClusterCARotationPhaseStartTime(ca)
+	assert.False(t, ok, "phase start time should be removed")
+}
+
+func TestEnsureCertificateFollowsCARotation(t *testing.T) {
+	const subject = "test-subject"
+	var (
+		caNN = types.NamespacedName{Name: "test-mtls-secret", Namespace: "ns"}
+		dp   = &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp-1",
+				Namespace: "ns",
+				UID:       types.UID("1234"),
+			},
+		}
+	)
+
+	testCases := []struct {
+		name            string
+		rotate          func(t *testing.T, ca *corev1.Secret)
+		renewalConfig   CertificateRenewalConfig
+		expectedResult  op.Result
+		expectReissued  bool
+		expectNewBundle bool
+		expectedEvent   string
+	}{
+		{
+			name: "trust bundle is updated in place when a new CA is being trusted",
+			rotate: func(t *testing.T, ca *corev1.Secret) {
+				require.NoError(t, StartClusterCARotation(ca, KeyConfig{Type: x509.ECDSA}, 0))
+			},
+			renewalConfig:   CertificateRenewalConfig{FollowCARotation: true},
+			expectedResult:  op.Updated,
+			expectNewBundle: true,
+		},
+		{
+			name: "certificate signed by the old CA is re-issued when the new CA is promoted",
+			rotate: func(t *testing.T, ca *corev1.Secret) {
+				require.NoError(t, StartClusterCARotation(ca, KeyConfig{Type: x509.ECDSA}, 0))
+				require.NoError(t, PromoteClusterCA(ca))
+			},
+			renewalConfig:   CertificateRenewalConfig{FollowCARotation: true},
+			expectedResult:  op.Updated,
+			expectReissued:  true,
+			expectNewBundle: true,
+			expectedEvent:   "Normal " + CertificateReissuedEventReason,
+		},
+		{
+			name: "certificate doesn't follow the rotation when it's not configured to",
+			rotate: func(t *testing.T, ca *corev1.Secret) {
+				require.NoError(t, StartClusterCARotation(ca, KeyConfig{Type: x509.ECDSA}, 0))
+				require.NoError(t, PromoteClusterCA(ca))
+			},
+			renewalConfig:  CertificateRenewalConfig{},
+			expectedResult: op.Noop,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			require.NoError(t, operatorv1beta1.AddToScheme(scheme))
+
+			caSecret, err := generateCACert(caNN)
+			require.NoError(t, err)
+			existingCert, err := generateCertSignedByCA(caSecret, subject, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			existingSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret-1",
+					Namespace: "ns",
+					Labels:    k8sresources.GetManagedLabelForOwner(dp),
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							Kind:       "DataPlane",
+							APIVersion: operatorv1beta1.SchemeGroupVersion.String(),
+							Name:       dp.Name,
+							UID:        dp.UID,
+						},
+					},
+				},
+				Data: map[string][]byte{
+					"ca.crt":  caSecret.Data["tls.crt"],
+					"tls.crt": existingCert,
+					"tls.key": caSecret.Data["tls.key"],
+				},
+			}
+			tc.rotate(t, caSecret)
+
+			fakeClient := fakectrlruntimeclient.
+				NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(dp, caSecret, existingSecret).
+				Build()
+			eventRecorder := record.NewFakeRecorder(10)
+
+			res, secret, err := EnsureCertificate(
+				ctx,
+				dp,
+				subject,
+				caNN,
+				[]certificatesv1.KeyUsage{
+					certificatesv1.UsageServerAuth,
+				},
+				KeyConfig{Type: x509.ECDSA},
+				IssuerConfig{},
+				tc.renewalConfig,
+				fakeClient,
+				eventRecorder,
+				nil,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedResult, res)
+			require.Equal(t, existingSecret.Name, secret.Name, "certificate should be kept in the same Secret")
+
+			if tc.expectNewBundle {
+				assert.Equal(t, CATrustBundle(caSecret), secret.Data["ca.crt"])
+			} else {
+				assert.Equal(t, existingSecret.Data["ca.crt"], secret.Data["ca.crt"])
+			}
+			if tc.expectReissued {
+				assert.NotEqual(t, existingCert, secret.Data["tls.crt"])
+				assert.True(t, IsCertificateSecretInSyncWithClusterCA(secret, caSecret))
+			} else {
+				assert.Equal(t, existingCert, secret.Data["tls.crt"])
+			}
+
+			if tc.expectedEvent == "" {
+				assert.Empty(t, eventRecorder.Events)
+				return
+			}
+			require.Len(t, eventRecorder.Events, 1)
+			assert.Contains(t, <-eventRecorder.Events, tc.expectedEvent)
+		})
+	}
+}
+
+func parsePEMCertificates(t *testing.T, data []byte) []*x509.Certificate {
+	t.Helper()
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certs = append(certs, cert)
+	}
+}
@@ -0,0 +1,518 @@
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/cert-manager/cert-manager/pkg/apis/certmanager"
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certutils "github.com/kong/kong-operator/controller/dataplane/utils/certificates"
+	"github.com/kong/kong-operator/controller/pkg/op"
+	"github.com/kong/kong-operator/internal/metrics"
+	"github.com/kong/kong-operator/pkg/consts"
+	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
+)
+
+// IssuerKind is the kind of the issuer of the certificates managed by the operator.
+type IssuerKind string
+
+const (
+	// IssuerKindClusterCA issues certificates signed by the cluster CA stored in a Secret.
+	IssuerKindClusterCA IssuerKind = ""
+	// IssuerKindCertManagerIssuer issues certificates through a cert-manager Issuer
+	// in the namespace of the certificate's owner.
+	IssuerKindCertManagerIssuer IssuerKind = "Issuer"
+	// IssuerKindCertManagerClusterIssuer issues certificates through a cert-manager ClusterIssuer.
+	IssuerKindCertManagerClusterIssuer IssuerKind = "ClusterIssuer"
+	// IssuerKindCertificateSigningRequest issues certificates through Kubernetes
+	// CertificateSigningRequests handled by an external signer.
+	IssuerKindCertificateSigningRequest IssuerKind = "CertificateSigningRequest"
+)
+
+const (
+	// CertificateNotReadyRequeueAfter is the duration after which the owner of a certificate
+	// which is being issued by an external issuer should be reconciled again.
+	CertificateNotReadyRequeueAfter = 5 * time.Second
+
+	// CertificateIssuanceFailedEventReason is the reason of the event emitted when
+	// an external issuer refuses to issue a certificate.
+	CertificateIssuanceFailedEventReason = "CertificateIssuanceFailed"
+
+	// pendingKeyKey is the Secret key holding the private key of a certificate requested
+	// through a CertificateSigningRequest which hasn't been issued yet.
+	pendingKeyKey = "pending-tls.key"
+)
+
+var (
+	// ErrCertificateNotReady is returned by EnsureCertificate when the certificate is being
+	// issued by an external issuer. Its owner should be reconciled again after CertificateNotReadyRequeueAfter.
+	ErrCertificateNotReady = errors.New("certificate is not issued yet")
+	// ErrCertificateIssuanceFailed is returned by EnsureCertificate when the external issuer refused
+	// to issue the certificate. It's not requested again until the request or its Secret changes,
+	// so its owner shouldn't be requeued.
+	ErrCertificateIssuanceFailed = errors.New("certificate issuance failed")
+)
+
+// IssuerConfig configures the issuer of the certificates managed by the operator.
+// The zero value issues certificates signed by the cluster CA.
+type IssuerConfig struct {
+	// Kind is the kind of the issuer.
+	Kind IssuerKind
+
+	// Name is the name of the cert-manager Issuer or ClusterIssuer, or the signer name
+	// set in CertificateSigningRequests.
+	Name string
+}
+
+// Validate validates the configuration.
+func (c IssuerConfig) Validate() error {
+	switch c.Kind {
+	case IssuerKindClusterCA:
+		if c.Name != "" {
+			return fmt.Errorf("issuer name %q can't be set without an issuer kind", c.Name)
+		}
+		return nil
+	case IssuerKindCertManagerIssuer, IssuerKindCertManagerClusterIssuer:
+		if c.Name == "" {
+			return fmt.Errorf("issuer name is required for issuer kind %s", c.Kind)
+		}
+		return nil
+	case IssuerKindCertificateSigningRequest:
+		// Signer names are qualified names in the form of <domain>/<path>.
+		if !strings.Contains(c.Name, "/") {
+			return fmt.Errorf("signer name must be in the <domain>/<path> form for issuer kind %s, got %q", c.Kind, c.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported issuer kind %q (possible values: %s, %s, %s)", c.Kind,
+			IssuerKindCertManagerIssuer, IssuerKindCertManagerClusterIssuer, IssuerKindCertificateSigningRequest,
+		)
+	}
+}
+
+// IsExternal returns true if certificates are issued by an external issuer
+// instead of the cluster CA.
+func (c IssuerConfig) IsExternal() bool {
+	return c.Kind != IssuerKindClusterCA
+}
+
+// -----------------------------------------------------------------------------
+// cert-manager
+// -----------------------------------------------------------------------------
+
+// ensureCertManagerCertificate ensures that a cert-manager Certificate exists for subject and
+// returns the Secret issued for it once the Certificate is ready. The Secret is labelled
+// like the provided generated Secret.
+func ensureCertManagerCertificate(
+	ctx context.Context,
+	owner client.Object,
+	subject string,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	issuer IssuerConfig,
+	cl client.Client,
+	eventRecorder record.EventRecorder,
+	generatedSecret *corev1.Secret,
+) (op.Result, *corev1.Secret, error) {
+	// The owner's UID is also recorded in a label, as for the Konnect client certificates,
+	// so that the Secrets created by cert-manager can be matched with their owner.
+	labels := maps.Clone(generatedSecret.Labels)
+	labels[certutils.ManagerUIDLabel] = string(owner.GetUID())
+
+	certs, err := certutils.ListCMCertificatesForOwner(ctx, cl, owner.GetNamespace(), owner.GetUID(), client.MatchingLabels(labels))
+	if err != nil {
+		return op.Noop, nil, fmt.Errorf("failed listing Certificates for %T %s/%s: %w", owner, owner.GetNamespace(), owner.GetName(), err)
+	}
+	if len(certs) > 1 {
+		if err := certutils.ReduceCMCertificates(ctx, cl, certs, certutils.FilterCMCertificates); err != nil {
+			return op.Noop, nil, err
+		}
+		return op.Noop, nil, errors.New("number of certificates reduced")
+	}
+
+	generated := generateCMCertificate(owner, subject, usages, keyConfig, renewalConfig, issuer, generatedSecret, labels)
+	if len(certs) == 0 {
+		if err := cl.Create(ctx, generated); err != nil {
+			return op.Noop, nil, fmt.Errorf("failed creating Certificate %s: %w", generated.Name, err)
+		}
+		return op.Noop, nil, ErrCertificateNotReady
+	}
+
+	existing := &certs[0]
+	var updated bool
+	updated, existing.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existing.ObjectMeta, generated.ObjectMeta)
+	if !equality.Semantic.DeepEqual(existing.Spec, generated.Spec) {
+		existing.Spec = generated.Spec
+		updated = true
+	}
+	if updated {
+		if err := cl.Update(ctx, existing); err != nil {
+			return op.Noop, nil, fmt.Errorf("failed updating Certificate %s: %w", existing.Name, err)
+		}
+		return op.Noop, nil, ErrCertificateNotReady
+	}
+	if !isCMCertificateReady(existing) {
+		return op.Noop, nil, ErrCertificateNotReady
+	}
+
+	var secret corev1.Secret
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: existing.Namespace, Name: existing.Spec.SecretName}, &secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return op.Noop, nil, ErrCertificateNotReady
+		}
+		return op.Noop, nil, err
+	}
+	if len(secret.Data["ca.crt"]) == 0 {
+		return op.Noop, nil, fmt.Errorf("%s %s didn't provide the CA certificate in Secret %s", issuer.Kind, issuer.Name, secret.Name)
+	}
+	if cert, err := ParseSecretCertificate(&secret); err == nil {
+		observeCertificate(owner, &secret, cert, renewalConfig, eventRecorder, time.Now())
+	}
+	// Make the Secret garbage collected together with its owner, like the ones issued by the cluster CA.
+	if !k8sutils.IsOwnedByRefUID(&secret, owner.GetUID()) {
+		k8sutils.SetOwnerForObject(&secret, owner)
+		if err := cl.Update(ctx, &secret); err != nil {
+			return op.Noop, nil, fmt.Errorf("failed updating secret %s: %w", secret.Name, err)
+		}
+		return op.Updated, &secret, nil
+	}
+	return op.Noop, &secret, nil
+}
+
+// generateCMCertificate generates a cert-manager Certificate for subject issued by the configured issuer.
+func generateCMCertificate(
+	owner client.Object,
+	subject string,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	issuer IssuerConfig,
+	generatedSecret *corev1.Secret,
+	labels map[string]string,
+) *certmanagerv1.Certificate {
+	// The Secret's name must be known upfront, derive it from the owner's UID so that
+	// it's stable and unique.
+	uidHash := sha256.Sum256([]byte(owner.GetUID()))
+	name := fmt.Sprintf("%s%x", generatedSecret.GenerateName, uidHash[:5])
+
+	cmUsages := make([]certmanagerv1.KeyUsage, 0, len(usages))
+	for _, u := range usages {
+		cmUsages = append(cmUsages, certmanagerv1.KeyUsage(u))
+	}
+
+	privateKey := &certmanagerv1.CertificatePrivateKey{
+		RotationPolicy: certmanagerv1.RotationPolicyAlways,
+		Algorithm:      certmanagerv1.ECDSAKeyAlgorithm,
+	}
+	if keyConfig.Type == x509.RSA {
+		privateKey.Algorithm = certmanagerv1.RSAKeyAlgorithm
+		privateKey.Size = keyConfig.Size
+	}
+
+	lifetime := renewalConfig.lifetime()
+	cert := &certmanagerv1.Certificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   owner.GetNamespace(),
+			Labels:      labels,
+			Annotations: generatedSecret.Annotations,
+		},
+		Spec: certmanagerv1.CertificateSpec{
+			SecretName: name,
+			SecretTemplate: &certmanagerv1.CertificateSecretTemplate{
+				Labels:      labels,
+				Annotations: generatedSecret.Annotations,
+			},
+			CommonName: subject,
+			DNSNames:   []string{subject},
+			Duration:   &metav1.Duration{Duration: lifetime},
+			Usages:     cmUsages,
+			PrivateKey: privateKey,
+			IssuerRef: cmmeta.ObjectReference{
+				Name:  issuer.Name,
+				Kind:  string(issuer.Kind),
+				Group: certmanager.GroupName,
+			},
+		},
+	}
+	// cert-manager renews certificates after 2/3 of their lifetime by default.
+	if renewalConfig.RenewalFraction > 0 {
+		cert.Spec.RenewBefore = &metav1.Duration{
+			Duration: time.Duration(float64(lifetime) * (1 - renewalConfig.RenewalFraction)),
+		}
+	}
+	k8sutils.SetOwnerForObject(cert, owner)
+
+	return cert
+}
+
+func isCMCertificateReady(cert *certmanagerv1.Certificate) bool {
+	for _, c := range cert.Status.Conditions {
+		if c.Type == certmanagerv1.CertificateConditionReady {
+			return c.Status == cmmeta.ConditionTrue && c.ObservedGeneration >= cert.Generation
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// CertificateSigningRequest
+// -----------------------------------------------------------------------------
+
+// ensureCSRIssuedCertificate ensures that the existing Secret (or a new one, when it's nil) holds
+// a certificate for subject issued through a CertificateSigningRequest handled by an external signer.
+// The private key is generated by the operator and kept in the Secret while the request is pending.
+// The trust bundle stored in the Secret is taken from the cluster CA Secret, which doesn't need to
+// hold the CA's private key.
+func ensureCSRIssuedCertificate(
+	ctx context.Context,
+	owner client.Object,
+	subject string,
+	mtlsCASecretNN types.NamespacedName,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	issuer IssuerConfig,
+	cl client.Client,
+	eventRecorder record.EventRecorder,
+	generatedSecret *corev1.Secret,
+	existingSecret *corev1.Secret,
+) (op.Result, *corev1.Secret, error) {
+	if existingSecret == nil {
+		// TLS Secrets must hold both keys, they're left empty until the certificate is issued.
+		generatedSecret.Data = map[string][]byte{
+			"tls.crt": nil,
+			"tls.key": nil,
+		}
+		if err := cl.Create(ctx, generatedSecret); err != nil {
+			return op.Noop, nil, err
+		}
+		if err := requestCertificate(ctx, cl, generatedSecret, subject, usages, keyConfig, renewalConfig, issuer); err != nil {
+			return op.Noop, nil, err
+		}
+		return op.Noop, nil, ErrCertificateNotReady
+	}
+
+	cert, certErr := ParseSecretCertificate(existingSecret)
+	hasValidCert := certErr == nil && cert.Subject.CommonName == subject && time.Now().Before(cert.NotAfter)
+	requestHash := certificateRequestHash(subject, usages, keyConfig, renewalConfig, issuer)
+
+	if csrName, ok := existingSecret.Annotations[consts.CertificateSigningRequestAnnotation]; ok {
+		issued, err := collectIssuedCertificate(ctx, cl, eventRecorder, owner, mtlsCASecretNN, existingSecret, csrName, requestHash)
+		switch {
+		case err != nil:
+			return op.Noop, nil, err
+		case issued:
+			if cert, err := ParseSecretCertificate(existingSecret); err == nil {
+				metrics.RecordCertificateExpiration(existingSecret.Namespace, existingSecret.Name, cert.NotAfter)
+			}
+			if hasValidCert && eventRecorder != nil {
+				eventRecorder.Eventf(owner, corev1.EventTypeNormal, CertificateRenewedEventReason,
+					"Renewed certificate in Secret %s which was valid until %s",
+					existingSecret.Name, cert.NotAfter.UTC().Format(time.RFC3339),
+				)
+			}
+			return op.Updated, existingSecret, nil
+		case hasValidCert:
+			// Keep using the current certificate until the renewed one is issued.
+			return op.Noop, existingSecret, nil
+		default:
+			return op.Noop, nil, ErrCertificateNotReady
+		}
+	}
+
+	// Don't flood the signer with a request it already refused: keep using the current
+	// certificate, if any, until the request changes or the annotation is removed.
+	requestFailed := existingSecret.Annotations[consts.CertificateSigningRequestFailedAnnotation] == requestHash
+	if requestFailed && !hasValidCert {
+		return op.Noop, nil, fmt.Errorf("%w: CertificateSigningRequest for Secret %s was refused, remove its %s annotation to request it again",
+			ErrCertificateIssuanceFailed, existingSecret.Name, consts.CertificateSigningRequestFailedAnnotation,
+		)
+	}
+	if !requestFailed && (!hasValidCert || renewalConfig.needsRenewal(cert, time.Now())) {
+		if err := requestCertificate(ctx, cl, existingSecret, subject, usages, keyConfig, renewalConfig, issuer); err != nil {
+			return op.Noop, nil, err
+		}
+		if hasValidCert {
+			return op.Noop, existingSecret, nil
+		}
+		return op.Noop, nil, ErrCertificateNotReady
+	}
+	observeCertificate(owner, existingSecret, cert, renewalConfig, eventRecorder, time.Now())
+
+	var updated bool
+	updated, existingSecret.ObjectMeta = k8sutils.EnsureObjectMetaIsUpdated(existingSecret.ObjectMeta, generatedSecret.ObjectMeta)
+	bundle, err := getTrustBundle(ctx, cl, mtlsCASecretNN)
+	if err != nil {
+		return op.Noop, nil, err
+	}
+	if string(existingSecret.Data["ca.crt"]) != string(bundle) {
+		existingSecret.Data["ca.crt"] = bundle
+		updated = true
+	}
+	if updated {
+		if err := cl.Update(ctx, existingSecret); err != nil {
+			return op.Noop, existingSecret, fmt.Errorf("failed updating secret %s: %w", existingSecret.Name, err)
+		}
+		return op.Updated, existingSecret, nil
+	}
+	return op.Noop, existingSecret, nil
+}
+
+// requestCertificate generates a private key, stores it in the Secret as pending and creates
+// a CertificateSigningRequest for it. The name of the request is recorded in the Secret's annotations.
+func requestCertificate(
+	ctx context.Context,
+	cl client.Client,
+	secret *corev1.Secret,
+	subject string,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	issuer IssuerConfig,
+) error {
+	request, key, err := createCertificateRequest(subject, keyConfig)
+	if err != nil {
+		return err
+	}
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: k8sutils.TrimGenerateName(fmt.Sprintf("%s-%s-", secret.Namespace, secret.Name)),
+			Labels: map[string]string{
+				consts.GatewayOperatorManagedByLabel:          secret.Labels[consts.GatewayOperatorManagedByLabel],
+				consts.GatewayOperatorManagedByNamespaceLabel: secret.Namespace,
+			},
+		},
+		Spec: newCertificateSigningRequestSpec(request, issuer.Name, usages, renewalConfig),
+	}
+	if err := cl.Create(ctx, csr); err != nil {
+		return fmt.Errorf("failed creating CertificateSigningRequest for secret %s: %w", secret.Name, err)
+	}
+
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+	secret.Data[pendingKeyKey] = key
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[consts.CertificateSigningRequestAnnotation] = csr.Name
+	delete(secret.Annotations, consts.CertificateSigningRequestFailedAnnotation)
+	if err := cl.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed updating secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+// collectIssuedCertificate moves the certificate issued for the CertificateSigningRequest to the Secret,
+// together with its pending private key. It returns false if the certificate hasn't been issued yet.
+// Denied and failed requests are deleted and the Secret is annotated with requestHash, so that the
+// request isn't created again until it changes. ErrCertificateIssuanceFailed is returned in that case.
+func collectIssuedCertificate(
+	ctx context.Context,
+	cl client.Client,
+	eventRecorder record.EventRecorder,
+	owner client.Object,
+	mtlsCASecretNN types.NamespacedName,
+	secret *corev1.Secret,
+	csrName string,
+	requestHash string,
+) (bool, error) {
+	clearPending := func() error {
+		delete(secret.Data, pendingKeyKey)
+		delete(secret.Annotations, consts.CertificateSigningRequestAnnotation)
+		return cl.Update(ctx, secret)
+	}
+
+	var csr certificatesv1.CertificateSigningRequest
+	if err := cl.Get(ctx, types.NamespacedName{Name: csrName}, &csr); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, clearPending()
+		}
+		return false, err
+	}
+
+	for _, c := range csr.Status.Conditions {
+		if (c.Type == certificatesv1.CertificateDenied || c.Type == certificatesv1.CertificateFailed) &&
+			c.Status == corev1.ConditionTrue {
+			if eventRecorder != nil {
+				eventRecorder.Eventf(owner, corev1.EventTypeWarning, CertificateIssuanceFailedEventReason,
+					"CertificateSigningRequest %s for Secret %s: %s: %s", csr.Name, secret.Name, c.Reason, c.Message,
+				)
+			}
+			if err := cl.Delete(ctx, &csr); client.IgnoreNotFound(err) != nil {
+				return false, err
+			}
+			secret.Annotations[consts.CertificateSigningRequestFailedAnnotation] = requestHash
+			if err := clearPending(); err != nil {
+				return false, err
+			}
+			return false, fmt.Errorf("%w: CertificateSigningRequest %s was %s: %s",
+				ErrCertificateIssuanceFailed, csr.Name, strings.ToLower(string(c.Type)), c.Message,
+			)
+		}
+	}
+	if len(csr.Status.Certificate) == 0 {
+		return false, nil
+	}
+
+	bundle, err := getTrustBundle(ctx, cl, mtlsCASecretNN)
+	if err != nil {
+		return false, err
+	}
+	secret.Data["ca.crt"] = bundle
+	secret.Data["tls.crt"] = csr.Status.Certificate
+	secret.Data["tls.key"] = secret.Data[pendingKeyKey]
+	if err := clearPending(); err != nil {
+		return false, fmt.Errorf("failed updating secret %s: %w", secret.Name, err)
+	}
+	if err := cl.Delete(ctx, &csr); client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// certificateRequestHash returns a hash of the parameters of a CertificateSigningRequest,
+// identifying the request regardless of its generated private key.
+func certificateRequestHash(
+	subject string,
+	usages []certificatesv1.KeyUsage,
+	keyConfig KeyConfig,
+	renewalConfig CertificateRenewalConfig,
+	issuer IssuerConfig,
+) string {
+	h := sha256.Sum256(fmt.Appendf(nil, "%s|%v|%s|%d|%s|%s",
+		subject, usages, keyConfig.Type, keyConfig.Size, issuer.Name, renewalConfig.lifetime(),
+	))
+	return fmt.Sprintf("%x", h[:8])
+}
+
+// getTrustBundle returns the trust bundle stored in the cluster CA Secret.
+func getTrustBundle(ctx context.Context, cl client.Client, mtlsCASecretNN types.NamespacedName) ([]byte, error) {
+	var ca corev1.Secret
+	if err := cl.Get(ctx, mtlsCASecretNN, &ca); err != nil {
+		return nil, fmt.Errorf("failed getting CA secret %s with the trusted CA certificates: %w", mtlsCASecretNN, err)
+	}
+	bundle := CATrustBundle(&ca)
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("CA secret %s doesn't contain CA certificates in 'ca.crt' nor 'tls.crt'", mtlsCASecretNN)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,344 @@
+package secrets
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	certmanagerv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
+	cmmeta "github.com/cert-manager/cert-manager/pkg/apis/meta/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakectrlruntimeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	certutils "github.com/kong/kong-operator/controller/dataplane/utils/certificates"
+	"github.com/kong/kong-operator/controller/pkg/op"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestIssuerConfigValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		issuer  IssuerConfig
+		wantErr bool
+	}{
+		{
+			name:   "cluster CA",
+			issuer: IssuerConfig{},
+		},
+		{
+			name:    "cluster CA with a name",
+			issuer:  IssuerConfig{Name: "issuer"},
+			wantErr: true,
+		},
+		{
+			name:   "cert-manager Issuer",
+			issuer: IssuerConfig{Kind: IssuerKindCertManagerIssuer, Name: "issuer"},
+		},
+		{
+			name:    "cert-manager ClusterIssuer without a name",
+			issuer:  IssuerConfig{Kind: IssuerKindCertManagerClusterIssuer},
+			wantErr: true,
+		},
+		{
+			name:   "CertificateSigningRequest",
+			issuer: IssuerConfig{Kind: IssuerKindCertificateSigningRequest, Name: "example.com/signer"},
+		},
+		{
+			name:    "CertificateSigningRequest with an unqualified signer name",
+			issuer:  IssuerConfig{Kind: IssuerKindCertificateSigningRequest, Name: "signer"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported kind",
+			issuer:  IssuerConfig{Kind: "Vault", Name: "vault"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.issuer.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEnsureCertificateCertManagerIssuer(t *testing.T) {
+	ctx := t.Context()
+	const subject = "test-subject"
+	var (
+		caNN   = types.NamespacedName{Name: "test-mtls-secret", Namespace: "ns"}
+		issuer = IssuerConfig{Kind: IssuerKindCertManagerClusterIssuer, Name: "kong-issuer"}
+		dp     = &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp-1",
+				Namespace: "ns",
+				UID:       types.UID("1234"),
+			},
+		}
+	)
+	fakeClient := newIssuerTestClient(t, dp)
+	ensure := func() (op.Result, *corev1.Secret, error) {
+		return EnsureCertificate(ctx, dp, subject, caNN,
+			[]certificatesv1.KeyUsage{certificatesv1.UsageServerAuth},
+			KeyConfig{Type: x509.ECDSA},
+			issuer,
+			CertificateRenewalConfig{Lifetime: 24 * time.Hour, RenewalFraction: 0.5},
+			fakeClient, nil, nil,
+		)
+	}
+
+	t.Log("Certificate is created and the certificate isn't ready until it's issued")
+	_, _, err := ensure()
+	require.ErrorIs(t, err, ErrCertificateNotReady)
+	certs, err := certutils.ListCMCertificatesForOwner(ctx, fakeClient, dp.Namespace, dp.UID)
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	cert := certs[0]
+	assert.Equal(t, cmmeta.ObjectReference{Name: "kong-issuer", Kind: "ClusterIssuer", Group: "cert-manager.io"}, cert.Spec.IssuerRef)
+	assert.Equal(t, subject, cert.Spec.CommonName)
+	assert.Equal(t, []certmanagerv1.KeyUsage{certmanagerv1.UsageServerAuth}, cert.Spec.Usages)
+	assert.Equal(t, 24*time.Hour, cert.Spec.Duration.Duration)
+	assert.Equal(t, 12*time.Hour, cert.Spec.RenewBefore.Duration)
+	assert.Equal(t, certmanagerv1.ECDSAKeyAlgorithm, cert.Spec.PrivateKey.Algorithm)
+	assert.Equal(t, string(dp.UID), cert.Spec.SecretTemplate.Labels[certutils.ManagerUIDLabel])
+
+	_, _, err = ensure()
+	require.ErrorIs(t, err, ErrCertificateNotReady)
+
+	t.Log("Secret issued by cert-manager is returned once the Certificate is ready")
+	issuerCA, err := generateCACert(types.NamespacedName{Name: "issuer-ca", Namespace: "cert-manager"})
+	require.NoError(t, err)
+	issued, err := generateCertSignedByCA(issuerCA, subject, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, fakeClient.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cert.Spec.SecretName,
+			Namespace: cert.Namespace,
+			Labels:    cert.Spec.SecretTemplate.Labels,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			"ca.crt":  issuerCA.Data["tls.crt"],
+			"tls.crt": issued,
+			"tls.key": issuerCA.Data["tls.key"],
+		},
+	}))
+	cert.Status.Conditions = []certmanagerv1.CertificateCondition{
+		{Type: certmanagerv1.CertificateConditionReady, Status: cmmeta.ConditionTrue},
+	}
+	require.NoError(t, fakeClient.Update(ctx, &cert))
+
+	res, secret, err := ensure()
+	require.NoError(t, err)
+	assert.Equal(t, op.Updated, res, "Secret should be owned by the DataPlane")
+	assert.Equal(t, cert.Spec.SecretName, secret.Name)
+	assert.Equal(t, issuerCA.Data["tls.crt"], secret.Data["ca.crt"])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, dp.UID, secret.OwnerReferences[0].UID)
+
+	res, _, err = ensure()
+	require.NoError(t, err)
+	assert.Equal(t, op.Noop, res)
+}
+
+func TestEnsureCertificateCertificateSigningRequest(t *testing.T) {
+	const subject = "test-subject"
+	var (
+		caNN   = types.NamespacedName{Name: "test-mtls-secret", Namespace: "ns"}
+		issuer = IssuerConfig{Kind: IssuerKindCertificateSigningRequest, Name: "example.com/kong"}
+		dp     = &operatorv1beta1.DataPlane{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dp-1",
+				Namespace: "ns",
+				UID:       types.UID("1234"),
+			},
+		}
+	)
+	// The signer's CA is the only one holding the private key, the cluster CA Secret
+	// only holds the trusted CA certificate.
+	signerCA, err := generateCACert(caNN)
+	require.NoError(t, err)
+	trustedCA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: caNN.Name, Namespace: caNN.Namespace},
+		Data: map[string][]byte{
+			"ca.crt": signerCA.Data["tls.crt"],
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		sign  func(t *testing.T, csr *certificatesv1.CertificateSigningRequest)
+		check func(t *testing.T, res op.Result, secret *corev1.Secret, err error, events []string)
+	}{
+		{
+			name: "certificate is stored in the Secret once it's issued",
+			sign: func(t *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+				issued, err := signCertificate(*csr, signerCA)
+				require.NoError(t, err)
+				csr.Status.Certificate = issued
+			},
+			check: func(t *testing.T, res op.Result, secret *corev1.Secret, err error, events []string) {
+				require.NoError(t, err)
+				assert.Equal(t, op.Updated, res)
+				assert.Equal(t, signerCA.Data["tls.crt"], secret.Data["ca.crt"])
+				assert.NotContains(t, secret.Data, pendingKeyKey)
+				assert.NotContains(t, secret.Annotations, consts.CertificateSigningRequestAnnotation)
+				cert, err := ParseSecretCertificate(secret)
+				require.NoError(t, err)
+				assert.Equal(t, subject, cert.Subject.CommonName)
+				assert.True(t, isSignedByCA(cert, signerCA))
+				assert.Empty(t, events)
+			},
+		},
+		{
+			name: "denied request is reported and removed",
+			sign: func(t *testing.T, csr *certificatesv1.CertificateSigningRequest) {
+				csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+					{
+						Type:    certificatesv1.CertificateDenied,
+						Status:  corev1.ConditionTrue,
+						Reason:  "PolicyViolation",
+						Message: "subject not allowed",
+					},
+				}
+			},
+			check: func(t *testing.T, _ op.Result, secret *corev1.Secret, err error, events []string) {
+				require.ErrorIs(t, err, ErrCertificateIssuanceFailed)
+				assert.Nil(t, secret)
+				require.Len(t, events, 1)
+				assert.Contains(t, events[0], "Warning "+CertificateIssuanceFailedEventReason)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := t.Context()
+			fakeClient := newIssuerTestClient(t, dp, trustedCA)
+			eventRecorder := record.NewFakeRecorder(10)
+			ensure := func() (op.Result, *corev1.Secret, error) {
+				return EnsureCertificate(ctx, dp, subject, caNN,
+					[]certificatesv1.KeyUsage{certificatesv1.UsageServerAuth},
+					KeyConfig{Type: x509.ECDSA},
+					issuer,
+					CertificateRenewalConfig{},
+					fakeClient, eventRecorder, nil,
+				)
+			}
+
+			_, _, err := ensure()
+			require.ErrorIs(t, err, ErrCertificateNotReady)
+			var secrets corev1.SecretList
+			require.NoError(t, fakeClient.List(ctx, &secrets, client.InNamespace(dp.Namespace), client.HasLabels{consts.GatewayOperatorManagedByLabel}))
+			require.Len(t, secrets.Items, 1)
+			pending := secrets.Items[0]
+			assert.NotEmpty(t, pending.Data[pendingKeyKey])
+			csrName := pending.Annotations[consts.CertificateSigningRequestAnnotation]
+			require.NotEmpty(t, csrName)
+
+			var csr certificatesv1.CertificateSigningRequest
+			require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: csrName}, &csr))
+			assert.Equal(t, issuer.Name, csr.Spec.SignerName)
+			assert.Equal(t, []certificatesv1.KeyUsage{certificatesv1.UsageServerAuth}, csr.Spec.Usages)
+
+			_, _, err = ensure()
+			require.ErrorIs(t, err, ErrCertificateNotReady, "certificate isn't ready until it's issued")
+
+			tc.sign(t, &csr)
+			require.NoError(t, fakeClient.Update(ctx, &csr))
+
+			res, secret, err := ensure()
+			var events []string
+			for len(eventRecorder.Events) > 0 {
+				events = append(events, <-eventRecorder.Events)
+			}
+			tc.check(t, res, secret, err, events)
+
+			err = fakeClient.Get(ctx, types.NamespacedName{Name: csrName}, &csr)
+			assert.True(t, k8serrors.IsNotFound(err), "CertificateSigningRequest should be deleted")
+		})
+	}
+}
+
+func TestEnsureCertificateCertificateSigningRequestNotRetriedAfterDenial(t *testing.T) {
+	ctx := t.Context()
+	caNN := types.NamespacedName{Name: "test-mtls-secret", Namespace: "ns"}
+	issuer := IssuerConfig{Kind: IssuerKindCertificateSigningRequest, Name: "example.com/kong"}
+	dp := &operatorv1beta1.DataPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dp-1",
+			Namespace: "ns",
+			UID:       types.UID("1234"),
+		},
+	}
+	trustedCA, err := generateCACert(caNN)
+	require.NoError(t, err)
+	fakeClient := newIssuerTestClient(t, dp, trustedCA)
+	ensure := func(subject string) error {
+		_, _, err := EnsureCertificate(ctx, dp, subject, caNN,
+			[]certificatesv1.KeyUsage{certificatesv1.UsageServerAuth},
+			KeyConfig{Type: x509.ECDSA},
+			issuer,
+			CertificateRenewalConfig{},
+			fakeClient, nil, nil,
+		)
+		return err
+	}
+	listCSRs := func(t *testing.T) []certificatesv1.CertificateSigningRequest {
+		t.Helper()
+		var csrs certificatesv1.CertificateSigningRequestList
+		require.NoError(t, fakeClient.List(ctx, &csrs))
+		return csrs.Items
+	}
+
+	require.ErrorIs(t, ensure("test-subject"), ErrCertificateNotReady)
+	csrs := listCSRs(t)
+	require.Len(t, csrs, 1)
+	csrs[0].Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+		{
+			Type:   certificatesv1.CertificateDenied,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	require.NoError(t, fakeClient.Update(ctx, &csrs[0]))
+	require.ErrorIs(t, ensure("test-subject"), ErrCertificateIssuanceFailed)
+
+	t.Log("denied request is not created again")
+	require.ErrorIs(t, ensure("test-subject"), ErrCertificateIssuanceFailed)
+	assert.Empty(t, listCSRs(t))
+
+	t.Log("changed request is created")
+	require.ErrorIs(t, ensure("other-subject"), ErrCertificateNotReady)
+	assert.Len(t, listCSRs(t), 1)
+}
+
+func newIssuerTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, certificatesv1.AddToScheme(scheme))
+	require.NoError(t, certmanagerv1.AddToScheme(scheme))
+	require.NoError(t, operatorv1beta1.AddToScheme(scheme))
+
+	return fakectrlruntimeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		Build()
+}
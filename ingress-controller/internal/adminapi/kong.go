@@ -155,6 +155,7 @@ func makeHTTPClient(opts managercfg.AdminAPIClientConfig, kongAdminToken string)
 		tlsConfig.RootCAs = certPool
 	}
 
+	tlsConfig.GetClientCertificate = opts.TLSClient.GetCertificate
 	clientCertificate, err := tlsutil.ExtractClientCertificates(
 		[]byte(opts.TLSClient.Cert), opts.TLSClient.CertFile, []byte(opts.TLSClient.Key), opts.TLSClient.KeyFile,
 	)
@@ -107,6 +107,40 @@ func TestAdminAPIClientWithTLSOptsAndFilePaths(t *testing.T) {
 	})
 }
 
+func TestAdminAPIClientWithTLSClientCertificateGetter(t *testing.T) {
+	const hostname = "localhost"
+	cert, key := certificate.MustGenerateCertPEMFormat(certificate.WithDNSNames(hostname))
+	caCert := cert
+	clientCert, err := tls.X509KeyPair(cert, key)
+	require.NoError(t, err)
+
+	var calls int
+	opts := managercfg.AdminAPIClientConfig{
+		TLSServerName: hostname,
+		CACert:        string(caCert),
+		TLSClient: managercfg.TLSClientConfig{
+			GetCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				calls++
+				return &clientCert, nil
+			},
+		},
+	}
+
+	t.Run("with mutually exclusive options set, it should fail", func(t *testing.T) {
+		optsConflict := opts
+		optsConflict.TLSSkipVerify = true
+		optsConflict.TLSServerName = ""
+		optsConflict.CACert = ""
+		_, err := adminapi.NewKongAPIClient("https://localhost", optsConflict, "")
+		require.ErrorContains(t, err, "when TLSSkipVerify is set, no other TLS options can be set")
+	})
+
+	t.Run("client certificate is provided by the getter", func(t *testing.T) {
+		validate(t, opts, caCert, cert, key, "")
+		require.Positive(t, calls, "client certificate getter should be used in the TLS handshake")
+	})
+}
+
 func TestNewKongClientForWorkspace(t *testing.T) {
 	const workspace = "workspace"
 
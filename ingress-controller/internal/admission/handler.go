@@ -115,6 +115,11 @@ var (
 		Version:  configurationv1alpha1.SchemeGroupVersion.Version,
 		Resource: "kongcustomentities",
 	}
+	kongUpstreamPolicyGVResource = metav1.GroupVersionResource{
+		Group:    configurationv1beta1.SchemeGroupVersion.Group,
+		Version:  configurationv1beta1.SchemeGroupVersion.Version,
+		Resource: "kongupstreampolicies",
+	}
 	secretGVResource = metav1.GroupVersionResource{
 		Group:    corev1.SchemeGroupVersion.Group,
 		Version:  corev1.SchemeGroupVersion.Version,
@@ -152,10 +157,20 @@ func (h RequestHandler) handleValidation(ctx context.Context, request admissionv
 		return h.handleGateway(ctx, request, responseBuilder)
 	case gatewayapi.V1HTTPRouteGVResource, gatewayapi.V1beta1HTTPRouteGVResource:
 		return h.handleHTTPRoute(ctx, request, responseBuilder)
+	case gatewayapi.V1GRPCRouteGVResource:
+		return h.handleGRPCRoute(ctx, request, responseBuilder)
+	case gatewayapi.V1alpha2TCPRouteGVResource:
+		return h.handleTCPRoute(ctx, request, responseBuilder)
+	case gatewayapi.V1alpha2TLSRouteGVResource:
+		return h.handleTLSRoute(ctx, request, responseBuilder)
+	case gatewayapi.V1alpha2UDPRouteGVResource:
+		return h.handleUDPRoute(ctx, request, responseBuilder)
 	case kongVaultGVResource:
 		return h.handleKongVault(ctx, request, responseBuilder)
 	case kongCustomEntityGVResource:
 		return h.handleKongCustomEntity(ctx, request, responseBuilder)
+	case kongUpstreamPolicyGVResource:
+		return h.handleKongUpstreamPolicy(ctx, request, responseBuilder)
 	case serviceGVResource:
 		return h.handleService(request, responseBuilder)
 	case ingressGVResource:
@@ -411,6 +426,82 @@ func (h RequestHandler) handleHTTPRoute(
 	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
 }
 
+// +kubebuilder:webhook:verbs=create;update,groups=gateway.networking.k8s.io,resources=grpcroutes,versions=v1,name=grpcroutes.validation.ingress-controller.konghq.com,path=/,webhookVersions=v1,matchPolicy=equivalent,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
+
+func (h RequestHandler) handleGRPCRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	grpcroute := gatewayapi.GRPCRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &grpcroute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateGRPCRoute(ctx, grpcroute)
+	if err != nil {
+		return nil, err
+	}
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+// +kubebuilder:webhook:verbs=create;update,groups=gateway.networking.k8s.io,resources=tcproutes,versions=v1alpha2,name=tcproutes.validation.ingress-controller.konghq.com,path=/,webhookVersions=v1,matchPolicy=equivalent,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
+
+func (h RequestHandler) handleTCPRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	tcproute := gatewayapi.TCPRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &tcproute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateTCPRoute(ctx, tcproute)
+	if err != nil {
+		return nil, err
+	}
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+// +kubebuilder:webhook:verbs=create;update,groups=gateway.networking.k8s.io,resources=tlsroutes,versions=v1alpha2,name=tlsroutes.validation.ingress-controller.konghq.com,path=/,webhookVersions=v1,matchPolicy=equivalent,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
+
+func (h RequestHandler) handleTLSRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	tlsroute := gatewayapi.TLSRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &tlsroute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateTLSRoute(ctx, tlsroute)
+	if err != nil {
+		return nil, err
+	}
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+// +kubebuilder:webhook:verbs=create;update,groups=gateway.networking.k8s.io,resources=udproutes,versions=v1alpha2,name=udproutes.validation.ingress-controller.konghq.com,path=/,webhookVersions=v1,matchPolicy=equivalent,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
+
+func (h RequestHandler) handleUDPRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	udproute := gatewayapi.UDPRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &udproute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateUDPRoute(ctx, udproute)
+	if err != nil {
+		return nil, err
+	}
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
 const (
 	serviceWarning = "%s is deprecated and will be removed in a future release. Use Service annotations " +
 		"for the 'proxy' section and %s with a KongUpstreamPolicy resource instead."
@@ -484,3 +575,19 @@ func (h RequestHandler) handleKongCustomEntity(ctx context.Context, request admi
 
 	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
 }
+
+// +kubebuilder:webhook:verbs=create;update,groups=configuration.konghq.com,resources=kongupstreampolicies,versions=v1beta1,name=kongupstreampolicies.validation.ingress-controller.konghq.com,path=/,webhookVersions=v1,matchPolicy=equivalent,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1
+
+func (h RequestHandler) handleKongUpstreamPolicy(ctx context.Context, request admissionv1.AdmissionRequest, responseBuilder *ResponseBuilder) (*admissionv1.AdmissionResponse, error) {
+	policy := configurationv1beta1.KongUpstreamPolicy{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &policy)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateKongUpstreamPolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
@@ -0,0 +1,96 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/admission/validation"
+	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+const grpcRouteKind = "GRPCRoute"
+
+// -----------------------------------------------------------------------------
+// Validation - GRPCRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateGRPCRoute validates a GRPCRoute managed by this controller. It checks
+// supported features and uses provided routesValidator to validate Kong routes
+// the GRPCRoute is translated to against Kong Gateway validation endpoint.
+func ValidateGRPCRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	translatorFeatures translator.FeatureFlags,
+	grpcroute *gatewayapi.GRPCRoute,
+	managerClient client.Client,
+) (bool, string, error) {
+	routeIsManaged, err := ensureRouteIsManagedByController(ctx, grpcroute.Namespace, grpcroute.Spec.ParentRefs, managerClient)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to determine whether GRPCRoute is managed by %q controller: %w",
+			gatewaycontroller.GetControllerName(), err)
+	}
+	if !routeIsManaged {
+		return true, "", nil
+	}
+
+	if err := validateGRPCRouteFeatures(grpcroute); err != nil {
+		return false, fmt.Sprintf("GRPCRoute spec did not pass validation: %s", err), nil
+	}
+
+	if err := validation.ValidateRouteSourceAnnotations(grpcroute); err != nil {
+		return false, fmt.Sprintf("GRPCRoute has invalid Kong annotations: %s", err), nil
+	}
+
+	// Translate GRPCRoute to Kong routes the same way the translator does. Hostnames inherited
+	// from Gateway listeners don't affect validity of routes, hence an empty store is used.
+	var routes []kongstate.Route
+	for ruleNumber := range grpcroute.Spec.Rules {
+		if translatorFeatures.ExpressionRoutes {
+			routes = append(routes, subtranslator.GenerateKongExpressionRoutesFromGRPCRouteRule(grpcroute, ruleNumber)...)
+		} else {
+			routes = append(routes, subtranslator.GenerateKongRoutesFromGRPCRouteRule(grpcroute, ruleNumber, store.NewFakeStoreEmpty())...)
+		}
+	}
+	ok, msg := validateKongRoutesWithKongGateway(ctx, routesValidator, grpcRouteKind, routes)
+	return ok, msg, nil
+}
+
+// -----------------------------------------------------------------------------
+// Validation - GRPCRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateGRPCRouteFeatures checks that the GRPCRoute doesn't use features
+// that are not supported by the GRPCRoute implementation.
+func validateGRPCRouteFeatures(grpcroute *gatewayapi.GRPCRoute) error {
+	for ruleIndex, rule := range grpcroute.Spec.Rules {
+		// None of the filters is translated to Kong configuration.
+		if len(rule.Filters) != 0 {
+			return fmt.Errorf("rules[%d].filters: filters are unsupported", ruleIndex)
+		}
+
+		for refIndex, ref := range rule.BackendRefs {
+			if len(ref.Filters) != 0 {
+				return fmt.Errorf("rules[%d].backendRefs[%d]: filters in backendRef is unsupported",
+					ruleIndex, refIndex)
+			}
+			if err := validateServiceBackendRef(grpcRouteKind, ref.BackendObjectReference); err != nil {
+				return fmt.Errorf("rules[%d].backendRefs[%d]: %w", ruleIndex, refIndex, err)
+			}
+		}
+
+		for matchIndex, match := range rule.Matches {
+			if match.Method != nil && match.Method.Service == nil && match.Method.Method == nil {
+				return fmt.Errorf("rules[%d].matches[%d]: method match must specify a service or a method",
+					ruleIndex, matchIndex)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/pkg/manager/scheme"
+)
+
+func TestValidateGRPCRoute(t *testing.T) {
+	var (
+		gatewayClassName = gatewayapi.ObjectName("kong")
+		gatewayClass     = &gatewayapi.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: string(gatewayClassName),
+			},
+			Spec: gatewayapi.GatewayClassSpec{
+				ControllerName: gatewaycontroller.GetControllerName(),
+			},
+		}
+		gateway = &gatewayapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "testing-gateway",
+			},
+			Spec: gatewayapi.GatewaySpec{
+				GatewayClassName: gatewayClassName,
+				Listeners: []gatewayapi.Listener{{
+					Name:     "http",
+					Port:     80,
+					Protocol: gatewayapi.HTTPProtocolType,
+				}},
+			},
+		}
+		parentRefs = []gatewayapi.ParentReference{{
+			Name: gatewayapi.ObjectName(gateway.Name),
+		}}
+		backendRefs = []gatewayapi.GRPCBackendRef{{
+			BackendRef: gatewayapi.BackendRef{
+				BackendObjectReference: gatewayapi.BackendObjectReference{
+					Name: "grpc-service",
+					Port: lo.ToPtr(gatewayapi.PortNumber(9000)),
+				},
+			},
+		}}
+	)
+
+	for _, tt := range []struct {
+		msg             string
+		route           *gatewayapi.GRPCRoute
+		cachedObjects   []client.Object
+		routesValidator routeValidator
+		valid           bool
+		validationMsg   string
+	}{
+		{
+			msg: "route not attached to a Gateway managed by the controller is accepted with no validations",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						Filters: []gatewayapi.GRPCRouteFilter{{
+							Type: gatewayapi.GRPCRouteFilterRequestHeaderModifier,
+						}},
+					}},
+				},
+			},
+			cachedObjects: []client.Object{gatewayClass},
+			valid:         true,
+		},
+		{
+			msg: "valid route is accepted",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						Matches: []gatewayapi.GRPCRouteMatch{{
+							Method: &gatewayapi.GRPCMethodMatch{
+								Service: lo.ToPtr("grpcbin.GRPCBin"),
+								Method:  lo.ToPtr("DummyUnary"),
+							},
+						}},
+						BackendRefs: backendRefs,
+					}},
+				},
+			},
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         true,
+		},
+		{
+			msg: "route with rule filters is rejected",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						Filters: []gatewayapi.GRPCRouteFilter{{
+							Type: gatewayapi.GRPCRouteFilterRequestHeaderModifier,
+						}},
+						BackendRefs: backendRefs,
+					}},
+				},
+			},
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "GRPCRoute spec did not pass validation: rules[0].filters: filters are unsupported",
+		},
+		{
+			msg: "route with a backendRef to a Pod is rejected",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						BackendRefs: []gatewayapi.GRPCBackendRef{{
+							BackendRef: gatewayapi.BackendRef{
+								BackendObjectReference: gatewayapi.BackendObjectReference{
+									Kind: lo.ToPtr(gatewayapi.Kind("Pod")),
+									Name: "grpc-pod",
+								},
+							},
+						}},
+					}},
+				},
+			},
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "GRPCRoute spec did not pass validation: rules[0].backendRefs[0]: Pod is not a supported kind for grpcroute backendRefs, only Service is supported",
+		},
+		{
+			msg: "route with an empty method match is rejected",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						Matches: []gatewayapi.GRPCRouteMatch{{
+							Method: &gatewayapi.GRPCMethodMatch{},
+						}},
+						BackendRefs: backendRefs,
+					}},
+				},
+			},
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "GRPCRoute spec did not pass validation: rules[0].matches[0]: method match must specify a service or a method",
+		},
+		{
+			msg: "route translated to Kong routes rejected by Kong Gateway is rejected",
+			route: &gatewayapi.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-grpcroute",
+				},
+				Spec: gatewayapi.GRPCRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: parentRefs,
+					},
+					Rules: []gatewayapi.GRPCRouteRule{{
+						BackendRefs: backendRefs,
+					}},
+				},
+			},
+			cachedObjects:   []client.Object{gatewayClass, gateway},
+			routesValidator: rejectingRoutesValidator{msg: "invalid route"},
+			valid:           false,
+			validationMsg:   "GRPCRoute failed schema validation: invalid route",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			fakeClient := fakeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tt.cachedObjects...).
+				Build()
+
+			routesValidator := tt.routesValidator
+			if routesValidator == nil {
+				routesValidator = mockRoutesValidator{}
+			}
+			valid, validMsg, err := ValidateGRPCRoute(
+				t.Context(), routesValidator, translator.FeatureFlags{}, tt.route, fakeClient,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.validationMsg, validMsg)
+		})
+	}
+}
+
+// rejectingRoutesValidator rejects all validated routes with msg.
+type rejectingRoutesValidator struct {
+	msg string
+}
+
+func (v rejectingRoutesValidator) Validate(_ context.Context, _ *kong.Route) (bool, string, error) {
+	return false, v.msg, nil
+}
@@ -3,21 +3,22 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/kong/go-kong/kong"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/kong-operator/ingress-controller/internal/admission/validation"
 	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
 	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
 	"github.com/kong/kong-operator/ingress-controller/internal/store"
 )
 
+const httpRouteKind = "HTTPRoute"
+
 type routeValidator interface {
 	Validate(context.Context, *kong.Route) (bool, string, error)
 }
@@ -39,7 +40,7 @@ func ValidateHTTPRoute(
 	managerClient client.Client,
 ) (bool, string, error) {
 	// Check if route is managed by this controller. If not, we don't need to validate it.
-	routeIsManaged, err := ensureHTTPRouteIsManagedByController(ctx, httproute, managerClient)
+	routeIsManaged, err := ensureRouteIsManagedByController(ctx, httproute.Namespace, httproute.Spec.ParentRefs, managerClient)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to determine whether HTTPRoute is managed by %q controller: %w",
 			gatewaycontroller.GetControllerName(), err)
@@ -67,68 +68,39 @@ func ValidateHTTPRoute(
 	return ok, msg, nil
 }
 
-// -----------------------------------------------------------------------------
-// Validation - HTTPRoute - Private Functions
-// -----------------------------------------------------------------------------
-
-// parentRefIsGateway returns true if the group/kind of ParentReference is empty or gateway.networking.k8s.io/Gateway.
-func parentRefIsGateway(parentRef gatewayapi.ParentReference) bool {
-	const KindGateway = gatewayapi.Kind("Gateway")
-
-	return (parentRef.Group == nil || (*parentRef.Group == "" || *parentRef.Group == gatewayapi.V1Group)) &&
-		(parentRef.Kind == nil || (*parentRef.Kind == "" || *parentRef.Kind == KindGateway))
-}
-
-// ensureHTTPRouteIsManagedByController checks whether the provided HTTPRoute is managed by this controller implementation.
-func ensureHTTPRouteIsManagedByController(ctx context.Context, httproute *gatewayapi.HTTPRoute, managerClient client.Client) (bool, error) {
-	// In order to be sure whether an HTTPRoute resource is managed by this
-	// controller we ignore references to Gateway resources that do not exist.
-	for _, parentRef := range httproute.Spec.ParentRefs {
-		// Skip the parentRefs that are not Gateways because they cannot refer to the controller.
-		// https://github.com/Kong/kubernetes-ingress-controller/issues/5912
-		if !parentRefIsGateway(parentRef) {
-			continue
-		}
-
-		// Determine the namespace of the gateway referenced via parentRef. If no
-		// explicit namespace is provided, assume the namespace of the route.
-		namespace := httproute.Namespace
-		if parentRef.Namespace != nil {
-			namespace = string(*parentRef.Namespace)
-		}
-
-		// gather the Gateway resource referenced by parentRef and fail validation
-		// if there is no such Gateway resource.
-		gateway := gatewayapi.Gateway{}
-		if err := managerClient.Get(ctx, client.ObjectKey{
-			Namespace: namespace,
-			Name:      string(parentRef.Name),
-		}, &gateway); err != nil {
-			if apierrors.IsNotFound(err) {
-				return false, nil
-			}
-			return false, fmt.Errorf("failed to get Gateway: %w", err)
-		}
-
-		// Pull the referenced GatewayClass object from the Gateway.
-		gatewayClass := gatewayapi.GatewayClass{}
-		if err := managerClient.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
-			if apierrors.IsNotFound(err) {
-				return false, nil
-			}
-			return false, fmt.Errorf("failed to get GatewayClass: %w", err)
-		}
-
-		// Determine ultimately whether the Gateway is managed by this controller implementation.
-		if gatewayClass.Spec.ControllerName == gatewaycontroller.GetControllerName() {
-			return true, nil
-		}
+// ValidateHTTPRouteConflicts checks whether the given HTTPRoute, if managed by
+// the controller, introduces matches conflicting with matches of HTTPRoutes and
+// Ingresses in the store, i.e. matching the same requests and routing them to
+// different backends.
+func ValidateHTTPRouteConflicts(
+	ctx context.Context,
+	httproute *gatewayapi.HTTPRoute,
+	managerClient client.Client,
+	storer store.Storer,
+) (bool, string, error) {
+	routeIsManaged, err := ensureRouteIsManagedByController(ctx, httproute.Namespace, httproute.Spec.ParentRefs, managerClient)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to determine whether HTTPRoute is managed by %q controller: %w",
+			gatewaycontroller.GetControllerName(), err)
+	}
+	if !routeIsManaged {
+		return true, "", nil
 	}
 
-	// If we get here, the HTTPRoute is not managed by this controller.
-	return false, nil
+	msg, err := validation.ValidateRouteConflicts(httproute, storer)
+	if err != nil {
+		return false, "", err
+	}
+	if msg != "" {
+		return false, fmt.Sprintf("HTTPRoute conflicts with existing routes: %s", msg), nil
+	}
+	return true, "", nil
 }
 
+// -----------------------------------------------------------------------------
+// Validation - HTTPRoute - Private Functions
+// -----------------------------------------------------------------------------
+
 // validateHTTPRouteFeatures checks for features that are not supported by this
 // HTTPRoute implementation and validates that the provided object is not using
 // any of those unsupported features.
@@ -140,6 +112,7 @@ func validateHTTPRouteFeatures(httproute *gatewayapi.HTTPRoute, translatorFeatur
 		gatewayapi.HTTPRouteFilterRequestRedirect:        {},
 		gatewayapi.HTTPRouteFilterURLRewrite:             {},
 		gatewayapi.HTTPRouteFilterExtensionRef:           {},
+		gatewayapi.HTTPRouteFilterRequestMirror:          {},
 	}
 	const (
 		KindService = gatewayapi.Kind("Service")
@@ -153,6 +126,27 @@ func validateHTTPRouteFeatures(httproute *gatewayapi.HTTPRoute, translatorFeatur
 				return fmt.Errorf("rules[%d].filters[%d]: filter type %s is unsupported",
 					ruleIndex, filterIndex, filter.Type)
 			}
+
+			// We only support mirroring requests to Kubernetes Services in the HTTPRoute's namespace.
+			if filter.Type == gatewayapi.HTTPRouteFilterRequestMirror {
+				if filter.RequestMirror == nil {
+					return fmt.Errorf("rules[%d].filters[%d]: %s filter configuration is missing",
+						ruleIndex, filterIndex, filter.Type)
+				}
+				ref := filter.RequestMirror.BackendRef
+				if ref.Group != nil && *ref.Group != "core" && *ref.Group != "" {
+					return fmt.Errorf("rules[%d].filters[%d]: %s is not a supported group for RequestMirror backendRef, only core is supported",
+						ruleIndex, filterIndex, *ref.Group)
+				}
+				if ref.Kind != nil && *ref.Kind != KindService {
+					return fmt.Errorf("rules[%d].filters[%d]: %s is not a supported kind for RequestMirror backendRef, only %s is supported",
+						ruleIndex, filterIndex, *ref.Kind, KindService)
+				}
+				if ref.Namespace != nil && string(*ref.Namespace) != httproute.Namespace {
+					return fmt.Errorf("rules[%d].filters[%d]: RequestMirror backendRef in a different namespace is unsupported",
+						ruleIndex, filterIndex)
+				}
+			}
 		}
 
 		for refIndex, ref := range rule.BackendRefs {
@@ -213,28 +207,13 @@ func validateWithKongGateway(
 		}
 	}
 	if len(errMsgs) > 0 {
-		return false, validationMsg(errMsgs)
+		return false, validationMsg(httpRouteKind, errMsgs)
 	}
+	var routes []kongstate.Route
 	for _, service := range translationResult.ServiceNameToKongstateService {
-		for _, route := range service.Routes {
-			route.Override(logr.Discard())
-			ok, msg, err := routesValidator.Validate(ctx, &route.Route)
-			if err != nil {
-				return false, fmt.Sprintf("Unable to validate HTTPRoute schema: %s", err.Error())
-			}
-			if !ok {
-				errMsgs = append(errMsgs, msg)
-			}
-		}
-	}
-	if len(errMsgs) > 0 {
-		return false, validationMsg(errMsgs)
+		routes = append(routes, service.Routes...)
 	}
-	return true, ""
-}
-
-func validationMsg(errMsgs []string) string {
-	return fmt.Sprintf("HTTPRoute failed schema validation: %s", strings.Join(errMsgs, ", "))
+	return validateKongRoutesWithKongGateway(ctx, routesValidator, httpRouteKind, routes)
 }
 
 func validateHTTPRouteTimeoutBackendRequest(httproute *gatewayapi.HTTPRoute) error {
@@ -244,17 +223,30 @@ func validateHTTPRouteTimeoutBackendRequest(httproute *gatewayapi.HTTPRoute) err
 
 	var firstTimeoutFound *gatewayapi.Duration
 	for _, rule := range httproute.Spec.Rules {
+		timeout := httpRouteRuleBackendTimeout(rule)
 		if firstTimeoutFound != nil {
-			if rule.Timeouts == nil {
+			if timeout == nil {
 				return fmt.Errorf("timeout is set for one of the rules, but not set for another")
 			}
-			if rule.Timeouts != nil && *rule.Timeouts.BackendRequest != *firstTimeoutFound {
+			if *timeout != *firstTimeoutFound {
 				return fmt.Errorf("timeout is set for one of the rules, but a different value is set in another rule")
 			}
-		} else if rule.Timeouts != nil && rule.Timeouts.BackendRequest != nil {
-			firstTimeoutFound = rule.Timeouts.BackendRequest
+		} else if timeout != nil {
+			firstTimeoutFound = timeout
 		}
 	}
 
 	return nil
 }
+
+// httpRouteRuleBackendTimeout returns the timeout which is applied to the backend
+// of the rule: the backendRequest timeout if set, request timeout otherwise.
+func httpRouteRuleBackendTimeout(rule gatewayapi.HTTPRouteRule) *gatewayapi.Duration {
+	if rule.Timeouts == nil {
+		return nil
+	}
+	if rule.Timeouts.BackendRequest != nil {
+		return rule.Timeouts.BackendRequest
+	}
+	return rule.Timeouts.Request
+}
@@ -462,7 +462,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 			validationMsg: "HTTPRoute spec did not pass validation: rules[0].backendRefs[0]: Pod is not a supported kind for httproute backendRefs, only Service is supported",
 		},
 		{
-			msg: "we do not support RequestMirror filter",
+			msg: "we do not support RequestMirror filter with non-Service backendRef",
 			route: &gatewayapi.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: corev1.NamespaceDefault,
@@ -493,6 +493,12 @@ func TestValidateHTTPRoute(t *testing.T) {
 						Filters: []gatewayapi.HTTPRouteFilter{
 							{
 								Type: gatewayapi.HTTPRouteFilterRequestMirror,
+								RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+									BackendRef: gatewayapi.BackendObjectReference{
+										Kind: &podKind,
+										Name: "service2",
+									},
+								},
 							},
 						},
 					}},
@@ -522,7 +528,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 				},
 			},
 			valid:         false,
-			validationMsg: "HTTPRoute spec did not pass validation: rules[0].filters[0]: filter type RequestMirror is unsupported",
+			validationMsg: "HTTPRoute spec did not pass validation: rules[0].filters[0]: Pod is not a supported kind for RequestMirror backendRef, only Service is supported",
 		},
 		{
 			msg: "we do not support CORS filter",
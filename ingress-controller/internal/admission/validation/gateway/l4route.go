@@ -0,0 +1,167 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/admission/validation"
+	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+)
+
+// l4Route is a route routing traffic at the transport layer.
+type l4Route interface {
+	*gatewayapi.UDPRoute | *gatewayapi.TCPRoute | *gatewayapi.TLSRoute
+	client.Object
+}
+
+// -----------------------------------------------------------------------------
+// Validation - TCPRoute, UDPRoute and TLSRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateTCPRoute validates a TCPRoute managed by this controller. It checks supported
+// features and uses provided routesValidator to validate Kong routes the TCPRoute is
+// translated to against Kong Gateway validation endpoint.
+func ValidateTCPRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	translatorFeatures translator.FeatureFlags,
+	tcproute *gatewayapi.TCPRoute,
+	managerClient client.Client,
+) (bool, string, error) {
+	var backendRefs [][]gatewayapi.BackendRef
+	for _, rule := range tcproute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs)
+	}
+	return validateL4Route(
+		ctx, routesValidator, translatorFeatures, tcproute, tcproute.Spec.ParentRefs, gatewayapi.TCPProtocolType, backendRefs, managerClient,
+	)
+}
+
+// ValidateUDPRoute validates a UDPRoute managed by this controller. It checks supported
+// features and uses provided routesValidator to validate Kong routes the UDPRoute is
+// translated to against Kong Gateway validation endpoint.
+func ValidateUDPRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	translatorFeatures translator.FeatureFlags,
+	udproute *gatewayapi.UDPRoute,
+	managerClient client.Client,
+) (bool, string, error) {
+	var backendRefs [][]gatewayapi.BackendRef
+	for _, rule := range udproute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs)
+	}
+	return validateL4Route(
+		ctx, routesValidator, translatorFeatures, udproute, udproute.Spec.ParentRefs, gatewayapi.UDPProtocolType, backendRefs, managerClient,
+	)
+}
+
+// ValidateTLSRoute validates a TLSRoute managed by this controller. It checks supported
+// features and uses provided routesValidator to validate Kong routes the TLSRoute is
+// translated to against Kong Gateway validation endpoint.
+func ValidateTLSRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	translatorFeatures translator.FeatureFlags,
+	tlsroute *gatewayapi.TLSRoute,
+	managerClient client.Client,
+) (bool, string, error) {
+	var backendRefs [][]gatewayapi.BackendRef
+	for _, rule := range tlsroute.Spec.Rules {
+		backendRefs = append(backendRefs, rule.BackendRefs)
+	}
+	return validateL4Route(
+		ctx, routesValidator, translatorFeatures, tlsroute, tlsroute.Spec.ParentRefs, gatewayapi.TLSProtocolType, backendRefs, managerClient,
+	)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - TCPRoute, UDPRoute and TLSRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateL4Route validates a TCPRoute, UDPRoute or TLSRoute attached to parentRefs. backendRefs
+// are the backendRefs of the route's rules.
+func validateL4Route[T l4Route](
+	ctx context.Context,
+	routesValidator routeValidator,
+	translatorFeatures translator.FeatureFlags,
+	route T,
+	parentRefs []gatewayapi.ParentReference,
+	protocol gatewayapi.ProtocolType,
+	backendRefs [][]gatewayapi.BackendRef,
+	managerClient client.Client,
+) (bool, string, error) {
+	kind := l4RouteKind(route)
+	routeIsManaged, err := ensureRouteIsManagedByController(ctx, route.GetNamespace(), parentRefs, managerClient)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to determine whether %s is managed by %q controller: %w",
+			kind, gatewaycontroller.GetControllerName(), err)
+	}
+	if !routeIsManaged {
+		return true, "", nil
+	}
+
+	if err := validateL4RouteFeatures(route, backendRefs); err != nil {
+		return false, fmt.Sprintf("%s spec did not pass validation: %s", kind, err), nil
+	}
+
+	if err := validation.ValidateRouteSourceAnnotations(route); err != nil {
+		return false, fmt.Sprintf("%s has invalid Kong annotations: %s", kind, err), nil
+	}
+
+	// TCPRoutes and UDPRoutes are matched by ports of the Gateway listeners they're attached to.
+	// Until the route is attached to any listener, there's nothing Kong Gateway could validate.
+	var gwPorts []gatewayapi.PortNumber
+	if protocol != gatewayapi.TLSProtocolType {
+		gwPorts, err = gatewayListeningPorts(ctx, route.GetNamespace(), protocol, parentRefs, managerClient)
+		if err != nil {
+			return false, "", err
+		}
+		if len(gwPorts) == 0 {
+			return true, "", nil
+		}
+	}
+
+	routes, err := translator.GenerateKongRoutesFromL4Route(route, gwPorts, translatorFeatures.ExpressionRoutes)
+	if err != nil {
+		return false, fmt.Sprintf("%s spec did not pass validation: %s", kind, err), nil
+	}
+	ok, msg := validateKongRoutesWithKongGateway(ctx, routesValidator, kind, routes)
+	return ok, msg, nil
+}
+
+// validateL4RouteFeatures checks the route's rules can be translated and use only supported backends.
+func validateL4RouteFeatures[T l4Route](route T, backendRefs [][]gatewayapi.BackendRef) error {
+	kind := l4RouteKind(route)
+	if len(backendRefs) == 0 {
+		return subtranslator.ErrRouteValidationNoRules
+	}
+	if tlsroute, ok := any(route).(*gatewayapi.TLSRoute); ok && len(tlsroute.Spec.Hostnames) == 0 {
+		return errors.New("no hostnames provided")
+	}
+	for ruleIndex, refs := range backendRefs {
+		for refIndex, ref := range refs {
+			if err := validateServiceBackendRef(kind, ref.BackendObjectReference); err != nil {
+				return fmt.Errorf("rules[%d].backendRefs[%d]: %w", ruleIndex, refIndex, err)
+			}
+		}
+	}
+	return nil
+}
+
+func l4RouteKind[T l4Route](route T) string {
+	switch any(route).(type) {
+	case *gatewayapi.UDPRoute:
+		return "UDPRoute"
+	case *gatewayapi.TCPRoute:
+		return "TCPRoute"
+	default:
+		return "TLSRoute"
+	}
+}
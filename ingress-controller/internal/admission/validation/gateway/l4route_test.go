@@ -0,0 +1,248 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/pkg/manager/scheme"
+)
+
+func TestValidateL4Routes(t *testing.T) {
+	var (
+		gatewayClassName = gatewayapi.ObjectName("kong")
+		gatewayClass     = &gatewayapi.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: string(gatewayClassName),
+			},
+			Spec: gatewayapi.GatewayClassSpec{
+				ControllerName: gatewaycontroller.GetControllerName(),
+			},
+		}
+		gateway = &gatewayapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "testing-gateway",
+			},
+			Spec: gatewayapi.GatewaySpec{
+				GatewayClassName: gatewayClassName,
+				Listeners: []gatewayapi.Listener{
+					{
+						Name:     "tcp",
+						Port:     8888,
+						Protocol: gatewayapi.TCPProtocolType,
+					},
+					{
+						Name:     "udp",
+						Port:     9999,
+						Protocol: gatewayapi.UDPProtocolType,
+					},
+					{
+						Name:     "tls",
+						Port:     8899,
+						Protocol: gatewayapi.TLSProtocolType,
+					},
+				},
+			},
+		}
+		commonRouteSpec = gatewayapi.CommonRouteSpec{
+			ParentRefs: []gatewayapi.ParentReference{{
+				Name: gatewayapi.ObjectName(gateway.Name),
+			}},
+		}
+		objectMeta = metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "testing-route",
+		}
+		backendRefs = []gatewayapi.BackendRef{{
+			BackendObjectReference: gatewayapi.BackendObjectReference{
+				Name: "service",
+				Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+			},
+		}}
+		podBackendRefs = []gatewayapi.BackendRef{{
+			BackendObjectReference: gatewayapi.BackendObjectReference{
+				Kind: lo.ToPtr(gatewayapi.Kind("Pod")),
+				Name: "pod",
+			},
+		}}
+	)
+
+	for _, tt := range []struct {
+		msg             string
+		validate        l4RouteValidateFunc
+		cachedObjects   []client.Object
+		routesValidator routeValidator
+		valid           bool
+		validationMsg   string
+	}{
+		{
+			msg: "TCPRoute not attached to a Gateway managed by the controller is accepted with no validations",
+			validate: tcpRoute(&gatewayapi.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass},
+			valid:         true,
+		},
+		{
+			msg: "valid TCPRoute is accepted",
+			validate: tcpRoute(&gatewayapi.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayapi.TCPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         true,
+		},
+		{
+			msg: "TCPRoute with no rules is rejected",
+			validate: tcpRoute(&gatewayapi.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "TCPRoute spec did not pass validation: no rules provided",
+		},
+		{
+			msg: "TCPRoute rejected by Kong Gateway is rejected",
+			validate: tcpRoute(&gatewayapi.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TCPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayapi.TCPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects:   []client.Object{gatewayClass, gateway},
+			routesValidator: rejectingRoutesValidator{msg: "invalid route"},
+			valid:           false,
+			validationMsg:   "TCPRoute failed schema validation: invalid route",
+		},
+		{
+			msg: "TCPRoute attached to a Gateway with no TCP listeners is not validated with Kong Gateway",
+			validate: tcpRoute(&gatewayapi.TCPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TCPRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: []gatewayapi.ParentReference{{
+							Name:        gatewayapi.ObjectName(gateway.Name),
+							SectionName: lo.ToPtr(gatewayapi.SectionName("udp")),
+						}},
+					},
+					Rules: []gatewayapi.TCPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects:   []client.Object{gatewayClass, gateway},
+			routesValidator: rejectingRoutesValidator{msg: "invalid route"},
+			valid:           true,
+		},
+		{
+			msg: "UDPRoute with a backendRef to a Pod is rejected",
+			validate: udpRoute(&gatewayapi.UDPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.UDPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayapi.UDPRouteRule{{BackendRefs: podBackendRefs}},
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "UDPRoute spec did not pass validation: rules[0].backendRefs[0]: Pod is not a supported kind for udproute backendRefs, only Service is supported",
+		},
+		{
+			msg: "valid UDPRoute is accepted",
+			validate: udpRoute(&gatewayapi.UDPRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.UDPRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayapi.UDPRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         true,
+		},
+		{
+			msg: "TLSRoute with no hostnames is rejected",
+			validate: tlsRoute(&gatewayapi.TLSRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TLSRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Rules:           []gatewayapi.TLSRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects: []client.Object{gatewayClass, gateway},
+			valid:         false,
+			validationMsg: "TLSRoute spec did not pass validation: no hostnames provided",
+		},
+		{
+			msg: "TLSRoute rejected by Kong Gateway is rejected",
+			validate: tlsRoute(&gatewayapi.TLSRoute{
+				ObjectMeta: objectMeta,
+				Spec: gatewayapi.TLSRouteSpec{
+					CommonRouteSpec: commonRouteSpec,
+					Hostnames:       []gatewayapi.Hostname{"example.com"},
+					Rules:           []gatewayapi.TLSRouteRule{{BackendRefs: backendRefs}},
+				},
+			}),
+			cachedObjects:   []client.Object{gatewayClass, gateway},
+			routesValidator: rejectingRoutesValidator{msg: "invalid route"},
+			valid:           false,
+			validationMsg:   "TLSRoute failed schema validation: invalid route",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			fakeClient := fakeclient.
+				NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(tt.cachedObjects...).
+				Build()
+
+			routesValidator := tt.routesValidator
+			if routesValidator == nil {
+				routesValidator = mockRoutesValidator{}
+			}
+			valid, validMsg, err := tt.validate(t.Context(), routesValidator, fakeClient)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.validationMsg, validMsg)
+		})
+	}
+}
+
+// l4RouteValidateFunc validates a TCPRoute, UDPRoute or TLSRoute.
+type l4RouteValidateFunc func(context.Context, routeValidator, client.Client) (bool, string, error)
+
+func tcpRoute(route *gatewayapi.TCPRoute) l4RouteValidateFunc {
+	return func(ctx context.Context, routesValidator routeValidator, managerClient client.Client) (bool, string, error) {
+		return ValidateTCPRoute(ctx, routesValidator, translator.FeatureFlags{}, route, managerClient)
+	}
+}
+
+func udpRoute(route *gatewayapi.UDPRoute) l4RouteValidateFunc {
+	return func(ctx context.Context, routesValidator routeValidator, managerClient client.Client) (bool, string, error) {
+		return ValidateUDPRoute(ctx, routesValidator, translator.FeatureFlags{}, route, managerClient)
+	}
+}
+
+func tlsRoute(route *gatewayapi.TLSRoute) l4RouteValidateFunc {
+	return func(ctx context.Context, routesValidator routeValidator, managerClient client.Client) (bool, string, error) {
+		return ValidateTLSRoute(ctx, routesValidator, translator.FeatureFlags{}, route, managerClient)
+	}
+}
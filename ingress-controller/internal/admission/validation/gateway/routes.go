@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+)
+
+// -----------------------------------------------------------------------------
+// Validation - Routes - Private Functions
+// -----------------------------------------------------------------------------
+
+// parentRefIsGateway returns true if the group/kind of ParentReference is empty or gateway.networking.k8s.io/Gateway.
+func parentRefIsGateway(parentRef gatewayapi.ParentReference) bool {
+	const KindGateway = gatewayapi.Kind("Gateway")
+
+	return (parentRef.Group == nil || (*parentRef.Group == "" || *parentRef.Group == gatewayapi.V1Group)) &&
+		(parentRef.Kind == nil || (*parentRef.Kind == "" || *parentRef.Kind == KindGateway))
+}
+
+// ensureRouteIsManagedByController checks whether a route in routeNamespace attached to parentRefs
+// is managed by this controller implementation.
+func ensureRouteIsManagedByController(
+	ctx context.Context, routeNamespace string, parentRefs []gatewayapi.ParentReference, managerClient client.Client,
+) (bool, error) {
+	// In order to be sure whether a route resource is managed by this
+	// controller we ignore references to Gateway resources that do not exist.
+	for _, parentRef := range parentRefs {
+		// Skip the parentRefs that are not Gateways because they cannot refer to the controller.
+		// https://github.com/Kong/kubernetes-ingress-controller/issues/5912
+		if !parentRefIsGateway(parentRef) {
+			continue
+		}
+
+		// Determine the namespace of the gateway referenced via parentRef. If no
+		// explicit namespace is provided, assume the namespace of the route.
+		namespace := routeNamespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+
+		// gather the Gateway resource referenced by parentRef and fail validation
+		// if there is no such Gateway resource.
+		gateway := gatewayapi.Gateway{}
+		if err := managerClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      string(parentRef.Name),
+		}, &gateway); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get Gateway: %w", err)
+		}
+
+		// Pull the referenced GatewayClass object from the Gateway.
+		gatewayClass := gatewayapi.GatewayClass{}
+		if err := managerClient.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get GatewayClass: %w", err)
+		}
+
+		// Determine ultimately whether the Gateway is managed by this controller implementation.
+		if gatewayClass.Spec.ControllerName == gatewaycontroller.GetControllerName() {
+			return true, nil
+		}
+	}
+
+	// If we get here, the route is not managed by this controller.
+	return false, nil
+}
+
+// gatewayListeningPorts returns ports of listeners of Gateways referenced in parentRefs accepting
+// the protocol. Gateways that don't exist are skipped.
+func gatewayListeningPorts(
+	ctx context.Context,
+	routeNamespace string,
+	protocol gatewayapi.ProtocolType,
+	parentRefs []gatewayapi.ParentReference,
+	managerClient client.Client,
+) ([]gatewayapi.PortNumber, error) {
+	var gwPorts []gatewayapi.PortNumber
+	for _, parentRef := range parentRefs {
+		if !parentRefIsGateway(parentRef) {
+			continue
+		}
+		namespace := routeNamespace
+		if parentRef.Namespace != nil {
+			namespace = string(*parentRef.Namespace)
+		}
+		gateway := gatewayapi.Gateway{}
+		if err := managerClient.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      string(parentRef.Name),
+		}, &gateway); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get Gateway: %w", err)
+		}
+		gwPorts = append(gwPorts, lo.FilterMap(gateway.Spec.Listeners, func(l gatewayapi.Listener, _ int) (gatewayapi.PortNumber, bool) {
+			if (parentRef.SectionName == nil || *parentRef.SectionName == l.Name) && protocol == l.Protocol {
+				return l.Port, true
+			}
+			return 0, false
+		})...)
+	}
+	return gwPorts, nil
+}
+
+// validateServiceBackendRef checks that the backendRef of a route of the kind points to a Kubernetes Service,
+// the only kind of backends supported by this implementation.
+func validateServiceBackendRef(kind string, ref gatewayapi.BackendObjectReference) error {
+	const KindService = gatewayapi.Kind("Service")
+
+	if ref.Group != nil && *ref.Group != "core" && *ref.Group != "" {
+		return fmt.Errorf("%s is not a supported group for %s backendRefs, only core is supported", *ref.Group, strings.ToLower(kind))
+	}
+	if ref.Kind != nil && *ref.Kind != KindService {
+		return fmt.Errorf("%s is not a supported kind for %s backendRefs, only %s is supported", *ref.Kind, strings.ToLower(kind), KindService)
+	}
+	return nil
+}
+
+// validateKongRoutesWithKongGateway validates Kong routes translated from a route of the kind against Kong Gateway.
+func validateKongRoutesWithKongGateway(
+	ctx context.Context, routesValidator routeValidator, kind string, routes []kongstate.Route,
+) (bool, string) {
+	var errMsgs []string
+	for _, route := range routes {
+		route.Override(logr.Discard())
+		ok, msg, err := routesValidator.Validate(ctx, &route.Route)
+		if err != nil {
+			return false, fmt.Sprintf("Unable to validate %s schema: %s", kind, err.Error())
+		}
+		if !ok {
+			errMsgs = append(errMsgs, msg)
+		}
+	}
+	if len(errMsgs) > 0 {
+		return false, validationMsg(kind, errMsgs)
+	}
+	return true, ""
+}
+
+func validationMsg(kind string, errMsgs []string) string {
+	return fmt.Sprintf("%s failed schema validation: %s", kind, strings.Join(errMsgs, ", "))
+}
@@ -0,0 +1,200 @@
+package kongupstreampolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configurationv1beta1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1beta1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+// UpstreamValidator validates Kong entities against Kong Gateway.
+type UpstreamValidator interface {
+	Validate(ctx context.Context, entityType kong.EntityType, entity any) (bool, string, error)
+}
+
+// ValidateKongUpstreamPolicy validates a KongUpstreamPolicy. It translates the policy to a Kong
+// upstream and uses provided upstreamsValidator (when not nil) to validate it against Kong Gateway
+// validation endpoint. It also checks that the policy doesn't conflict with Services it's applied to:
+// a Kong upstream is generated for all Services used as backends of a single route rule, hence all
+// of them have to use the same KongUpstreamPolicy.
+func ValidateKongUpstreamPolicy(
+	ctx context.Context,
+	upstreamsValidator UpstreamValidator,
+	policy *configurationv1beta1.KongUpstreamPolicy,
+	managerClient client.Client,
+	storer store.Storer,
+) (bool, string, error) {
+	if upstreamsValidator != nil {
+		upstream := kongstate.TranslateKongUpstreamPolicy(policy.Spec)
+		// The name of the upstream is generated from the Service it's applied to.
+		// Any valid hostname is used in its place.
+		upstream.Name = kong.String(fmt.Sprintf("%s.%s.svc", policy.Name, policy.Namespace))
+		ok, msg, err := upstreamsValidator.Validate(ctx, kong.EntityTypeUpstreams, upstream)
+		if err != nil {
+			return false, fmt.Sprintf("Unable to validate KongUpstreamPolicy: %s", err), nil
+		}
+		if !ok {
+			return false, fmt.Sprintf("KongUpstreamPolicy failed schema validation: %s", msg), nil
+		}
+	}
+
+	conflicts, err := conflictingServices(ctx, policy, managerClient, storer)
+	if err != nil {
+		return false, "", err
+	}
+	if len(conflicts) > 0 {
+		return false, fmt.Sprintf("KongUpstreamPolicy conflicts with Services it's applied to: %s", strings.Join(conflicts, ", ")), nil
+	}
+	return true, "", nil
+}
+
+// conflictingServices returns descriptions of route rules using Services the policy is applied to together with
+// Services that don't use the policy as their backends.
+func conflictingServices(
+	ctx context.Context,
+	policy *configurationv1beta1.KongUpstreamPolicy,
+	managerClient client.Client,
+	storer store.Storer,
+) ([]string, error) {
+	var services corev1.ServiceList
+	if err := managerClient.List(ctx, &services, client.InNamespace(policy.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list Services: %w", err)
+	}
+	policyServices := make(map[k8stypes.NamespacedName]struct{})
+	for _, svc := range services.Items {
+		if name, ok := annotations.ExtractUpstreamPolicy(svc.Annotations); ok && name == policy.Name {
+			policyServices[client.ObjectKeyFromObject(&svc)] = struct{}{}
+		}
+	}
+	if len(policyServices) == 0 {
+		return nil, nil
+	}
+
+	rules, err := listRouteRulesBackends(storer)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	for _, rule := range rules {
+		if !slices.ContainsFunc(rule.services, func(nn k8stypes.NamespacedName) bool {
+			_, ok := policyServices[nn]
+			return ok
+		}) {
+			continue
+		}
+		for _, nn := range rule.services {
+			if _, ok := policyServices[nn]; ok {
+				continue
+			}
+			// Backends that don't exist are not translated to upstream targets.
+			if _, err := storer.GetService(nn.Namespace, nn.Name); err != nil {
+				if errors.As(err, &store.NotFoundError{}) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get Service %s: %w", nn, err)
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s uses Service %s not using the KongUpstreamPolicy", rule.description, nn))
+		}
+	}
+	return lo.Uniq(conflicts), nil
+}
+
+// routeRuleBackends are Services used as backends of a route rule.
+type routeRuleBackends struct {
+	// description identifies the rule in messages.
+	description string
+	services    []k8stypes.NamespacedName
+}
+
+// listRouteRulesBackends returns Services used in rules of all routes from the store.
+func listRouteRulesBackends(storer store.Storer) ([]routeRuleBackends, error) {
+	var rules []routeRuleBackends
+	addRule := func(kind string, route client.Object, ruleIndex int, refs []gatewayapi.BackendRef) {
+		rules = append(rules, routeRuleBackends{
+			description: fmt.Sprintf("%s %s/%s rules[%d]", kind, route.GetNamespace(), route.GetName(), ruleIndex),
+			services: lo.FilterMap(refs, func(ref gatewayapi.BackendRef, _ int) (k8stypes.NamespacedName, bool) {
+				return serviceRef(route.GetNamespace(), ref)
+			}),
+		})
+	}
+
+	httpRoutes, err := storer.ListHTTPRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	for _, route := range httpRoutes {
+		for i, rule := range route.Spec.Rules {
+			addRule("HTTPRoute", route, i, lo.Map(rule.BackendRefs, func(ref gatewayapi.HTTPBackendRef, _ int) gatewayapi.BackendRef {
+				return ref.BackendRef
+			}))
+		}
+	}
+	grpcRoutes, err := storer.ListGRPCRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GRPCRoutes: %w", err)
+	}
+	for _, route := range grpcRoutes {
+		for i, rule := range route.Spec.Rules {
+			addRule("GRPCRoute", route, i, lo.Map(rule.BackendRefs, func(ref gatewayapi.GRPCBackendRef, _ int) gatewayapi.BackendRef {
+				return ref.BackendRef
+			}))
+		}
+	}
+	tcpRoutes, err := storer.ListTCPRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TCPRoutes: %w", err)
+	}
+	for _, route := range tcpRoutes {
+		for i, rule := range route.Spec.Rules {
+			addRule("TCPRoute", route, i, rule.BackendRefs)
+		}
+	}
+	udpRoutes, err := storer.ListUDPRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list UDPRoutes: %w", err)
+	}
+	for _, route := range udpRoutes {
+		for i, rule := range route.Spec.Rules {
+			addRule("UDPRoute", route, i, rule.BackendRefs)
+		}
+	}
+	tlsRoutes, err := storer.ListTLSRoutes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TLSRoutes: %w", err)
+	}
+	for _, route := range tlsRoutes {
+		for i, rule := range route.Spec.Rules {
+			addRule("TLSRoute", route, i, rule.BackendRefs)
+		}
+	}
+	return rules, nil
+}
+
+// serviceRef returns the Service the backendRef of a route in routeNamespace points to.
+func serviceRef(routeNamespace string, ref gatewayapi.BackendRef) (k8stypes.NamespacedName, bool) {
+	if ref.Group != nil && *ref.Group != "core" && *ref.Group != "" {
+		return k8stypes.NamespacedName{}, false
+	}
+	if ref.Kind != nil && *ref.Kind != "Service" {
+		return k8stypes.NamespacedName{}, false
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return k8stypes.NamespacedName{Namespace: namespace, Name: string(ref.Name)}, true
+}
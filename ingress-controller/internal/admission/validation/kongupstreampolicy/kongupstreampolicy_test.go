@@ -0,0 +1,149 @@
+package kongupstreampolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configurationv1beta1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1beta1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+	"github.com/kong/kong-operator/ingress-controller/pkg/manager/scheme"
+)
+
+func TestValidateKongUpstreamPolicy(t *testing.T) {
+	var (
+		policy = &configurationv1beta1.KongUpstreamPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "policy",
+			},
+			Spec: configurationv1beta1.KongUpstreamPolicySpec{
+				Algorithm: lo.ToPtr("round-robin"),
+			},
+		}
+		serviceWithPolicy = func(name string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      name,
+					Annotations: map[string]string{
+						configurationv1beta1.KongUpstreamPolicyAnnotationKey: policy.Name,
+					},
+				},
+			}
+		}
+		serviceWithoutPolicy = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "service-without-policy",
+			},
+		}
+		httpRoute = func(serviceNames ...string) *gatewayapi.HTTPRoute {
+			return &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "httproute",
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					Rules: []gatewayapi.HTTPRouteRule{{
+						BackendRefs: lo.Map(serviceNames, func(name string, _ int) gatewayapi.HTTPBackendRef {
+							return gatewayapi.HTTPBackendRef{
+								BackendRef: gatewayapi.BackendRef{
+									BackendObjectReference: gatewayapi.BackendObjectReference{
+										Name: gatewayapi.ObjectName(name),
+										Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+									},
+								},
+							}
+						}),
+					}},
+				},
+			}
+		}
+	)
+
+	testCases := []struct {
+		name               string
+		services           []*corev1.Service
+		httpRoutes         []*gatewayapi.HTTPRoute
+		upstreamsValidator UpstreamValidator
+		expectedValid      bool
+		expectedMsg        string
+	}{
+		{
+			name:          "policy not applied to any Service is accepted",
+			services:      []*corev1.Service{serviceWithoutPolicy},
+			httpRoutes:    []*gatewayapi.HTTPRoute{httpRoute(serviceWithoutPolicy.Name)},
+			expectedValid: true,
+		},
+		{
+			name:          "policy applied to all Services of a route rule is accepted",
+			services:      []*corev1.Service{serviceWithPolicy("svc-1"), serviceWithPolicy("svc-2")},
+			httpRoutes:    []*gatewayapi.HTTPRoute{httpRoute("svc-1", "svc-2")},
+			expectedValid: true,
+		},
+		{
+			name:          "policy applied to a Service used along with Service not using it is rejected",
+			services:      []*corev1.Service{serviceWithPolicy("svc-1"), serviceWithoutPolicy},
+			httpRoutes:    []*gatewayapi.HTTPRoute{httpRoute("svc-1", serviceWithoutPolicy.Name)},
+			expectedValid: false,
+			expectedMsg: "KongUpstreamPolicy conflicts with Services it's applied to: " +
+				"HTTPRoute default/httproute rules[0] uses Service default/service-without-policy not using the KongUpstreamPolicy",
+		},
+		{
+			name:          "non-existent Services used along with Services using the policy are ignored",
+			services:      []*corev1.Service{serviceWithPolicy("svc-1")},
+			httpRoutes:    []*gatewayapi.HTTPRoute{httpRoute("svc-1", "non-existent")},
+			expectedValid: true,
+		},
+		{
+			name:               "policy rejected by Kong Gateway is rejected",
+			upstreamsValidator: fakeUpstreamsValidator{valid: false, msg: "invalid algorithm"},
+			expectedValid:      false,
+			expectedMsg:        "KongUpstreamPolicy failed schema validation: invalid algorithm",
+		},
+		{
+			name:               "policy accepted by Kong Gateway is accepted",
+			upstreamsValidator: fakeUpstreamsValidator{valid: true},
+			expectedValid:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(scheme.Get()).
+				WithObjects(lo.Map(tc.services, func(svc *corev1.Service, _ int) client.Object { return svc })...).
+				Build()
+			storer, err := store.NewFakeStore(store.FakeObjects{
+				Services:   tc.services,
+				HTTPRoutes: tc.httpRoutes,
+			})
+			require.NoError(t, err)
+
+			valid, msg, err := ValidateKongUpstreamPolicy(t.Context(), tc.upstreamsValidator, policy, fakeClient, storer)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValid, valid)
+			assert.Equal(t, tc.expectedMsg, msg)
+		})
+	}
+}
+
+type fakeUpstreamsValidator struct {
+	valid bool
+	msg   string
+}
+
+func (v fakeUpstreamsValidator) Validate(_ context.Context, _ kong.EntityType, _ any) (bool, string, error) {
+	return v.valid, v.msg, nil
+}
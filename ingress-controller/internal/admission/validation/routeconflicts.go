@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+// ValidateRouteConflicts checks whether the provided HTTPRoute or Ingress introduces matches conflicting
+// with matches of HTTPRoutes and Ingresses in the store, i.e. matching the same requests and routing them
+// to different backends. Conflicts existing before the change are not reported. It returns a message
+// describing the new conflicts, or an empty string if there are none.
+func ValidateRouteConflicts(obj client.Object, storer store.Storer) (string, error) {
+	httpRoutes, err := storer.ListHTTPRoutes()
+	if err != nil {
+		return "", fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	ingresses := storer.ListIngressesV1()
+	existingConflicts := translator.DetectRouteConflicts(httpRoutes, ingresses)
+
+	// The object is not created yet, it loses conflicts with all existing objects.
+	if obj.GetCreationTimestamp().IsZero() {
+		obj.SetCreationTimestamp(metav1.Now())
+	}
+	var kind string
+	switch o := obj.(type) {
+	case *gatewayapi.HTTPRoute:
+		kind = "HTTPRoute"
+		httpRoutes = replaceObject(httpRoutes, o)
+	case *netv1.Ingress:
+		kind = "Ingress"
+		ingresses = replaceObject(ingresses, o)
+	default:
+		return "", fmt.Errorf("unsupported object type %T", obj)
+	}
+
+	existing := make(map[string]struct{}, len(existingConflicts))
+	for _, c := range existingConflicts {
+		existing[routeConflictKey(c)] = struct{}{}
+	}
+	var msgs []string
+	for _, c := range translator.DetectRouteConflicts(httpRoutes, ingresses) {
+		if !isObject(c.Kind, c.Object, kind, obj) && !isObject(c.ConflictingKind, c.ConflictingObject, kind, obj) {
+			continue
+		}
+		if _, ok := existing[routeConflictKey(c)]; ok {
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%s %s/%s %s", c.Kind, c.Object.GetNamespace(), c.Object.GetName(), c.Message()))
+	}
+	return strings.Join(msgs, "; "), nil
+}
+
+// replaceObject returns objects with the object of the same namespace and name replaced by obj,
+// or with obj appended if there's no such object.
+func replaceObject[T client.Object](objects []T, obj T) []T {
+	replaced := make([]T, 0, len(objects)+1)
+	for _, o := range objects {
+		if o.GetNamespace() != obj.GetNamespace() || o.GetName() != obj.GetName() {
+			replaced = append(replaced, o)
+		}
+	}
+	return append(replaced, obj)
+}
+
+func isObject(aKind string, a client.Object, bKind string, b client.Object) bool {
+	return aKind == bKind && a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
+
+func routeConflictKey(c translator.RouteConflict) string {
+	return fmt.Sprintf("%s/%s/%s/%s|%s/%s/%s/%s|%s",
+		c.Kind, c.Object.GetNamespace(), c.Object.GetName(), c.Location,
+		c.ConflictingKind, c.ConflictingObject.GetNamespace(), c.ConflictingObject.GetName(), c.ConflictingLocation,
+		c.Expression,
+	)
+}
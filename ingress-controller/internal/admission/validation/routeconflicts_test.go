@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+func TestValidateRouteConflicts(t *testing.T) {
+	var (
+		created = metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+		httpRoute = func(name, service string) *gatewayapi.HTTPRoute {
+			return &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					Hostnames: []gatewayapi.Hostname{"example.com"},
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{{
+							Path: &gatewayapi.HTTPPathMatch{
+								Type:  lo.ToPtr(gatewayapi.PathMatchPathPrefix),
+								Value: lo.ToPtr("/foo"),
+							},
+						}},
+						BackendRefs: []gatewayapi.HTTPBackendRef{{
+							BackendRef: gatewayapi.BackendRef{
+								BackendObjectReference: gatewayapi.BackendObjectReference{
+									Name: gatewayapi.ObjectName(service),
+									Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+								},
+							},
+						}},
+					}},
+				},
+			}
+		}
+		ingress = func(name, service string) *netv1.Ingress {
+			return &netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      name,
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+					},
+				},
+				Spec: netv1.IngressSpec{
+					Rules: []netv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: netv1.IngressRuleValue{
+							HTTP: &netv1.HTTPIngressRuleValue{
+								Paths: []netv1.HTTPIngressPath{{
+									Path:     "/foo",
+									PathType: lo.ToPtr(netv1.PathTypePrefix),
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: service,
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+		}
+		withCreationTimestamp = func(obj client.Object) client.Object {
+			obj.SetCreationTimestamp(created)
+			return obj
+		}
+	)
+
+	testCases := []struct {
+		name        string
+		httpRoutes  []*gatewayapi.HTTPRoute
+		ingresses   []*netv1.Ingress
+		obj         client.Object
+		expectedMsg string
+	}{
+		{
+			name:      "new HTTPRoute routing the same requests to the same backend is accepted",
+			ingresses: []*netv1.Ingress{withCreationTimestamp(ingress("ingress", "svc-1")).(*netv1.Ingress)},
+			obj:       httpRoute("route", "svc-1"),
+		},
+		{
+			name:      "new HTTPRoute routing the same requests to a different backend is rejected",
+			ingresses: []*netv1.Ingress{withCreationTimestamp(ingress("ingress", "svc-1")).(*netv1.Ingress)},
+			obj:       httpRoute("route", "svc-2"),
+			expectedMsg: "HTTPRoute default/route rules[0].matches[0] conflicts with rules[0].http.paths[0] " +
+				"of Ingress default/ingress routing the same requests to different backends",
+		},
+		{
+			name:       "new Ingress routing the same requests to a different backend is rejected",
+			httpRoutes: []*gatewayapi.HTTPRoute{withCreationTimestamp(httpRoute("route", "svc-1")).(*gatewayapi.HTTPRoute)},
+			obj:        ingress("ingress", "svc-2"),
+			expectedMsg: "Ingress default/ingress rules[0].http.paths[0] conflicts with rules[0].matches[0] " +
+				"of HTTPRoute default/route routing the same requests to different backends",
+		},
+		{
+			name: "updated HTTPRoute winning a conflict is rejected",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				withCreationTimestamp(httpRoute("route", "svc-1")).(*gatewayapi.HTTPRoute),
+			},
+			ingresses: []*netv1.Ingress{
+				func() *netv1.Ingress {
+					ingress := ingress("ingress", "svc-1")
+					ingress.CreationTimestamp = metav1.NewTime(created.Add(time.Hour))
+					return ingress
+				}(),
+			},
+			obj: withCreationTimestamp(httpRoute("route", "svc-2")),
+			expectedMsg: "Ingress default/ingress rules[0].http.paths[0] conflicts with rules[0].matches[0] " +
+				"of HTTPRoute default/route routing the same requests to different backends",
+		},
+		{
+			name: "updated HTTPRoute with a conflict existing before the update is accepted",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				withCreationTimestamp(httpRoute("route", "svc-2")).(*gatewayapi.HTTPRoute),
+			},
+			ingresses: []*netv1.Ingress{
+				withCreationTimestamp(ingress("ingress", "svc-1")).(*netv1.Ingress),
+			},
+			obj: func() client.Object {
+				route := withCreationTimestamp(httpRoute("route", "svc-2")).(*gatewayapi.HTTPRoute)
+				route.Labels = map[string]string{"updated": "true"}
+				return route
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storer, err := store.NewFakeStore(store.FakeObjects{
+				HTTPRoutes:  tc.httpRoutes,
+				IngressesV1: tc.ingresses,
+			})
+			require.NoError(t, err)
+
+			msg, err := ValidateRouteConflicts(tc.obj, storer)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMsg, msg)
+		})
+	}
+}
@@ -19,9 +19,11 @@ import (
 	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
 	configurationv1beta1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1beta1"
 
+	"github.com/kong/kong-operator/ingress-controller/internal/admission/validation"
 	credsvalidation "github.com/kong/kong-operator/ingress-controller/internal/admission/validation/consumers/credentials"
 	gatewayvalidation "github.com/kong/kong-operator/ingress-controller/internal/admission/validation/gateway"
 	ingressvalidation "github.com/kong/kong-operator/ingress-controller/internal/admission/validation/ingress"
+	kongupstreampolicyvalidation "github.com/kong/kong-operator/ingress-controller/internal/admission/validation/kongupstreampolicy"
 	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
 	gatewaycontroller "github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
@@ -43,7 +45,12 @@ type KongValidator interface {
 	ValidateCredential(ctx context.Context, secret corev1.Secret) (bool, string)
 	ValidateGateway(ctx context.Context, gateway gatewayapi.Gateway) (bool, string, error)
 	ValidateHTTPRoute(ctx context.Context, httproute gatewayapi.HTTPRoute) (bool, string, error)
+	ValidateGRPCRoute(ctx context.Context, grpcroute gatewayapi.GRPCRoute) (bool, string, error)
+	ValidateTCPRoute(ctx context.Context, tcproute gatewayapi.TCPRoute) (bool, string, error)
+	ValidateTLSRoute(ctx context.Context, tlsroute gatewayapi.TLSRoute) (bool, string, error)
+	ValidateUDPRoute(ctx context.Context, udproute gatewayapi.UDPRoute) (bool, string, error)
 	ValidateIngress(ctx context.Context, ingress netv1.Ingress) (bool, string, error)
+	ValidateKongUpstreamPolicy(ctx context.Context, policy configurationv1beta1.KongUpstreamPolicy) (bool, string, error)
 }
 
 // AdminAPIServicesProvider provides KongHTTPValidator with Kong Admin API services that are needed to perform
@@ -110,6 +117,9 @@ type KongHTTPValidator struct {
 	ManagerClient            client.Client
 	AdminAPIServicesProvider AdminAPIServicesProvider
 	TranslatorFeatures       translator.FeatureFlags
+	// RejectRouteConflicts enables rejecting HTTPRoutes and Ingresses with matches conflicting
+	// with matches of existing HTTPRoutes and Ingresses routing the same requests to different backends.
+	RejectRouteConflicts bool
 
 	ingressClassMatcher   func(*metav1.ObjectMeta, string, annotations.ClassMatching) bool
 	ingressV1ClassMatcher func(*netv1.Ingress, annotations.ClassMatching) bool
@@ -444,12 +454,44 @@ func (validator KongHTTPValidator) ValidateGateway(
 func (validator KongHTTPValidator) ValidateHTTPRoute(
 	ctx context.Context, httproute gatewayapi.HTTPRoute,
 ) (bool, string, error) {
-	var routeValidator routeValidator = noOpRoutesValidator{}
-	if routesSvc, ok := validator.AdminAPIServicesProvider.GetRoutesService(); ok {
-		routeValidator = routesSvc
+	valid, msg, err := gatewayvalidation.ValidateHTTPRoute(
+		ctx, validator.routesValidator(), validator.TranslatorFeatures, &httproute, validator.ManagerClient,
+	)
+	if err != nil || !valid || !validator.RejectRouteConflicts {
+		return valid, msg, err
 	}
-	return gatewayvalidation.ValidateHTTPRoute(
-		ctx, routeValidator, validator.TranslatorFeatures, &httproute, validator.ManagerClient,
+	return gatewayvalidation.ValidateHTTPRouteConflicts(ctx, &httproute, validator.ManagerClient, validator.Storer)
+}
+
+func (validator KongHTTPValidator) ValidateGRPCRoute(
+	ctx context.Context, grpcroute gatewayapi.GRPCRoute,
+) (bool, string, error) {
+	return gatewayvalidation.ValidateGRPCRoute(
+		ctx, validator.routesValidator(), validator.TranslatorFeatures, &grpcroute, validator.ManagerClient,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateTCPRoute(
+	ctx context.Context, tcproute gatewayapi.TCPRoute,
+) (bool, string, error) {
+	return gatewayvalidation.ValidateTCPRoute(
+		ctx, validator.routesValidator(), validator.TranslatorFeatures, &tcproute, validator.ManagerClient,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateTLSRoute(
+	ctx context.Context, tlsroute gatewayapi.TLSRoute,
+) (bool, string, error) {
+	return gatewayvalidation.ValidateTLSRoute(
+		ctx, validator.routesValidator(), validator.TranslatorFeatures, &tlsroute, validator.ManagerClient,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateUDPRoute(
+	ctx context.Context, udproute gatewayapi.UDPRoute,
+) (bool, string, error) {
+	return gatewayvalidation.ValidateUDPRoute(
+		ctx, validator.routesValidator(), validator.TranslatorFeatures, &udproute, validator.ManagerClient,
 	)
 }
 
@@ -462,17 +504,32 @@ func (validator KongHTTPValidator) ValidateIngress(
 		return true, "", nil
 	}
 
-	var routeValidator routeValidator = noOpRoutesValidator{}
-	if routesSvc, ok := validator.AdminAPIServicesProvider.GetRoutesService(); ok {
-		routeValidator = routesSvc
+	valid, msg, err := ingressvalidation.ValidateIngress(ctx, validator.routesValidator(), validator.TranslatorFeatures, &ingress, validator.Logger, validator.Storer)
+	if err != nil || !valid || !validator.RejectRouteConflicts {
+		return valid, msg, err
+	}
+	conflictsMsg, err := validation.ValidateRouteConflicts(&ingress, validator.Storer)
+	if err != nil {
+		return false, "", err
 	}
-	return ingressvalidation.ValidateIngress(ctx, routeValidator, validator.TranslatorFeatures, &ingress, validator.Logger, validator.Storer)
+	if conflictsMsg != "" {
+		return false, fmt.Sprintf("Ingress conflicts with existing routes: %s", conflictsMsg), nil
+	}
+	return true, "", nil
 }
 
 type routeValidator interface {
 	Validate(context.Context, *kong.Route) (bool, string, error)
 }
 
+// routesValidator returns the validator of Kong routes, a no-op one when the Admin API is not available.
+func (validator KongHTTPValidator) routesValidator() routeValidator {
+	if routesSvc, ok := validator.AdminAPIServicesProvider.GetRoutesService(); ok {
+		return routesSvc
+	}
+	return noOpRoutesValidator{}
+}
+
 type noOpRoutesValidator struct{}
 
 func (noOpRoutesValidator) Validate(_ context.Context, _ *kong.Route) (bool, string, error) {
@@ -514,6 +571,20 @@ func (validator KongHTTPValidator) ValidateVault(ctx context.Context, k8sKongVau
 	return true, "", nil
 }
 
+// ValidateKongUpstreamPolicy checks if the KongUpstreamPolicy translates to a valid Kong upstream
+// and doesn't conflict with Services it's applied to.
+func (validator KongHTTPValidator) ValidateKongUpstreamPolicy(
+	ctx context.Context, policy configurationv1beta1.KongUpstreamPolicy,
+) (bool, string, error) {
+	var upstreamsValidator kongupstreampolicyvalidation.UpstreamValidator
+	if schemaService, ok := validator.AdminAPIServicesProvider.GetSchemasService(); ok {
+		upstreamsValidator = schemaService
+	}
+	return kongupstreampolicyvalidation.ValidateKongUpstreamPolicy(
+		ctx, upstreamsValidator, &policy, validator.ManagerClient, validator.Storer,
+	)
+}
+
 // -----------------------------------------------------------------------------
 // KongHTTPValidator - Private Methods
 // -----------------------------------------------------------------------------
@@ -12,6 +12,7 @@ import (
 	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
 	"github.com/kong/kong-operator/ingress-controller/internal/controllers/gateway"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
 	"github.com/kong/kong-operator/ingress-controller/internal/konnect"
 	"github.com/kong/kong-operator/ingress-controller/internal/license"
 	"github.com/kong/kong-operator/ingress-controller/internal/manager/consts"
@@ -71,6 +72,10 @@ func (c *CLIConfig) bindFlagSet() {
 	// TODO: When FallbackConfiguration graduates we should remove the feature gate mention from the help text.
 	// https://github.com/Kong/kubernetes-ingress-controller/issues/6170
 	flagSet.BoolVar(&c.UseLastValidConfigForFallback, "use-last-valid-config-for-fallback", false, fmt.Sprintf(`When recovering from config push failures, use the last valid configuration cache to backfill broken objects. It can only be used with the %s feature gate enabled.`, managercfg.FallbackConfigurationFeature))
+	flagSet.Var(flags.NewValidatedValue(&c.LastValidConfigSecret, namespacedNameFromFlagValue, nnTypeNameOverride), "last-valid-config-secret",
+		`Secret namespaced name in "namespace/name" format, to persist the last valid configuration in, so it's used to recover from config push failures after restarts.`)
+	flagSet.DurationVar(&c.IncrementalTranslationFullRebuildInterval, "incremental-translation-full-rebuild-interval", translator.DefaultIncrementalTranslationFullRebuildInterval,
+		fmt.Sprintf(`Interval in which all Kubernetes objects are re-translated regardless of changes. It's only used with the %s feature gate enabled.`, managercfg.IncrementalTranslationFeature))
 	// Default has to be explicitly passed to generate the proper docs. See https://github.com/kubernetes-sigs/controller-runtime/blob/f1c5dd3851ce3df8b4b7830d9b6eae6271f6932d/pkg/cache/cache.go#L146-L151.
 	flagSet.DurationVar(&c.SyncPeriod, "sync-period", 10*time.Hour, `Determine the minimum frequency at which watched resources are reconciled. Set to 0 to use default from controller-runtime.`)
 	flagSet.BoolVar(&c.SkipCACertificates, "skip-ca-certificates", false, `Disable syncing CA certificate syncing (for use with multi-workspace environments).`)
@@ -175,6 +180,8 @@ func (c *CLIConfig) bindFlagSet() {
 		`Admission server PEM certificate value. Mutually exclusive with --admission-webhook-cert-file.`)
 	flagSet.StringVar(&c.AdmissionServer.Key, "admission-webhook-key", "",
 		`Admission server PEM private key value. Mutually exclusive with --admission-webhook-key-file.`)
+	flagSet.BoolVar(&c.AdmissionServer.RejectRouteConflicts, "admission-webhook-reject-route-conflicts", false,
+		`Reject HTTPRoutes and Ingresses with matches conflicting with matches of existing HTTPRoutes and Ingresses routing the same requests to different backends.`)
 
 	// Diagnostics
 	flagSet.BoolVar(&c.EnableProfiling, "profiling", false, fmt.Sprintf("Enable profiling via web interface host:%v/debug/pprof/.", consts.DiagnosticsPort))
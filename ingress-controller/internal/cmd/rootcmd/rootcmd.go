@@ -15,10 +15,11 @@ import (
 // Execute is the entry point to the controller manager.
 func Execute() {
 	var (
-		rootCmd    = GetRootCmd()
-		versionCmd = GetVersionCmd()
+		rootCmd      = GetRootCmd()
+		versionCmd   = GetVersionCmd()
+		translateCmd = GetTranslateCmd()
 	)
-	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(versionCmd, translateCmd)
 	cobra.CheckErr(rootCmd.Execute())
 }
 
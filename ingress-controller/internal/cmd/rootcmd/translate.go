@@ -0,0 +1,203 @@
+package rootcmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	dpconf "github.com/kong/kong-operator/ingress-controller/internal/dataplane/config"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/offline"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/manager/consts"
+	"github.com/kong/kong-operator/ingress-controller/internal/manager/flags"
+	"github.com/kong/kong-operator/ingress-controller/internal/versions"
+	managercfg "github.com/kong/kong-operator/ingress-controller/pkg/manager/config"
+)
+
+// translateOptions are the options of the translate command.
+type translateOptions struct {
+	filenames                               []string
+	namespace                               string
+	outputFormat                            string
+	kongVersion                             string
+	routerFlavor                            string
+	enterprise                              bool
+	ingressClass                            string
+	clusterDomain                           string
+	enableDrainSupport                      bool
+	combinedServicesFromDifferentHTTPRoutes bool
+	featureGates                            managercfg.FeatureGates
+	failOnTranslationFailures               bool
+}
+
+// GetTranslateCmd returns the command rendering Kong configuration from Kubernetes manifests.
+func GetTranslateCmd() *cobra.Command {
+	var opts translateOptions
+
+	cmd := &cobra.Command{
+		Use:   "translate",
+		Short: "Render Kong declarative configuration from Kubernetes manifests",
+		Long: "Render Kong declarative configuration from Ingress, Gateway API and Kong CRD manifests the same way " +
+			"the controller translates objects, without a Kubernetes cluster nor a Kong Gateway. " +
+			"Translation failures and objects of kinds that are not translated are reported on stderr. " +
+			"All routes are translated, regardless of the Gateways they are attached to, and defaults of " +
+			"plugins' configuration are not filled as plugin schemas are only available from a running Kong Gateway.",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runTranslate(cmd.Context(), opts, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+		SilenceUsage: true,
+	}
+	flagSet := cmd.Flags()
+	flagSet.StringSliceVarP(&opts.filenames, "filename", "f", nil, `Manifest files or directories (not recursive) with manifests to translate, "-" reads from stdin.`)
+	flagSet.StringVarP(&opts.namespace, "namespace", "n", "default", "The namespace of namespaced objects that don't specify one.")
+	flagSet.StringVarP(&opts.outputFormat, "output", "o", string(offline.FormatDeck), fmt.Sprintf("The format of the rendered configuration, one of: %s, %s.", offline.FormatDeck, offline.FormatDBLess))
+	flagSet.StringVar(&opts.kongVersion, "kong-version", "3.11.0", "The version of the Kong Gateway the configuration is rendered for.")
+	flagSet.StringVar(&opts.routerFlavor, "router-flavor", string(dpconf.RouterFlavorTraditionalCompatible), fmt.Sprintf(
+		"The router flavor of the Kong Gateway, one of: %s, %s, %s.",
+		dpconf.RouterFlavorTraditional, dpconf.RouterFlavorTraditionalCompatible, dpconf.RouterFlavorExpressions,
+	))
+	flagSet.BoolVar(&opts.enterprise, "enterprise", false, "Render configuration for the Kong Gateway Enterprise edition.")
+	flagSet.StringVar(&opts.ingressClass, "ingress-class", annotations.DefaultIngressClass, "Name of the ingress class of the translated objects.")
+	flagSet.StringVar(&opts.clusterDomain, "cluster-domain", managercfg.DefaultClusterDomain, "The cluster domain used in addresses of upstream services.")
+	flagSet.BoolVar(&opts.enableDrainSupport, "enable-drain-support", consts.DefaultEnableDrainSupport, "Include terminating endpoints in Kong upstreams with weight=0.")
+	flagSet.BoolVar(&opts.combinedServicesFromDifferentHTTPRoutes, "combined-services-from-different-httproutes", false, "Combine rules from different HTTPRoutes that are sharing the same combination of backends to one Kong service.")
+	flagSet.Var(flags.NewMapStringBoolForFeatureGatesWithDefaults(&opts.featureGates), "feature-gates", "A set of comma separated key=value pairs that describe feature gates for alpha/beta/experimental features. "+
+		fmt.Sprintf("See the Feature Gates documentation for information and available options: %s.", managercfg.DocsURL))
+	flagSet.BoolVar(&opts.failOnTranslationFailures, "fail-on-translation-failures", false, "Exit with an error when any object fails to be translated.")
+	_ = cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+func runTranslate(ctx context.Context, opts translateOptions, stdin io.Reader, stdout, stderr io.Writer) error {
+	routerFlavor := dpconf.RouterFlavor(opts.routerFlavor)
+	if !slices.Contains([]dpconf.RouterFlavor{
+		dpconf.RouterFlavorTraditional, dpconf.RouterFlavorTraditionalCompatible, dpconf.RouterFlavorExpressions,
+	}, routerFlavor) {
+		return fmt.Errorf("unsupported router flavor %q", opts.routerFlavor)
+	}
+	kongVersion, err := semver.ParseTolerant(opts.kongVersion)
+	if err != nil {
+		return fmt.Errorf("invalid Kong version %q: %w", opts.kongVersion, err)
+	}
+
+	manifests, closeManifests, err := openManifests(opts.filenames, stdin)
+	if err != nil {
+		return err
+	}
+	defer closeManifests()
+	loaded, err := offline.LoadManifests(opts.namespace, manifests...)
+	if err != nil {
+		return err
+	}
+	for _, skipped := range loaded.Skipped {
+		fmt.Fprintf(stderr, "skipped %s: kind is not translated\n", skipped)
+	}
+
+	result, err := offline.Translate(ctx, logr.Discard(), loaded.CacheStores, offline.Config{
+		FeatureFlags: translator.NewFeatureFlags(
+			opts.featureGates,
+			routerFlavor,
+			false,
+			opts.enterprise,
+			kongVersion.GTE(versions.KongRedirectPluginCutoff),
+			opts.combinedServicesFromDifferentHTTPRoutes,
+		),
+		KongVersion:        kongVersion,
+		IngressClass:       opts.ingressClass,
+		ClusterDomain:      opts.clusterDomain,
+		EnableDrainSupport: opts.enableDrainSupport,
+	})
+	if err != nil {
+		return err
+	}
+	out, err := result.Marshal(offline.Format(opts.outputFormat))
+	if err != nil {
+		return err
+	}
+	if _, err := stdout.Write(out); err != nil {
+		return err
+	}
+
+	for _, failure := range result.TranslationFailures {
+		objects := make([]string, 0, len(failure.CausingObjects()))
+		for _, obj := range failure.CausingObjects() {
+			objects = append(objects, fmt.Sprintf("%s %s/%s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName()))
+		}
+		fmt.Fprintf(stderr, "translation failure: %s (objects: %s)\n", failure.Message(), strings.Join(objects, ", "))
+	}
+	if opts.failOnTranslationFailures && len(result.TranslationFailures) > 0 {
+		return fmt.Errorf("%d translation failures", len(result.TranslationFailures))
+	}
+	return nil
+}
+
+// openManifests opens the manifest files, the files in the directories and stdin for "-".
+// The returned function closes all the opened files.
+func openManifests(filenames []string, stdin io.Reader) ([]io.Reader, func(), error) {
+	var (
+		readers []io.Reader
+		files   []*os.File
+	)
+	closeAll := func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}
+	open := func(path string) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+		return nil
+	}
+
+	for _, filename := range filenames {
+		if filename == "-" {
+			readers = append(readers, stdin)
+			continue
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		if !info.IsDir() {
+			if err := open(filename); err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			continue
+		}
+		entries, err := os.ReadDir(filename)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+			default:
+				continue
+			}
+			if entry.IsDir() {
+				continue
+			}
+			if err := open(filepath.Join(filename, entry.Name())); err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+		}
+	}
+	return readers, closeAll, nil
+}
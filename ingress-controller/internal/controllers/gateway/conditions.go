@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"strings"
 
 	"github.com/samber/lo"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -146,3 +147,60 @@ func ensureParentsProgrammedCondition[
 	// no need to update if no status is changed.
 	return false, nil
 }
+
+// ensureParentsConflictedCondition ensures that provided route's existing parent
+// statuses have Conflicted condition set when any of the route's matches conflict
+// with matches of other routes, and that the condition is removed otherwise.
+// It returns a boolean flag indicating whether an update to the provided route
+// has been performed.
+func ensureParentsConflictedCondition[
+	routeT gatewayapi.RouteT,
+](
+	ctx context.Context,
+	client client.SubResourceWriter,
+	route routeT,
+	routeParentStatuses []gatewayapi.RouteParentStatus,
+	gateways []supportedGatewayWithCondition,
+	conflicts []string,
+) (bool, error) {
+	parentStatuses := getParentStatuses(route, routeParentStatuses)
+
+	condition := newCondition(
+		ConditionTypeConflicted,
+		metav1.ConditionTrue,
+		string(ConditionReasonMatchConflict),
+		route.GetGeneration(),
+	)
+	condition.Message = strings.Join(conflicts, "; ")
+
+	statusChanged := false
+	for _, g := range gateways {
+		parentStatus, ok := parentStatuses[routeParentStatusKey(route, g)]
+		if !ok {
+			continue
+		}
+
+		var changed bool
+		if len(conflicts) > 0 {
+			changed = setRouteParentStatusCondition(parentStatus, condition)
+		} else {
+			conditions := lo.Reject(parentStatus.Conditions, func(c metav1.Condition, _ int) bool {
+				return c.Type == ConditionTypeConflicted
+			})
+			changed = len(conditions) != len(parentStatus.Conditions)
+			parentStatus.Conditions = conditions
+		}
+		if changed {
+			setRouteParentInStatusForParent(route, *parentStatus, g)
+		}
+		statusChanged = statusChanged || changed
+	}
+
+	if !statusChanged {
+		return false, nil
+	}
+	if err := client.Update(ctx, route); err != nil {
+		return false, err
+	}
+	return true, nil
+}
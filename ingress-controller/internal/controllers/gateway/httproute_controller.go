@@ -505,6 +505,19 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			debug(log, httproute, "Programmed condition updated")
 			return ctrl.Result{}, nil
 		}
+
+		// report matches conflicting with matches of older routes routing the same requests
+		// to different backends, the requests are not routed by this HTTPRoute.
+		conflicts := r.DataplaneClient.KubernetesObjectRouteConflicts(httproute)
+		statusUpdated, err = ensureParentsConflictedCondition(ctx, r.Status(), httproute, httproute.Status.Parents, gateways, conflicts)
+		if err != nil {
+			debug(log, httproute, "Failed to update conflicted condition")
+			return ctrl.Result{}, err
+		}
+		if statusUpdated {
+			debug(log, httproute, "Conflicted condition updated")
+			return ctrl.Result{}, nil
+		}
 	}
 
 	// once the data-plane has accepted the HTTPRoute object, we're all set.
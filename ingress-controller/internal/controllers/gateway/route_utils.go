@@ -32,6 +32,9 @@ const (
 	ConditionReasonProgrammedUnknown   gatewayapi.RouteConditionReason = "Unknown"
 	ConditionReasonConfiguredInGateway gatewayapi.RouteConditionReason = "ConfiguredInGateway"
 	ConditionReasonTranslationError    gatewayapi.RouteConditionReason = "TranslationError"
+
+	ConditionTypeConflicted                                      = "Conflicted"
+	ConditionReasonMatchConflict gatewayapi.RouteConditionReason = "MatchConflict"
 )
 
 var (
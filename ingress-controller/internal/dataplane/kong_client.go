@@ -50,6 +50,9 @@ const (
 	KongConfigurationTranslationFailedEventReason = "KongConfigurationTranslationFailed"
 	// KongConfigurationApplyFailedEventReason defines an event reason used for creating all config apply resource failure events.
 	KongConfigurationApplyFailedEventReason = "KongConfigurationApplyFailed"
+	// KongRouteConflictEventReason defines an event reason used for creating events for routes losing conflicts
+	// with other routes matching the same requests.
+	KongRouteConflictEventReason = "KongRouteConflict"
 
 	// FallbackKongConfigurationApplySucceededEventReason defines an event reason to tell the updating of fallback Kong configuration succeeded.
 	FallbackKongConfigurationApplySucceededEventReason = "FallbackKongConfigurationSucceeded"
@@ -91,6 +94,12 @@ type FallbackConfigGenerator interface {
 	) (store.CacheStores, fallback.GeneratedCacheMetadata, error)
 }
 
+// LastValidConfigStore persists the last valid configuration, so it survives restarts.
+type LastValidConfigStore interface {
+	Store(s *kongstate.KongState)
+	Load(ctx context.Context) (*kongstate.KongState, bool, error)
+}
+
 // KonnectKongStateUpdater is an interface for updating the current state of configuration seen by Konnect.
 type KonnectKongStateUpdater interface {
 	UpdateKongState(kongState *kongstate.KongState, isFallback bool)
@@ -149,9 +158,17 @@ type KongClient struct {
 	// is actively configured (e.g. to know how to set the object status).
 	kubernetesObjectReportsFilter k8sobj.ConfigurationStatusSet
 
+	// kubernetesObjectRouteConflicts are conflicts of HTTPRoutes and Ingresses losing conflicts with other
+	// routes in the most recent Update(), by the objects' keys.
+	kubernetesObjectRouteConflicts map[string]objectRouteConflicts
+
 	// eventRecorder is used to record warning events for resource failures.
 	eventRecorder record.EventRecorder
 
+	// recordedRouteConflicts is the set of route conflicts Events were recorded for in the last update.
+	// It's used to record Events only for conflicts that appear, not on every update.
+	recordedRouteConflicts map[string]struct{}
+
 	// SHAs is a slice is configuration hashes send in last batch send.
 	SHAs []string
 
@@ -192,6 +209,12 @@ type KongClient struct {
 	// lastValidCacheSnapshot can also represent the fallback cache snapshot that was successfully synced with gateways.
 	lastValidCacheSnapshot *store.CacheStores
 
+	// lastValidConfigStore persists the last valid configuration successfully synced with the gateways.
+	lastValidConfigStore LastValidConfigStore
+
+	// lastValidConfigRestored indicates whether restoring the persisted last valid configuration has been attempted.
+	lastValidConfigRestored bool
+
 	// konnectKongStateUpdater is used to update the current state seen by Konnect that will be picked asynchronously
 	// by the Konnect config synchronization loop.
 	konnectKongStateUpdater KonnectKongStateUpdater
@@ -207,6 +230,13 @@ func WithDiagnosticsClient(diagnostic diagnostics.Client) func(*KongClient) {
 	}
 }
 
+// WithLastValidConfigStore sets the store persisting the last valid configuration for the KongClient.
+func WithLastValidConfigStore(s LastValidConfigStore) func(*KongClient) {
+	return func(c *KongClient) {
+		c.lastValidConfigStore = s
+	}
+}
+
 // NewKongClient provides a new KongClient object after connecting to the
 // data-plane API and verifying integrity.
 func NewKongClient(
@@ -442,6 +472,14 @@ func (c *KongClient) KubernetesObjectConfigurationStatus(obj client.Object) k8so
 	return c.kubernetesObjectReportsFilter.Get(obj)
 }
 
+// KubernetesObjectRouteConflicts returns messages describing conflicts the provided HTTPRoute or Ingress
+// lost to other routes matching the same requests in the most recently applied configuration.
+func (c *KongClient) KubernetesObjectRouteConflicts(obj client.Object) []string {
+	c.kubernetesObjectReportLock.RLock()
+	defer c.kubernetesObjectReportLock.RUnlock()
+	return c.kubernetesObjectRouteConflicts[objectKey(obj)].messages
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Interface Implementation
 // -----------------------------------------------------------------------------
@@ -474,6 +512,10 @@ func (c *KongClient) Update(ctx context.Context) error {
 		}
 	}
 
+	// Restore the persisted last valid configuration so it can be used for recovering from configuration
+	// rejections even when the controller has been restarted with broken objects in the cluster.
+	c.maybeRestoreLastValidConfig(ctx)
+
 	// If FallbackConfiguration is enabled, we take a snapshot of the cache so that we operate on a consistent
 	// set of resources in case of failures being returned from Kong. As we're going to generate a fallback config
 	// based on the cache contents, we need to ensure it is not modified during the process.
@@ -522,6 +564,10 @@ func (c *KongClient) Update(ctx context.Context) error {
 		c.metricsRecorder.RecordTranslationBrokenResources(0)
 		c.logger.V(logging.DebugLevel).Info("Successfully built data-plane configuration", "duration", translationDuration.String())
 	}
+	c.recordRouteConflictEvents(parsingResult.RouteConflicts)
+	if err := c.maybeSendTranslationDiagnostics(ctx, cacheSnapshot, parsingResult.TranslationFailures); err != nil {
+		return fmt.Errorf("failed to send translation diagnostics: %w", err)
+	}
 
 	const isFallback = false
 	shas, gatewaysSyncErr := c.sendOutToGatewayClients(ctx, parsingResult.KongState, c.kongConfig, isFallback)
@@ -559,7 +605,7 @@ func (c *KongClient) Update(ctx context.Context) error {
 		if !slices.Equal(shas, c.SHAs) {
 			c.logger.V(logging.DebugLevel).Info("Triggering report for configured Kubernetes objects", "count",
 				len(parsingResult.ConfiguredKubernetesObjects))
-			c.triggerKubernetesObjectReport(parsingResult.ConfiguredKubernetesObjects, parsingResult.TranslationFailures)
+			c.triggerKubernetesObjectReport(parsingResult.ConfiguredKubernetesObjects, parsingResult.TranslationFailures, parsingResult.RouteConflicts)
 		} else {
 			c.logger.V(logging.DebugLevel).Info("No configuration change; resource status update not necessary, skipping")
 		}
@@ -576,6 +622,35 @@ func (c *KongClient) maybePreserveTheLastValidConfigCache(lastValidCache store.C
 	}
 }
 
+// maybeRestoreLastValidConfig restores the persisted last valid configuration once if a LastValidConfigStore
+// is configured, unless a last valid configuration has already been fetched from the gateways. The restored
+// configuration is pushed to the gateways when they reject the current one and no fallback configuration
+// could be applied. It doesn't depend on the `FallbackConfiguration` feature gate: the configuration is
+// persisted and restored regardless of it, but the cache used for backfilling broken objects with the
+// `--use-last-valid-config-for-fallback` flag is only ever preserved in memory.
+func (c *KongClient) maybeRestoreLastValidConfig(ctx context.Context) {
+	if c.lastValidConfigStore == nil || c.lastValidConfigRestored {
+		return
+	}
+	c.lastValidConfigRestored = true
+
+	if _, found := c.kongConfigFetcher.LastValidConfig(); found {
+		c.logger.V(logging.DebugLevel).Info("Last valid configuration already available, skipping restoring the persisted one")
+		return
+	}
+	lastValidConfig, found, err := c.lastValidConfigStore.Load(ctx)
+	if err != nil {
+		c.logger.Error(err, "Failed to restore the persisted last valid configuration")
+		return
+	}
+	if !found {
+		c.logger.V(logging.DebugLevel).Info("No persisted last valid configuration found")
+		return
+	}
+	c.kongConfigFetcher.StoreLastValidConfig(lastValidConfig)
+	c.logger.Info("Restored the persisted last valid configuration")
+}
+
 // maybeTryRecoveringFromGatewaysSyncError tries to recover from a configuration rejection if the error is of the expected
 // UpdateError type. Otherwise, we assume the error is non-recoverable by means of fallback configuration generation
 // or applying the last valid configuration, and we need to rely on the retry mechanism. It can happen in case of
@@ -674,7 +749,9 @@ func (c *KongClient) tryRecoveringWithFallbackConfiguration(
 	if c.AreKubernetesObjectReportsEnabled() {
 		c.logger.V(logging.DebugLevel).Info("Triggering report for configured Kubernetes objects in fallback configuration",
 			"count", len(fallbackParsingResult.ConfiguredKubernetesObjects))
-		c.triggerKubernetesObjectReport(fallbackParsingResult.ConfiguredKubernetesObjects, fallbackParsingResult.TranslationFailures)
+		c.triggerKubernetesObjectReport(
+			fallbackParsingResult.ConfiguredKubernetesObjects, fallbackParsingResult.TranslationFailures, fallbackParsingResult.RouteConflicts,
+		)
 	}
 
 	// Configuration was successfully recovered with the fallback configuration. Store the last valid configuration.
@@ -768,6 +845,9 @@ func (c *KongClient) sendOutToGatewayClients(
 	c.SHAs = shas
 
 	c.kongConfigFetcher.StoreLastValidConfig(s)
+	if c.lastValidConfigStore != nil {
+		c.lastValidConfigStore.Store(s)
+	}
 
 	return previousSHAs, nil
 }
@@ -939,8 +1019,13 @@ func prepareSendDiagnosticFn(
 // triggerKubernetesObjectReport will update the KongClient with a set which
 // enables filtering for which objects are currently applied to the data-plane,
 // as well as updating the c.kubernetesObjectStatusQueue to queue those objects
-// for reconciliation so their statuses can be properly updated.
-func (c *KongClient) triggerKubernetesObjectReport(reportedObjects []client.Object, translationFailures []failures.ResourceFailure) {
+// for reconciliation so their statuses can be properly updated. Objects which started
+// or stopped losing route conflicts are queued as well.
+func (c *KongClient) triggerKubernetesObjectReport(
+	reportedObjects []client.Object,
+	translationFailures []failures.ResourceFailure,
+	routeConflicts []translator.RouteConflict,
+) {
 	// first a new set of the included objects for the most recent configuration
 	// needs to be generated.
 	set := k8sobj.ConfigurationStatusSet{}
@@ -958,12 +1043,13 @@ func (c *KongClient) triggerKubernetesObjectReport(reportedObjects []client.Obje
 	}
 
 	c.updateKubernetesObjectReportFilter(set)
+	changedConflictsObjects := c.updateKubernetesObjectRouteConflicts(routeConflicts)
 
 	// after the filter has been updated we signal the status queue so that the
 	// control-plane can update the Kubernetes object statuses for affected objs.
 	// this has to be done in a separate loop so that the filter is in place
 	// before the objects are enqueued, as the filter is used by the control-plane
-	for _, obj := range UniqueObjects(reportedObjects, translationFailures) {
+	for _, obj := range UniqueObjects(slices.Concat(reportedObjects, changedConflictsObjects), translationFailures) {
 		c.kubernetesObjectStatusQueue.Publish(obj)
 	}
 }
@@ -973,10 +1059,47 @@ func UniqueObjects(reportedObjects []client.Object, resourceFailures []failures.
 		return f.CausingObjects()
 	})
 	allObjects := slices.Concat(reportedObjects, allCausingObjects)
-	return lo.UniqBy(allObjects, func(obj client.Object) string {
-		return obj.GetObjectKind().GroupVersionKind().String() + "/" +
-			obj.GetNamespace() + "/" + obj.GetName()
-	})
+	return lo.UniqBy(allObjects, objectKey)
+}
+
+func objectKey(obj client.Object) string {
+	return obj.GetObjectKind().GroupVersionKind().String() + "/" +
+		obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// objectRouteConflicts are messages describing route conflicts an object lost.
+type objectRouteConflicts struct {
+	object   client.Object
+	messages []string
+}
+
+// updateKubernetesObjectRouteConflicts overrides the internal route conflicts with the provided ones.
+// It returns objects whose conflicts have changed.
+func (c *KongClient) updateKubernetesObjectRouteConflicts(routeConflicts []translator.RouteConflict) []client.Object {
+	conflicts := make(map[string]objectRouteConflicts)
+	for _, conflict := range routeConflicts {
+		key := objectKey(conflict.Object)
+		objConflicts := conflicts[key]
+		objConflicts.object = conflict.Object
+		objConflicts.messages = append(objConflicts.messages, conflict.Message())
+		conflicts[key] = objConflicts
+	}
+
+	c.kubernetesObjectReportLock.Lock()
+	defer c.kubernetesObjectReportLock.Unlock()
+	var changed []client.Object
+	for key, objConflicts := range conflicts {
+		if !slices.Equal(c.kubernetesObjectRouteConflicts[key].messages, objConflicts.messages) {
+			changed = append(changed, objConflicts.object)
+		}
+	}
+	for key, objConflicts := range c.kubernetesObjectRouteConflicts {
+		if _, ok := conflicts[key]; !ok {
+			changed = append(changed, objConflicts.object)
+		}
+	}
+	c.kubernetesObjectRouteConflicts = conflicts
+	return changed
 }
 
 // updateKubernetesObjectReportFilter overrides the internal object set with
@@ -1007,6 +1130,37 @@ func (c *KongClient) recordResourceFailureEvents(resourceFailures []failures.Res
 	}
 }
 
+// recordRouteConflictEvents records warning Events for objects losing route conflicts. Events are only recorded
+// for conflicts that weren't present in the previous update, so a persisting conflict doesn't produce an Event
+// on every update.
+func (c *KongClient) recordRouteConflictEvents(routeConflicts []translator.RouteConflict) {
+	recorded := make(map[string]struct{}, len(routeConflicts))
+	defer func() { c.recordedRouteConflicts = recorded }()
+
+	for _, conflict := range routeConflicts {
+		key := routeConflictKey(conflict)
+		recorded[key] = struct{}{}
+		if _, ok := c.recordedRouteConflicts[key]; ok {
+			continue
+		}
+		c.logger.V(logging.DebugLevel).Info(
+			"route lost a conflict with another route - recording a Warning event for object",
+			"name", conflict.Object.GetName(),
+			"namespace", conflict.Object.GetNamespace(),
+			"kind", conflict.Kind,
+			"message", conflict.Message(),
+		)
+		c.eventRecorder.Event(conflict.Object, corev1.EventTypeWarning, KongRouteConflictEventReason, conflict.Message())
+	}
+}
+
+// routeConflictKey identifies a route conflict by the losing object and the conflict's description.
+func routeConflictKey(conflict translator.RouteConflict) string {
+	return fmt.Sprintf("%s/%s/%s/%s: %s",
+		conflict.Kind, conflict.Object.GetNamespace(), conflict.Object.GetName(), conflict.Object.GetUID(), conflict.Message(),
+	)
+}
+
 // recordApplyConfigurationEvents records event attached to KIC pod after KIC applied Kong configuration.
 func (c *KongClient) recordApplyConfigurationEvents(err error, rootURL string, isFallback bool) {
 	podNN, ok := c.controllerPodReference.Get()
@@ -1093,3 +1247,27 @@ func (c *KongClient) maybeSendFallbackConfigDiagnostics(ctx context.Context, gen
 	}
 	return nil
 }
+
+// maybeSendTranslationDiagnostics sends the translation failures along with the objects they were produced from
+// to the diagnostics server. cacheSnapshot is used when available, the live cache otherwise.
+func (c *KongClient) maybeSendTranslationDiagnostics(
+	ctx context.Context, cacheSnapshot store.CacheStores, translationFailures []failures.ResourceFailure,
+) error {
+	ch := c.diagnostic.Translations
+	if ch == nil {
+		return nil
+	}
+	cache := cacheSnapshot
+	if !cache.Available() {
+		cache = *c.cache
+	}
+	select {
+	case ch <- diagnostics.TranslationDiagnostic{TranslationFailures: translationFailures, Cache: cache}:
+		c.logger.V(logging.DebugLevel).Info("Shipping translation diagnostics to diagnostics server")
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		c.logger.Error(nil, "Translation diagnostics buffer full, dropping diagnostics")
+	}
+	return nil
+}
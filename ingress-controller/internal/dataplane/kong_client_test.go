@@ -1440,6 +1440,149 @@ func TestKongClient_LastValidCacheSnapshot(t *testing.T) {
 	}
 }
 
+type mockLastValidConfigStore struct {
+	persisted   *kongstate.KongState
+	loadCalls   int
+	storedCalls int
+}
+
+func (m *mockLastValidConfigStore) Store(s *kongstate.KongState) {
+	m.storedCalls++
+	m.persisted = s
+}
+
+func (m *mockLastValidConfigStore) Load(context.Context) (*kongstate.KongState, bool, error) {
+	m.loadCalls++
+	if m.persisted == nil {
+		return nil, false, nil
+	}
+	return m.persisted, true, nil
+}
+
+func TestKongClient_LastValidConfigStore(t *testing.T) {
+	persistedConfig := &kongstate.KongState{
+		Services: []kongstate.Service{
+			{
+				Service: kong.Service{
+					Name: kong.String("persisted"),
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                  string
+		fallbackConfiguration bool
+		lastValidConfig       *kongstate.KongState
+		expectRestored        bool
+	}{
+		{
+			name:                  "persisted config is restored with FallbackConfiguration enabled",
+			fallbackConfiguration: true,
+			expectRestored:        true,
+		},
+		{
+			name:           "persisted config is restored with FallbackConfiguration disabled",
+			expectRestored: true,
+		},
+		{
+			name:            "persisted config is not restored when last valid config was fetched from gateways",
+			lastValidConfig: &kongstate.KongState{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				ctx                    = t.Context()
+				updateStrategyResolver = mocks.NewUpdateStrategyResolver()
+				configChangeDetector   = mocks.ConfigurationChangeDetector{ConfigurationChanged: true}
+				configBuilder          = newMockKongConfigBuilder()
+				lastValidConfigFetcher = &mockKongLastValidConfigFetcher{lastKongState: tc.lastValidConfig}
+				originalCache          = cacheStoresFromObjs(t)
+				lastValidConfigStore   = &mockLastValidConfigStore{persisted: persistedConfig}
+				clientsProvider        = &mockGatewayClientsProvider{
+					gatewayClients: []*adminapi.Client{mustSampleGatewayClient(t)},
+				}
+			)
+			kongClient, err := NewKongClient(
+				zapr.NewLogger(zap.NewNop()),
+				time.Second,
+				sendconfig.Config{
+					FallbackConfiguration: tc.fallbackConfiguration,
+				},
+				mocks.NewEventRecorder(),
+				dpconf.DBModeOff,
+				clientsProvider,
+				updateStrategyResolver,
+				configChangeDetector,
+				lastValidConfigFetcher,
+				configBuilder,
+				&originalCache,
+				newMockFallbackConfigGenerator(),
+				mocks.MetricsRecorder{},
+				WithLastValidConfigStore(lastValidConfigStore),
+			)
+			require.NoError(t, err)
+
+			kongClient.maybeRestoreLastValidConfig(ctx)
+			restoredConfig, _ := lastValidConfigFetcher.LastValidConfig()
+			if tc.expectRestored {
+				t.Log("Verifying that the persisted configuration was restored")
+				require.Equal(t, 1, lastValidConfigStore.loadCalls)
+				require.Same(t, persistedConfig, restoredConfig)
+			} else {
+				t.Log("Verifying that the persisted configuration was not restored")
+				require.Zero(t, lastValidConfigStore.loadCalls)
+				require.Same(t, tc.lastValidConfig, restoredConfig)
+			}
+
+			t.Log("Verifying that the successfully applied configuration was persisted")
+			require.NoError(t, kongClient.Update(ctx))
+			require.Equal(t, 1, lastValidConfigStore.storedCalls)
+			require.Same(t, configBuilder.kongState, lastValidConfigStore.persisted)
+
+			t.Log("Verifying that the persisted configuration is restored only once")
+			require.NoError(t, kongClient.Update(ctx))
+			require.LessOrEqual(t, lastValidConfigStore.loadCalls, 1)
+		})
+	}
+}
+
+func TestKongClient_RecordRouteConflictEvents(t *testing.T) {
+	newConflict := func(name, location string) translator.RouteConflict {
+		return translator.RouteConflict{
+			Object:              &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}},
+			Kind:                "Ingress",
+			Location:            location,
+			ConflictingObject:   &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "winner", Namespace: "default"}},
+			ConflictingKind:     "Ingress",
+			ConflictingLocation: "spec.rules[0].http.paths[0]",
+		}
+	}
+	first := newConflict("first", "spec.rules[0].http.paths[0]")
+	second := newConflict("second", "spec.rules[0].http.paths[0]")
+
+	eventRecorder := mocks.NewEventRecorder()
+	kongClient := &KongClient{
+		logger:        zapr.NewLogger(zap.NewNop()),
+		eventRecorder: eventRecorder,
+	}
+
+	kongClient.recordRouteConflictEvents([]translator.RouteConflict{first})
+	require.Len(t, eventRecorder.Events(), 1, "expected an event for a new conflict")
+
+	kongClient.recordRouteConflictEvents([]translator.RouteConflict{first})
+	require.Len(t, eventRecorder.Events(), 1, "expected no event for an unchanged conflict")
+
+	kongClient.recordRouteConflictEvents([]translator.RouteConflict{first, second})
+	require.Len(t, eventRecorder.Events(), 2, "expected an event only for the conflict that appeared")
+
+	kongClient.recordRouteConflictEvents(nil)
+	kongClient.recordRouteConflictEvents([]translator.RouteConflict{first})
+	require.Len(t, eventRecorder.Events(), 3, "expected an event for a conflict that reappeared")
+}
+
 func cacheStoresFromObjs(t *testing.T, objs ...runtime.Object) store.CacheStores {
 	for i := range objs {
 		obj := objs[i].(client.Object)
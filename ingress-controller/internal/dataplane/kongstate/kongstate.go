@@ -653,6 +653,7 @@ func (ks *KongState) FillPlugins(
 	failuresCollector *failures.ResourceFailuresCollector,
 ) {
 	ks.Plugins = buildPlugins(log, s, failuresCollector, ks.getPluginRelations(s, log, failuresCollector))
+	ks.mergeRoutePreFunctionPlugins()
 }
 
 // FillIDs iterates over the KongState and fills in the ID field for each entity
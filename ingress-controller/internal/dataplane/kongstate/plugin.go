@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"sigs.k8s.io/yaml"
@@ -354,3 +356,96 @@ type PluginRelatedEntitiesRefs struct {
 	RelatedEntities      map[string]RelatedEntitiesRef
 	RouteAttachedService map[string]*Service
 }
+
+// preFunctionPluginName is the name of the Kong plugin running custom Lua code.
+const preFunctionPluginName = "pre-function"
+
+// mergeRoutePreFunctionPlugins merges the pre-function plugins set directly on Routes
+// (e.g. translated from HTTPRoute RequestMirror filters) with the pre-function plugins
+// configured by users through KongPlugins or KongClusterPlugins. Kong only runs the most
+// specific instance of a plugin for a request, so without merging either of them would
+// be ignored.
+// When a user plugin is applied to the Route, the Route's Lua code is prepended to it
+// and the Route's plugin is dropped. Otherwise the Lua code of the user plugin applied
+// to the Route's Service, or globally, is appended to the Route's plugin.
+func (ks *KongState) mergeRoutePreFunctionPlugins() {
+	isUserPreFunction := func(p Plugin) bool {
+		return lo.FromPtr(p.Name) == preFunctionPluginName && p.Consumer == nil && p.ConsumerGroup == nil
+	}
+	findUserPlugin := func(matches func(p Plugin) bool) int {
+		return slices.IndexFunc(ks.Plugins, func(p Plugin) bool {
+			return isUserPreFunction(p) && matches(p)
+		})
+	}
+
+	for i := range ks.Services {
+		svc := &ks.Services[i]
+		for j := range svc.Routes {
+			route := &svc.Routes[j]
+			idx := slices.IndexFunc(route.Plugins, func(p kong.Plugin) bool {
+				return lo.FromPtr(p.Name) == preFunctionPluginName
+			})
+			if idx < 0 {
+				continue
+			}
+			// Route plugins might be shared with other copies of the Route.
+			plugins := slices.Clone(route.Plugins)
+
+			if k := findUserPlugin(func(p Plugin) bool {
+				return p.Route != nil && lo.FromPtr(p.Route.ID) == lo.FromPtr(route.Name)
+			}); k >= 0 {
+				ks.Plugins[k].Config = mergePreFunctionConfigs(plugins[idx].Config, ks.Plugins[k].Config)
+				route.Plugins = slices.Delete(plugins, idx, idx+1)
+				continue
+			}
+
+			k := findUserPlugin(func(p Plugin) bool {
+				return p.Route == nil && p.Service != nil && lo.FromPtr(p.Service.ID) == lo.FromPtr(svc.Name)
+			})
+			if k < 0 {
+				k = findUserPlugin(func(p Plugin) bool {
+					return p.Route == nil && p.Service == nil
+				})
+			}
+			if k >= 0 {
+				plugins[idx] = *plugins[idx].DeepCopy()
+				plugins[idx].Config = mergePreFunctionConfigs(plugins[idx].Config, ks.Plugins[k].Config)
+				route.Plugins = plugins
+			}
+		}
+	}
+}
+
+// mergePreFunctionConfigs returns a pre-function plugin configuration which runs
+// the Lua code of the first configuration before the code of the second one in
+// every phase. Fields other than phases are taken from the second configuration.
+func mergePreFunctionConfigs(first, second kong.Configuration) kong.Configuration {
+	merged := second.DeepCopy()
+	if merged == nil {
+		merged = kong.Configuration{}
+	}
+	for phase := range first {
+		chunks := preFunctionPhaseChunks(first, phase)
+		if len(chunks) == 0 {
+			continue
+		}
+		merged[phase] = append(slices.Clone(chunks), preFunctionPhaseChunks(second, phase)...)
+	}
+	return merged
+}
+
+// preFunctionPhaseChunks returns the Lua code chunks configured for the provided
+// phase in a pre-function plugin configuration.
+func preFunctionPhaseChunks(config kong.Configuration, phase string) []string {
+	switch chunks := config[phase].(type) {
+	case []string:
+		return chunks
+	case []any:
+		return lo.FilterMap(chunks, func(c any, _ int) (string, bool) {
+			s, ok := c.(string)
+			return s, ok
+		})
+	default:
+		return nil
+	}
+}
@@ -765,3 +765,117 @@ func TestKongPluginFromK8SPlugin(t *testing.T) {
 		})
 	}
 }
+
+func TestKongState_MergeRoutePreFunctionPlugins(t *testing.T) {
+	routePreFunction := func() []kong.Plugin {
+		return []kong.Plugin{
+			{Name: kong.String("request-transformer")},
+			{
+				Name:   kong.String("pre-function"),
+				Config: kong.Configuration{"access": []string{"mirror()"}},
+			},
+		}
+	}
+	userPreFunction := func(route *kong.Route, service *kong.Service) Plugin {
+		return Plugin{
+			Plugin: kong.Plugin{
+				Name:    kong.String("pre-function"),
+				Route:   route,
+				Service: service,
+				Config: kong.Configuration{
+					"access":        []any{"user_access()"},
+					"header_filter": []any{"user_header_filter()"},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                 string
+		plugins              []Plugin
+		expectedRoutePlugins []kong.Plugin
+		expectedPlugins      []Plugin
+	}{
+		{
+			name:                 "no user plugin",
+			expectedRoutePlugins: routePreFunction(),
+		},
+		{
+			name:    "user plugin on the route replaces the route plugin",
+			plugins: []Plugin{userPreFunction(&kong.Route{ID: kong.String("route")}, nil)},
+			expectedRoutePlugins: []kong.Plugin{
+				{Name: kong.String("request-transformer")},
+			},
+			expectedPlugins: []Plugin{
+				{
+					Plugin: kong.Plugin{
+						Name:  kong.String("pre-function"),
+						Route: &kong.Route{ID: kong.String("route")},
+						Config: kong.Configuration{
+							"access":        []string{"mirror()", "user_access()"},
+							"header_filter": []any{"user_header_filter()"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "user plugin on the service is merged into the route plugin",
+			plugins: []Plugin{userPreFunction(nil, &kong.Service{ID: kong.String("service")})},
+			expectedRoutePlugins: []kong.Plugin{
+				{Name: kong.String("request-transformer")},
+				{
+					Name: kong.String("pre-function"),
+					Config: kong.Configuration{
+						"access":        []string{"mirror()", "user_access()"},
+						"header_filter": []any{"user_header_filter()"},
+					},
+				},
+			},
+			expectedPlugins: []Plugin{userPreFunction(nil, &kong.Service{ID: kong.String("service")})},
+		},
+		{
+			name:    "global user plugin is merged into the route plugin",
+			plugins: []Plugin{userPreFunction(nil, nil)},
+			expectedRoutePlugins: []kong.Plugin{
+				{Name: kong.String("request-transformer")},
+				{
+					Name: kong.String("pre-function"),
+					Config: kong.Configuration{
+						"access":        []string{"mirror()", "user_access()"},
+						"header_filter": []any{"user_header_filter()"},
+					},
+				},
+			},
+			expectedPlugins: []Plugin{userPreFunction(nil, nil)},
+		},
+		{
+			name:                 "user plugin on another route is left alone",
+			plugins:              []Plugin{userPreFunction(&kong.Route{ID: kong.String("other")}, nil)},
+			expectedRoutePlugins: routePreFunction(),
+			expectedPlugins:      []Plugin{userPreFunction(&kong.Route{ID: kong.String("other")}, nil)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ks := KongState{
+				Services: []Service{
+					{
+						Service: kong.Service{Name: kong.String("service")},
+						Routes: []Route{
+							{
+								Route:   kong.Route{Name: kong.String("route")},
+								Plugins: routePreFunction(),
+							},
+						},
+					},
+				},
+				Plugins: tc.plugins,
+			}
+			ks.mergeRoutePreFunctionPlugins()
+			assert.Equal(t, tc.expectedRoutePlugins, ks.Services[0].Routes[0].Plugins)
+			assert.Equal(t, tc.expectedPlugins, ks.Plugins)
+		})
+	}
+}
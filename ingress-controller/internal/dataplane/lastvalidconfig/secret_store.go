@@ -0,0 +1,295 @@
+// Package lastvalidconfig persists the last valid Kong configuration, so it's available
+// for recovering from configuration rejections after the controller restarts.
+package lastvalidconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1"
+	configurationv1beta1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1beta1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+)
+
+const (
+	// SecretKey is the key of the Secret's data holding the persisted configuration.
+	SecretKey = "config.json.gz"
+	// HashAnnotation is the annotation of the Secret holding the hash of the persisted configuration.
+	HashAnnotation = "konghq.com/last-valid-config-hash"
+
+	// DefaultWriteInterval is the default minimal interval between writes of the Secret.
+	DefaultWriteInterval = 30 * time.Second
+
+	// maxSize is the maximum size of the persisted configuration. It leaves a margin for the Secret's
+	// metadata below the 1MiB limit of Kubernetes objects.
+	maxSize = 1024*1024 - 64*1024
+
+	// flushTimeout is the timeout of writing the pending configuration when the store is stopped.
+	flushTimeout = 5 * time.Second
+)
+
+// SecretStore persists the last valid Kong configuration in a Secret. Writes are debounced:
+// Store only records the configuration and the most recent one is written by Start at most
+// once per write interval.
+type SecretStore struct {
+	logger        logr.Logger
+	client        client.Client
+	reader        client.Reader
+	nn            k8stypes.NamespacedName
+	writeInterval time.Duration
+
+	lock sync.Mutex
+	// pending is the configuration that has been stored but not written yet.
+	pending *kongstate.KongState
+	// lastHash is the hash of the configuration that was written most recently. It's used to skip
+	// updating the Secret when the configuration hasn't changed.
+	lastHash string
+}
+
+// NewSecretStore returns a SecretStore persisting the configuration in the Secret nn. Writes go
+// through cl while the Secret is read with reader, which should not be backed by a cache as the
+// Secret is not necessarily matched by the label selectors of cached Secrets.
+func NewSecretStore(logger logr.Logger, cl client.Client, reader client.Reader, nn k8stypes.NamespacedName) *SecretStore {
+	return &SecretStore{
+		logger:        logger,
+		client:        cl,
+		reader:        reader,
+		nn:            nn,
+		writeInterval: DefaultWriteInterval,
+	}
+}
+
+// Store schedules persisting state. It's written by Start with the next write, replacing any
+// configuration that was stored before and hasn't been written yet. state must not be modified
+// after it's stored.
+func (s *SecretStore) Store(state *kongstate.KongState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pending = state
+}
+
+// Start writes the pending configuration to the Secret every write interval until ctx is done.
+// The configuration pending when ctx is done is written before returning.
+func (s *SecretStore) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.writeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			defer cancel()
+			if err := s.Flush(flushCtx); err != nil {
+				s.logger.Error(err, "Failed to persist the last valid configuration")
+			}
+			return nil
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				s.logger.Error(err, "Failed to persist the last valid configuration")
+			}
+		}
+	}
+}
+
+// NeedLeaderElection implements the controller-runtime LeaderElectionRunnable interface. The Secret
+// is written only by the leader as only the leader syncs the configuration with the gateways.
+func (s *SecretStore) NeedLeaderElection() bool {
+	return true
+}
+
+// Flush writes the pending configuration to the Secret. The Secret is not updated when the
+// configuration is the same as the one written most recently.
+func (s *SecretStore) Flush(ctx context.Context) error {
+	s.lock.Lock()
+	state := s.pending
+	s.pending = nil
+	s.lock.Unlock()
+	if state == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(withoutKubernetesObjects(state))
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	hash := hex.EncodeToString(sum[:])
+	if hash == s.getLastHash() {
+		return nil
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err := w.Write(payload); err != nil {
+		return fmt.Errorf("failed to compress configuration: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress configuration: %w", err)
+	}
+	if compressed.Len() > maxSize {
+		return fmt.Errorf("compressed configuration size %d exceeds the limit of %d bytes", compressed.Len(), maxSize)
+	}
+
+	var secret corev1.Secret
+	err = s.reader.Get(ctx, s.nn, &secret)
+	switch {
+	case k8serrors.IsNotFound(err):
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        s.nn.Name,
+				Namespace:   s.nn.Namespace,
+				Annotations: map[string]string{HashAnnotation: hash},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{SecretKey: compressed.Bytes()},
+		}
+		if err := s.client.Create(ctx, &secret); err != nil {
+			s.requeue(state)
+			return fmt.Errorf("failed to create secret %s: %w", s.nn, err)
+		}
+	case err != nil:
+		s.requeue(state)
+		return fmt.Errorf("failed to get secret %s: %w", s.nn, err)
+	default:
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[HashAnnotation] = hash
+		secret.Data = map[string][]byte{SecretKey: compressed.Bytes()}
+		if err := s.client.Update(ctx, &secret); err != nil {
+			s.requeue(state)
+			return fmt.Errorf("failed to update secret %s: %w", s.nn, err)
+		}
+	}
+
+	s.setLastHash(hash)
+	return nil
+}
+
+// Load returns the configuration persisted in the Secret. false is returned when nothing has
+// been persisted yet.
+func (s *SecretStore) Load(ctx context.Context) (*kongstate.KongState, bool, error) {
+	var secret corev1.Secret
+	if err := s.reader.Get(ctx, s.nn, &secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get secret %s: %w", s.nn, err)
+	}
+	data, ok := secret.Data[SecretKey]
+	if !ok {
+		return nil, false, nil
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress configuration: %w", err)
+	}
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decompress configuration: %w", err)
+	}
+	var state kongstate.KongState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	s.setLastHash(secret.Annotations[HashAnnotation])
+	return &state, true, nil
+}
+
+// requeue makes the state that failed to be written pending again unless a newer one has been
+// stored in the meantime.
+func (s *SecretStore) requeue(state *kongstate.KongState) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.pending == nil {
+		s.pending = state
+	}
+}
+
+func (s *SecretStore) getLastHash() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.lastHash
+}
+
+func (s *SecretStore) setLastHash(hash string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastHash = hash
+}
+
+// withoutKubernetesObjects returns a copy of state without the Kubernetes objects its entities
+// were translated from. They're not needed for sending the configuration to the gateways and
+// would make the persisted configuration needlessly large.
+func withoutKubernetesObjects(state *kongstate.KongState) *kongstate.KongState {
+	out := *state
+
+	out.Services = make([]kongstate.Service, len(state.Services))
+	for i, svc := range state.Services {
+		out.Services[i] = serviceWithoutKubernetesObjects(svc)
+	}
+	out.Upstreams = make([]kongstate.Upstream, len(state.Upstreams))
+	for i, upstream := range state.Upstreams {
+		upstream.Service = serviceWithoutKubernetesObjects(upstream.Service)
+		out.Upstreams[i] = upstream
+	}
+	out.Plugins = make([]kongstate.Plugin, len(state.Plugins))
+	for i, plugin := range state.Plugins {
+		plugin.K8sParent = nil
+		out.Plugins[i] = plugin
+	}
+	out.Consumers = make([]kongstate.Consumer, len(state.Consumers))
+	for i, consumer := range state.Consumers {
+		consumer.K8sKongConsumer = configurationv1.KongConsumer{}
+		out.Consumers[i] = consumer
+	}
+	out.ConsumerGroups = make([]kongstate.ConsumerGroup, len(state.ConsumerGroups))
+	for i, consumerGroup := range state.ConsumerGroups {
+		consumerGroup.K8sKongConsumerGroup = configurationv1beta1.KongConsumerGroup{}
+		out.ConsumerGroups[i] = consumerGroup
+	}
+	out.Vaults = make([]kongstate.Vault, len(state.Vaults))
+	for i, vault := range state.Vaults {
+		vault.K8sKongVault = nil
+		out.Vaults[i] = vault
+	}
+	if state.CustomEntities != nil {
+		out.CustomEntities = make(map[string]*kongstate.KongCustomEntityCollection, len(state.CustomEntities))
+		for entityType, collection := range state.CustomEntities {
+			entities := make([]kongstate.CustomEntity, len(collection.Entities))
+			for i, entity := range collection.Entities {
+				entity.K8sKongCustomEntity = nil
+				entities[i] = entity
+			}
+			out.CustomEntities[entityType] = &kongstate.KongCustomEntityCollection{
+				Schema:   collection.Schema,
+				Entities: entities,
+			}
+		}
+	}
+	return &out
+}
+
+func serviceWithoutKubernetesObjects(svc kongstate.Service) kongstate.Service {
+	svc.Backends = nil
+	svc.K8sServices = nil
+	svc.Parent = nil
+	return svc
+}
@@ -0,0 +1,129 @@
+package lastvalidconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/pkg/manager/scheme"
+)
+
+func TestSecretStore(t *testing.T) {
+	ctx := context.Background()
+	secretNN := k8stypes.NamespacedName{Namespace: "kong", Name: "last-valid-config"}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Get()).Build()
+	s := NewSecretStore(logr.Discard(), cl, cl, secretNN)
+
+	t.Run("nothing persisted yet", func(t *testing.T) {
+		_, found, err := s.Load(ctx)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	state := &kongstate.KongState{
+		Services: []kongstate.Service{
+			{
+				Service: kong.Service{
+					Name: kong.String("svc"),
+					Host: kong.String("svc.default.80.svc"),
+				},
+				Routes: []kongstate.Route{
+					{
+						Route: kong.Route{
+							Name:  kong.String("route"),
+							Paths: kong.StringSlice("/"),
+						},
+					},
+				},
+				K8sServices: map[string]*corev1.Service{
+					"default/svc": {ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}},
+				},
+				Parent: &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"}},
+			},
+		},
+		Plugins: []kongstate.Plugin{
+			{
+				Plugin:    kong.Plugin{Name: kong.String("cors")},
+				K8sParent: &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"}},
+			},
+		},
+	}
+
+	t.Run("storing doesn't write the secret until flushed", func(t *testing.T) {
+		s.Store(state)
+		var secret corev1.Secret
+		require.Error(t, cl.Get(ctx, secretNN, &secret))
+	})
+
+	require.NoError(t, s.Flush(ctx))
+	var secret corev1.Secret
+	require.NoError(t, cl.Get(ctx, secretNN, &secret))
+	require.Contains(t, secret.Data, SecretKey)
+	require.NotEmpty(t, secret.Annotations[HashAnnotation])
+
+	t.Run("only the most recently stored configuration is written", func(t *testing.T) {
+		s.Store(&kongstate.KongState{})
+		s.Store(state)
+		require.NoError(t, s.Flush(ctx))
+		var stored corev1.Secret
+		require.NoError(t, cl.Get(ctx, secretNN, &stored))
+		assert.Equal(t, secret.ResourceVersion, stored.ResourceVersion, "storing the same configuration shouldn't update the secret")
+	})
+
+	t.Run("persisted configuration is loaded without Kubernetes objects", func(t *testing.T) {
+		loaded, found, err := NewSecretStore(logr.Discard(), cl, cl, secretNN).Load(ctx)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		require.Len(t, loaded.Services, 1)
+		assert.Equal(t, state.Services[0].Service, loaded.Services[0].Service)
+		require.Len(t, loaded.Services[0].Routes, 1)
+		assert.Equal(t, state.Services[0].Routes[0].Route, loaded.Services[0].Routes[0].Route)
+		assert.Nil(t, loaded.Services[0].K8sServices)
+		assert.Nil(t, loaded.Services[0].Parent)
+		require.Len(t, loaded.Plugins, 1)
+		assert.Equal(t, state.Plugins[0].Plugin, loaded.Plugins[0].Plugin)
+		assert.Nil(t, loaded.Plugins[0].K8sParent)
+
+		assert.NotNil(t, state.Services[0].Parent, "the stored configuration shouldn't be modified")
+		assert.NotNil(t, state.Plugins[0].K8sParent, "the stored configuration shouldn't be modified")
+	})
+
+	t.Run("changed configuration updates the secret", func(t *testing.T) {
+		s.Store(&kongstate.KongState{})
+		require.NoError(t, s.Flush(ctx))
+
+		loaded, found, err := NewSecretStore(logr.Discard(), cl, cl, secretNN).Load(ctx)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Empty(t, loaded.Services)
+	})
+
+	t.Run("pending configuration is written when stopped", func(t *testing.T) {
+		s := NewSecretStore(logr.Discard(), cl, cl, secretNN)
+		s.writeInterval = time.Hour
+		s.Store(state)
+
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() { done <- s.Start(ctx) }()
+		cancel()
+		require.NoError(t, <-done)
+
+		loaded, found, err := s.Load(context.Background())
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Len(t, loaded.Services, 1)
+	})
+}
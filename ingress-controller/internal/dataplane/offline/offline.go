@@ -0,0 +1,236 @@
+// Package offline renders Kong declarative configuration from Kubernetes manifests
+// without a Kubernetes cluster nor a Kong Gateway.
+package offline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-kong/kong"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/deckgen"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/failures"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/sendconfig"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+// Format is the format of the rendered configuration.
+type Format string
+
+const (
+	// FormatDeck is the decK file format.
+	FormatDeck Format = "deck"
+	// FormatDBLess is the format of the declarative configuration of DB-less Kong Gateways.
+	FormatDBLess Format = "dbless"
+)
+
+// clusterScopedKinds are kinds of the supported objects that are not namespaced.
+var clusterScopedKinds = map[string]struct{}{
+	"IngressClass":      {},
+	"KongClusterPlugin": {},
+	"KongVault":         {},
+}
+
+// Manifests are Kubernetes objects loaded from manifests.
+type Manifests struct {
+	// CacheStores hold the objects that are translated.
+	CacheStores store.CacheStores
+	// Skipped are the objects (Kind namespace/name) of kinds that are not translated, e.g. Deployments.
+	Skipped []string
+}
+
+// LoadManifests decodes Kubernetes objects from YAML or JSON manifests, each possibly
+// containing multiple documents and List objects. Namespaced objects without a namespace
+// are placed in defaultNamespace.
+func LoadManifests(defaultNamespace string, manifests ...io.Reader) (Manifests, error) {
+	var (
+		objs    []runtime.Object
+		skipped []string
+	)
+	for _, m := range manifests {
+		decoder := k8syaml.NewYAMLOrJSONDecoder(m, 4096)
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return Manifests{}, fmt.Errorf("failed to decode manifest: %w", err)
+			}
+			// Skip empty documents.
+			if len(raw) == 0 || string(raw) == "null" {
+				continue
+			}
+			var obj unstructured.Unstructured
+			if err := obj.UnmarshalJSON(raw); err != nil {
+				return Manifests{}, fmt.Errorf("failed to decode object: %w", err)
+			}
+
+			items := []unstructured.Unstructured{obj}
+			if obj.IsList() {
+				list, err := obj.ToList()
+				if err != nil {
+					return Manifests{}, fmt.Errorf("failed to decode list: %w", err)
+				}
+				items = list.Items
+			}
+			for i := range items {
+				item := &items[i]
+				if !store.IsSupported(item.GroupVersionKind()) {
+					skipped = append(skipped, fmt.Sprintf("%s %s/%s", item.GetKind(), item.GetNamespace(), item.GetName()))
+					continue
+				}
+				if _, clusterScoped := clusterScopedKinds[item.GetKind()]; !clusterScoped && item.GetNamespace() == "" {
+					item.SetNamespace(defaultNamespace)
+				}
+				objs = append(objs, item)
+			}
+		}
+	}
+
+	cacheStores, err := store.NewCacheStoresFromObjs(objs...)
+	if err != nil {
+		return Manifests{}, fmt.Errorf("failed to load objects: %w", err)
+	}
+	return Manifests{
+		CacheStores: cacheStores,
+		Skipped:     skipped,
+	}, nil
+}
+
+// Config configures the translation.
+type Config struct {
+	// FeatureFlags are the translator's feature flags.
+	FeatureFlags translator.FeatureFlags
+	// KongVersion is the version of the Kong Gateway the configuration is rendered for.
+	KongVersion semver.Version
+	// IngressClass is the name of the ingress class of the translated objects.
+	IngressClass string
+	// ClusterDomain is the cluster domain used in addresses of upstream services.
+	ClusterDomain string
+	// EnableDrainSupport includes terminating endpoints in upstreams with weight=0.
+	EnableDrainSupport bool
+}
+
+// Result is the result of the translation.
+type Result struct {
+	// Content is the rendered decK configuration.
+	Content *file.Content
+	// CustomEntities are the custom entities rendered from KongCustomEntities by their types.
+	CustomEntities sendconfig.CustomEntitiesByType
+	// TranslationFailures are the failures of translating objects. Objects that failed
+	// are not part of the configuration, like when translated by the controller.
+	TranslationFailures []failures.ResourceFailure
+}
+
+// Translate translates the objects in cacheStores to Kong configuration the same way the
+// controller does. Plugin and custom entity schemas are only available from a running Kong
+// Gateway, hence defaults of plugins' configuration are not filled and KongCustomEntities
+// can't be translated.
+func Translate(ctx context.Context, logger logr.Logger, cacheStores store.CacheStores, cfg Config) (Result, error) {
+	storer := store.New(cacheStores, cfg.IngressClass, logger)
+	t, err := translator.NewTranslator(logger, storer, "", cfg.KongVersion, cfg.FeatureFlags, schemaServiceProvider{},
+		translator.Config{
+			ClusterDomain:      cfg.ClusterDomain,
+			EnableDrainSupport: cfg.EnableDrainSupport,
+		},
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create translator: %w", err)
+	}
+
+	result := t.BuildKongConfig()
+	content := deckgen.ToDeckContent(ctx, logger, result.KongState, deckgen.GenerateDeckContentParams{
+		ExpressionRoutes: cfg.FeatureFlags.ExpressionRoutes,
+		PluginSchemas:    pluginSchemaStore{},
+	})
+	customEntities := make(sendconfig.CustomEntitiesByType)
+	for entityType, collection := range result.KongState.CustomEntities {
+		for _, entity := range collection.Entities {
+			customEntities[entityType] = append(customEntities[entityType], entity.Object)
+		}
+	}
+	return Result{
+		Content:             content,
+		CustomEntities:      customEntities,
+		TranslationFailures: result.TranslationFailures,
+	}, nil
+}
+
+// Marshal returns the rendered configuration as YAML in the provided format.
+func (r Result) Marshal(format Format) ([]byte, error) {
+	switch format {
+	case FormatDeck:
+		return yaml.Marshal(r.Content)
+	case FormatDBLess:
+		// The converter is allowed to modify the content.
+		var content file.Content
+		b, err := json.Marshal(r.Content)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &content); err != nil {
+			return nil, err
+		}
+		config, err := json.Marshal(sendconfig.DefaultContentToDBLessConfigConverter{}.Convert(&content))
+		if err != nil {
+			return nil, err
+		}
+		if len(r.CustomEntities) > 0 {
+			withCustomEntities := map[string]any{}
+			if err := json.Unmarshal(config, &withCustomEntities); err != nil {
+				return nil, err
+			}
+			for entityType, entities := range r.CustomEntities {
+				withCustomEntities[entityType] = entities
+			}
+			if config, err = json.Marshal(withCustomEntities); err != nil {
+				return nil, err
+			}
+		}
+		return yaml.JSONToYAML(config)
+	default:
+		return nil, fmt.Errorf("unsupported format %q, supported formats are %s and %s", format, FormatDeck, FormatDBLess)
+	}
+}
+
+// schemaServiceProvider provides the schema service that is always unavailable.
+type schemaServiceProvider struct{}
+
+func (schemaServiceProvider) GetSchemaService() kong.AbstractSchemaService {
+	return translator.UnavailableSchemaService{}
+}
+
+// pluginSchemaStore returns the same schema for all plugins, with no configuration
+// fields and the default protocols of Kong plugins, as the schemas of plugins are
+// only available from a running Kong Gateway.
+type pluginSchemaStore struct{}
+
+func (pluginSchemaStore) Schema(_ context.Context, _ string) (map[string]any, error) {
+	return map[string]any{
+		"fields": []any{
+			map[string]any{
+				"protocols": map[string]any{
+					"default": []any{"grpc", "grpcs", "http", "https"},
+				},
+			},
+			map[string]any{
+				"config": map[string]any{
+					"type":   "record",
+					"fields": []any{},
+				},
+			},
+		},
+	}, nil
+}
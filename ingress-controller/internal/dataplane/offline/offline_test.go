@@ -0,0 +1,114 @@
+package offline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	dpconf "github.com/kong/kong-operator/ingress-controller/internal/dataplane/config"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
+	managercfg "github.com/kong/kong-operator/ingress-controller/pkg/manager/config"
+)
+
+const manifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo-svc
+spec:
+  ports:
+  - name: http
+    port: 80
+    targetPort: 8080
+---
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: foo
+spec:
+  ingressClassName: kong
+  rules:
+  - http:
+      paths:
+      - path: /foo
+        pathType: Prefix
+        backend:
+          service:
+            name: foo-svc
+            port:
+              number: 80
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: foo
+    namespace: bar
+`
+
+func TestLoadManifests(t *testing.T) {
+	loaded, err := LoadManifests("default", strings.NewReader(manifests))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Deployment bar/foo"}, loaded.Skipped)
+	require.Len(t, loaded.CacheStores.Service.List(), 1)
+	require.Len(t, loaded.CacheStores.IngressV1.List(), 1)
+
+	_, exists, err := loaded.CacheStores.Service.GetByKey("default/foo-svc")
+	require.NoError(t, err)
+	assert.True(t, exists, "Service without a namespace should be placed in the default namespace")
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		_, err := LoadManifests("default", strings.NewReader("kind: [Service"))
+		require.Error(t, err)
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	loaded, err := LoadManifests("default", strings.NewReader(manifests))
+	require.NoError(t, err)
+
+	result, err := Translate(context.Background(), logr.Discard(), loaded.CacheStores, Config{
+		FeatureFlags: translator.NewFeatureFlags(
+			managercfg.GetFeatureGatesDefaults(), dpconf.RouterFlavorTraditionalCompatible, false, false, true, false,
+		),
+		KongVersion:   semver.MustParse("3.11.0"),
+		IngressClass:  annotations.DefaultIngressClass,
+		ClusterDomain: managercfg.DefaultClusterDomain,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.TranslationFailures)
+
+	require.Len(t, result.Content.Services, 1)
+	assert.Equal(t, "default.foo-svc.80", *result.Content.Services[0].Name)
+	require.Len(t, result.Content.Services[0].Routes, 1)
+	require.Len(t, result.Content.Upstreams, 1)
+
+	t.Run("deck", func(t *testing.T) {
+		out, err := result.Marshal(FormatDeck)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "default.foo-svc.80")
+		assert.Contains(t, string(out), "routes:")
+	})
+
+	t.Run("dbless", func(t *testing.T) {
+		out, err := result.Marshal(FormatDBLess)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "default.foo-svc.80")
+		assert.Contains(t, string(out), "_format_version")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := result.Marshal("json")
+		require.Error(t, err)
+	})
+}
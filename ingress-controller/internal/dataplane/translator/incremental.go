@@ -0,0 +1,644 @@
+package translator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configurationv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1alpha1"
+	incubatorv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/incubator/v1alpha1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/failures"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/fallback"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/logging"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+)
+
+// DefaultIncrementalTranslationFullRebuildInterval is the default interval in which IncrementalTranslator
+// re-translates all routes regardless of changes.
+const DefaultIncrementalTranslationFullRebuildInterval = 10 * time.Minute
+
+// errRoutesComponentsCollision is returned when translations of different routes components can't be merged
+// because they produce Kong entities with the same names.
+var errRoutesComponentsCollision = errors.New("routes components translated to Kong entities with the same names")
+
+// IncrementalTranslator is a Translator that re-translates only Ingresses and Gateway API routes affected
+// by changes made since the previous build.
+//
+// Routes are grouped into components of the cache dependency graph: routes sharing a Kubernetes Service
+// (or a KongServiceFacade) belong to the same component together with the Services and their EndpointSlices.
+// Translations of components none of which objects, nor objects they refer to (e.g. KongPlugins or
+// KongUpstreamPolicies), have changed are reused. Changes to objects which routes translation may depend on
+// without referring to them (Gateways, ReferenceGrants, BackendTLSPolicies, IngressClasses and
+// IngressClassParameters) cause all routes to be re-translated. All routes are also re-translated periodically
+// as a safety net. Kong entities not derived from routes (e.g. consumers, plugins or certificates) are always
+// built from scratch.
+//
+// The dependency graph is not rebuilt on every build: dependencies are resolved again only for the objects
+// which changed, unless objects were added or removed.
+//
+// Only the default backend of the oldest Ingress defining one is translated, the same as in a full build.
+//
+// With the expressions router, priorities of Kong Routes are assigned relative to all the other routes, so
+// routes can't be translated separately per component. All routes are then always translated together.
+type IncrementalTranslator struct {
+	*Translator
+
+	cache               store.CacheStores
+	fullRebuildInterval time.Duration
+	now                 func() time.Time
+
+	lastFullRebuild   time.Time
+	globalObjectsHash string
+	// dependencies are the resolved dependencies of the cache objects by their keys.
+	dependencies map[string]objectDependencies
+	// objectKeysHash identifies the set of the cache objects dependencies were resolved for.
+	objectKeysHash string
+	// components are the last translations of routes components by their keys.
+	components map[string]routesComponentTranslation
+}
+
+// NewIncrementalTranslator creates an IncrementalTranslator translating objects from the cache
+// with the provided Translator, which has to use a store backed by the same cache.
+func NewIncrementalTranslator(
+	translator *Translator,
+	cache store.CacheStores,
+	fullRebuildInterval time.Duration,
+) *IncrementalTranslator {
+	return &IncrementalTranslator{
+		Translator:          translator,
+		cache:               cache,
+		fullRebuildInterval: fullRebuildInterval,
+		now:                 time.Now,
+	}
+}
+
+// UpdateCache updates the store cache used by the translator.
+func (t *IncrementalTranslator) UpdateCache(c store.CacheStores) {
+	t.cache = c
+	t.Translator.UpdateCache(c)
+}
+
+// BuildKongConfig creates a Kong configuration from Ingress and Custom resources defined in Kubernetes,
+// re-translating only routes affected by changes since the previous build.
+func (t *IncrementalTranslator) BuildKongConfig() KongConfigBuildingResult {
+	if t.featureFlags.ExpressionRoutes {
+		t.components = nil
+		return t.Translator.BuildKongConfig()
+	}
+
+	routes, err := t.translateRoutesIncrementally()
+	if err != nil {
+		if errors.Is(err, errRoutesComponentsCollision) {
+			t.logger.V(logging.DebugLevel).Info("Falling back to full translation", "reason", err.Error())
+		} else {
+			t.logger.Error(err, "Incremental translation failed, falling back to full translation")
+			t.components = nil
+		}
+		return t.Translator.BuildKongConfig()
+	}
+	return t.buildKongConfig(routes)
+}
+
+// routesComponentTranslation is a translation of a single routes component.
+type routesComponentTranslation struct {
+	// version identifies the versions of the component's objects the translation was done for.
+	version           string
+	routes            routesTranslationResult
+	failures          []failures.ResourceFailure
+	configuredObjects []client.Object
+}
+
+func (t *IncrementalTranslator) translateRoutesIncrementally() (routesTranslationResult, error) {
+	components, globalObjectsHash, err := t.routesComponents()
+	if err != nil {
+		return routesTranslationResult{}, err
+	}
+
+	now := t.now()
+	switch {
+	case t.lastFullRebuild.IsZero() || now.Sub(t.lastFullRebuild) >= t.fullRebuildInterval:
+		t.logger.V(logging.DebugLevel).Info("Re-translating all routes periodically")
+		t.components = nil
+		t.lastFullRebuild = now
+	case globalObjectsHash != t.globalObjectsHash:
+		t.logger.V(logging.DebugLevel).Info("Objects all routes depend on changed, re-translating all routes")
+		t.components = nil
+	}
+	t.globalObjectsHash = globalObjectsHash
+
+	translations := make(map[string]routesComponentTranslation, len(components))
+	translatedCount := 0
+	for _, component := range components {
+		translation, ok := t.components[component.key]
+		if !ok || translation.version != component.version {
+			translation = t.translateRoutesComponent(component)
+			translatedCount++
+		}
+		translations[component.key] = translation
+	}
+	t.components = translations
+	t.logger.V(logging.DebugLevel).Info("Translated routes components",
+		"components", len(components), "translated", translatedCount,
+	)
+
+	// Merge translations in a stable order for the result not to change between builds.
+	keys := slices.Sorted(maps.Keys(translations))
+	sortedTranslations := lo.Map(keys, func(key string, _ int) routesComponentTranslation {
+		return translations[key]
+	})
+	routes, err := mergeRoutesComponentTranslations(sortedTranslations)
+	if err != nil {
+		return routesTranslationResult{}, err
+	}
+	for _, translation := range sortedTranslations {
+		for _, failure := range translation.failures {
+			t.registerTranslationFailure(failure.Message(), failure.CausingObjects()...)
+		}
+		for _, obj := range translation.configuredObjects {
+			t.registerSuccessfullyTranslatedObject(obj)
+		}
+	}
+	return routes, nil
+}
+
+// translateRoutesComponent translates routes of a single component. Objects routes refer to are
+// looked up in the whole store.
+func (t *IncrementalTranslator) translateRoutesComponent(component routesComponent) routesComponentTranslation {
+	componentTranslator := *t.Translator
+	componentTranslator.storer = component.storer(t.storer)
+	routes := componentTranslator.translateRoutes()
+	return routesComponentTranslation{
+		version:           component.version,
+		routes:            routes,
+		failures:          t.popTranslationFailures(),
+		configuredObjects: t.popConfiguredKubernetesObjects(),
+	}
+}
+
+// routesComponent is a connected component of the cache dependency graph containing Ingresses or
+// Gateway API routes.
+type routesComponent struct {
+	// key identifies the component's objects.
+	key string
+	// version changes whenever any of the component's objects changes.
+	version string
+
+	ingresses  []*netv1.Ingress
+	httpRoutes []*gatewayapi.HTTPRoute
+	udpRoutes  []*gatewayapi.UDPRoute
+	tcpRoutes  []*gatewayapi.TCPRoute
+	tlsRoutes  []*gatewayapi.TLSRoute
+	grpcRoutes []*gatewayapi.GRPCRoute
+}
+
+// objectDependencies are the keys of the objects an object refers to, resolved for a version of the object.
+type objectDependencies struct {
+	version string
+	keys    []string
+}
+
+// routesComponents groups routes in the store into components of the cache dependency graph. It also returns
+// a hash of the objects which all routes translations may depend on.
+//
+// The dependency graph is maintained incrementally: dependencies are resolved only for objects which changed
+// since the previous build, or for all objects when objects were added or removed, as dependencies are resolved
+// by looking up the referenced objects.
+func (t *IncrementalTranslator) routesComponents() ([]routesComponent, string, error) {
+	var (
+		objects        = make(map[string]client.Object)
+		keys           []string
+		globalObjects  []string
+		servicesByName = make(map[string]string)
+	)
+	for _, s := range t.cache.ListAllStores() {
+		if s == nil {
+			continue
+		}
+		for _, o := range s.List() {
+			obj, ok := o.(client.Object)
+			if !ok {
+				return nil, "", fmt.Errorf("expected client.Object, got %T", o)
+			}
+			key := objectKey(obj)
+			objects[key] = obj
+			keys = append(keys, key)
+			if isGlobalRoutesDependency(obj) {
+				globalObjects = append(globalObjects, objectVersion(obj))
+			}
+			if service, ok := obj.(*corev1.Service); ok {
+				servicesByName[service.Namespace+"/"+service.Name] = key
+			}
+		}
+	}
+	slices.Sort(keys)
+	slices.Sort(globalObjects)
+
+	keysHash := hashStrings(keys)
+	resolveAll := keysHash != t.objectKeysHash
+	dependencies := make(map[string]objectDependencies, len(objects))
+	resolvedCount := 0
+	for key, obj := range objects {
+		version := objectVersion(obj)
+		if deps, ok := t.dependencies[key]; ok && !resolveAll && deps.version == version {
+			dependencies[key] = deps
+			continue
+		}
+		deps, err := fallback.ResolveDependencies(t.cache, obj)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to resolve dependencies of %s: %w", key, err)
+		}
+		depKeys := lo.Map(deps, func(dep client.Object, _ int) string { return objectKey(dep) })
+		switch obj := obj.(type) {
+		case *netv1.Ingress:
+			// Default backends are not a part of the dependency graph.
+			depKeys = append(depKeys, ingressDefaultBackendKeys(obj)...)
+		case *discoveryv1.EndpointSlice:
+			// EndpointSlices are not a part of the dependency graph, they're connected with their Services by labels.
+			if service, ok := servicesByName[obj.Namespace+"/"+obj.Labels[discoveryv1.LabelServiceName]]; ok {
+				depKeys = append(depKeys, service)
+			}
+		}
+		dependencies[key] = objectDependencies{version: version, keys: depKeys}
+		resolvedCount++
+	}
+	t.dependencies = dependencies
+	t.objectKeysHash = keysHash
+	t.logger.V(logging.DebugLevel).Info("Resolved objects dependencies",
+		"objects", len(objects), "resolved", resolvedCount,
+	)
+
+	// Routes are connected with Services and KongServiceFacades they route to, and Services with their
+	// EndpointSlices. Other objects referenced by the component's objects only affect its version.
+	components := newDisjointSets[string]()
+	for key, obj := range objects {
+		if !isRoutesComponentObject(obj) {
+			continue
+		}
+		components.add(key)
+		for _, dep := range dependencies[key].keys {
+			if depObj, ok := objects[dep]; ok && isRoutesComponentObject(depObj) {
+				components.union(key, dep)
+			}
+		}
+	}
+
+	// Routes are listed from the store, as it filters out the ones that shouldn't be translated.
+	// They're matched with the cache objects by keys, as the store may return their copies.
+	byRoot := make(map[string]*routesComponent)
+	componentOf := func(obj client.Object) *routesComponent {
+		root := components.find(objectKey(obj))
+		if c, ok := byRoot[root]; ok {
+			return c
+		}
+		c := &routesComponent{}
+		byRoot[root] = c
+		return c
+	}
+
+	// Only the default backend of the oldest Ingress defining one is translated, like in a full build.
+	// Default backends of the other Ingresses are dropped, so that each component doesn't translate its own.
+	ingresses := t.storer.ListIngressesV1()
+	defaultBackendIngress := oldestIngressWithDefaultBackend(ingresses)
+	for _, ingress := range ingresses {
+		c := componentOf(ingress)
+		if ingress.Spec.DefaultBackend != nil && ingress != defaultBackendIngress {
+			ingress = ingress.DeepCopy()
+			ingress.Spec.DefaultBackend = nil
+		}
+		c.ingresses = append(c.ingresses, ingress)
+	}
+	if err := distributeRoutes(t.storer.ListHTTPRoutes, componentOf, func(c *routesComponent, r *gatewayapi.HTTPRoute) {
+		c.httpRoutes = append(c.httpRoutes, r)
+	}); err != nil {
+		return nil, "", err
+	}
+	if err := distributeRoutes(t.storer.ListUDPRoutes, componentOf, func(c *routesComponent, r *gatewayapi.UDPRoute) {
+		c.udpRoutes = append(c.udpRoutes, r)
+	}); err != nil {
+		return nil, "", err
+	}
+	if err := distributeRoutes(t.storer.ListTCPRoutes, componentOf, func(c *routesComponent, r *gatewayapi.TCPRoute) {
+		c.tcpRoutes = append(c.tcpRoutes, r)
+	}); err != nil {
+		return nil, "", err
+	}
+	if err := distributeRoutes(t.storer.ListTLSRoutes, componentOf, func(c *routesComponent, r *gatewayapi.TLSRoute) {
+		c.tlsRoutes = append(c.tlsRoutes, r)
+	}); err != nil {
+		return nil, "", err
+	}
+	if err := distributeRoutes(t.storer.ListGRPCRoutes, componentOf, func(c *routesComponent, r *gatewayapi.GRPCRoute) {
+		c.grpcRoutes = append(c.grpcRoutes, r)
+	}); err != nil {
+		return nil, "", err
+	}
+
+	// Key of a component is computed from its objects. Its version is computed from the versions of its objects
+	// and of all the objects they (transitively) refer to, so that only changes of referenced objects cause
+	// the component to be re-translated.
+	keysByRoot := make(map[string][]string)
+	for key, obj := range objects {
+		if !isRoutesComponentObject(obj) {
+			continue
+		}
+		root := components.find(key)
+		if _, ok := byRoot[root]; ok {
+			keysByRoot[root] = append(keysByRoot[root], key)
+		}
+	}
+	result := make([]routesComponent, 0, len(byRoot))
+	for root, c := range byRoot {
+		componentKeys := keysByRoot[root]
+		referenced := referencedObjectKeys(componentKeys, dependencies)
+		versions := make([]string, 0, len(referenced)+1)
+		for key := range referenced {
+			if obj, ok := objects[key]; ok {
+				versions = append(versions, objectVersion(obj))
+			}
+		}
+		if defaultBackendIngress != nil && referenced.Has(objectKey(defaultBackendIngress)) {
+			versions = append(versions, "defaultBackend:"+objectKey(defaultBackendIngress))
+		}
+		slices.Sort(componentKeys)
+		slices.Sort(versions)
+		c.key = hashStrings(componentKeys)
+		c.version = hashStrings(versions)
+		result = append(result, *c)
+	}
+	return result, hashStrings(globalObjects), nil
+}
+
+// referencedObjectKeys returns the provided keys together with the keys of all the objects they transitively refer to.
+func referencedObjectKeys(keys []string, dependencies map[string]objectDependencies) sets.Set[string] {
+	referenced := sets.New(keys...)
+	queue := slices.Clone(keys)
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dep := range dependencies[key].keys {
+			if !referenced.Has(dep) {
+				referenced.Insert(dep)
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return referenced
+}
+
+// oldestIngressWithDefaultBackend returns the Ingress whose default backend is translated, i.e. the oldest one
+// defining a default backend, the same way as getDefaultBackendService picks it.
+func oldestIngressWithDefaultBackend(ingresses []*netv1.Ingress) *netv1.Ingress {
+	withDefaultBackend := lo.Filter(ingresses, func(ingress *netv1.Ingress, _ int) bool {
+		return ingress.Spec.DefaultBackend != nil
+	})
+	if len(withDefaultBackend) == 0 {
+		return nil
+	}
+	sort.SliceStable(withDefaultBackend, func(i, j int) bool {
+		return withDefaultBackend[i].CreationTimestamp.Before(&withDefaultBackend[j].CreationTimestamp)
+	})
+	return withDefaultBackend[0]
+}
+
+// ingressDefaultBackendKeys returns the keys of the Service or KongServiceFacade the default backend of the Ingress
+// refers to.
+func ingressDefaultBackendKeys(ingress *netv1.Ingress) []string {
+	defaultBackend := ingress.Spec.DefaultBackend
+	if defaultBackend == nil {
+		return nil
+	}
+	meta := metav1.ObjectMeta{Namespace: ingress.Namespace}
+	switch {
+	case defaultBackend.Service != nil:
+		meta.Name = defaultBackend.Service.Name
+		return []string{objectKey(&corev1.Service{ObjectMeta: meta})}
+	case defaultBackend.Resource != nil && subtranslator.IsKongServiceFacade(defaultBackend.Resource):
+		meta.Name = defaultBackend.Resource.Name
+		return []string{objectKey(&incubatorv1alpha1.KongServiceFacade{ObjectMeta: meta})}
+	default:
+		return nil
+	}
+}
+
+// distributeRoutes assigns routes listed by list to their components.
+func distributeRoutes[T client.Object](
+	list func() ([]T, error),
+	componentOf func(client.Object) *routesComponent,
+	add func(*routesComponent, T),
+) error {
+	routes, err := list()
+	if err != nil {
+		return fmt.Errorf("failed to list %T: %w", *new(T), err)
+	}
+	for _, route := range routes {
+		add(componentOf(route), route)
+	}
+	return nil
+}
+
+// isGlobalRoutesDependency returns true for objects which translation of any route may depend on without
+// referring to them (e.g. Gateways the routes are attached to or ReferenceGrants allowing their backends).
+// Changes to them cause all routes to be re-translated.
+func isGlobalRoutesDependency(obj client.Object) bool {
+	switch obj.(type) {
+	case *gatewayapi.Gateway,
+		*gatewayapi.ReferenceGrant,
+		*gatewayapi.BackendTLSPolicy,
+		*netv1.IngressClass,
+		*configurationv1alpha1.IngressClassParameters:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRoutesComponentObject returns true for objects that are grouped into routes components.
+func isRoutesComponentObject(obj client.Object) bool {
+	switch obj.(type) {
+	case *netv1.Ingress,
+		*gatewayapi.HTTPRoute,
+		*gatewayapi.UDPRoute,
+		*gatewayapi.TCPRoute,
+		*gatewayapi.TLSRoute,
+		*gatewayapi.GRPCRoute,
+		*corev1.Service,
+		*discoveryv1.EndpointSlice,
+		*incubatorv1alpha1.KongServiceFacade:
+		return true
+	default:
+		return false
+	}
+}
+
+func objectKey(obj client.Object) string {
+	return fmt.Sprintf("%T:%s/%s", obj, obj.GetNamespace(), obj.GetName())
+}
+
+// objectVersion identifies a version of an object.
+func objectVersion(obj client.Object) string {
+	return fmt.Sprintf("%s:%s:%s", objectKey(obj), obj.GetUID(), obj.GetResourceVersion())
+}
+
+func hashStrings(values []string) string {
+	h := sha256.New()
+	for _, v := range values {
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// storer returns a store listing only the component's routes and looking up all the other objects in s.
+func (c routesComponent) storer(s store.Storer) store.Storer {
+	return routesComponentStorer{Storer: s, component: c}
+}
+
+// routesComponentStorer is a store.Storer listing only Ingresses and Gateway API routes of a single
+// routes component.
+type routesComponentStorer struct {
+	store.Storer
+	component routesComponent
+}
+
+func (s routesComponentStorer) ListIngressesV1() []*netv1.Ingress {
+	return s.component.ingresses
+}
+
+func (s routesComponentStorer) ListHTTPRoutes() ([]*gatewayapi.HTTPRoute, error) {
+	return s.component.httpRoutes, nil
+}
+
+func (s routesComponentStorer) ListUDPRoutes() ([]*gatewayapi.UDPRoute, error) {
+	return s.component.udpRoutes, nil
+}
+
+func (s routesComponentStorer) ListTCPRoutes() ([]*gatewayapi.TCPRoute, error) {
+	return s.component.tcpRoutes, nil
+}
+
+func (s routesComponentStorer) ListTLSRoutes() ([]*gatewayapi.TLSRoute, error) {
+	return s.component.tlsRoutes, nil
+}
+
+func (s routesComponentStorer) ListGRPCRoutes() ([]*gatewayapi.GRPCRoute, error) {
+	return s.component.grpcRoutes, nil
+}
+
+// mergeRoutesComponentTranslations merges translations of routes components. Kong entities are copied,
+// so that the translations can be reused after the result is modified. It returns errRoutesComponentsCollision
+// if the same Kong Service or Upstream was translated from multiple components (e.g. from routes referring
+// to a non-existent Service), as such entities have to be translated together.
+func mergeRoutesComponentTranslations(translations []routesComponentTranslation) (routesTranslationResult, error) {
+	result := routesTranslationResult{
+		ingressRules:        newIngressRules(),
+		servicesToBeSkipped: make(map[string]any),
+	}
+	upstreamNames := sets.New[string]()
+	for _, translation := range translations {
+		rules := translation.routes.ingressRules
+		for name, service := range rules.ServiceNameToServices {
+			if _, ok := result.ingressRules.ServiceNameToServices[name]; ok {
+				return routesTranslationResult{}, fmt.Errorf("%w: Service %s", errRoutesComponentsCollision, name)
+			}
+			result.ingressRules.ServiceNameToServices[name] = copyService(service)
+			result.ingressRules.ServiceNameToParent[name] = rules.ServiceNameToParent[name]
+		}
+		result.ingressRules.SecretNameToSNIs.merge(rules.SecretNameToSNIs)
+		maps.Copy(result.servicesToBeSkipped, translation.routes.servicesToBeSkipped)
+
+		for _, upstream := range translation.routes.upstreams {
+			name := lo.FromPtr(upstream.Name)
+			if upstreamNames.Has(name) {
+				return routesTranslationResult{}, fmt.Errorf("%w: Upstream %s", errRoutesComponentsCollision, name)
+			}
+			upstreamNames.Insert(name)
+			result.upstreams = append(result.upstreams, copyUpstream(upstream))
+		}
+	}
+	return result, nil
+}
+
+// copyService returns a copy of the Service that can be modified without affecting the original.
+// Kubernetes objects the Service refers to are not copied as they're not modified.
+func copyService(service kongstate.Service) kongstate.Service {
+	service.Service = *service.Service.DeepCopy()
+	service.Routes = lo.Map(service.Routes, func(route kongstate.Route, _ int) kongstate.Route {
+		route.Route = *route.Route.DeepCopy()
+		route.Plugins = copyPlugins(route.Plugins)
+		return route
+	})
+	service.Plugins = copyPlugins(service.Plugins)
+	service.Backends = slices.Clone(service.Backends)
+	service.K8sServices = maps.Clone(service.K8sServices)
+	return service
+}
+
+func copyUpstream(upstream kongstate.Upstream) kongstate.Upstream {
+	upstream.Upstream = *upstream.Upstream.DeepCopy()
+	upstream.Targets = lo.Map(upstream.Targets, func(target kongstate.Target, _ int) kongstate.Target {
+		return kongstate.Target{Target: *target.Target.DeepCopy()}
+	})
+	upstream.Service = copyService(upstream.Service)
+	return upstream
+}
+
+func copyPlugins(plugins []kong.Plugin) []kong.Plugin {
+	return lo.Map(plugins, func(plugin kong.Plugin, _ int) kong.Plugin {
+		return *plugin.DeepCopy()
+	})
+}
+
+// disjointSets is a union-find data structure.
+type disjointSets[T comparable] struct {
+	parents map[T]T
+}
+
+func newDisjointSets[T comparable]() disjointSets[T] {
+	return disjointSets[T]{parents: make(map[T]T)}
+}
+
+func (d disjointSets[T]) add(v T) {
+	if _, ok := d.parents[v]; !ok {
+		d.parents[v] = v
+	}
+}
+
+// find returns the representative of the set v belongs to.
+func (d disjointSets[T]) find(v T) T {
+	parent, ok := d.parents[v]
+	if !ok {
+		d.parents[v] = v
+		return v
+	}
+	if parent == v {
+		return v
+	}
+	root := d.find(parent)
+	d.parents[v] = root
+	return root
+}
+
+func (d disjointSets[T]) union(a, b T) {
+	rootA, rootB := d.find(a), d.find(b)
+	if rootA != rootB {
+		d.parents[rootA] = rootB
+	}
+}
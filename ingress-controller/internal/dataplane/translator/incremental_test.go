@@ -0,0 +1,361 @@
+package translator
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	configurationv1 "github.com/kong/kubernetes-configuration/v2/api/configuration/v1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+	"github.com/kong/kong-operator/ingress-controller/internal/util/builder"
+)
+
+func TestIncrementalTranslator_BuildKongConfig(t *testing.T) {
+	var (
+		ingress = func(name, path, service, resourceVersion string) *netv1.Ingress {
+			return &netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            name,
+					UID:             k8stypes.UID("ingress-" + name),
+					ResourceVersion: resourceVersion,
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+					},
+				},
+				Spec: netv1.IngressSpec{
+					Rules: []netv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: netv1.IngressRuleValue{
+							HTTP: &netv1.HTTPIngressRuleValue{
+								Paths: []netv1.HTTPIngressPath{{
+									Path:     path,
+									PathType: lo.ToPtr(netv1.PathTypePrefix),
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: service,
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+		}
+		service = func(name string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            name,
+					UID:             k8stypes.UID("service-" + name),
+					ResourceVersion: "1",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 80}},
+				},
+			}
+		}
+		// markTranslations marks all cached components translations to check whether they're reused by the next build.
+		markTranslations = func(it *IncrementalTranslator) {
+			for key, translation := range it.components {
+				skipped := maps.Clone(translation.routes.servicesToBeSkipped)
+				if skipped == nil {
+					skipped = make(map[string]any)
+				}
+				skipped["marked"] = nil
+				translation.routes.servicesToBeSkipped = skipped
+				it.components[key] = translation
+			}
+		}
+		countReusedTranslations = func(it *IncrementalTranslator) int {
+			return lo.CountBy(lo.Values(it.components), func(translation routesComponentTranslation) bool {
+				_, ok := translation.routes.servicesToBeSkipped["marked"]
+				return ok
+			})
+		}
+		fullBuild = func(t *testing.T, storer store.Storer) KongConfigBuildingResult {
+			return mustNewTranslator(t, storer).BuildKongConfig()
+		}
+	)
+
+	cache := store.NewCacheStores()
+	for _, obj := range []any{
+		ingress("ingress-1", "/foo", "svc-1", "1"),
+		ingress("ingress-2", "/bar", "svc-2", "1"),
+		service("svc-1"),
+		service("svc-2"),
+	} {
+		require.NoError(t, cache.Add(obj))
+	}
+	storer := store.New(cache, annotations.DefaultIngressClass, logr.Discard())
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	it := NewIncrementalTranslator(mustNewTranslator(t, storer), cache, time.Hour)
+	it.now = func() time.Time { return now }
+
+	t.Log("Initial build translates all routes")
+	result := it.BuildKongConfig()
+	expected := fullBuild(t, storer)
+	require.Empty(t, result.TranslationFailures)
+	require.Len(t, it.components, 2)
+	assert.Equal(t, expected.KongState.Services, result.KongState.Services)
+	assert.Equal(t, expected.KongState.Upstreams, result.KongState.Upstreams)
+	assert.ElementsMatch(t, expected.ConfiguredKubernetesObjects, result.ConfiguredKubernetesObjects)
+
+	t.Log("Only the changed Ingress is re-translated")
+	markTranslations(it)
+	require.NoError(t, cache.Add(ingress("ingress-2", "/baz", "svc-2", "2")))
+	result = it.BuildKongConfig()
+	require.Len(t, it.components, 2)
+	assert.Equal(t, 1, countReusedTranslations(it))
+	assert.Equal(t, fullBuild(t, storer).KongState.Services, result.KongState.Services)
+
+	t.Log("Change of an object not referenced by routes doesn't cause any route to be re-translated")
+	markTranslations(it)
+	require.NoError(t, cache.Add(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "secret",
+			UID:             "secret",
+			ResourceVersion: "1",
+		},
+	}))
+	result = it.BuildKongConfig()
+	assert.Equal(t, 2, countReusedTranslations(it))
+	assert.Equal(t, fullBuild(t, storer).KongState.Services, result.KongState.Services)
+
+	t.Log("Change of an object referenced by a route causes only its component to be re-translated")
+	plugin := func(resourceVersion string) *configurationv1.KongPlugin {
+		return &configurationv1.KongPlugin{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            "plugin",
+				UID:             "plugin",
+				ResourceVersion: resourceVersion,
+			},
+			PluginName: "key-auth",
+		}
+	}
+	ingressWithPlugin := ingress("ingress-1", "/foo", "svc-1", "3")
+	ingressWithPlugin.Annotations[annotations.AnnotationPrefix+annotations.PluginsKey] = "plugin"
+	require.NoError(t, cache.Add(plugin("1")))
+	require.NoError(t, cache.Add(ingressWithPlugin))
+	it.BuildKongConfig()
+	markTranslations(it)
+	require.NoError(t, cache.Add(plugin("2")))
+	result = it.BuildKongConfig()
+	assert.Equal(t, 1, countReusedTranslations(it))
+	assert.Equal(t, fullBuild(t, storer).KongState.Services, result.KongState.Services)
+
+	t.Log("Change of an object all routes depend on causes all routes to be re-translated")
+	markTranslations(it)
+	require.NoError(t, cache.Add(&gatewayapi.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "grant",
+			UID:             "grant",
+			ResourceVersion: "1",
+		},
+	}))
+	result = it.BuildKongConfig()
+	assert.Zero(t, countReusedTranslations(it))
+	assert.Equal(t, fullBuild(t, storer).KongState.Services, result.KongState.Services)
+
+	t.Log("All routes are re-translated periodically")
+	markTranslations(it)
+	now = now.Add(time.Hour)
+	result = it.BuildKongConfig()
+	assert.Zero(t, countReusedTranslations(it))
+	assert.Equal(t, fullBuild(t, storer).KongState.Services, result.KongState.Services)
+}
+
+func TestIncrementalTranslator_BuildKongConfigHTTPRoutesEquivalentToFullBuild(t *testing.T) {
+	var (
+		httpRoute = func(name, path, service, resourceVersion string) *gatewayapi.HTTPRoute {
+			return &gatewayapi.HTTPRoute{
+				TypeMeta: gatewayapi.V1HTTPRouteTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            name,
+					UID:             k8stypes.UID("httproute-" + name),
+					ResourceVersion: resourceVersion,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: commonRouteSpecMock("gateway"),
+					Hostnames:       []gatewayapi.Hostname{"example.com"},
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{
+							builder.NewHTTPRouteMatch().WithPathPrefix(path).Build(),
+						},
+						BackendRefs: []gatewayapi.HTTPBackendRef{
+							builder.NewHTTPBackendRef(service).WithPort(80).Build(),
+						},
+					}},
+				},
+			}
+		}
+		service = func(name string) *corev1.Service {
+			return &corev1.Service{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: "v1",
+					Kind:       "Service",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            name,
+					UID:             k8stypes.UID("service-" + name),
+					ResourceVersion: "1",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 80}},
+				},
+			}
+		}
+	)
+
+	testCases := []struct {
+		name               string
+		expressionRoutes   bool
+		expectedComponents int
+	}{
+		{
+			name:               "traditional router",
+			expectedComponents: 2,
+		},
+		{
+			name:             "expressions router falls back to full build",
+			expressionRoutes: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := store.NewCacheStores()
+			for _, obj := range []any{
+				httpRoute("httproute-1", "/foo", "svc-1", "1"),
+				httpRoute("httproute-2", "/foo/bar", "svc-2", "1"),
+				httpRoute("httproute-3", "/baz", "svc-1", "1"),
+				service("svc-1"),
+				service("svc-2"),
+			} {
+				require.NoError(t, cache.Add(obj))
+			}
+			storer := store.New(cache, annotations.DefaultIngressClass, logr.Discard())
+			newTranslator := func() *Translator {
+				tr := mustNewTranslator(t, storer)
+				tr.featureFlags.ExpressionRoutes = tc.expressionRoutes
+				return tr
+			}
+			requireEquivalentToFullBuild := func(t *testing.T, result KongConfigBuildingResult) {
+				expected := newTranslator().BuildKongConfig()
+				require.Empty(t, result.TranslationFailures)
+				require.NotEmpty(t, result.KongState.Services)
+				assert.Equal(t, expected.KongState.Services, result.KongState.Services)
+				assert.Equal(t, expected.KongState.Upstreams, result.KongState.Upstreams)
+				assert.ElementsMatch(t, expected.ConfiguredKubernetesObjects, result.ConfiguredKubernetesObjects)
+			}
+
+			it := NewIncrementalTranslator(newTranslator(), cache, time.Hour)
+
+			t.Log("Initial build is equivalent to a full build")
+			requireEquivalentToFullBuild(t, it.BuildKongConfig())
+			require.Len(t, it.components, tc.expectedComponents)
+
+			t.Log("Build after an HTTPRoute changed is equivalent to a full build")
+			require.NoError(t, cache.Add(httpRoute("httproute-2", "/foo/bar/baz", "svc-2", "2")))
+			requireEquivalentToFullBuild(t, it.BuildKongConfig())
+
+			t.Log("Build after an HTTPRoute moved to another Service is equivalent to a full build")
+			require.NoError(t, cache.Add(httpRoute("httproute-3", "/baz", "svc-2", "2")))
+			requireEquivalentToFullBuild(t, it.BuildKongConfig())
+			require.Len(t, it.components, tc.expectedComponents)
+		})
+	}
+}
+
+func TestIncrementalTranslator_BuildKongConfigDefaultBackendsEquivalentToFullBuild(t *testing.T) {
+	var (
+		ingress = func(name, service string, created time.Time, resourceVersion string) *netv1.Ingress {
+			return &netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              name,
+					UID:               k8stypes.UID("ingress-" + name),
+					ResourceVersion:   resourceVersion,
+					CreationTimestamp: metav1.NewTime(created),
+					Annotations: map[string]string{
+						annotations.IngressClassKey: annotations.DefaultIngressClass,
+					},
+				},
+				Spec: netv1.IngressSpec{
+					DefaultBackend: &netv1.IngressBackend{
+						Service: &netv1.IngressServiceBackend{
+							Name: service,
+							Port: netv1.ServiceBackendPort{Number: 80},
+						},
+					},
+				},
+			}
+		}
+		service = func(name string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:       "default",
+					Name:            name,
+					UID:             k8stypes.UID("service-" + name),
+					ResourceVersion: "1",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{{Port: 80}},
+				},
+			}
+		}
+		created = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	cache := store.NewCacheStores()
+	for _, obj := range []any{
+		ingress("ingress-1", "svc-1", created.Add(time.Hour), "1"),
+		ingress("ingress-2", "svc-2", created.Add(2*time.Hour), "1"),
+		service("svc-1"),
+		service("svc-2"),
+		service("svc-3"),
+	} {
+		require.NoError(t, cache.Add(obj))
+	}
+	storer := store.New(cache, annotations.DefaultIngressClass, logr.Discard())
+	requireEquivalentToFullBuild := func(t *testing.T, result KongConfigBuildingResult) {
+		expected := mustNewTranslator(t, storer).BuildKongConfig()
+		require.Len(t, result.KongState.Services, 1, "only one default backend should be translated")
+		assert.Equal(t, expected.KongState.Services, result.KongState.Services)
+		assert.Equal(t, expected.KongState.Upstreams, result.KongState.Upstreams)
+	}
+
+	it := NewIncrementalTranslator(mustNewTranslator(t, storer), cache, time.Hour)
+
+	t.Log("Only the default backend of the oldest Ingress is translated")
+	requireEquivalentToFullBuild(t, it.BuildKongConfig())
+	require.Len(t, it.components, 2)
+
+	t.Log("An older Ingress with a default backend replaces the previous one")
+	require.NoError(t, cache.Add(ingress("ingress-3", "svc-3", created, "1")))
+	requireEquivalentToFullBuild(t, it.BuildKongConfig())
+
+	t.Log("The previous default backend is translated again once the older Ingress is deleted")
+	require.NoError(t, cache.Delete(ingress("ingress-3", "svc-3", created, "1")))
+	requireEquivalentToFullBuild(t, it.BuildKongConfig())
+}
@@ -0,0 +1,108 @@
+package translator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	netv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+)
+
+// RouteConflict is a request match claimed by HTTPRoutes or Ingresses routing the matching requests to
+// different backends. Requests are routed by the route created first, the other route loses the conflict.
+type RouteConflict struct {
+	// Object is the HTTPRoute or Ingress losing the conflict.
+	Object client.Object
+	// Kind is the kind of the Object.
+	Kind string
+	// Location is the location of the conflicting match in the Object's spec.
+	Location string
+
+	// ConflictingObject is the HTTPRoute or Ingress requests matched by the Object are routed by.
+	ConflictingObject client.Object
+	// ConflictingKind is the kind of the ConflictingObject.
+	ConflictingKind string
+	// ConflictingLocation is the location of the conflicting match in the ConflictingObject's spec.
+	ConflictingLocation string
+
+	// Expression is the ATC expression of the conflicting match.
+	Expression string
+}
+
+// Message describes the conflict from the losing Object's perspective.
+func (c RouteConflict) Message() string {
+	return fmt.Sprintf("%s conflicts with %s of %s %s/%s routing the same requests to different backends",
+		c.Location, c.ConflictingLocation, c.ConflictingKind, c.ConflictingObject.GetNamespace(), c.ConflictingObject.GetName(),
+	)
+}
+
+// DetectRouteConflicts returns conflicts between matches of different HTTPRoutes and Ingresses matching the
+// same requests (i.e. the same hosts, paths, methods, headers and query parameters) and routing them to
+// different backends. Matches are compared with their ATC expressions, so both router flavors report the same
+// conflicts. Only matches with exactly equal expressions are detected: matches that overlap without being
+// equal (e.g. a path prefix covering another match's exact path) or that are equivalent but translate to
+// different expressions (e.g. a regex path matching the same requests as another match's exact path)
+// aren't reported. The oldest object wins a conflict, ties are broken by the objects' "{namespace}/{name}".
+func DetectRouteConflicts(httpRoutes []*gatewayapi.HTTPRoute, ingresses []*netv1.Ingress) []RouteConflict {
+	var claims []subtranslator.RouteMatchClaim
+	for _, httproute := range httpRoutes {
+		claims = append(claims, subtranslator.RouteMatchClaimsFromHTTPRoute(httproute)...)
+	}
+	for _, ingress := range ingresses {
+		claims = append(claims, subtranslator.RouteMatchClaimsFromIngress(ingress)...)
+	}
+
+	// Claims are sorted from the winning ones, so the first claim of an expression routes its requests.
+	slices.SortStableFunc(claims, func(a, b subtranslator.RouteMatchClaim) int {
+		return compareRouteObjects(a.Object, b.Object)
+	})
+	winningClaims := make(map[string]subtranslator.RouteMatchClaim, len(claims))
+	var conflicts []RouteConflict
+	for _, claim := range claims {
+		winning, ok := winningClaims[claim.Expression]
+		if !ok {
+			winningClaims[claim.Expression] = claim
+			continue
+		}
+		// Matches of the same object are prioritized by the object's own rules.
+		if winning.Object == claim.Object || winning.Backends == claim.Backends {
+			continue
+		}
+		conflicts = append(conflicts, RouteConflict{
+			Object:              claim.Object,
+			Kind:                claim.Kind,
+			Location:            claim.Location,
+			ConflictingObject:   winning.Object,
+			ConflictingKind:     winning.Kind,
+			ConflictingLocation: winning.Location,
+			Expression:          claim.Expression,
+		})
+	}
+	return conflicts
+}
+
+// compareRouteObjects orders objects by their creation timestamps and "{namespace}/{name}".
+func compareRouteObjects(a, b client.Object) int {
+	aCreated, bCreated := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !aCreated.Equal(&bCreated) {
+		if aCreated.Before(&bCreated) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a.GetNamespace()+"/"+a.GetName(), b.GetNamespace()+"/"+b.GetName())
+}
+
+// detectRouteConflicts returns conflicts between HTTPRoutes and Ingresses in the store.
+func (t *Translator) detectRouteConflicts() []RouteConflict {
+	httpRoutes, err := t.storer.ListHTTPRoutes()
+	if err != nil {
+		t.logger.Error(err, "Failed to list HTTPRoutes")
+		return nil
+	}
+	return DetectRouteConflicts(httpRoutes, t.storer.ListIngressesV1())
+}
@@ -0,0 +1,174 @@
+package translator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+)
+
+func TestDetectRouteConflicts(t *testing.T) {
+	var (
+		older = metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		newer = metav1.NewTime(older.Add(time.Hour))
+
+		httpRoute = func(name string, created metav1.Time, path, service string) *gatewayapi.HTTPRoute {
+			return &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              name,
+					CreationTimestamp: created,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					Hostnames: []gatewayapi.Hostname{"example.com"},
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{{
+							Path: &gatewayapi.HTTPPathMatch{
+								Type:  lo.ToPtr(gatewayapi.PathMatchPathPrefix),
+								Value: lo.ToPtr(path),
+							},
+						}},
+						BackendRefs: []gatewayapi.HTTPBackendRef{{
+							BackendRef: gatewayapi.BackendRef{
+								BackendObjectReference: gatewayapi.BackendObjectReference{
+									Name: gatewayapi.ObjectName(service),
+									Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+								},
+							},
+						}},
+					}},
+				},
+			}
+		}
+		ingress = func(name string, created metav1.Time, path, service string) *netv1.Ingress {
+			return &netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              name,
+					CreationTimestamp: created,
+				},
+				Spec: netv1.IngressSpec{
+					Rules: []netv1.IngressRule{{
+						Host: "example.com",
+						IngressRuleValue: netv1.IngressRuleValue{
+							HTTP: &netv1.HTTPIngressRuleValue{
+								Paths: []netv1.HTTPIngressPath{{
+									Path:     path,
+									PathType: lo.ToPtr(netv1.PathTypePrefix),
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: service,
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								}},
+							},
+						},
+					}},
+				},
+			}
+		}
+	)
+
+	testCases := []struct {
+		name             string
+		httpRoutes       []*gatewayapi.HTTPRoute
+		ingresses        []*netv1.Ingress
+		expectedMessages map[string][]string
+	}{
+		{
+			name: "HTTPRoute and Ingress matching different paths don't conflict",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				httpRoute("route", older, "/foo", "svc-1"),
+			},
+			ingresses: []*netv1.Ingress{
+				ingress("ingress", newer, "/bar", "svc-2"),
+			},
+		},
+		{
+			name: "HTTPRoute and Ingress matching the same requests with the same backend don't conflict",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				httpRoute("route", older, "/foo", "svc-1"),
+			},
+			ingresses: []*netv1.Ingress{
+				ingress("ingress", newer, "/foo", "svc-1"),
+			},
+		},
+		{
+			name: "newer Ingress conflicts with HTTPRoute matching the same requests with a different backend",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				httpRoute("route", older, "/foo", "svc-1"),
+			},
+			ingresses: []*netv1.Ingress{
+				ingress("ingress", newer, "/foo/", "svc-2"),
+			},
+			expectedMessages: map[string][]string{
+				"Ingress/ingress": {
+					"rules[0].http.paths[0] conflicts with rules[0].matches[0] of HTTPRoute default/route routing the same requests to different backends",
+				},
+			},
+		},
+		{
+			name: "newer HTTPRoute conflicts with Ingress matching the same requests with a different backend",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				httpRoute("route", newer, "/foo", "svc-1"),
+			},
+			ingresses: []*netv1.Ingress{
+				ingress("ingress", older, "/foo", "svc-2"),
+			},
+			expectedMessages: map[string][]string{
+				"HTTPRoute/route": {
+					"rules[0].matches[0] conflicts with rules[0].http.paths[0] of Ingress default/ingress routing the same requests to different backends",
+				},
+			},
+		},
+		{
+			name: "HTTPRoutes created at the same time are ordered by names",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				httpRoute("route-b", older, "/foo", "svc-1"),
+				httpRoute("route-a", older, "/foo", "svc-2"),
+			},
+			expectedMessages: map[string][]string{
+				"HTTPRoute/route-b": {
+					"rules[0].matches[0] conflicts with rules[0].matches[0] of HTTPRoute default/route-a routing the same requests to different backends",
+				},
+			},
+		},
+		{
+			name: "matches of the same HTTPRoute don't conflict",
+			httpRoutes: []*gatewayapi.HTTPRoute{
+				func() *gatewayapi.HTTPRoute {
+					route := httpRoute("route", older, "/foo", "svc-1")
+					rule := *httpRoute("route", older, "/foo", "svc-2").Spec.Rules[0].DeepCopy()
+					route.Spec.Rules = append(route.Spec.Rules, rule)
+					return route
+				}(),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conflicts := DetectRouteConflicts(tc.httpRoutes, tc.ingresses)
+
+			messages := make(map[string][]string)
+			for _, c := range conflicts {
+				require.NotNil(t, c.Object)
+				require.NotNil(t, c.ConflictingObject)
+				key := c.Kind + "/" + c.Object.GetName()
+				messages[key] = append(messages[key], c.Message())
+			}
+			if len(tc.expectedMessages) == 0 {
+				assert.Empty(t, messages)
+				return
+			}
+			assert.Equal(t, tc.expectedMessages, messages)
+		})
+	}
+}
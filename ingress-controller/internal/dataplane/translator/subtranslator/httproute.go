@@ -281,14 +281,25 @@ func applyTimeoutToServiceFromHTTPRouteRule(svc *kongstate.Service, rule gateway
 	if rule.Timeouts == nil {
 		return
 	}
+	// Kong doesn't limit the duration of the whole request, so the request timeout
+	// is applied to the backend when the backendRequest timeout is not set.
+	// The backendRequest timeout must not be greater than the request timeout.
+	timeout := rule.Timeouts.BackendRequest
+	if timeout == nil {
+		timeout = rule.Timeouts.Request
+	}
 	backendRequestTimeout := DefaultServiceTimeout
-	if rule.Timeouts != nil && rule.Timeouts.BackendRequest != nil {
-		duration, err := time.ParseDuration(string(*rule.Timeouts.BackendRequest))
-		// We ignore the error here because the rule.Timeouts.BackendRequest is validated
+	if timeout != nil {
+		duration, err := time.ParseDuration(string(*timeout))
+		// We ignore the error here because the timeouts are validated
 		// to be a strict subset of Golang time.ParseDuration so it should never happen
 		if err != nil {
 			return
 		}
+		// Zero duration disables the timeout, keep Kong's default then.
+		if duration == 0 {
+			return
+		}
 		backendRequestTimeout = int(duration.Milliseconds())
 	}
 	// if the backendRequestTimeout is the same as the default timeout, we don't need to apply it to the service.
@@ -918,6 +929,8 @@ func generateKongRoutePathFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch) []
 type setKongRoutePluginsOptions struct {
 	expressionsRouterEnabled  bool
 	redirectKongPluginEnabled bool
+	// routeNamespace is the namespace of the route the filters belong to.
+	routeNamespace string
 }
 
 // setRoutePlugins converts HTTPRouteFilter into Kong plugins. The plugins are set into the given kongstate.Route.
@@ -931,6 +944,7 @@ func setRoutePlugins(
 	tags []*string,
 	options setKongRoutePluginsOptions,
 ) error {
+	options.routeNamespace = route.Ingress.Namespace
 	generatedPlugins, err := generatePluginsFromHTTPRouteFilters(filters, path, tags, options)
 	if err != nil {
 		return err
@@ -980,6 +994,7 @@ func generatePluginsFromHTTPRouteFilters(
 		kongPlugins                 []kong.Plugin
 		pluginNamesFromExtensionRef []string
 		kongRouteModifiers          []kongRouteModifier
+		requestMirrors              []gatewayapi.HTTPRequestMirrorFilter
 	)
 
 	for _, filter := range filters {
@@ -1014,6 +1029,12 @@ func generatePluginsFromHTTPRouteFilters(
 			transformerPlugins = append(transformerPlugins, plugins...)
 			kongRouteModifiers = append(kongRouteModifiers, routeModifiers...)
 
+		case gatewayapi.HTTPRouteFilterRequestMirror:
+			if err := validateRequestMirrorFilter(filter.RequestMirror, options.routeNamespace); err != nil {
+				return httpRouteFiltersOriginatedPlugins{}, err
+			}
+			requestMirrors = append(requestMirrors, *filter.RequestMirror)
+
 		default:
 			// filters of other types are not supported
 			return httpRouteFiltersOriginatedPlugins{}, fmt.Errorf("httpFilter %s unsupported", filter.Type)
@@ -1027,6 +1048,9 @@ func generatePluginsFromHTTPRouteFilters(
 		return httpRouteFiltersOriginatedPlugins{}, fmt.Errorf("failed to merge transformerPlugins of the same type: %w", err)
 	}
 	kongPlugins = append(kongPlugins, transformerPluginsToKongPlugins(transformerPlugins)...)
+	if len(requestMirrors) > 0 {
+		kongPlugins = append(kongPlugins, generateRequestMirrorKongPlugin(requestMirrors, options.routeNamespace))
+	}
 
 	for i := range kongPlugins {
 		// This plugin is derived from an HTTPRoute filter, not a KongPlugin, so we apply tags indicating that
@@ -1213,6 +1237,132 @@ func generateRequestRedirectUsingRedirectKongPlugin(
 	return redirectPlugin
 }
 
+// requestMirrorLuaPrelude is the Lua code of the pre-function plugin used to mirror requests.
+// It defines the mirror(url, numerator, denominator) function which sends a copy of the request
+// to the given URL, in a timer so that the proxied request is not delayed, for the given fraction
+// of requests. The responses of the mirrored requests are ignored.
+// Mirroring is skipped when the worker already has too many pending or running timers, and
+// the mirrored requests are subject to connect, send and read timeouts, so that a slow mirror
+// cannot accumulate timers. Request bodies which have been buffered to a file are read from it
+// when the io library is available, otherwise such requests are not mirrored.
+// It requires resty.http to be allowed in the sandbox, e.g. with
+// untrusted_lua_sandbox_requires = resty.http in the Kong configuration.
+const requestMirrorLuaPrelude = `local http = require("resty.http")
+local max_timers = 256
+local timeout_ms = 5000
+local method = kong.request.get_method()
+local path = kong.request.get_path_with_query()
+local headers = kong.request.get_headers()
+headers["transfer-encoding"] = nil
+local body, body_err = kong.request.get_raw_body()
+if body == nil then
+  local body_file = ngx.req.get_body_file()
+  if body_file then
+    body_err = "request body buffered to a file cannot be read"
+    if io then
+      local f, err = io.open(body_file, "rb")
+      if f then
+        body, body_err = f:read("*a"), nil
+        f:close()
+      else
+        body_err = err
+      end
+    end
+  end
+end
+local function send(premature, url)
+  if premature then
+    return
+  end
+  local httpc = http.new()
+  httpc:set_timeouts(timeout_ms, timeout_ms, timeout_ms)
+  local _, err = httpc:request_uri(url .. path, { method = method, headers = headers, body = body })
+  if err then
+    kong.log.warn("failed mirroring request to ", url, ": ", err)
+  end
+end
+local function mirror(url, numerator, denominator)
+  if numerator < denominator and math.random() * denominator >= numerator then
+    return
+  end
+  if body == nil and body_err then
+    kong.log.warn("skipping request mirroring to ", url, ": ", body_err)
+    return
+  end
+  if ngx.timer.pending_count() + ngx.timer.running_count() >= max_timers then
+    kong.log.warn("skipping request mirroring to ", url, ": too many pending timers")
+    return
+  end
+  local ok, err = ngx.timer.at(0, send, url)
+  if not ok then
+    kong.log.warn("failed scheduling request mirroring to ", url, ": ", err)
+  end
+end
+`
+
+// validateRequestMirrorFilter checks whether the RequestMirror filter can be translated.
+// Mirroring to Services in other namespaces than the route's one is not supported.
+func validateRequestMirrorFilter(filter *gatewayapi.HTTPRequestMirrorFilter, namespace string) error {
+	if filter == nil {
+		return fmt.Errorf("%s is not provided", gatewayapi.HTTPRouteFilterRequestMirror)
+	}
+	ref := filter.BackendRef
+	if ref.Group != nil && *ref.Group != "" && *ref.Group != "core" {
+		return fmt.Errorf("%s backendRef group %s unsupported", gatewayapi.HTTPRouteFilterRequestMirror, *ref.Group)
+	}
+	if ref.Kind != nil && *ref.Kind != "Service" {
+		return fmt.Errorf("%s backendRef kind %s unsupported", gatewayapi.HTTPRouteFilterRequestMirror, *ref.Kind)
+	}
+	if ref.Namespace != nil && string(*ref.Namespace) != namespace {
+		return fmt.Errorf("%s backendRef in a different namespace unsupported", gatewayapi.HTTPRouteFilterRequestMirror)
+	}
+	if ref.Port == nil {
+		return fmt.Errorf("%s backendRef port is not provided", gatewayapi.HTTPRouteFilterRequestMirror)
+	}
+	if filter.Percent != nil && filter.Fraction != nil {
+		return fmt.Errorf("%s cannot have both percent and fraction set", gatewayapi.HTTPRouteFilterRequestMirror)
+	}
+	if filter.Fraction != nil && lo.FromPtrOr(filter.Fraction.Denominator, 100) <= 0 {
+		return fmt.Errorf("%s fraction denominator must be greater than 0", gatewayapi.HTTPRouteFilterRequestMirror)
+	}
+	return nil
+}
+
+// requestMirrorURL returns the URL of the Kubernetes Service requests are mirrored to.
+// The Service is resolved through the cluster DNS.
+func requestMirrorURL(ref gatewayapi.BackendObjectReference, namespace string) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d", ref.Name, namespace, lo.FromPtr(ref.Port))
+}
+
+// requestMirrorRatio returns the numerator and denominator of the fraction of requests to mirror.
+func requestMirrorRatio(filter gatewayapi.HTTPRequestMirrorFilter) (int32, int32) {
+	switch {
+	case filter.Percent != nil:
+		return *filter.Percent, 100
+	case filter.Fraction != nil:
+		return filter.Fraction.Numerator, lo.FromPtrOr(filter.Fraction.Denominator, 100)
+	default:
+		return 100, 100
+	}
+}
+
+// generateRequestMirrorKongPlugin converts RequestMirror filters into a single kong.Plugin of type
+// pre-function, as only one plugin of a given type can be configured on a Kong Route.
+func generateRequestMirrorKongPlugin(filters []gatewayapi.HTTPRequestMirrorFilter, namespace string) kong.Plugin {
+	var code strings.Builder
+	code.WriteString(requestMirrorLuaPrelude)
+	for _, filter := range filters {
+		numerator, denominator := requestMirrorRatio(filter)
+		fmt.Fprintf(&code, "mirror(%q, %d, %d)\n", requestMirrorURL(filter.BackendRef, namespace), numerator, denominator)
+	}
+	return kong.Plugin{
+		Name: kong.String("pre-function"),
+		Config: kong.Configuration{
+			"access": []string{code.String()},
+		},
+	}
+}
+
 func generateExtensionRefKongPlugin(modifier *gatewayapi.LocalObjectReference) (string, error) {
 	if modifier.Group != "configuration.konghq.com" || modifier.Kind != "KongPlugin" {
 		return "", fmt.Errorf("plugin %s/%s unsupported", modifier.Group, modifier.Kind)
@@ -3,6 +3,7 @@ package subtranslator
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -293,6 +294,172 @@ func TestGeneratePluginsFromHTTPRouteFilters(t *testing.T) {
 	}
 }
 
+func TestApplyTimeoutToServiceFromHTTPRouteRule(t *testing.T) {
+	testCases := []struct {
+		name            string
+		timeouts        *gatewayapi.HTTPRouteTimeouts
+		expectedTimeout *int
+	}{
+		{
+			name: "no timeouts",
+		},
+		{
+			name: "backend request timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				BackendRequest: lo.ToPtr(gatewayapi.Duration("500ms")),
+			},
+			expectedTimeout: lo.ToPtr(500),
+		},
+		{
+			name: "request timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request: lo.ToPtr(gatewayapi.Duration("2s")),
+			},
+			expectedTimeout: lo.ToPtr(2000),
+		},
+		{
+			name: "backend request timeout takes precedence over request timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request:        lo.ToPtr(gatewayapi.Duration("2s")),
+				BackendRequest: lo.ToPtr(gatewayapi.Duration("1s")),
+			},
+			expectedTimeout: lo.ToPtr(1000),
+		},
+		{
+			name: "zero request timeout disables the timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request: lo.ToPtr(gatewayapi.Duration("0s")),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := kongstate.Service{}
+			applyTimeoutToServiceFromHTTPRouteRule(&svc, gatewayapi.HTTPRouteRule{Timeouts: tc.timeouts})
+			assert.Equal(t, tc.expectedTimeout, svc.ReadTimeout)
+			assert.Equal(t, tc.expectedTimeout, svc.WriteTimeout)
+			assert.Equal(t, tc.expectedTimeout, svc.ConnectTimeout)
+		})
+	}
+}
+
+func TestGenerateRequestMirrorKongPlugin(t *testing.T) {
+	testCases := []struct {
+		name                string
+		filters             []gatewayapi.HTTPRouteFilter
+		expectedMirrorCalls []string
+		expectedErr         bool
+	}{
+		{
+			name: "single mirror of all requests",
+			filters: []gatewayapi.HTTPRouteFilter{
+				{
+					Type: gatewayapi.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.BackendObjectReference{
+							Name: "staging",
+							Port: lo.ToPtr(gatewayapi.PortNumber(8080)),
+						},
+					},
+				},
+			},
+			expectedMirrorCalls: []string{
+				`mirror("http://staging.default.svc:8080", 100, 100)`,
+			},
+		},
+		{
+			name: "multiple mirrors with percent and fraction",
+			filters: []gatewayapi.HTTPRouteFilter{
+				{
+					Type: gatewayapi.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.BackendObjectReference{
+							Name:      "staging",
+							Namespace: lo.ToPtr(gatewayapi.Namespace("default")),
+							Port:      lo.ToPtr(gatewayapi.PortNumber(80)),
+						},
+						Percent: lo.ToPtr(int32(25)),
+					},
+				},
+				{
+					Type: gatewayapi.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.BackendObjectReference{
+							Name: "canary",
+							Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+						},
+						Fraction: &gatewayapi.Fraction{
+							Numerator:   1,
+							Denominator: lo.ToPtr(int32(1000)),
+						},
+					},
+				},
+			},
+			expectedMirrorCalls: []string{
+				`mirror("http://staging.default.svc:80", 25, 100)`,
+				`mirror("http://canary.default.svc:80", 1, 1000)`,
+			},
+		},
+		{
+			name: "mirror to a different namespace",
+			filters: []gatewayapi.HTTPRouteFilter{
+				{
+					Type: gatewayapi.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.BackendObjectReference{
+							Name:      "staging",
+							Namespace: lo.ToPtr(gatewayapi.Namespace("other")),
+							Port:      lo.ToPtr(gatewayapi.PortNumber(80)),
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "mirror without port",
+			filters: []gatewayapi.HTTPRouteFilter{
+				{
+					Type: gatewayapi.HTTPRouteFilterRequestMirror,
+					RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+						BackendRef: gatewayapi.BackendObjectReference{
+							Name: "staging",
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags := []*string{lo.ToPtr("k8s-kind:HTTPRoute")}
+			options := setKongRoutePluginsOptions{routeNamespace: "default"}
+			result, err := generatePluginsFromHTTPRouteFilters(tc.filters, "/", tags, options)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result.Plugins, 1)
+
+			plugin := result.Plugins[0]
+			assert.Equal(t, "pre-function", *plugin.Name)
+			assert.Equal(t, tags, plugin.Tags)
+			access, ok := plugin.Config["access"].([]string)
+			require.True(t, ok)
+			require.Len(t, access, 1)
+			assert.True(t, strings.HasPrefix(access[0], requestMirrorLuaPrelude))
+			assert.Equal(t,
+				strings.Join(tc.expectedMirrorCalls, "\n")+"\n",
+				strings.TrimPrefix(access[0], requestMirrorLuaPrelude),
+			)
+		})
+	}
+}
+
 func TestGenerateRequestTransformerForURLRewrite(t *testing.T) {
 	testCases := []struct {
 		name                          string
@@ -0,0 +1,136 @@
+package subtranslator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	netv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/annotations"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/atc"
+	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
+)
+
+// RouteMatchClaim is a single request match of an HTTPRoute or Ingress rule together with the backends
+// the matching requests are routed to.
+type RouteMatchClaim struct {
+	// Object is the HTTPRoute or Ingress the match comes from.
+	Object client.Object
+	// Kind is the kind of the Object.
+	Kind string
+	// Location is the location of the match in the Object's spec, e.g. rules[0].matches[1].
+	Location string
+	// Expression is the ATC expression of the match. Matches of HTTPRoutes and Ingresses with equal
+	// expressions match the same requests regardless of the router flavor they're translated for.
+	Expression string
+	// Backends identifies the Services the matching requests are routed to.
+	Backends string
+}
+
+// RouteMatchClaimsFromHTTPRoute returns matches of the HTTPRoute split per hostname.
+func RouteMatchClaimsFromHTTPRoute(httproute *gatewayapi.HTTPRoute) []RouteMatchClaim {
+	return lo.Map(SplitHTTPRoute(httproute), func(m SplitHTTPRouteMatch, _ int) RouteMatchClaim {
+		match := m.Match
+		// Requests match the "/" prefix when no path is specified.
+		if match.Path == nil {
+			match.Path = &gatewayapi.HTTPPathMatch{
+				Type:  lo.ToPtr(gatewayapi.PathMatchPathPrefix),
+				Value: lo.ToPtr("/"),
+			}
+		}
+		matcher := atc.And()
+		if m.Hostname != "" {
+			matcher.And(hostMatcherFromHosts([]string{m.Hostname}))
+		}
+		matcher.And(generateMatcherFromHTTPRouteMatch(match, false))
+
+		rule := httproute.Spec.Rules[m.RuleIndex]
+		backends := lo.Map(rule.BackendRefs, func(ref gatewayapi.HTTPBackendRef, _ int) string {
+			namespace := httproute.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			return fmt.Sprintf("%s/%s", namespace, ref.Name)
+		})
+
+		return RouteMatchClaim{
+			Object:     httproute,
+			Kind:       "HTTPRoute",
+			Location:   fmt.Sprintf("rules[%d].matches[%d]", m.RuleIndex, m.MatchIndex),
+			Expression: matcher.Expression(),
+			Backends:   backendsKey(backends),
+		}
+	})
+}
+
+// RouteMatchClaimsFromIngress returns paths of the Ingress rules split per host.
+func RouteMatchClaimsFromIngress(ingress *netv1.Ingress) []RouteMatchClaim {
+	ingressAnnotations := ingress.GetAnnotations()
+	pathRegexPrefix := annotations.ExtractRegexPrefix(ingressAnnotations)
+	if pathRegexPrefix == "" {
+		pathRegexPrefix = ControllerPathRegexPrefix
+	}
+	hostAliases, _ := annotations.ExtractHostAliases(ingressAnnotations)
+	headers, _ := annotations.ExtractHeaders(ingressAnnotations)
+	methods := annotations.ExtractMethods(ingressAnnotations)
+
+	var claims []RouteMatchClaim
+	for ruleIndex, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		hosts := []string{""}
+		if rule.Host != "" {
+			hosts = append([]string{rule.Host}, hostAliases...)
+		}
+		for _, host := range hosts {
+			for pathIndex, path := range rule.HTTP.Paths {
+				if path.PathType == nil {
+					continue
+				}
+				matcher := atc.And()
+				if host != "" {
+					matcher.And(hostMatcherFromHosts([]string{host}))
+				}
+				matcher.And(pathMatcherFromIngressPath(path, pathRegexPrefix))
+				if len(headers) > 0 {
+					matcher.And(headerMatcherFromHeaders(headers))
+				}
+				if len(methods) > 0 {
+					matcher.And(methodMatcherFromMethods(methods))
+				}
+
+				claims = append(claims, RouteMatchClaim{
+					Object:     ingress,
+					Kind:       "Ingress",
+					Location:   fmt.Sprintf("rules[%d].http.paths[%d]", ruleIndex, pathIndex),
+					Expression: matcher.Expression(),
+					Backends:   backendsKey([]string{ingressBackendKey(ingress.Namespace, path.Backend)}),
+				})
+			}
+		}
+	}
+	return claims
+}
+
+func ingressBackendKey(namespace string, backend netv1.IngressBackend) string {
+	if backend.Resource != nil {
+		return fmt.Sprintf("%s/%s/%s", backend.Resource.Kind, namespace, backend.Resource.Name)
+	}
+	if backend.Service == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", namespace, backend.Service.Name)
+}
+
+// backendsKey returns a key identifying a set of backends. Ports and weights are not taken into account as
+// Ingress ports can be referred to by name, hence matches routed to the same Services are never reported
+// as conflicting.
+func backendsKey(backends []string) string {
+	backends = lo.Uniq(backends)
+	slices.Sort(backends)
+	return strings.Join(backends, ",")
+}
@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/kongstate"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator/subtranslator"
 	"github.com/kong/kong-operator/ingress-controller/internal/gatewayapi"
 	"github.com/kong/kong-operator/ingress-controller/internal/util"
 )
@@ -74,6 +75,57 @@ func generateKongRoutesFromRouteRule[T tRoute, TRule tRouteRule](
 	}, nil
 }
 
+// GenerateKongRoutesFromL4Route converts all rules of a TCPRoute, UDPRoute or TLSRoute to Kong Route objects
+// the same way the Translator does, given the ports of the Gateway listeners the route is attached to. Kong
+// Services are not generated as it's meant to be used for validating routes.
+func GenerateKongRoutesFromL4Route[T tRoute](
+	route T,
+	gwPorts []gatewayapi.PortNumber,
+	expressionRoutes bool,
+) ([]kongstate.Route, error) {
+	var (
+		routes []kongstate.Route
+		err    error
+	)
+	switch r := any(route).(type) {
+	case *gatewayapi.UDPRoute:
+		routes, err = generateKongRoutesFromRouteRules(r, gwPorts, r.Spec.Rules)
+	case *gatewayapi.TCPRoute:
+		routes, err = generateKongRoutesFromRouteRules(r, gwPorts, r.Spec.Rules)
+	case *gatewayapi.TLSRoute:
+		// TLSRoute matches based on hostname with Gateway listener thus passing gwPorts is pointless.
+		routes, err = generateKongRoutesFromRouteRules(r, nil, r.Spec.Rules)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if expressionRoutes {
+		for i := range routes {
+			subtranslator.ApplyExpressionToL4KongRoute(&routes[i])
+			routes[i].Destinations = nil
+			routes[i].SNIs = nil
+		}
+	}
+	return routes, nil
+}
+
+func generateKongRoutesFromRouteRules[T tRoute, TRule tRouteRule](
+	route T,
+	gwPorts []gatewayapi.PortNumber,
+	rules []TRule,
+) ([]kongstate.Route, error) {
+	var routes []kongstate.Route
+	for ruleNumber, rule := range rules {
+		ruleRoutes, err := generateKongRoutesFromRouteRule(route, gwPorts, ruleNumber, rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, ruleRoutes...)
+	}
+	return routes, nil
+}
+
 // routeToKongRoute converts Gateway Route to kong.Route.
 func routeToKongRoute[TRoute tTCPorUDPorTLSRoute](
 	r TRoute,
@@ -324,3 +324,62 @@ func TestGenerateKongRoutesFromRouteRule_TLS(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateKongRoutesFromL4Route(t *testing.T) {
+	tcpRoute := &gatewayapi.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mytcproute-name",
+			Namespace: "mynamespace",
+		},
+		Spec: gatewayapi.TCPRouteSpec{
+			Rules: []gatewayapi.TCPRouteRule{
+				{
+					BackendRefs: []gatewayapi.BackendRef{
+						{
+							BackendObjectReference: gatewayapi.BackendObjectReference{
+								Name: "service",
+								Port: lo.ToPtr(gatewayapi.PortNumber(1234)),
+							},
+						},
+					},
+				},
+				{
+					BackendRefs: []gatewayapi.BackendRef{
+						{
+							BackendObjectReference: gatewayapi.BackendObjectReference{
+								Name: "other-service",
+								Port: lo.ToPtr(gatewayapi.PortNumber(1234)),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("traditional routes", func(t *testing.T) {
+		routes, err := GenerateKongRoutesFromL4Route(tcpRoute, []gatewayapi.PortNumber{8080}, false)
+		require.NoError(t, err)
+		require.Len(t, routes, 2)
+		require.Equal(t, "tcproute.mynamespace.mytcproute-name.0.0", *routes[0].Name)
+		require.Equal(t, "tcproute.mynamespace.mytcproute-name.1.0", *routes[1].Name)
+		require.Equal(t, []*kong.CIDRPort{{Port: kong.Int(8080)}}, routes[0].Destinations)
+	})
+
+	t.Run("expression routes", func(t *testing.T) {
+		routes, err := GenerateKongRoutesFromL4Route(tcpRoute, []gatewayapi.PortNumber{8080}, true)
+		require.NoError(t, err)
+		require.Len(t, routes, 2)
+		require.Empty(t, routes[0].Destinations)
+		require.Contains(t, *routes[0].Expression, "net.dst.port == 8080")
+	})
+
+	t.Run("rule without backendRefs", func(t *testing.T) {
+		_, err := GenerateKongRoutesFromL4Route(&gatewayapi.UDPRoute{
+			Spec: gatewayapi.UDPRouteSpec{
+				Rules: []gatewayapi.UDPRouteRule{{}},
+			},
+		}, []gatewayapi.PortNumber{8080}, false)
+		require.Error(t, err)
+	})
+}
@@ -170,6 +170,10 @@ type KongConfigBuildingResult struct {
 
 	// ConfiguredKubernetesObjects is a list of Kubernetes objects that were successfully translated.
 	ConfiguredKubernetesObjects []client.Object
+
+	// RouteConflicts is a list of conflicts between HTTPRoutes and Ingresses matching the same requests
+	// and routing them to different backends.
+	RouteConflicts []RouteConflict
 }
 
 // UpdateCache updates the store cache used by the translator.
@@ -181,8 +185,22 @@ func (t *Translator) UpdateCache(c store.CacheStores) {
 // BuildKongConfig creates a Kong configuration from Ingress and Custom resources
 // defined in Kubernetes.
 func (t *Translator) BuildKongConfig() KongConfigBuildingResult {
-	ctx := context.Background()
+	return t.buildKongConfig(t.translateRoutes())
+}
+
+// routesTranslationResult is a result of translating Ingresses and Gateway API routes
+// together with the Kubernetes Services they route to.
+type routesTranslationResult struct {
+	ingressRules ingressRules
+	// servicesToBeSkipped are names of Kong Services that must not be configured
+	// because of annotations inconsistency between their Kubernetes Services.
+	servicesToBeSkipped map[string]any
+	upstreams           []kongstate.Upstream
+}
 
+// translateRoutes translates Ingresses and Gateway API routes in the store into Kong Services,
+// Routes and Upstreams.
+func (t *Translator) translateRoutes() routesTranslationResult {
 	// Translate and merge all rules together from all Kubernetes API sources
 	ingressRules := mergeIngressRules(
 		t.ingressRulesFromIngressV1(),
@@ -198,18 +216,33 @@ func (t *Translator) BuildKongConfig() KongConfigBuildingResult {
 	// services to be skipped because of annotations inconsistency
 	servicesToBeSkipped := ingressRules.populateServices(t.logger, t.storer, t.failuresCollector, t.translatedObjectsCollector)
 
-	// add the routes and services to the state
-	var result kongstate.KongState
-
 	// generate Upstreams and Targets from service defs
 	// update ServiceNameToServices with resolved ports (translating any name references to their number, as Kong
 	// services require a number)
-	result.Upstreams, ingressRules.ServiceNameToServices = t.getUpstreams(ingressRules.ServiceNameToServices)
+	var upstreams []kongstate.Upstream
+	upstreams, ingressRules.ServiceNameToServices = t.getUpstreams(ingressRules.ServiceNameToServices)
+
+	return routesTranslationResult{
+		ingressRules:        ingressRules,
+		servicesToBeSkipped: servicesToBeSkipped,
+		upstreams:           upstreams,
+	}
+}
+
+// buildKongConfig creates a Kong configuration from the translated routes and the rest of
+// Kong resources defined in Kubernetes.
+func (t *Translator) buildKongConfig(routes routesTranslationResult) KongConfigBuildingResult {
+	ctx := context.Background()
+	ingressRules := routes.ingressRules
+
+	// add the routes and services to the state
+	var result kongstate.KongState
+	result.Upstreams = routes.upstreams
 
 	for key, service := range ingressRules.ServiceNameToServices {
 		// if the service doesn't need to be skipped, then add it to the
 		// list of services.
-		if _, ok := servicesToBeSkipped[key]; !ok {
+		if _, ok := routes.servicesToBeSkipped[key]; !ok {
 			result.Services = append(result.Services, service)
 		}
 	}
@@ -293,6 +326,7 @@ func (t *Translator) BuildKongConfig() KongConfigBuildingResult {
 		KongState:                   &result,
 		TranslationFailures:         t.popTranslationFailures(),
 		ConfiguredKubernetesObjects: t.popConfiguredKubernetesObjects(),
+		RouteConflicts:              t.detectRouteConflicts(),
 	}
 }
 
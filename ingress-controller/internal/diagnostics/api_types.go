@@ -65,3 +65,59 @@ type DiffResponse struct {
 	// Available lists the currently available diff hashes and timestamps.
 	Available []DiffIndex `json:"available"`
 }
+
+// ExplainResponse is the GET /debug/config/explain response schema.
+type ExplainResponse struct {
+	// Object is the explained object.
+	Object ExplainedObject `json:"object"`
+	// ConfigHash is the hash of the last successfully applied configuration the entities are found in.
+	ConfigHash string `json:"hash,omitempty"`
+	// Entities are Kong entities generated from the object in the last successfully applied configuration.
+	Entities ExplainedEntities `json:"entities"`
+	// TranslationFailures are the messages of the object's failures in the last translation.
+	TranslationFailures []string `json:"translationFailures,omitempty"`
+	// Fallback describes how the object was treated by the current fallback configuration.
+	Fallback ExplainedFallback `json:"fallback"`
+	// Dependencies are the objects the object depends on.
+	Dependencies []FallbackAffectedObjectMeta `json:"dependencies,omitempty"`
+	// Dependants are the objects depending on the object.
+	Dependants []FallbackAffectedObjectMeta `json:"dependants,omitempty"`
+}
+
+// ExplainedObject identifies the explained object.
+type ExplainedObject struct {
+	// Group is the resource group.
+	Group string `json:"group"`
+	// Kind is the resource kind.
+	Kind string `json:"kind"`
+	// Namespace is the object namespace.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the object name.
+	Name string `json:"name"`
+	// ID is the object UID. It's empty when the object is not found.
+	ID string `json:"id,omitempty"`
+	// Found indicates whether the object is among the objects the last configuration was translated from.
+	Found bool `json:"found"`
+}
+
+// ExplainedEntities are Kong entities generated from an object.
+type ExplainedEntities struct {
+	Services  []string `json:"services,omitempty"`
+	Routes    []string `json:"routes,omitempty"`
+	Plugins   []string `json:"plugins,omitempty"`
+	Upstreams []string `json:"upstreams,omitempty"`
+}
+
+// ExplainedFallback describes how an object was treated by the fallback configuration.
+type ExplainedFallback struct {
+	// Status is the fallback configuration generation status.
+	Status FallbackStatus `json:"status"`
+	// Broken indicates the object was reported as broken by the Kong Admin API.
+	Broken bool `json:"broken,omitempty"`
+	// Excluded indicates the object was excluded from the fallback configuration.
+	Excluded bool `json:"excluded,omitempty"`
+	// Backfilled indicates the object was backfilled from the last valid cache state.
+	Backfilled bool `json:"backfilled,omitempty"`
+	// CausingObjects are the objects that caused the object to be excluded or backfilled.
+	CausingObjects []string `json:"causingObjects,omitempty"`
+}
@@ -42,11 +42,14 @@ type Collector struct {
 
 	currentFallbackCacheMetadata mo.Option[fallback.GeneratedCacheMetadata]
 
+	lastTranslation mo.Option[TranslationDiagnostic]
+
 	diffs diffMap
 
-	configLock   sync.RWMutex
-	fallbackLock sync.RWMutex
-	diffLock     sync.RWMutex
+	configLock      sync.RWMutex
+	fallbackLock    sync.RWMutex
+	translationLock sync.RWMutex
+	diffLock        sync.RWMutex
 }
 
 func NewCollector(logger logr.Logger, cfg managercfg.Config) *Collector {
@@ -58,6 +61,7 @@ func NewCollector(logger logr.Logger, cfg managercfg.Config) *Collector {
 			Configs:               make(chan ConfigDump, diagnosticConfigBufferDepth),
 			FallbackCacheMetadata: make(chan fallback.GeneratedCacheMetadata, diagnosticConfigBufferDepth),
 			Diffs:                 make(chan ConfigDiff, diagnosticConfigBufferDepth),
+			Translations:          make(chan TranslationDiagnostic, diagnosticConfigBufferDepth),
 		},
 	}
 }
@@ -113,6 +117,14 @@ func (s *Collector) CurrentFallbackCacheMetadata() mo.Option[fallback.GeneratedC
 	return s.currentFallbackCacheMetadata
 }
 
+// LastTranslationDiagnostic returns the results of the last translation of Kubernetes objects.
+func (s *Collector) LastTranslationDiagnostic() mo.Option[TranslationDiagnostic] {
+	s.translationLock.RLock()
+	defer s.translationLock.RUnlock()
+
+	return s.lastTranslation
+}
+
 // LastConfigDiffHash returns the hash of the last config diff.
 func (s *Collector) LastConfigDiffHash() string {
 	s.diffLock.RLock()
@@ -147,6 +159,8 @@ func (s *Collector) receiveDiagnostics(ctx context.Context) error {
 			s.onFallbackCacheMetadata(meta)
 		case diff := <-s.clientDiagnostic.Diffs:
 			s.onDiff(diff)
+		case translation := <-s.clientDiagnostic.Translations:
+			s.onTranslation(translation)
 		case <-ctx.Done():
 			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
 				s.logger.Error(err, "Shutting down diagnostic collection: context completed with error")
@@ -191,6 +205,14 @@ func (s *Collector) onFallbackCacheMetadata(meta fallback.GeneratedCacheMetadata
 	s.currentFallbackCacheMetadata = mo.Some(meta)
 }
 
+// onTranslation handles new results of translating Kubernetes objects.
+func (s *Collector) onTranslation(translation TranslationDiagnostic) {
+	s.translationLock.Lock()
+	defer s.translationLock.Unlock()
+
+	s.lastTranslation = mo.Some(translation)
+}
+
 // onDiff handles a new configuration diff.
 func (s *Collector) onDiff(diff ConfigDiff) {
 	s.diffLock.Lock()
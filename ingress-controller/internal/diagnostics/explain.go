@@ -0,0 +1,278 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/fallback"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+	"github.com/kong/kong-operator/ingress-controller/internal/util"
+)
+
+const (
+	// explainGroupQuery is the query parameter of the /explain endpoint with the object's group. When omitted,
+	// objects of the kind are matched regardless of their group.
+	explainGroupQuery = "group"
+	// explainKindQuery is the query parameter of the /explain endpoint with the object's kind.
+	explainKindQuery = "kind"
+	// explainNamespaceQuery is the query parameter of the /explain endpoint with the object's namespace.
+	explainNamespaceQuery = "namespace"
+	// explainNameQuery is the query parameter of the /explain endpoint with the object's name.
+	explainNameQuery = "name"
+)
+
+// explainedObjectRef identifies the object requested from the /explain endpoint.
+type explainedObjectRef struct {
+	group     *string
+	kind      string
+	namespace string
+	name      string
+}
+
+func (ref explainedObjectRef) matchesHash(h fallback.ObjectHash) bool {
+	return strings.EqualFold(h.Kind, ref.kind) &&
+		h.Namespace == ref.namespace &&
+		h.Name == ref.name &&
+		(ref.group == nil || h.Group == *ref.group)
+}
+
+// matchesTags returns true when Kong entity tags were generated for the object.
+func (ref explainedObjectRef) matchesTags(tags []*string) bool {
+	var h fallback.ObjectHash
+	for _, tag := range lo.FromSlicePtr(tags) {
+		switch {
+		case strings.HasPrefix(tag, util.K8sNameTagPrefix):
+			h.Name = strings.TrimPrefix(tag, util.K8sNameTagPrefix)
+		case strings.HasPrefix(tag, util.K8sNamespaceTagPrefix):
+			h.Namespace = strings.TrimPrefix(tag, util.K8sNamespaceTagPrefix)
+		case strings.HasPrefix(tag, util.K8sKindTagPrefix):
+			h.Kind = strings.TrimPrefix(tag, util.K8sKindTagPrefix)
+		case strings.HasPrefix(tag, util.K8sGroupTagPrefix):
+			h.Group = strings.TrimPrefix(tag, util.K8sGroupTagPrefix)
+		}
+	}
+	return ref.matchesHash(h)
+}
+
+func (h *HTTPHandler) handleExplain(rw http.ResponseWriter, r *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	ref := explainedObjectRef{
+		kind:      query.Get(explainKindQuery),
+		namespace: query.Get(explainNamespaceQuery),
+		name:      query.Get(explainNameQuery),
+	}
+	if query.Has(explainGroupQuery) {
+		group := query.Get(explainGroupQuery)
+		if group == "core" {
+			group = ""
+		}
+		ref.group = &group
+	}
+	if ref.kind == "" || ref.name == "" {
+		rw.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprintf(rw, `{"message":"query parameters %q and %q are required"}`, explainKindQuery, explainNameQuery)
+		return
+	}
+
+	resp := ExplainResponse{
+		Object: ExplainedObject{
+			Group:     lo.FromPtr(ref.group),
+			Kind:      ref.kind,
+			Namespace: ref.namespace,
+			Name:      ref.name,
+		},
+		Fallback: ExplainedFallback{Status: FallbackStatusNotTriggered},
+	}
+
+	if config, configHash, ok := h.diagnosticsProvider.LastSuccessfulConfigDump(); ok {
+		resp.ConfigHash = configHash
+		resp.Entities = explainEntities(config, ref)
+	}
+
+	if translation, ok := h.diagnosticsProvider.LastTranslationDiagnostic().Get(); ok {
+		for _, failure := range translation.TranslationFailures {
+			if slices.ContainsFunc(failure.CausingObjects(), func(obj client.Object) bool {
+				return ref.matchesHash(fallback.GetObjectHash(obj))
+			}) {
+				resp.TranslationFailures = append(resp.TranslationFailures, failure.Message())
+			}
+		}
+
+		if obj, ok := findObject(translation.Cache, ref); ok {
+			gvk := obj.GetObjectKind().GroupVersionKind()
+			resp.Object.Group = gvk.Group
+			resp.Object.Kind = gvk.Kind
+			resp.Object.ID = string(obj.GetUID())
+			resp.Object.Found = true
+
+			dependencies, dependants, err := explainDependencies(translation.Cache, obj)
+			if err != nil {
+				rw.WriteHeader(http.StatusInternalServerError)
+				_, _ = fmt.Fprintf(rw, `{"message":%q}`, err.Error())
+				return
+			}
+			resp.Dependencies = dependencies
+			resp.Dependants = dependants
+		}
+	}
+
+	if meta, ok := h.diagnosticsProvider.CurrentFallbackCacheMetadata().Get(); ok {
+		resp.Fallback = explainFallback(meta, ref)
+	}
+
+	if err := json.NewEncoder(rw).Encode(resp); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// explainEntities returns names of Kong entities in the config generated from the object.
+func explainEntities(config file.Content, ref explainedObjectRef) ExplainedEntities {
+	var entities ExplainedEntities
+	addPlugins := func(plugins []*file.FPlugin) {
+		for _, p := range plugins {
+			if p != nil && ref.matchesTags(p.Tags) {
+				entities.Plugins = append(entities.Plugins, pluginName(p.Plugin.Name, p.InstanceName, p.ID))
+			}
+		}
+	}
+	addRoute := func(route *file.FRoute) {
+		if ref.matchesTags(route.Tags) {
+			entities.Routes = append(entities.Routes, entityName(route.Name, route.ID))
+		}
+		addPlugins(route.Plugins)
+	}
+
+	for _, s := range config.Services {
+		if ref.matchesTags(s.Tags) {
+			entities.Services = append(entities.Services, entityName(s.Name, s.ID))
+		}
+		for _, route := range s.Routes {
+			if route != nil {
+				addRoute(route)
+			}
+		}
+		addPlugins(s.Plugins)
+	}
+	for i := range config.Routes {
+		addRoute(&config.Routes[i])
+	}
+	for i := range config.Plugins {
+		addPlugins([]*file.FPlugin{&config.Plugins[i]})
+	}
+	for _, u := range config.Upstreams {
+		if ref.matchesTags(u.Tags) {
+			entities.Upstreams = append(entities.Upstreams, entityName(u.Name, u.ID))
+		}
+	}
+	return entities
+}
+
+func entityName(name, id *string) string {
+	if name != nil {
+		return *name
+	}
+	return lo.FromPtr(id)
+}
+
+func pluginName(name, instanceName, id *string) string {
+	if instanceName != nil {
+		return fmt.Sprintf("%s (%s)", lo.FromPtr(name), *instanceName)
+	}
+	if id != nil {
+		return fmt.Sprintf("%s (%s)", lo.FromPtr(name), *id)
+	}
+	return lo.FromPtr(name)
+}
+
+// findObject returns the object from the cache.
+func findObject(cache store.CacheStores, ref explainedObjectRef) (client.Object, bool) {
+	if !cache.Available() {
+		return nil, false
+	}
+	for _, s := range cache.ListAllStores() {
+		for _, item := range s.List() {
+			obj, ok := item.(client.Object)
+			if ok && ref.matchesHash(fallback.GetObjectHash(obj)) {
+				return obj, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// explainDependencies returns the objects the object depends on and the objects depending on it, per
+// the fallback dependency graph.
+func explainDependencies(
+	cache store.CacheStores, obj client.Object,
+) (dependencies []FallbackAffectedObjectMeta, dependants []FallbackAffectedObjectMeta, err error) {
+	deps, err := fallback.ResolveDependencies(cache, obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve dependencies: %w", err)
+	}
+	g, err := fallback.NewDefaultCacheGraphProvider().CacheToGraph(cache)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	adjacencyMap, err := g.AdjacencyMap()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dependencyHashes := lo.Map(deps, func(dep client.Object, _ int) fallback.ObjectHash {
+		return fallback.GetObjectHash(dep)
+	})
+	return toAffectedObjectsMeta(dependencyHashes), toAffectedObjectsMeta(adjacencyMap[fallback.GetObjectHash(obj)]), nil
+}
+
+// toAffectedObjectsMeta converts hashes to objects' metadata sorted by their string representation.
+func toAffectedObjectsMeta(hashes []fallback.ObjectHash) []FallbackAffectedObjectMeta {
+	hashes = slices.Clone(hashes)
+	slices.SortFunc(hashes, func(a, b fallback.ObjectHash) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return lo.Map(hashes, func(h fallback.ObjectHash, _ int) FallbackAffectedObjectMeta {
+		return FallbackAffectedObjectMeta{
+			Group:     h.Group,
+			Kind:      h.Kind,
+			Namespace: h.Namespace,
+			Name:      h.Name,
+			ID:        string(h.UID),
+		}
+	})
+}
+
+// explainFallback returns how the object was treated by the fallback configuration.
+func explainFallback(meta fallback.GeneratedCacheMetadata, ref explainedObjectRef) ExplainedFallback {
+	explained := ExplainedFallback{
+		Status: FallbackStatusTriggered,
+		Broken: slices.ContainsFunc(meta.BrokenObjects, ref.matchesHash),
+	}
+	for _, affected := range meta.ExcludedObjects {
+		if ref.matchesHash(fallback.GetObjectHash(affected.Object)) {
+			explained.Excluded = true
+			explained.CausingObjects = append(explained.CausingObjects, causingObjectsStrings(affected.CausingObjects)...)
+		}
+	}
+	for _, affected := range meta.BackfilledObjects {
+		if ref.matchesHash(fallback.GetObjectHash(affected.Object)) {
+			explained.Backfilled = true
+			explained.CausingObjects = append(explained.CausingObjects, causingObjectsStrings(affected.CausingObjects)...)
+		}
+	}
+	return explained
+}
+
+func causingObjectsStrings(hashes []fallback.ObjectHash) []string {
+	return lo.Map(hashes, func(h fallback.ObjectHash, _ int) string {
+		return h.String()
+	})
+}
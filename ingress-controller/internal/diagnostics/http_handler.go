@@ -23,6 +23,7 @@ type Provider interface {
 	LastErrorBody() ([]byte, bool)
 
 	CurrentFallbackCacheMetadata() mo.Option[fallback.GeneratedCacheMetadata]
+	LastTranslationDiagnostic() mo.Option[TranslationDiagnostic]
 
 	LastConfigDiffHash() string
 	ConfigDiffByHash(string) (ConfigDiff, bool)
@@ -48,6 +49,7 @@ func NewConfigDiagnosticsHTTPHandler(diagnosticsProvider Provider, dumpsIncludeS
 	mux.HandleFunc("/fallback", h.handleCurrentFallback)
 	mux.HandleFunc("/raw-error", h.handleLastErrBody)
 	mux.HandleFunc("/diff-report", h.handleDiffReport)
+	mux.HandleFunc("/explain", h.handleExplain)
 
 	h.mux = mux
 	return h
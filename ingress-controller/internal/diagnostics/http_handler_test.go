@@ -9,11 +9,19 @@ import (
 	"testing"
 
 	"github.com/kong/go-database-reconciler/pkg/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 	"github.com/samber/mo"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/failures"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/fallback"
 	"github.com/kong/kong-operator/ingress-controller/internal/diagnostics"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
+	"github.com/kong/kong-operator/ingress-controller/internal/util"
 )
 
 // MockDiagnosticsProvider is a mock implementation of diagnostics.Provider.
@@ -27,6 +35,8 @@ type MockDiagnosticsProvider struct {
 
 	lastConfigDiffHash mo.Option[string]
 	configDiffsByHash  map[string]diagnostics.ConfigDiff
+
+	lastTranslation mo.Option[diagnostics.TranslationDiagnostic]
 }
 
 func (m MockDiagnosticsProvider) LastSuccessfulConfigDump() (file.Content, string, bool) {
@@ -54,6 +64,10 @@ func (m MockDiagnosticsProvider) CurrentFallbackCacheMetadata() mo.Option[fallba
 	return m.currentFallbackCacheMeta
 }
 
+func (m MockDiagnosticsProvider) LastTranslationDiagnostic() mo.Option[diagnostics.TranslationDiagnostic] {
+	return m.lastTranslation
+}
+
 func (m MockDiagnosticsProvider) LastConfigDiffHash() string {
 	if h, ok := m.lastConfigDiffHash.Get(); ok {
 		return h
@@ -193,6 +207,55 @@ func TestHTTPHandler(t *testing.T) {
       "timestamp": ""
     }
   ]
+}`,
+		},
+		{
+			name:               "explain without kind",
+			provider:           MockDiagnosticsProvider{},
+			endpoint:           "/explain?name=foo",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "explain object with entities in the last successful config",
+			provider: MockDiagnosticsProvider{
+				lastSuccessfulConfigDump: mo.Some(file.Content{
+					Services: []file.FService{
+						{
+							Service: kong.Service{
+								Name: lo.ToPtr("default.foo-svc.80"),
+								Tags: kongTags("Service", "default", "foo-svc"),
+							},
+							Routes: []*file.FRoute{
+								{
+									Route: kong.Route{
+										Name: lo.ToPtr("default.foo.foo-svc.foo.example.com.80"),
+										Tags: kongTags("Ingress", "default", "foo"),
+									},
+								},
+							},
+						},
+					},
+					Plugins: []file.FPlugin{
+						{
+							Plugin: kong.Plugin{
+								Name:         lo.ToPtr("key-auth"),
+								InstanceName: lo.ToPtr("foo-auth"),
+								Tags:         kongTags("Ingress", "default", "foo"),
+							},
+						},
+					},
+				}),
+			},
+			endpoint:           "/explain?kind=ingress&namespace=default&name=foo",
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{
+  "object": {"group": "", "kind": "ingress", "namespace": "default", "name": "foo", "found": false},
+  "hash": "success-hash",
+  "entities": {
+    "routes": ["default.foo.foo-svc.foo.example.com.80"],
+    "plugins": ["key-auth (foo-auth)"]
+  },
+  "fallback": {"status": "not-triggered"}
 }`,
 		},
 	}
@@ -225,3 +288,97 @@ func TestHTTPHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPHandlerExplainTranslatedObject(t *testing.T) {
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo-svc",
+			Namespace: "default",
+			UID:       "service-uid",
+		},
+	}
+	ingress := &netv1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "ingress-uid",
+		},
+		Spec: netv1.IngressSpec{
+			Rules: []netv1.IngressRule{
+				{
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{
+								{
+									Path: "/foo",
+									Backend: netv1.IngressBackend{
+										Service: &netv1.IngressServiceBackend{
+											Name: "foo-svc",
+											Port: netv1.ServiceBackendPort{Number: 80},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cache, err := store.NewCacheStoresFromObjs(service, ingress)
+	require.NoError(t, err)
+	translationFailure, err := failures.NewResourceFailure("no ingress class", ingress)
+	require.NoError(t, err)
+
+	h := diagnostics.NewConfigDiagnosticsHTTPHandler(MockDiagnosticsProvider{
+		lastTranslation: mo.Some(diagnostics.TranslationDiagnostic{
+			TranslationFailures: []failures.ResourceFailure{translationFailure},
+			Cache:               cache,
+		}),
+		currentFallbackCacheMeta: mo.Some(fallback.GeneratedCacheMetadata{
+			BrokenObjects: []fallback.ObjectHash{fallback.GetObjectHash(ingress)},
+		}),
+	}, true)
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL + "/explain?group=core&kind=Service&namespace=default&name=foo-svc")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var explained diagnostics.ExplainResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&explained))
+	require.True(t, explained.Object.Found)
+	require.Equal(t, "service-uid", explained.Object.ID)
+	require.Empty(t, explained.TranslationFailures)
+	require.Empty(t, explained.Dependencies)
+	require.Equal(t, []diagnostics.FallbackAffectedObjectMeta{
+		{Group: "networking.k8s.io", Kind: "Ingress", Namespace: "default", Name: "foo", ID: "ingress-uid"},
+	}, explained.Dependants)
+	require.False(t, explained.Fallback.Broken)
+
+	resp, err = s.Client().Get(s.URL + "/explain?kind=Ingress&namespace=default&name=foo")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	explained = diagnostics.ExplainResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&explained))
+	require.True(t, explained.Object.Found)
+	require.Equal(t, "networking.k8s.io", explained.Object.Group)
+	require.Equal(t, []string{"no ingress class"}, explained.TranslationFailures)
+	require.Equal(t, []diagnostics.FallbackAffectedObjectMeta{
+		{Kind: "Service", Namespace: "default", Name: "foo-svc", ID: "service-uid"},
+	}, explained.Dependencies)
+	require.Equal(t, diagnostics.FallbackStatusTriggered, explained.Fallback.Status)
+	require.True(t, explained.Fallback.Broken)
+}
+
+func kongTags(kind, namespace, name string) []*string {
+	return lo.ToSlicePtr([]string{
+		util.K8sKindTagPrefix + kind,
+		util.K8sNamespaceTagPrefix + namespace,
+		util.K8sNameTagPrefix + name,
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"github.com/kong/go-database-reconciler/pkg/file"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/failures"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/fallback"
+	"github.com/kong/kong-operator/ingress-controller/internal/store"
 )
 
 // DumpMeta annotates a config dump.
@@ -27,6 +29,14 @@ type ConfigDump struct {
 	RawResponseBody []byte
 }
 
+// TranslationDiagnostic contains the results of translating Kubernetes objects into Kong configuration.
+type TranslationDiagnostic struct {
+	// TranslationFailures are the failures of translating objects.
+	TranslationFailures []failures.ResourceFailure
+	// Cache holds the objects the configuration was translated from.
+	Cache store.CacheStores
+}
+
 // Client contains settings and channels for receiving diagnostic data from the controller's Kong client.
 // TODO(czeslavo): we could consider refactoring this to use private channels and expose methods for sending data.
 type Client struct {
@@ -42,6 +52,9 @@ type Client struct {
 
 	// Diffs is the channel that receives diff info in DB mode.
 	Diffs chan ConfigDiff
+
+	// Translations is the channel that receives results of translating Kubernetes objects.
+	Translations chan TranslationDiagnostic
 }
 
 // AffectedObject is a Kubernetes object associated with diagnostic information.
@@ -24,6 +24,7 @@ type (
 	BackendRef                                = gatewayv1.BackendRef
 	CommonRouteSpec                           = gatewayv1.CommonRouteSpec
 	Duration                                  = gatewayv1.Duration
+	Fraction                                  = gatewayv1.Fraction
 	Gateway                                   = gatewayv1.Gateway
 	GatewayClass                              = gatewayv1.GatewayClass
 	GatewayClassList                          = gatewayv1.GatewayClassList
@@ -45,6 +46,7 @@ type (
 	HTTPMethod                                = gatewayv1.HTTPMethod
 	HTTPPathMatch                             = gatewayv1.HTTPPathMatch
 	HTTPQueryParamMatch                       = gatewayv1.HTTPQueryParamMatch
+	HTTPRequestMirrorFilter                   = gatewayv1.HTTPRequestMirrorFilter
 	HTTPRequestRedirectFilter                 = gatewayv1.HTTPRequestRedirectFilter
 	HTTPRoute                                 = gatewayv1.HTTPRoute
 	HTTPRouteFilter                           = gatewayv1.HTTPRouteFilter
@@ -93,6 +95,7 @@ type (
 	GRPCMethodMatch                           = gatewayv1.GRPCMethodMatch
 	GRPCMethodMatchType                       = gatewayv1.GRPCMethodMatchType
 	GRPCRoute                                 = gatewayv1.GRPCRoute
+	GRPCRouteFilter                           = gatewayv1.GRPCRouteFilter
 	GRPCRouteList                             = gatewayv1.GRPCRouteList
 	GRPCRouteMatch                            = gatewayv1.GRPCRouteMatch
 	GRPCRouteRule                             = gatewayv1.GRPCRouteRule
@@ -152,6 +155,7 @@ const (
 	GRPCHeaderMatchExact                  = gatewayv1.GRPCHeaderMatchExact
 	GRPCMethodMatchExact                  = gatewayv1.GRPCMethodMatchExact
 	GRPCMethodMatchRegularExpression      = gatewayv1.GRPCMethodMatchRegularExpression
+	GRPCRouteFilterRequestHeaderModifier  = gatewayv1.GRPCRouteFilterRequestHeaderModifier
 	HostnameAddressType                   = gatewayv1.HostnameAddressType
 	IPAddressType                         = gatewayv1.IPAddressType
 	ListenerConditionAccepted             = gatewayv1.ListenerConditionAccepted
@@ -58,6 +58,26 @@ var (
 		Version:  gatewayv1.GroupVersion.Version,
 		Resource: "httproutes",
 	}
+	V1GRPCRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1.GroupVersion.Group,
+		Version:  gatewayv1.GroupVersion.Version,
+		Resource: "grpcroutes",
+	}
+	V1alpha2TCPRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "tcproutes",
+	}
+	V1alpha2TLSRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "tlsroutes",
+	}
+	V1alpha2UDPRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "udproutes",
+	}
 	V1beta1GatewayGVResource = metav1.GroupVersionResource{
 		Group:    gatewayv1beta1.GroupVersion.Group,
 		Version:  gatewayv1beta1.GroupVersion.Version,
@@ -30,6 +30,7 @@ import (
 	dpconf "github.com/kong/kong-operator/ingress-controller/internal/dataplane/config"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/configfetcher"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/fallback"
+	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/lastvalidconfig"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/sendconfig"
 	"github.com/kong/kong-operator/ingress-controller/internal/dataplane/translator"
 	"github.com/kong/kong-operator/ingress-controller/internal/diagnostics"
@@ -243,10 +244,30 @@ func New(
 	fallbackConfigGenerator := fallback.NewGenerator(fallback.NewDefaultCacheGraphProvider(), logger)
 	metricsRecorder := metrics.NewGlobalCtrlRuntimeMetricsRecorder(instanceID)
 
+	var kongConfigBuilder dataplane.KongConfigBuilder = configTranslator
+	if c.FeatureGates.Enabled(managercfg.IncrementalTranslationFeature) {
+		setupLog.Info("Incremental translation enabled", "fullRebuildInterval", c.IncrementalTranslationFullRebuildInterval)
+		kongConfigBuilder = translator.NewIncrementalTranslator(
+			configTranslator,
+			cache,
+			c.IncrementalTranslationFullRebuildInterval,
+		)
+	}
+
 	var dataplaneClientOpts []dataplane.KongClientOption
 	if dc, ok := diagnosticsClient.Get(); ok {
 		dataplaneClientOpts = append(dataplaneClientOpts, dataplane.WithDiagnosticsClient(dc))
 	}
+	if secretNN, ok := c.LastValidConfigSecret.Get(); ok {
+		setupLog.Info("Persisting the last valid configuration enabled", "secret", secretNN)
+		lastValidConfigStore := lastvalidconfig.NewSecretStore(
+			logger.WithName("last-valid-config-store"), mgr.GetClient(), mgr.GetAPIReader(), secretNN,
+		)
+		if err := mgr.Add(lastValidConfigStore); err != nil {
+			return nil, fmt.Errorf("failed adding lastvalidconfig.SecretStore runnable to the manager: %w", err)
+		}
+		dataplaneClientOpts = append(dataplaneClientOpts, dataplane.WithLastValidConfigStore(lastValidConfigStore))
+	}
 	dataplaneClient, err := dataplane.NewKongClient(
 		logger,
 		c.ProxySyncTimeout,
@@ -257,7 +278,7 @@ func New(
 		updateStrategyResolver,
 		configurationChangeDetector,
 		kongConfigFetcher,
-		configTranslator,
+		kongConfigBuilder,
 		&cache,
 		fallbackConfigGenerator,
 		metricsRecorder,
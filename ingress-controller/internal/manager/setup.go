@@ -224,15 +224,17 @@ func (m *Manager) setupAdmissionServer(
 	}
 
 	adminAPIServicesProvider := admission.NewDefaultAdminAPIServicesProvider(m.clientsManager)
+	validator := admission.NewKongHTTPValidator(
+		admissionLogger,
+		m.m.GetClient(),
+		m.cfg.IngressClassName,
+		adminAPIServicesProvider,
+		translatorFeatures,
+		storer,
+	)
+	validator.RejectRouteConflicts = m.cfg.AdmissionServer.RejectRouteConflicts
 	srv, err := admission.MakeTLSServer(m.cfg.AdmissionServer, &admission.RequestHandler{
-		Validator: admission.NewKongHTTPValidator(
-			admissionLogger,
-			m.m.GetClient(),
-			m.cfg.IngressClassName,
-			adminAPIServicesProvider,
-			translatorFeatures,
-			storer,
-		),
+		Validator:         validator,
 		ReferenceIndexers: referenceIndexers,
 		Logger:            admissionLogger,
 	})
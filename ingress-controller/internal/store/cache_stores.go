@@ -3,6 +3,7 @@ package store
 import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured/unstructuredscheme"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	yamlserializer "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 
@@ -57,6 +58,12 @@ func NewCacheStoresFromObjs(objs ...runtime.Object) (CacheStores, error) {
 	return c, nil
 }
 
+// IsSupported returns whether objects of the provided GroupVersionKind can be stored in CacheStores.
+func IsSupported(gvk schema.GroupVersionKind) bool {
+	_, err := mkObjFromGVK(gvk)
+	return err == nil
+}
+
 // CacheStoresLockNotInitializedError is returned when the RW lock in the cache stores is nil.
 // It indicates that the CacheStores may not be correctly initialized.
 type CacheStoresLockNotInitializedError struct{}
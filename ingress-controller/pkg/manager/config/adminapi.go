@@ -1,5 +1,7 @@
 package config
 
+import "crypto/tls"
+
 // AdminAPIClientConfig defines parameters that configure a client for Kong Admin API.
 type AdminAPIClientConfig struct {
 	// Disable verification of TLS certificate of Kong's Admin endpoint.
@@ -22,7 +24,8 @@ type AdminAPIClientConfig struct {
 // - only one of Cert / CertFile,
 // - only one of Key / KeyFile,
 // - if any of Cert / CertFile is set, one of Key / KeyFile has to be set,
-// - if any of Key / KeyFile is set, one of Cert / CertFile has to be set.
+// - if any of Key / KeyFile is set, one of Cert / CertFile has to be set,
+// - if GetCertificate is set, none of the other fields can be set.
 type TLSClientConfig struct {
 	// Cert is a client certificate.
 	Cert string
@@ -33,8 +36,13 @@ type TLSClientConfig struct {
 	Key string
 	// KeyFile is a client key file path.
 	KeyFile string
+
+	// GetCertificate returns the client certificate for every TLS handshake. It allows the certificate
+	// to be replaced, e.g. when it's renewed, without recreating the clients. It's not taken into account
+	// in the configuration hash, so replacing the certificate doesn't restart the manager.
+	GetCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error) `json:"-" hash:"-"`
 }
 
 func (c TLSClientConfig) IsZero() bool {
-	return c == TLSClientConfig{}
+	return c.Cert == "" && c.CertFile == "" && c.Key == "" && c.KeyFile == "" && c.GetCertificate == nil
 }
@@ -9,4 +9,7 @@ type AdmissionServerConfig struct {
 
 	KeyPath string
 	Key     string
+
+	// RejectRouteConflicts enables rejecting HTTPRoutes and Ingresses conflicting with existing ones.
+	RejectRouteConflicts bool
 }
@@ -42,6 +42,7 @@ type Config struct {
 	AnonymousReports                  bool
 	EnableReverseSync                 bool
 	UseLastValidConfigForFallback     bool
+	LastValidConfigSecret             OptionalNamespacedName
 	SyncPeriod                        time.Duration
 	SkipCACertificates                bool
 	CacheSyncTimeout                  time.Duration
@@ -147,6 +148,10 @@ type Config struct {
 	// that are sharing the same combination of backends to one Kong service.
 	CombinedServicesFromDifferentHTTPRoutes bool
 
+	// IncrementalTranslationFullRebuildInterval is the interval in which all Kubernetes objects are re-translated
+	// when the IncrementalTranslation feature gate is enabled.
+	IncrementalTranslationFullRebuildInterval time.Duration
+
 	// Feature Gates
 	FeatureGates FeatureGates
 
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"os"
 	"testing"
@@ -19,6 +20,32 @@ func TestConfigJSONMarshal(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestHashIgnoresTLSClientCertificateGetter(t *testing.T) {
+	withGetter := func(cert *tls.Certificate) managercfg.Config {
+		return managercfg.Config{
+			KongAdminAPIConfig: managercfg.AdminAPIClientConfig{
+				TLSClient: managercfg.TLSClientConfig{
+					GetCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return cert, nil },
+				},
+			},
+		}
+	}
+
+	hash1, err := managercfg.Hash(withGetter(&tls.Certificate{}))
+	require.NoError(t, err)
+	hash2, err := managercfg.Hash(withGetter(nil))
+	require.NoError(t, err)
+	require.Equal(t, hash1, hash2)
+
+	hash3, err := managercfg.Hash(managercfg.Config{
+		KongAdminAPIConfig: managercfg.AdminAPIClientConfig{
+			TLSClient: managercfg.TLSClientConfig{Cert: "cert", Key: "key"},
+		},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, hash1, hash3)
+}
+
 func TestConfigResolve(t *testing.T) {
 	t.Run("Admin Token Path", func(t *testing.T) {
 		validWithTokenPath := func() managercfg.Config {
@@ -26,6 +26,10 @@ const (
 	// for configuring custom Kong entities that KIC does not support yet.
 	// Requires feature gate `FillIDs` to be enabled.
 	KongCustomEntityFeature = "KongCustomEntity"
+
+	// IncrementalTranslationFeature is the name of the feature-gate that makes KIC re-translate only Ingresses and
+	// Gateway API routes affected by changes to Kubernetes objects instead of all of them on every sync.
+	IncrementalTranslationFeature = "IncrementalTranslation"
 )
 
 // GetFeatureGatesDefaults returns the default values for all feature gates.
@@ -40,5 +44,6 @@ func GetFeatureGatesDefaults() FeatureGates {
 		SanitizeKonnectConfigDumpsFeature: true,
 		FallbackConfigurationFeature:      false,
 		KongCustomEntityFeature:           true,
+		IncrementalTranslationFeature:     false,
 	}
 }
@@ -97,6 +97,10 @@ func validateClientTLS(clientTLS TLSClientConfig) error {
 	clientCertProvided := clientTLS.Cert != "" || clientTLS.CertFile != ""
 	clientKeyProvided := clientTLS.Key != "" || clientTLS.KeyFile != ""
 
+	if clientTLS.GetCertificate != nil && (clientCertProvided || clientKeyProvided) {
+		return errors.New("both client certificate getter and client certificate or key specified, only one allowed")
+	}
+
 	if clientCertProvided && !clientKeyProvided {
 		return errors.New("client certificate was provided, but the client key was not")
 	}
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -153,6 +154,22 @@ func TestConfigValidate(t *testing.T) {
 			c.KongAdminAPIConfig.TLSClient.KeyFile = "non-empty-path"
 			require.NoError(t, c.Validate())
 		})
+
+		t.Run("tls client certificate getter instead of cert and key is accepted", func(t *testing.T) {
+			c := validWithClientTLS()
+			c.KongAdminAPIConfig.TLSClient = managercfg.TLSClientConfig{
+				GetCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return &tls.Certificate{}, nil },
+			}
+			require.NoError(t, c.Validate())
+		})
+
+		t.Run("tls client certificate getter with cert and key is rejected", func(t *testing.T) {
+			c := validWithClientTLS()
+			c.KongAdminAPIConfig.TLSClient.GetCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return &tls.Certificate{}, nil
+			}
+			require.ErrorContains(t, c.Validate(), "both client certificate getter and client certificate or key specified")
+		})
 	})
 
 	t.Run("Admin Token", func(t *testing.T) {
@@ -200,6 +217,24 @@ func TestConfigValidate(t *testing.T) {
 		})
 	})
 
+	t.Run("last valid config secret", func(t *testing.T) {
+		t.Run("set without feature gate is accepted", func(t *testing.T) {
+			c := managercfg.Config{
+				LastValidConfigSecret: mo.Some(k8stypes.NamespacedName{Name: "last-valid-config", Namespace: "ns"}),
+			}
+			require.NoError(t, c.Validate())
+		})
+		t.Run("set with feature gate is accepted", func(t *testing.T) {
+			c := managercfg.Config{
+				LastValidConfigSecret: mo.Some(k8stypes.NamespacedName{Name: "last-valid-config", Namespace: "ns"}),
+				FeatureGates: map[string]bool{
+					managercfg.FallbackConfigurationFeature: true,
+				},
+			}
+			require.NoError(t, c.Validate())
+		})
+	})
+
 	t.Run("gateway discovery", func(t *testing.T) {
 		validEnabled := func() *managercfg.Config {
 			return &managercfg.Config{
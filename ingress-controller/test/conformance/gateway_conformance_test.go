@@ -19,6 +19,8 @@ import (
 	dpconf "github.com/kong/kong-operator/ingress-controller/internal/dataplane/config"
 	"github.com/kong/kong-operator/ingress-controller/test/internal/testenv"
 	"github.com/kong/kong-operator/modules/manager/metadata"
+	"github.com/kong/kong-operator/pkg/consts"
+	"github.com/kong/kong-operator/pkg/gatewayapi"
 )
 
 var skippedTestsForTraditionalRoutes = []string{
@@ -47,9 +49,15 @@ var traditionalRoutesSupportedFeatures = []features.FeatureName{
 	features.SupportHTTPRoute,
 	features.SupportGRPCRoute,
 	// extended features
+	features.SupportHTTPRouteMethodMatching,
 	features.SupportHTTPRouteResponseHeaderModification,
 	features.SupportHTTPRoutePathRewrite,
 	features.SupportHTTPRouteHostRewrite,
+	features.SupportHTTPRouteRequestTimeout,
+	features.SupportHTTPRouteBackendProtocolWebSocket,
+	// NOTE: HTTPRouteRequestMirror features are not claimed as the mirroring requires
+	// resty.http to be allowed in the Lua sandbox of the DataPlane. They have to be kept
+	// in sync with the features advertised in pkg/gatewayapi/supportedfeatures.go.
 	// TODO: https://github.com/Kong/kubernetes-ingress-controller/issues/5868
 	// Temporarily disabled and tracking through the following issue.
 	// suite.SupportHTTPRouteBackendTimeout,
@@ -66,6 +74,11 @@ var expressionRoutesSupportedFeatures = []features.FeatureName{
 	features.SupportHTTPRouteResponseHeaderModification,
 	features.SupportHTTPRoutePathRewrite,
 	features.SupportHTTPRouteHostRewrite,
+	features.SupportHTTPRouteRequestTimeout,
+	features.SupportHTTPRouteBackendProtocolWebSocket,
+	// NOTE: HTTPRouteRequestMirror features are not claimed as the mirroring requires
+	// resty.http to be allowed in the Lua sandbox of the DataPlane. They have to be kept
+	// in sync with the features advertised in pkg/gatewayapi/supportedfeatures.go.
 	// TODO: https://github.com/Kong/kubernetes-ingress-controller/issues/5868
 	// Temporarily disabled and tracking through the following issue.
 	// features.SupportHTTPRouteBackendTimeout,
@@ -94,6 +107,13 @@ func TestGatewayConformance(t *testing.T) {
 		t.Fatalf("unsupported KongRouterFlavor: %s", rf)
 	}
 
+	// Conformance must not claim features that aren't advertised by the Gateway
+	// API supported features of the same router flavor.
+	advertisedFeatures, err := gatewayapi.GetSupportedFeatures(consts.RouterFlavor(mode))
+	require.NoError(t, err)
+	require.Empty(t, sets.New(supportedFeatures...).Difference(advertisedFeatures).UnsortedList(),
+		"conformance claims features which are not advertised for %s router flavor", mode)
+
 	opts := conformance.DefaultOptions(t)
 	opts.GatewayClassName = gatewayClassName
 	opts.Debug = true
@@ -48,6 +48,16 @@ const (
 	MetricNameKonnectEntityOperationDuration = "gateway_operator_konnect_entity_operation_duration_milliseconds"
 )
 
+const (
+	// CertificateSecretNamespaceKey is the namespace of the Secret storing a certificate issued by the operator.
+	CertificateSecretNamespaceKey = "secret_namespace"
+	// CertificateSecretNameKey is the name of the Secret storing a certificate issued by the operator.
+	CertificateSecretNameKey = "secret_name"
+
+	// MetricNameCertificateExpirationTimestamp is the metric of expiration timestamps of certificates issued by the operator.
+	MetricNameCertificateExpirationTimestamp = "gateway_operator_certificate_expiration_timestamp_seconds"
+)
+
 var (
 	konnectEntityOperationCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -94,8 +104,38 @@ var (
 		},
 		[]string{KonnectServerURLKey, KonnectEntityOperationTypeKey, KonnectEntityTypeKey, SuccessKey, StatusCodeKey},
 	)
+
+	certificateExpirationTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: MetricNameCertificateExpirationTimestamp,
+			Help: fmt.Sprintf(
+				"Expiration time of certificates issued by the operator as a Unix timestamp in seconds. "+
+					"`%s` and `%s` describe the Secret storing the certificate.",
+				CertificateSecretNamespaceKey, CertificateSecretNameKey,
+			),
+		},
+		[]string{CertificateSecretNamespaceKey, CertificateSecretNameKey},
+	)
 )
 
+// RecordCertificateExpiration records the expiration time of a certificate issued by the operator
+// and stored in the namespace/name Secret.
+func RecordCertificateExpiration(namespace, name string, notAfter time.Time) {
+	certificateExpirationTimestamp.With(prometheus.Labels{
+		CertificateSecretNamespaceKey: namespace,
+		CertificateSecretNameKey:      name,
+	}).Set(float64(notAfter.Unix()))
+}
+
+// DeleteCertificateExpiration stops reporting the expiration time of a certificate
+// stored in the namespace/name Secret.
+func DeleteCertificateExpiration(namespace, name string) {
+	certificateExpirationTimestamp.Delete(prometheus.Labels{
+		CertificateSecretNamespaceKey: namespace,
+		CertificateSecretNameKey:      name,
+	})
+}
+
 // GlobalCtrlRuntimeMetricsRecorder is a metrics recorder that uses a global Prometheus registry
 // provided by the controller-runtime. Any instance of it will record metrics to the same registry.
 //
@@ -164,6 +204,7 @@ func init() {
 	allMetrics := []prometheus.Collector{
 		konnectEntityOperationCount,
 		konnectEntityOperationDuration,
+		certificateExpirationTimestamp,
 	}
 	for _, m := range allMetrics {
 		ctrlmetrics.Registry.MustRegister(m)
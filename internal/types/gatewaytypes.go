@@ -8,6 +8,7 @@ type (
 	Gateway                = gatewayv1.Gateway
 	GatewayList            = gatewayv1.GatewayList
 	GatewayClass           = gatewayv1.GatewayClass
+	GatewayClassList       = gatewayv1.GatewayClassList
 	GatewaySpec            = gatewayv1.GatewaySpec
 	GatewayStatusAddress   = gatewayv1.GatewayStatusAddress
 	Listener               = gatewayv1.Listener
@@ -15,6 +16,8 @@ type (
 	HTTPRouteSpec          = gatewayv1.HTTPRouteSpec
 	HTTPRouteRule          = gatewayv1.HTTPRouteRule
 	HTTPRouteList          = gatewayv1.HTTPRouteList
+	GRPCRoute              = gatewayv1.GRPCRoute
+	GRPCRouteList          = gatewayv1.GRPCRouteList
 	ParentReference        = gatewayv1.ParentReference
 	CommonRouteSpec        = gatewayv1.CommonRouteSpec
 	Kind                   = gatewayv1.Kind
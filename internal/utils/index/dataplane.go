@@ -4,11 +4,14 @@ import (
 	"strings"
 
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	"github.com/kong/kong-operator/pkg/consts"
 )
 
 const (
@@ -17,6 +20,12 @@ const (
 	KongPluginInstallationsIndex = "KongPluginInstallations"
 	// DataPlaneOnOwnerGatewayIndex is the key to index the DataPlanes based on the owner Gateways.
 	DataPlaneOnOwnerGatewayIndex = "DataPlaneOnOwnerGateway"
+	// DataPlaneOnProxyEnvConfigMapIndex is the key to index the DataPlanes based on the ConfigMaps
+	// referenced by the env and envFrom of their proxy container, in a form of namespace/name strings.
+	DataPlaneOnProxyEnvConfigMapIndex = "DataPlaneOnProxyEnvConfigMap"
+	// DataPlaneOnProxyEnvSecretIndex is the key to index the DataPlanes based on the Secrets
+	// referenced by the env and envFrom of their proxy container, in a form of namespace/name strings.
+	DataPlaneOnProxyEnvSecretIndex = "DataPlaneOnProxyEnvSecret"
 )
 
 // DataPlaneFlags contains flags that control which indexes are created for the DataPlane object.
@@ -48,6 +57,14 @@ func OptionsForDataPlane(flags DataPlaneFlags) []Option {
 			Object:         &operatorv1beta1.DataPlane{},
 			Field:          DataPlaneOnOwnerGatewayIndex,
 			ExtractValueFn: OwnerGatewayOnDataPlane,
+		}, Option{
+			Object:         &operatorv1beta1.DataPlane{},
+			Field:          DataPlaneOnProxyEnvConfigMapIndex,
+			ExtractValueFn: ProxyEnvConfigMapsOnDataPlane,
+		}, Option{
+			Object:         &operatorv1beta1.DataPlane{},
+			Field:          DataPlaneOnProxyEnvSecretIndex,
+			ExtractValueFn: ProxyEnvSecretsOnDataPlane,
 		})
 	}
 
@@ -86,3 +103,65 @@ func OwnerGatewayOnDataPlane(o client.Object) []string {
 
 	return []string{dp.Namespace + "/" + ownerGateway.Name}
 }
+
+// ProxyEnvConfigMapsOnDataPlane returns the ConfigMaps referenced by the env and envFrom
+// of the DataPlane's proxy container.
+func ProxyEnvConfigMapsOnDataPlane(o client.Object) []string {
+	dp, ok := o.(*operatorv1beta1.DataPlane)
+	if !ok {
+		return nil
+	}
+	container, ok := dataPlaneProxyContainer(dp)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+			result = append(result, dp.Namespace+"/"+env.ValueFrom.ConfigMapKeyRef.Name)
+		}
+	}
+	for _, envFrom := range container.EnvFrom {
+		if envFrom.ConfigMapRef != nil {
+			result = append(result, dp.Namespace+"/"+envFrom.ConfigMapRef.Name)
+		}
+	}
+	return lo.Uniq(result)
+}
+
+// ProxyEnvSecretsOnDataPlane returns the Secrets referenced by the env and envFrom
+// of the DataPlane's proxy container.
+func ProxyEnvSecretsOnDataPlane(o client.Object) []string {
+	dp, ok := o.(*operatorv1beta1.DataPlane)
+	if !ok {
+		return nil
+	}
+	container, ok := dataPlaneProxyContainer(dp)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			result = append(result, dp.Namespace+"/"+env.ValueFrom.SecretKeyRef.Name)
+		}
+	}
+	for _, envFrom := range container.EnvFrom {
+		if envFrom.SecretRef != nil {
+			result = append(result, dp.Namespace+"/"+envFrom.SecretRef.Name)
+		}
+	}
+	return lo.Uniq(result)
+}
+
+func dataPlaneProxyContainer(dp *operatorv1beta1.DataPlane) (corev1.Container, bool) {
+	podTemplateSpec := dp.Spec.Deployment.PodTemplateSpec
+	if podTemplateSpec == nil {
+		return corev1.Container{}, false
+	}
+	return lo.Find(podTemplateSpec.Spec.Containers, func(c corev1.Container) bool {
+		return c.Name == consts.DataPlaneProxyContainerName
+	})
+}
@@ -0,0 +1,33 @@
+package index
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+const (
+	// GatewayClassOnGatewayIndex is the key to be used to access the GatewayClass name
+	// referenced by a Gateway.
+	GatewayClassOnGatewayIndex = "GatewayClassOnGateway"
+)
+
+// OptionsForGateway returns the options for Gateway.
+func OptionsForGateway() []Option {
+	return []Option{
+		{
+			Object:         &gwtypes.Gateway{},
+			Field:          GatewayClassOnGatewayIndex,
+			ExtractValueFn: GatewayClassOnGateway,
+		},
+	}
+}
+
+// GatewayClassOnGateway returns the name of the GatewayClass of the Gateway.
+func GatewayClassOnGateway(o client.Object) []string {
+	gateway, ok := o.(*gwtypes.Gateway)
+	if !ok {
+		return nil
+	}
+	return []string{string(gateway.Spec.GatewayClassName)}
+}
@@ -0,0 +1,43 @@
+package index
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1beta1 "github.com/kong/kubernetes-configuration/v2/api/gateway-operator/v1beta1"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+const (
+	// GatewayConfigurationOnGatewayClassIndex is the key to be used to access the GatewayConfiguration
+	// referenced in the parametersRef of a GatewayClass, in a form of namespace/name string.
+	GatewayConfigurationOnGatewayClassIndex = "GatewayConfigurationOnGatewayClass"
+)
+
+// OptionsForGatewayClass returns the options for GatewayClass.
+func OptionsForGatewayClass() []Option {
+	return []Option{
+		{
+			Object:         &gwtypes.GatewayClass{},
+			Field:          GatewayConfigurationOnGatewayClassIndex,
+			ExtractValueFn: GatewayConfigurationOnGatewayClass,
+		},
+	}
+}
+
+// GatewayConfigurationOnGatewayClass returns the GatewayConfiguration referenced
+// in the parametersRef of the GatewayClass.
+func GatewayConfigurationOnGatewayClass(o client.Object) []string {
+	gatewayClass, ok := o.(*gwtypes.GatewayClass)
+	if !ok {
+		return nil
+	}
+	paramsRef := gatewayClass.Spec.ParametersRef
+	if paramsRef == nil ||
+		string(paramsRef.Group) != operatorv1beta1.SchemeGroupVersion.Group ||
+		string(paramsRef.Kind) != "GatewayConfiguration" ||
+		paramsRef.Namespace == nil {
+		return nil
+	}
+	return []string{string(*paramsRef.Namespace) + "/" + paramsRef.Name}
+}
@@ -0,0 +1,87 @@
+package index
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+const (
+	// BackendServiceIndex is the key to be used to access the Services referenced in the backendRefs
+	// of a route, in a form of list of namespace/name strings.
+	BackendServiceIndex = "BackendService"
+	// ParentGatewayIndex is the key to be used to access the Gateways referenced in the parentRefs
+	// of a route, in a form of list of namespace/name strings.
+	ParentGatewayIndex = "ParentGateway"
+)
+
+// OptionsForHTTPRoute returns the options for HTTPRoute.
+func OptionsForHTTPRoute() []Option {
+	return []Option{
+		{
+			Object:         &gwtypes.HTTPRoute{},
+			Field:          BackendServiceIndex,
+			ExtractValueFn: BackendServicesOnHTTPRoute,
+		},
+		{
+			Object:         &gwtypes.HTTPRoute{},
+			Field:          ParentGatewayIndex,
+			ExtractValueFn: ParentGatewaysOnHTTPRoute,
+		},
+	}
+}
+
+// BackendServicesOnHTTPRoute returns the Services referenced in the backendRefs of the HTTPRoute.
+func BackendServicesOnHTTPRoute(o client.Object) []string {
+	route, ok := o.(*gwtypes.HTTPRoute)
+	if !ok {
+		return nil
+	}
+
+	var refs []gwtypes.BackendObjectReference
+	for _, rule := range route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			refs = append(refs, b.BackendObjectReference)
+		}
+	}
+	return backendServices(route.Namespace, refs)
+}
+
+// backendServices returns the Services referenced by the backendRefs of a route in the namespace,
+// in a form of list of namespace/name strings.
+func backendServices(namespace string, refs []gwtypes.BackendObjectReference) []string {
+	var result []string
+	for _, ref := range refs {
+		if (ref.Group != nil && *ref.Group != "" && *ref.Group != "core") || (ref.Kind != nil && *ref.Kind != "Service") {
+			continue
+		}
+		ns := namespace
+		if ref.Namespace != nil {
+			ns = string(*ref.Namespace)
+		}
+		result = append(result, ns+"/"+string(ref.Name))
+	}
+	return result
+}
+
+// ParentGatewaysOnHTTPRoute returns the Gateways referenced in the parentRefs of the HTTPRoute.
+func ParentGatewaysOnHTTPRoute(o client.Object) []string {
+	route, ok := o.(*gwtypes.HTTPRoute)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, ref := range route.Spec.ParentRefs {
+		if (ref.Group != nil && string(*ref.Group) != gwtypes.GroupVersion.Group) ||
+			(ref.Kind != nil && *ref.Kind != "Gateway") {
+			continue
+		}
+		namespace := route.Namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		result = append(result, namespace+"/"+string(ref.Name))
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+func TestBackendServicesOnHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []string
+	}{
+		{
+			name: "wrong type returns nil",
+			obj:  &gwtypes.Gateway{},
+			want: nil,
+		},
+		{
+			name: "Services are returned with the route namespace by default",
+			obj: &gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec: gwtypes.HTTPRouteSpec{
+					Rules: []gwtypes.HTTPRouteRule{
+						{
+							BackendRefs: []gwtypes.HTTPBackendRef{
+								{BackendRef: gwtypes.BackendRef{BackendObjectReference: gwtypes.BackendObjectReference{Name: "svc-1"}}},
+								{BackendRef: gwtypes.BackendRef{BackendObjectReference: gwtypes.BackendObjectReference{
+									Name:      "svc-2",
+									Namespace: lo.ToPtr(gwtypes.Namespace("other")),
+								}}},
+							},
+						},
+					},
+				},
+			},
+			want: []string{"default/svc-1", "other/svc-2"},
+		},
+		{
+			name: "non Service backends are skipped",
+			obj: &gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec: gwtypes.HTTPRouteSpec{
+					Rules: []gwtypes.HTTPRouteRule{
+						{
+							BackendRefs: []gwtypes.HTTPBackendRef{
+								{BackendRef: gwtypes.BackendRef{BackendObjectReference: gwtypes.BackendObjectReference{
+									Name:  "bucket",
+									Group: lo.ToPtr(gwtypes.Group("example.com")),
+									Kind:  lo.ToPtr(gwtypes.Kind("Bucket")),
+								}}},
+							},
+						},
+					},
+				},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BackendServicesOnHTTPRoute(tt.obj))
+		})
+	}
+}
+
+func TestParentGatewaysOnHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []string
+	}{
+		{
+			name: "wrong type returns nil",
+			obj:  &gwtypes.Gateway{},
+			want: nil,
+		},
+		{
+			name: "Gateways are returned with the route namespace by default",
+			obj: &gwtypes.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec: gwtypes.HTTPRouteSpec{
+					CommonRouteSpec: gwtypes.CommonRouteSpec{
+						ParentRefs: []gwtypes.ParentReference{
+							{Name: "gw-1"},
+							{Name: "gw-2", Namespace: lo.ToPtr(gwtypes.Namespace("other"))},
+							{Name: "svc", Kind: lo.ToPtr(gwtypes.Kind("Service")), Group: lo.ToPtr(gwtypes.Group(""))},
+						},
+					},
+				},
+			},
+			want: []string{"default/gw-1", "other/gw-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParentGatewaysOnHTTPRoute(tt.obj))
+		})
+	}
+}
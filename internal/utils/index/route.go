@@ -0,0 +1,89 @@
+package index
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+// OptionsForRoutes returns the options for the GRPCRoutes, TCPRoutes, TLSRoutes and UDPRoutes
+// which CRDs are installed according to crdExists, as they're not part of all the Gateway API
+// channels or versions. HTTPRoutes are covered by OptionsForHTTPRoute.
+func OptionsForRoutes(crdExists func(schema.GroupVersionResource) (bool, error)) ([]Option, error) {
+	routes := []struct {
+		resource schema.GroupVersionResource
+		object   client.Object
+	}{
+		{
+			resource: gatewayv1.SchemeGroupVersion.WithResource("grpcroutes"),
+			object:   &gwtypes.GRPCRoute{},
+		},
+		{
+			resource: gatewayv1alpha2.SchemeGroupVersion.WithResource("tcproutes"),
+			object:   &gatewayv1alpha2.TCPRoute{},
+		},
+		{
+			resource: gatewayv1alpha2.SchemeGroupVersion.WithResource("tlsroutes"),
+			object:   &gatewayv1alpha2.TLSRoute{},
+		},
+		{
+			resource: gatewayv1alpha2.SchemeGroupVersion.WithResource("udproutes"),
+			object:   &gatewayv1alpha2.UDPRoute{},
+		},
+	}
+
+	var opts []Option
+	for _, route := range routes {
+		ok, err := crdExists(route.resource)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		opts = append(opts, Option{
+			Object:         route.object,
+			Field:          BackendServiceIndex,
+			ExtractValueFn: BackendServicesOnRoute,
+		})
+	}
+	return opts, nil
+}
+
+// BackendServicesOnRoute returns the Services referenced in the backendRefs of the GRPCRoute,
+// TCPRoute, TLSRoute or UDPRoute.
+func BackendServicesOnRoute(o client.Object) []string {
+	var refs []gwtypes.BackendObjectReference
+	switch route := o.(type) {
+	case *gwtypes.GRPCRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				refs = append(refs, b.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.TCPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				refs = append(refs, b.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.TLSRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				refs = append(refs, b.BackendObjectReference)
+			}
+		}
+	case *gatewayv1alpha2.UDPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				refs = append(refs, b.BackendObjectReference)
+			}
+		}
+	default:
+		return nil
+	}
+	return backendServices(o.GetNamespace(), refs)
+}
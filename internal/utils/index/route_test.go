@@ -0,0 +1,93 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	gwtypes "github.com/kong/kong-operator/internal/types"
+)
+
+func TestOptionsForRoutes(t *testing.T) {
+	opts, err := OptionsForRoutes(func(gvr schema.GroupVersionResource) (bool, error) {
+		return gvr.Resource == "grpcroutes" || gvr.Resource == "tcproutes", nil
+	})
+	require.NoError(t, err)
+	require.Len(t, opts, 2)
+	assert.IsType(t, &gwtypes.GRPCRoute{}, opts[0].Object)
+	assert.IsType(t, &gatewayv1alpha2.TCPRoute{}, opts[1].Object)
+	for _, opt := range opts {
+		assert.Equal(t, BackendServiceIndex, opt.Field)
+	}
+}
+
+func TestBackendServicesOnRoute(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want []string
+	}{
+		{
+			name: "wrong type returns nil",
+			obj:  &gwtypes.HTTPRoute{},
+			want: nil,
+		},
+		{
+			name: "GRPCRoute Services are returned with the route namespace by default",
+			obj: &gwtypes.GRPCRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec: gatewayv1.GRPCRouteSpec{
+					Rules: []gatewayv1.GRPCRouteRule{
+						{
+							BackendRefs: []gatewayv1.GRPCBackendRef{
+								{BackendRef: gwtypes.BackendRef{BackendObjectReference: gwtypes.BackendObjectReference{Name: "svc-1"}}},
+								{BackendRef: gwtypes.BackendRef{BackendObjectReference: gwtypes.BackendObjectReference{
+									Name:      "svc-2",
+									Namespace: lo.ToPtr(gwtypes.Namespace("other")),
+								}}},
+							},
+						},
+					},
+				},
+			},
+			want: []string{"default/svc-1", "other/svc-2"},
+		},
+		{
+			name: "TCPRoute core Services are returned and other backends are skipped",
+			obj: &gatewayv1alpha2.TCPRoute{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+				Spec: gatewayv1alpha2.TCPRouteSpec{
+					Rules: []gatewayv1alpha2.TCPRouteRule{
+						{
+							BackendRefs: []gwtypes.BackendRef{
+								{BackendObjectReference: gwtypes.BackendObjectReference{
+									Name:  "svc",
+									Group: lo.ToPtr(gwtypes.Group("core")),
+								}},
+								{BackendObjectReference: gwtypes.BackendObjectReference{
+									Name:  "bucket",
+									Group: lo.ToPtr(gwtypes.Group("example.com")),
+									Kind:  lo.ToPtr(gwtypes.Kind("Bucket")),
+								}},
+							},
+						},
+					},
+				},
+			},
+			want: []string{"default/svc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BackendServicesOnRoute(tt.obj))
+		})
+	}
+}
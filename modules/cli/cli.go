@@ -55,6 +55,13 @@ func New(m metadata.Info) *CLI {
 	flagSet.StringVar(&deferCfg.ClusterCASecretNamespace, "cluster-ca-secret-namespace", "", "Specifies the namespace of the Secret that contains the cluster CA certificate.")
 	flagSet.Var(&cfg.ClusterCAKeyType, "cluster-ca-key-type", "Type of the key used for the cluster CA certificate (possible values: ecdsa, rsa). Default: ecdsa.")
 	flagSet.IntVar(&cfg.ClusterCAKeySize, "cluster-ca-key-size", mgrconfig.DefaultClusterCAKeySize, "Size (in bits) of the key used for the cluster CA certificate. Only used for RSA keys.")
+	flagSet.DurationVar(&cfg.ClusterCALifetime, "cluster-ca-lifetime", mgrconfig.DefaultClusterCALifetime, "Lifetime of the cluster CA certificates generated by the operator.")
+	flagSet.Float64Var(&cfg.ClusterCARotationFraction, "cluster-ca-rotation-fraction", 0, "Fraction of the cluster CA certificate's lifetime after which the cluster CA is rotated without downtime. Set to 0 to only rotate it on request, by annotating the cluster CA Secret with gateway-operator.konghq.com/ca-rotation-phase=Requested.")
+	flagSet.DurationVar(&cfg.ClusterCARotationDataPlaneTimeout, "cluster-ca-rotation-dataplane-timeout", mgrconfig.DefaultClusterCARotationDataPlaneTimeout, "Time a phase of the cluster CA rotation waits for DataPlane Deployments to roll out before proceeding without the ones which are not in sync, e.g. because they have unavailable Pods. Set to 0 to wait indefinitely.")
+	flagSet.StringVar(&cfg.ClusterCertificateIssuerKind, "cluster-certificate-issuer-kind", "", "Kind of the external issuer of the ControlPlane and DataPlane Admin API mTLS certificates and of the KonnectExtension data plane client certificates (possible values: Issuer, ClusterIssuer, CertificateSigningRequest). When set, the cluster CA is neither generated nor rotated by the operator and the ControlPlane extensions controller and the metrics adapter must be disabled, as the DataPlane metrics scraper and the metrics adapter serving certificate require the cluster CA private key. With CertificateSigningRequest, the cluster CA Secret must hold the signer's CA certificate without its private key. Defaults to signing the certificates with the cluster CA.")
+	flagSet.StringVar(&cfg.ClusterCertificateIssuerName, "cluster-certificate-issuer-name", "", "Name of the cert-manager Issuer or ClusterIssuer, or signer name of the CertificateSigningRequests, issuing the ControlPlane and DataPlane Admin API mTLS certificates and the KonnectExtension data plane client certificates.")
+	flagSet.DurationVar(&cfg.ClusterCertificateLifetime, "cluster-certificate-lifetime", mgrconfig.DefaultClusterCertificateLifetime, "Lifetime of the ControlPlane and DataPlane Admin API mTLS certificates signed by the cluster CA. It's capped by the expiry of the cluster CA certificate.")
+	flagSet.Float64Var(&cfg.ClusterCertificateRenewalFraction, "cluster-certificate-renewal-fraction", mgrconfig.DefaultClusterCertificateRenewalFraction, "Fraction of the ControlPlane and DataPlane Admin API mTLS certificates' lifetime after which they are renewed. DataPlane Pods are rolled out with the renewed certificate. Set to 0 to disable the renewal.")
 	flagSet.DurationVar(&cfg.CacheSyncTimeout, "cache-sync-timeout", 0, "Sets the time limit for syncing controller caches. Defaults to the controller-runtime value if set to `0`.")
 	flagSet.StringVar(&cfg.ClusterDomain, "cluster-domain", ingressmgrconfig.DefaultClusterDomain, "The cluster domain. This is used e.g. in generating addresses for upstream services.")
 	flagSet.DurationVar(&cfg.CacheSyncPeriod, "cache-sync-period", 0, "Sets the minimum frequency for reconciling watched resources. Defaults to the controller-runtime value if unspecified or set to 0s.")
@@ -72,13 +79,23 @@ func New(m metadata.Info) *CLI {
 	flagSet.BoolVar(&cfg.ControlPlaneConfigurationDumpEnabled, "enable-controlplane-config-dump", false, "Enable the server to dump generated Kong configuration from ControlPlanes. Only effective when ControlPlane controller is enabled.")
 	flagSet.StringVar(&cfg.ControlPlaneConfigurationDumpAddr, "controlplane-config-dump-bind-address", manager.DefaultControlPlaneConfigurationDumpAddr, "The address where server dumps ControlPlane configuration. Only enabled when 'enable-controlplane-config-dump' is true.")
 
+	// custom metrics API adapter
+	flagSet.BoolVar(&cfg.MetricsAdapterEnabled, "enable-metrics-adapter", false, "Enable the server serving the custom metrics API with per Pod Kong metrics of DataPlanes scraped through DataPlaneMetricsExtensions. Only effective when ControlPlane extensions controller is enabled.")
+	flagSet.StringVar(&cfg.MetricsAdapterAddr, "metrics-adapter-bind-address", manager.DefaultMetricsAdapterAddr, "The address the custom metrics API adapter server binds to. Only enabled when 'enable-metrics-adapter' is true.")
+	flagSet.StringVar(&cfg.MetricsAdapterServiceName, "metrics-adapter-service-name", manager.DefaultMetricsAdapterServiceName, "The name of the Service in the operator's namespace through which the Kubernetes API server reaches the custom metrics API adapter server. Its DNS names are set in the adapter's serving certificate.")
+
 	// controllers for specialized APIs and features
 	flagSet.BoolVar(&cfg.AIGatewayControllerEnabled, "enable-controller-aigateway", false, "Enable the AIGateway controller. (Experimental).")
 	flagSet.BoolVar(&cfg.KongPluginInstallationControllerEnabled, "enable-controller-kongplugininstallation", false, "Enable the KongPluginInstallation controller.")
+	flagSet.BoolVar(&cfg.KongPluginImageVerificationRequired, "kongplugininstallation-require-image-verification", false, "Require the cosign signature of KongPluginInstallations' plugin images to be verified before plugins are installed. Only effective when KongPluginInstallation controller is enabled.")
+	flagSet.StringVar(&cfg.KongPluginCosignSecretName, "kongplugininstallation-cosign-secret", "", "Name of the Secret in the operator's namespace holding the cosign public keys, Fulcio roots and Rekor public keys trusted at the operator level to verify plugin images. When set, all KongPluginInstallations' plugin images are verified with it and their cosign annotations are ignored.")
+	flagSet.StringVar(&cfg.KongPluginCosignIdentity, "kongplugininstallation-cosign-identity", "", "Identity (an email address or a URI) of the cosign keyless signatures of plugin images trusted at the operator level. Requires 'kongplugininstallation-cosign-issuer' and 'kongplugininstallation-cosign-secret' to be set.")
+	flagSet.StringVar(&cfg.KongPluginCosignIssuer, "kongplugininstallation-cosign-issuer", "", "OIDC issuer of the cosign keyless signatures of plugin images trusted at the operator level. Requires 'kongplugininstallation-cosign-identity' and 'kongplugininstallation-cosign-secret' to be set.")
 	flagSet.BoolVar(&cfg.GatewayAPIExperimentalEnabled, "enable-gateway-api-experimental", false, "Enable the Gateway API experimental features.")
 
 	// controllers for Konnect APIs
 	flagSet.BoolVar(&cfg.KonnectControllersEnabled, "enable-controller-konnect", false, "Enable the Konnect controllers.")
+	flagSet.BoolVar(&cfg.FullHybridControllerEnabled, "enable-controller-fullhybrid", false, "Enable the controller translating HTTPRoutes attached to Konnect-backed Gateways into Konnect entities. Only effective when Konnect controllers are enabled.")
 	flagSet.DurationVar(&cfg.KonnectSyncPeriod, "konnect-sync-period", consts.DefaultKonnectSyncPeriod, "Sync period for Konnect entities. After a successful reconciliation of Konnect entities the controller will wait this duration before enforcing configuration on Konnect once again.")
 	flagSet.UintVar(&cfg.KonnectMaxConcurrentReconciles, "konnect-controller-max-concurrent-reconciles", consts.DefaultKonnectMaxConcurrentReconciles, "Maximum number of concurrent reconciles for Konnect entities.")
 
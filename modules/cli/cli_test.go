@@ -275,16 +275,25 @@ func expectedDefaultCfg() manager.Config {
 		ClusterCASecretNamespace:                "kong-system",
 		ClusterCAKeyType:                        mgrconfig.ECDSA,
 		ClusterCAKeySize:                        mgrconfig.DefaultClusterCAKeySize,
+		ClusterCALifetime:                       mgrconfig.DefaultClusterCALifetime,
+		ClusterCARotationDataPlaneTimeout:       mgrconfig.DefaultClusterCARotationDataPlaneTimeout,
+		ClusterCertificateLifetime:              mgrconfig.DefaultClusterCertificateLifetime,
+		ClusterCertificateRenewalFraction:       mgrconfig.DefaultClusterCertificateRenewalFraction,
 		GatewayControllerEnabled:                true,
 		ControlPlaneControllerEnabled:           true,
 		DataPlaneControllerEnabled:              true,
 		DataPlaneBlueGreenControllerEnabled:     true,
 		ControlPlaneConfigurationDumpEnabled:    false,
 		ControlPlaneConfigurationDumpAddr:       ":10256",
+		MetricsAdapterEnabled:                   false,
+		MetricsAdapterAddr:                      ":6443",
+		MetricsAdapterServiceName:               "gateway-operator-metrics-adapter",
 		ControlPlaneExtensionsControllerEnabled: true,
 		KonnectControllersEnabled:               false,
+		FullHybridControllerEnabled:             false,
 		KonnectSyncPeriod:                       consts.DefaultKonnectSyncPeriod,
 		KongPluginInstallationControllerEnabled: false,
+		KongPluginImageVerificationRequired:     false,
 		LoggerOpts:                              &zap.Options{},
 		KonnectMaxConcurrentReconciles:          consts.DefaultKonnectMaxConcurrentReconciles,
 		ClusterDomain:                           ingressmgrconfig.DefaultClusterDomain,
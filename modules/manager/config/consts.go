@@ -1,8 +1,26 @@
 package config
 
+import "time"
+
 // DefaultClusterCAKeySize is the default size of the cluster CA key.
 const DefaultClusterCAKeySize = 4096
 
+// DefaultClusterCALifetime is the default lifetime of the cluster CA certificate.
+const DefaultClusterCALifetime = 315400000 * time.Second
+
+// DefaultClusterCARotationDataPlaneTimeout is the default time a phase of the cluster CA
+// rotation waits for DataPlane Deployments to roll out before proceeding without them.
+const DefaultClusterCARotationDataPlaneTimeout = 30 * time.Minute
+
+const (
+	// DefaultClusterCertificateLifetime is the default lifetime of the ControlPlane and DataPlane
+	// Admin API certificates signed by the cluster CA.
+	DefaultClusterCertificateLifetime = 315400000 * time.Second
+	// DefaultClusterCertificateRenewalFraction is the default fraction of the ControlPlane and DataPlane
+	// Admin API certificates' lifetime after which they're renewed.
+	DefaultClusterCertificateRenewalFraction = 0.67
+)
+
 const (
 	// DefaultSecretLabelSelector is the deafult label selector to filter reconciled `Secret`s.
 	DefaultSecretLabelSelector = "konghq.com/secret"
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -26,10 +27,12 @@ import (
 	konnectv1alpha1 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha1"
 	konnectv1alpha2 "github.com/kong/kubernetes-configuration/v2/api/konnect/v1alpha2"
 
+	"github.com/kong/kong-operator/controller/clusterca"
 	"github.com/kong/kong-operator/controller/controlplane"
 	"github.com/kong/kong-operator/controller/controlplane_extensions"
 	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
 	"github.com/kong/kong-operator/controller/dataplane"
+	"github.com/kong/kong-operator/controller/fullhybrid"
 	"github.com/kong/kong-operator/controller/gateway"
 	"github.com/kong/kong-operator/controller/gatewayclass"
 	"github.com/kong/kong-operator/controller/kongplugininstallation"
@@ -43,6 +46,7 @@ import (
 	gwtypes "github.com/kong/kong-operator/internal/types"
 	"github.com/kong/kong-operator/internal/utils/index"
 	"github.com/kong/kong-operator/modules/manager/logging"
+	"github.com/kong/kong-operator/modules/metricsadapter"
 	"github.com/kong/kong-operator/pkg/consts"
 	k8sutils "github.com/kong/kong-operator/pkg/utils/kubernetes"
 )
@@ -123,6 +127,22 @@ func SetupCacheIndexes(ctx context.Context, mgr manager.Manager, cfg Config) err
 			index.OptionsForKonnectCloudGatewayDataPlaneGroupConfiguration(cl),
 		)
 	}
+	if cfg.GatewayControllerEnabled || (cfg.FullHybridControllerEnabled && cfg.KonnectControllersEnabled) {
+		indexOptions = slices.Concat(indexOptions, index.OptionsForHTTPRoute())
+	}
+	if cfg.GatewayControllerEnabled {
+		routeOptions, err := index.OptionsForRoutes(k8sutils.CRDChecker{Client: mgr.GetClient()}.CRDExists)
+		if err != nil {
+			return fmt.Errorf("failed to check route CRDs: %w", err)
+		}
+		indexOptions = slices.Concat(indexOptions, routeOptions)
+	}
+	if cfg.FullHybridControllerEnabled && cfg.KonnectControllersEnabled {
+		indexOptions = slices.Concat(indexOptions,
+			index.OptionsForGateway(),
+			index.OptionsForGatewayClass(),
+		)
+	}
 
 	for _, e := range indexOptions {
 		ctrllog.FromContext(ctx).Info("Setting up index", "index", e.String())
@@ -179,6 +199,16 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 				operatorv1alpha1.KongPluginInstallationGVR(),
 			},
 		},
+		{
+			Condition: c.FullHybridControllerEnabled && c.KonnectControllersEnabled,
+			GVRs: []schema.GroupVersionResource{
+				{
+					Group:    gatewayv1.SchemeGroupVersion.Group,
+					Version:  gatewayv1.SchemeGroupVersion.Version,
+					Resource: "httproutes",
+				},
+			},
+		},
 		{
 			Condition: c.AIGatewayControllerEnabled,
 			GVRs: []schema.GroupVersionResource{
@@ -338,6 +368,49 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 		Size: c.ClusterCAKeySize,
 	}
 
+	clusterCertificateRenewalConfig := secrets.CertificateRenewalConfig{
+		Lifetime:         c.ClusterCertificateLifetime,
+		RenewalFraction:  c.ClusterCertificateRenewalFraction,
+		FollowCARotation: true,
+	}
+	if err := clusterCertificateRenewalConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cluster certificate renewal configuration: %w", err)
+	}
+
+	clusterCertificateIssuer := secrets.IssuerConfig{
+		Kind: secrets.IssuerKind(c.ClusterCertificateIssuerKind),
+		Name: c.ClusterCertificateIssuerName,
+	}
+	if err := clusterCertificateIssuer.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cluster certificate issuer configuration: %w", err)
+	}
+	// The DataPlane metrics scraper signs its client certificate, and the metrics adapter
+	// its serving certificate, with the cluster CA private key, which isn't available
+	// when certificates are issued by an external issuer.
+	if clusterCertificateIssuer.IsExternal() && c.ControlPlaneExtensionsControllerEnabled {
+		return nil, fmt.Errorf(
+			"cluster certificate issuer kind %s can't be used with the ControlPlane extensions controller enabled: "+
+				"the DataPlane metrics scraper requires the cluster CA private key", clusterCertificateIssuer.Kind,
+		)
+	}
+	// The metrics adapter's serving certificate isn't issued through the external issuer either:
+	// it's trusted by the Kubernetes API server through the APIService caBundle, which is only
+	// kept in sync with the cluster CA trust bundle.
+	if clusterCertificateIssuer.IsExternal() && c.MetricsAdapterEnabled {
+		return nil, fmt.Errorf(
+			"cluster certificate issuer kind %s can't be used with the metrics adapter enabled: "+
+				"its serving certificate requires the cluster CA private key", clusterCertificateIssuer.Kind,
+		)
+	}
+
+	clusterCARenewalConfig := secrets.CertificateRenewalConfig{
+		Lifetime:        c.ClusterCALifetime,
+		RenewalFraction: c.ClusterCARotationFraction,
+	}
+	if err := clusterCARenewalConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid cluster CA rotation configuration: %w", err)
+	}
+
 	const (
 		// NOTE: This will be parametrized.
 		metricsScrapeInterval = 10 * time.Second
@@ -352,8 +425,57 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 		},
 		clusterCAKeyConfig,
 	)
-	if err := mgr.Add(scrapersMgr); err != nil {
-		return nil, fmt.Errorf("failed to add scrapers manager to controller-runtime manager: %w", err)
+	// Without the cluster CA private key, metrics can't be scraped and promotion analysis
+	// of blue green rollouts reports that metrics aren't available.
+	var rolloutMetricsProvider dataplane.RolloutMetricsProvider
+	if !clusterCertificateIssuer.IsExternal() {
+		if err := mgr.Add(scrapersMgr); err != nil {
+			return nil, fmt.Errorf("failed to add scrapers manager to controller-runtime manager: %w", err)
+		}
+		rolloutMetricsProvider = scrapersMgr
+	}
+	if c.MetricsAdapterEnabled && c.ControlPlaneExtensionsControllerEnabled {
+		adapterLogger := ctrl.Log.WithName("metrics_adapter")
+		kubeClient, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client for metrics adapter: %w", err)
+		}
+		adapterNamespace, err := k8sutils.GetSelfNamespace()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace of metrics adapter Service: %w", err)
+		}
+		if err := mgr.Add(&metricsadapter.Server{
+			Addr: c.MetricsAdapterAddr,
+			Handler: metricsadapter.NewHTTPHandler(
+				mgr.GetClient(),
+				adapterLogger.WithName("http_handler"),
+				scrapersMgr,
+			),
+			Client:     mgr.GetClient(),
+			KubeClient: kubeClient,
+			CASecretNN: k8stypes.NamespacedName{
+				Name:      c.ClusterCASecretName,
+				Namespace: c.ClusterCASecretNamespace,
+			},
+			CAKeyConfig: clusterCAKeyConfig,
+			ServiceNN: k8stypes.NamespacedName{
+				Name:      c.MetricsAdapterServiceName,
+				Namespace: adapterNamespace,
+			},
+			Logger: adapterLogger,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add metrics adapter server to controller-runtime manager: %w", err)
+		}
+		if err := mgr.Add(&metricsadapter.APIServiceCABundleSyncer{
+			Client: mgr.GetClient(),
+			CASecretNN: k8stypes.NamespacedName{
+				Name:      c.ClusterCASecretName,
+				Namespace: c.ClusterCASecretNamespace,
+			},
+			Logger: adapterLogger.WithName("apiservice"),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to add metrics adapter APIService caBundle syncer to controller-runtime manager: %w", err)
+		}
 	}
 	podLabels, err := k8sutils.GetSelfPodLabels()
 	if err != nil {
@@ -397,73 +519,100 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 		{
 			Enabled: c.GatewayControllerEnabled || c.ControlPlaneControllerEnabled,
 			Controller: &controlplane.Reconciler{
-				CacheSyncPeriod:          c.CacheSyncPeriod,
-				CacheSyncTimeout:         c.CacheSyncTimeout,
-				AnonymousReportsEnabled:  c.AnonymousReports,
-				LoggingMode:              c.LoggingMode,
-				Client:                   mgr.GetClient(),
-				ClusterCASecretName:      c.ClusterCASecretName,
-				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
-				ClusterCAKeyConfig:       clusterCAKeyConfig,
-				SecretLabelSelector:      c.SecretLabelSelector,
-				ConfigMapLabelSelector:   c.ConfigMapLabelSelector,
-				KonnectEnabled:           c.KonnectControllersEnabled,
-				EnforceConfig:            c.EnforceConfig,
-				KubeConfigPath:           c.KubeconfigPath,
-				RestConfig:               mgr.GetConfig(),
-				InstancesManager:         cpsMgr,
-				ClusterDomain:            c.ClusterDomain,
-				EmitKubernetesEvents:     c.EmitKubernetesEvents,
-				WatchNamespaces:          c.WatchNamespaces,
+				CacheSyncPeriod:                 c.CacheSyncPeriod,
+				CacheSyncTimeout:                c.CacheSyncTimeout,
+				AnonymousReportsEnabled:         c.AnonymousReports,
+				LoggingMode:                     c.LoggingMode,
+				Client:                          mgr.GetClient(),
+				ClusterCASecretName:             c.ClusterCASecretName,
+				ClusterCASecretNamespace:        c.ClusterCASecretNamespace,
+				ClusterCAKeyConfig:              clusterCAKeyConfig,
+				ClusterCertificateRenewalConfig: clusterCertificateRenewalConfig,
+				ClusterCertificateIssuer:        clusterCertificateIssuer,
+				SecretLabelSelector:             c.SecretLabelSelector,
+				ConfigMapLabelSelector:          c.ConfigMapLabelSelector,
+				KonnectEnabled:                  c.KonnectControllersEnabled,
+				EnforceConfig:                   c.EnforceConfig,
+				KubeConfigPath:                  c.KubeconfigPath,
+				RestConfig:                      mgr.GetConfig(),
+				InstancesManager:                cpsMgr,
+				ClusterDomain:                   c.ClusterDomain,
+				EmitKubernetesEvents:            c.EmitKubernetesEvents,
+				WatchNamespaces:                 c.WatchNamespaces,
+			},
+		},
+		// ClusterCA controller
+		{
+			// There's no cluster CA to rotate when certificates are issued by an external issuer.
+			Enabled: (c.GatewayControllerEnabled || c.ControlPlaneControllerEnabled || c.DataPlaneControllerEnabled || c.DataPlaneBlueGreenControllerEnabled) &&
+				!clusterCertificateIssuer.IsExternal(),
+			Controller: &clusterca.Reconciler{
+				Client:           mgr.GetClient(),
+				CacheSyncTimeout: c.CacheSyncTimeout,
+				LoggingMode:      c.LoggingMode,
+				CASecretNN: k8stypes.NamespacedName{
+					Name:      c.ClusterCASecretName,
+					Namespace: c.ClusterCASecretNamespace,
+				},
+				CAKeyConfig:          clusterCAKeyConfig,
+				CARenewalConfig:      clusterCARenewalConfig,
+				DataPlaneSyncTimeout: c.ClusterCARotationDataPlaneTimeout,
 			},
 		},
 		// DataPlane controller
 		{
 			Enabled: (c.DataPlaneControllerEnabled || c.GatewayControllerEnabled) && !c.DataPlaneBlueGreenControllerEnabled,
 			Controller: &dataplane.Reconciler{
-				CacheSyncTimeout:         c.CacheSyncTimeout,
-				Client:                   mgr.GetClient(),
-				ClusterCASecretName:      c.ClusterCASecretName,
-				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
-				ClusterCAKeyConfig:       clusterCAKeyConfig,
-				SecretLabelSelector:      c.SecretLabelSelector,
-				ConfigMapLabelSelector:   c.ConfigMapLabelSelector,
-				DefaultImage:             consts.DefaultDataPlaneImage,
-				KonnectEnabled:           c.KonnectControllersEnabled,
-				EnforceConfig:            c.EnforceConfig,
-				LoggingMode:              c.LoggingMode,
-				ValidateDataPlaneImage:   c.ValidateImages,
+				CacheSyncTimeout:                c.CacheSyncTimeout,
+				Client:                          mgr.GetClient(),
+				ClusterCASecretName:             c.ClusterCASecretName,
+				ClusterCASecretNamespace:        c.ClusterCASecretNamespace,
+				ClusterCAKeyConfig:              clusterCAKeyConfig,
+				ClusterCertificateRenewalConfig: clusterCertificateRenewalConfig,
+				ClusterCertificateIssuer:        clusterCertificateIssuer,
+				SecretLabelSelector:             c.SecretLabelSelector,
+				ConfigMapLabelSelector:          c.ConfigMapLabelSelector,
+				DefaultImage:                    consts.DefaultDataPlaneImage,
+				KonnectEnabled:                  c.KonnectControllersEnabled,
+				EnforceConfig:                   c.EnforceConfig,
+				LoggingMode:                     c.LoggingMode,
+				ValidateDataPlaneImage:          c.ValidateImages,
 			},
 		},
 		// DataPlaneBlueGreen controller
 		{
 			Enabled: c.DataPlaneBlueGreenControllerEnabled,
 			Controller: &dataplane.BlueGreenReconciler{
-				CacheSyncTimeout:         c.CacheSyncTimeout,
-				Client:                   mgr.GetClient(),
-				ClusterCASecretName:      c.ClusterCASecretName,
-				ClusterCASecretNamespace: c.ClusterCASecretNamespace,
-				ClusterCAKeyConfig:       clusterCAKeyConfig,
-				SecretLabelSelector:      c.SecretLabelSelector,
+				CacheSyncTimeout:                c.CacheSyncTimeout,
+				Client:                          mgr.GetClient(),
+				ClusterCASecretName:             c.ClusterCASecretName,
+				ClusterCASecretNamespace:        c.ClusterCASecretNamespace,
+				ClusterCAKeyConfig:              clusterCAKeyConfig,
+				ClusterCertificateRenewalConfig: clusterCertificateRenewalConfig,
+				ClusterCertificateIssuer:        clusterCertificateIssuer,
+				SecretLabelSelector:             c.SecretLabelSelector,
 				DataPlaneController: &dataplane.Reconciler{
-					CacheSyncTimeout:         c.CacheSyncTimeout,
-					Client:                   mgr.GetClient(),
-					ClusterCASecretName:      c.ClusterCASecretName,
-					ClusterCASecretNamespace: c.ClusterCASecretNamespace,
-					ClusterCAKeyConfig:       clusterCAKeyConfig,
-					SecretLabelSelector:      c.SecretLabelSelector,
-					ConfigMapLabelSelector:   c.ConfigMapLabelSelector,
-					DefaultImage:             consts.DefaultDataPlaneImage,
-					KonnectEnabled:           c.KonnectControllersEnabled,
-					EnforceConfig:            c.EnforceConfig,
-					ValidateDataPlaneImage:   c.ValidateImages,
-					LoggingMode:              c.LoggingMode,
+					CacheSyncTimeout:                c.CacheSyncTimeout,
+					Client:                          mgr.GetClient(),
+					ClusterCASecretName:             c.ClusterCASecretName,
+					ClusterCASecretNamespace:        c.ClusterCASecretNamespace,
+					ClusterCAKeyConfig:              clusterCAKeyConfig,
+					ClusterCertificateRenewalConfig: clusterCertificateRenewalConfig,
+					ClusterCertificateIssuer:        clusterCertificateIssuer,
+					SecretLabelSelector:             c.SecretLabelSelector,
+					ConfigMapLabelSelector:          c.ConfigMapLabelSelector,
+					DefaultImage:                    consts.DefaultDataPlaneImage,
+					KonnectEnabled:                  c.KonnectControllersEnabled,
+					EnforceConfig:                   c.EnforceConfig,
+					ValidateDataPlaneImage:          c.ValidateImages,
+					LoggingMode:                     c.LoggingMode,
 				},
 				DefaultImage:           consts.DefaultDataPlaneImage,
 				KonnectEnabled:         c.KonnectControllersEnabled,
 				EnforceConfig:          c.EnforceConfig,
 				ValidateDataPlaneImage: c.ValidateImages,
 				LoggingMode:            c.LoggingMode,
+				RolloutMetricsProvider: rolloutMetricsProvider,
 			},
 		},
 		// DataPlaneOwnedServiceFinalizer controller
@@ -506,11 +655,15 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 		{
 			Enabled: c.KongPluginInstallationControllerEnabled,
 			Controller: &kongplugininstallation.Reconciler{
-				CacheSyncTimeout:       c.CacheSyncTimeout,
-				Client:                 mgr.GetClient(),
-				Scheme:                 mgr.GetScheme(),
-				LoggingMode:            c.LoggingMode,
-				ConfigMapLabelSelector: c.ConfigMapLabelSelector,
+				CacheSyncTimeout:         c.CacheSyncTimeout,
+				Client:                   mgr.GetClient(),
+				Scheme:                   mgr.GetScheme(),
+				LoggingMode:              c.LoggingMode,
+				ConfigMapLabelSelector:   c.ConfigMapLabelSelector,
+				RequireImageVerification: c.KongPluginImageVerificationRequired,
+				TrustedCosignSecretNN:    kongPluginTrustedCosignSecretNN(c),
+				TrustedCosignIdentity:    c.KongPluginCosignIdentity,
+				TrustedCosignIssuer:      c.KongPluginCosignIssuer,
 			},
 		},
 		// ControlPlaneExtensions controller
@@ -577,9 +730,28 @@ func SetupControllers(mgr manager.Manager, c *Config, cpsMgr *multiinstance.Mana
 					ClusterCASecretName:      c.ClusterCASecretName,
 					ClusterCASecretNamespace: c.ClusterCASecretNamespace,
 					ClusterCAKeyConfig:       clusterCAKeyConfig,
+					ClusterCertificateIssuer: clusterCertificateIssuer,
 					SecretLabelSelector:      c.SecretLabelSelector,
 				},
 			},
+			// Full-hybrid HTTPRoute controller
+			ControllerDef{
+				Enabled: c.FullHybridControllerEnabled && c.KonnectControllersEnabled,
+				Controller: &fullhybrid.HTTPRouteReconciler{
+					CacheSyncTimeout: c.CacheSyncTimeout,
+					Client:           mgr.GetClient(),
+					LoggingMode:      c.LoggingMode,
+				},
+			},
+			// Full-hybrid Service controller
+			ControllerDef{
+				Enabled: c.FullHybridControllerEnabled && c.KonnectControllersEnabled,
+				Controller: &fullhybrid.ServiceReconciler{
+					CacheSyncTimeout: c.CacheSyncTimeout,
+					Client:           mgr.GetClient(),
+					LoggingMode:      c.LoggingMode,
+				},
+			},
 		)
 
 		// Add controllers responsible for cleaning up KongPluginBinding cleanup finalizers
@@ -632,6 +804,18 @@ type konnectControllerFactory struct {
 	metricRecorder          metrics.Recorder
 }
 
+// kongPluginTrustedCosignSecretNN returns the Secret holding the cosign keys and
+// roots trusted at the operator level to verify plugin images, if configured.
+func kongPluginTrustedCosignSecretNN(c *Config) k8stypes.NamespacedName {
+	if c.KongPluginCosignSecretName == "" {
+		return k8stypes.NamespacedName{}
+	}
+	return k8stypes.NamespacedName{
+		Namespace: c.ControllerNamespace,
+		Name:      c.KongPluginCosignSecretName,
+	}
+}
+
 func newKonnectEntityController[
 	T constraints.SupportedKonnectEntityType,
 	TEnt constraints.EntityType[T],
@@ -79,10 +79,29 @@ type Config struct {
 	ClusterCASecretNamespace string
 	ClusterCAKeyType         mgrconfig.KeyType
 	ClusterCAKeySize         int
-	LoggerOpts               *zap.Options
-	EnforceConfig            bool
-	ClusterDomain            string
-	EmitKubernetesEvents     bool
+	// ClusterCALifetime is the lifetime of generated cluster CA certificates.
+	ClusterCALifetime time.Duration
+	// ClusterCARotationFraction is the fraction of the cluster CA certificate's lifetime
+	// after which the cluster CA is rotated. The rotation is only performed on request when it's 0.
+	ClusterCARotationFraction float64
+	// ClusterCARotationDataPlaneTimeout is the time a phase of the cluster CA rotation waits for
+	// DataPlane Deployments to roll out before proceeding without the ones not in sync.
+	ClusterCARotationDataPlaneTimeout time.Duration
+	// ClusterCertificateLifetime is the lifetime of the ControlPlane and DataPlane Admin API certificates.
+	ClusterCertificateLifetime time.Duration
+	// ClusterCertificateRenewalFraction is the fraction of the ControlPlane and DataPlane Admin API
+	// certificates' lifetime after which they're renewed. 0 disables the renewal.
+	ClusterCertificateRenewalFraction float64
+	// ClusterCertificateIssuerKind is the kind of the external issuer of the ControlPlane and DataPlane
+	// Admin API certificates. They're signed by the cluster CA when it's empty.
+	ClusterCertificateIssuerKind string
+	// ClusterCertificateIssuerName is the name of the cert-manager issuer or the signer name
+	// of the external issuer of the ControlPlane and DataPlane Admin API certificates.
+	ClusterCertificateIssuerName string
+	LoggerOpts                   *zap.Options
+	EnforceConfig                bool
+	ClusterDomain                string
+	EmitKubernetesEvents         bool
 	// SecretLabelSelector specifies the label which will be used to limit the ingestion of secrets. Only those that have this label set to "true" will be ingested.
 	SecretLabelSelector string
 	// ConfigMapLabelSelector specifies the label which will be used to limit the ingestion of configmaps. Only those that have this label set to "true" will be ingested.
@@ -105,9 +124,18 @@ type Config struct {
 	ControlPlaneConfigurationDumpEnabled bool
 	ControlPlaneConfigurationDumpAddr    string
 
+	// Options for the custom metrics API adapter serving DataPlane Pods' metrics.
+	MetricsAdapterEnabled     bool
+	MetricsAdapterAddr        string
+	MetricsAdapterServiceName string
+
 	// Controllers for specialty APIs and experimental features.
 	AIGatewayControllerEnabled              bool
 	KongPluginInstallationControllerEnabled bool
+	KongPluginImageVerificationRequired     bool
+	KongPluginCosignSecretName              string
+	KongPluginCosignIdentity                string
+	KongPluginCosignIssuer                  string
 	KonnectSyncPeriod                       time.Duration
 	KonnectMaxConcurrentReconciles          uint
 	GatewayAPIExperimentalEnabled           bool
@@ -115,6 +143,9 @@ type Config struct {
 
 	// Controllers for Konnect APIs.
 	KonnectControllersEnabled bool
+	// FullHybridControllerEnabled enables the controller translating HTTPRoutes
+	// into Konnect entities. Only effective when Konnect controllers are enabled.
+	FullHybridControllerEnabled bool
 
 	// Webhook options.
 	ConversionWebhookEnabled bool
@@ -127,6 +158,10 @@ const (
 	DefaultProbeAddr = ":8081"
 	// DefaultControlPlaneConfigurationDumpAddr is the default bind address for the server to dump ControlPlane configuration.
 	DefaultControlPlaneConfigurationDumpAddr = ":10256"
+	// DefaultMetricsAdapterAddr is the default bind address for the custom metrics API adapter server.
+	DefaultMetricsAdapterAddr = ":6443"
+	// DefaultMetricsAdapterServiceName is the default name of the Service exposing the custom metrics API adapter server.
+	DefaultMetricsAdapterServiceName = "gateway-operator-metrics-adapter"
 )
 
 // DefaultConfig returns a default configuration for the manager.
@@ -270,6 +305,10 @@ func Run(
 		return fmt.Errorf("unsupported cluster CA key type: %w", err)
 	}
 
+	issuer := secrets.IssuerConfig{
+		Kind: secrets.IssuerKind(cfg.ClusterCertificateIssuerKind),
+		Name: cfg.ClusterCertificateIssuerName,
+	}
 	caMgr := &caManager{
 		Logger:          ctrl.Log.WithName("ca_manager"),
 		Client:          mgr.GetClient(),
@@ -279,6 +318,8 @@ func Run(
 			Type: keyType,
 			Size: cfg.ClusterCAKeySize,
 		},
+		Lifetime: cfg.ClusterCALifetime,
+		Disabled: issuer.IsExternal(),
 	}
 	if cfg.SecretLabelSelector != "" {
 		caMgr.SecretLabels = map[string]string{
@@ -396,6 +437,7 @@ func warnIfLegacyDevelopmentModeEnabled(log logr.Logger) {
 }
 
 // caManager is a manager responsible for creating a cluster CA certificate.
+// Its rotation is handled by the cluster CA controller.
 type caManager struct {
 	Logger          logr.Logger
 	Client          client.Client
@@ -403,10 +445,18 @@ type caManager struct {
 	SecretNamespace string
 	SecretLabels    map[string]string
 	KeyConfig       secrets.KeyConfig
+	Lifetime        time.Duration
+	// Disabled prevents the creation of the cluster CA, so that no CA private key is stored
+	// in the cluster when certificates are issued by an external issuer.
+	Disabled bool
 }
 
 // Start starts the CA manager.
 func (m *caManager) Start(ctx context.Context) error {
+	if m.Disabled {
+		m.Logger.Info("certificates are issued by an external issuer, not generating a cluster CA certificate")
+		return nil
+	}
 	if m.SecretName == "" {
 		return fmt.Errorf("cannot use an empty secret name when creating a CA secret")
 	}
@@ -417,8 +467,7 @@ func (m *caManager) Start(ctx context.Context) error {
 }
 
 func (m *caManager) maybeCreateCACertificate(ctx context.Context) error {
-	// TODO https://github.com/kong/kong-operator/issues/199 this also needs to check if the CA is expired and
-	// managed, and needs to reissue it (and all issued certificates) if so
+	// NOTE: The rotation of an existing CA is performed by the cluster CA controller.
 	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
 	defer cancel()
 
@@ -430,7 +479,7 @@ func (m *caManager) maybeCreateCACertificate(ctx context.Context) error {
 	if err := m.Client.Get(ctx, objectKey, &ca); err != nil {
 		if k8serrors.IsNotFound(err) {
 			m.Logger.Info(fmt.Sprintf("no CA certificate Secret %s found, generating CA certificate", objectKey))
-			return secrets.CreateClusterCACertificate(ctx, m.Client, objectKey, m.SecretLabels, m.KeyConfig)
+			return secrets.CreateClusterCACertificate(ctx, m.Client, objectKey, m.SecretLabels, m.KeyConfig, m.Lifetime)
 		}
 
 		return err
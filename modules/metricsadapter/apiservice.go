@@ -0,0 +1,113 @@
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+)
+
+var apiServiceGVK = schema.GroupVersionKind{
+	Group:   "apiregistration.k8s.io",
+	Version: "v1",
+	Kind:    "APIService",
+}
+
+// APIServiceCABundleSyncer keeps the caBundle of the APIService registering the
+// metrics adapter in sync with the cluster CA trust bundle, so that the Kubernetes
+// API server trusts the serving certificates issued by every replica.
+// It runs on the leader only.
+type APIServiceCABundleSyncer struct {
+	// Client is used to read the cluster CA Secret and to patch the APIService's caBundle.
+	Client client.Client
+	// CASecretNN is the cluster CA Secret signing the serving certificates.
+	CASecretNN types.NamespacedName
+
+	Logger logr.Logger
+
+	caBundleChecksum string
+}
+
+var (
+	_ manager.Runnable               = &APIServiceCABundleSyncer{}
+	_ manager.LeaderElectionRunnable = &APIServiceCABundleSyncer{}
+)
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface: only the
+// leader patches the APIService.
+func (s *APIServiceCABundleSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start implements the Start method of manager.Runnable interface to add to the manager.
+// It syncs the caBundle of the APIService on every cluster CA check until ctx expires.
+func (s *APIServiceCABundleSyncer) Start(ctx context.Context) error {
+	if err := s.ensureCABundle(ctx); err != nil {
+		s.Logger.Error(err, "Failed syncing metrics adapter APIService caBundle")
+	}
+
+	ticker := time.NewTicker(servingCertRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.ensureCABundle(ctx); err != nil {
+				s.Logger.Error(err, "Failed syncing metrics adapter APIService caBundle")
+			}
+		}
+	}
+}
+
+// ensureCABundle patches the caBundle of the APIService when the cluster CA trust
+// bundle changed since the last successful patch.
+func (s *APIServiceCABundleSyncer) ensureCABundle(ctx context.Context) error {
+	var caSecret corev1.Secret
+	if err := s.Client.Get(ctx, s.CASecretNN, &caSecret); err != nil {
+		return fmt.Errorf("failed getting cluster CA Secret %s: %w", s.CASecretNN, err)
+	}
+	caBundle := secrets.CATrustBundle(&caSecret)
+	checksum := secrets.CATrustBundleChecksum(caBundle)
+	if checksum == s.caBundleChecksum {
+		return nil
+	}
+
+	if err := s.patchAPIServiceCABundle(ctx, caBundle); err != nil {
+		return err
+	}
+	s.caBundleChecksum = checksum
+	s.Logger.Info("Metrics adapter APIService caBundle synced")
+	return nil
+}
+
+// patchAPIServiceCABundle sets the caBundle of the APIService registering the
+// metrics adapter to the cluster CA trust bundle.
+func (s *APIServiceCABundleSyncer) patchAPIServiceCABundle(ctx context.Context, caBundle []byte) error {
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{
+			"caBundle": caBundle,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed marshaling APIService patch: %w", err)
+	}
+
+	apiService := &unstructured.Unstructured{}
+	apiService.SetGroupVersionKind(apiServiceGVK)
+	apiService.SetName(Version + "." + GroupName)
+	if err := s.Client.Patch(ctx, apiService, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed patching caBundle of APIService %s: %w", apiService.GetName(), err)
+	}
+	return nil
+}
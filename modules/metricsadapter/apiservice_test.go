@@ -0,0 +1,77 @@
+package metricsadapter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestLeaderElection(t *testing.T) {
+	assert.False(t, (&Server{}).NeedLeaderElection(), "metrics adapter server should run on all replicas")
+	assert.True(t, (&APIServiceCABundleSyncer{}).NeedLeaderElection(), "APIService caBundle should be patched by the leader only")
+}
+
+func TestAPIServiceCABundleSyncer(t *testing.T) {
+	ctx := context.Background()
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-ca",
+			Namespace: "kong-system",
+		},
+		Data: map[string][]byte{
+			consts.TLSCRT: []byte("ca-1"),
+		},
+	}
+
+	var patches []string
+	cl := fake.NewClientBuilder().
+		WithObjects(caSecret).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, patch client.Patch, _ ...client.PatchOption) error {
+				assert.Equal(t, Version+"."+GroupName, obj.GetName())
+				data, err := patch.Data(obj)
+				require.NoError(t, err)
+				var p struct {
+					Spec struct {
+						CABundle []byte `json:"caBundle"`
+					} `json:"spec"`
+				}
+				require.NoError(t, json.Unmarshal(data, &p))
+				patches = append(patches, string(p.Spec.CABundle))
+				return nil
+			},
+		}).
+		Build()
+
+	s := &APIServiceCABundleSyncer{
+		Client:     cl,
+		CASecretNN: types.NamespacedName{Name: caSecret.Name, Namespace: caSecret.Namespace},
+		Logger:     logr.Discard(),
+	}
+
+	t.Log("verifying the caBundle is patched with the cluster CA trust bundle")
+	require.NoError(t, s.ensureCABundle(ctx))
+	assert.Equal(t, []string{"ca-1"}, patches)
+
+	t.Log("verifying the caBundle is not patched again when the trust bundle didn't change")
+	require.NoError(t, s.ensureCABundle(ctx))
+	assert.Equal(t, []string{"ca-1"}, patches)
+
+	t.Log("verifying the caBundle follows the rotation of the cluster CA")
+	caSecret.Data[consts.TLSCRT] = []byte("ca-2")
+	require.NoError(t, cl.Update(ctx, caSecret))
+	require.NoError(t, s.ensureCABundle(ctx))
+	assert.Equal(t, []string{"ca-1", "ca-2"}, patches)
+}
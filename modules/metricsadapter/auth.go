@@ -0,0 +1,136 @@
+package metricsadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apiserver/pkg/authentication/authenticatorfactory"
+	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
+	"k8s.io/apiserver/pkg/authorization/authorizerfactory"
+	"k8s.io/apiserver/pkg/endpoints/filters"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/server/dynamiccertificates"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// authenticationConfigMapNamespace and authenticationConfigMapName point to the
+	// ConfigMap in which the Kubernetes API server publishes the configuration
+	// of the front proxy (the aggregation layer) for extension API servers.
+	authenticationConfigMapNamespace = "kube-system"
+	authenticationConfigMapName      = "extension-apiserver-authentication"
+
+	requestHeaderClientCAKey           = "requestheader-client-ca-file"
+	requestHeaderUsernameHeadersKey    = "requestheader-username-headers"
+	requestHeaderUIDHeadersKey         = "requestheader-uid-headers"
+	requestHeaderGroupHeadersKey       = "requestheader-group-headers"
+	requestHeaderExtraHeaderPrefixKey  = "requestheader-extra-headers-prefix"
+	requestHeaderAllowedClientNamesKey = "requestheader-allowed-names"
+
+	// The values below match the defaults of the delegated authentication and
+	// authorization of k8s.io/apiserver.
+	delegatedAuthCacheTTL        = 10 * time.Second
+	delegatedAuthnTimeout        = 10 * time.Second
+	delegatedAuthzAllowCacheTTL  = 10 * time.Second
+	delegatedAuthzDenyCacheTTL   = 10 * time.Second
+	delegatedAuthControllerCount = 1
+)
+
+// withDelegatedAuth wraps the handler so that requests are authenticated and
+// authorized by the Kubernetes API server, the same way the API server's
+// extension API servers do:
+//   - requests proxied by the aggregation layer are authenticated by the front
+//     proxy client certificate, verified against the requestheader client CA,
+//     and the user set in the requestheader headers,
+//   - requests with a bearer token are authenticated with TokenReviews,
+//   - authenticated users are authorized with SubjectAccessReviews.
+//
+// It starts the controllers watching the extension-apiserver-authentication
+// ConfigMap which run until ctx is done.
+func withDelegatedAuth(ctx context.Context, kubeClient kubernetes.Interface, handler http.Handler) (http.Handler, error) {
+	clientCA, err := dynamiccertificates.NewDynamicCAFromConfigMapController(
+		"requestheader-client-ca",
+		authenticationConfigMapNamespace,
+		authenticationConfigMapName,
+		requestHeaderClientCAKey,
+		kubeClient,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating requestheader client CA controller: %w", err)
+	}
+	if err := clientCA.RunOnce(ctx); err != nil {
+		return nil, fmt.Errorf("failed loading requestheader client CA: %w", err)
+	}
+	go clientCA.Run(ctx, delegatedAuthControllerCount)
+
+	headers := headerrequest.NewRequestHeaderAuthRequestController(
+		authenticationConfigMapName,
+		authenticationConfigMapNamespace,
+		kubeClient,
+		requestHeaderUsernameHeadersKey,
+		requestHeaderUIDHeadersKey,
+		requestHeaderGroupHeadersKey,
+		requestHeaderExtraHeaderPrefixKey,
+		requestHeaderAllowedClientNamesKey,
+	)
+	if err := headers.RunOnce(ctx); err != nil {
+		return nil, fmt.Errorf("failed loading requestheader configuration: %w", err)
+	}
+	go headers.Run(ctx, delegatedAuthControllerCount)
+
+	requestHeaderConfig := &authenticatorfactory.RequestHeaderConfig{
+		UsernameHeaders:     headerrequest.StringSliceProviderFunc(headers.UsernameHeaders),
+		UIDHeaders:          headerrequest.StringSliceProviderFunc(headers.UIDHeaders),
+		GroupHeaders:        headerrequest.StringSliceProviderFunc(headers.GroupHeaders),
+		ExtraHeaderPrefixes: headerrequest.StringSliceProviderFunc(headers.ExtraHeaderPrefixes),
+		AllowedClientNames:  headerrequest.StringSliceProviderFunc(headers.AllowedClientNames),
+		CAContentProvider:   clientCA,
+	}
+
+	authenticator, _, err := authenticatorfactory.DelegatingAuthenticatorConfig{
+		TokenAccessReviewClient:  kubeClient.AuthenticationV1(),
+		TokenAccessReviewTimeout: delegatedAuthnTimeout,
+		WebhookRetryBackoff:      delegatedAuthBackoff(),
+		CacheTTL:                 delegatedAuthCacheTTL,
+		RequestHeaderConfig:      requestHeaderConfig,
+	}.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating delegated authenticator: %w", err)
+	}
+
+	authorizer, err := authorizerfactory.DelegatingAuthorizerConfig{
+		SubjectAccessReviewClient: kubeClient.AuthorizationV1(),
+		AllowCacheTTL:             delegatedAuthzAllowCacheTTL,
+		DenyCacheTTL:              delegatedAuthzDenyCacheTTL,
+		WebhookRetryBackoff:       delegatedAuthBackoff(),
+	}.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed creating delegated authorizer: %w", err)
+	}
+
+	codecs := serializer.NewCodecFactory(clientgoscheme.Scheme)
+	handler = filters.WithAuthorization(handler, authorizer, codecs)
+	handler = filters.WithAuthentication(handler, authenticator, filters.Unauthorized(codecs), nil, requestHeaderConfig)
+	handler = filters.WithRequestInfo(handler, &request.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("apis", "api"),
+		GrouplessAPIPrefixes: sets.NewString("api"),
+	})
+	return handler, nil
+}
+
+// delegatedAuthBackoff returns the backoff used when the TokenReviews and
+// SubjectAccessReviews requests fail, the same as k8s.io/apiserver's default.
+func delegatedAuthBackoff() *wait.Backoff {
+	return &wait.Backoff{
+		Duration: 500 * time.Millisecond,
+		Factor:   1.5,
+		Jitter:   0.2,
+		Steps:    5,
+	}
+}
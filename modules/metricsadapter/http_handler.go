@@ -0,0 +1,204 @@
+package metricsadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
+)
+
+const (
+	// GroupName is the group of the custom metrics API.
+	GroupName = "custom.metrics.k8s.io"
+	// Version is the version of the custom metrics API served by the adapter.
+	Version = "v1beta2"
+
+	groupVersion = GroupName + "/" + Version
+)
+
+// HTTPHandler is a handler for the custom metrics API requests.
+// It serves the per Pod metrics of DataPlane Pods so that DataPlanes'
+// HorizontalPodAutoscalers can scale on metrics of type Pods, e.g.
+// kong_requests_per_second.
+type HTTPHandler struct {
+	cl       client.Client
+	logger   logr.Logger
+	mux      *http.ServeMux
+	provider metricsscraper.PodMetricsProvider
+}
+
+// NewHTTPHandler returns a new HTTP Handler to handle custom metrics API requests.
+func NewHTTPHandler(
+	cl client.Client,
+	logger logr.Logger,
+	provider metricsscraper.PodMetricsProvider,
+) *HTTPHandler {
+	h := &HTTPHandler{
+		cl:       cl,
+		logger:   logger,
+		provider: provider,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /apis/"+GroupName, h.handleAPIGroup)
+	mux.HandleFunc("GET /apis/"+groupVersion, h.handleAPIResourceList)
+	mux.HandleFunc("GET /apis/"+groupVersion+"/namespaces/{namespace}/pods/{name}/{metric}", h.handlePodsMetric)
+	h.mux = mux
+
+	return h
+}
+
+// ServeHTTP serves custom metrics API requests.
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *HTTPHandler) handleAPIGroup(rw http.ResponseWriter, _ *http.Request) {
+	gv := metav1.GroupVersionForDiscovery{
+		GroupVersion: groupVersion,
+		Version:      Version,
+	}
+	h.writeJSON(rw, http.StatusOK, metav1.APIGroup{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIGroup",
+			APIVersion: "v1",
+		},
+		Name:             GroupName,
+		Versions:         []metav1.GroupVersionForDiscovery{gv},
+		PreferredVersion: gv,
+	})
+}
+
+func (h *HTTPHandler) handleAPIResourceList(rw http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(rw, http.StatusOK, metav1.APIResourceList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "APIResourceList",
+			APIVersion: "v1",
+		},
+		GroupVersion: groupVersion,
+		APIResources: lo.Map(metricsscraper.PodMetricNames, func(name string, _ int) metav1.APIResource {
+			return metav1.APIResource{
+				Name:       "pods/" + name,
+				Namespaced: true,
+				Kind:       "MetricValueList",
+				Verbs:      metav1.Verbs{"get"},
+			}
+		}),
+	})
+}
+
+// handlePodsMetric serves the value of a metric for a single Pod or,
+// when the name is "*", for all the Pods matching the labelSelector.
+func (h *HTTPHandler) handlePodsMetric(rw http.ResponseWriter, r *http.Request) {
+	var (
+		namespace = r.PathValue("namespace")
+		name      = r.PathValue("name")
+		metric    = r.PathValue("metric")
+	)
+	if !slices.Contains(metricsscraper.PodMetricNames, metric) {
+		h.writeStatus(rw, http.StatusNotFound, fmt.Sprintf("metric %s is not supported", metric))
+		return
+	}
+
+	var pods []corev1.Pod
+	if name == "*" {
+		selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+		if err != nil {
+			h.writeStatus(rw, http.StatusBadRequest, fmt.Sprintf("invalid labelSelector: %v", err))
+			return
+		}
+		var podList corev1.PodList
+		if err := h.cl.List(r.Context(), &podList,
+			client.InNamespace(namespace),
+			client.MatchingLabelsSelector{Selector: selector},
+		); err != nil {
+			h.logger.Error(err, "failed to list Pods", "namespace", namespace)
+			h.writeStatus(rw, http.StatusInternalServerError, "failed to list Pods")
+			return
+		}
+		pods = podList.Items
+	} else {
+		var pod corev1.Pod
+		if err := h.cl.Get(r.Context(), types.NamespacedName{Namespace: namespace, Name: name}, &pod); err != nil {
+			if k8serrors.IsNotFound(err) {
+				h.writeStatus(rw, http.StatusNotFound, fmt.Sprintf("Pod %s/%s not found", namespace, name))
+				return
+			}
+			h.logger.Error(err, "failed to get Pod", "namespace", namespace, "name", name)
+			h.writeStatus(rw, http.StatusInternalServerError, "failed to get Pod")
+			return
+		}
+		pods = []corev1.Pod{pod}
+	}
+
+	list := MetricValueList{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MetricValueList",
+			APIVersion: groupVersion,
+		},
+		Items: []MetricValue{},
+	}
+	for _, pod := range pods {
+		podMetrics, ok := h.provider.PodMetrics(client.ObjectKeyFromObject(&pod))
+		if !ok {
+			continue
+		}
+		value, ok := podMetrics.Values[metric]
+		if !ok {
+			continue
+		}
+		list.Items = append(list.Items, MetricValue{
+			DescribedObject: corev1.ObjectReference{
+				Kind:       "Pod",
+				APIVersion: "v1",
+				Namespace:  pod.Namespace,
+				Name:       pod.Name,
+			},
+			Metric: MetricIdentifier{
+				Name: metric,
+			},
+			Timestamp:     metav1.NewTime(podMetrics.Timestamp),
+			WindowSeconds: lo.ToPtr(int64(podMetrics.Window.Seconds())),
+			Value:         *resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI),
+		})
+	}
+
+	if name != "*" && len(list.Items) == 0 {
+		h.writeStatus(rw, http.StatusNotFound, fmt.Sprintf("metric %s for Pod %s/%s not found", metric, namespace, name))
+		return
+	}
+	h.writeJSON(rw, http.StatusOK, list)
+}
+
+func (h *HTTPHandler) writeStatus(rw http.ResponseWriter, code int, message string) {
+	h.writeJSON(rw, code, metav1.Status{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Status",
+			APIVersion: "v1",
+		},
+		Status:  metav1.StatusFailure,
+		Message: message,
+		Code:    int32(code),
+	})
+}
+
+func (h *HTTPHandler) writeJSON(rw http.ResponseWriter, code int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		h.logger.Error(err, "failed to write response")
+	}
+}
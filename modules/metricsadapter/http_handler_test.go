@@ -0,0 +1,123 @@
+package metricsadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kong-operator/controller/controlplane_extensions/metricsscraper"
+	"github.com/kong/kong-operator/modules/manager/scheme"
+)
+
+type mockPodMetricsProvider map[types.NamespacedName]metricsscraper.PodMetrics
+
+func (m mockPodMetricsProvider) PodMetrics(pod types.NamespacedName) (metricsscraper.PodMetrics, bool) {
+	pm, ok := m[pod]
+	return pm, ok
+}
+
+func TestHTTPHandler(t *testing.T) {
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    labels,
+			},
+		}
+	}
+	timestamp := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := mockPodMetricsProvider{
+		{Namespace: "default", Name: "dp-1"}: {
+			Timestamp: timestamp,
+			Window:    10 * time.Second,
+			Values: map[string]float64{
+				metricsscraper.PodMetricNameKongRequestsPerSecond: 12.5,
+				metricsscraper.PodMetricNameKongUpstreamLatencyMs: 3,
+			},
+		},
+		{Namespace: "default", Name: "other"}: {
+			Timestamp: timestamp,
+			Window:    10 * time.Second,
+			Values: map[string]float64{
+				metricsscraper.PodMetricNameKongRequestsPerSecond: 1,
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().
+		WithScheme(scheme.Get()).
+		WithObjects(
+			pod("dp-1", map[string]string{"app": "dp"}),
+			pod("dp-2", map[string]string{"app": "dp"}),
+			pod("other", map[string]string{"app": "other"}),
+		).
+		Build()
+	handler := NewHTTPHandler(cl, logr.Discard(), provider)
+
+	get := func(t *testing.T, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("discovery", func(t *testing.T) {
+		rec := get(t, "/apis/custom.metrics.k8s.io/v1beta2")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var list metav1.APIResourceList
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		assert.Equal(t, "custom.metrics.k8s.io/v1beta2", list.GroupVersion)
+		assert.ElementsMatch(t,
+			[]string{"pods/kong_requests_per_second", "pods/kong_upstream_latency_ms"},
+			lo.Map(list.APIResources, func(r metav1.APIResource, _ int) string { return r.Name }),
+		)
+	})
+
+	t.Run("Pods matching the label selector", func(t *testing.T) {
+		rec := get(t, "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/*/kong_requests_per_second?labelSelector=app%3Ddp")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var list MetricValueList
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		// Pods without metrics are not listed.
+		require.Len(t, list.Items, 1)
+		item := list.Items[0]
+		assert.Equal(t, "dp-1", item.DescribedObject.Name)
+		assert.Equal(t, "kong_requests_per_second", item.Metric.Name)
+		assert.Equal(t, "12500m", item.Value.String())
+		assert.Equal(t, int64(10), lo.FromPtr(item.WindowSeconds))
+		assert.True(t, timestamp.Equal(item.Timestamp.Time))
+	})
+
+	t.Run("single Pod", func(t *testing.T) {
+		rec := get(t, "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/dp-1/kong_upstream_latency_ms")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var list MetricValueList
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "3", list.Items[0].Value.String())
+	})
+
+	t.Run("single Pod without metrics", func(t *testing.T) {
+		rec := get(t, "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/dp-2/kong_requests_per_second")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("unsupported metric", func(t *testing.T) {
+		rec := get(t, "/apis/custom.metrics.k8s.io/v1beta2/namespaces/default/pods/*/cpu")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
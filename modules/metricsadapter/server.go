@@ -0,0 +1,112 @@
+package metricsadapter
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+)
+
+// Server serves the custom metrics API to the Kubernetes API aggregation layer.
+//
+// Requests are authenticated and authorized by delegating to the Kubernetes API
+// server and the server serves a certificate signed by the cluster CA, which the
+// APIService registering the adapter trusts through its caBundle.
+//
+// The server runs on every replica so that the adapter Service can reach any of them,
+// while the caBundle of the APIService is kept in sync by APIServiceCABundleSyncer
+// on the leader only.
+type Server struct {
+	Addr    string
+	Handler *HTTPHandler
+
+	// Client is used to read the cluster CA Secret.
+	Client client.Client
+	// KubeClient is used for TokenReviews, SubjectAccessReviews and to watch
+	// the extension-apiserver-authentication ConfigMap.
+	KubeClient kubernetes.Interface
+	// CASecretNN is the cluster CA Secret signing the serving certificate.
+	CASecretNN types.NamespacedName
+	// CAKeyConfig is the key configuration of the cluster CA.
+	CAKeyConfig secrets.KeyConfig
+	// ServiceNN is the Service through which the Kubernetes API server reaches the adapter.
+	ServiceNN types.NamespacedName
+
+	Logger logr.Logger
+
+	certLock sync.RWMutex
+	cert     servingCert
+}
+
+var (
+	_ manager.Runnable               = &Server{}
+	_ manager.LeaderElectionRunnable = &Server{}
+)
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface: the server
+// runs on all replicas, not only on the leader.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements the Start method of manager.Runnable interface to add to the manager.
+// It starts up the HTTPS server and blocks until ctx expires.
+func (s *Server) Start(ctx context.Context) error {
+	if err := s.ensureServingCert(ctx); err != nil {
+		return fmt.Errorf("failed issuing metrics adapter serving certificate: %w", err)
+	}
+	handler, err := withDelegatedAuth(ctx, s.KubeClient, s.Handler)
+	if err != nil {
+		return fmt.Errorf("failed setting up metrics adapter authentication: %w", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              s.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			GetCertificate: s.getCertificate,
+			// The client certificate of the aggregation layer is verified by the
+			// requestheader authenticator against the requestheader client CA.
+			ClientAuth: tls.RequestClientCert,
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	go func() {
+		if err := httpServer.ListenAndServeTLS("", ""); err != nil {
+			if !errors.Is(err, http.ErrServerClosed) {
+				s.Logger.Error(err, "Could not start metrics adapter server")
+			}
+		}
+	}()
+
+	s.Logger.Info("Metrics adapter server is starting to listen", "addr", s.Addr)
+
+	ticker := time.NewTicker(servingCertRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.Logger.Info("Shutting down metrics adapter server")
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return httpServer.Shutdown(ctx) //nolint:contextcheck
+		case <-ticker.C:
+			if err := s.ensureServingCert(ctx); err != nil {
+				s.Logger.Error(err, "Failed refreshing metrics adapter serving certificate")
+			}
+		}
+	}
+}
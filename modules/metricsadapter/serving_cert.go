@@ -0,0 +1,163 @@
+package metricsadapter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+const (
+	// servingCertLifetime is the lifetime of the serving certificate of the
+	// metrics adapter. It's capped by the expiry of the cluster CA.
+	servingCertLifetime = 365 * 24 * time.Hour
+	// servingCertRenewBefore is how long before its expiry the serving
+	// certificate is re-issued.
+	servingCertRenewBefore = 30 * 24 * time.Hour
+	// servingCertRefreshInterval is the interval at which the cluster CA is
+	// checked for changes so that the serving certificate and the APIService
+	// caBundle follow the rotation of the cluster CA.
+	servingCertRefreshInterval = time.Minute
+)
+
+// servingCert is the serving certificate of the metrics adapter together with
+// the checksum of the cluster CA trust bundle it has been issued for.
+type servingCert struct {
+	cert             *tls.Certificate
+	notAfter         time.Time
+	caBundleChecksum string
+}
+
+func (c servingCert) needsRenewal(caBundleChecksum string, now time.Time) bool {
+	return c.cert == nil ||
+		c.caBundleChecksum != caBundleChecksum ||
+		now.After(c.notAfter.Add(-servingCertRenewBefore))
+}
+
+// ensureServingCert makes sure the metrics adapter serves a certificate signed
+// by the cluster CA.
+func (s *Server) ensureServingCert(ctx context.Context) error {
+	var caSecret corev1.Secret
+	if err := s.Client.Get(ctx, s.CASecretNN, &caSecret); err != nil {
+		return fmt.Errorf("failed getting cluster CA Secret %s: %w", s.CASecretNN, err)
+	}
+	caBundle := secrets.CATrustBundle(&caSecret)
+	checksum := secrets.CATrustBundleChecksum(caBundle)
+
+	s.certLock.RLock()
+	current := s.cert
+	s.certLock.RUnlock()
+	if !current.needsRenewal(checksum, time.Now()) {
+		return nil
+	}
+
+	cert, notAfter, err := issueServingCert(&caSecret, s.CAKeyConfig, s.dnsNames())
+	if err != nil {
+		return err
+	}
+	s.certLock.Lock()
+	s.cert = servingCert{
+		cert:             cert,
+		notAfter:         notAfter,
+		caBundleChecksum: checksum,
+	}
+	s.certLock.Unlock()
+
+	s.Logger.Info("Metrics adapter serving certificate issued", "notAfter", notAfter)
+	return nil
+}
+
+// getCertificate returns the current serving certificate for TLS handshakes.
+func (s *Server) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.certLock.RLock()
+	defer s.certLock.RUnlock()
+	if s.cert.cert == nil {
+		return nil, fmt.Errorf("metrics adapter serving certificate has not been issued yet")
+	}
+	return s.cert.cert, nil
+}
+
+// dnsNames returns the DNS names under which the Kubernetes API server
+// reaches the metrics adapter through its Service.
+func (s *Server) dnsNames() []string {
+	name, namespace := s.ServiceNN.Name, s.ServiceNN.Namespace
+	return []string{
+		name,
+		name + "." + namespace,
+		name + "." + namespace + ".svc",
+		name + "." + namespace + ".svc.cluster.local",
+	}
+}
+
+// issueServingCert issues a serving certificate for the provided DNS names
+// signed by the cluster CA stored in the provided Secret.
+func issueServingCert(
+	caSecret *corev1.Secret,
+	keyConfig secrets.KeyConfig,
+	dnsNames []string,
+) (*tls.Certificate, time.Time, error) {
+	caCertBlock, _ := pem.Decode(caSecret.Data[consts.TLSCRT])
+	if caCertBlock == nil {
+		return nil, time.Time{}, fmt.Errorf("failed decoding %q data from secret %s", consts.TLSCRT, caSecret.Name)
+	}
+	caCert, err := x509.ParseCertificate(caCertBlock.Bytes)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	caKeyBlock, _ := pem.Decode(caSecret.Data[consts.TLSKey])
+	if caKeyBlock == nil {
+		return nil, time.Time{}, fmt.Errorf("failed decoding %q data from secret %s", consts.TLSKey, caSecret.Name)
+	}
+	caKey, err := secrets.ParseKey(keyConfig.Type, caKeyBlock)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	key, _, signatureAlgorithm, err := secrets.CreatePrivateKey(keyConfig)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	notAfter := now.Add(servingCertLifetime)
+	if caCert.NotAfter.Before(notAfter) {
+		notAfter = caCert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   dnsNames[0],
+			Organization: []string{"Kong, Inc."},
+			Country:      []string{"US"},
+		},
+		DNSNames:           dnsNames,
+		NotBefore:          now.Add(-time.Minute),
+		NotAfter:           notAfter,
+		KeyUsage:           x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		SignatureAlgorithm: signatureAlgorithm,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed signing metrics adapter serving certificate: %w", err)
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der, caCert.Raw},
+		PrivateKey:  key,
+	}, notAfter, nil
+}
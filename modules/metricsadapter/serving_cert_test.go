@@ -0,0 +1,133 @@
+package metricsadapter
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kong-operator/controller/pkg/secrets"
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestIssueServingCert(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Kong Operator CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	require.NoError(t, err)
+	caKeyDER, err := x509.MarshalECPrivateKey(caKey)
+	require.NoError(t, err)
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-ca",
+			Namespace: "kong-system",
+		},
+		Data: map[string][]byte{
+			consts.TLSCRT: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}),
+			consts.TLSKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}),
+		},
+	}
+
+	s := &Server{ServiceNN: types.NamespacedName{Name: "adapter", Namespace: "kong-system"}}
+	dnsNames := s.dnsNames()
+	assert.Equal(t, []string{
+		"adapter",
+		"adapter.kong-system",
+		"adapter.kong-system.svc",
+		"adapter.kong-system.svc.cluster.local",
+	}, dnsNames)
+
+	tlsCert, notAfter, err := issueServingCert(caSecret, secrets.KeyConfig{Type: x509.ECDSA}, dnsNames)
+	require.NoError(t, err)
+	require.Len(t, tlsCert.Certificate, 2)
+	cert, err := x509.ParseCertificate(tlsCert.Certificate[0])
+	require.NoError(t, err)
+
+	t.Log("verifying the serving certificate is signed by the cluster CA and valid for the Service")
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(caCert)
+	_, err = cert.Verify(x509.VerifyOptions{
+		DNSName:   "adapter.kong-system.svc",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	require.NoError(t, err)
+
+	t.Log("verifying the serving certificate doesn't outlive the cluster CA")
+	assert.Equal(t, caCert.NotAfter, notAfter)
+	assert.Equal(t, caCert.NotAfter, cert.NotAfter)
+}
+
+func TestServingCertNeedsRenewal(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name     string
+		cert     servingCert
+		checksum string
+		expected bool
+	}{
+		{
+			name:     "not issued",
+			checksum: "a",
+			expected: true,
+		},
+		{
+			name: "up to date",
+			cert: servingCert{
+				cert:             &tls.Certificate{},
+				notAfter:         now.Add(servingCertLifetime),
+				caBundleChecksum: "a",
+			},
+			checksum: "a",
+		},
+		{
+			name: "cluster CA changed",
+			cert: servingCert{
+				cert:             &tls.Certificate{},
+				notAfter:         now.Add(servingCertLifetime),
+				caBundleChecksum: "a",
+			},
+			checksum: "b",
+			expected: true,
+		},
+		{
+			name: "about to expire",
+			cert: servingCert{
+				cert:             &tls.Certificate{},
+				notAfter:         now.Add(servingCertRenewBefore / 2),
+				caBundleChecksum: "a",
+			},
+			checksum: "a",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.cert.needsRenewal(tc.checksum, now))
+		})
+	}
+}
@@ -9,3 +9,79 @@ const (
 	// ControlPlane's ValidatingWebhookConfiguration.
 	AnnotationSpecHash = "gateway-operator.konghq.com/spec-hash"
 )
+
+const (
+	// DataPlaneMetricsExtensionExportedMetricsAnnotation is the annotation set on
+	// DataPlaneMetricsExtensions to configure which Kong Prometheus metric families
+	// scraped from the DataPlane are exported, enriched with Kubernetes metadata,
+	// by the operator.
+	// The value of such an annotation is to be intended as a comma-separated list of
+	// metric family names.
+	//
+	// Example:
+	// gateway-operator.konghq.com/exported-metrics: "kong_http_requests_total,kong_upstream_latency_ms"
+	DataPlaneMetricsExtensionExportedMetricsAnnotation = OperatorAnnotationPrefix + "exported-metrics"
+)
+
+const (
+	// DataPlaneMetricsExtensionOTLPEndpointAnnotation is the annotation set on
+	// DataPlaneMetricsExtensions to push the exported metrics to an OpenTelemetry
+	// collector using OTLP.
+	// For the grpc protocol its value is the collector's host:port, for the
+	// http/protobuf protocol it is the collector's URL. When the URL has no path,
+	// /v1/metrics is used.
+	//
+	// Example:
+	// gateway-operator.konghq.com/otlp-endpoint: "otel-collector.observability.svc:4317"
+	DataPlaneMetricsExtensionOTLPEndpointAnnotation = OperatorAnnotationPrefix + "otlp-endpoint"
+	// DataPlaneMetricsExtensionOTLPProtocolAnnotation is the annotation set on
+	// DataPlaneMetricsExtensions to configure the OTLP protocol used to push metrics.
+	// Supported values are "grpc" (the default) and "http/protobuf".
+	DataPlaneMetricsExtensionOTLPProtocolAnnotation = OperatorAnnotationPrefix + "otlp-protocol"
+	// DataPlaneMetricsExtensionOTLPInsecureAnnotation is the annotation set on
+	// DataPlaneMetricsExtensions to disable TLS when pushing metrics using the
+	// grpc protocol. For the http/protobuf protocol the endpoint's scheme decides.
+	DataPlaneMetricsExtensionOTLPInsecureAnnotation = OperatorAnnotationPrefix + "otlp-insecure"
+	// DataPlaneMetricsExtensionOTLPResourceAttributesAnnotation is the annotation
+	// set on DataPlaneMetricsExtensions to add resource attributes to the metrics
+	// pushed using OTLP.
+	// The value of such an annotation is to be intended as a comma-separated list of
+	// key=value pairs.
+	//
+	// Example:
+	// gateway-operator.konghq.com/otlp-resource-attributes: "k8s.cluster.name=prod-eu,deployment.environment=prod"
+	DataPlaneMetricsExtensionOTLPResourceAttributesAnnotation = OperatorAnnotationPrefix + "otlp-resource-attributes"
+)
+
+const (
+	// ClusterCARotationPhaseAnnotation is the annotation set on the cluster CA Secret
+	// to request the rotation of the cluster CA and to track the phase of an ongoing rotation.
+	// Setting it to "Requested" starts the rotation. It's removed when the rotation is completed.
+	//
+	// Example:
+	// gateway-operator.konghq.com/ca-rotation-phase: "Requested"
+	ClusterCARotationPhaseAnnotation = OperatorAnnotationPrefix + "ca-rotation-phase"
+	// ClusterCARotationProgressAnnotation is the annotation set on the cluster CA Secret
+	// during its rotation with the number of certificates and DataPlane Deployments
+	// which are in sync with the current phase of the rotation, e.g. "3/5".
+	ClusterCARotationProgressAnnotation = OperatorAnnotationPrefix + "ca-rotation-progress"
+	// ClusterCARotationPhaseStartedAnnotation is the annotation set on the cluster CA Secret
+	// during its rotation with the time the current phase started at, in RFC 3339 format.
+	ClusterCARotationPhaseStartedAnnotation = OperatorAnnotationPrefix + "ca-rotation-phase-started"
+	// ClusterCARotationPendingDataPlanesAnnotation is the annotation set on the cluster CA Secret
+	// during its rotation with the comma separated namespaced names of the DataPlanes which
+	// Deployments are not yet in sync with the current phase of the rotation.
+	ClusterCARotationPendingDataPlanesAnnotation = OperatorAnnotationPrefix + "ca-rotation-pending-dataplanes"
+)
+
+const (
+	// CertificateSigningRequestAnnotation is the annotation set on certificate Secrets with the name
+	// of the pending CertificateSigningRequest issuing their certificate, when certificates are
+	// issued through CertificateSigningRequests.
+	CertificateSigningRequestAnnotation = OperatorAnnotationPrefix + "certificate-signing-request"
+	// CertificateSigningRequestFailedAnnotation is the annotation set on certificate Secrets when the
+	// CertificateSigningRequest issuing their certificate was denied or failed, with a hash of the request.
+	// No new request is created until the request changes (e.g. its subject or signer) or the annotation
+	// is removed.
+	CertificateSigningRequestFailedAnnotation = OperatorAnnotationPrefix + "certificate-signing-request-failed"
+)
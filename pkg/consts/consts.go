@@ -55,6 +55,14 @@ const (
 	// the gateway controller.
 	GatewayManagedLabelValue = "gateway"
 
+	// HTTPRouteManagedByLabelValue indicates that the object's lifecycle is managed by
+	// the full-hybrid HTTPRoute controller.
+	HTTPRouteManagedByLabelValue = "httproute"
+
+	// ServiceManagedByLabelValue indicates that the object's lifecycle is managed by
+	// the full-hybrid Service controller.
+	ServiceManagedByLabelValue = "service"
+
 	// ServiceSecretLabel is a label that is added to operator related Service
 	// Secrets to designate which Service this particular Secret it used by.
 	ServiceSecretLabel = OperatorLabelPrefix + "service-secret"
@@ -19,6 +19,12 @@ const (
 	// ControlPlaneManagedLabelValue indicates that an object's lifecycle is managed
 	// by the controlplane controller.
 	ControlPlaneManagedLabelValue = "controlplane"
+
+	// ControlPlaneLastValidConfigSecretAnnotation is the annotation of a ControlPlane naming the Secret,
+	// in the ControlPlane's namespace, the last valid configuration is persisted in. It's restored
+	// when the ControlPlane restarts so configuration rejections can be recovered from even when
+	// broken objects are present in the cluster at that time.
+	ControlPlaneLastValidConfigSecretAnnotation = OperatorAnnotationPrefix + "last-valid-config-secret"
 )
 
 // -----------------------------------------------------------------------------
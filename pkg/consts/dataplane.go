@@ -36,6 +36,17 @@ const (
 	// shall be removed. This guarantees no interference with annotations from other sources (e.g. users).
 	AnnotationLastAppliedAnnotations = "gateway-operator.konghq.com/last-applied-annotations"
 
+	// DataPlaneClusterCertificateSerialAnnotation is the annotation set on the DataPlane Deployment
+	// with the serial number of the DataPlane's Admin API certificate. Kong doesn't reload certificates
+	// from disk, so it's also set on the Pod template when the certificate is renewed to roll the new
+	// certificate out to the Pods.
+	DataPlaneClusterCertificateSerialAnnotation = OperatorAnnotationPrefix + "cluster-certificate-serial"
+
+	// DataPlaneClusterCAChecksumAnnotation is the annotation set on the DataPlane Pod template
+	// with the checksum of the CA certificates trusted by the DataPlane's Admin API. Changing it
+	// when the cluster CA is rotated rolls the new trust bundle out to the Pods.
+	DataPlaneClusterCAChecksumAnnotation = OperatorAnnotationPrefix + "cluster-ca-checksum"
+
 	// DataPlanePodStateLabel indicates the state of a DataPlane Pod.
 	// Useful for progressive rollouts.
 	DataPlanePodStateLabel = "gateway-operator.konghq.com/dataplane-pod-state"
@@ -54,6 +65,13 @@ const (
 	// - the "live" Deployment wraps the "live" DataPlane Pods.
 	DataPlaneStateLabelValueLive = "live"
 
+	// DataPlaneStateLabelValueRetired indicates that a DataPlane resource is
+	// a "retired" resource.
+	// This is used in:
+	// - the "retired" Deployments which were previously "live", are scaled to 0
+	//   and retained to allow rolling back a promoted BlueGreen rollout.
+	DataPlaneStateLabelValueRetired = "retired"
+
 	// DataPlaneAdminServiceLabelValue indicates that the service is intended to expose the
 	// DataPlane admin API.
 	DataPlaneAdminServiceLabelValue ServiceType = "admin"
@@ -179,6 +197,87 @@ const (
 	EnvVarKongDatabase = "KONG_DATABASE"
 )
 
+// -----------------------------------------------------------------------------
+// Consts - DataPlane BlueGreen promotion analysis
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlanePromotionAnalysisAnnotation enables automated, metrics-driven
+	// analysis of the "preview" Deployment before it gets promoted in a BlueGreen
+	// rollout. When set to "true" the operator compares the preview Pods' error
+	// rate and upstream latency against the live Pods over the analysis window
+	// and either proceeds with the promotion or aborts it. The preview Pods are
+	// analyzed while they receive a share of the traffic, at the last step of
+	// DataPlaneRolloutTrafficStepsAnnotation below 100% or at an added 10% step.
+	DataPlanePromotionAnalysisAnnotation = OperatorAnnotationPrefix + "promotion-analysis"
+
+	// DataPlanePromotionAnalysisWindowAnnotation sets the duration (e.g. "5m")
+	// over which the metrics are collected before the promotion decision is made.
+	DataPlanePromotionAnalysisWindowAnnotation = OperatorAnnotationPrefix + "promotion-analysis-window"
+
+	// DataPlanePromotionAnalysisMinRequestsAnnotation sets the minimum number of
+	// requests that the preview Pods have to serve during the analysis window
+	// for the analysis to be conclusive.
+	DataPlanePromotionAnalysisMinRequestsAnnotation = OperatorAnnotationPrefix + "promotion-analysis-min-requests"
+
+	// DataPlanePromotionAnalysisMaxErrorRateIncreaseAnnotation sets the maximum
+	// allowed increase of the 5xx error rate (expressed as a fraction, e.g. "0.01"
+	// for 1 percentage point) of the preview Pods compared to the live Pods.
+	DataPlanePromotionAnalysisMaxErrorRateIncreaseAnnotation = OperatorAnnotationPrefix + "promotion-analysis-max-error-rate-increase"
+
+	// DataPlanePromotionAnalysisMaxLatencyRatioAnnotation sets the maximum allowed
+	// ratio of the preview Pods' mean kong_upstream_latency_ms to the live Pods'
+	// one (e.g. "1.2" allows the preview to be 20% slower).
+	DataPlanePromotionAnalysisMaxLatencyRatioAnnotation = OperatorAnnotationPrefix + "promotion-analysis-max-latency-ratio"
+)
+
+// -----------------------------------------------------------------------------
+// Consts - DataPlane BlueGreen progressive traffic shifting
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneRolloutTrafficStepsAnnotation enables progressive traffic shifting
+	// between the live and preview Deployments of a DataPlane using the BlueGreen
+	// rollout strategy. The value is a comma-separated list of increasing traffic
+	// percentages (e.g. "10,25,50,100") that the preview Pods should receive
+	// before the promotion. Traffic is shifted by scaling the replicas of live and
+	// preview Deployments behind the shared live ingress Service.
+	DataPlaneRolloutTrafficStepsAnnotation = OperatorAnnotationPrefix + "rollout-traffic-steps"
+
+	// DataPlaneRolloutTrafficStepPauseAnnotation sets the duration (e.g. "2m")
+	// for which each traffic step is held before advancing to the next one.
+	DataPlaneRolloutTrafficStepPauseAnnotation = OperatorAnnotationPrefix + "rollout-traffic-step-pause"
+
+	// DataPlaneRolloutPausedAnnotation pauses progressive traffic shifting at the
+	// current step when set to "true".
+	DataPlaneRolloutPausedAnnotation = OperatorAnnotationPrefix + "rollout-paused"
+
+	// DataPlaneRolloutTrafficShiftingStateAnnotation is set by the operator on
+	// the preview Deployment to keep track of progressive traffic shifting.
+	DataPlaneRolloutTrafficShiftingStateAnnotation = OperatorAnnotationPrefix + "rollout-traffic-shifting-state"
+)
+
+// -----------------------------------------------------------------------------
+// Consts - DataPlane BlueGreen revision history and rollback
+// -----------------------------------------------------------------------------
+
+const (
+	// DataPlaneRolloutRevisionHistoryLimitAnnotation sets the number of previously
+	// live Deployments which are retained (scaled to 0) after a BlueGreen promotion
+	// so that the DataPlane can be rolled back to them.
+	// When unset or set to "0" the previously live Deployments are deleted.
+	DataPlaneRolloutRevisionHistoryLimitAnnotation = OperatorAnnotationPrefix + "rollout-revision-history-limit"
+
+	// DataPlaneRollbackAnnotation requests a rollback of the DataPlane to the most
+	// recently retired revision when set to "true". The operator removes the
+	// annotation once the rollback is done.
+	DataPlaneRollbackAnnotation = OperatorAnnotationPrefix + "rollback"
+
+	// DataPlaneDeploymentRetiredAtAnnotation is set by the operator on retired
+	// Deployments and contains the RFC 3339 timestamp of their retirement.
+	DataPlaneDeploymentRetiredAtAnnotation = OperatorAnnotationPrefix + "retired-at"
+)
+
 // -----------------------------------------------------------------------------
 // Consts - DataPlane Finalizers
 // -----------------------------------------------------------------------------
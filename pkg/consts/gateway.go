@@ -0,0 +1,48 @@
+package consts
+
+// -----------------------------------------------------------------------------
+// Consts - Gateway DataPlane NetworkPolicy
+// -----------------------------------------------------------------------------
+
+const (
+	// GatewayConfigurationNetworkPolicyKindAnnotation is the annotation set on
+	// GatewayConfigurations to select the kind of the policy generated for the
+	// DataPlanes of Gateways using them.
+	// Possible values are "NetworkPolicy" (default) and "CiliumNetworkPolicy".
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-kind: "CiliumNetworkPolicy"
+	GatewayConfigurationNetworkPolicyKindAnnotation = OperatorAnnotationPrefix + "network-policy-kind"
+
+	// GatewayConfigurationNetworkPolicyRulesAnnotation is the annotation set on
+	// GatewayConfigurations with additional rules of the policy generated for the
+	// DataPlanes of Gateways using them.
+	// The value is a JSON object with "ingress" and "egress" lists of
+	// NetworkPolicy rules. When egress rules are set, the DataPlane's egress is
+	// restricted to them and to DNS.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-rules: '{"egress":[{"to":[{"namespaceSelector":{"matchLabels":{"team":"payments"}}}]}]}'
+	GatewayConfigurationNetworkPolicyRulesAnnotation = OperatorAnnotationPrefix + "network-policy-rules"
+
+	// GatewayConfigurationNetworkPolicyMetricsNamespacesAnnotation is the annotation
+	// set on GatewayConfigurations to restrict the access to the DataPlane's metrics
+	// port to the comma-separated list of namespaces (e.g. the ones running Prometheus)
+	// and to the operator itself. The metrics port is open to all sources when it's not set.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-metrics-namespaces: "monitoring"
+	GatewayConfigurationNetworkPolicyMetricsNamespacesAnnotation = OperatorAnnotationPrefix + "network-policy-metrics-namespaces"
+
+	// GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation is the annotation set on
+	// GatewayConfigurations to restrict the egress of the DataPlanes of Gateways using them
+	// to the backends of the routes attached to the Gateways, to DNS and to the egress rules
+	// set through GatewayConfigurationNetworkPolicyRulesAnnotation.
+	// The backends are the pods selected by the Services referenced by the routes' backendRefs,
+	// on the target ports of the referenced Service ports, or the endpoints of the Services
+	// without a selector. Konnect is also reachable when the DataPlane is connected to it.
+	//
+	// Example:
+	// gateway-operator.konghq.com/network-policy-egress-from-routes: "true"
+	GatewayConfigurationNetworkPolicyEgressFromRoutesAnnotation = OperatorAnnotationPrefix + "network-policy-egress-from-routes"
+)
@@ -12,4 +12,98 @@ const (
 	// AnnotationKongPluginInstallationGenerationInternal is the annotation key used to store KongPluginInstallation
 	// and its generation, internal usage to re-trigger deployment when KongPluginInstallation changes.
 	AnnotationKongPluginInstallationGenerationInternal = OperatorLabelPrefix + "kong-plugin-installation-generation"
+
+	// AnnotationKongPluginInstallationImageDigestsInternal is the annotation key used to store KongPluginInstallation
+	// and the digest of its image, internal usage to re-trigger deployment when the image behind a tag changes.
+	AnnotationKongPluginInstallationImageDigestsInternal = OperatorLabelPrefix + "kong-plugin-installation-image-digests"
+)
+
+const (
+	// AnnotationKongPluginInstallationImageDigest is the annotation key used to store, on the ConfigMap
+	// holding a plugin, the digest of the image manifest the plugin has been fetched from.
+	AnnotationKongPluginInstallationImageDigest = OperatorAnnotationPrefix + "plugin-image-digest"
+
+	// AnnotationKongPluginInstallationImageReference is the annotation key used to store, on the ConfigMap
+	// of a KongPluginInstallation delivered with KongPluginInstallationDeliveryImageVolume, the reference
+	// of the plugin image pinned by digest that DataPlanes mount as a volume.
+	AnnotationKongPluginInstallationImageReference = OperatorAnnotationPrefix + "plugin-image-reference"
+
+	// AnnotationKongPluginInstallationSourceHash is the annotation key used to store, on the ConfigMap
+	// holding a plugin, the hash of the inputs the plugin has been fetched with (the KongPluginInstallation's
+	// generation and annotations and the Secrets it references).
+	AnnotationKongPluginInstallationSourceHash = OperatorAnnotationPrefix + "plugin-source-hash"
+
+	// KongPluginInstallationPollIntervalAnnotation is the annotation set on KongPluginInstallations
+	// to periodically resolve the plugin image (e.g. a mutable tag like :stable) to its digest.
+	// The plugin is fetched again, and DataPlanes using it are rolled out, only when the digest changes.
+	// Its value is a duration, not shorter than 30s.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-image-poll-interval: "5m"
+	KongPluginInstallationPollIntervalAnnotation = OperatorAnnotationPrefix + "plugin-image-poll-interval"
+
+	// KongPluginInstallationDeliveryAnnotation is the annotation set on KongPluginInstallations
+	// to configure how the plugin is delivered to DataPlanes. Supported values are
+	// KongPluginInstallationDeliveryConfigMap (default) and KongPluginInstallationDeliveryImageVolume.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-delivery: "image-volume"
+	KongPluginInstallationDeliveryAnnotation = OperatorAnnotationPrefix + "plugin-delivery"
+
+	// KongPluginInstallationRequireDigestAnnotation is the annotation set on KongPluginInstallations
+	// to require the plugin image to be pinned by digest, e.g. registry.example.com/plugin@sha256:<hex>.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-image-require-digest: "true"
+	KongPluginInstallationRequireDigestAnnotation = OperatorAnnotationPrefix + "plugin-image-require-digest"
+
+	// KongPluginInstallationCosignSecretAnnotation is the annotation set on KongPluginInstallations
+	// to verify the cosign signature of the plugin image before the plugin is installed.
+	// Its value is the name of a Secret, in the KongPluginInstallation's namespace, holding
+	// the trusted public keys under the KongPluginInstallationCosignPublicKeySecretKey key and,
+	// for keyless signatures, the Fulcio root certificates and the Rekor public keys under
+	// the KongPluginInstallationCosignFulcioRootsSecretKey and KongPluginInstallationCosignRekorPublicKeySecretKey keys.
+	// It's ignored when the cosign keys and roots are configured at the operator level.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-image-cosign-secret: "plugin-signing-keys"
+	KongPluginInstallationCosignSecretAnnotation = OperatorAnnotationPrefix + "plugin-image-cosign-secret"
+
+	// KongPluginInstallationCosignIdentityAnnotation is the annotation set on KongPluginInstallations
+	// to accept keyless signatures made with a certificate issued for the provided identity
+	// (an email address or a URI). It requires KongPluginInstallationCosignIssuerAnnotation to be set.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-image-cosign-identity: "https://github.com/org/repo/.github/workflows/release.yaml@refs/heads/main"
+	KongPluginInstallationCosignIdentityAnnotation = OperatorAnnotationPrefix + "plugin-image-cosign-identity"
+
+	// KongPluginInstallationCosignIssuerAnnotation is the annotation set on KongPluginInstallations
+	// to configure the OIDC issuer of the identity keyless signatures are accepted for.
+	//
+	// Example:
+	// gateway-operator.konghq.com/plugin-image-cosign-issuer: "https://token.actions.githubusercontent.com"
+	KongPluginInstallationCosignIssuerAnnotation = OperatorAnnotationPrefix + "plugin-image-cosign-issuer"
+)
+
+const (
+	// KongPluginInstallationDeliveryConfigMap delivers the plugin's files to DataPlanes through
+	// a ConfigMap, which limits the size of the plugin to 1 MiB.
+	KongPluginInstallationDeliveryConfigMap = "configmap"
+	// KongPluginInstallationDeliveryImageVolume delivers the plugin to DataPlanes by mounting
+	// the plugin image as an image volume, which allows plugins larger than 1 MiB. It requires
+	// the ImageVolume feature of Kubernetes to be enabled and the image to be pullable by
+	// DataPlane Pods (e.g. with imagePullSecrets in the DataPlane's Pod template).
+	KongPluginInstallationDeliveryImageVolume = "image-volume"
+)
+
+const (
+	// KongPluginInstallationCosignPublicKeySecretKey is the key of the Secret referenced by
+	// KongPluginInstallationCosignSecretAnnotation holding the PEM encoded trusted public keys.
+	KongPluginInstallationCosignPublicKeySecretKey = "cosign.pub"
+	// KongPluginInstallationCosignFulcioRootsSecretKey is the key of the Secret referenced by
+	// KongPluginInstallationCosignSecretAnnotation holding the PEM encoded Fulcio root certificates.
+	KongPluginInstallationCosignFulcioRootsSecretKey = "fulcio.crt"
+	// KongPluginInstallationCosignRekorPublicKeySecretKey is the key of the Secret referenced by
+	// KongPluginInstallationCosignSecretAnnotation holding the PEM encoded Rekor public keys.
+	KongPluginInstallationCosignRekorPublicKeySecretKey = "rekor.pub"
 )
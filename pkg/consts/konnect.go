@@ -57,4 +57,24 @@ const (
 	// of all the certificates created out of the secret, separated by commas.
 	// Example: konnect.konghq.com/certificate-ids: "xxxxxx,yyyyyy,zzzzzz"
 	DataPlaneCertificateIDAnnotationKey = "konnect.konghq.com/certificate-ids"
+
+	// KonnectDriftPolicyAnnotationKey is the annotation set on Konnect entities to configure what happens
+	// when their state in Konnect differs from the desired one, e.g. because of changes made in the Konnect UI.
+	// Supported values are KonnectDriftPolicyEnforce (default), KonnectDriftPolicyReportOnly and
+	// KonnectDriftPolicyIgnore. It's only used for KongServices, KongRoutes and KongUpstreams, other
+	// Konnect entities are always re-applied as with KonnectDriftPolicyEnforce and get an Unknown Drifted
+	// condition with the DriftDetectionUnsupported reason when another value is set.
+	// Example: konnect.konghq.com/drift-policy: "ReportOnly"
+	KonnectDriftPolicyAnnotationKey = "konnect.konghq.com/drift-policy"
+)
+
+const (
+	// KonnectDriftPolicyEnforce reports the drift of a Konnect entity and periodically re-applies its desired state.
+	KonnectDriftPolicyEnforce = "Enforce"
+	// KonnectDriftPolicyReportOnly reports the drift of a Konnect entity without reverting it.
+	// The desired state is applied only when the entity's spec changes.
+	KonnectDriftPolicyReportOnly = "ReportOnly"
+	// KonnectDriftPolicyIgnore neither reports nor reverts the drift of a Konnect entity.
+	// The desired state is applied only when the entity's spec changes.
+	KonnectDriftPolicyIgnore = "Ignore"
 )
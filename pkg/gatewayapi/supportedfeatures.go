@@ -16,14 +16,21 @@ var (
 
 	expressionsRouterSupportedFeatures = commonSupportedFeatures.Clone().Insert(
 		// extended
-		features.SupportHTTPRouteMethodMatching,
 		features.SupportHTTPRouteQueryParamMatching,
 	)
 )
 
+// commonSupportedFeatures are the features supported regardless of the router flavor.
+//
+// HTTPRouteDestinationPortMatching is not advertised as Kong routes cannot match
+// on the Gateway listener port, and HTTPRouteBackendProtocolH2C is not advertised
+// as Kong doesn't proxy HTTP/2 cleartext to upstreams other than gRPC ones.
+// The HTTPRouteRequestMirror features are not advertised as the mirroring
+// requires resty.http to be allowed in the Lua sandbox of the DataPlane.
 var commonSupportedFeatures = sets.New(
 	// core features
 	features.SupportHTTPRoute,
+	features.SupportGRPCRoute,
 	features.SupportGateway,
 	features.SupportReferenceGrant,
 
@@ -31,9 +38,12 @@ var commonSupportedFeatures = sets.New(
 	features.SupportGatewayPort8080,
 
 	// HTTPRoute extended
+	features.SupportHTTPRouteMethodMatching,
 	features.SupportHTTPRouteResponseHeaderModification,
 	features.SupportHTTPRoutePathRewrite,
 	features.SupportHTTPRouteHostRewrite,
+	features.SupportHTTPRouteRequestTimeout,
+	features.SupportHTTPRouteBackendProtocolWebSocket,
 )
 
 // GetSupportedFeatures returns the supported features for the given router type.
@@ -0,0 +1,35 @@
+package gatewayapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/gateway-api/pkg/features"
+
+	"github.com/kong/kong-operator/pkg/consts"
+)
+
+func TestGetSupportedFeatures(t *testing.T) {
+	traditional, err := GetSupportedFeatures(consts.RouterFlavorTraditionalCompatible)
+	require.NoError(t, err)
+	expressions, err := GetSupportedFeatures(consts.RouterFlavorExpressions)
+	require.NoError(t, err)
+
+	for _, f := range []features.FeatureName{
+		features.SupportGRPCRoute,
+		features.SupportHTTPRouteMethodMatching,
+		features.SupportHTTPRouteRequestTimeout,
+		features.SupportHTTPRouteBackendProtocolWebSocket,
+	} {
+		assert.True(t, traditional.Has(f), "traditional_compatible router should support %s", f)
+		assert.True(t, expressions.Has(f), "expressions router should support %s", f)
+	}
+
+	assert.False(t, traditional.Has(features.SupportHTTPRouteQueryParamMatching))
+	assert.True(t, expressions.Has(features.SupportHTTPRouteQueryParamMatching))
+	assert.True(t, expressions.IsSuperset(traditional))
+
+	_, err = GetSupportedFeatures(consts.RouterFlavor("unknown"))
+	require.Error(t, err)
+}
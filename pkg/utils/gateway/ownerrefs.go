@@ -111,34 +111,7 @@ func ListHTTPRoutesForGateway(
 
 	var httpRoutes []gwtypes.HTTPRoute
 	for _, httpRoute := range httpRoutesList.Items {
-		if !lo.ContainsBy(httpRoute.Spec.ParentRefs, func(parentRef gwtypes.ParentReference) bool {
-			gwGVK := gateway.GroupVersionKind()
-			if parentRef.Group != nil && string(*parentRef.Group) != gwGVK.Group {
-				return false
-			}
-			if parentRef.Kind != nil && string(*parentRef.Kind) != gwGVK.Kind {
-				return false
-			}
-			if string(parentRef.Name) != gateway.Name {
-				return false
-			}
-
-			if parentRef.SectionName != nil {
-				if !lo.ContainsBy(gateway.Spec.Listeners, func(listener gwtypes.Listener) bool {
-					if listener.Name != *parentRef.SectionName {
-						return false
-					}
-					if parentRef.Port != nil && listener.Port != *parentRef.Port {
-						return false
-					}
-					return true
-				}) {
-					return false
-				}
-			}
-
-			return true
-		}) {
+		if !isRouteParentedByGateway(httpRoute.Spec.ParentRefs, gateway) {
 			continue
 		}
 
@@ -148,6 +121,74 @@ func ListHTTPRoutesForGateway(
 	return httpRoutes, nil
 }
 
+// ListGRPCRoutesForGateway is a helper function which returns a list of GRPCRoutes
+// that have the provided Gateway set as parent in their spec.
+func ListGRPCRoutesForGateway(
+	ctx context.Context,
+	c client.Client,
+	gateway *gwtypes.Gateway,
+	opts ...client.ListOption,
+) ([]gwtypes.GRPCRoute, error) {
+	if gateway.Namespace == "" {
+		return nil, fmt.Errorf("can't list GRPCRoutes for gateway: Gateway %s was missing namespace", gateway.Name)
+	}
+
+	var grpcRoutesList gwtypes.GRPCRouteList
+	err := c.List(
+		ctx,
+		&grpcRoutesList,
+		opts...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't list GRPCRoutes for gateway: %w", err)
+	}
+
+	var grpcRoutes []gwtypes.GRPCRoute
+	for _, grpcRoute := range grpcRoutesList.Items {
+		if !isRouteParentedByGateway(grpcRoute.Spec.ParentRefs, gateway) {
+			continue
+		}
+
+		grpcRoutes = append(grpcRoutes, grpcRoute)
+	}
+
+	return grpcRoutes, nil
+}
+
+// isRouteParentedByGateway returns true if any of the route's parentRefs
+// references the provided Gateway (and one of its listeners when the
+// sectionName is set).
+func isRouteParentedByGateway(parentRefs []gwtypes.ParentReference, gateway *gwtypes.Gateway) bool {
+	return lo.ContainsBy(parentRefs, func(parentRef gwtypes.ParentReference) bool {
+		gwGVK := gateway.GroupVersionKind()
+		if parentRef.Group != nil && string(*parentRef.Group) != gwGVK.Group {
+			return false
+		}
+		if parentRef.Kind != nil && string(*parentRef.Kind) != gwGVK.Kind {
+			return false
+		}
+		if string(parentRef.Name) != gateway.Name {
+			return false
+		}
+
+		if parentRef.SectionName != nil {
+			if !lo.ContainsBy(gateway.Spec.Listeners, func(listener gwtypes.Listener) bool {
+				if listener.Name != *parentRef.SectionName {
+					return false
+				}
+				if parentRef.Port != nil && listener.Port != *parentRef.Port {
+					return false
+				}
+				return true
+			}) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
 // GetDataPlaneServiceName is a helper function that retrieves the name of the service owned by provided dataplane.
 // It accepts a string as the last argument to specify which service to retrieve (proxy/admin)
 func GetDataPlaneServiceName(
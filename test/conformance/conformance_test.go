@@ -30,12 +30,24 @@ import (
 	"github.com/kong/kong-operator/pkg/vars"
 )
 
-var skippedTestsForExpressionsRouter = []string{}
+var skippedTestsForExpressionsRouter = []string{
+	// grpcroute
+	// When processing this scenario, the Kong's expressions router requires `priority`
+	// to be specified for routes.
+	// We cannot provide that for routes that are part of the conformance suite.
+	tests.GRPCRouteListenerHostnameMatching.ShortName,
+}
 
 var skippedTestsForTraditionalCompatibleRouter = []string{
 	// httproute
 	tests.HTTPRouteHeaderMatching.ShortName,
 	tests.HTTPRouteInvalidBackendRefUnknownKind.ShortName,
+
+	// grpcroute
+	// Kong does not define priority behavior for catch-all routes created for
+	// the header and method matches unless priorities are manually added.
+	tests.GRPCRouteHeaderMatching.ShortName,
+	tests.GRPCExactMethodMatching.ShortName,
 }
 
 type ConformanceConfig struct {
@@ -102,6 +114,7 @@ func TestGatewayConformance(t *testing.T) {
 	opts.Mode = mode
 	opts.ConformanceProfiles = sets.New(
 		suite.GatewayHTTPConformanceProfileName,
+		suite.GatewayGRPCConformanceProfileName,
 	)
 	opts.RestConfig.QPS = -1
 	opts.SupportedFeatures = supportedFeatures
@@ -114,7 +114,7 @@ func TestDataPlaneKonnectExtension(t *testing.T) {
 		Namespace: ns.Name,
 	}, map[string]string{
 		"konghq.com/secret": "true",
-	}, clusterCAKeyConfig))
+	}, clusterCAKeyConfig, 0))
 
 	t.Logf("Creating KonnectAPIAuthConfiguration")
 	konnectAPIAuthConfiguration := deploy.KonnectAPIAuthConfigurationWithProgrammed(t, ctx, cl)